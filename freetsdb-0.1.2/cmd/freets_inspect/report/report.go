@@ -27,6 +27,7 @@ type Command struct {
 	dir             string
 	pattern         string
 	detailed, exact bool
+	codecs          bool
 }
 
 // NewCommand returns a new instance of Command.
@@ -43,6 +44,7 @@ func (cmd *Command) Run(args ...string) error {
 	fs.StringVar(&cmd.pattern, "pattern", "", "Include only files matching a pattern")
 	fs.BoolVar(&cmd.detailed, "detailed", false, "Report detailed cardinality estimates")
 	fs.BoolVar(&cmd.exact, "exact", false, "Report exact counts")
+	fs.BoolVar(&cmd.codecs, "codecs", false, "Report compression ratios per codec")
 
 	fs.SetOutput(cmd.Stdout)
 	fs.Usage = cmd.printUsage
@@ -72,6 +74,8 @@ func (cmd *Command) Run(args ...string) error {
 
 	dbCardinalities := map[string]counter{}
 
+	codecStats := map[string]*codecStat{}
+
 	start := time.Now()
 
 	tw := tabwriter.NewWriter(cmd.Stdout, 8, 2, 1, ' ', 0)
@@ -142,6 +146,12 @@ func (cmd *Command) Run(args ...string) error {
 				}
 			}
 		}
+		if cmd.codecs {
+			if err := addCodecStats(codecStats, reader); err != nil {
+				fmt.Fprintf(cmd.Stderr, "error: %s: %v. Skipping.\n", file.Name(), err)
+			}
+		}
+
 		minT, maxT := reader.TimeRange()
 		if minT < minTime {
 			minTime = minT
@@ -204,6 +214,10 @@ func (cmd *Command) Run(args ...string) error {
 		}
 	}
 
+	if cmd.codecs {
+		cmd.printCodecStats(codecStats)
+	}
+
 	fmt.Printf("Completed in %s\n", time.Since(start))
 	return nil
 }
@@ -218,6 +232,67 @@ func sortKeys(vals map[string]counter) (keys []string) {
 	return keys
 }
 
+// codecStat accumulates the compression of the values of the blocks using a codec.
+type codecStat struct {
+	blocks, values, size, rawSize int64
+}
+
+// addCodecStats adds the compression of the values of every block of reader to stats.
+func addCodecStats(stats map[string]*codecStat, reader *tsm1.TSMReader) error {
+	iter := reader.BlockIterator()
+	for iter.Next() {
+		_, _, _, _, _, block, err := iter.Read()
+		if err != nil {
+			return err
+		}
+
+		s, err := tsm1.BlockCodecStat(block)
+		if err != nil {
+			return err
+		}
+
+		stat := stats[s.Codec]
+		if stat == nil {
+			stat = &codecStat{}
+			stats[s.Codec] = stat
+		}
+		stat.blocks++
+		stat.values += int64(s.N)
+		stat.size += int64(s.Size)
+		stat.rawSize += int64(s.RawSize)
+	}
+	return iter.Err()
+}
+
+// printCodecStats prints the compression ratio of the values per codec.
+func (cmd *Command) printCodecStats(stats map[string]*codecStat) {
+	codecs := make([]string, 0, len(stats))
+	for codec := range stats {
+		codecs = append(codecs, codec)
+	}
+	sort.Strings(codecs)
+
+	fmt.Fprintf(cmd.Stdout, "\n  Codecs:\n")
+	tw := tabwriter.NewWriter(cmd.Stdout, 8, 2, 1, ' ', 0)
+	fmt.Fprintln(tw, strings.Join([]string{"    Codec", "Blocks", "Values", "Size", "Raw Size", "Ratio"}, "\t"))
+	for _, codec := range codecs {
+		stat := stats[codec]
+		ratio := 0.0
+		if stat.size > 0 {
+			ratio = float64(stat.rawSize) / float64(stat.size)
+		}
+		fmt.Fprintln(tw, strings.Join([]string{
+			"    " + codec,
+			strconv.FormatInt(stat.blocks, 10),
+			strconv.FormatInt(stat.values, 10),
+			strconv.FormatInt(stat.size, 10),
+			strconv.FormatInt(stat.rawSize, 10),
+			strconv.FormatFloat(ratio, 'f', 2, 64),
+		}, "\t"))
+	}
+	tw.Flush()
+}
+
 func (cmd *Command) isShardDir(dir string) error {
 	name := filepath.Base(dir)
 	if id, err := strconv.Atoi(name); err != nil || id < 1 {
@@ -290,6 +365,10 @@ Usage: freets_inspect report [flags]
     -detailed
             Report detailed cardinality estimates.
             Defaults to "false".
+    -codecs
+            Report the number of blocks and values and the compression ratio of
+            the values of each block codec.  Note: this reads every block.
+            Defaults to "false".
 `
 
 	fmt.Fprintf(cmd.Stdout, usage)
@@ -69,35 +69,16 @@ func (cmd *Command) Run(args ...string) error {
 
 	// Verify the checksums of every block in every file
 	for _, f := range files {
-		file, err := os.OpenFile(f, os.O_RDONLY, 0600)
+		brokenFileBlocks, count, err := VerifyFile(tw, f)
 		if err != nil {
 			return err
 		}
+		brokenBlocks += brokenFileBlocks
+		totalBlocks += count
 
-		reader, err := tsm1.NewTSMReader(file)
-		if err != nil {
-			return err
-		}
-
-		blockItr := reader.BlockIterator()
-		brokenFileBlocks := 0
-		count := 0
-		for blockItr.Next() {
-			totalBlocks++
-			key, _, _, _, checksum, buf, err := blockItr.Read()
-			if err != nil {
-				brokenBlocks++
-				fmt.Fprintf(tw, "%s: could not get checksum for key %v block %d due to error: %q\n", f, key, count, err)
-			} else if expected := crc32.ChecksumIEEE(buf); checksum != expected {
-				brokenBlocks++
-				fmt.Fprintf(tw, "%s: got %d but expected %d for key %v, block %d\n", f, checksum, expected, key, count)
-			}
-			count++
-		}
 		if brokenFileBlocks == 0 {
 			fmt.Fprintf(tw, "%s: healthy\n", f)
 		}
-		reader.Close()
 	}
 
 	fmt.Fprintf(tw, "Broken Blocks: %d / %d, in %vs\n", brokenBlocks, totalBlocks, time.Since(start).Seconds())
@@ -105,6 +86,36 @@ func (cmd *Command) Run(args ...string) error {
 	return nil
 }
 
+// VerifyFile verifies the checksum of every block in the TSM file at path and
+// writes the broken blocks to w. It returns the number of broken blocks and
+// the number of blocks in the file.
+func VerifyFile(w io.Writer, path string) (broken, total int, err error) {
+	file, err := os.OpenFile(path, os.O_RDONLY, 0600)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	reader, err := tsm1.NewTSMReader(file)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer reader.Close()
+
+	blockItr := reader.BlockIterator()
+	for blockItr.Next() {
+		key, _, _, _, checksum, buf, err := blockItr.Read()
+		if err != nil {
+			broken++
+			fmt.Fprintf(w, "%s: could not get checksum for key %v block %d due to error: %q\n", path, key, total, err)
+		} else if expected := crc32.ChecksumIEEE(buf); checksum != expected {
+			broken++
+			fmt.Fprintf(w, "%s: got %d but expected %d for key %v, block %d\n", path, checksum, expected, key, total)
+		}
+		total++
+	}
+	return broken, total, nil
+}
+
 // printUsage prints the usage message to STDERR.
 func (cmd *Command) printUsage() {
 	usage := fmt.Sprintf(`Verifies the integrity of TSM files.
@@ -0,0 +1,197 @@
+// Package ae is the anti-entropy subcommand of the freetsd-ctl command.
+package ae
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/freetsdb/freetsdb/services/ae"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tcp"
+)
+
+// Command represents the program execution for "freetsd-ctl ae".
+type Command struct {
+	Stdout io.Writer
+	Stderr io.Writer
+
+	MetaAddr string
+	NodeAddr string
+	ShardID  uint64
+
+	// Dialer connects to the cluster TCP port of data nodes.
+	Dialer *tcp.Dialer
+}
+
+// NewCommand returns a new instance of Command with default settings.
+func NewCommand() *Command {
+	return &Command{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+}
+
+// Run executes the program.
+func (cmd *Command) Run(args ...string) error {
+	if len(args) == 0 {
+		cmd.printUsage()
+		return errors.New("subcommand required")
+	}
+
+	sub, args := args[0], args[1:]
+	if err := cmd.parseFlags(sub, args); err != nil {
+		return err
+	}
+
+	hosts, err := cmd.hosts()
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "status":
+		return cmd.status(hosts)
+	case "repair":
+		return cmd.repair(hosts)
+	default:
+		cmd.printUsage()
+		return fmt.Errorf("unknown subcommand %q", sub)
+	}
+}
+
+// parseFlags parses and validates the command line arguments.
+func (cmd *Command) parseFlags(sub string, args []string) error {
+	fs := flag.NewFlagSet("ae "+sub, flag.ContinueOnError)
+	fs.StringVar(&cmd.MetaAddr, "meta", "localhost:8091", "")
+	fs.StringVar(&cmd.NodeAddr, "node", "", "")
+	fs.SetOutput(cmd.Stderr)
+	fs.Usage = cmd.printUsage
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if sub == "repair" && fs.NArg() > 0 {
+		id, err := strconv.ParseUint(fs.Arg(0), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid shard id: %s", fs.Arg(0))
+		}
+		cmd.ShardID = id
+	}
+	return nil
+}
+
+// hosts returns the TCP addresses of the data nodes the command applies to.
+// If no node was given, every data node in the cluster is used.
+func (cmd *Command) hosts() ([]string, error) {
+	if cmd.NodeAddr != "" {
+		return []string{cmd.NodeAddr}, nil
+	}
+
+	peers, err := cmd.getMetaServers(cmd.MetaAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("Failed to get MetaServerInfo: empty Peers")
+	}
+
+	metaClient := meta.NewClient(nil)
+	metaClient.SetMetaServers(peers)
+	if err := metaClient.Open(); err != nil {
+		return nil, err
+	}
+	defer metaClient.Close()
+
+	nodes, err := metaClient.DataNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		hosts = append(hosts, n.TCPHost)
+	}
+	return hosts, nil
+}
+
+// status prints the anti-entropy status of every shard on hosts.
+func (cmd *Command) status(hosts []string) error {
+	tw := tabwriter.NewWriter(cmd.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(tw, "Node\tShard\tDatabase\tRetention Policy\tState\tDivergent Series\tRepaired Ranges\tLast Check\tError")
+	for _, host := range hosts {
+		status, err := ae.NewClient(host, cmd.Dialer).Status()
+		if err != nil {
+			fmt.Fprintf(tw, "%s\t\t\t\t\t\t\t\t%s\n", host, err)
+			continue
+		}
+		for _, st := range status {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+				host, st.ShardID, st.Database, st.RetentionPolicy, st.State,
+				st.DivergentSeries, st.RepairedRanges, st.LastCheck.Format(time.RFC3339), st.Error)
+		}
+	}
+	return tw.Flush()
+}
+
+// repair starts an anti-entropy repair on hosts.
+func (cmd *Command) repair(hosts []string) error {
+	for _, host := range hosts {
+		if err := ae.NewClient(host, cmd.Dialer).Repair(cmd.ShardID); err != nil {
+			return fmt.Errorf("%s: %s", host, err)
+		}
+		fmt.Fprintf(cmd.Stdout, "Repair started on %s\n", host)
+	}
+	return nil
+}
+
+func (cmd *Command) getMetaServers(metaAddr string) ([]string, error) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/meta-servers", metaAddr))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.New(string(b))
+	}
+
+	peers := []string{}
+	if err := json.NewDecoder(resp.Body).Decode(&peers); err != nil {
+		return nil, err
+	}
+
+	return peers, nil
+}
+
+// printUsage prints the usage message to STDERR.
+func (cmd *Command) printUsage() {
+	fmt.Fprintf(cmd.Stderr, `usage: freetsd-ctl ae status [flags]
+       freetsd-ctl ae repair [flags] [shard-id]
+
+Shows the anti-entropy status of shards, or starts comparing shards with
+their replicas and pulling any missing data. Repair checks every shard on
+the node unless a shard id is given.
+
+Options:
+  -meta <addr>
+        Optional. The HTTP address of a meta node. Defaults to localhost:8091.
+  -node <addr>
+        Optional. The TCP address of a single data node. Defaults to every
+        data node in the cluster.
+
+`)
+}
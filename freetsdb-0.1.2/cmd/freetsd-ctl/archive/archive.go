@@ -0,0 +1,194 @@
+// Package archive is the archive-shard subcommand of the freetsd-ctl command.
+package archive
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/backup_util"
+	"github.com/freetsdb/freetsdb/services/archive"
+	"github.com/freetsdb/freetsdb/services/copier"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tcp"
+)
+
+// Command represents the program execution for "freetsd-ctl archive-shard".
+type Command struct {
+	Stdout io.Writer
+	Stderr io.Writer
+
+	MetaAddr string
+	Dir      string
+	ShardID  uint64
+
+	// Dialer connects to the cluster TCP port of data nodes.
+	Dialer *tcp.Dialer
+}
+
+// NewCommand returns a new instance of Command with default settings.
+func NewCommand() *Command {
+	return &Command{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+}
+
+// Run executes the program.
+func (cmd *Command) Run(args ...string) error {
+	if err := cmd.parseFlags(args); err != nil {
+		return err
+	}
+
+	metaClient, err := backup_util.OpenMetaClient(cmd.MetaAddr)
+	if err != nil {
+		return err
+	}
+	defer metaClient.Close()
+
+	return cmd.archiveShard(metaClient)
+}
+
+// parseFlags parses and validates the command line arguments.
+func (cmd *Command) parseFlags(args []string) error {
+	fs := flag.NewFlagSet("archive-shard", flag.ContinueOnError)
+	fs.StringVar(&cmd.MetaAddr, "meta", "localhost:8091", "")
+	fs.StringVar(&cmd.Dir, "dir", "", "")
+	fs.SetOutput(cmd.Stderr)
+	fs.Usage = cmd.printUsage
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		cmd.printUsage()
+		return errors.New("shard id required")
+	}
+
+	id, err := strconv.ParseUint(fs.Arg(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid shard id: %s", fs.Arg(0))
+	}
+	cmd.ShardID = id
+
+	if cmd.Dir == "" {
+		return errors.New("archive directory required")
+	}
+
+	return nil
+}
+
+// archiveShard writes a compacted copy of the shard to the archive
+// directory, marks the shard as archived and deletes it from its owners.
+func (cmd *Command) archiveShard(metaClient *meta.Client) error {
+	database, policy, sgi := metaClient.ShardOwner(cmd.ShardID)
+	if sgi == nil {
+		return meta.ErrShardNotFound
+	}
+
+	var si meta.ShardInfo
+	for _, s := range sgi.Shards {
+		if s.ID == cmd.ShardID {
+			si = s
+		}
+	}
+
+	now := time.Now().UTC()
+	if si.Archived() {
+		return meta.ErrShardArchived
+	} else if sgi.EndTime.After(now) {
+		return fmt.Errorf("shard %d has not ended yet, it ends at %s", cmd.ShardID, sgi.EndTime)
+	} else if len(si.Owners) == 0 {
+		return fmt.Errorf("shard %d has no owners", cmd.ShardID)
+	}
+
+	m := &archive.Manifest{
+		ShardID:      cmd.ShardID,
+		Database:     database,
+		Policy:       policy,
+		ShardGroupID: sgi.ID,
+		StartTime:    sgi.StartTime,
+		EndTime:      sgi.EndTime,
+		ArchivedAt:   now,
+	}
+
+	// Read the shard from the first owner that can serve it.
+	var owners []*meta.NodeInfo
+	var written bool
+	for _, o := range si.Owners {
+		n, err := metaClient.DataNode(o.NodeID)
+		if err != nil {
+			return err
+		}
+		owners = append(owners, n)
+
+		if written {
+			continue
+		}
+		if err := cmd.writeArchive(n.TCPHost, m); err != nil {
+			fmt.Fprintf(cmd.Stderr, "Failed to archive shard %d from %s: %s\n", cmd.ShardID, n.TCPHost, err)
+			continue
+		}
+		written = true
+	}
+	if !written {
+		return fmt.Errorf("no owner of shard %d could be archived", cmd.ShardID)
+	}
+
+	if err := metaClient.ArchiveShard(cmd.ShardID, now); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.Stdout, "Archived shard %d to %s (%d bytes)\n", cmd.ShardID, archive.Key(database, policy, cmd.ShardID), m.Size)
+
+	// The shard is no longer owned by any node, so failing to remove a copy
+	// only leaves a stale local copy behind that is not queried anymore.
+	for _, n := range owners {
+		if err := copier.NewClient(n.TCPHost, cmd.Dialer).RemoveShard(cmd.ShardID); err != nil {
+			fmt.Fprintf(cmd.Stderr, "Failed to remove shard %d from %s: %s\n", cmd.ShardID, n.TCPHost, err)
+			continue
+		}
+		fmt.Fprintf(cmd.Stdout, "Removed shard %d from %s\n", cmd.ShardID, n.TCPHost)
+	}
+	return nil
+}
+
+// writeArchive streams a fully compacted copy of the shard from host to the
+// archive directory.
+func (cmd *Command) writeArchive(host string, m *archive.Manifest) error {
+	r, err := copier.NewClient(host, cmd.Dialer).CompactedShardReader(cmd.ShardID)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return archive.Write(cmd.Dir, m, r)
+}
+
+// printUsage prints the usage message to STDERR.
+func (cmd *Command) printUsage() {
+	fmt.Fprintf(cmd.Stderr, `usage: freetsd-ctl archive-shard [flags] <shard-id>
+
+Fully compacts a shard on one of its owners and writes its TSM and index
+files with a manifest to an archive directory, using the key layout
+
+    shards/<database>/<policy>/<shard-id>/data.tar
+    shards/<database>/<policy>/<shard-id>/manifest.json
+
+The shard is then marked as archived in the meta store and deleted from its
+owners. Only shards whose shard group has ended can be archived. Use
+RESTORE SHARD <shard-id> to bring the shard back for queries; the data node
+reads it from its [data] archive-dir.
+
+Options:
+  -dir <path>
+        Required. The archive directory, for example a local directory
+        served by an S3-compatible object store.
+  -meta <addr>
+        Optional. The HTTP address of a meta node. Defaults to localhost:8091.
+
+`)
+}
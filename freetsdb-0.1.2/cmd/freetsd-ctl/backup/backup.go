@@ -2,7 +2,9 @@
 package backup
 
 import (
+	"crypto/sha256"
 	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -19,6 +21,7 @@ import (
 	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/backup_util"
 	"github.com/freetsdb/freetsdb/services/snapshotter"
 	"github.com/freetsdb/freetsdb/tcp"
+	"github.com/freetsdb/freetsdb/tsdb"
 	gzip "github.com/klauspost/pgzip"
 )
 
@@ -44,6 +47,9 @@ type Command struct {
 	Stderr io.Writer
 	Stdout io.Writer
 
+	// Dialer connects to the cluster TCP port of data nodes.
+	Dialer *tcp.Dialer
+
 	host            string
 	path            string
 	database        string
@@ -60,6 +66,14 @@ type Command struct {
 	portableFileBase string
 	continueOnError  bool
 
+	cluster  bool
+	metaAddr string
+	parallel int
+
+	incremental bool
+	// previous holds the last incremental backup of each shard in path.
+	previous map[uint64]backup_util.Entry
+
 	BackupFiles []string
 }
 
@@ -83,7 +97,9 @@ func (cmd *Command) Run(args ...string) error {
 		return err
 	}
 
-	if cmd.shardID != "" {
+	if cmd.cluster {
+		err = cmd.backupCluster()
+	} else if cmd.shardID != "" {
 		// always backup the metastore
 		if err := cmd.backupMetastore(); err != nil {
 			return err
@@ -159,6 +175,10 @@ func (cmd *Command) parseFlags(args []string) (err error) {
 	fs.StringVar(&endArg, "end", "", "")
 	fs.BoolVar(&cmd.portable, "portable", false, "")
 	fs.BoolVar(&cmd.continueOnError, "skip-errors", false, "")
+	fs.BoolVar(&cmd.cluster, "cluster", false, "")
+	fs.StringVar(&cmd.metaAddr, "meta", "localhost:8091", "")
+	fs.IntVar(&cmd.parallel, "parallel", 4, "")
+	fs.BoolVar(&cmd.incremental, "incremental", false, "")
 
 	fs.SetOutput(cmd.Stderr)
 	fs.Usage = cmd.printUsage
@@ -170,8 +190,27 @@ func (cmd *Command) parseFlags(args []string) (err error) {
 
 	cmd.BackupFiles = []string{}
 
+	// Cluster backups are always written in the portable format.
+	if cmd.cluster {
+		if cmd.parallel < 1 {
+			return errors.New("-parallel must be at least 1")
+		}
+		cmd.portable = true
+		cmd.manifest.Cluster = true
+	}
+
+	if cmd.incremental {
+		if sinceArg != "" || startArg != "" || endArg != "" {
+			return errors.New("-incremental is not compatible with -since, -start or -end")
+		}
+		cmd.portable = true
+		cmd.manifest.Incremental = true
+	}
+
 	// for portable saving, if needed
-	cmd.portableFileBase = time.Now().UTC().Format(backup_util.PortableFileNamePattern)
+	now := time.Now().UTC()
+	cmd.portableFileBase = now.Format(backup_util.PortableFileNamePattern)
+	cmd.manifest.Time = now
 
 	// if startArg and endArg are unspecified, or if we are using -since then assume we are doing a full backup of the shards
 	cmd.isBackup = (startArg == "" && endArg == "") || sinceArg != ""
@@ -213,14 +252,28 @@ func (cmd *Command) parseFlags(args []string) (err error) {
 	}
 	cmd.path = fs.Arg(0)
 
-	err = os.MkdirAll(cmd.path, 0700)
+	if err := os.MkdirAll(cmd.path, 0700); err != nil {
+		return err
+	}
 
-	return err
+	if cmd.incremental {
+		_, chains, err := backup_util.LoadPointInTime(cmd.path, now)
+		if err != nil {
+			return err
+		}
+		cmd.previous = make(map[uint64]backup_util.Entry, len(chains))
+		for id, chain := range chains {
+			cmd.previous[id] = chain[len(chain)-1]
+		}
+	}
+	return nil
 }
 
 func (cmd *Command) backupShard(db, rp, sid string) error {
 	reqType := snapshotter.RequestShardBackup
-	if !cmd.isBackup {
+	if cmd.incremental {
+		reqType = snapshotter.RequestShardIncrementalBackup
+	} else if !cmd.isBackup {
 		reqType = snapshotter.RequestShardExport
 	}
 
@@ -234,7 +287,10 @@ func (cmd *Command) backupShard(db, rp, sid string) error {
 		return err
 	}
 
-	if cmd.isBackup {
+	if cmd.incremental {
+		cmd.StdoutLogger.Printf("backing up db=%v rp=%v shard=%v to %s incrementally",
+			db, rp, sid, shardArchivePath)
+	} else if cmd.isBackup {
 		cmd.StdoutLogger.Printf("backing up db=%v rp=%v shard=%v to %s since %s",
 			db, rp, sid, shardArchivePath, cmd.since.Format(time.RFC3339))
 	} else {
@@ -249,10 +305,11 @@ func (cmd *Command) backupShard(db, rp, sid string) error {
 		Since:                 cmd.since,
 		ExportStart:           cmd.start,
 		ExportEnd:             cmd.end,
+		Position:              cmd.previousPosition(id, cmd.host),
 	}
 
 	// TODO: verify shard backup data
-	err = cmd.downloadAndVerify(req, shardArchivePath, nil)
+	err = cmd.downloadAndVerify(cmd.host, req, shardArchivePath, nil)
 	if err != nil {
 		os.Remove(shardArchivePath)
 		return err
@@ -262,69 +319,102 @@ func (cmd *Command) backupShard(db, rp, sid string) error {
 	}
 
 	if cmd.portable {
-		f, err := os.Open(shardArchivePath)
+		entry, err := cmd.packIncrementalShard(shardArchivePath, cmd.host, db, rp, id)
 		if err != nil {
 			return err
 		}
-		defer f.Close()
-		defer os.Remove(shardArchivePath)
-
-		filePrefix := cmd.portableFileBase + ".s" + sid
-		filename := filePrefix + ".tar.gz"
-		out, err := os.OpenFile(filepath.Join(cmd.path, filename), os.O_CREATE|os.O_RDWR, 0600)
-		if err != nil {
-			return err
-		}
-
-		zw := gzip.NewWriter(out)
-		zw.Name = filePrefix + ".tar"
+		cmd.manifest.Files = append(cmd.manifest.Files, *entry)
+		cmd.BackupFiles = append(cmd.BackupFiles, entry.FileName)
+	}
+	return nil
 
-		cw := backup_util.CountingWriter{Writer: zw}
+}
 
-		_, err = io.Copy(&cw, f)
-		if err != nil {
-			if err := zw.Close(); err != nil {
-				return err
-			}
+// packShard compresses a downloaded shard archive into a portable backup file
+// and returns its manifest entry. The archive is removed.
+func (cmd *Command) packShard(shardArchivePath, db, rp string, id uint64) (*backup_util.Entry, error) {
+	f, err := os.Open(shardArchivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	defer os.Remove(shardArchivePath)
 
-			if err := out.Close(); err != nil {
-				return err
-			}
-			return err
-		}
+	filePrefix := cmd.portableFileBase + ".s" + strconv.FormatUint(id, 10)
+	filename := filePrefix + ".tar.gz"
+	out, err := os.OpenFile(filepath.Join(cmd.path, filename), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
 
-		shardid, err := strconv.ParseUint(sid, 10, 64)
-		if err != nil {
-			if err := zw.Close(); err != nil {
-				return err
-			}
+	h := sha256.New()
+	zw := gzip.NewWriter(io.MultiWriter(out, h))
+	zw.Name = filePrefix + ".tar"
 
-			if err := out.Close(); err != nil {
-				return err
-			}
-			return err
-		}
-		cmd.manifest.Files = append(cmd.manifest.Files, backup_util.Entry{
-			Database:     db,
-			Policy:       rp,
-			ShardID:      shardid,
-			FileName:     filename,
-			Size:         cw.Total,
-			LastModified: 0,
-		})
+	cw := backup_util.CountingWriter{Writer: zw}
 
+	_, err = io.Copy(&cw, f)
+	if err != nil {
 		if err := zw.Close(); err != nil {
-			return err
+			return nil, err
 		}
 
 		if err := out.Close(); err != nil {
-			return err
+			return nil, err
 		}
+		return nil, err
+	}
 
-		cmd.BackupFiles = append(cmd.BackupFiles, filename)
+	if err := zw.Close(); err != nil {
+		return nil, err
 	}
-	return nil
 
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+
+	return &backup_util.Entry{
+		Database:     db,
+		Policy:       rp,
+		ShardID:      id,
+		FileName:     filename,
+		Size:         cw.Total,
+		LastModified: 0,
+		Checksum:     hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// packIncrementalShard packs a downloaded shard archive like packShard. For
+// incremental backups the host and the position of the backup are recorded
+// in the manifest entry.
+func (cmd *Command) packIncrementalShard(shardArchivePath, host, db, rp string, id uint64) (*backup_util.Entry, error) {
+	if !cmd.incremental {
+		return cmd.packShard(shardArchivePath, db, rp, id)
+	}
+
+	pos, err := backup_util.ReadPosition(shardArchivePath)
+	if err != nil {
+		os.Remove(shardArchivePath)
+		return nil, err
+	}
+
+	entry, err := cmd.packShard(shardArchivePath, db, rp, id)
+	if err != nil {
+		return nil, err
+	}
+	entry.Host = host
+	entry.Position = pos
+	return entry, nil
+}
+
+// previousPosition returns the position of the last incremental backup of
+// the shard if it was taken from host. Every replica of a shard has its own
+// files, so the backups of other hosts can not be continued.
+func (cmd *Command) previousPosition(id uint64, host string) *tsdb.BackupPosition {
+	if e, ok := cmd.previous[id]; ok && e.Host == host {
+		return e.Position
+	}
+	return nil
 }
 
 // backupDatabase will request the database information from the server and then backup
@@ -404,7 +494,7 @@ func (cmd *Command) backupMetastore() error {
 		Type: snapshotter.RequestMetastoreBackup,
 	}
 
-	err = cmd.downloadAndVerify(req, metastoreArchivePath, func(file string) error {
+	err = cmd.downloadAndVerify(cmd.host, req, metastoreArchivePath, func(file string) error {
 		f, err := os.Open(file)
 		if err != nil {
 			return err
@@ -444,22 +534,31 @@ func (cmd *Command) backupMetastore() error {
 		if err != nil {
 			return err
 		}
-		filename := cmd.portableFileBase + ".meta"
-		ep := backup_util.PortablePacker{Data: metaBytes, MaxNodeID: 0}
-		protoBytes, err := ep.MarshalBinary()
-		if err != nil {
-			return err
-		}
-		if err := ioutil.WriteFile(filepath.Join(cmd.path, filename), protoBytes, 0644); err != nil {
-			fmt.Fprintln(cmd.Stdout, "Error.")
-			return err
-		}
+		return cmd.writePortableMeta(metaBytes)
+	}
 
-		cmd.manifest.Meta.FileName = filename
-		cmd.manifest.Meta.Size = int64(len(metaBytes))
-		cmd.BackupFiles = append(cmd.BackupFiles, filename)
+	return nil
+}
+
+// writePortableMeta writes the meta data to the portable meta file and adds
+// it to the manifest.
+func (cmd *Command) writePortableMeta(metaBytes []byte) error {
+	filename := cmd.portableFileBase + ".meta"
+	ep := backup_util.PortablePacker{Data: metaBytes, MaxNodeID: 0}
+	protoBytes, err := ep.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(cmd.path, filename), protoBytes, 0644); err != nil {
+		fmt.Fprintln(cmd.Stdout, "Error.")
+		return err
 	}
 
+	cmd.manifest.Meta.FileName = filename
+	cmd.manifest.Meta.Size = int64(len(metaBytes))
+	sum := sha256.Sum256(protoBytes)
+	cmd.manifest.Meta.Checksum = hex.EncodeToString(sum[:])
+	cmd.BackupFiles = append(cmd.BackupFiles, filename)
 	return nil
 }
 
@@ -478,9 +577,9 @@ func (cmd *Command) nextPath(path string) (string, error) {
 
 // downloadAndVerify will download either the metastore or shard to a temp file and then
 // rename it to a good backup file name after complete
-func (cmd *Command) downloadAndVerify(req *snapshotter.Request, path string, validator func(string) error) error {
+func (cmd *Command) downloadAndVerify(host string, req *snapshotter.Request, path string, validator func(string) error) error {
 	tmppath := path + backup_util.Suffix
-	if err := cmd.download(req, tmppath); err != nil {
+	if err := cmd.download(host, req, tmppath); err != nil {
 		return err
 	}
 
@@ -512,7 +611,7 @@ func (cmd *Command) downloadAndVerify(req *snapshotter.Request, path string, val
 }
 
 // download downloads a snapshot of either the metastore or a shard from a host to a given path.
-func (cmd *Command) download(req *snapshotter.Request, path string) error {
+func (cmd *Command) download(host string, req *snapshotter.Request, path string) error {
 	// Create local file to write to.
 	f, err := os.Create(path)
 	if err != nil {
@@ -524,7 +623,7 @@ func (cmd *Command) download(req *snapshotter.Request, path string) error {
 	for i := 0; i < 10; i++ {
 		if err = func() error {
 			// Connect to snapshotter service.
-			conn, err := tcp.Dial("tcp", cmd.host, snapshotter.MuxHeader)
+			conn, err := cmd.Dialer.Dial("tcp", host, snapshotter.MuxHeader)
 			if err != nil {
 				return err
 			}
@@ -564,7 +663,7 @@ func (cmd *Command) download(req *snapshotter.Request, path string) error {
 func (cmd *Command) requestInfo(request *snapshotter.Request) (*snapshotter.Response, error) {
 	// Connect to snapshotter service.
 	var r snapshotter.Response
-	conn, err := tcp.Dial("tcp", cmd.host, snapshotter.MuxHeader)
+	conn, err := cmd.Dialer.Dial("tcp", cmd.host, snapshotter.MuxHeader)
 	if err != nil {
 		return nil, err
 	}
@@ -619,6 +718,17 @@ Usage: freetsd-ctl backup [options] PATH
             Recommend using '-start <timestamp>' instead.
     -skip-errors 
             Optional flag to continue backing up the remaining shards when the current shard fails to backup. 
+    -cluster
+            Back up the whole cluster in the portable format. Shard ownership is read from the meta service and
+            one healthy replica of every shard is backed up. '-host' is not used.
+    -meta <host:port>
+            The HTTP address of a meta node, used with '-cluster'. Defaults to localhost:8091.
+    -parallel <n>
+            The number of shards backed up at the same time with '-cluster'. Defaults to 4.
+    -incremental
+            Back up only the TSM files and WAL segment tails added to each shard since the last incremental
+            backup in PATH, in the portable format. The first incremental backup of a shard holds all of its
+            files. Not compatible with '-since <timestamp>', '-start <timestamp>' or '-end <timestamp>'.
 `)
 
 }
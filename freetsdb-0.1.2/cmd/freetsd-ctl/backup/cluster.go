@@ -0,0 +1,197 @@
+package backup
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/backup_util"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/services/snapshotter"
+)
+
+// clusterShard is a shard to back up from one of the data nodes owning it.
+type clusterShard struct {
+	database string
+	policy   string
+	id       uint64
+
+	// hosts are the TCP addresses of the owners that are up, in the order
+	// they should be tried.
+	hosts []string
+}
+
+// backupCluster backs up the meta data of the cluster and one replica of
+// every shard, reading the owners of each shard from the meta service.
+func (cmd *Command) backupCluster() error {
+	client, err := backup_util.OpenMetaClient(cmd.metaAddr)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// Take the meta data and the shard list from the same snapshot so the
+	// manifest matches the meta data.
+	data := client.Data()
+	metaBytes, err := data.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	cmd.StdoutLogger.Printf("backing up cluster meta data from %s", cmd.metaAddr)
+	if err := cmd.writePortableMeta(metaBytes); err != nil {
+		return err
+	}
+
+	shards, err := cmd.clusterShards(&data)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, cmd.parallel)
+	for _, sh := range shards {
+		wg.Add(1)
+		go func(sh clusterShard) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			entry, err := cmd.backupClusterShard(sh)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				cmd.StderrLogger.Printf("error (%s) when backing up db: %s, rp %s, shard %d", err, sh.database, sh.policy, sh.id)
+				if firstErr == nil && !cmd.continueOnError {
+					firstErr = err
+				}
+				return
+			} else if entry == nil {
+				return
+			}
+			cmd.manifest.Files = append(cmd.manifest.Files, *entry)
+			cmd.BackupFiles = append(cmd.BackupFiles, entry.FileName)
+		}(sh)
+	}
+	wg.Wait()
+
+	sort.Slice(cmd.manifest.Files, func(i, j int) bool { return cmd.manifest.Files[i].ShardID < cmd.manifest.Files[j].ShardID })
+	return firstErr
+}
+
+// clusterShards returns the shards selected for backup, with the hosts they
+// can be read from.
+func (cmd *Command) clusterShards(data *meta.Data) ([]clusterShard, error) {
+	var shardID uint64
+	if cmd.shardID != "" {
+		id, err := strconv.ParseUint(cmd.shardID, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		shardID = id
+	}
+
+	var shards []clusterShard
+	for _, dbi := range data.Databases {
+		if cmd.database != "" && dbi.Name != cmd.database {
+			continue
+		}
+		for _, rpi := range dbi.RetentionPolicies {
+			if cmd.retentionPolicy != "" && rpi.Name != cmd.retentionPolicy {
+				continue
+			}
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+				for _, si := range sgi.Shards {
+					if shardID != 0 && si.ID != shardID {
+						continue
+					}
+
+					sh := clusterShard{database: dbi.Name, policy: rpi.Name, id: si.ID}
+					for _, i := range rand.Perm(len(si.Owners)) {
+						ni := data.DataNode(si.Owners[i].NodeID)
+						if ni == nil || ni.Down() {
+							continue
+						}
+						sh.hosts = append(sh.hosts, ni.TCPHost)
+					}
+					if len(sh.hosts) == 0 {
+						return nil, fmt.Errorf("no healthy owner for shard %d in %s.%s", si.ID, dbi.Name, rpi.Name)
+					}
+					shards = append(shards, sh)
+				}
+			}
+		}
+	}
+	return shards, nil
+}
+
+// backupClusterShard downloads a shard from the first owner that succeeds
+// and writes it as a portable backup file. It returns nil if the shard has
+// no data.
+func (cmd *Command) backupClusterShard(sh clusterShard) (*backup_util.Entry, error) {
+	reqType := snapshotter.RequestShardBackup
+	if cmd.incremental {
+		reqType = snapshotter.RequestShardIncrementalBackup
+	} else if !cmd.isBackup {
+		reqType = snapshotter.RequestShardExport
+	}
+	req := &snapshotter.Request{
+		Type:                  reqType,
+		BackupDatabase:        sh.database,
+		BackupRetentionPolicy: sh.policy,
+		ShardID:               sh.id,
+		Since:                 cmd.since,
+		ExportStart:           cmd.start,
+		ExportEnd:             cmd.end,
+	}
+
+	shardArchivePath := filepath.Join(cmd.path, fmt.Sprintf(backup_util.BackupFilePattern, sh.database, sh.policy, sh.id)) + ".00"
+
+	// Continue the incremental backup of the shard from the same owner.
+	hosts := sh.hosts
+	if prev, ok := cmd.previous[sh.id]; ok {
+		for i, host := range hosts {
+			if host == prev.Host {
+				hosts = append([]string{host}, append(hosts[:i:i], hosts[i+1:]...)...)
+				break
+			}
+		}
+	}
+
+	var (
+		err  error
+		host string
+	)
+	for _, host = range hosts {
+		cmd.StdoutLogger.Printf("backing up db=%v rp=%v shard=%v from %s since %s",
+			sh.database, sh.policy, sh.id, host, cmd.since.Format(time.RFC3339))
+
+		req.Position = cmd.previousPosition(sh.id, host)
+		if err = cmd.downloadAndVerify(host, req, shardArchivePath, nil); err == nil {
+			break
+		}
+		os.Remove(shardArchivePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing was downloaded for an empty shard.
+	if _, err := os.Stat(shardArchivePath); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return cmd.packIncrementalShard(shardArchivePath, host, sh.database, sh.policy, sh.id)
+}
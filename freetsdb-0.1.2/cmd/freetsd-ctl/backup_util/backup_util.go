@@ -2,17 +2,24 @@ package backup_util
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	internal "github.com/freetsdb/freetsdb/cmd/freetsd-ctl/backup_util/internal"
+	"github.com/freetsdb/freetsdb/services/meta"
 	"github.com/freetsdb/freetsdb/services/snapshotter"
+	"github.com/freetsdb/freetsdb/tsdb"
 	"io/ioutil"
 	"path/filepath"
 )
@@ -85,11 +92,17 @@ func GetMetaBytes(fname string) ([]byte, error) {
 
 // Manifest lists the meta and shard file information contained in the backup.
 // If Limited is false, the manifest contains a full backup, otherwise
-// it is a partial backup.
+// it is a partial backup. If Cluster is true, the backup holds one replica
+// of every shard in a cluster and the meta data read from the meta service.
+// If Incremental is true, each shard file only holds the data added since the
+// previous incremental backup of the shard.
 type Manifest struct {
-	Meta    MetaEntry `json:"meta"`
-	Limited bool      `json:"limited"`
-	Files   []Entry   `json:"files"`
+	Meta        MetaEntry `json:"meta"`
+	Limited     bool      `json:"limited"`
+	Cluster     bool      `json:"cluster,omitempty"`
+	Incremental bool      `json:"incremental,omitempty"`
+	Time        time.Time `json:"time"`
+	Files       []Entry   `json:"files"`
 
 	// If limited is true, then one (or all) of the following fields will be set
 
@@ -106,6 +119,14 @@ type Entry struct {
 	FileName     string `json:"fileName"`
 	Size         int64  `json:"size"`
 	LastModified int64  `json:"lastModified"`
+
+	// Host and Position are the host an incremental backup of the shard was
+	// taken from and the position it brings the backup of the shard to.
+	Host     string               `json:"host,omitempty"`
+	Position *tsdb.BackupPosition `json:"position,omitempty"`
+
+	// Checksum is the hex encoded SHA-256 checksum of the backup file.
+	Checksum string `json:"checksum,omitempty"`
 }
 
 func (e *Entry) SizeOrZero() int64 {
@@ -119,6 +140,23 @@ func (e *Entry) SizeOrZero() int64 {
 type MetaEntry struct {
 	FileName string `json:"fileName"`
 	Size     int64  `json:"size"`
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// FileChecksum returns the hex encoded SHA-256 checksum of the file at path,
+// as recorded in the manifest entries.
+func FileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
 // Size returns the size of the manifest.
@@ -202,6 +240,38 @@ func LoadIncremental(dir string) (*MetaEntry, map[uint64]*Entry, error) {
 	return &metaEntry, shards, nil
 }
 
+// OpenMetaClient returns an open client for the meta service of the cluster
+// that the meta node at metaAddr belongs to.
+func OpenMetaClient(metaAddr string) (*meta.Client, error) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/meta-servers", metaAddr))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.New(string(b))
+	}
+
+	peers := []string{}
+	if err := json.NewDecoder(resp.Body).Decode(&peers); err != nil {
+		return nil, err
+	} else if len(peers) == 0 {
+		return nil, fmt.Errorf("Failed to get MetaServerInfo: empty Peers")
+	}
+
+	client := meta.NewClient(nil)
+	client.SetMetaServers(peers)
+	if err := client.Open(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
 type CountingWriter struct {
 	io.Writer
 	Total int64 // Total # of bytes transferred
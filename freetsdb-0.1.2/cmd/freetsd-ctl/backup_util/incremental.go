@@ -0,0 +1,443 @@
+package backup_util
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/freetsdb/freetsdb/pkg/bytesutil"
+	intar "github.com/freetsdb/freetsdb/pkg/tar"
+	"github.com/freetsdb/freetsdb/tsdb"
+	"github.com/freetsdb/freetsdb/tsdb/engine/tsm1"
+	gzip "github.com/klauspost/pgzip"
+)
+
+// LoadPointInTime loads the incremental manifests in dir taken at or before
+// t. It returns the meta entry of the newest of them and, by shard ID, the
+// incremental backups of each shard, oldest first. The meta entry is nil if
+// there are no such manifests.
+//
+// A restore to t brings the data back as it was when the newest of these
+// backups was taken, not as it was at t. WAL entries do not record when they
+// were written, so writes made between that backup and t can not be told
+// apart from later ones.
+func LoadPointInTime(dir string, t time.Time) (*MetaEntry, map[uint64][]Entry, error) {
+	fileNames, err := filepath.Glob(filepath.Join(dir, "*.manifest"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var manifests []Manifest
+	for _, fileName := range fileNames {
+		b, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var manifest Manifest
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			return nil, nil, fmt.Errorf("read manifest: %v", err)
+		}
+
+		if manifest.Incremental && !manifest.Time.After(t) {
+			manifests = append(manifests, manifest)
+		}
+	}
+
+	chains := make(map[uint64][]Entry)
+	if len(manifests) == 0 {
+		return nil, chains, nil
+	}
+
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].Time.Before(manifests[j].Time) })
+	for _, manifest := range manifests {
+		for _, sh := range manifest.Files {
+			chains[sh.ShardID] = append(chains[sh.ShardID], sh)
+		}
+	}
+
+	metaEntry := manifests[len(manifests)-1].Meta
+	return &metaEntry, chains, nil
+}
+
+// ReadPosition returns the position stored in the shard archive of an
+// incremental backup at path.
+func ReadPosition(path string) (*tsdb.BackupPosition, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no backup position in %s", path)
+		} else if err != nil {
+			return nil, err
+		}
+
+		if filepath.Base(hdr.Name) != tsdb.BackupPositionFile {
+			continue
+		}
+
+		var pos tsdb.BackupPosition
+		if err := json.NewDecoder(tr).Decode(&pos); err != nil {
+			return nil, err
+		}
+		return &pos, nil
+	}
+}
+
+// ReplayChain writes a shard archive holding the data of a shard as captured
+// by the last of the incremental backups in chain, which are ordered oldest
+// first and stored in dir, to w. Each file of the shard is taken from the
+// newest backup holding it and the WAL segment tails are joined and written to
+// a new TSM file. The deletes in the WAL are written as tombstones of the TSM
+// files of the backup, since the data they remove may have been written to
+// disk before the WAL segments were.
+func ReplayChain(dir string, chain []Entry, w io.Writer) error {
+	if len(chain) == 0 {
+		return fmt.Errorf("empty backup chain")
+	}
+	last := chain[len(chain)-1]
+	if last.Position == nil {
+		return fmt.Errorf("shard %d: %s is not an incremental backup", last.ShardID, last.FileName)
+	}
+
+	tmp, err := ioutil.TempDir("", "freetsd-replay")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	walDir := filepath.Join(tmp, tsdb.BackupWALDir)
+	if err := os.Mkdir(walDir, 0700); err != nil {
+		return err
+	}
+
+	var relativePath string
+	for _, e := range chain {
+		path, err := replayEntry(dir, e, last.Position, tmp)
+		if err != nil {
+			return fmt.Errorf("shard %d: %s: %v", e.ShardID, e.FileName, err)
+		}
+		relativePath = path
+	}
+
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	files := make([]string, 0, len(last.Position.Files))
+	for name := range last.Position.Files {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+
+	var generation int
+	var tsmFiles []string
+	for _, name := range files {
+		fullPath := filepath.Join(tmp, name)
+		if fi, err := os.Stat(fullPath); os.IsNotExist(err) || err == nil && fi.Size() != last.Position.Files[name] {
+			return fmt.Errorf("shard %d: incomplete backup chain, %s not found", last.ShardID, name)
+		} else if err != nil {
+			return err
+		}
+
+		if strings.HasSuffix(name, "."+tsm1.TSMFileExtension) {
+			tsmFiles = append(tsmFiles, fullPath)
+			if gen, _, err := tsm1.DefaultParseFileName(name); err == nil && gen > generation {
+				generation = gen
+			}
+		}
+	}
+
+	segments := make([]string, 0, len(last.Position.WAL))
+	for name, size := range last.Position.WAL {
+		fullPath := filepath.Join(walDir, name)
+		if fi, err := os.Stat(fullPath); os.IsNotExist(err) || err == nil && fi.Size() != size {
+			return fmt.Errorf("shard %d: incomplete backup chain, WAL segment %s not found", last.ShardID, name)
+		} else if err != nil {
+			return err
+		}
+		segments = append(segments, fullPath)
+	}
+	sort.Strings(segments)
+
+	cache, deletes, err := readSegments(segments)
+	if err != nil {
+		return fmt.Errorf("shard %d: %v", last.ShardID, err)
+	}
+
+	tombstones, err := writeTombstones(tsmFiles, deletes)
+	if err != nil {
+		return fmt.Errorf("shard %d: %v", last.ShardID, err)
+	}
+	for _, name := range tombstones {
+		if _, ok := last.Position.Files[name]; !ok {
+			files = append(files, name)
+		}
+	}
+	sort.Strings(files)
+
+	for _, name := range files {
+		fullPath := filepath.Join(tmp, name)
+		fi, err := os.Stat(fullPath)
+		if err != nil {
+			return err
+		}
+		if err := intar.StreamFile(fi, relativePath, fullPath, tw); err != nil {
+			return err
+		}
+	}
+
+	// The recent writes of the shard are only in the WAL. Write them to a
+	// TSM file following the newest one in the backup.
+	name := tsm1.DefaultFormatFileName(generation+1, 1) + "." + tsm1.TSMFileExtension
+	if ok, err := writeCacheToTSM(cache, filepath.Join(tmp, name)); err != nil {
+		return err
+	} else if ok {
+		fi, err := os.Stat(filepath.Join(tmp, name))
+		if err != nil {
+			return err
+		}
+		if err := intar.StreamFile(fi, relativePath, filepath.Join(tmp, name), tw); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+// replayEntry extracts the files of the incremental backup e in dir that are
+// part of the shard at the position target into tmp, and appends its WAL
+// segment tails to the segments in the BackupWALDir of tmp. It returns the
+// relative path of the shard in the archive.
+func replayEntry(dir string, e Entry, target *tsdb.BackupPosition, tmp string) (string, error) {
+	if e.Position == nil {
+		return "", fmt.Errorf("not an incremental backup")
+	}
+
+	f, err := os.Open(filepath.Join(dir, e.FileName))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return "", err
+	}
+	defer gr.Close()
+
+	var relativePath string
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return relativePath, nil
+		} else if err != nil {
+			return "", err
+		}
+
+		name := filepath.FromSlash(hdr.Name)
+		base := filepath.Base(name)
+		parent := filepath.Dir(name)
+
+		switch {
+		case base == tsdb.BackupPositionFile:
+			relativePath = parent
+		case filepath.Base(parent) == tsdb.BackupWALDir:
+			offset, ok := e.Position.Tails[base]
+			if !ok {
+				return "", fmt.Errorf("no offset for WAL segment %s", base)
+			}
+			if err := appendWALTail(tr, filepath.Join(tmp, tsdb.BackupWALDir, base), offset); err != nil {
+				return "", err
+			}
+		default:
+			if _, ok := target.Files[base]; !ok {
+				continue
+			}
+			if err := copyFile(tr, filepath.Join(tmp, base)); err != nil {
+				return "", err
+			}
+		}
+	}
+}
+
+// appendWALTail writes the tail of a WAL segment starting at offset to the
+// segment at path. A tail starting at zero replaces the segment.
+func appendWALTail(r io.Reader, path string, offset int64) error {
+	flag := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if offset == 0 {
+		flag |= os.O_TRUNC
+	} else if fi, err := os.Stat(path); os.IsNotExist(err) || err == nil && fi.Size() != offset {
+		return fmt.Errorf("incomplete backup chain, WAL segment %s is missing data before offset %d", filepath.Base(path), offset)
+	} else if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, flag, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// copyFile writes the contents of r to a file at path, replacing any
+// existing file.
+func copyFile(r io.Reader, path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readSegments loads the WAL segments into a cache and returns it with the
+// deletes found in them, in the order they were made. Like the CacheLoader, it
+// stops reading a segment at the first entry that can not be read.
+func readSegments(segments []string) (*tsm1.Cache, []*tsm1.DeleteRangeWALEntry, error) {
+	cache := tsm1.NewCache(0)
+	var deletes []*tsm1.DeleteRangeWALEntry
+	for _, segment := range segments {
+		f, err := os.Open(segment)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		r := tsm1.NewWALSegmentReader(f)
+		for r.Next() {
+			entry, err := r.Read()
+			if err != nil {
+				break
+			}
+
+			switch e := entry.(type) {
+			case *tsm1.WriteWALEntry:
+				if err := cache.WriteMulti(e.Values); err != nil {
+					r.Close()
+					return nil, nil, err
+				}
+			case *tsm1.DeleteRangeWALEntry:
+				cache.DeleteRange(e.Keys, e.Min, e.Max)
+				deletes = append(deletes, e)
+			case *tsm1.DeleteWALEntry:
+				cache.Delete(e.Keys)
+				deletes = append(deletes, &tsm1.DeleteRangeWALEntry{Keys: e.Keys, Min: math.MinInt64, Max: math.MaxInt64})
+			}
+		}
+		if err := r.Close(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return cache, deletes, nil
+}
+
+// writeTombstones applies the deletes to the TSM files, writing them to their
+// tombstone files. It returns the names of the tombstone files written to.
+func writeTombstones(tsmFiles []string, deletes []*tsm1.DeleteRangeWALEntry) ([]string, error) {
+	if len(deletes) == 0 {
+		return nil, nil
+	}
+
+	var names []string
+	for _, path := range tsmFiles {
+		if err := func() error {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+
+			r, err := tsm1.NewTSMReader(f)
+			if err != nil {
+				f.Close()
+				return err
+			}
+			defer r.Close()
+
+			for _, d := range deletes {
+				keys := make([][]byte, len(d.Keys))
+				copy(keys, d.Keys)
+				bytesutil.Sort(keys)
+				if err := r.DeleteRange(keys, d.Min, d.Max); err != nil {
+					return err
+				}
+			}
+
+			for _, ts := range r.TombstoneFiles() {
+				names = append(names, filepath.Base(ts.Path))
+			}
+			return nil
+		}(); err != nil {
+			return nil, fmt.Errorf("%s: %v", filepath.Base(path), err)
+		}
+	}
+	return names, nil
+}
+
+// writeCacheToTSM writes the values in the cache to a TSM file at path. It
+// returns false, and writes no file, if the cache holds no values.
+func writeCacheToTSM(cache *tsm1.Cache, path string) (bool, error) {
+	// Every value of a key may have been deleted.
+	var keys [][]byte
+	for _, key := range cache.Keys() {
+		if len(cache.Values(key)) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return false, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return false, err
+	}
+
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		f.Close()
+		return false, err
+	}
+	defer w.Close()
+
+	for _, key := range keys {
+		values := cache.Values(key)
+		for len(values) > 0 {
+			block := values
+			if len(block) > tsdb.DefaultMaxPointsPerBlock {
+				block = block[:tsdb.DefaultMaxPointsPerBlock]
+			}
+			if err := w.Write(key, block); err != nil {
+				return false, err
+			}
+			values = values[len(block):]
+		}
+	}
+
+	if err := w.WriteIndex(); err != nil {
+		return false, err
+	}
+
+	// make sure the whole file is out to disk
+	return true, w.Flush()
+}
@@ -0,0 +1,229 @@
+package backup_util_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/backup_util"
+	"github.com/freetsdb/freetsdb/tsdb"
+	"github.com/freetsdb/freetsdb/tsdb/engine/tsm1"
+	gzip "github.com/klauspost/pgzip"
+)
+
+// Ensure the deletes in the WAL tails of a backup chain remove the data they
+// target in the TSM files of the backup, while later writes are kept.
+func TestReplayChain_WALDeletes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay-chain-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	keyA, keyB := "cpu,host=A#!~#value", "cpu,host=B#!~#value"
+
+	shardDir := filepath.Join(dir, "shard")
+	walDir := filepath.Join(shardDir, tsdb.BackupWALDir)
+	if err := os.MkdirAll(walDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	tsmName := tsm1.DefaultFormatFileName(1, 1) + "." + tsm1.TSMFileExtension
+	mustWriteTSM(t, filepath.Join(shardDir, tsmName), map[string][]tsm1.Value{
+		keyA: {tsm1.NewValue(1, 1.0), tsm1.NewValue(2, 2.0), tsm1.NewValue(3, 3.0)},
+		keyB: {tsm1.NewValue(1, 1.0)},
+	})
+
+	wal := tsm1.NewWAL(walDir)
+	if err := wal.Open(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wal.DeleteRange([][]byte{[]byte(keyA)}, 2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wal.WriteMulti(map[string][]tsm1.Value{keyA: {tsm1.NewValue(3, 30.0)}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := wal.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	segments, err := filepath.Glob(filepath.Join(walDir, "*."+tsm1.WALFileExtension))
+	if err != nil || len(segments) != 1 {
+		t.Fatalf("unexpected WAL segments: %v, %v", segments, err)
+	}
+
+	pos := &tsdb.BackupPosition{
+		Files: map[string]int64{tsmName: mustFileSize(t, filepath.Join(shardDir, tsmName))},
+		WAL:   map[string]int64{filepath.Base(segments[0]): mustFileSize(t, segments[0])},
+		Tails: map[string]int64{filepath.Base(segments[0]): 0},
+	}
+	mustWriteBackup(t, filepath.Join(dir, "shard.tar.gz"), "db0/rp0/1", pos, shardDir, []string{
+		tsmName,
+		filepath.Join(tsdb.BackupWALDir, filepath.Base(segments[0])),
+	})
+
+	var buf bytes.Buffer
+	entry := backup_util.Entry{Database: "db0", Policy: "rp0", ShardID: 1, FileName: "shard.tar.gz", Position: pos}
+	if err := backup_util.ReplayChain(dir, []backup_util.Entry{entry}, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	outDir := filepath.Join(dir, "out")
+	names := mustExtract(t, &buf, outDir)
+	if exp := []string{
+		"db0/rp0/1/" + tsm1.DefaultFormatFileName(1, 1) + ".tombstone",
+		"db0/rp0/1/" + tsmName,
+		"db0/rp0/1/" + tsm1.DefaultFormatFileName(2, 1) + "." + tsm1.TSMFileExtension,
+	}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("unexpected files: got %v, exp %v", names, exp)
+	}
+
+	got := mustReadTSM(t, filepath.Join(outDir, "db0/rp0/1"))
+	exp := map[string][]int64{keyA: {1, 3}, keyB: {1}}
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected values: got %v, exp %v", got, exp)
+	}
+}
+
+func mustWriteTSM(t *testing.T, path string, values map[string][]tsm1.Value) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"cpu,host=A#!~#value", "cpu,host=B#!~#value"} {
+		if err := w.Write([]byte(key), values[key]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustFileSize(t *testing.T, path string) int64 {
+	t.Helper()
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fi.Size()
+}
+
+// mustWriteBackup writes a gzipped shard archive at path holding pos and the
+// files of dir, under relativePath.
+func mustWriteBackup(t *testing.T, path, relativePath string, pos *tsdb.BackupPosition, dir string, files []string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	b, err := json.Marshal(pos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: relativePath + "/" + tsdb.BackupPositionFile, Mode: 0644, Size: int64(len(b))}); err != nil {
+		t.Fatal(err)
+	} else if _, err := tw.Write(b); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range files {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: relativePath + "/" + filepath.ToSlash(name), Mode: 0644, Size: int64(len(b))}); err != nil {
+			t.Fatal(err)
+		} else if _, err := tw.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// mustExtract extracts the tar archive in r to dir and returns the names of
+// its entries.
+func mustExtract(t *testing.T, r io.Reader, dir string) []string {
+	t.Helper()
+	var names []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+
+		path := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		} else if err := ioutil.WriteFile(path, b, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// mustReadTSM returns the timestamps of the values of each key in the TSM
+// files in dir, with their tombstones applied.
+func mustReadTSM(t *testing.T, dir string) map[string][]int64 {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string][]int64)
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := tsm1.NewTSMReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < r.KeyCount(); i++ {
+			key, _ := r.KeyAt(i)
+			values, err := r.ReadAll(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, v := range values {
+				got[string(key)] = append(got[string(key)], v.UnixNano())
+			}
+		}
+		r.Close()
+	}
+	return got
+}
@@ -33,9 +33,14 @@ Usage: freetsd-ctl [[command] [arguments]]
 
 The commands are:
 
+    ae                   shows anti-entropy status or repairs shard replicas
+    archive-shard        archives a shard to an archive directory
     backup               downloads a snapshot of a data node and saves it to disk
     config               display the default configuration
+    copy-shard           copies a shard from one data node to another
     help                 display this help message
+    hh                   lists, purges or replays hinted handoff queues
+    move-shard           moves a shard from one data node to another
     restore              uses a snapshot of a data node to rebuild a cluster
     run                  run node with existing configuration
     version              displays the FreeTSDB version
@@ -43,4 +48,9 @@ The commands are:
 "run" is the default command.
 
 Use "freetsd-ctl [command] -help" for more information about a command.
+
+If the cluster TCP port requires TLS, and optionally a shared secret, set the
+matching [coordinator] options as environment variables, for example
+FREETSDB_COORDINATOR_TLS_ENABLED, FREETSDB_COORDINATOR_TLS_CERTIFICATE and
+FREETSDB_COORDINATOR_INTERNAL_SHARED_SECRET.
 `
@@ -0,0 +1,250 @@
+// Package hh is the hinted handoff subcommand of the freetsd-ctl command.
+package hh
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/freetsdb/freetsdb/services/hh"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tcp"
+)
+
+// Command represents the program execution for "freetsd-ctl hh".
+type Command struct {
+	Stdout io.Writer
+	Stderr io.Writer
+
+	MetaAddr string
+	NodeAddr string
+	NodeID   uint64
+
+	// Dialer connects to the cluster TCP port of data nodes.
+	Dialer *tcp.Dialer
+}
+
+// NewCommand returns a new instance of Command with default settings.
+func NewCommand() *Command {
+	return &Command{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+}
+
+// Run executes the program.
+func (cmd *Command) Run(args ...string) error {
+	if len(args) == 0 {
+		cmd.printUsage()
+		return errors.New("subcommand required")
+	}
+
+	sub, args := args[0], args[1:]
+	if err := cmd.parseFlags(sub, args); err != nil {
+		return err
+	}
+
+	hosts, err := cmd.hosts()
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "list":
+		return cmd.list(hosts)
+	case "purge":
+		return cmd.purge(hosts)
+	case "replay":
+		return cmd.replay(hosts)
+	default:
+		cmd.printUsage()
+		return fmt.Errorf("unknown subcommand %q", sub)
+	}
+}
+
+// parseFlags parses and validates the command line arguments.
+func (cmd *Command) parseFlags(sub string, args []string) error {
+	fs := flag.NewFlagSet("hh "+sub, flag.ContinueOnError)
+	fs.StringVar(&cmd.MetaAddr, "meta", "localhost:8091", "")
+	fs.StringVar(&cmd.NodeAddr, "node", "", "")
+	fs.SetOutput(cmd.Stderr)
+	fs.Usage = cmd.printUsage
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if sub == "purge" || sub == "replay" {
+		if fs.NArg() != 1 {
+			return errors.New("destination node id required")
+		}
+		id, err := strconv.ParseUint(fs.Arg(0), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid node id: %s", fs.Arg(0))
+		}
+		cmd.NodeID = id
+	}
+	return nil
+}
+
+// hosts returns the TCP addresses of the data nodes the command applies to.
+// If no node was given, every data node in the cluster is used.
+func (cmd *Command) hosts() ([]string, error) {
+	if cmd.NodeAddr != "" {
+		return []string{cmd.NodeAddr}, nil
+	}
+
+	peers, err := cmd.getMetaServers(cmd.MetaAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("Failed to get MetaServerInfo: empty Peers")
+	}
+
+	metaClient := meta.NewClient(nil)
+	metaClient.SetMetaServers(peers)
+	if err := metaClient.Open(); err != nil {
+		return nil, err
+	}
+	defer metaClient.Close()
+
+	nodes, err := metaClient.DataNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		hosts = append(hosts, n.TCPHost)
+	}
+	return hosts, nil
+}
+
+// list prints the hinted handoff queues held on hosts.
+func (cmd *Command) list(hosts []string) error {
+	tw := tabwriter.NewWriter(cmd.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(tw, "Node\tDestination\tActive\tBytes\tWrites\tPoints\tMax Size\tOldest\tLast Modified\tError")
+	for _, host := range hosts {
+		status, err := hh.NewClient(host, cmd.Dialer).Status()
+		if err != nil {
+			fmt.Fprintf(tw, "%s\t\t\t\t\t\t\t\t\t%s\n", host, err)
+			continue
+		}
+		for _, st := range status {
+			fmt.Fprintf(tw, "%s\t%d\t%t\t%d\t%d\t%d\t%d\t%s\t%s\t\n",
+				host, st.NodeID, st.Active, st.Bytes, st.Writes, st.Points, st.MaxSize,
+				formatTime(st.Oldest), formatTime(st.LastModified))
+		}
+	}
+	return tw.Flush()
+}
+
+// purge deletes the queue held for the destination node on hosts.
+func (cmd *Command) purge(hosts []string) error {
+	return cmd.each(hosts, "Purged", func(c *hh.Client) error { return c.Purge(cmd.NodeID) })
+}
+
+// replay makes hosts send the queue held for the destination node now.
+func (cmd *Command) replay(hosts []string) error {
+	return cmd.each(hosts, "Replay started", func(c *hh.Client) error { return c.Replay(cmd.NodeID) })
+}
+
+// each calls fn for each host. When every data node is targeted, hosts
+// without a queue for the destination are skipped.
+func (cmd *Command) each(hosts []string, action string, fn func(c *hh.Client) error) error {
+	var n int
+	for _, host := range hosts {
+		c := hh.NewClient(host, cmd.Dialer)
+		if cmd.NodeAddr == "" {
+			if ok, err := hasQueue(c, cmd.NodeID); err != nil {
+				return fmt.Errorf("%s: %s", host, err)
+			} else if !ok {
+				continue
+			}
+		}
+
+		if err := fn(c); err != nil {
+			return fmt.Errorf("%s: %s", host, err)
+		}
+		fmt.Fprintf(cmd.Stdout, "%s queue for node %d on %s\n", action, cmd.NodeID, host)
+		n++
+	}
+
+	if n == 0 {
+		return fmt.Errorf("no hinted handoff queue for node %d", cmd.NodeID)
+	}
+	return nil
+}
+
+// hasQueue returns true if the node behind c holds a queue for nodeID.
+func hasQueue(c *hh.Client, nodeID uint64) (bool, error) {
+	status, err := c.Status()
+	if err != nil {
+		return false, err
+	}
+	for _, st := range status {
+		if st.NodeID == nodeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func (cmd *Command) getMetaServers(metaAddr string) ([]string, error) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/meta-servers", metaAddr))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.New(string(b))
+	}
+
+	peers := []string{}
+	if err := json.NewDecoder(resp.Body).Decode(&peers); err != nil {
+		return nil, err
+	}
+
+	return peers, nil
+}
+
+// printUsage prints the usage message to STDERR.
+func (cmd *Command) printUsage() {
+	fmt.Fprintf(cmd.Stderr, `usage: freetsd-ctl hh list [flags]
+       freetsd-ctl hh purge [flags] <node-id>
+       freetsd-ctl hh replay [flags] <node-id>
+
+Lists the hinted handoff queues that data nodes hold for unreachable nodes,
+deletes the queues held for a destination node, or makes them send their
+data to the destination node without waiting for the retry interval.
+
+Options:
+  -meta <addr>
+        Optional. The HTTP address of a meta node. Defaults to localhost:8091.
+  -node <addr>
+        Optional. The TCP address of a single data node. Defaults to every
+        data node in the cluster.
+
+`)
+}
@@ -9,10 +9,17 @@ import (
 	"time"
 
 	"github.com/freetsdb/freetsdb/cmd"
+	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/ae"
+	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/archive"
 	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/backup"
 	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/help"
+	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/hh"
 	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/node"
 	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/restore"
+	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/shard"
+	"github.com/freetsdb/freetsdb/coordinator"
+	"github.com/freetsdb/freetsdb/tcp"
+	"github.com/freetsdb/freetsdb/toml"
 )
 
 // These variables are populated via the Go linker.
@@ -65,6 +72,11 @@ func NewMain() *Main {
 func (m *Main) Run(args ...string) error {
 	name, args := cmd.ParseCommandName(args)
 
+	dialer, err := newDialer(os.Getenv)
+	if err != nil {
+		return err
+	}
+
 	// Extract name from args.
 	switch name {
 	case "", "help":
@@ -74,16 +86,43 @@ func (m *Main) Run(args ...string) error {
 
 	case "backup":
 		name := backup.NewCommand()
+		name.Dialer = dialer
 		if err := name.Run(args...); err != nil {
 			return fmt.Errorf("backup: %s", err)
 		}
 	case "restore":
 		name := restore.NewCommand()
+		name.Dialer = dialer
 		if err := name.Run(args...); err != nil {
 			return fmt.Errorf("restore: %s", err)
 		}
 	case "add-meta", "remove-meta", "add-data", "remove-data", "show":
 		cmd := node.NewCommand(name)
+		cmd.Dialer = dialer
+		if err := cmd.Run(args...); err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+	case "ae":
+		name := ae.NewCommand()
+		name.Dialer = dialer
+		if err := name.Run(args...); err != nil {
+			return fmt.Errorf("ae: %s", err)
+		}
+	case "hh":
+		name := hh.NewCommand()
+		name.Dialer = dialer
+		if err := name.Run(args...); err != nil {
+			return fmt.Errorf("hh: %s", err)
+		}
+	case "archive-shard":
+		name := archive.NewCommand()
+		name.Dialer = dialer
+		if err := name.Run(args...); err != nil {
+			return fmt.Errorf("archive-shard: %s", err)
+		}
+	case "copy-shard", "move-shard":
+		cmd := shard.NewCommand(name)
+		cmd.Dialer = dialer
 		if err := cmd.Run(args...); err != nil {
 			return fmt.Errorf("%s: %s", name, err)
 		}
@@ -94,6 +133,28 @@ func (m *Main) Run(args ...string) error {
 	return nil
 }
 
+// newDialer returns the dialer used to reach the cluster TCP port of data
+// nodes, built from the [coordinator] TLS and shared secret settings given as
+// FREETSDB_COORDINATOR_* environment variables.
+func newDialer(getenv func(string) string) (*tcp.Dialer, error) {
+	c := coordinator.NewConfig()
+	if err := toml.ApplyEnvOverrides(getenv, "FREETSDB_COORDINATOR", &c); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("coordinator config: %s", err)
+	}
+
+	_, tlsConfig, err := c.MuxTLSConfigs(nil)
+	if err != nil {
+		return nil, fmt.Errorf("coordinator tls configuration: %s", err)
+	}
+	return &tcp.Dialer{
+		TLSConfig: tlsConfig,
+		Secret:    []byte(c.InternalSharedSecret),
+	}, nil
+}
+
 // VersionCommand represents the command executed by "freetsd-ctl version".
 type VersionCommand struct {
 	Stdout io.Writer
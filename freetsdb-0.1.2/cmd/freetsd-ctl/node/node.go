@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/freetsdb/freetsdb/services/meta"
 	"github.com/freetsdb/freetsdb/tcp"
@@ -24,6 +25,9 @@ type Command struct {
 
 	// TODO: when the new meta stuff is done this should not be exported or be gone
 	MetaConfig *meta.Config
+
+	// Dialer connects to the cluster TCP port of data nodes.
+	Dialer *tcp.Dialer
 }
 
 // NewCommand returns a new instance of Command with default settings.
@@ -202,7 +206,7 @@ func (cmd *Command) addData(metaAddr, newNodeAddr string) error {
 	r.Type = RequestClusterJoin
 	r.Peers = peers
 
-	conn, err := tcp.Dial("tcp", newNodeAddr, NodeMuxHeader)
+	conn, err := cmd.Dialer.Dial("tcp", newNodeAddr, NodeMuxHeader)
 	if err != nil {
 		return err
 	}
@@ -281,7 +285,14 @@ func (cmd *Command) nodeInfo(metaAddr string) error {
 
 	fmt.Fprintln(cmd.Stdout, "Data Nodes:")
 	for _, n := range dataNodes {
-		fmt.Fprintln(cmd.Stdout, n.ID, "    ", n.TCPHost)
+		status, lastSeen := n.Status, "-"
+		if status == "" {
+			status = "unknown"
+		}
+		if !n.LastSeen.IsZero() {
+			lastSeen = n.LastSeen.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintln(cmd.Stdout, n.ID, "    ", n.TCPHost, "    ", status, "    ", lastSeen)
 	}
 	fmt.Fprintln(cmd.Stdout, "")
 
@@ -0,0 +1,294 @@
+package restore
+
+import (
+	"archive/tar"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/backup_util"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/services/snapshotter"
+)
+
+// clusterShard is the shard of the cluster that a backed up shard is
+// restored into.
+type clusterShard struct {
+	database string
+	policy   string
+	shard    meta.ShardInfo
+}
+
+// clusterMeta is the part of the meta client used to create the restored
+// databases, retention policies and shard groups in a cluster.
+type clusterMeta interface {
+	Database(name string) *meta.DatabaseInfo
+	CreateDatabase(name string) (*meta.DatabaseInfo, error)
+	SetDatabaseConsistency(name, level string) error
+	RetentionPolicy(database, name string) (*meta.RetentionPolicyInfo, error)
+	CreateRetentionPolicy(database string, rpi *meta.RetentionPolicyInfo) (*meta.RetentionPolicyInfo, error)
+	SetDefaultRetentionPolicy(database, name string) error
+	CreateShardGroup(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error)
+}
+
+// runCluster restores a portable backup to a cluster. The databases and
+// retention policies are created through the meta service, along with a new
+// shard group for every backed up shard group. The backed up shards are then
+// imported into the new shards on each of their owners.
+func (cmd *Command) runCluster() error {
+	client, err := backup_util.OpenMetaClient(cmd.metaAddr)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	data, err := cmd.loadPortableMeta()
+	if err != nil {
+		cmd.StderrLogger.Printf("error reading meta: %v", err)
+		return err
+	}
+
+	shards, err := cmd.createClusterMeta(client, data)
+	if err != nil {
+		cmd.StderrLogger.Printf("error updating meta: %v", err)
+		return err
+	}
+
+	if err := cmd.importClusterShards(client, shards); err != nil {
+		cmd.StderrLogger.Printf("error updating shards: %v", err)
+		return err
+	}
+	return nil
+}
+
+// loadPortableMeta reads the meta data from the portable backup.
+func (cmd *Command) loadPortableMeta() (*meta.Data, error) {
+	fileBytes, err := ioutil.ReadFile(filepath.Join(cmd.backupFilesPath, cmd.manifestMeta.FileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var ep backup_util.PortablePacker
+	if err := ep.UnmarshalBinary(fileBytes); err != nil {
+		return nil, err
+	}
+
+	var data meta.Data
+	if err := data.UnmarshalBinary(ep.Data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// createClusterMeta creates the backed up databases, retention policies and
+// shard groups in the cluster. It returns the shard each backed up shard is
+// restored into, by backed up shard ID.
+//
+// The meta service assigns the shards of each new group to the current data
+// nodes. If the group has a different number of shards than the backed up
+// group, several backed up shards are restored into the same shard.
+func (cmd *Command) createClusterMeta(client clusterMeta, data *meta.Data) (map[uint64]clusterShard, error) {
+	shards := make(map[uint64]clusterShard)
+	for _, dbi := range data.Databases {
+		if cmd.sourceDatabase != "" && dbi.Name != cmd.sourceDatabase {
+			continue
+		}
+
+		database := dbi.Name
+		if cmd.destinationDatabase != "" {
+			database = cmd.destinationDatabase
+		}
+
+		newDB := client.Database(database) == nil
+		if newDB {
+			if _, err := client.CreateDatabase(database); err != nil {
+				return nil, err
+			}
+			if dbi.Consistency != "" {
+				if err := client.SetDatabaseConsistency(database, dbi.Consistency); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		for _, rpi := range dbi.RetentionPolicies {
+			if cmd.backupRetention != "" && rpi.Name != cmd.backupRetention {
+				continue
+			}
+
+			policy := rpi.Name
+			if cmd.restoreRetention != "" {
+				policy = cmd.restoreRetention
+			}
+
+			if rp, err := client.RetentionPolicy(database, policy); err != nil {
+				return nil, err
+			} else if rp != nil {
+				return nil, fmt.Errorf("retention policy already exists: %s.%s", database, policy)
+			}
+
+			if !cmd.dryRun && !cmd.verify {
+				cmd.StdoutLogger.Printf("Creating retention policy %s.%s", database, policy)
+			}
+			if _, err := client.CreateRetentionPolicy(database, &meta.RetentionPolicyInfo{
+				Name:               policy,
+				ReplicaN:           rpi.ReplicaN,
+				Duration:           rpi.Duration,
+				ShardGroupDuration: rpi.ShardGroupDuration,
+				Consistency:        rpi.Consistency,
+			}); err != nil {
+				return nil, err
+			}
+			if newDB && dbi.DefaultRetentionPolicy == rpi.Name {
+				if err := client.SetDefaultRetentionPolicy(database, policy); err != nil {
+					return nil, err
+				}
+			}
+
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() || len(sgi.Shards) == 0 {
+					continue
+				}
+
+				newSGI, err := client.CreateShardGroup(database, policy, sgi.StartTime)
+				if err != nil {
+					return nil, err
+				} else if newSGI == nil || len(newSGI.Shards) == 0 {
+					return nil, fmt.Errorf("shard group for %s not created in %s.%s", sgi.StartTime, database, policy)
+				}
+
+				for i, si := range sgi.Shards {
+					shards[si.ID] = clusterShard{
+						database: database,
+						policy:   policy,
+						shard:    newSGI.Shards[i%len(newSGI.Shards)],
+					}
+				}
+			}
+		}
+	}
+	return shards, nil
+}
+
+// importClusterShards imports the backed up shard files into their new
+// shards on every owner.
+func (cmd *Command) importClusterShards(client *meta.Client, shards map[uint64]clusterShard) error {
+	for _, file := range cmd.portableFiles() {
+		// if the shard is not mapped then its metadata was NOT imported
+		// and should be skipped
+		sh, ok := shards[file.ShardID]
+		if !ok {
+			cmd.StdoutLogger.Printf("Meta info not found for shard %d on database %s. Skipping shard file %s", file.ShardID, file.Database, file.FileName)
+			continue
+		}
+
+		for _, owner := range sh.shard.Owners {
+			ni, err := client.DataNode(owner.NodeID)
+			if err != nil {
+				return err
+			}
+
+			cmd.StdoutLogger.Printf("Restoring shard %d into shard %d on %s from backup %s\n", file.ShardID, sh.shard.ID, ni.TCPHost, file.FileName)
+			if err := cmd.importClusterShard(ni.TCPHost, file, sh); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importClusterShard uploads a backed up shard file to a data node.
+func (cmd *Command) importClusterShard(host string, file *backup_util.Entry, sh clusterShard) error {
+	r, err := cmd.openShard(file)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return snapshotter.NewClient(host, cmd.Dialer).ImportShard(sh.shard.ID, sh.database, sh.policy, tar.NewReader(r))
+}
+
+// portableFiles returns the manifest entries of the backed up shards selected
+// for the restore, ordered by shard ID.
+func (cmd *Command) portableFiles() []*backup_util.Entry {
+	var files []*backup_util.Entry
+	for _, file := range cmd.manifestFiles {
+		if cmd.sourceDatabase != "" && cmd.sourceDatabase != file.Database {
+			continue
+		} else if cmd.backupRetention != "" && cmd.backupRetention != file.Policy {
+			continue
+		} else if cmd.shard != 0 && cmd.shard != file.ShardID {
+			continue
+		}
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].ShardID < files[j].ShardID })
+	return files
+}
+
+// dryRunMeta applies the changes of a cluster restore to a copy of the meta
+// data of the cluster instead of the meta service. It is used to check and
+// report a restore without making it.
+type dryRunMeta struct {
+	data *meta.Data
+}
+
+// Database returns the database with the given name.
+func (m *dryRunMeta) Database(name string) *meta.DatabaseInfo {
+	return m.data.Database(name)
+}
+
+// CreateDatabase creates a database or returns it if it already exists.
+func (m *dryRunMeta) CreateDatabase(name string) (*meta.DatabaseInfo, error) {
+	if err := m.data.CreateDatabase(name); err != nil {
+		return nil, err
+	}
+	return m.data.Database(name), nil
+}
+
+// SetDatabaseConsistency sets the default write consistency of a database.
+func (m *dryRunMeta) SetDatabaseConsistency(name, level string) error {
+	return m.data.SetDatabaseConsistency(name, level)
+}
+
+// RetentionPolicy returns the retention policy of a database.
+func (m *dryRunMeta) RetentionPolicy(database, name string) (*meta.RetentionPolicyInfo, error) {
+	return m.data.RetentionPolicy(database, name)
+}
+
+// CreateRetentionPolicy creates a retention policy or returns it if it
+// already exists.
+func (m *dryRunMeta) CreateRetentionPolicy(database string, rpi *meta.RetentionPolicyInfo) (*meta.RetentionPolicyInfo, error) {
+	if rp, _ := m.data.RetentionPolicy(database, rpi.Name); rp != nil {
+		return rp, nil
+	}
+
+	if rpi.Duration < meta.MinRetentionPolicyDuration && rpi.Duration != 0 {
+		return nil, meta.ErrRetentionPolicyDurationTooLow
+	}
+
+	if err := m.data.CreateRetentionPolicy(database, rpi, false); err != nil {
+		return nil, err
+	}
+	return m.data.RetentionPolicy(database, rpi.Name)
+}
+
+// SetDefaultRetentionPolicy sets the default retention policy of a database.
+func (m *dryRunMeta) SetDefaultRetentionPolicy(database, name string) error {
+	return m.data.SetDefaultRetentionPolicy(database, name)
+}
+
+// CreateShardGroup creates a shard group for the timestamp or returns the
+// one that already exists.
+func (m *dryRunMeta) CreateShardGroup(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error) {
+	if sg, _ := m.data.ShardGroupByTimestamp(database, policy, timestamp); sg != nil {
+		return sg, nil
+	}
+
+	if err := m.data.CreateShardGroup(database, policy, timestamp); err != nil {
+		return nil, err
+	}
+	return m.data.ShardGroupByTimestamp(database, policy, timestamp)
+}
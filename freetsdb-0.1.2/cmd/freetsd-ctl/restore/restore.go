@@ -15,6 +15,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	gzip "github.com/klauspost/pgzip"
 
@@ -22,6 +23,7 @@ import (
 	tarstream "github.com/freetsdb/freetsdb/pkg/tar"
 	"github.com/freetsdb/freetsdb/services/meta"
 	"github.com/freetsdb/freetsdb/services/snapshotter"
+	"github.com/freetsdb/freetsdb/tcp"
 )
 
 // Command represents the program execution for "freetsd-ctl restore".
@@ -34,6 +36,9 @@ type Command struct {
 	Stderr io.Writer
 	Stdout io.Writer
 
+	// Dialer connects to the cluster TCP port of data nodes.
+	Dialer *tcp.Dialer
+
 	host   string
 	client *snapshotter.Client
 
@@ -47,9 +52,17 @@ type Command struct {
 	shard               uint64
 	portable            bool
 	online              bool
+	cluster             bool
+	metaAddr            string
+	verify              bool
+	dryRun              bool
 	manifestMeta        *backup_util.MetaEntry
 	manifestFiles       map[uint64]*backup_util.Entry
 
+	// manifestChains holds the incremental backups of each shard up to the
+	// point in time being restored, oldest first.
+	manifestChains map[uint64][]backup_util.Entry
+
 	// TODO: when the new meta stuff is done this should not be exported or be gone
 	MetaConfig *meta.Config
 
@@ -74,7 +87,22 @@ func (cmd *Command) Run(args ...string) error {
 		return err
 	}
 
-	if cmd.portable {
+	// Verify and dry run only check and report a restore.
+	if cmd.verify || cmd.dryRun {
+		if cmd.verify {
+			if err := cmd.runVerify(); err != nil {
+				return err
+			}
+		}
+		if cmd.dryRun {
+			return cmd.runDryRun()
+		}
+		return nil
+	}
+
+	if cmd.cluster {
+		return cmd.runCluster()
+	} else if cmd.portable {
 		return cmd.runOnlinePortable()
 	} else if cmd.online {
 		return cmd.runOnlineLegacy()
@@ -146,6 +174,12 @@ func (cmd *Command) parseFlags(args []string) error {
 	fs.Uint64Var(&cmd.shard, "shard", 0, "")
 	fs.BoolVar(&cmd.online, "online", false, "")
 	fs.BoolVar(&cmd.portable, "portable", false, "")
+	fs.BoolVar(&cmd.cluster, "cluster", false, "")
+	fs.StringVar(&cmd.metaAddr, "meta", "localhost:8091", "")
+	var timeArg string
+	fs.StringVar(&timeArg, "time", "", "")
+	fs.BoolVar(&cmd.verify, "verify", false, "")
+	fs.BoolVar(&cmd.dryRun, "dry-run", false, "")
 	fs.SetOutput(cmd.Stdout)
 	fs.Usage = cmd.printUsage
 	if err := fs.Parse(args); err != nil {
@@ -154,7 +188,7 @@ func (cmd *Command) parseFlags(args []string) error {
 
 	cmd.MetaConfig = meta.NewConfig()
 	cmd.MetaConfig.Dir = cmd.metadir
-	cmd.client = snapshotter.NewClient(cmd.host)
+	cmd.client = snapshotter.NewClient(cmd.host, cmd.Dialer)
 
 	// Require output path.
 	cmd.backupFilesPath = fs.Arg(0)
@@ -167,6 +201,11 @@ func (cmd *Command) parseFlags(args []string) error {
 		return fmt.Errorf("backup path should be a valid directory: %s", cmd.backupFilesPath)
 	}
 
+	// Cluster restores read portable backups.
+	if cmd.cluster {
+		cmd.portable = true
+	}
+
 	if cmd.portable || cmd.online {
 		// validate the arguments
 
@@ -183,7 +222,28 @@ func (cmd *Command) parseFlags(args []string) error {
 		}
 
 		if cmd.portable {
-			var err error
+			at := time.Now().UTC()
+			if timeArg != "" {
+				if at, err = time.Parse(time.RFC3339, timeArg); err != nil {
+					return err
+				}
+			}
+
+			// Incremental backups are restored to the newest backup taken
+			// at or before the point in time.
+			cmd.manifestMeta, cmd.manifestChains, err = backup_util.LoadPointInTime(cmd.backupFilesPath, at)
+			if err != nil {
+				return fmt.Errorf("restore failed while processing manifest files: %s", err.Error())
+			} else if cmd.manifestMeta != nil {
+				cmd.manifestFiles = make(map[uint64]*backup_util.Entry, len(cmd.manifestChains))
+				for id, chain := range cmd.manifestChains {
+					cmd.manifestFiles[id] = &chain[len(chain)-1]
+				}
+				return nil
+			} else if timeArg != "" {
+				return fmt.Errorf("no incremental backups taken at or before %s in %s", timeArg, cmd.backupFilesPath)
+			}
+
 			cmd.manifestMeta, cmd.manifestFiles, err = backup_util.LoadIncremental(cmd.backupFilesPath)
 			if err != nil {
 				return fmt.Errorf("restore failed while processing manifest files: %s", err.Error())
@@ -398,27 +458,21 @@ func (cmd *Command) uploadShardsPortable() error {
 						continue
 					}
 					cmd.StdoutLogger.Printf("Restoring shard %d live from backup %s\n", file.ShardID, file.FileName)
-					f, err := os.Open(filepath.Join(cmd.backupFilesPath, file.FileName))
+					r, err := cmd.openShard(file)
 					if err != nil {
-						f.Close()
 						return err
 					}
-					gr, err := gzip.NewReader(f)
-					if err != nil {
-						f.Close()
-						return err
-					}
-					tr := tar.NewReader(gr)
+					tr := tar.NewReader(r)
 					targetDB := cmd.destinationDatabase
 					if targetDB == "" {
 						targetDB = file.Database
 					}
 
 					if err := cmd.client.UploadShard(oldID, newID, targetDB, cmd.restoreRetention, tr); err != nil {
-						f.Close()
+						r.Close()
 						return err
 					}
-					f.Close()
+					r.Close()
 				}
 			}
 		}
@@ -426,6 +480,65 @@ func (cmd *Command) uploadShardsPortable() error {
 	return nil
 }
 
+// openShard returns a reader of the tar archive of a backed up shard file.
+// Shards of incremental backups are replayed from their chain of backups into
+// a temp file first.
+func (cmd *Command) openShard(file *backup_util.Entry) (io.ReadCloser, error) {
+	chain, ok := cmd.manifestChains[file.ShardID]
+	if !ok {
+		f, err := os.Open(filepath.Join(cmd.backupFilesPath, file.FileName))
+		if err != nil {
+			return nil, err
+		}
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &shardFile{Reader: gr, closers: []io.Closer{gr, f}}, nil
+	}
+
+	f, err := ioutil.TempFile("", "freetsd-restore")
+	if err != nil {
+		return nil, err
+	}
+	sf := &shardFile{Reader: f, closers: []io.Closer{f}, tmp: f.Name()}
+
+	cmd.StdoutLogger.Printf("Replaying %d incremental backups of shard %d", len(chain), file.ShardID)
+	if err := backup_util.ReplayChain(cmd.backupFilesPath, chain, f); err != nil {
+		sf.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		sf.Close()
+		return nil, err
+	}
+	return sf, nil
+}
+
+// shardFile is a backed up shard archive opened by openShard.
+type shardFile struct {
+	io.Reader
+	closers []io.Closer
+
+	// tmp is the temp file removed on close, if any.
+	tmp string
+}
+
+// Close closes the archive and removes its temp file.
+func (f *shardFile) Close() error {
+	var err error
+	for _, c := range f.closers {
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	if f.tmp != "" {
+		os.Remove(f.tmp)
+	}
+	return err
+}
+
 // unpackFiles will look for backup files matching the pattern and restore them to the data dir
 func (cmd *Command) uploadShardsLegacy() error {
 	// find the destinationDatabase backup files
@@ -544,22 +657,32 @@ func (cmd *Command) unpackFiles(pat string) error {
 
 // unpackTar will restore a single tar archive to the data dir
 func (cmd *Command) unpackTar(tarFile string) error {
+	shardPath, err := cmd.legacyShardPath(tarFile)
+	if err != nil {
+		return err
+	}
+
 	f, err := os.Open(tarFile)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
 
+	os.MkdirAll(shardPath, 0755)
+
+	return tarstream.Restore(f, shardPath)
+}
+
+// legacyShardPath returns the shard directory in the data dir that a legacy
+// backup file is restored to.
+func (cmd *Command) legacyShardPath(tarFile string) (string, error) {
 	// should get us ["db","rp", "00001", "00"]
 	pathParts := strings.Split(filepath.Base(tarFile), ".")
 	if len(pathParts) != 4 {
-		return fmt.Errorf("backup tarfile name incorrect format")
+		return "", fmt.Errorf("backup tarfile name incorrect format")
 	}
 
-	shardPath := filepath.Join(cmd.datadir, pathParts[0], pathParts[1], strings.Trim(pathParts[2], "0"))
-	os.MkdirAll(shardPath, 0755)
-
-	return tarstream.Restore(f, shardPath)
+	return filepath.Join(cmd.datadir, pathParts[0], pathParts[1], strings.TrimLeft(pathParts[2], "0")), nil
 }
 
 // printUsage prints the usage message to STDERR.
@@ -593,6 +716,23 @@ Options:
     -shard <id>
             Identifier of the shard to be restored. Optional. If specified, then '-db <db_name>' and '-rp <rp_name>' are
             required.
+    -cluster
+            Restore a portable backup to a cluster. Shard groups are created through the meta service and their
+            shards are spread across the current data nodes, which may differ in number from the backed up
+            cluster. '-host' is not used.
+    -meta  <host:port>
+            The HTTP address of a meta node, used with '-cluster'. Defaults to localhost:8091.
+    -time  <2015-12-24T08:12:23Z>
+            Restore incremental backups to the newest backup taken at or before the timestamp (RFC3339 format).
+            The backups of each shard up to it are replayed in order, so the data is restored as of that backup,
+            not as of the timestamp. Optional. Defaults to the latest backup.
+    -verify
+            Check the backup instead of restoring it. The checksums of the backup files recorded in the manifest
+            and the block checksums of every TSM file are verified, and the restore is checked for conflicts
+            with the databases, retention policies and shards of the target.
+    -dry-run
+            Print the databases, retention policies, shard groups, shards and users the restore would create
+            instead of restoring the backup. May be combined with '-verify'.
     PATH
             Path to directory containing the backup files.
 
@@ -0,0 +1,30 @@
+package restore
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Ensure only the leading zeros of the shard ID in a legacy backup file name
+// are dropped, so shard 10 is not restored to the directory of shard 1.
+func TestCommand_legacyShardPath(t *testing.T) {
+	cmd := &Command{datadir: "data"}
+	for _, tt := range []struct {
+		file string
+		exp  string
+	}{
+		{file: "db0.rp0.00001.00", exp: filepath.Join("data", "db0", "rp0", "1")},
+		{file: "db0.rp0.00010.00", exp: filepath.Join("data", "db0", "rp0", "10")},
+		{file: filepath.Join("backup", "db0.rp0.00100.01"), exp: filepath.Join("data", "db0", "rp0", "100")},
+	} {
+		if got, err := cmd.legacyShardPath(tt.file); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.file, err)
+		} else if got != tt.exp {
+			t.Fatalf("%s: unexpected path: got %s, exp %s", tt.file, got, tt.exp)
+		}
+	}
+
+	if _, err := cmd.legacyShardPath("db0.rp0.00001"); err == nil {
+		t.Fatal("expected error for a malformed file name")
+	}
+}
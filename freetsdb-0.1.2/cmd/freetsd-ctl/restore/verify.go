@@ -0,0 +1,541 @@
+package restore
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tsmverify "github.com/freetsdb/freetsdb/cmd/freets_inspect/verify/tsm"
+	"github.com/freetsdb/freetsdb/cmd/freetsd-ctl/backup_util"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tsdb/engine/tsm1"
+)
+
+// verifier reports the checks of restore -verify and counts the ones that
+// failed.
+type verifier struct {
+	w        io.Writer
+	problems int
+}
+
+// okf reports a passed check.
+func (v *verifier) okf(format string, a ...interface{}) {
+	fmt.Fprintf(v.w, "ok    "+format+"\n", a...)
+}
+
+// failf reports a failed check.
+func (v *verifier) failf(format string, a ...interface{}) {
+	v.problems++
+	fmt.Fprintf(v.w, "FAIL  "+format+"\n", a...)
+}
+
+// checksum compares the checksum of the backup file at path with the one
+// recorded in its manifest. Backups taken before checksums were recorded
+// are only checked to be readable. It returns false if the check failed.
+func (v *verifier) checksum(path, expected string) bool {
+	name := filepath.Base(path)
+	sum, err := backup_util.FileChecksum(path)
+	if err != nil {
+		v.failf("%s: %v", name, err)
+		return false
+	} else if expected == "" {
+		v.okf("%s: no checksum in manifest", name)
+	} else if sum != expected {
+		v.failf("%s: checksum %s does not match %s in manifest", name, sum, expected)
+		return false
+	} else {
+		v.okf("%s: checksum matches manifest", name)
+	}
+	return true
+}
+
+// runVerify checks the backup files and the restore against the target
+// without restoring anything. It returns an error if any check failed.
+func (cmd *Command) runVerify() error {
+	v := &verifier{w: cmd.Stdout}
+
+	if cmd.portable {
+		cmd.verifyPortableFiles(v)
+	} else {
+		cmd.verifyLegacyFiles(v)
+	}
+	cmd.verifyTarget(v)
+
+	if v.problems > 0 {
+		return fmt.Errorf("verify failed with %d problems", v.problems)
+	}
+	fmt.Fprintf(cmd.Stdout, "Backup %s verified\n", cmd.backupFilesPath)
+	return nil
+}
+
+// verifyPortableFiles checks the meta file and the selected shard files of a
+// portable backup against the manifest and the TSM files of every shard.
+// The shards of incremental backups are checked after replaying their chain.
+func (cmd *Command) verifyPortableFiles(v *verifier) {
+	if v.checksum(filepath.Join(cmd.backupFilesPath, cmd.manifestMeta.FileName), cmd.manifestMeta.Checksum) {
+		if _, err := cmd.loadPortableMeta(); err != nil {
+			v.failf("%s: %v", cmd.manifestMeta.FileName, err)
+		}
+	}
+
+	for _, file := range cmd.portableFiles() {
+		chain, ok := cmd.manifestChains[file.ShardID]
+		if !ok {
+			chain = []backup_util.Entry{*file}
+		}
+
+		ok = true
+		for _, e := range chain {
+			if !v.checksum(filepath.Join(cmd.backupFilesPath, e.FileName), e.Checksum) {
+				ok = false
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		r, err := cmd.openShard(file)
+		if err != nil {
+			v.failf("%s: %v", file.FileName, err)
+			continue
+		}
+		verifyShardArchive(v, file.FileName, r)
+		r.Close()
+	}
+}
+
+// verifyLegacyFiles checks the meta file and the TSM files of the selected
+// shards of a legacy backup, which has no manifest.
+func (cmd *Command) verifyLegacyFiles(v *verifier) {
+	if cmd.online || cmd.metadir != "" {
+		if _, err := cmd.loadLegacyMeta(); err != nil {
+			v.failf("meta: %v", err)
+		}
+	}
+
+	files, err := cmd.restoreFiles()
+	if err != nil {
+		v.failf("%v", err)
+		return
+	}
+
+	for _, file := range files {
+		f, err := os.Open(filepath.Join(cmd.backupFilesPath, file.FileName))
+		if err != nil {
+			v.failf("%s: %v", file.FileName, err)
+			continue
+		}
+		verifyShardArchive(v, file.FileName, f)
+		f.Close()
+	}
+}
+
+// verifyShardArchive verifies the block checksums of every TSM file in the
+// shard archive read from r.
+func verifyShardArchive(v *verifier, name string, r io.Reader) {
+	// TSM files can only be read from disk.
+	tmp, err := ioutil.TempDir("", "freetsd-verify")
+	if err != nil {
+		v.failf("%s: %v", name, err)
+		return
+	}
+	defer os.RemoveAll(tmp)
+
+	var files, blocks, broken int
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			v.failf("%s: %v", name, err)
+			return
+		}
+
+		if !strings.HasSuffix(hdr.Name, "."+tsm1.TSMFileExtension) {
+			continue
+		}
+
+		path := filepath.Join(tmp, filepath.Base(hdr.Name))
+		f, err := os.Create(path)
+		if err != nil {
+			v.failf("%s: %v", name, err)
+			return
+		}
+		_, err = io.Copy(f, tr)
+		if e := f.Close(); err == nil {
+			err = e
+		}
+		if err != nil {
+			v.failf("%s: %s: %v", name, hdr.Name, err)
+			return
+		}
+
+		n, total, err := tsmverify.VerifyFile(ioutil.Discard, path)
+		os.Remove(path)
+		if err != nil {
+			v.failf("%s: %s: %v", name, hdr.Name, err)
+			broken++
+			continue
+		} else if n > 0 {
+			v.failf("%s: %s: %d of %d blocks broken", name, hdr.Name, n, total)
+			broken++
+		}
+		files++
+		blocks += total
+	}
+
+	if broken == 0 {
+		v.okf("%s: %d TSM files with %d blocks healthy", name, files, blocks)
+	}
+}
+
+// verifyTarget checks that the restore does not conflict with the target.
+// Offline restores must not overwrite an existing meta store or shards.
+func (cmd *Command) verifyTarget(v *verifier) {
+	plan, err := cmd.plan()
+	if err != nil {
+		v.failf("target: %v", err)
+		return
+	}
+
+	if !cmd.portable && !cmd.online {
+		if cmd.metadir != "" {
+			if _, err := os.Stat(filepath.Join(cmd.metadir, "meta.db")); err == nil {
+				v.failf("target: meta store already present: %s", cmd.metadir)
+			}
+		}
+		for _, file := range plan.files {
+			if path := plan.targets[file.ShardID]; path != "" {
+				if _, err := os.Stat(path); err == nil {
+					v.failf("target: shard already present: %s", path)
+				}
+			}
+		}
+	}
+
+	for _, file := range plan.files {
+		if _, ok := plan.targets[file.ShardID]; !ok {
+			v.failf("target: meta info not found for shard %d on database %s", file.ShardID, file.Database)
+		}
+	}
+	v.okf("target: checked %d shards", len(plan.files))
+}
+
+// restorePlan describes the changes a restore makes to the target.
+type restorePlan struct {
+	// before and after are the meta data of the target before and after the
+	// restore. They are nil if no meta data is restored.
+	before, after *meta.Data
+
+	// files are the restored backup files and targets describes where each
+	// of them is restored to, by backed up shard ID.
+	files   []backup_util.Entry
+	targets map[uint64]string
+}
+
+// plan works out the changes the restore makes to the target without making
+// them. It returns an error if the restore would fail.
+func (cmd *Command) plan() (*restorePlan, error) {
+	files, err := cmd.restoreFiles()
+	if err != nil {
+		return nil, err
+	}
+	plan := &restorePlan{files: files, targets: make(map[uint64]string)}
+
+	if cmd.cluster {
+		return plan, cmd.planCluster(plan)
+	} else if cmd.portable || cmd.online {
+		return plan, cmd.planOnline(plan)
+	}
+	return plan, cmd.planOffline(plan)
+}
+
+// planCluster creates the restored meta data in a copy of the meta data of
+// the cluster, the same way as runCluster does through the meta service.
+func (cmd *Command) planCluster(plan *restorePlan) error {
+	client, err := backup_util.OpenMetaClient(cmd.metaAddr)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	data, err := cmd.loadPortableMeta()
+	if err != nil {
+		return err
+	}
+
+	before := client.Data()
+	m := &dryRunMeta{data: before.Clone()}
+	shards, err := cmd.createClusterMeta(m, data)
+	if err != nil {
+		return err
+	}
+	plan.before, plan.after = &before, m.data
+
+	for _, file := range plan.files {
+		sh, ok := shards[file.ShardID]
+		if !ok {
+			continue
+		}
+
+		var hosts []string
+		for _, owner := range sh.shard.Owners {
+			if ni := m.data.DataNode(owner.NodeID); ni != nil {
+				hosts = append(hosts, ni.TCPHost)
+			}
+		}
+		plan.targets[file.ShardID] = fmt.Sprintf("%s.%s shard %d on %s", sh.database, sh.policy, sh.shard.ID, strings.Join(hosts, ", "))
+	}
+	return nil
+}
+
+// planOnline imports the backed up meta data into a copy of the meta data of
+// the target, the same way as the target does for an online restore.
+func (cmd *Command) planOnline(plan *restorePlan) error {
+	var (
+		data *meta.Data
+		err  error
+	)
+	if cmd.portable {
+		data, err = cmd.loadPortableMeta()
+	} else {
+		data, err = cmd.loadLegacyMeta()
+	}
+	if err != nil {
+		return err
+	}
+
+	before, err := cmd.client.MetastoreBackup()
+	if err != nil {
+		return err
+	}
+
+	after := before.Clone()
+	shardIDMap, _, err := after.ImportData(*data, cmd.sourceDatabase, cmd.destinationDatabase, cmd.backupRetention, cmd.restoreRetention)
+	if err != nil {
+		return err
+	}
+	plan.before, plan.after = before, after
+
+	for _, file := range plan.files {
+		if newID, ok := shardIDMap[file.ShardID]; ok {
+			plan.targets[file.ShardID] = fmt.Sprintf("shard %d on %s", newID, cmd.host)
+		}
+	}
+	return nil
+}
+
+// planOffline works out the shard directories the backup files are unpacked
+// to. The meta store, if restored, is replaced by the backed up one.
+func (cmd *Command) planOffline(plan *restorePlan) error {
+	if cmd.metadir != "" {
+		data, err := cmd.loadLegacyMeta()
+		if err != nil {
+			return err
+		}
+		plan.before, plan.after = &meta.Data{}, data
+	}
+
+	for _, file := range plan.files {
+		path, err := cmd.legacyShardPath(file.FileName)
+		if err != nil {
+			return err
+		}
+		plan.targets[file.ShardID] = path
+	}
+	return nil
+}
+
+// runDryRun prints the changes the restore would make to the target.
+func (cmd *Command) runDryRun() error {
+	plan, err := cmd.plan()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.Stdout, "Dry run of restoring %s, nothing is restored.\n", cmd.backupFilesPath)
+	if plan.after != nil {
+		printMetaChanges(cmd.Stdout, plan.before, plan.after)
+	}
+
+	var lines []string
+	for _, file := range plan.files {
+		if target, ok := plan.targets[file.ShardID]; ok {
+			lines = append(lines, fmt.Sprintf("%s.%s shard %d from %s into %s", file.Database, file.Policy, file.ShardID, file.FileName, target))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s.%s shard %d from %s skipped, meta info not found", file.Database, file.Policy, file.ShardID, file.FileName))
+		}
+	}
+	printSection(cmd.Stdout, "Shards to restore", lines)
+	return nil
+}
+
+// printMetaChanges prints the databases, retention policies, shard groups
+// and users in after that are not in before.
+func printMetaChanges(w io.Writer, before, after *meta.Data) {
+	groups := make(map[uint64]bool)
+	for _, dbi := range before.Databases {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				groups[sgi.ID] = true
+			}
+		}
+	}
+
+	var databases, policies, shardGroups, users []string
+	for _, dbi := range after.Databases {
+		if before.Database(dbi.Name) == nil {
+			databases = append(databases, dbi.Name)
+		}
+
+		for _, rpi := range dbi.RetentionPolicies {
+			if rp, _ := before.RetentionPolicy(dbi.Name, rpi.Name); rp == nil {
+				policies = append(policies, fmt.Sprintf("%s.%s duration %s, shard duration %s, replication %d",
+					dbi.Name, rpi.Name, rpi.Duration, rpi.ShardGroupDuration, rpi.ReplicaN))
+			}
+
+			for _, sgi := range rpi.ShardGroups {
+				if groups[sgi.ID] || sgi.Deleted() {
+					continue
+				}
+
+				shards := make([]string, 0, len(sgi.Shards))
+				for _, si := range sgi.Shards {
+					shards = append(shards, formatShard(si))
+				}
+				shardGroups = append(shardGroups, fmt.Sprintf("%s.%s shard group %d from %s to %s with shards %s",
+					dbi.Name, rpi.Name, sgi.ID, sgi.StartTime.Format(time.RFC3339), sgi.EndTime.Format(time.RFC3339), strings.Join(shards, ", ")))
+			}
+		}
+	}
+
+	for _, ui := range after.Users {
+		if before.User(ui.Name) == nil {
+			if ui.Admin {
+				users = append(users, ui.Name+" (admin)")
+			} else {
+				users = append(users, ui.Name)
+			}
+		}
+	}
+
+	printSection(w, "Databases to create", databases)
+	printSection(w, "Retention policies to create", policies)
+	printSection(w, "Shard groups to create", shardGroups)
+	printSection(w, "Users to create", users)
+}
+
+// formatShard formats the ID of a shard with the IDs of its owners.
+func formatShard(si meta.ShardInfo) string {
+	if len(si.Owners) == 0 {
+		return strconv.FormatUint(si.ID, 10)
+	}
+
+	owners := make([]string, 0, len(si.Owners))
+	for _, owner := range si.Owners {
+		owners = append(owners, strconv.FormatUint(owner.NodeID, 10))
+	}
+	return fmt.Sprintf("%d (nodes %s)", si.ID, strings.Join(owners, ", "))
+}
+
+// printSection prints a titled list of lines, or nothing if it is empty.
+func printSection(w io.Writer, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "%s:\n", title)
+	for _, line := range lines {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+}
+
+// restoreFiles returns the manifest entries of the backup files of the
+// restored shards. Legacy backups have no manifest, so their entries are
+// made from the names of the backup files.
+func (cmd *Command) restoreFiles() ([]backup_util.Entry, error) {
+	var files []backup_util.Entry
+	if cmd.portable {
+		for _, file := range cmd.portableFiles() {
+			files = append(files, *file)
+		}
+		return files, nil
+	}
+
+	pat := cmd.legacyShardPattern()
+	if pat == "" {
+		return nil, nil
+	}
+
+	names, err := filepath.Glob(pat)
+	if err != nil {
+		return nil, err
+	} else if len(names) == 0 {
+		return nil, fmt.Errorf("no backup files for %s in %s", pat, cmd.backupFilesPath)
+	}
+
+	for _, name := range names {
+		// should get us ["db","rp", "00001", "00"]
+		parts := strings.Split(filepath.Base(name), ".")
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("backup tarfile name incorrect format: %s", name)
+		}
+
+		id, err := strconv.ParseUint(parts[2], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, backup_util.Entry{
+			Database: parts[0],
+			Policy:   parts[1],
+			ShardID:  id,
+			FileName: filepath.Base(name),
+		})
+	}
+	return files, nil
+}
+
+// legacyShardPattern returns the pattern of the legacy backup files that are
+// restored, or an empty string if no shards are restored.
+func (cmd *Command) legacyShardPattern() string {
+	pat := filepath.Join(cmd.backupFilesPath, cmd.sourceDatabase)
+	if cmd.online {
+		return pat + ".*"
+	} else if cmd.shard != 0 {
+		return filepath.Join(cmd.backupFilesPath, fmt.Sprintf(backup_util.BackupFilePattern, cmd.sourceDatabase, cmd.backupRetention, cmd.shard)) + ".*"
+	} else if cmd.restoreRetention != "" {
+		return fmt.Sprintf("%s.%s.*", pat, cmd.backupRetention)
+	} else if cmd.datadir != "" {
+		return pat + ".*"
+	}
+	return ""
+}
+
+// loadLegacyMeta reads the meta data from the latest meta store backup of a
+// legacy backup.
+func (cmd *Command) loadLegacyMeta() (*meta.Data, error) {
+	metaFiles, err := filepath.Glob(filepath.Join(cmd.backupFilesPath, backup_util.Metafile+".*"))
+	if err != nil {
+		return nil, err
+	} else if len(metaFiles) == 0 {
+		return nil, fmt.Errorf("no metastore backups in %s", cmd.backupFilesPath)
+	}
+
+	metaBytes, err := backup_util.GetMetaBytes(metaFiles[len(metaFiles)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	var data meta.Data
+	if err := data.UnmarshalBinary(metaBytes); err != nil {
+		return nil, fmt.Errorf("unmarshal: %s", err)
+	}
+	return &data, nil
+}
@@ -0,0 +1,201 @@
+package restore
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/freetsdb/freetsdb/tsdb/engine/tsm1"
+)
+
+// Ensure verify passes for a healthy backup restored to an empty data dir.
+func TestCommand_Verify(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	backupDir, dataDir := filepath.Join(dir, "backup"), filepath.Join(dir, "data")
+	MustWriteLegacyShard(t, backupDir, "db0.rp0.00001.00", false)
+
+	var buf bytes.Buffer
+	cmd := NewCommand()
+	cmd.Stdout = &buf
+	if err := cmd.Run("-verify", "-db", "db0", "-newdb", "db0", "-datadir", dataDir, backupDir); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, buf.String())
+	}
+
+	for _, exp := range []string{
+		"ok    db0.rp0.00001.00: 1 TSM files with 2 blocks healthy\n",
+		"ok    target: checked 1 shards\n",
+		"Backup " + backupDir + " verified\n",
+	} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Fatalf("expected %q in output:\n%s", exp, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "FAIL") {
+		t.Fatalf("unexpected failure in output:\n%s", buf.String())
+	}
+}
+
+// Ensure verify reports TSM files with broken blocks.
+func TestCommand_Verify_CorruptTSM(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	backupDir, dataDir := filepath.Join(dir, "backup"), filepath.Join(dir, "data")
+	MustWriteLegacyShard(t, backupDir, "db0.rp0.00001.00", true)
+
+	var buf bytes.Buffer
+	cmd := NewCommand()
+	cmd.Stdout = &buf
+	if err := cmd.Run("-verify", "-db", "db0", "-newdb", "db0", "-datadir", dataDir, backupDir); err == nil || err.Error() != "verify failed with 1 problems" {
+		t.Fatalf("unexpected error: %v\n%s", err, buf.String())
+	}
+
+	if exp := "FAIL  db0.rp0.00001.00: db0/rp0/1/000000001-000000001.tsm: 1 of 2 blocks broken\n"; !strings.Contains(buf.String(), exp) {
+		t.Fatalf("expected %q in output:\n%s", exp, buf.String())
+	}
+}
+
+// Ensure verify reports a restored shard with no backup file and a shard
+// that is already present in the data dir.
+func TestCommand_Verify_Shards(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	backupDir, dataDir := filepath.Join(dir, "backup"), filepath.Join(dir, "data")
+	MustWriteLegacyShard(t, backupDir, "db0.rp0.00001.00", false)
+
+	t.Run("Missing", func(t *testing.T) {
+		var buf bytes.Buffer
+		cmd := NewCommand()
+		cmd.Stdout = &buf
+		if err := cmd.Run("-verify", "-db", "db0", "-newdb", "db0", "-rp", "rp0", "-shard", "2", "-datadir", dataDir, backupDir); err == nil {
+			t.Fatalf("expected error\n%s", buf.String())
+		}
+
+		if exp := "FAIL  target: no backup files for " + filepath.Join(backupDir, "db0.rp0.00002.*"); !strings.Contains(buf.String(), exp) {
+			t.Fatalf("expected %q in output:\n%s", exp, buf.String())
+		}
+	})
+
+	t.Run("Present", func(t *testing.T) {
+		shardDir := filepath.Join(dataDir, "db0", "rp0", "1")
+		if err := os.MkdirAll(shardDir, 0777); err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dataDir)
+
+		var buf bytes.Buffer
+		cmd := NewCommand()
+		cmd.Stdout = &buf
+		if err := cmd.Run("-verify", "-db", "db0", "-newdb", "db0", "-datadir", dataDir, backupDir); err == nil {
+			t.Fatalf("expected error\n%s", buf.String())
+		}
+
+		if exp := "FAIL  target: shard already present: " + shardDir + "\n"; !strings.Contains(buf.String(), exp) {
+			t.Fatalf("expected %q in output:\n%s", exp, buf.String())
+		}
+	})
+}
+
+// Ensure a dry run reports where each shard is restored to and restores
+// nothing.
+func TestCommand_DryRun(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	backupDir, dataDir := filepath.Join(dir, "backup"), filepath.Join(dir, "data")
+	MustWriteLegacyShard(t, backupDir, "db0.rp0.00001.00", false)
+	MustWriteLegacyShard(t, backupDir, "db0.rp0.00010.00", false)
+
+	var buf bytes.Buffer
+	cmd := NewCommand()
+	cmd.Stdout = &buf
+	if err := cmd.Run("-dry-run", "-db", "db0", "-newdb", "db0", "-datadir", dataDir, backupDir); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, buf.String())
+	}
+
+	exp := "Dry run of restoring " + backupDir + ", nothing is restored.\n" +
+		"Shards to restore:\n" +
+		"  db0.rp0 shard 1 from db0.rp0.00001.00 into " + filepath.Join(dataDir, "db0", "rp0", "1") + "\n" +
+		"  db0.rp0 shard 10 from db0.rp0.00010.00 into " + filepath.Join(dataDir, "db0", "rp0", "10") + "\n"
+	if buf.String() != exp {
+		t.Fatalf("unexpected output:\ngot:\n%s\nexp:\n%s", buf.String(), exp)
+	}
+
+	if _, err := os.Stat(dataDir); !os.IsNotExist(err) {
+		t.Fatalf("expected no data dir, got %v", err)
+	}
+}
+
+// Ensure a dry run fails if a restored shard has no backup file.
+func TestCommand_DryRun_MissingShard(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	backupDir, dataDir := filepath.Join(dir, "backup"), filepath.Join(dir, "data")
+	MustWriteLegacyShard(t, backupDir, "db0.rp0.00001.00", false)
+
+	var buf bytes.Buffer
+	cmd := NewCommand()
+	cmd.Stdout = &buf
+	err := cmd.Run("-dry-run", "-db", "db0", "-newdb", "db0", "-rp", "rp0", "-shard", "2", "-datadir", dataDir, backupDir)
+	if err == nil || !strings.HasPrefix(err.Error(), "no backup files for ") {
+		t.Fatalf("unexpected error: %v\n%s", err, buf.String())
+	}
+}
+
+// MustTempDir returns a new temporary directory.
+func MustTempDir() string {
+	dir, err := ioutil.TempDir("", "freetsd-restore-")
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+// MustWriteLegacyShard writes a legacy shard backup file named name to dir,
+// holding a TSM file with two blocks. If corrupt is true, the data of the
+// first block is changed so its checksum no longer matches.
+func MustWriteLegacyShard(t *testing.T, dir, name string, corrupt bool) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	var tsm bytes.Buffer
+	w, err := tsm1.NewTSMWriter(&tsm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"cpu,host=A#!~#value", "cpu,host=B#!~#value"} {
+		if err := w.Write([]byte(key), []tsm1.Value{tsm1.NewValue(1, 1.0), tsm1.NewValue(2, 2.0)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatal(err)
+	} else if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b := tsm.Bytes()
+	if corrupt {
+		// The first block follows the 5 byte header and its 4 byte checksum.
+		b[10] ^= 0xff
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "db0/rp0/1/000000001-000000001.tsm", Mode: 0644, Size: int64(len(b))}); err != nil {
+		t.Fatal(err)
+	} else if _, err := tw.Write(b); err != nil {
+		t.Fatal(err)
+	} else if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0666); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,207 @@
+// Package shard is the copy-shard and move-shard subcommands of the freetsd-ctl command.
+package shard
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/freetsdb/freetsdb/services/copier"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tcp"
+)
+
+// Command represents the program execution for "freetsd-ctl copy-shard"
+// and "freetsd-ctl move-shard".
+type Command struct {
+	Stdout io.Writer
+	Stderr io.Writer
+
+	Cmd      string
+	MetaAddr string
+
+	SourceAddr string
+	DestAddr   string
+	ShardID    uint64
+
+	// Dialer connects to the cluster TCP port of data nodes.
+	Dialer *tcp.Dialer
+}
+
+// NewCommand returns a new instance of Command with default settings.
+func NewCommand(c string) *Command {
+	return &Command{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+		Cmd:    c,
+	}
+}
+
+// Run executes the program.
+func (cmd *Command) Run(args ...string) error {
+	if err := cmd.parseFlags(args); err != nil {
+		return err
+	}
+
+	peers, err := cmd.getMetaServers(cmd.MetaAddr)
+	if err != nil {
+		return err
+	}
+
+	if len(peers) == 0 {
+		return fmt.Errorf("Failed to get MetaServerInfo: empty Peers")
+	}
+
+	metaClient := meta.NewClient(nil)
+	metaClient.SetMetaServers(peers)
+	if err := metaClient.Open(); err != nil {
+		return err
+	}
+	defer metaClient.Close()
+
+	if err := cmd.copyShard(metaClient); err != nil {
+		return err
+	}
+
+	if cmd.Cmd == "move-shard" {
+		return cmd.removeSource(metaClient)
+	}
+	return nil
+}
+
+// parseFlags parses and validates the command line arguments.
+func (cmd *Command) parseFlags(args []string) error {
+	fs := flag.NewFlagSet(cmd.Cmd, flag.ContinueOnError)
+	fs.StringVar(&cmd.MetaAddr, "meta", "localhost:8091", "")
+	fs.SetOutput(cmd.Stderr)
+	fs.Usage = cmd.printUsage
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 3 {
+		cmd.printUsage()
+		return errors.New("source, destination and shard id required")
+	}
+
+	cmd.SourceAddr = fs.Arg(0)
+	cmd.DestAddr = fs.Arg(1)
+
+	id, err := strconv.ParseUint(fs.Arg(2), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid shard id: %s", fs.Arg(2))
+	}
+	cmd.ShardID = id
+
+	if cmd.SourceAddr == cmd.DestAddr {
+		return errors.New("source and destination must be different nodes")
+	}
+
+	return nil
+}
+
+// copyShard copies the shard to the destination node and registers the
+// destination as a new owner of the shard.
+func (cmd *Command) copyShard(metaClient *meta.Client) error {
+	src, err := metaClient.DataNodeByTCPHost(cmd.SourceAddr)
+	if err != nil {
+		return fmt.Errorf("source %s: %s", cmd.SourceAddr, err)
+	}
+	dst, err := metaClient.DataNodeByTCPHost(cmd.DestAddr)
+	if err != nil {
+		return fmt.Errorf("destination %s: %s", cmd.DestAddr, err)
+	}
+
+	_, _, sgi := metaClient.ShardOwner(cmd.ShardID)
+	if sgi == nil {
+		return meta.ErrShardNotFound
+	}
+
+	for _, si := range sgi.Shards {
+		if si.ID != cmd.ShardID {
+			continue
+		}
+		if !si.OwnedBy(src.ID) {
+			return fmt.Errorf("shard %d is not owned by node %d", cmd.ShardID, src.ID)
+		} else if si.OwnedBy(dst.ID) {
+			return fmt.Errorf("shard %d is already owned by node %d", cmd.ShardID, dst.ID)
+		}
+	}
+
+	if err := copier.NewClient(dst.TCPHost, cmd.Dialer).CopyShard(cmd.ShardID, src.TCPHost); err != nil {
+		return err
+	}
+
+	if err := metaClient.AddShardOwner(cmd.ShardID, dst.ID); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.Stdout, "Copied shard %d from %s to %s\n", cmd.ShardID, src.TCPHost, dst.TCPHost)
+	return nil
+}
+
+// removeSource drops the source node from the shard owners and deletes
+// the shard from the source node.
+func (cmd *Command) removeSource(metaClient *meta.Client) error {
+	src, err := metaClient.DataNodeByTCPHost(cmd.SourceAddr)
+	if err != nil {
+		return err
+	}
+
+	if err := metaClient.RemoveShardOwner(cmd.ShardID, src.ID); err != nil {
+		return err
+	}
+
+	if err := copier.NewClient(src.TCPHost, cmd.Dialer).RemoveShard(cmd.ShardID); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.Stdout, "Removed shard %d from %s\n", cmd.ShardID, src.TCPHost)
+	return nil
+}
+
+func (cmd *Command) getMetaServers(metaAddr string) ([]string, error) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/meta-servers", metaAddr))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.New(string(b))
+	}
+
+	peers := []string{}
+	if err := json.NewDecoder(resp.Body).Decode(&peers); err != nil {
+		return nil, err
+	}
+
+	return peers, nil
+}
+
+// printUsage prints the usage message to STDERR.
+func (cmd *Command) printUsage() {
+	fmt.Fprintf(cmd.Stderr, `usage: freetsd-ctl %s [flags] <source> <destination> <shard-id>
+
+Copies a shard from the source data node to the destination data node and
+adds the destination to the shard owners. move-shard also removes the shard
+from the source data node once the copy has completed.
+
+<source> and <destination> are the TCP bind addresses of the data nodes.
+
+Options:
+  -meta <addr>
+        Optional. The HTTP address of a meta node. Defaults to localhost:8091.
+
+`, cmd.Cmd)
+}
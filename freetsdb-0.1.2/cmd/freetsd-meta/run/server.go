@@ -66,6 +66,9 @@ type Server struct {
 	// tcpAddr is the host:port combination for the TCP listener that services mux onto
 	tcpAddr string
 
+	// muxTLSConfig secures the TCP listener that services mux onto, if set.
+	muxTLSConfig *tls.Config
+
 	config *Config
 }
 
@@ -79,6 +82,16 @@ func updateTLSConfig(into **tls.Config, with *tls.Config) {
 
 // NewServer returns a new instance of Server built from a config.
 func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
+	tlsConfig, err := c.TLS.Parse()
+	if err != nil {
+		return nil, fmt.Errorf("tls configuration: %v", err)
+	}
+
+	// Secure the raft TCP port and every connection dialed to other meta nodes.
+	muxTLSConfig, dialTLSConfig, err := c.Meta.MuxTLSConfigs(tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("meta tls configuration: %v", err)
+	}
 
 	// We need to ensure that a meta directory always exists even if
 	// we don't start the meta store.  node.json is always stored under
@@ -130,12 +143,18 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 
 		tcpAddr: bind,
 
+		muxTLSConfig: muxTLSConfig,
+
 		config: c,
 	}
 
 	s.MetaService = meta.NewService(c.Meta)
 	s.MetaService.Version = s.buildInfo.Version
 	s.MetaService.Node = s.Node
+	s.MetaService.Dialer = &tcp.Dialer{
+		TLSConfig: dialTLSConfig,
+		Secret:    []byte(c.Meta.InternalSharedSecret),
+	}
 
 	return s, nil
 }
@@ -157,6 +176,8 @@ func (s *Server) Open() error {
 
 	// Multiplex listener.
 	mux := tcp.NewMux()
+	mux.TLSConfig = s.muxTLSConfig
+	mux.Secret = []byte(s.config.Meta.InternalSharedSecret)
 	go mux.Serve(ln)
 
 	if s.MetaService != nil {
@@ -13,13 +13,16 @@ import (
 	"github.com/freetsdb/freetsdb/monitor"
 	"github.com/freetsdb/freetsdb/monitor/diagnostics"
 	"github.com/freetsdb/freetsdb/pkg/tlsconfig"
+	"github.com/freetsdb/freetsdb/services/ae"
 	"github.com/freetsdb/freetsdb/services/collectd"
 	"github.com/freetsdb/freetsdb/services/continuous_querier"
 	"github.com/freetsdb/freetsdb/services/graphite"
+	"github.com/freetsdb/freetsdb/services/heartbeat"
 	"github.com/freetsdb/freetsdb/services/hh"
 	"github.com/freetsdb/freetsdb/services/httpd"
 	"github.com/freetsdb/freetsdb/services/opentsdb"
 	"github.com/freetsdb/freetsdb/services/precreator"
+	"github.com/freetsdb/freetsdb/services/rebalance"
 	"github.com/freetsdb/freetsdb/services/retention"
 	"github.com/freetsdb/freetsdb/services/subscriber"
 	"github.com/freetsdb/freetsdb/services/udp"
@@ -44,6 +47,8 @@ type Config struct {
 	Coordinator coordinator.Config `toml:"coordinator"`
 	Retention   retention.Config   `toml:"retention"`
 	Precreator  precreator.Config  `toml:"shard-precreation"`
+	Rebalance   rebalance.Config   `toml:"rebalance"`
+	Heartbeat   heartbeat.Config   `toml:"heartbeat"`
 
 	Monitor        monitor.Config    `toml:"monitor"`
 	Subscriber     subscriber.Config `toml:"subscriber"`
@@ -56,6 +61,7 @@ type Config struct {
 
 	ContinuousQuery continuous_querier.Config `toml:"continuous_queries"`
 	HintedHandoff   hh.Config                 `toml:"hinted-handoff"`
+	AntiEntropy     ae.Config                 `toml:"anti-entropy"`
 
 	// Server reporting
 	ReportingDisabled bool `toml:"reporting-disabled"`
@@ -77,6 +83,8 @@ func NewConfig() *Config {
 	c.Data = tsdb.NewConfig()
 	c.Coordinator = coordinator.NewConfig()
 	c.Precreator = precreator.NewConfig()
+	c.Rebalance = rebalance.NewConfig()
+	c.Heartbeat = heartbeat.NewConfig()
 
 	c.Monitor = monitor.NewConfig()
 	c.Subscriber = subscriber.NewConfig()
@@ -89,6 +97,7 @@ func NewConfig() *Config {
 	c.UDPInputs = []udp.Config{udp.NewConfig()}
 
 	c.ContinuousQuery = continuous_querier.NewConfig()
+	c.AntiEntropy = ae.NewConfig()
 	c.Retention = retention.NewConfig()
 	c.BindAddress = DefaultBindAddress
 
@@ -146,6 +155,10 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.Coordinator.Validate(); err != nil {
+		return fmt.Errorf("invalid coordinator config: %v", err)
+	}
+
 	//if err := c.HintedHandoff.Validate(); err != nil {
 	//	return err
 	//}
@@ -171,10 +184,22 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.Rebalance.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Heartbeat.Validate(); err != nil {
+		return err
+	}
+
 	if err := c.Subscriber.Validate(); err != nil {
 		return err
 	}
 
+	if err := c.AntiEntropy.Validate(); err != nil {
+		return err
+	}
+
 	for _, collectd := range c.CollectdInputs {
 		if err := collectd.Validate(); err != nil {
 			return fmt.Errorf("invalid collectd config: %v", err)
@@ -210,12 +235,15 @@ func (c *Config) diagnosticsClients() map[string]diagnostics.Client {
 		"config-coordinator": c.Coordinator,
 		"config-retention":   c.Retention,
 		"config-precreator":  c.Precreator,
+		"config-rebalance":   c.Rebalance,
+		"config-heartbeat":   c.Heartbeat,
 
 		"config-monitor":    c.Monitor,
 		"config-subscriber": c.Subscriber,
 		"config-httpd":      c.HTTPD,
 
-		"config-cqs": c.ContinuousQuery,
+		"config-cqs":          c.ContinuousQuery,
+		"config-anti-entropy": c.AntiEntropy,
 	}
 
 	// Config settings that can be repeated and can be disabled.
@@ -25,15 +25,18 @@ import (
 	"github.com/freetsdb/freetsdb/monitor"
 	"github.com/freetsdb/freetsdb/platform/storage/reads"
 	"github.com/freetsdb/freetsdb/query"
+	"github.com/freetsdb/freetsdb/services/ae"
 	"github.com/freetsdb/freetsdb/services/collectd"
 	"github.com/freetsdb/freetsdb/services/continuous_querier"
 	"github.com/freetsdb/freetsdb/services/copier"
 	"github.com/freetsdb/freetsdb/services/graphite"
+	"github.com/freetsdb/freetsdb/services/heartbeat"
 	"github.com/freetsdb/freetsdb/services/hh"
 	"github.com/freetsdb/freetsdb/services/httpd"
 	"github.com/freetsdb/freetsdb/services/meta"
 	"github.com/freetsdb/freetsdb/services/opentsdb"
 	"github.com/freetsdb/freetsdb/services/precreator"
+	"github.com/freetsdb/freetsdb/services/rebalance"
 	"github.com/freetsdb/freetsdb/services/retention"
 	"github.com/freetsdb/freetsdb/services/snapshotter"
 	"github.com/freetsdb/freetsdb/services/storage"
@@ -88,6 +91,7 @@ type Server struct {
 	ShardWriter   *coordinator.ShardWriter
 	HintedHandoff *hh.Service
 	Subscriber    *subscriber.Service
+	Rebalancer    *rebalance.Service
 
 	Services []Service
 
@@ -95,6 +99,7 @@ type Server struct {
 	CoordinatorService *coordinator.Service
 	SnapshotterService *snapshotter.Service
 	CopierService      *copier.Service
+	AntiEntropyService *ae.Service
 
 	Monitor *monitor.Monitor
 
@@ -117,6 +122,12 @@ type Server struct {
 	// tcpAddr is the host:port combination for the TCP listener that services mux onto
 	tcpAddr string
 
+	// muxTLSConfig secures the TCP listener that services mux onto, if set.
+	muxTLSConfig *tls.Config
+
+	// dialer connects to the TCP listener of the other data nodes.
+	dialer *tcp.Dialer
+
 	config *Config
 }
 
@@ -144,6 +155,16 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 		updateTLSConfig(&c.OpenTSDBInputs[i].TLS, tlsConfig)
 	}
 
+	// Secure the cluster TCP port and every connection dialed to other nodes.
+	muxTLSConfig, dialTLSConfig, err := c.Coordinator.MuxTLSConfigs(tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("coordinator tls configuration: %v", err)
+	}
+	dialer := &tcp.Dialer{
+		TLSConfig: dialTLSConfig,
+		Secret:    []byte(c.Coordinator.InternalSharedSecret),
+	}
+
 	// We need to ensure that a meta directory always exists even if
 	// we don't start the meta store.  node.json is always stored under
 	// the meta directory.
@@ -195,6 +216,9 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 		httpUseTLS:  c.HTTPD.HTTPSEnabled,
 		tcpAddr:     bind,
 
+		muxTLSConfig: muxTLSConfig,
+		dialer:       dialer,
+
 		config: c,
 	}
 
@@ -208,9 +232,26 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 	s.TSDBStore.EngineOptions.EngineVersion = c.Data.Engine
 	s.TSDBStore.EngineOptions.IndexVersion = c.Data.Index
 
+	// Merge duplicate points using the policy of each shard's retention policy.
+	s.TSDBStore.EngineOptions.DuplicatePolicy = func(database, rp string) string {
+		rpi, err := s.MetaClient.RetentionPolicy(database, rp)
+		if err != nil || rpi == nil {
+			return ""
+		}
+		return rpi.DuplicatePolicy
+	}
+	s.TSDBStore.EngineOptions.Codecs = func(database, rp string) (string, string) {
+		rpi, err := s.MetaClient.RetentionPolicy(database, rp)
+		if err != nil || rpi == nil {
+			return "", ""
+		}
+		return rpi.StringCodec, rpi.FloatCodec
+	}
+
 	// Set the shard writer
 	s.ShardWriter = coordinator.NewShardWriter(time.Duration(c.Coordinator.ShardWriterTimeout),
 		c.Coordinator.MaxRemoteWriteConnections)
+	s.ShardWriter.Dialer = dialer
 
 	// Create the hinted handoff service
 	s.HintedHandoff = hh.NewService(c.HintedHandoff, s.ShardWriter, s.MetaClient)
@@ -219,9 +260,22 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 	// Create the Subscriber service
 	s.Subscriber = subscriber.NewService(c.Subscriber)
 
+	// Create the rebalance service
+	s.Rebalancer = rebalance.NewService(c.Rebalance)
+	s.Rebalancer.MetaClient = s.MetaClient
+	s.Rebalancer.Node = s.Node
+	s.Rebalancer.ShardCopier = rebalance.NewShardCopier(dialer)
+
 	// Initialize points writer.
 	s.PointsWriter = coordinator.NewPointsWriter()
 	s.PointsWriter.WriteTimeout = time.Duration(c.Coordinator.WriteTimeout)
+	if c.Coordinator.WriteConsistency != "" {
+		level, err := coordinator.ParseConsistencyLevel(c.Coordinator.WriteConsistency)
+		if err != nil {
+			return nil, err
+		}
+		s.PointsWriter.DefaultConsistency = level
+	}
 	s.PointsWriter.TSDBStore = s.TSDBStore
 	s.PointsWriter.ShardWriter = s.ShardWriter
 	s.PointsWriter.HintedHandoff = s.HintedHandoff
@@ -232,19 +286,26 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 	metaExecutor := coordinator.NewMetaExecutor()
 	metaExecutor.MetaClient = s.MetaClient
 	metaExecutor.Node = s.Node
+	metaExecutor.Dialer = dialer
 
 	// Initialize query executor.
 	s.QueryExecutor = query.NewExecutor()
 	s.QueryExecutor.StatementExecutor = &coordinator.StatementExecutor{
-		MetaClient:  s.MetaClient,
-		TaskManager: s.QueryExecutor.TaskManager,
-		TSDBStore:   s.TSDBStore,
-		Node:        s.Node,
+		MetaClient:   s.MetaClient,
+		TaskManager:  s.QueryExecutor.TaskManager,
+		TSDBStore:    s.TSDBStore,
+		ArchiveDir:   s.config.Data.ArchiveDir,
+		Dialer:       dialer,
+		MetaExecutor: metaExecutor,
+		Node:         s.Node,
 		ShardMapper: &coordinator.LocalShardMapper{
 			MetaClient: s.MetaClient,
+			Dialer:     dialer,
 			TSDBStore:  coordinator.LocalTSDBStore{Store: s.TSDBStore},
 		},
 		Monitor:           s.Monitor,
+		Rebalancer:        s.Rebalancer,
+		HintedHandoff:     s.HintedHandoff,
 		PointsWriter:      s.PointsWriter,
 		MaxSelectPointN:   c.Coordinator.MaxSelectPointN,
 		MaxSelectSeriesN:  c.Coordinator.MaxSelectSeriesN,
@@ -299,10 +360,22 @@ func (s *Server) appendSnapshotterService() {
 func (s *Server) appendCopierService() {
 	srv := copier.NewService()
 	srv.TSDBStore = s.TSDBStore
+	srv.MetaClient = s.MetaClient
+	srv.Dialer = s.dialer
 	s.Services = append(s.Services, srv)
 	s.CopierService = srv
 }
 
+func (s *Server) appendAntiEntropyService(c ae.Config) {
+	srv := ae.NewService(c)
+	srv.Node = s.Node
+	srv.MetaClient = s.MetaClient
+	srv.TSDBStore = s.TSDBStore
+	srv.Dialer = s.dialer
+	s.Services = append(s.Services, srv)
+	s.AntiEntropyService = srv
+}
+
 func (s *Server) appendMonitorService() {
 	s.Services = append(s.Services, s.Monitor)
 }
@@ -332,6 +405,12 @@ func (s *Server) appendHTTPDService(c httpd.Config) {
 	srv.Handler.PointsWriter = s.PointsWriter
 	srv.Handler.Version = s.buildInfo.Version
 	ss := storage.NewStore(s.TSDBStore, s.MetaClient)
+	ss.Node = s.Node
+	ss.Remote = coordinator.NewRemoteStorage(&coordinator.NodeDialer{
+		MetaClient: s.MetaClient,
+		Dialer:     s.dialer,
+		Timeout:    time.Duration(s.config.Coordinator.ShardMapperTimeout),
+	})
 	srv.Handler.Store = ss
 	srv.Handler.Controller = control.NewController(s.MetaClient, reads.NewReader(ss), authorizer, c.AuthEnabled, s.Logger)
 
@@ -388,6 +467,23 @@ func (s *Server) appendPrecreatorService(c precreator.Config) error {
 	return nil
 }
 
+func (s *Server) appendRebalanceService(c rebalance.Config) {
+	if !c.Enabled {
+		return
+	}
+	s.Services = append(s.Services, s.Rebalancer)
+}
+
+func (s *Server) appendHeartbeatService(c heartbeat.Config) {
+	if !c.Enabled {
+		return
+	}
+	srv := heartbeat.NewService(c)
+	srv.Node = s.Node
+	srv.MetaClient = s.MetaClient
+	s.Services = append(s.Services, srv)
+}
+
 func (s *Server) appendUDPService(c udp.Config) {
 	if !c.Enabled {
 		return
@@ -405,6 +501,11 @@ func (s *Server) appendContinuousQueryService(c continuous_querier.Config) {
 	srv := continuous_querier.NewService(c)
 	srv.MetaClient = s.MetaClient
 	srv.QueryExecutor = s.QueryExecutor
+	srv.ShardMapper = &coordinator.LocalShardMapper{
+		MetaClient: s.MetaClient,
+		Dialer:     s.dialer,
+		TSDBStore:  coordinator.LocalTSDBStore{Store: s.TSDBStore},
+	}
 	srv.Monitor = s.Monitor
 	s.Services = append(s.Services, srv)
 }
@@ -431,6 +532,8 @@ func (s *Server) Open() error {
 
 	// Multiplex listener.
 	mux := tcp.NewMux()
+	mux.TLSConfig = s.muxTLSConfig
+	mux.Secret = []byte(s.config.Coordinator.InternalSharedSecret)
 	go mux.Serve(ln)
 
 	s.Logger.Info("Open Server mux.Serve over")
@@ -457,9 +560,12 @@ func (s *Server) Open() error {
 		s.appendPrecreatorService(s.config.Precreator)
 		s.appendSnapshotterService()
 		s.appendCopierService()
+		s.appendAntiEntropyService(s.config.AntiEntropy)
 		s.appendContinuousQueryService(s.config.ContinuousQuery)
 		s.appendHTTPDService(s.config.HTTPD)
 		s.appendRetentionPolicyService(s.config.Retention)
+		s.appendRebalanceService(s.config.Rebalance)
+		s.appendHeartbeatService(s.config.Heartbeat)
 
 		for _, i := range s.config.GraphiteInputs {
 			if err := s.appendGraphiteService(i); err != nil {
@@ -489,6 +595,8 @@ func (s *Server) Open() error {
 		s.CoordinatorService.Listener = mux.Listen(coordinator.MuxHeader)
 		s.SnapshotterService.Listener = mux.Listen(snapshotter.MuxHeader)
 		s.CopierService.Listener = mux.Listen(copier.MuxHeader)
+		s.AntiEntropyService.Listener = mux.Listen(ae.MuxHeader)
+		s.HintedHandoff.Listener = mux.Listen(hh.MuxHeader)
 
 		// Configure logging for all services and clients.
 		s.MetaClient.WithLogger(s.Logger)
@@ -531,6 +639,8 @@ func (s *Server) Open() error {
 				return fmt.Errorf("open service: %s", err)
 			}
 		}
+
+		go s.watchDuplicatePolicies()
 	}
 
 	// Start the reporting service, if not disabled.
@@ -660,6 +770,19 @@ func (s *Server) reportServer() {
 	go cl.Save(usage)
 }
 
+// watchDuplicatePolicies applies altered duplicate policies of retention
+// policies to the open shards.
+func (s *Server) watchDuplicatePolicies() {
+	for {
+		select {
+		case <-s.MetaClient.WaitForDataChanged():
+			s.TSDBStore.UpdateDuplicatePolicies()
+		case <-s.closing:
+			return
+		}
+	}
+}
+
 // monitorErrorChan reads an error channel and resends it through the server.
 func (s *Server) monitorErrorChan(ch <-chan error) {
 	for {
@@ -3,10 +3,14 @@
 package coordinator
 
 import (
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/freetsdb/freetsdb/monitor/diagnostics"
 	"github.com/freetsdb/freetsdb/query"
+	"github.com/freetsdb/freetsdb/tcp"
 	"github.com/freetsdb/freetsdb/toml"
 )
 
@@ -35,12 +39,18 @@ const (
 	// DefaultMaxSelectSeriesN is the maximum number of series a SELECT can run.
 	// A value of zero will make the maximum series count unlimited.
 	DefaultMaxSelectSeriesN = 0
+
+	// DefaultWriteConsistency is the write consistency level used when
+	// neither the request, the retention policy nor the database set one.
+	// The UDP, collectd, Graphite and OpenTSDB inputs fall back to any instead.
+	DefaultWriteConsistency = "one"
 )
 
 // Config represents the configuration for the cluster service.
 type Config struct {
 	ForceRemoteShardMapping   bool          `toml:"force-remote-mapping"`
 	WriteTimeout              toml.Duration `toml:"write-timeout"`
+	WriteConsistency          string        `toml:"write-consistency"`
 	ShardWriterTimeout        toml.Duration `toml:"shard-writer-timeout"`
 	MaxRemoteWriteConnections int           `toml:"max-remote-write-connections"`
 	ShardMapperTimeout        toml.Duration `toml:"shard-mapper-timeout"`
@@ -51,12 +61,26 @@ type Config struct {
 	MaxSelectPointN      int           `toml:"max-select-point"`
 	MaxSelectSeriesN     int           `toml:"max-select-series"`
 	MaxSelectBucketsN    int           `toml:"max-select-buckets"`
+
+	// TLSEnabled secures the cluster TCP port with TLS. If TLSCACertificate
+	// is set, nodes must also present a certificate signed by it.
+	TLSEnabled            bool   `toml:"tls-enabled"`
+	TLSCertificate        string `toml:"tls-certificate"`
+	TLSPrivateKey         string `toml:"tls-private-key"`
+	TLSCACertificate      string `toml:"tls-ca-certificate"`
+	TLSInsecureSkipVerify bool   `toml:"tls-insecure-skip-verify"`
+
+	// InternalSharedSecret, if set, must be known by every node and tool
+	// connecting to the cluster TCP port. It only authenticates the start of
+	// each connection, so it requires TLSEnabled to protect the rest.
+	InternalSharedSecret string `toml:"internal-shared-secret"`
 }
 
 // NewConfig returns an instance of Config with defaults.
 func NewConfig() Config {
 	return Config{
 		WriteTimeout:              toml.Duration(DefaultWriteTimeout),
+		WriteConsistency:          DefaultWriteConsistency,
 		ShardWriterTimeout:        toml.Duration(DefaultShardWriterTimeout),
 		ShardMapperTimeout:        toml.Duration(DefaultShardMapperTimeout),
 		MaxRemoteWriteConnections: DefaultMaxRemoteWriteConnections,
@@ -68,15 +92,43 @@ func NewConfig() Config {
 	}
 }
 
+// Validate returns an error if the config is invalid.
+func (c Config) Validate() error {
+	if c.TLSEnabled && c.TLSCertificate == "" {
+		return errors.New("tls-certificate must be specified when tls-enabled is true")
+	}
+	if c.InternalSharedSecret != "" && !c.TLSEnabled {
+		return errors.New("tls-enabled must be true when internal-shared-secret is set")
+	}
+	if c.WriteConsistency != "" {
+		if _, err := ParseConsistencyLevel(c.WriteConsistency); err != nil {
+			return fmt.Errorf("invalid write-consistency %q", c.WriteConsistency)
+		}
+	}
+	return nil
+}
+
+// MuxTLSConfigs returns the server and client TLS configurations for the
+// cluster TCP port, or nil if TLS is disabled. base supplies the cipher and
+// version settings and may be nil.
+func (c Config) MuxTLSConfigs(base *tls.Config) (server, client *tls.Config, err error) {
+	if !c.TLSEnabled {
+		return nil, nil, nil
+	}
+	return tcp.TLSConfigs(base, c.TLSCertificate, c.TLSPrivateKey, c.TLSCACertificate, c.TLSInsecureSkipVerify)
+}
+
 // Diagnostics returns a diagnostics representation of a subset of the Config.
 func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 	return diagnostics.RowFromMap(map[string]interface{}{
 		"write-timeout":          c.WriteTimeout,
+		"write-consistency":      c.WriteConsistency,
 		"max-concurrent-queries": c.MaxConcurrentQueries,
 		"query-timeout":          c.QueryTimeout,
 		"log-queries-after":      c.LogQueriesAfter,
 		"max-select-point":       c.MaxSelectPointN,
 		"max-select-series":      c.MaxSelectSeriesN,
 		"max-select-buckets":     c.MaxSelectBucketsN,
+		"tls-enabled":            c.TLSEnabled,
 	}), nil
 }
@@ -14,6 +14,10 @@ func TestConfig_Parse(t *testing.T) {
 	if _, err := toml.Decode(`
 shard-writer-timeout = "10s"
 write-timeout = "20s"
+write-consistency = "quorum"
+tls-enabled = true
+tls-certificate = "/etc/ssl/node.pem"
+internal-shared-secret = "secret"
 `, &c); err != nil {
 		t.Fatal(err)
 	}
@@ -23,5 +27,40 @@ write-timeout = "20s"
 		t.Fatalf("unexpected shard-writer timeout: %s", c.ShardWriterTimeout)
 	} else if time.Duration(c.WriteTimeout) != 20*time.Second {
 		t.Fatalf("unexpected write timeout s: %s", c.WriteTimeout)
+	} else if c.WriteConsistency != "quorum" {
+		t.Fatalf("unexpected write consistency: %s", c.WriteConsistency)
+	} else if !c.TLSEnabled {
+		t.Fatalf("unexpected tls enabled: %v", c.TLSEnabled)
+	} else if c.TLSCertificate != "/etc/ssl/node.pem" {
+		t.Fatalf("unexpected tls certificate: %s", c.TLSCertificate)
+	} else if c.InternalSharedSecret != "secret" {
+		t.Fatalf("unexpected internal shared secret: %s", c.InternalSharedSecret)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	c := coordinator.NewConfig()
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation fail from NewConfig: %s", err)
+	}
+
+	c.InternalSharedSecret = "secret"
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for internal-shared-secret without tls-enabled, got nil")
+	}
+
+	c.TLSEnabled = true
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for tls-enabled without tls-certificate, got nil")
+	}
+
+	c.TLSCertificate = "/etc/ssl/node.pem"
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation fail: %s", err)
+	}
+
+	c.WriteConsistency = "most"
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for invalid write-consistency, got nil")
 	}
 }
@@ -6,9 +6,11 @@
 Package internal is a generated protocol buffer package.
 
 It is generated from these files:
+
 	internal/data.proto
 
 It has these top-level messages:
+
 	WriteShardRequest
 	WriteShardResponse
 	ExecuteStatementRequest
@@ -17,6 +19,20 @@ It has these top-level messages:
 	CreateIteratorResponse
 	FieldDimensionsRequest
 	FieldDimensionsResponse
+	MeasurementNamesRequest
+	MeasurementNamesResponse
+	TagKeysRequest
+	TagKeysResponse
+	TagValuesRequest
+	TagValuesResponse
+	SeriesSketchesRequest
+	SeriesSketchesResponse
+	ReadSeriesRequest
+	ReadSeriesResponse
+	ReadCursorRequest
+	ReadCursorResponse
+	CompactionsRequest
+	CompactionsResponse
 */
 package internal
 
@@ -285,6 +301,414 @@ func (m *FieldDimensionsResponse) GetErr() string {
 	return ""
 }
 
+type MeasurementNamesRequest struct {
+	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Condition        *string `protobuf:"bytes,2,opt,name=Condition" json:"Condition,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *MeasurementNamesRequest) Reset()         { *m = MeasurementNamesRequest{} }
+func (m *MeasurementNamesRequest) String() string { return proto.CompactTextString(m) }
+func (*MeasurementNamesRequest) ProtoMessage()    {}
+
+func (m *MeasurementNamesRequest) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *MeasurementNamesRequest) GetCondition() string {
+	if m != nil && m.Condition != nil {
+		return *m.Condition
+	}
+	return ""
+}
+
+type MeasurementNamesResponse struct {
+	Names            [][]byte `protobuf:"bytes,1,rep,name=Names" json:"Names,omitempty"`
+	Err              *string  `protobuf:"bytes,2,opt,name=Err" json:"Err,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *MeasurementNamesResponse) Reset()         { *m = MeasurementNamesResponse{} }
+func (m *MeasurementNamesResponse) String() string { return proto.CompactTextString(m) }
+func (*MeasurementNamesResponse) ProtoMessage()    {}
+
+func (m *MeasurementNamesResponse) GetNames() [][]byte {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+func (m *MeasurementNamesResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
+type TagKeysRequest struct {
+	ShardIDs         []uint64 `protobuf:"varint,1,rep,name=ShardIDs" json:"ShardIDs,omitempty"`
+	Condition        *string  `protobuf:"bytes,2,opt,name=Condition" json:"Condition,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *TagKeysRequest) Reset()         { *m = TagKeysRequest{} }
+func (m *TagKeysRequest) String() string { return proto.CompactTextString(m) }
+func (*TagKeysRequest) ProtoMessage()    {}
+
+func (m *TagKeysRequest) GetShardIDs() []uint64 {
+	if m != nil {
+		return m.ShardIDs
+	}
+	return nil
+}
+
+func (m *TagKeysRequest) GetCondition() string {
+	if m != nil && m.Condition != nil {
+		return *m.Condition
+	}
+	return ""
+}
+
+type TagKeysResponse struct {
+	TagKeys          []byte  `protobuf:"bytes,1,opt,name=TagKeys" json:"TagKeys,omitempty"`
+	Err              *string `protobuf:"bytes,2,opt,name=Err" json:"Err,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *TagKeysResponse) Reset()         { *m = TagKeysResponse{} }
+func (m *TagKeysResponse) String() string { return proto.CompactTextString(m) }
+func (*TagKeysResponse) ProtoMessage()    {}
+
+func (m *TagKeysResponse) GetTagKeys() []byte {
+	if m != nil {
+		return m.TagKeys
+	}
+	return nil
+}
+
+func (m *TagKeysResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
+type TagValuesRequest struct {
+	ShardIDs         []uint64 `protobuf:"varint,1,rep,name=ShardIDs" json:"ShardIDs,omitempty"`
+	Condition        *string  `protobuf:"bytes,2,opt,name=Condition" json:"Condition,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *TagValuesRequest) Reset()         { *m = TagValuesRequest{} }
+func (m *TagValuesRequest) String() string { return proto.CompactTextString(m) }
+func (*TagValuesRequest) ProtoMessage()    {}
+
+func (m *TagValuesRequest) GetShardIDs() []uint64 {
+	if m != nil {
+		return m.ShardIDs
+	}
+	return nil
+}
+
+func (m *TagValuesRequest) GetCondition() string {
+	if m != nil && m.Condition != nil {
+		return *m.Condition
+	}
+	return ""
+}
+
+type TagValuesResponse struct {
+	TagValues        []byte  `protobuf:"bytes,1,opt,name=TagValues" json:"TagValues,omitempty"`
+	Err              *string `protobuf:"bytes,2,opt,name=Err" json:"Err,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *TagValuesResponse) Reset()         { *m = TagValuesResponse{} }
+func (m *TagValuesResponse) String() string { return proto.CompactTextString(m) }
+func (*TagValuesResponse) ProtoMessage()    {}
+
+func (m *TagValuesResponse) GetTagValues() []byte {
+	if m != nil {
+		return m.TagValues
+	}
+	return nil
+}
+
+func (m *TagValuesResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
+type SeriesSketchesRequest struct {
+	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SeriesSketchesRequest) Reset()         { *m = SeriesSketchesRequest{} }
+func (m *SeriesSketchesRequest) String() string { return proto.CompactTextString(m) }
+func (*SeriesSketchesRequest) ProtoMessage()    {}
+
+func (m *SeriesSketchesRequest) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+type SeriesSketchesResponse struct {
+	Sketch           []byte  `protobuf:"bytes,1,opt,name=Sketch" json:"Sketch,omitempty"`
+	TSSketch         []byte  `protobuf:"bytes,2,opt,name=TSSketch" json:"TSSketch,omitempty"`
+	Err              *string `protobuf:"bytes,3,opt,name=Err" json:"Err,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SeriesSketchesResponse) Reset()         { *m = SeriesSketchesResponse{} }
+func (m *SeriesSketchesResponse) String() string { return proto.CompactTextString(m) }
+func (*SeriesSketchesResponse) ProtoMessage()    {}
+
+func (m *SeriesSketchesResponse) GetSketch() []byte {
+	if m != nil {
+		return m.Sketch
+	}
+	return nil
+}
+
+func (m *SeriesSketchesResponse) GetTSSketch() []byte {
+	if m != nil {
+		return m.TSSketch
+	}
+	return nil
+}
+
+func (m *SeriesSketchesResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
+type ReadSeriesRequest struct {
+	ShardIDs         []uint64 `protobuf:"varint,1,rep,name=ShardIDs" json:"ShardIDs,omitempty"`
+	Predicate        []byte   `protobuf:"bytes,2,opt,name=Predicate" json:"Predicate,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *ReadSeriesRequest) Reset()         { *m = ReadSeriesRequest{} }
+func (m *ReadSeriesRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadSeriesRequest) ProtoMessage()    {}
+
+func (m *ReadSeriesRequest) GetShardIDs() []uint64 {
+	if m != nil {
+		return m.ShardIDs
+	}
+	return nil
+}
+
+func (m *ReadSeriesRequest) GetPredicate() []byte {
+	if m != nil {
+		return m.Predicate
+	}
+	return nil
+}
+
+type ReadSeriesResponse struct {
+	Keys             [][]byte `protobuf:"bytes,1,rep,name=Keys" json:"Keys,omitempty"`
+	Fields           []string `protobuf:"bytes,2,rep,name=Fields" json:"Fields,omitempty"`
+	Err              *string  `protobuf:"bytes,3,opt,name=Err" json:"Err,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *ReadSeriesResponse) Reset()         { *m = ReadSeriesResponse{} }
+func (m *ReadSeriesResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadSeriesResponse) ProtoMessage()    {}
+
+func (m *ReadSeriesResponse) GetKeys() [][]byte {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *ReadSeriesResponse) GetFields() []string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+func (m *ReadSeriesResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
+type ReadCursorRequest struct {
+	ShardIDs         []uint64 `protobuf:"varint,1,rep,name=ShardIDs" json:"ShardIDs,omitempty"`
+	SeriesKey        []byte   `protobuf:"bytes,2,opt,name=SeriesKey" json:"SeriesKey,omitempty"`
+	Field            *string  `protobuf:"bytes,3,opt,name=Field" json:"Field,omitempty"`
+	Ascending        *bool    `protobuf:"varint,4,opt,name=Ascending" json:"Ascending,omitempty"`
+	StartTime        *int64   `protobuf:"varint,5,opt,name=StartTime" json:"StartTime,omitempty"`
+	EndTime          *int64   `protobuf:"varint,6,opt,name=EndTime" json:"EndTime,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *ReadCursorRequest) Reset()         { *m = ReadCursorRequest{} }
+func (m *ReadCursorRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadCursorRequest) ProtoMessage()    {}
+
+func (m *ReadCursorRequest) GetShardIDs() []uint64 {
+	if m != nil {
+		return m.ShardIDs
+	}
+	return nil
+}
+
+func (m *ReadCursorRequest) GetSeriesKey() []byte {
+	if m != nil {
+		return m.SeriesKey
+	}
+	return nil
+}
+
+func (m *ReadCursorRequest) GetField() string {
+	if m != nil && m.Field != nil {
+		return *m.Field
+	}
+	return ""
+}
+
+func (m *ReadCursorRequest) GetAscending() bool {
+	if m != nil && m.Ascending != nil {
+		return *m.Ascending
+	}
+	return false
+}
+
+func (m *ReadCursorRequest) GetStartTime() int64 {
+	if m != nil && m.StartTime != nil {
+		return *m.StartTime
+	}
+	return 0
+}
+
+func (m *ReadCursorRequest) GetEndTime() int64 {
+	if m != nil && m.EndTime != nil {
+		return *m.EndTime
+	}
+	return 0
+}
+
+type ReadCursorResponse struct {
+	Type             *int32    `protobuf:"varint,1,opt,name=Type" json:"Type,omitempty"`
+	Timestamps       []int64   `protobuf:"varint,2,rep,packed,name=Timestamps" json:"Timestamps,omitempty"`
+	FloatValues      []float64 `protobuf:"fixed64,3,rep,packed,name=FloatValues" json:"FloatValues,omitempty"`
+	IntegerValues    []int64   `protobuf:"varint,4,rep,packed,name=IntegerValues" json:"IntegerValues,omitempty"`
+	UnsignedValues   []uint64  `protobuf:"varint,5,rep,packed,name=UnsignedValues" json:"UnsignedValues,omitempty"`
+	StringValues     []string  `protobuf:"bytes,6,rep,name=StringValues" json:"StringValues,omitempty"`
+	BooleanValues    []bool    `protobuf:"varint,7,rep,packed,name=BooleanValues" json:"BooleanValues,omitempty"`
+	Err              *string   `protobuf:"bytes,8,opt,name=Err" json:"Err,omitempty"`
+	XXX_unrecognized []byte    `json:"-"`
+}
+
+func (m *ReadCursorResponse) Reset()         { *m = ReadCursorResponse{} }
+func (m *ReadCursorResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadCursorResponse) ProtoMessage()    {}
+
+func (m *ReadCursorResponse) GetType() int32 {
+	if m != nil && m.Type != nil {
+		return *m.Type
+	}
+	return 0
+}
+
+func (m *ReadCursorResponse) GetTimestamps() []int64 {
+	if m != nil {
+		return m.Timestamps
+	}
+	return nil
+}
+
+func (m *ReadCursorResponse) GetFloatValues() []float64 {
+	if m != nil {
+		return m.FloatValues
+	}
+	return nil
+}
+
+func (m *ReadCursorResponse) GetIntegerValues() []int64 {
+	if m != nil {
+		return m.IntegerValues
+	}
+	return nil
+}
+
+func (m *ReadCursorResponse) GetUnsignedValues() []uint64 {
+	if m != nil {
+		return m.UnsignedValues
+	}
+	return nil
+}
+
+func (m *ReadCursorResponse) GetStringValues() []string {
+	if m != nil {
+		return m.StringValues
+	}
+	return nil
+}
+
+func (m *ReadCursorResponse) GetBooleanValues() []bool {
+	if m != nil {
+		return m.BooleanValues
+	}
+	return nil
+}
+
+func (m *ReadCursorResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
+type CompactionsRequest struct {
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *CompactionsRequest) Reset()         { *m = CompactionsRequest{} }
+func (m *CompactionsRequest) String() string { return proto.CompactTextString(m) }
+func (*CompactionsRequest) ProtoMessage()    {}
+
+type CompactionsResponse struct {
+	Compactions      []byte  `protobuf:"bytes,1,opt,name=Compactions" json:"Compactions,omitempty"`
+	Err              *string `protobuf:"bytes,2,opt,name=Err" json:"Err,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *CompactionsResponse) Reset()         { *m = CompactionsResponse{} }
+func (m *CompactionsResponse) String() string { return proto.CompactTextString(m) }
+func (*CompactionsResponse) ProtoMessage()    {}
+
+func (m *CompactionsResponse) GetCompactions() []byte {
+	if m != nil {
+		return m.Compactions
+	}
+	return nil
+}
+
+func (m *CompactionsResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*WriteShardRequest)(nil), "internal.WriteShardRequest")
 	proto.RegisterType((*WriteShardResponse)(nil), "internal.WriteShardResponse")
@@ -294,4 +718,18 @@ func init() {
 	proto.RegisterType((*CreateIteratorResponse)(nil), "internal.CreateIteratorResponse")
 	proto.RegisterType((*FieldDimensionsRequest)(nil), "internal.FieldDimensionsRequest")
 	proto.RegisterType((*FieldDimensionsResponse)(nil), "internal.FieldDimensionsResponse")
+	proto.RegisterType((*MeasurementNamesRequest)(nil), "internal.MeasurementNamesRequest")
+	proto.RegisterType((*MeasurementNamesResponse)(nil), "internal.MeasurementNamesResponse")
+	proto.RegisterType((*TagKeysRequest)(nil), "internal.TagKeysRequest")
+	proto.RegisterType((*TagKeysResponse)(nil), "internal.TagKeysResponse")
+	proto.RegisterType((*TagValuesRequest)(nil), "internal.TagValuesRequest")
+	proto.RegisterType((*TagValuesResponse)(nil), "internal.TagValuesResponse")
+	proto.RegisterType((*SeriesSketchesRequest)(nil), "internal.SeriesSketchesRequest")
+	proto.RegisterType((*SeriesSketchesResponse)(nil), "internal.SeriesSketchesResponse")
+	proto.RegisterType((*ReadSeriesRequest)(nil), "internal.ReadSeriesRequest")
+	proto.RegisterType((*ReadSeriesResponse)(nil), "internal.ReadSeriesResponse")
+	proto.RegisterType((*ReadCursorRequest)(nil), "internal.ReadCursorRequest")
+	proto.RegisterType((*ReadCursorResponse)(nil), "internal.ReadCursorResponse")
+	proto.RegisterType((*CompactionsRequest)(nil), "internal.CompactionsRequest")
+	proto.RegisterType((*CompactionsResponse)(nil), "internal.CompactionsResponse")
 }
@@ -12,12 +12,14 @@ type MetaClient interface {
 	CreateContinuousQuery(database, name, query string) error
 	CreateDatabase(name string) (*meta.DatabaseInfo, error)
 	CreateDatabaseWithRetentionPolicy(name string, rpi *meta.RetentionPolicyInfo) (*meta.DatabaseInfo, error)
+	CreateDownsample(database, rp, target string, interval time.Duration, aggregates []string) error
 	CreateRetentionPolicy(database string, rpi *meta.RetentionPolicyInfo) (*meta.RetentionPolicyInfo, error)
 	CreateSubscription(database, rp, name, mode string, destinations []string) error
 	CreateUser(name, password string, admin bool) (*meta.UserInfo, error)
 	Database(name string) *meta.DatabaseInfo
 	Databases() ([]meta.DatabaseInfo, error)
 	DataNode(id uint64) (*meta.NodeInfo, error)
+	Dropped() []meta.DroppedInfo
 	DataNodes() ([]meta.NodeInfo, error)
 	DeleteDataNode(id uint64) error
 	MetaNodes() ([]meta.NodeInfo, error)
@@ -25,16 +27,29 @@ type MetaClient interface {
 	DropShard(id uint64) error
 	DropContinuousQuery(database, name string) error
 	DropDatabase(name string) error
+	DropDownsample(database, rp, target string) error
 	DropRetentionPolicy(database, name string) error
 	DropSubscription(database, rp, name string) error
 	DropUser(name string) error
 	RetentionPolicy(database, name string) (rpi *meta.RetentionPolicyInfo, err error)
 	SetAdminPrivilege(username string, admin bool) error
+	SetDatabaseConsistency(name, level string) error
 	SetPrivilege(username, database string, p influxql.Privilege) error
+	SetTrashEnabled(enabled bool) error
 	ShardsByTimeRange(sources influxql.Sources, tmin, tmax time.Time) (a []meta.ShardInfo, err error)
 	SetDefaultRetentionPolicy(database, name string) error
 	ShardGroupsByTimeRange(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error)
+	ShardOwner(shardID uint64) (database, policy string, sgi *meta.ShardGroupInfo)
+	TrashDatabase(name string) error
+	TrashEnabled() bool
+	TrashMeasurement(database, name string) error
+	TrashShard(id uint64) error
 	TruncateShardGroups(t time.Time) error
+	UnarchiveShard(shardID, nodeID uint64) error
+	UndropDatabase(name string) error
+	UndropMeasurement(database, name string) error
+	UndropShard(id uint64) error
+	UndropShardGroup(id uint64) error
 	UpdateRetentionPolicy(database, name string, rpu *meta.RetentionPolicyUpdate) error
 	UpdateUser(name, password string) error
 	UserPrivilege(username, database string) (*influxql.Privilege, error)
@@ -12,17 +12,20 @@ type MetaClient struct {
 	CreateContinuousQueryFn             func(database, name, query string) error
 	CreateDatabaseFn                    func(name string) (*meta.DatabaseInfo, error)
 	CreateDatabaseWithRetentionPolicyFn func(name string, rpi *meta.RetentionPolicyInfo) (*meta.DatabaseInfo, error)
-	CreateRetentionPolicyFn             func(database string, rpi *meta.RetentionPolicyInfo, makeDefault bool) (*meta.RetentionPolicyInfo, error)
+	CreateDownsampleFn                  func(database, rp, target string, interval time.Duration, aggregates []string) error
+	CreateRetentionPolicyFn             func(database string, rpi *meta.RetentionPolicyInfo) (*meta.RetentionPolicyInfo, error)
 	CreateSubscriptionFn                func(database, rp, name, mode string, destinations []string) error
-	CreateUserFn                        func(name, password string, admin bool) (meta.User, error)
+	CreateUserFn                        func(name, password string, admin bool) (*meta.UserInfo, error)
 	DatabaseFn                          func(name string) *meta.DatabaseInfo
 	DatabasesFn                         func() ([]meta.DatabaseInfo, error)
+	DroppedFn                           func() []meta.DroppedInfo
 	DataNodeFn                          func(id uint64) (*meta.NodeInfo, error)
 	DataNodesFn                         func() ([]meta.NodeInfo, error)
 	DeleteDataNodeFn                    func(id uint64) error
 	DeleteMetaNodeFn                    func(id uint64) error
 	DropContinuousQueryFn               func(database, name string) error
 	DropDatabaseFn                      func(name string) error
+	DropDownsampleFn                    func(database, rp, target string) error
 	DropRetentionPolicyFn               func(database, name string) error
 	DropSubscriptionFn                  func(database, rp, name string) error
 	DropShardFn                         func(id uint64) error
@@ -30,9 +33,23 @@ type MetaClient struct {
 	MetaNodesFn                         func() ([]meta.NodeInfo, error)
 	RetentionPolicyFn                   func(database, name string) (rpi *meta.RetentionPolicyInfo, err error)
 	SetAdminPrivilegeFn                 func(username string, admin bool) error
+	SetDatabaseConsistencyFn            func(name, level string) error
+	SetDefaultRetentionPolicyFn         func(database, name string) error
 	SetPrivilegeFn                      func(username, database string, p influxql.Privilege) error
+	SetTrashEnabledFn                   func(enabled bool) error
 	ShardGroupsByTimeRangeFn            func(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error)
+	ShardsByTimeRangeFn                 func(sources influxql.Sources, tmin, tmax time.Time) (a []meta.ShardInfo, err error)
+	ShardOwnerFn                        func(shardID uint64) (database, policy string, sgi *meta.ShardGroupInfo)
+	TrashDatabaseFn                     func(name string) error
+	TrashEnabledFn                      func() bool
+	TrashMeasurementFn                  func(database, name string) error
+	TrashShardFn                        func(id uint64) error
 	TruncateShardGroupsFn               func(t time.Time) error
+	UnarchiveShardFn                    func(shardID, nodeID uint64) error
+	UndropDatabaseFn                    func(name string) error
+	UndropMeasurementFn                 func(database, name string) error
+	UndropShardFn                       func(id uint64) error
+	UndropShardGroupFn                  func(id uint64) error
 	UpdateRetentionPolicyFn             func(database, name string, rpu *meta.RetentionPolicyUpdate) error
 	UpdateUserFn                        func(name, password string) error
 	UserPrivilegeFn                     func(username, database string) (*influxql.Privilege, error)
@@ -48,12 +65,20 @@ func (c *MetaClient) CreateDatabase(name string) (*meta.DatabaseInfo, error) {
 	return c.CreateDatabaseFn(name)
 }
 
+func (c *MetaClient) CreateDownsample(database, rp, target string, interval time.Duration, aggregates []string) error {
+	return c.CreateDownsampleFn(database, rp, target, interval, aggregates)
+}
+
+func (c *MetaClient) DropDownsample(database, rp, target string) error {
+	return c.DropDownsampleFn(database, rp, target)
+}
+
 func (c *MetaClient) CreateDatabaseWithRetentionPolicy(name string, rpi *meta.RetentionPolicyInfo) (*meta.DatabaseInfo, error) {
 	return c.CreateDatabaseWithRetentionPolicyFn(name, rpi)
 }
 
-func (c *MetaClient) CreateRetentionPolicy(database string, rpi *meta.RetentionPolicyInfo, makeDefault bool) (*meta.RetentionPolicyInfo, error) {
-	return c.CreateRetentionPolicyFn(database, spec, makeDefault)
+func (c *MetaClient) CreateRetentionPolicy(database string, rpi *meta.RetentionPolicyInfo) (*meta.RetentionPolicyInfo, error) {
+	return c.CreateRetentionPolicyFn(database, rpi)
 }
 
 func (c *MetaClient) DropShard(id uint64) error {
@@ -64,7 +89,7 @@ func (c *MetaClient) CreateSubscription(database, rp, name, mode string, destina
 	return c.CreateSubscriptionFn(database, rp, name, mode, destinations)
 }
 
-func (c *MetaClient) CreateUser(name, password string, admin bool) (meta.User, error) {
+func (c *MetaClient) CreateUser(name, password string, admin bool) (*meta.UserInfo, error) {
 	return c.CreateUserFn(name, password, admin)
 }
 
@@ -76,6 +101,10 @@ func (c *MetaClient) Databases() ([]meta.DatabaseInfo, error) {
 	return c.DatabasesFn()
 }
 
+func (c *MetaClient) Dropped() []meta.DroppedInfo {
+	return c.DroppedFn()
+}
+
 func (c *MetaClient) DataNode(id uint64) (*meta.NodeInfo, error) {
 	return c.DataNodeFn(id)
 }
@@ -124,18 +153,74 @@ func (c *MetaClient) SetAdminPrivilege(username string, admin bool) error {
 	return c.SetAdminPrivilegeFn(username, admin)
 }
 
+func (c *MetaClient) SetDatabaseConsistency(name, level string) error {
+	return c.SetDatabaseConsistencyFn(name, level)
+}
+
+func (c *MetaClient) SetDefaultRetentionPolicy(database, name string) error {
+	return c.SetDefaultRetentionPolicyFn(database, name)
+}
+
 func (c *MetaClient) SetPrivilege(username, database string, p influxql.Privilege) error {
 	return c.SetPrivilegeFn(username, database, p)
 }
 
+func (c *MetaClient) SetTrashEnabled(enabled bool) error {
+	return c.SetTrashEnabledFn(enabled)
+}
+
 func (c *MetaClient) ShardGroupsByTimeRange(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error) {
 	return c.ShardGroupsByTimeRangeFn(database, policy, min, max)
 }
 
+func (c *MetaClient) ShardsByTimeRange(sources influxql.Sources, tmin, tmax time.Time) (a []meta.ShardInfo, err error) {
+	return c.ShardsByTimeRangeFn(sources, tmin, tmax)
+}
+
+func (c *MetaClient) ShardOwner(shardID uint64) (database, policy string, sgi *meta.ShardGroupInfo) {
+	return c.ShardOwnerFn(shardID)
+}
+
+func (c *MetaClient) TrashDatabase(name string) error {
+	return c.TrashDatabaseFn(name)
+}
+
+func (c *MetaClient) TrashEnabled() bool {
+	return c.TrashEnabledFn()
+}
+
+func (c *MetaClient) TrashMeasurement(database, name string) error {
+	return c.TrashMeasurementFn(database, name)
+}
+
+func (c *MetaClient) TrashShard(id uint64) error {
+	return c.TrashShardFn(id)
+}
+
 func (c *MetaClient) TruncateShardGroups(t time.Time) error {
 	return c.TruncateShardGroupsFn(t)
 }
 
+func (c *MetaClient) UnarchiveShard(shardID, nodeID uint64) error {
+	return c.UnarchiveShardFn(shardID, nodeID)
+}
+
+func (c *MetaClient) UndropDatabase(name string) error {
+	return c.UndropDatabaseFn(name)
+}
+
+func (c *MetaClient) UndropMeasurement(database, name string) error {
+	return c.UndropMeasurementFn(database, name)
+}
+
+func (c *MetaClient) UndropShard(id uint64) error {
+	return c.UndropShardFn(id)
+}
+
+func (c *MetaClient) UndropShardGroup(id uint64) error {
+	return c.UndropShardGroupFn(id)
+}
+
 func (c *MetaClient) UpdateRetentionPolicy(database, name string, rpu *meta.RetentionPolicyUpdate) error {
 	return c.UpdateRetentionPolicyFn(database, name, rpu)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/freetsdb/freetsdb"
 	"github.com/freetsdb/freetsdb/services/influxql"
 	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tcp"
 )
 
 const (
@@ -27,6 +28,9 @@ type MetaExecutor struct {
 	Logger         *log.Logger
 	Node           *freetsdb.Node
 
+	// Dialer connects to the other data nodes.
+	Dialer *tcp.Dialer
+
 	nodeExecutor interface {
 		executeOnNode(stmt influxql.Statement, database string, node *meta.NodeInfo) error
 	}
@@ -132,8 +136,13 @@ func (m *MetaExecutor) executeOnNode(stmt influxql.Statement, database string, n
 		return err
 	}
 
-	// Read the response.
-	conn.SetReadDeadline(time.Now().Add(m.timeout))
+	// Read the response.  Statements rewriting shard data run for as long as
+	// the node takes to rewrite them, so their response has no deadline.
+	if longRunningStatement(stmt) {
+		conn.SetReadDeadline(time.Time{})
+	} else {
+		conn.SetReadDeadline(time.Now().Add(m.timeout))
+	}
 	_, buf, err = ReadTLV(conn)
 	if err != nil {
 		conn.MarkUnusable()
@@ -153,12 +162,22 @@ func (m *MetaExecutor) executeOnNode(stmt influxql.Statement, database string, n
 	return nil
 }
 
+// longRunningStatement returns true if stmt rewrites the data of shards on
+// the nodes executing it.
+func longRunningStatement(stmt influxql.Statement) bool {
+	switch stmt.(type) {
+	case *influxql.AlterFieldTypeStatement:
+		return true
+	}
+	return false
+}
+
 // dial returns a connection to a single node in the cluster.
 func (m *MetaExecutor) dial(nodeID uint64) (net.Conn, error) {
 	// If we don't have a connection pool for that addr yet, create one
 	_, ok := m.pool.getPool(nodeID)
 	if !ok {
-		factory := &connFactory{nodeID: nodeID, clientPool: m.pool, timeout: m.timeout}
+		factory := &connFactory{nodeID: nodeID, clientPool: m.pool, timeout: m.timeout, dialer: m.Dialer}
 		factory.metaClient = m.MetaClient
 
 		p, err := NewBoundedPool(1, m.maxConnections, m.timeout, factory.dial)
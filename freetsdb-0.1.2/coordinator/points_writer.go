@@ -50,6 +50,16 @@ const (
 	ConsistencyLevelAll
 )
 
+// ConsistencyLevelDefault uses the consistency level of the retention policy
+// being written to, falling back to that of its database and then to the
+// PointsWriter's default.
+const ConsistencyLevelDefault ConsistencyLevel = -1
+
+// ConsistencyLevelDefaultAny is like ConsistencyLevelDefault but falls back
+// to ConsistencyLevelAny. It is used by the UDP, collectd, Graphite and
+// OpenTSDB inputs, which have always relied on hinted handoff.
+const ConsistencyLevelDefaultAny ConsistencyLevel = -2
+
 var (
 	// ErrTimeout is returned when a write times out.
 	ErrTimeout = errors.New("timeout")
@@ -89,6 +99,10 @@ type PointsWriter struct {
 	WriteTimeout time.Duration
 	Logger       *zap.Logger
 
+	// DefaultConsistency is used for writes with ConsistencyLevelDefault
+	// when neither the retention policy nor its database set a level.
+	DefaultConsistency ConsistencyLevel
+
 	Node *freetsdb.Node
 
 	MetaClient interface {
@@ -122,10 +136,11 @@ type PointsWriter struct {
 // NewPointsWriter returns a new instance of PointsWriter for a node.
 func NewPointsWriter() *PointsWriter {
 	return &PointsWriter{
-		closing:      make(chan struct{}),
-		WriteTimeout: DefaultWriteTimeout,
-		Logger:       zap.NewNop(),
-		stats:        &WriteStatistics{},
+		closing:            make(chan struct{}),
+		WriteTimeout:       DefaultWriteTimeout,
+		Logger:             zap.NewNop(),
+		DefaultConsistency: ConsistencyLevelOne,
+		stats:              &WriteStatistics{},
 	}
 }
 
@@ -321,7 +336,7 @@ func (l sgList) Append(sgi meta.ShardGroupInfo) sgList {
 // WritePointsInto is a copy of WritePoints that uses a tsdb structure instead of
 // a cluster structure for information. This is to avoid a circular dependency.
 func (w *PointsWriter) WritePointsInto(p *IntoWriteRequest) error {
-	return w.WritePointsPrivileged(p.Database, p.RetentionPolicy, ConsistencyLevelOne, p.Points)
+	return w.WritePointsPrivileged(p.Database, p.RetentionPolicy, ConsistencyLevelDefault, p.Points)
 }
 
 // WritePoints writes the data to the underlying storage. consitencyLevel and user are only used for clustered scenarios
@@ -334,12 +349,20 @@ func (w *PointsWriter) WritePointsPrivileged(database, retentionPolicy string, c
 	atomic.AddInt64(&w.stats.WriteReq, 1)
 	atomic.AddInt64(&w.stats.PointWriteReq, int64(len(points)))
 
-	if retentionPolicy == "" {
+	if retentionPolicy == "" || consistencyLevel < 0 {
 		db := w.MetaClient.Database(database)
 		if db == nil {
 			return freetsdb.ErrDatabaseNotFound(database)
 		}
-		retentionPolicy = db.DefaultRetentionPolicy
+		if retentionPolicy == "" {
+			retentionPolicy = db.DefaultRetentionPolicy
+		}
+		switch consistencyLevel {
+		case ConsistencyLevelDefault:
+			consistencyLevel = w.consistencyLevel(db, retentionPolicy, w.DefaultConsistency)
+		case ConsistencyLevelDefaultAny:
+			consistencyLevel = w.consistencyLevel(db, retentionPolicy, ConsistencyLevelAny)
+		}
 	}
 
 	shardMappings, err := w.MapShards(&WritePointsRequest{Database: database, RetentionPolicy: retentionPolicy, Points: points})
@@ -405,12 +428,34 @@ func (w *PointsWriter) WritePointsPrivileged(database, retentionPolicy string, c
 	return err
 }
 
+// consistencyLevel returns the write consistency level of a retention policy
+// in db, or fallback if neither it nor db set one. Levels that fail to parse
+// are ignored.
+func (w *PointsWriter) consistencyLevel(db *meta.DatabaseInfo, retentionPolicy string, fallback ConsistencyLevel) ConsistencyLevel {
+	if rpi := db.RetentionPolicy(retentionPolicy); rpi != nil && rpi.Consistency != "" {
+		if level, err := ParseConsistencyLevel(rpi.Consistency); err == nil {
+			return level
+		}
+	}
+	if db.Consistency != "" {
+		if level, err := ParseConsistencyLevel(db.Consistency); err == nil {
+			return level
+		}
+	}
+	return fallback
+}
+
 // writeToShards writes points to a shard and ensures a write consistency level has been met.  If the write
 // partially succeeds, ErrPartialWrite is returned.
 func (w *PointsWriter) writeToShard(shard *meta.ShardInfo, database, retentionPolicy string,
 	consistency ConsistencyLevel, points []models.Point) error {
 	atomic.AddInt64(&w.stats.PointWriteReqLocal, int64(len(points)))
 
+	// Archived shards have no owners to write to until they are restored.
+	if shard.Archived() {
+		return meta.ErrShardArchived
+	}
+
 	// The required number of writes to achieve the requested consistency level
 	required := len(shard.Owners)
 	switch consistency {
@@ -3,6 +3,8 @@ package coordinator
 import (
 	"testing"
 	"time"
+
+	"github.com/freetsdb/freetsdb/services/meta"
 )
 
 func TestSgList_ShardGroupAt(t *testing.T) {
@@ -44,3 +46,37 @@ func TestSgList_ShardGroupAt(t *testing.T) {
 		}
 	}
 }
+
+func TestPointsWriter_ConsistencyLevel(t *testing.T) {
+	db := &meta.DatabaseInfo{
+		Name: "db0",
+		RetentionPolicies: []meta.RetentionPolicyInfo{
+			{Name: "rp0"},
+			{Name: "rp1", Consistency: "all"},
+			{Name: "rp2", Consistency: "bogus"},
+		},
+	}
+
+	w := NewPointsWriter()
+
+	examples := []struct {
+		DBConsistency   string
+		RetentionPolicy string
+		Exp             ConsistencyLevel
+	}{
+		{RetentionPolicy: "rp0", Exp: ConsistencyLevelAny},
+		{RetentionPolicy: "rp1", Exp: ConsistencyLevelAll},
+		{RetentionPolicy: "rp2", Exp: ConsistencyLevelAny},
+		{DBConsistency: "quorum", RetentionPolicy: "rp0", Exp: ConsistencyLevelQuorum},
+		{DBConsistency: "quorum", RetentionPolicy: "rp1", Exp: ConsistencyLevelAll},
+		{DBConsistency: "quorum", RetentionPolicy: "rp2", Exp: ConsistencyLevelQuorum},
+		{DBConsistency: "one", RetentionPolicy: "missing", Exp: ConsistencyLevelOne},
+	}
+
+	for i, example := range examples {
+		db.Consistency = example.DBConsistency
+		if got := w.consistencyLevel(db, example.RetentionPolicy, ConsistencyLevelAny); got != example.Exp {
+			t.Errorf("[Example %d] got %v, expected %v", i+1, got, example.Exp)
+		}
+	}
+}
@@ -324,6 +324,15 @@ func TestPointsWriter_WritePoints(t *testing.T) {
 			},
 		}
 
+		// Remote owners are reached through the ShardWriter.
+		shardWriter := &fakeShardWriter{
+			WriteShardFn: func(shardID, ownerID uint64, points []models.Point) error {
+				mu.Lock()
+				defer mu.Unlock()
+				return theTest.err[int(ownerID)-1]
+			},
+		}
+
 		ms.DatabaseFn = func(database string) *meta.DatabaseInfo {
 			return nil
 		}
@@ -338,13 +347,14 @@ func TestPointsWriter_WritePoints(t *testing.T) {
 		c := coordinator.NewPointsWriter()
 		c.MetaClient = ms
 		c.TSDBStore = store
+		c.ShardWriter = shardWriter
 		c.AddWriteSubscriber(sub.Points())
 		c.Node = &freetsdb.Node{ID: 1}
 
 		c.Open()
 		defer c.Close()
 
-		err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+		err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, coordinator.ConsistencyLevelOne, pr.Points)
 		if err == nil && test.expErr != nil {
 			t.Errorf("PointsWriter.WritePointsPrivileged(): '%s' error: got %v, exp %v", test.name, err, test.expErr)
 		}
@@ -420,12 +430,20 @@ func TestPointsWriter_WritePoints_Dropped(t *testing.T) {
 	c.Open()
 	defer c.Close()
 
-	err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+	err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, coordinator.ConsistencyLevelOne, pr.Points)
 	if _, ok := err.(tsdb.PartialWriteError); !ok {
 		t.Errorf("PointsWriter.WritePoints(): got %v, exp %v", err, tsdb.PartialWriteError{})
 	}
 }
 
+type fakeShardWriter struct {
+	WriteShardFn func(shardID, ownerID uint64, points []models.Point) error
+}
+
+func (f *fakeShardWriter) WriteShard(shardID, ownerID uint64, points []models.Point) error {
+	return f.WriteShardFn(shardID, ownerID, points)
+}
+
 type fakePointsWriter struct {
 	WritePointsIntoFn func(*coordinator.IntoWriteRequest) error
 }
@@ -495,7 +513,7 @@ func TestBufferedPointsWriter(t *testing.T) {
 		req.AddPoint("cpu", float64(i), time.Now().Add(time.Duration(i)*time.Second), nil)
 	}
 
-	r := coordinator.IntoWriteRequest(req)
+	r := coordinator.IntoWriteRequest{Database: req.Database, RetentionPolicy: req.RetentionPolicy, Points: req.Points}
 	if err := w.WritePointsInto(&r); err != nil {
 		t.Fatal(err)
 	} else if writePointsIntoCnt != 5 {
@@ -0,0 +1,163 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/freetsdb/freetsdb/query"
+	"github.com/freetsdb/freetsdb/services/influxql"
+	"github.com/freetsdb/freetsdb/tsdb"
+)
+
+// replicaIteratorCreator creates iterators that read a shard from several of
+// its owners and merge their points, for queries using a read consistency
+// above one.
+type replicaIteratorCreator struct {
+	shardID uint64
+
+	// local is the shard on this node, or nil if this node is not an owner.
+	local tsdb.ShardGroup
+
+	// nodeIDs are the remote owners in the order they should be tried.
+	nodeIDs []uint64
+	dialer  *NodeDialer
+
+	// required is the number of owners that must be read.
+	required int
+}
+
+// CreateIterator reads the required number of owners and merges their points.
+// Owners that cannot be reached are replaced by the next owner in the list.
+func (ic *replicaIteratorCreator) CreateIterator(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+	// Replicas are compared point by point, so aggregates pushed down to the
+	// shards are computed here from the raw points instead.
+	call, isCall := opt.Expr.(*influxql.Call)
+	ropt := opt
+	if isCall {
+		ropt.Expr = call.Args[0]
+	}
+	ropt.Ordered = true
+
+	// Points only carry the tags of the dimensions, so the other tags are read
+	// as auxiliary fields to keep the series sharing a timestamp apart.
+	_, dimensions, err := ic.FieldDimensions(m, []string{m.Name})
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(dimensions))
+	for k := range dimensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	ropt, seriesTags := query.WithSeriesTags(ropt, keys)
+
+	var inputs []query.Iterator
+	if ic.local != nil {
+		itr, err := ic.local.CreateIterator(ctx, m, ropt)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, itr)
+	}
+
+	for _, nodeID := range ic.nodeIDs {
+		if len(inputs) == ic.required {
+			break
+		}
+
+		remote := newRemoteIteratorCreator(ic.dialer, []uint64{nodeID}, []uint64{ic.shardID})
+		itr, err := remote.CreateIterator(ctx, m, ropt)
+		if err != nil {
+			continue
+		}
+		inputs = append(inputs, itr)
+	}
+
+	if len(inputs) < ic.required {
+		query.Iterators(inputs).Close()
+		return nil, fmt.Errorf("read consistency not met for shard %d: %d of %d owners read", ic.shardID, len(inputs), ic.required)
+	}
+
+	d := replicaDivergenceFromContext(ctx)
+	itr := query.NewReplicaMergeIterator(inputs, ropt, seriesTags, func() { d.add(ic.shardID) })
+	if itr == nil || !isCall {
+		return itr, nil
+	}
+	return query.NewCallIterator(itr, opt)
+}
+
+// FieldDimensions returns the fields and dimensions of the measurements in the
+// local shard, or in the first remote owner that can be reached.
+func (ic *replicaIteratorCreator) FieldDimensions(m *influxql.Measurement, measurements []string) (map[string]influxql.DataType, map[string]struct{}, error) {
+	if ic.local != nil {
+		return ic.local.FieldDimensions(measurements)
+	}
+	remote := newRemoteIteratorCreator(ic.dialer, ic.nodeIDs, []uint64{ic.shardID})
+	return remote.FieldDimensions(m)
+}
+
+// replicaDivergence counts the points that shard replicas disagreed on while
+// executing a query.
+type replicaDivergence struct {
+	mu     sync.Mutex
+	shards map[uint64]int
+}
+
+// add records a disagreement between the replicas of a shard.
+func (d *replicaDivergence) add(shardID uint64) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.shards == nil {
+		d.shards = make(map[uint64]int)
+	}
+	d.shards[shardID]++
+}
+
+// Message returns a warning describing the disagreements, or nil if the
+// replicas always agreed.
+func (d *replicaDivergence) Message() *query.Message {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.shards) == 0 {
+		return nil
+	}
+
+	var n int
+	ids := make([]uint64, 0, len(d.shards))
+	for id, count := range d.shards {
+		ids = append(ids, id)
+		n += count
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	shards := make([]string, len(ids))
+	for i, id := range ids {
+		shards[i] = fmt.Sprint(id)
+	}
+
+	return &query.Message{
+		Level: query.WarningLevel,
+		Text:  fmt.Sprintf("replicas disagreed on %d points in shards %s; the points of the most complete replica were returned", n, strings.Join(shards, ", ")),
+	}
+}
+
+type replicaDivergenceContextKey struct{}
+
+// newContextWithReplicaDivergence returns a context that records replica
+// disagreements in d.
+func newContextWithReplicaDivergence(ctx context.Context, d *replicaDivergence) context.Context {
+	return context.WithValue(ctx, replicaDivergenceContextKey{}, d)
+}
+
+// replicaDivergenceFromContext returns the replicaDivergence of ctx, or nil.
+func replicaDivergenceFromContext(ctx context.Context) *replicaDivergence {
+	d, _ := ctx.Value(replicaDivergenceContextKey{}).(*replicaDivergence)
+	return d
+}
@@ -0,0 +1,384 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/freetsdb/freetsdb/platform/storage/reads"
+	"github.com/freetsdb/freetsdb/platform/storage/reads/datatypes"
+	"github.com/freetsdb/freetsdb/platform/tsdb/cursors"
+	"github.com/freetsdb/freetsdb/services/influxql"
+	"github.com/freetsdb/freetsdb/services/storage"
+)
+
+// errCursorSuperseded is returned by a remote cursor read after another
+// cursor was opened on the same connection.
+var errCursorSuperseded = errors.New("remote cursor superseded")
+
+var _ storage.RemoteStore = (*RemoteStorage)(nil)
+
+// RemoteStorage reads series and cursors from the shards of remote nodes
+// for the storage read path.
+type RemoteStorage struct {
+	dialer *NodeDialer
+}
+
+// NewRemoteStorage returns a new instance of RemoteStorage.
+func NewRemoteStorage(dialer *NodeDialer) *RemoteStorage {
+	return &RemoteStorage{dialer: dialer}
+}
+
+// SeriesCursor returns a cursor over the series and fields matching predicate
+// in shards on a remote node.
+func (s *RemoteStorage) SeriesCursor(ctx context.Context, nodeID uint64, shardIDs []uint64, predicate *datatypes.Predicate) (reads.SeriesCursor, error) {
+	conn, err := s.dialer.DialNode(nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := EncodeTLV(conn, readSeriesRequestMessage, &ReadSeriesRequest{
+		ShardIDs:  shardIDs,
+		Predicate: predicate,
+	}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	cur := &remoteSeriesCursor{conn: conn, timeout: s.dialer.Timeout}
+	if err := cur.readBatch(); err != nil {
+		cur.Close()
+		return nil, err
+	}
+	return cur, nil
+}
+
+// CursorIterators returns a cursor iterator for each list of shards on a
+// remote node. The iterators share a single connection, which is closed
+// when ctx is done.
+func (s *RemoteStorage) CursorIterators(ctx context.Context, nodeID uint64, shardIDs [][]uint64) cursors.CursorIterators {
+	session := &remoteCursorSession{dialer: s.dialer, nodeID: nodeID}
+	go func() {
+		<-ctx.Done()
+		session.Close()
+	}()
+
+	itrs := make(cursors.CursorIterators, len(shardIDs))
+	for i := range shardIDs {
+		itrs[i] = &remoteCursorIterator{session: session, shardIDs: shardIDs[i]}
+	}
+	return itrs
+}
+
+// remoteSeriesCursor reads the series rows streamed by a remote node.
+type remoteSeriesCursor struct {
+	conn    net.Conn
+	timeout time.Duration
+
+	rows []ReadSeriesRow
+	row  reads.SeriesRow
+	eof  bool
+	err  error
+}
+
+// readBatch reads the next batch of rows from the connection.
+func (c *remoteSeriesCursor) readBatch() error {
+	if c.timeout > 0 {
+		c.conn.SetDeadline(time.Now().Add(c.timeout))
+	}
+
+	var resp ReadSeriesResponse
+	if _, err := DecodeTLV(c.conn, &resp); err != nil {
+		return err
+	} else if resp.Err != nil {
+		return resp.Err
+	}
+
+	c.rows = resp.Rows
+	if len(c.rows) == 0 {
+		c.eof = true
+	}
+	return nil
+}
+
+func (c *remoteSeriesCursor) Close() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
+
+func (c *remoteSeriesCursor) Err() error {
+	return c.err
+}
+
+func (c *remoteSeriesCursor) Next() *reads.SeriesRow {
+	if len(c.rows) == 0 {
+		if c.eof || c.err != nil {
+			c.Close()
+			return nil
+		}
+		if err := c.readBatch(); err != nil {
+			c.err = err
+			c.Close()
+			return nil
+		} else if c.eof {
+			c.Close()
+			return nil
+		}
+	}
+
+	row := c.rows[0]
+	c.rows = c.rows[1:]
+
+	c.row.Name = row.Name
+	c.row.SeriesTags = row.Tags
+	c.row.Field = row.Field
+	return &c.row
+}
+
+// remoteCursorSession is a connection to a remote node over which cursors
+// are opened one at a time. Opening a cursor supersedes the previous one.
+type remoteCursorSession struct {
+	dialer *NodeDialer
+	nodeID uint64
+
+	mu     sync.Mutex
+	conn   net.Conn
+	gen    int
+	closed bool
+}
+
+// open opens a cursor over shards on the remote node and returns its first
+// block and generation.
+func (s *remoteCursorSession) open(shardIDs []uint64, r *cursors.CursorRequest) (*ReadCursorResponse, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, 0, errors.New("remote cursor session closed")
+	}
+
+	if s.conn == nil {
+		conn, err := s.dialer.DialNode(s.nodeID)
+		if err != nil {
+			return nil, 0, err
+		}
+		s.conn = conn
+	}
+
+	s.gen++
+	resp, err := s.roundTrip(readCursorRequestMessage, &ReadCursorRequest{
+		ShardIDs:  shardIDs,
+		Name:      r.Name,
+		Tags:      r.Tags,
+		Field:     r.Field,
+		Ascending: r.Ascending,
+		StartTime: r.StartTime,
+		EndTime:   r.EndTime,
+	})
+	return resp, s.gen, err
+}
+
+// next returns the next block of the cursor with generation gen.
+func (s *remoteCursorSession) next(gen int) (*ReadCursorResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, errors.New("remote cursor session closed")
+	} else if gen != s.gen {
+		return nil, errCursorSuperseded
+	}
+	return s.roundTrip(readCursorNextMessage, nil)
+}
+
+// roundTrip sends a message and reads the response. The connection is
+// dropped on error as its state is unknown.
+func (s *remoteCursorSession) roundTrip(typ byte, req *ReadCursorRequest) (*ReadCursorResponse, error) {
+	if s.dialer.Timeout > 0 {
+		s.conn.SetDeadline(time.Now().Add(s.dialer.Timeout))
+	}
+
+	var err error
+	if req != nil {
+		err = EncodeTLV(s.conn, typ, req)
+	} else {
+		err = WriteTLV(s.conn, typ, nil)
+	}
+
+	var resp ReadCursorResponse
+	if err == nil {
+		_, err = DecodeTLV(s.conn, &resp)
+	}
+	if err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return nil, err
+	}
+	return &resp, resp.Err
+}
+
+// Close closes the connection.
+func (s *remoteCursorSession) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// remoteCursorIterator creates cursors over a list of shards on a remote node.
+type remoteCursorIterator struct {
+	session  *remoteCursorSession
+	shardIDs []uint64
+}
+
+func (itr *remoteCursorIterator) Next(ctx context.Context, r *cursors.CursorRequest) (cursors.Cursor, error) {
+	resp, gen, err := itr.session.open(itr.shardIDs, r)
+	if err != nil {
+		return nil, err
+	}
+
+	c := remoteCursor{session: itr.session, gen: gen, resp: resp}
+	switch resp.Type {
+	case influxql.Unknown:
+		return nil, nil
+	case influxql.Float:
+		return &remoteFloatCursor{remoteCursor: c}, nil
+	case influxql.Integer:
+		return &remoteIntegerCursor{remoteCursor: c}, nil
+	case influxql.Unsigned:
+		return &remoteUnsignedCursor{remoteCursor: c}, nil
+	case influxql.String:
+		return &remoteStringCursor{remoteCursor: c}, nil
+	case influxql.Boolean:
+		return &remoteBooleanCursor{remoteCursor: c}, nil
+	default:
+		return nil, fmt.Errorf("unsupported remote cursor type: %s", resp.Type)
+	}
+}
+
+func (itr *remoteCursorIterator) Stats() cursors.CursorStats {
+	return cursors.CursorStats{}
+}
+
+// remoteCursor reads the blocks of a cursor opened on a remote node.
+type remoteCursor struct {
+	session *remoteCursorSession
+	gen     int
+	resp    *ReadCursorResponse
+	eof     bool
+	err     error
+}
+
+// nextBlock returns the next block read from the remote node, or nil once
+// the cursor is exhausted.
+func (c *remoteCursor) nextBlock() *ReadCursorResponse {
+	if c.eof {
+		return nil
+	}
+
+	resp := c.resp
+	c.resp = nil
+	if resp == nil {
+		var err error
+		if resp, err = c.session.next(c.gen); err != nil {
+			c.err, c.eof = err, true
+			return nil
+		}
+	}
+
+	if len(resp.Timestamps) == 0 {
+		c.eof = true
+		return nil
+	}
+	return resp
+}
+
+func (c *remoteCursor) Close() {
+	c.eof = true
+	c.resp = nil
+}
+
+func (c *remoteCursor) Err() error {
+	if c.err == errCursorSuperseded {
+		return nil
+	}
+	return c.err
+}
+
+func (c *remoteCursor) Stats() cursors.CursorStats {
+	return cursors.CursorStats{}
+}
+
+type remoteFloatCursor struct {
+	remoteCursor
+	a cursors.FloatArray
+}
+
+func (c *remoteFloatCursor) Next() *cursors.FloatArray {
+	c.a.Timestamps, c.a.Values = nil, nil
+	if resp := c.nextBlock(); resp != nil {
+		c.a.Timestamps, c.a.Values = resp.Timestamps, resp.FloatValues
+	}
+	return &c.a
+}
+
+type remoteIntegerCursor struct {
+	remoteCursor
+	a cursors.IntegerArray
+}
+
+func (c *remoteIntegerCursor) Next() *cursors.IntegerArray {
+	c.a.Timestamps, c.a.Values = nil, nil
+	if resp := c.nextBlock(); resp != nil {
+		c.a.Timestamps, c.a.Values = resp.Timestamps, resp.IntegerValues
+	}
+	return &c.a
+}
+
+type remoteUnsignedCursor struct {
+	remoteCursor
+	a cursors.UnsignedArray
+}
+
+func (c *remoteUnsignedCursor) Next() *cursors.UnsignedArray {
+	c.a.Timestamps, c.a.Values = nil, nil
+	if resp := c.nextBlock(); resp != nil {
+		c.a.Timestamps, c.a.Values = resp.Timestamps, resp.UnsignedValues
+	}
+	return &c.a
+}
+
+type remoteStringCursor struct {
+	remoteCursor
+	a cursors.StringArray
+}
+
+func (c *remoteStringCursor) Next() *cursors.StringArray {
+	c.a.Timestamps, c.a.Values = nil, nil
+	if resp := c.nextBlock(); resp != nil {
+		c.a.Timestamps, c.a.Values = resp.Timestamps, resp.StringValues
+	}
+	return &c.a
+}
+
+type remoteBooleanCursor struct {
+	remoteCursor
+	a cursors.BooleanArray
+}
+
+func (c *remoteBooleanCursor) Next() *cursors.BooleanArray {
+	c.a.Timestamps, c.a.Values = nil, nil
+	if resp := c.nextBlock(); resp != nil {
+		c.a.Timestamps, c.a.Values = resp.Timestamps, resp.BooleanValues
+	}
+	return &c.a
+}
@@ -0,0 +1,191 @@
+package coordinator
+
+import (
+	"bytes"
+	"encoding"
+	"sort"
+	"time"
+
+	"github.com/freetsdb/freetsdb/pkg/estimator"
+	"github.com/freetsdb/freetsdb/services/influxql"
+	"github.com/freetsdb/freetsdb/tsdb"
+)
+
+// remoteStoreTimeout is the time allowed for a remote node to answer a
+// metadata query.
+const remoteStoreTimeout = 10 * time.Second
+
+// remoteTSDBStore answers metadata queries from the store of a remote node.
+type remoteTSDBStore struct {
+	dialer *NodeDialer
+	nodeID uint64
+}
+
+// newRemoteTSDBStore returns a new instance of remoteTSDBStore for a remote node.
+func newRemoteTSDBStore(dialer *NodeDialer, nodeID uint64) *remoteTSDBStore {
+	return &remoteTSDBStore{
+		dialer: dialer,
+		nodeID: nodeID,
+	}
+}
+
+// MeasurementNames returns the measurement names of database on the remote node.
+func (s *remoteTSDBStore) MeasurementNames(database string, cond influxql.Expr) ([][]byte, error) {
+	var resp MeasurementNamesResponse
+	if err := s.call(measurementNamesRequestMessage, &MeasurementNamesRequest{
+		Database:  database,
+		Condition: cond,
+	}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Names, resp.Err
+}
+
+// TagKeys returns the tag keys of shards on the remote node.
+func (s *remoteTSDBStore) TagKeys(shardIDs []uint64, cond influxql.Expr) ([]tsdb.TagKeys, error) {
+	var resp TagKeysResponse
+	if err := s.call(tagKeysRequestMessage, &TagKeysRequest{
+		ShardIDs:  shardIDs,
+		Condition: cond,
+	}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.TagKeys, resp.Err
+}
+
+// TagValues returns the tag values of shards on the remote node.
+func (s *remoteTSDBStore) TagValues(shardIDs []uint64, cond influxql.Expr) ([]tsdb.TagValues, error) {
+	var resp TagValuesResponse
+	if err := s.call(tagValuesRequestMessage, &TagValuesRequest{
+		ShardIDs:  shardIDs,
+		Condition: cond,
+	}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.TagValues, resp.Err
+}
+
+// SeriesSketches returns the series and tombstone sketches of database on
+// the remote node.
+func (s *remoteTSDBStore) SeriesSketches(database string) (estimator.Sketch, estimator.Sketch, error) {
+	var resp SeriesSketchesResponse
+	if err := s.call(seriesSketchesRequestMessage, &SeriesSketchesRequest{
+		Database: database,
+	}, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Sketch, resp.TSSketch, resp.Err
+}
+
+// Compactions returns the compactions running on the remote node.
+func (s *remoteTSDBStore) Compactions() ([]tsdb.CompactionInfo, error) {
+	var resp CompactionsResponse
+	if err := s.call(compactionsRequestMessage, &CompactionsRequest{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Compactions, resp.Err
+}
+
+// call sends a request to the remote node and decodes the response into resp.
+func (s *remoteTSDBStore) call(typ byte, req encoding.BinaryMarshaler, resp encoding.BinaryUnmarshaler) error {
+	conn, err := s.dialer.DialNode(s.nodeID)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := EncodeTLV(conn, typ, req); err != nil {
+		return err
+	}
+	_, err = DecodeTLV(conn, resp)
+	return err
+}
+
+// mergeMeasurementNames returns the sorted union of measurement names.
+func mergeMeasurementNames(a ...[][]byte) [][]byte {
+	if len(a) == 1 {
+		return a[0]
+	}
+
+	set := make(map[string]struct{})
+	for _, names := range a {
+		for _, name := range names {
+			set[string(name)] = struct{}{}
+		}
+	}
+
+	names := make([][]byte, 0, len(set))
+	for name := range set {
+		names = append(names, []byte(name))
+	}
+	sort.Slice(names, func(i, j int) bool { return bytes.Compare(names[i], names[j]) < 0 })
+	return names
+}
+
+// mergeTagKeys returns the union of tag keys per measurement, sorted by
+// measurement and key.
+func mergeTagKeys(a ...[]tsdb.TagKeys) []tsdb.TagKeys {
+	if len(a) == 1 {
+		return a[0]
+	}
+
+	sets := make(map[string]map[string]struct{})
+	for _, tagKeys := range a {
+		for _, m := range tagKeys {
+			set, ok := sets[m.Measurement]
+			if !ok {
+				set = make(map[string]struct{})
+				sets[m.Measurement] = set
+			}
+			for _, key := range m.Keys {
+				set[key] = struct{}{}
+			}
+		}
+	}
+
+	result := make([]tsdb.TagKeys, 0, len(sets))
+	for name, set := range sets {
+		keys := make([]string, 0, len(set))
+		for key := range set {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		result = append(result, tsdb.TagKeys{Measurement: name, Keys: keys})
+	}
+	sort.Sort(tsdb.TagKeysSlice(result))
+	return result
+}
+
+// mergeTagValues returns the union of tag values per measurement, sorted by
+// measurement, key and value.
+func mergeTagValues(a ...[]tsdb.TagValues) []tsdb.TagValues {
+	if len(a) == 1 {
+		return a[0]
+	}
+
+	sets := make(map[string]map[tsdb.KeyValue]struct{})
+	for _, tagValues := range a {
+		for _, m := range tagValues {
+			set, ok := sets[m.Measurement]
+			if !ok {
+				set = make(map[tsdb.KeyValue]struct{})
+				sets[m.Measurement] = set
+			}
+			for _, kv := range m.Values {
+				set[kv] = struct{}{}
+			}
+		}
+	}
+
+	result := make([]tsdb.TagValues, 0, len(sets))
+	for name, set := range sets {
+		values := make([]tsdb.KeyValue, 0, len(set))
+		for kv := range set {
+			values = append(values, kv)
+		}
+		sort.Sort(tsdb.KeyValues(values))
+		result = append(result, tsdb.TagValues{Measurement: name, Values: values})
+	}
+	sort.Sort(tsdb.TagValuesSlice(result))
+	return result
+}
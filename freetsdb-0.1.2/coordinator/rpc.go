@@ -8,8 +8,12 @@ import (
 
 	"github.com/freetsdb/freetsdb/coordinator/internal"
 	"github.com/freetsdb/freetsdb/models"
+	"github.com/freetsdb/freetsdb/pkg/estimator"
+	"github.com/freetsdb/freetsdb/pkg/estimator/hll"
+	"github.com/freetsdb/freetsdb/platform/storage/reads/datatypes"
 	"github.com/freetsdb/freetsdb/query"
 	"github.com/freetsdb/freetsdb/services/influxql"
+	"github.com/freetsdb/freetsdb/tsdb"
 	"github.com/gogo/protobuf/proto"
 )
 
@@ -360,3 +364,538 @@ func (r *FieldDimensionsResponse) UnmarshalBinary(data []byte) error {
 	}
 	return nil
 }
+
+// MeasurementNamesRequest represents a request to retrieve the measurement
+// names of a database on a remote node.
+type MeasurementNamesRequest struct {
+	Database  string
+	Condition influxql.Expr
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *MeasurementNamesRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.MeasurementNamesRequest{
+		Database:  proto.String(r.Database),
+		Condition: marshalCondition(r.Condition),
+	})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *MeasurementNamesRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.MeasurementNamesRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.Database = pb.GetDatabase()
+	cond, err := unmarshalCondition(pb.GetCondition())
+	if err != nil {
+		return err
+	}
+	r.Condition = cond
+	return nil
+}
+
+// MeasurementNamesResponse represents a response to a MeasurementNamesRequest.
+type MeasurementNamesResponse struct {
+	Names [][]byte
+	Err   error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *MeasurementNamesResponse) MarshalBinary() ([]byte, error) {
+	pb := internal.MeasurementNamesResponse{Names: r.Names}
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *MeasurementNamesResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.MeasurementNamesResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.Names = pb.GetNames()
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
+// TagKeysRequest represents a request to retrieve the tag keys of a set of
+// shards on a remote node.
+type TagKeysRequest struct {
+	ShardIDs  []uint64
+	Condition influxql.Expr
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *TagKeysRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.TagKeysRequest{
+		ShardIDs:  r.ShardIDs,
+		Condition: marshalCondition(r.Condition),
+	})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *TagKeysRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.TagKeysRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.ShardIDs = pb.GetShardIDs()
+	cond, err := unmarshalCondition(pb.GetCondition())
+	if err != nil {
+		return err
+	}
+	r.Condition = cond
+	return nil
+}
+
+// TagKeysResponse represents a response to a TagKeysRequest.
+type TagKeysResponse struct {
+	TagKeys []tsdb.TagKeys
+	Err     error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *TagKeysResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.TagKeysResponse
+
+	buf, err := json.Marshal(r.TagKeys)
+	if err != nil {
+		return nil, err
+	}
+	pb.TagKeys = buf
+
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *TagKeysResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.TagKeysResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(pb.GetTagKeys(), &r.TagKeys); err != nil {
+		return err
+	}
+
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
+// TagValuesRequest represents a request to retrieve the tag values of a set
+// of shards on a remote node.
+type TagValuesRequest struct {
+	ShardIDs  []uint64
+	Condition influxql.Expr
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *TagValuesRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.TagValuesRequest{
+		ShardIDs:  r.ShardIDs,
+		Condition: marshalCondition(r.Condition),
+	})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *TagValuesRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.TagValuesRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.ShardIDs = pb.GetShardIDs()
+	cond, err := unmarshalCondition(pb.GetCondition())
+	if err != nil {
+		return err
+	}
+	r.Condition = cond
+	return nil
+}
+
+// TagValuesResponse represents a response to a TagValuesRequest.
+type TagValuesResponse struct {
+	TagValues []tsdb.TagValues
+	Err       error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *TagValuesResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.TagValuesResponse
+
+	buf, err := json.Marshal(r.TagValues)
+	if err != nil {
+		return nil, err
+	}
+	pb.TagValues = buf
+
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *TagValuesResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.TagValuesResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(pb.GetTagValues(), &r.TagValues); err != nil {
+		return err
+	}
+
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
+// SeriesSketchesRequest represents a request to retrieve the series sketches
+// of a database on a remote node.
+type SeriesSketchesRequest struct {
+	Database string
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *SeriesSketchesRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.SeriesSketchesRequest{
+		Database: proto.String(r.Database),
+	})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *SeriesSketchesRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.SeriesSketchesRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	r.Database = pb.GetDatabase()
+	return nil
+}
+
+// SeriesSketchesResponse represents a response to a SeriesSketchesRequest.
+// Sketch estimates the series in the database and TSSketch the tombstoned
+// series.
+type SeriesSketchesResponse struct {
+	Sketch   estimator.Sketch
+	TSSketch estimator.Sketch
+	Err      error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *SeriesSketchesResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.SeriesSketchesResponse
+
+	if r.Sketch != nil {
+		buf, err := r.Sketch.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		pb.Sketch = buf
+	}
+
+	if r.TSSketch != nil {
+		buf, err := r.TSSketch.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		pb.TSSketch = buf
+	}
+
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *SeriesSketchesResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.SeriesSketchesResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	if pb.Sketch != nil {
+		r.Sketch = hll.NewDefaultPlus()
+		if err := r.Sketch.UnmarshalBinary(pb.GetSketch()); err != nil {
+			return err
+		}
+	}
+
+	if pb.TSSketch != nil {
+		r.TSSketch = hll.NewDefaultPlus()
+		if err := r.TSSketch.UnmarshalBinary(pb.GetTSSketch()); err != nil {
+			return err
+		}
+	}
+
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
+// ReadSeriesRequest represents a request to list the series and fields
+// matching a storage predicate in shards on a remote node.
+type ReadSeriesRequest struct {
+	ShardIDs  []uint64
+	Predicate *datatypes.Predicate
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ReadSeriesRequest) MarshalBinary() ([]byte, error) {
+	pb := internal.ReadSeriesRequest{ShardIDs: r.ShardIDs}
+	if r.Predicate != nil {
+		buf, err := r.Predicate.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		pb.Predicate = buf
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ReadSeriesRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.ReadSeriesRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.ShardIDs = pb.GetShardIDs()
+	if pb.Predicate != nil {
+		r.Predicate = &datatypes.Predicate{}
+		if err := r.Predicate.Unmarshal(pb.GetPredicate()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSeriesResponse represents a batch of series rows streamed in response
+// to a ReadSeriesRequest. An empty batch marks the end of the stream.
+type ReadSeriesResponse struct {
+	Rows []ReadSeriesRow
+	Err  error
+}
+
+// ReadSeriesRow is a single series and field.
+type ReadSeriesRow struct {
+	Name  []byte
+	Tags  models.Tags
+	Field string
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ReadSeriesResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.ReadSeriesResponse
+	for _, row := range r.Rows {
+		pb.Keys = append(pb.Keys, models.MakeKey(row.Name, row.Tags))
+		pb.Fields = append(pb.Fields, row.Field)
+	}
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ReadSeriesResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.ReadSeriesResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	keys, fields := pb.GetKeys(), pb.GetFields()
+	if len(keys) != len(fields) {
+		return fmt.Errorf("series count mismatch: %d keys, %d fields", len(keys), len(fields))
+	}
+
+	r.Rows = make([]ReadSeriesRow, len(keys))
+	for i := range keys {
+		r.Rows[i].Name, r.Rows[i].Tags = models.ParseKeyBytes(keys[i])
+		r.Rows[i].Field = fields[i]
+	}
+
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
+// ReadCursorRequest represents a request to open a cursor over a series
+// field in shards on a remote node.
+type ReadCursorRequest struct {
+	ShardIDs  []uint64
+	Name      []byte
+	Tags      models.Tags
+	Field     string
+	Ascending bool
+	StartTime int64
+	EndTime   int64
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ReadCursorRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.ReadCursorRequest{
+		ShardIDs:  r.ShardIDs,
+		SeriesKey: models.MakeKey(r.Name, r.Tags),
+		Field:     proto.String(r.Field),
+		Ascending: proto.Bool(r.Ascending),
+		StartTime: proto.Int64(r.StartTime),
+		EndTime:   proto.Int64(r.EndTime),
+	})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ReadCursorRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.ReadCursorRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.ShardIDs = pb.GetShardIDs()
+	r.Name, r.Tags = models.ParseKeyBytes(pb.GetSeriesKey())
+	r.Field = pb.GetField()
+	r.Ascending = pb.GetAscending()
+	r.StartTime = pb.GetStartTime()
+	r.EndTime = pb.GetEndTime()
+	return nil
+}
+
+// ReadCursorResponse represents a block of values read from a remote cursor.
+// Type is influxql.Unknown if the series has no data in the shards. A block
+// without timestamps marks the end of the cursor.
+type ReadCursorResponse struct {
+	Type           influxql.DataType
+	Timestamps     []int64
+	FloatValues    []float64
+	IntegerValues  []int64
+	UnsignedValues []uint64
+	StringValues   []string
+	BooleanValues  []bool
+	Err            error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ReadCursorResponse) MarshalBinary() ([]byte, error) {
+	pb := internal.ReadCursorResponse{
+		Type:           proto.Int32(int32(r.Type)),
+		Timestamps:     r.Timestamps,
+		FloatValues:    r.FloatValues,
+		IntegerValues:  r.IntegerValues,
+		UnsignedValues: r.UnsignedValues,
+		StringValues:   r.StringValues,
+		BooleanValues:  r.BooleanValues,
+	}
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ReadCursorResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.ReadCursorResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.Type = influxql.DataType(pb.GetType())
+	r.Timestamps = pb.GetTimestamps()
+	r.FloatValues = pb.GetFloatValues()
+	r.IntegerValues = pb.GetIntegerValues()
+	r.UnsignedValues = pb.GetUnsignedValues()
+	r.StringValues = pb.GetStringValues()
+	r.BooleanValues = pb.GetBooleanValues()
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
+// CompactionsRequest represents a request to retrieve the compactions running
+// on a remote node.
+type CompactionsRequest struct{}
+
+// MarshalBinary encodes r to a binary format.
+func (r *CompactionsRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.CompactionsRequest{})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *CompactionsRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.CompactionsRequest
+	return proto.Unmarshal(data, &pb)
+}
+
+// CompactionsResponse represents a response to a CompactionsRequest.
+type CompactionsResponse struct {
+	Compactions []tsdb.CompactionInfo
+	Err         error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *CompactionsResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.CompactionsResponse
+
+	buf, err := json.Marshal(r.Compactions)
+	if err != nil {
+		return nil, err
+	}
+	pb.Compactions = buf
+
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *CompactionsResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.CompactionsResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(pb.GetCompactions(), &r.Compactions); err != nil {
+		return err
+	}
+
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
+// marshalCondition encodes a condition as InfluxQL. A nil condition is
+// encoded as nil.
+func marshalCondition(cond influxql.Expr) *string {
+	if cond == nil {
+		return nil
+	}
+	return proto.String(cond.String())
+}
+
+// unmarshalCondition parses a condition encoded by marshalCondition.
+func unmarshalCondition(s string) (influxql.Expr, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return influxql.ParseExpr(s)
+}
@@ -1,13 +1,9 @@
 package coordinator
 
 import (
-	"errors"
-	"reflect"
+	"bytes"
 	"testing"
 	"time"
-
-	"github.com/davecgh/go-spew/spew"
-	"github.com/freetsdb/freetsdb/services/influxql"
 )
 
 func TestWriteShardRequestBinary(t *testing.T) {
@@ -48,7 +44,7 @@ func TestWriteShardRequestBinary(t *testing.T) {
 	for i, p := range srPoints {
 		g := gotPoints[i]
 
-		if g.Name() != p.Name() {
+		if !bytes.Equal(g.Name(), p.Name()) {
 			t.Errorf("Point %d name mismatch: got %v, exp %v", i, g.Name(), p.Name())
 		}
 
@@ -56,23 +52,32 @@ func TestWriteShardRequestBinary(t *testing.T) {
 			t.Errorf("Point %d time mismatch: got %v, exp %v", i, g.Time(), p.Time())
 		}
 
-		if g.HashID() != p.HashID() {
-			t.Errorf("Point #%d HashID() mismatch: got %v, exp %v", i, g.HashID(), p.HashID())
+		if !bytes.Equal(g.Key(), p.Key()) {
+			t.Errorf("Point #%d Key() mismatch: got %s, exp %s", i, g.Key(), p.Key())
 		}
 
-		for k, v := range p.Tags() {
-			if g.Tags()[k] != v {
-				t.Errorf("Point #%d tag mismatch: got %v, exp %v", i, k, v)
+		for _, tag := range p.Tags() {
+			if v := g.Tags().Get(tag.Key); !bytes.Equal(v, tag.Value) {
+				t.Errorf("Point #%d tag mismatch: got %s, exp %s", i, tag.Key, tag.Value)
 			}
 		}
 
-		if len(p.Fields()) != len(g.Fields()) {
-			t.Errorf("Point %d field count mismatch: got %v, exp %v", i, len(g.Fields()), len(p.Fields()))
+		pFields, err := p.Fields()
+		if err != nil {
+			t.Fatal(err)
+		}
+		gFields, err := g.Fields()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(pFields) != len(gFields) {
+			t.Errorf("Point %d field count mismatch: got %v, exp %v", i, len(gFields), len(pFields))
 		}
 
-		for j, f := range p.Fields() {
-			if g.Fields()[j] != f {
-				t.Errorf("Point %d field mismatch: got %v, exp %v", i, g.Fields()[j], f)
+		for j, f := range pFields {
+			if gFields[j] != f {
+				t.Errorf("Point %d field mismatch: got %v, exp %v", i, gFields[j], f)
 			}
 		}
 	}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding"
 	"encoding/binary"
+	"errors"
 	"expvar"
 	"fmt"
 	"io"
@@ -14,9 +15,11 @@ import (
 	//"time"
 
 	"github.com/freetsdb/freetsdb"
+	"github.com/freetsdb/freetsdb/platform/tsdb/cursors"
 	"github.com/freetsdb/freetsdb/query"
 	"github.com/freetsdb/freetsdb/services/influxql"
 	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/services/storage"
 	"github.com/freetsdb/freetsdb/tsdb"
 	"go.uber.org/zap"
 )
@@ -41,8 +44,22 @@ const (
 
 	seriesKeysReq  = "seriesKeysReq"
 	seriesKeysResp = "seriesKeysResp"
+
+	measurementNamesReq = "measurementNamesReq"
+	tagKeysReq          = "tagKeysReq"
+	tagValuesReq        = "tagValuesReq"
+	seriesSketchesReq   = "seriesSketchesReq"
+
+	readSeriesReq = "readSeriesReq"
+	readCursorReq = "readCursorReq"
+
+	compactionsReq = "compactionsReq"
 )
 
+// readSeriesBatchSize is the number of series rows sent in each
+// ReadSeriesResponse.
+const readSeriesBatchSize = 1000
+
 // Service processes data received over raw TCP connections.
 type Service struct {
 	mu sync.RWMutex
@@ -54,6 +71,7 @@ type Service struct {
 
 	MetaClient interface {
 		ShardOwner(shardID uint64) (string, string, *meta.ShardGroupInfo)
+		TrashEnabled() bool
 	}
 
 	TSDBStore TSDBStore
@@ -192,6 +210,33 @@ func (s *Service) handleConn(conn net.Conn) {
 			s.statMap.Add(fieldDimensionsReq, 1)
 			s.processFieldDimensionsRequest(conn)
 			return
+		case measurementNamesRequestMessage:
+			s.statMap.Add(measurementNamesReq, 1)
+			s.processMeasurementNamesRequest(conn)
+			return
+		case tagKeysRequestMessage:
+			s.statMap.Add(tagKeysReq, 1)
+			s.processTagKeysRequest(conn)
+			return
+		case tagValuesRequestMessage:
+			s.statMap.Add(tagValuesReq, 1)
+			s.processTagValuesRequest(conn)
+			return
+		case seriesSketchesRequestMessage:
+			s.statMap.Add(seriesSketchesReq, 1)
+			s.processSeriesSketchesRequest(conn)
+			return
+		case readSeriesRequestMessage:
+			s.statMap.Add(readSeriesReq, 1)
+			s.processReadSeriesRequest(conn)
+			return
+		case readCursorRequestMessage:
+			s.processReadCursorRequests(conn)
+			return
+		case compactionsRequestMessage:
+			s.statMap.Add(compactionsReq, 1)
+			s.processCompactionsRequest(conn)
+			return
 		default:
 			s.Logger.Info("coordinator service message type not found:", zap.Uint8("Type", uint8(typ)))
 		}
@@ -217,13 +262,40 @@ func (s *Service) processExecuteStatementRequest(buf []byte) error {
 func (s *Service) executeStatement(stmt influxql.Statement, database string) error {
 	switch t := stmt.(type) {
 	case *influxql.DropDatabaseStatement:
+		if s.MetaClient.TrashEnabled() {
+			return s.TSDBStore.TrashDatabase(t.Name)
+		}
 		return s.TSDBStore.DeleteDatabase(t.Name)
 	case *influxql.DropMeasurementStatement:
+		if s.MetaClient.TrashEnabled() {
+			return s.TSDBStore.TrashMeasurement(database, t.Name)
+		}
 		return s.TSDBStore.DeleteMeasurement(database, t.Name)
 	case *influxql.DropSeriesStatement:
 		return s.TSDBStore.DeleteSeries(database, t.Sources, t.Condition)
+	case *influxql.AlterFieldTypeStatement:
+		return s.TSDBStore.ConvertFieldType(database, t.Measurement, t.Field, t.Type)
+	case *influxql.CompactShardStatement:
+		return ignoreShardNotFound(s.TSDBStore.CompactShard(t.ID, t.Optimize))
+	case *influxql.PauseCompactionsStatement:
+		s.TSDBStore.SetCompactionsPaused(t.Database, true)
+		return nil
+	case *influxql.ResumeCompactionsStatement:
+		s.TSDBStore.SetCompactionsPaused(t.Database, false)
+		return nil
 	case *influxql.DropRetentionPolicyStatement:
 		return s.TSDBStore.DeleteRetentionPolicy(database, t.Name)
+	case *influxql.DropShardStatement:
+		if s.MetaClient.TrashEnabled() {
+			return s.TSDBStore.TrashShard(t.ID)
+		}
+		return s.TSDBStore.DeleteShard(t.ID)
+	case *influxql.UndropDatabaseStatement:
+		return ignoreNotInTrash(s.TSDBStore.UndropDatabase(t.Name))
+	case *influxql.UndropMeasurementStatement:
+		return ignoreNotInTrash(s.TSDBStore.UndropMeasurement(database, t.Name))
+	case *influxql.UndropShardStatement:
+		return ignoreNotInTrash(s.TSDBStore.UndropShard(t.ID))
 	default:
 		return fmt.Errorf("%q should not be executed across a cluster", stmt.String())
 	}
@@ -314,16 +386,17 @@ func (s *Service) processCreateIteratorRequest(conn net.Conn) {
 		itr = ic
 		return nil
 	}(); err != nil {
-		itr.Close()
 		//s.Logger.Printf("error reading CreateIterator request: %s", err)
 		EncodeTLV(conn, createIteratorResponseMessage, &CreateIteratorResponse{Err: err})
 		return
 	}
 
-	// Exit if no iterator was produced.
+	// Report an unknown type if no iterator was produced.
 	if itr == nil {
+		EncodeTLV(conn, createIteratorResponseMessage, &CreateIteratorResponse{typ: influxql.Unknown})
 		return
 	}
+	defer itr.Close()
 
 	var typ influxql.DataType
 	switch itr.(type) {
@@ -400,6 +473,335 @@ func (s *Service) processFieldDimensionsRequest(conn net.Conn) {
 	}
 }
 
+// The metadata requests below are only sent by the node coordinating a query,
+// which has already authorized it, so they are answered with an open
+// authorizer.
+
+func (s *Service) processMeasurementNamesRequest(conn net.Conn) {
+	var resp MeasurementNamesResponse
+	if err := func() error {
+		var req MeasurementNamesRequest
+		if err := DecodeLV(conn, &req); err != nil {
+			return err
+		}
+
+		names, err := s.TSDBStore.MeasurementNames(query.OpenAuthorizer, req.Database, req.Condition)
+		if err != nil {
+			return err
+		}
+		resp.Names = names
+		return nil
+	}(); err != nil {
+		s.Logger.Info("error reading MeasurementNames request", zap.Error(err))
+		resp.Err = err
+	}
+
+	if err := EncodeTLV(conn, measurementNamesResponseMessage, &resp); err != nil {
+		s.Logger.Info("error writing MeasurementNames response", zap.Error(err))
+	}
+}
+
+func (s *Service) processTagKeysRequest(conn net.Conn) {
+	var resp TagKeysResponse
+	if err := func() error {
+		var req TagKeysRequest
+		if err := DecodeLV(conn, &req); err != nil {
+			return err
+		}
+
+		keys, err := s.TSDBStore.TagKeys(query.OpenAuthorizer, req.ShardIDs, req.Condition)
+		if err != nil {
+			return err
+		}
+		resp.TagKeys = keys
+		return nil
+	}(); err != nil {
+		s.Logger.Info("error reading TagKeys request", zap.Error(err))
+		resp.Err = err
+	}
+
+	if err := EncodeTLV(conn, tagKeysResponseMessage, &resp); err != nil {
+		s.Logger.Info("error writing TagKeys response", zap.Error(err))
+	}
+}
+
+func (s *Service) processTagValuesRequest(conn net.Conn) {
+	var resp TagValuesResponse
+	if err := func() error {
+		var req TagValuesRequest
+		if err := DecodeLV(conn, &req); err != nil {
+			return err
+		}
+
+		values, err := s.TSDBStore.TagValues(query.OpenAuthorizer, req.ShardIDs, req.Condition)
+		if err != nil {
+			return err
+		}
+		resp.TagValues = values
+		return nil
+	}(); err != nil {
+		s.Logger.Info("error reading TagValues request", zap.Error(err))
+		resp.Err = err
+	}
+
+	if err := EncodeTLV(conn, tagValuesResponseMessage, &resp); err != nil {
+		s.Logger.Info("error writing TagValues response", zap.Error(err))
+	}
+}
+
+func (s *Service) processSeriesSketchesRequest(conn net.Conn) {
+	var resp SeriesSketchesResponse
+	if err := func() error {
+		var req SeriesSketchesRequest
+		if err := DecodeLV(conn, &req); err != nil {
+			return err
+		}
+
+		ss, ts, err := s.TSDBStore.SeriesSketches(req.Database)
+		if err != nil {
+			return err
+		}
+		resp.Sketch, resp.TSSketch = ss, ts
+		return nil
+	}(); err != nil {
+		s.Logger.Info("error reading SeriesSketches request", zap.Error(err))
+		resp.Err = err
+	}
+
+	if err := EncodeTLV(conn, seriesSketchesResponseMessage, &resp); err != nil {
+		s.Logger.Info("error writing SeriesSketches response", zap.Error(err))
+	}
+}
+
+func (s *Service) processCompactionsRequest(conn net.Conn) {
+	var resp CompactionsResponse
+	if err := func() error {
+		var req CompactionsRequest
+		if err := DecodeLV(conn, &req); err != nil {
+			return err
+		}
+
+		resp.Compactions = s.TSDBStore.Compactions()
+		return nil
+	}(); err != nil {
+		s.Logger.Info("error reading Compactions request", zap.Error(err))
+		resp.Err = err
+	}
+
+	if err := EncodeTLV(conn, compactionsResponseMessage, &resp); err != nil {
+		s.Logger.Info("error writing Compactions response", zap.Error(err))
+	}
+}
+
+func (s *Service) processReadSeriesRequest(conn net.Conn) {
+	if err := func() error {
+		var req ReadSeriesRequest
+		if err := DecodeLV(conn, &req); err != nil {
+			return err
+		}
+
+		cur, err := storage.NewSeriesCursor(context.Background(), req.Predicate, s.TSDBStore.Shards(req.ShardIDs))
+		if err != nil {
+			return err
+		} else if cur == nil {
+			return EncodeTLV(conn, readSeriesResponseMessage, &ReadSeriesResponse{})
+		}
+		defer cur.Close()
+
+		// Stream the series in batches, ending with an empty batch.
+		var resp ReadSeriesResponse
+		for {
+			row := cur.Next()
+			if row != nil {
+				resp.Rows = append(resp.Rows, ReadSeriesRow{
+					Name:  append([]byte(nil), row.Name...),
+					Tags:  row.SeriesTags.Clone(),
+					Field: row.Field,
+				})
+				if len(resp.Rows) < readSeriesBatchSize {
+					continue
+				}
+			} else if err := cur.Err(); err != nil {
+				return err
+			}
+
+			if err := EncodeTLV(conn, readSeriesResponseMessage, &resp); err != nil {
+				return err
+			} else if row == nil && len(resp.Rows) == 0 {
+				return nil
+			}
+			resp.Rows = resp.Rows[:0]
+		}
+	}(); err != nil {
+		s.Logger.Info("error reading ReadSeries request", zap.Error(err))
+		if err := EncodeTLV(conn, readSeriesResponseMessage, &ReadSeriesResponse{Err: err}); err != nil {
+			s.Logger.Info("error writing ReadSeries response", zap.Error(err))
+		}
+	}
+}
+
+// processReadCursorRequests serves cursor requests on conn until the client
+// closes the connection. Each request replaces the previously open cursor,
+// whose remaining blocks are then read with readCursorNextMessage.
+func (s *Service) processReadCursorRequests(conn net.Conn) {
+	var cur *readCursor
+	defer func() {
+		if cur != nil {
+			cur.Close()
+		}
+	}()
+
+	for typ := byte(readCursorRequestMessage); ; {
+		buf, err := ReadLV(conn)
+		if err != nil {
+			s.Logger.Info("unable to read length-value:", zap.Error(err))
+			return
+		}
+
+		var resp ReadCursorResponse
+		switch typ {
+		case readCursorRequestMessage:
+			s.statMap.Add(readCursorReq, 1)
+			if cur != nil {
+				cur.Close()
+				cur = nil
+			}
+
+			var req ReadCursorRequest
+			if err := req.UnmarshalBinary(buf); err != nil {
+				resp.Err = err
+				break
+			}
+			cur = newReadCursor(context.Background(), s.TSDBStore.Shards(req.ShardIDs), &cursors.CursorRequest{
+				Name:      req.Name,
+				Tags:      req.Tags,
+				Field:     req.Field,
+				Ascending: req.Ascending,
+				StartTime: req.StartTime,
+				EndTime:   req.EndTime,
+			})
+			cur.next(&resp)
+		case readCursorNextMessage:
+			if cur == nil {
+				resp.Err = errors.New("no open cursor")
+				break
+			}
+			cur.next(&resp)
+		default:
+			s.Logger.Info("unexpected cursor message type:", zap.Uint8("Type", uint8(typ)))
+			return
+		}
+
+		if err := EncodeTLV(conn, readCursorResponseMessage, &resp); err != nil {
+			s.Logger.Info("error writing ReadCursor response", zap.Error(err))
+			return
+		}
+
+		if typ, err = ReadType(conn); err != nil {
+			if !strings.HasSuffix(err.Error(), "EOF") {
+				s.Logger.Info("unable to read type", zap.Error(err))
+			}
+			return
+		}
+	}
+}
+
+// readCursor reads the blocks of a series field from the cursors of a set of
+// shards, one shard after another. Cursors whose type differs from the
+// first cursor are skipped.
+type readCursor struct {
+	typ     influxql.DataType
+	cursors []cursors.Cursor
+}
+
+// newReadCursor returns a cursor over the series field in req across shards.
+func newReadCursor(ctx context.Context, shards []*tsdb.Shard, req *cursors.CursorRequest) *readCursor {
+	c := &readCursor{}
+	for _, sh := range shards {
+		itr, err := sh.CreateCursorIterator(ctx)
+		if itr == nil || err != nil {
+			continue
+		}
+
+		cur, err := itr.Next(ctx, req)
+		if cur == nil || err != nil {
+			continue
+		}
+
+		typ := cursorType(cur)
+		if c.typ == influxql.Unknown {
+			c.typ = typ
+		} else if typ != c.typ {
+			cur.Close()
+			continue
+		}
+		c.cursors = append(c.cursors, cur)
+	}
+	return c
+}
+
+// next reads the next block of values into resp. The block is empty once
+// every cursor is exhausted.
+func (c *readCursor) next(resp *ReadCursorResponse) {
+	resp.Type = c.typ
+	for len(c.cursors) > 0 {
+		cur := c.cursors[0]
+		switch cur := cur.(type) {
+		case cursors.FloatArrayCursor:
+			a := cur.Next()
+			resp.Timestamps, resp.FloatValues = a.Timestamps, a.Values
+		case cursors.IntegerArrayCursor:
+			a := cur.Next()
+			resp.Timestamps, resp.IntegerValues = a.Timestamps, a.Values
+		case cursors.UnsignedArrayCursor:
+			a := cur.Next()
+			resp.Timestamps, resp.UnsignedValues = a.Timestamps, a.Values
+		case cursors.StringArrayCursor:
+			a := cur.Next()
+			resp.Timestamps, resp.StringValues = a.Timestamps, a.Values
+		case cursors.BooleanArrayCursor:
+			a := cur.Next()
+			resp.Timestamps, resp.BooleanValues = a.Timestamps, a.Values
+		}
+
+		if err := cur.Err(); err != nil {
+			resp.Err = err
+			return
+		} else if len(resp.Timestamps) > 0 {
+			return
+		}
+
+		cur.Close()
+		c.cursors = c.cursors[1:]
+	}
+}
+
+// Close closes the remaining cursors.
+func (c *readCursor) Close() {
+	for _, cur := range c.cursors {
+		cur.Close()
+	}
+	c.cursors = nil
+}
+
+// cursorType returns the data type of the values read by cur.
+func cursorType(cur cursors.Cursor) influxql.DataType {
+	switch cur.(type) {
+	case cursors.FloatArrayCursor:
+		return influxql.Float
+	case cursors.IntegerArrayCursor:
+		return influxql.Integer
+	case cursors.UnsignedArrayCursor:
+		return influxql.Unsigned
+	case cursors.StringArrayCursor:
+		return influxql.String
+	case cursors.BooleanArrayCursor:
+		return influxql.Boolean
+	default:
+		return influxql.Unknown
+	}
+}
+
 // ReadTLV reads a type-length-value record from r.
 func ReadTLV(r io.Reader) (byte, []byte, error) {
 	typ, err := ReadType(r)
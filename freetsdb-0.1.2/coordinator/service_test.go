@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/freetsdb/freetsdb/coordinator"
+	"github.com/freetsdb/freetsdb/internal"
 	"github.com/freetsdb/freetsdb/models"
 	"github.com/freetsdb/freetsdb/services/meta"
 	"github.com/freetsdb/freetsdb/tcp"
@@ -33,7 +34,7 @@ type testService struct {
 	muxln     net.Listener
 	responses chan *serviceResponse
 
-	TSDBStore TSDBStore
+	TSDBStore internal.TSDBStoreMock
 }
 
 func newTestWriteService(f func(shardID uint64, points []models.Point) error) testService {
@@ -105,7 +106,7 @@ type Service struct {
 	*coordinator.Service
 
 	ln        net.Listener
-	TSDBStore TSDBStore
+	TSDBStore internal.TSDBStoreMock
 }
 
 // NewService returns a new instance of Service.
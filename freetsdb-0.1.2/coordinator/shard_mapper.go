@@ -2,14 +2,19 @@ package coordinator
 
 import (
 	"context"
+	"errors"
 	"io"
 	"math/rand"
 	"net"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/freetsdb/freetsdb/query"
 	"github.com/freetsdb/freetsdb/services/influxql"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tcp"
 	"github.com/freetsdb/freetsdb/tsdb"
 )
 
@@ -27,6 +32,9 @@ type LocalShardMapper struct {
 	//}
 	MetaClient MetaClient
 
+	// Dialer connects to the other data nodes.
+	Dialer *tcp.Dialer
+
 	TSDBStore interface {
 		ShardGroup(ids []uint64) tsdb.ShardGroup
 		Shards(ids []uint64) []*tsdb.Shard
@@ -37,14 +45,16 @@ type LocalShardMapper struct {
 // MapShards maps the sources to the appropriate shards into an IteratorCreator.
 func (e *LocalShardMapper) MapShards(sources influxql.Sources, t influxql.TimeRange, opt query.SelectOptions) (query.ShardGroup, error) {
 	a := &LocalShardMapping{
-		ShardMap:  make(map[Source]tsdb.ShardGroup),
-		RemoteICs: make(map[Source][]remoteIteratorCreator),
+		ShardMap:   make(map[Source]tsdb.ShardGroup),
+		RemoteICs:  make(map[Source][]remoteIteratorCreator),
+		ReplicaICs: make(map[Source][]*replicaIteratorCreator),
 	}
 
 	tmin := time.Unix(0, t.MinTimeNano())
 	tmax := time.Unix(0, t.MaxTimeNano())
 	a.MinTime, a.MaxTime = tmin, tmax
 	a.LocalNodeID = opt.NodeID
+	a.ReadConsistency = opt.ReadConsistency
 	if err := e.mapShards(a, sources, tmin, tmax); err != nil {
 		return nil, err
 	}
@@ -78,29 +88,33 @@ func (e *LocalShardMapper) mapShards(a *LocalShardMapping, sources influxql.Sour
 				shardIDs := make([]uint64, 0, len(groups[0].Shards)*len(groups))
 				for _, g := range groups {
 					for _, si := range g.Shards {
-						var nodeID uint64
-						if si.OwnedBy(a.LocalNodeID) {
-							nodeID = a.LocalNodeID
-						} else if len(si.Owners) > 0 {
-							nodeID = si.Owners[rand.Intn(len(si.Owners))].NodeID
-						} else {
-							// This should not occur but if the shard has no owners then
-							// we don't want this to panic by trying to randomly select a node.
+						// Shards with several owners are read from as many of
+						// them as the read consistency requires.
+						if required := a.ReadConsistency.Owners(len(si.Owners)); required > 1 {
+							a.ReplicaICs[source] = append(a.ReplicaICs[source], e.newReplicaIteratorCreator(a, s, si, required))
 							continue
 						}
-						if nodeID == a.LocalNodeID {
+
+						if si.OwnedBy(a.LocalNodeID) {
 							shardIDs = append(shardIDs, si.ID)
+							continue
+						}
 
-						} else {
-							dialer := &NodeDialer{
-								MetaClient: e.MetaClient,
-								Timeout:    time.Duration(3 * time.Second),
-							}
-							remoteShardIDs := []uint64{si.ID}
-							remoteIC := newRemoteIteratorCreator(dialer, nodeID, remoteShardIDs)
-							a.RemoteICs[source] = append(a.RemoteICs[source], remoteIC)
+						// This should not occur but if the shard has no owners then
+						// we don't want this to panic by trying to select a node.
+						nodeIDs := shardOwnerIDs(e.MetaClient, si)
+						if len(nodeIDs) == 0 {
+							continue
+						}
 
+						dialer := &NodeDialer{
+							MetaClient: e.MetaClient,
+							Dialer:     e.Dialer,
+							Timeout:    time.Duration(3 * time.Second),
 						}
+						remoteShardIDs := []uint64{si.ID}
+						remoteIC := newRemoteIteratorCreator(dialer, nodeIDs, remoteShardIDs)
+						a.RemoteICs[source] = append(a.RemoteICs[source], remoteIC)
 					}
 				}
 				shards := e.TSDBStore.Shards(shardIDs)
@@ -122,12 +136,45 @@ func (e *LocalShardMapper) mapShards(a *LocalShardMapping, sources influxql.Sour
 	return nil
 }
 
+// newReplicaIteratorCreator returns a replicaIteratorCreator reading required
+// owners of a shard, starting with the local node if it is an owner.
+func (e *LocalShardMapper) newReplicaIteratorCreator(a *LocalShardMapping, m *influxql.Measurement, si meta.ShardInfo, required int) *replicaIteratorCreator {
+	ic := &replicaIteratorCreator{
+		shardID: si.ID,
+		dialer: &NodeDialer{
+			MetaClient: e.MetaClient,
+			Dialer:     e.Dialer,
+			Timeout:    time.Duration(3 * time.Second),
+		},
+		required: required,
+	}
+
+	for _, nodeID := range shardOwnerIDs(e.MetaClient, si) {
+		if nodeID != a.LocalNodeID {
+			ic.nodeIDs = append(ic.nodeIDs, nodeID)
+			continue
+		}
+
+		if len(e.TSDBStore.Shards([]uint64{si.ID})) == 0 {
+			e.TSDBStore.CreateShard(m.Database, m.RetentionPolicy, si.ID, true)
+		}
+		ic.local = e.TSDBStore.ShardGroup([]uint64{si.ID})
+	}
+	return ic
+}
+
 // ShardMapper maps data sources to a list of shard information.
 type LocalShardMapping struct {
 	ShardMap map[Source]tsdb.ShardGroup
 
 	RemoteICs map[Source][]remoteIteratorCreator
 
+	// ReplicaICs read the shards with several owners when ReadConsistency
+	// requires more than one owner to be read.
+	ReplicaICs map[Source][]*replicaIteratorCreator
+
+	ReadConsistency query.ReadConsistency
+
 	// MinTime is the minimum time that this shard mapper will allow.
 	// Any attempt to use a time before this one will automatically result in using
 	// this time instead.
@@ -149,7 +196,8 @@ func (a *LocalShardMapping) FieldDimensions(m *influxql.Measurement) (fields map
 
 	sg := a.ShardMap[source]
 	RemoteICs := a.RemoteICs[source]
-	if sg == nil && RemoteICs == nil {
+	ReplicaICs := a.ReplicaICs[source]
+	if sg == nil && RemoteICs == nil && ReplicaICs == nil {
 		return nil, nil, nil
 	}
 
@@ -158,7 +206,7 @@ func (a *LocalShardMapping) FieldDimensions(m *influxql.Measurement) (fields map
 
 	var measurements []string
 	if m.Regex != nil {
-		measurements = sg.MeasurementsByRegex(m.Regex.Val)
+		measurements = a.measurementsByRegex(source, m.Regex.Val)
 	} else {
 		measurements = []string{m.Name}
 	}
@@ -191,9 +239,52 @@ func (a *LocalShardMapping) FieldDimensions(m *influxql.Measurement) (fields map
 		}
 	}
 
+	for _, replicaIC := range ReplicaICs {
+		f, d, err := replicaIC.FieldDimensions(m, measurements)
+		if err != nil {
+			return nil, nil, err
+		}
+		for k, typ := range f {
+			fields[k] = typ
+		}
+		for k := range d {
+			dimensions[k] = struct{}{}
+		}
+	}
+
 	return
 }
 
+// measurementsByRegex returns the names of the measurements matching re in the
+// local shards of source, including the shards read from several owners.
+func (a *LocalShardMapping) measurementsByRegex(source Source, re *regexp.Regexp) []string {
+	var names []string
+	if sg := a.ShardMap[source]; sg != nil {
+		names = sg.MeasurementsByRegex(re)
+	}
+	if len(a.ReplicaICs[source]) == 0 {
+		return names
+	}
+
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	for _, ic := range a.ReplicaICs[source] {
+		if ic.local == nil {
+			continue
+		}
+		for _, name := range ic.local.MeasurementsByRegex(re) {
+			if _, ok := set[name]; !ok {
+				set[name] = struct{}{}
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (a *LocalShardMapping) MapType(m *influxql.Measurement, field string) influxql.DataType {
 	source := Source{
 		Database:        m.Database,
@@ -207,7 +298,7 @@ func (a *LocalShardMapping) MapType(m *influxql.Measurement, field string) influ
 
 	var names []string
 	if m.Regex != nil {
-		names = sg.MeasurementsByRegex(m.Regex.Val)
+		names = a.measurementsByRegex(source, m.Regex.Val)
 	} else {
 		names = []string{m.Name}
 	}
@@ -221,6 +312,14 @@ func (a *LocalShardMapping) MapType(m *influxql.Measurement, field string) influ
 		if typ.LessThan(t) {
 			typ = t
 		}
+		for _, ic := range a.ReplicaICs[source] {
+			if ic.local == nil {
+				continue
+			}
+			if t := ic.local.MapType(name, field); typ.LessThan(t) {
+				typ = t
+			}
+		}
 	}
 
 	return typ
@@ -244,7 +343,8 @@ func (a *LocalShardMapping) CreateIterator(ctx context.Context, m *influxql.Meas
 
 	sg := a.ShardMap[source]
 	RemoteICs := a.RemoteICs[source]
-	if sg == nil && RemoteICs == nil {
+	ReplicaICs := a.ReplicaICs[source]
+	if sg == nil && RemoteICs == nil && ReplicaICs == nil {
 		return nil, nil
 	}
 
@@ -258,7 +358,7 @@ func (a *LocalShardMapping) CreateIterator(ctx context.Context, m *influxql.Meas
 
 	inputs := []query.Iterator{}
 	if m.Regex != nil {
-		measurements := sg.MeasurementsByRegex(m.Regex.Val)
+		measurements := a.measurementsByRegex(source, m.Regex.Val)
 		if err := func() error {
 			// Create a Measurement for each returned matching measurement value
 			// from the regex.
@@ -278,6 +378,14 @@ func (a *LocalShardMapping) CreateIterator(ctx context.Context, m *influxql.Meas
 					}
 					inputs = append(inputs, input)
 				}
+
+				for _, replicaIC := range ReplicaICs {
+					input, err := replicaIC.CreateIterator(ctx, mm, opt)
+					if err != nil {
+						return err
+					}
+					inputs = append(inputs, input)
+				}
 			}
 			return nil
 		}(); err != nil {
@@ -300,30 +408,70 @@ func (a *LocalShardMapping) CreateIterator(ctx context.Context, m *influxql.Meas
 			}
 			inputs = append(inputs, input)
 		}
+
+		for _, replicaIC := range ReplicaICs {
+			input, err := replicaIC.CreateIterator(ctx, m, opt)
+			if err != nil {
+				query.Iterators(inputs).Close()
+				return nil, err
+			}
+			inputs = append(inputs, input)
+		}
 	}
 
 	return query.Iterators(inputs).Merge(opt)
 }
 
+// shardOwnerIDs returns the IDs of the nodes owning a shard in the order they
+// should be tried. Owners that are up come first in random order, to spread
+// the load between replicas, followed by the owners marked as down.
+func shardOwnerIDs(mc MetaClient, si meta.ShardInfo) []uint64 {
+	var up, down []uint64
+	for _, i := range rand.Perm(len(si.Owners)) {
+		id := si.Owners[i].NodeID
+		if ni, err := mc.DataNode(id); err == nil && ni.Down() {
+			down = append(down, id)
+		} else {
+			up = append(up, id)
+		}
+	}
+	return append(up, down...)
+}
+
 // remoteIteratorCreator creates iterators for remote shards.
 type remoteIteratorCreator struct {
 	dialer   *NodeDialer
-	nodeID   uint64
+	nodeIDs  []uint64
 	shardIDs []uint64
 }
 
-// newRemoteIteratorCreator returns a new instance of remoteIteratorCreator for a remote shard.
-func newRemoteIteratorCreator(dialer *NodeDialer, nodeID uint64, shardIDs []uint64) remoteIteratorCreator {
+// newRemoteIteratorCreator returns a new instance of remoteIteratorCreator for
+// a remote shard. The owners in nodeIDs are tried in order until one of them
+// can be reached.
+func newRemoteIteratorCreator(dialer *NodeDialer, nodeIDs []uint64, shardIDs []uint64) remoteIteratorCreator {
 	return remoteIteratorCreator{
 		dialer:   dialer,
-		nodeID:   nodeID,
+		nodeIDs:  nodeIDs,
 		shardIDs: shardIDs,
 	}
 }
 
+// dial returns a connection to the first owner that can be reached.
+func (ic *remoteIteratorCreator) dial() (conn net.Conn, err error) {
+	for _, nodeID := range ic.nodeIDs {
+		if conn, err = ic.dialer.DialNode(nodeID); err == nil {
+			return conn, nil
+		}
+	}
+	if err == nil {
+		err = errors.New("no shard owners")
+	}
+	return nil, err
+}
+
 // CreateIterator creates a remote streaming iterator.
 func (ic *remoteIteratorCreator) CreateIterator(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
-	conn, err := ic.dialer.DialNode(ic.nodeID)
+	conn, err := ic.dial()
 	if err != nil {
 		return nil, err
 	}
@@ -344,7 +492,7 @@ func (ic *remoteIteratorCreator) CreateIterator(ctx context.Context, m *influxql
 		if _, err := DecodeTLV(conn, &resp); err != nil {
 			return err
 		} else if resp.Err != nil {
-			return err
+			return resp.Err
 		}
 
 		return nil
@@ -353,12 +501,18 @@ func (ic *remoteIteratorCreator) CreateIterator(ctx context.Context, m *influxql
 		return nil, err
 	}
 
+	// The shards had no data matching the request.
+	if resp.typ == influxql.Unknown {
+		conn.Close()
+		return nil, nil
+	}
+
 	return query.NewReaderIterator(ctx, conn, resp.typ, resp.stats), nil
 }
 
 // FieldDimensions returns the unique fields and dimensions across a list of sources.
 func (ic *remoteIteratorCreator) FieldDimensions(m *influxql.Measurement) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error) {
-	conn, err := ic.dialer.DialNode(ic.nodeID)
+	conn, err := ic.dial()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -384,6 +538,7 @@ func (ic *remoteIteratorCreator) FieldDimensions(m *influxql.Measurement) (field
 // NodeDialer dials connections to a given node.
 type NodeDialer struct {
 	MetaClient MetaClient
+	Dialer     *tcp.Dialer
 	Timeout    time.Duration
 }
 
@@ -394,18 +549,12 @@ func (d *NodeDialer) DialNode(nodeID uint64) (net.Conn, error) {
 		return nil, err
 	}
 
-	conn, err := net.Dial("tcp", ni.TCPHost)
+	conn, err := d.Dialer.Dial("tcp", ni.TCPHost, MuxHeader)
 	if err != nil {
 		return nil, err
 	}
 	conn.SetDeadline(time.Now().Add(d.Timeout))
 
-	// Write the cluster multiplexing header byte
-	if _, err := conn.Write([]byte{MuxHeader}); err != nil {
-		conn.Close()
-		return nil, err
-	}
-
 	return conn, nil
 }
 
@@ -36,6 +36,10 @@ func TestLocalShardMapper(t *testing.T) {
 	}
 
 	tsdbStore := &internal.TSDBStoreMock{}
+	tsdbStore.ShardsFn = func(ids []uint64) []*tsdb.Shard {
+		// Report every shard as present so the mapper doesn't try to create them.
+		return make([]*tsdb.Shard, len(ids))
+	}
 	tsdbStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup {
 		if !reflect.DeepEqual(ids, []uint64{1, 2, 3, 4}) {
 			t.Errorf("unexpected shard ids: %#v", ids)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/freetsdb/freetsdb/models"
 	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tcp"
 )
 
 const (
@@ -21,6 +22,28 @@ const (
 
 	fieldDimensionsRequestMessage
 	fieldDimensionsResponseMessage
+
+	measurementNamesRequestMessage
+	measurementNamesResponseMessage
+
+	tagKeysRequestMessage
+	tagKeysResponseMessage
+
+	tagValuesRequestMessage
+	tagValuesResponseMessage
+
+	seriesSketchesRequestMessage
+	seriesSketchesResponseMessage
+
+	readSeriesRequestMessage
+	readSeriesResponseMessage
+
+	readCursorRequestMessage
+	readCursorNextMessage
+	readCursorResponseMessage
+
+	compactionsRequestMessage
+	compactionsResponseMessage
 )
 
 // ShardWriter writes a set of points to a shard.
@@ -29,6 +52,9 @@ type ShardWriter struct {
 	timeout        time.Duration
 	maxConnections int
 
+	// Dialer connects to the other data nodes.
+	Dialer *tcp.Dialer
+
 	MetaClient interface {
 		DataNode(id uint64) (ni *meta.NodeInfo, err error)
 		ShardOwner(shardID uint64) (database, policy string, sgi *meta.ShardGroupInfo)
@@ -113,7 +139,7 @@ func (w *ShardWriter) dial(nodeID uint64) (net.Conn, error) {
 	// If we don't have a connection pool for that addr yet, create one
 	_, ok := w.pool.getPool(nodeID)
 	if !ok {
-		factory := &connFactory{nodeID: nodeID, clientPool: w.pool, timeout: w.timeout}
+		factory := &connFactory{nodeID: nodeID, clientPool: w.pool, timeout: w.timeout, dialer: w.Dialer}
 		factory.metaClient = w.MetaClient
 
 		p, err := NewBoundedPool(1, w.maxConnections, w.timeout, factory.dial)
@@ -145,6 +171,7 @@ var errMaxConnectionsExceeded = fmt.Errorf("can not exceed max connections of %d
 type connFactory struct {
 	nodeID  uint64
 	timeout time.Duration
+	dialer  *tcp.Dialer
 
 	clientPool interface {
 		size() int
@@ -169,15 +196,8 @@ func (c *connFactory) dial() (net.Conn, error) {
 		return nil, fmt.Errorf("node %d does not exist", c.nodeID)
 	}
 
-	conn, err := net.DialTimeout("tcp", ni.TCPHost, c.timeout)
-	if err != nil {
-		return nil, err
-	}
-
-	// Write a marker byte for cluster messages.
-	_, err = conn.Write([]byte{MuxHeader})
+	conn, err := c.dialer.DialTimeout("tcp", ni.TCPHost, MuxHeader, c.timeout)
 	if err != nil {
-		conn.Close()
 		return nil, err
 	}
 
@@ -30,7 +30,7 @@ func TestShardWriter_WriteShard_Success(t *testing.T) {
 	// Build a single point.
 	now := time.Now()
 	var points []models.Point
-	points = append(points, models.MustNewPoint("cpu", models.Tags{"host": "server01"}, map[string]interface{}{"value": int64(100)}, now))
+	points = append(points, models.MustNewPoint("cpu", models.NewTags(map[string]string{"host": "server01"}), map[string]interface{}{"value": int64(100)}, now))
 
 	// Write to shard and close.
 	if err := w.WriteShard(1, 2, points); err != nil {
@@ -48,12 +48,17 @@ func TestShardWriter_WriteShard_Success(t *testing.T) {
 	}
 
 	// Validate point.
-	if p := responses[0].points[0]; p.Name() != "cpu" {
+	p := responses[0].points[0]
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(p.Name()) != "cpu" {
 		t.Fatalf("unexpected name: %s", p.Name())
-	} else if p.Fields()["value"] != int64(100) {
-		t.Fatalf("unexpected 'value' field: %d", p.Fields()["value"])
-	} else if p.Tags()["host"] != "server01" {
-		t.Fatalf("unexpected 'host' tag: %s", p.Tags()["host"])
+	} else if fields["value"] != int64(100) {
+		t.Fatalf("unexpected 'value' field: %d", fields["value"])
+	} else if host := p.Tags().GetString("host"); host != "server01" {
+		t.Fatalf("unexpected 'host' tag: %s", host)
 	} else if p.Time().UnixNano() != now.UnixNano() {
 		t.Fatalf("unexpected time: %s", p.Time())
 	}
@@ -78,7 +83,7 @@ func TestShardWriter_WriteShard_Multiple(t *testing.T) {
 	// Build a single point.
 	now := time.Now()
 	var points []models.Point
-	points = append(points, models.MustNewPoint("cpu", models.Tags{"host": "server01"}, map[string]interface{}{"value": int64(100)}, now))
+	points = append(points, models.MustNewPoint("cpu", models.NewTags(map[string]string{"host": "server01"}), map[string]interface{}{"value": int64(100)}, now))
 
 	// Write to shard twice and close.
 	if err := w.WriteShard(1, 2, points); err != nil {
@@ -98,12 +103,17 @@ func TestShardWriter_WriteShard_Multiple(t *testing.T) {
 	}
 
 	// Validate point.
-	if p := responses[0].points[0]; p.Name() != "cpu" {
+	p := responses[0].points[0]
+	fields, err := p.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(p.Name()) != "cpu" {
 		t.Fatalf("unexpected name: %s", p.Name())
-	} else if p.Fields()["value"] != int64(100) {
-		t.Fatalf("unexpected 'value' field: %d", p.Fields()["value"])
-	} else if p.Tags()["host"] != "server01" {
-		t.Fatalf("unexpected 'host' tag: %s", p.Tags()["host"])
+	} else if fields["value"] != int64(100) {
+		t.Fatalf("unexpected 'value' field: %d", fields["value"])
+	} else if host := p.Tags().GetString("host"); host != "server01" {
+		t.Fatalf("unexpected 'host' tag: %s", host)
 	} else if p.Time().UnixNano() != now.UnixNano() {
 		t.Fatalf("unexpected time: %s", p.Time())
 	}
@@ -129,7 +139,7 @@ func TestShardWriter_WriteShard_Error(t *testing.T) {
 	ownerID := uint64(2)
 	var points []models.Point
 	points = append(points, models.MustNewPoint(
-		"cpu", models.Tags{"host": "server01"}, map[string]interface{}{"value": int64(100)}, now,
+		"cpu", models.NewTags(map[string]string{"host": "server01"}), map[string]interface{}{"value": int64(100)}, now,
 	))
 
 	if err := w.WriteShard(shardID, ownerID, points); err == nil || err.Error() != "error code 1: write shard 1: failed to write" {
@@ -160,7 +170,7 @@ func TestShardWriter_Write_ErrDialTimeout(t *testing.T) {
 	var points []models.Point
 
 	points = append(points, models.MustNewPoint(
-		"cpu", models.Tags{"host": "server01"}, map[string]interface{}{"value": int64(100)}, now,
+		"cpu", models.NewTags(map[string]string{"host": "server01"}), map[string]interface{}{"value": int64(100)}, now,
 	))
 
 	if err, exp := w.WriteShard(shardID, ownerID, points), "i/o timeout"; err == nil || !strings.Contains(err.Error(), exp) {
@@ -183,7 +193,7 @@ func TestShardWriter_Write_ErrReadTimeout(t *testing.T) {
 	ownerID := uint64(2)
 	var points []models.Point
 	points = append(points, models.MustNewPoint(
-		"cpu", models.Tags{"host": "server01"}, map[string]interface{}{"value": int64(100)}, now,
+		"cpu", models.NewTags(map[string]string{"host": "server01"}), map[string]interface{}{"value": int64(100)}, now,
 	))
 
 	if err := w.WriteShard(shardID, ownerID, points); err == nil || !strings.Contains(err.Error(), "i/o timeout") {
@@ -213,7 +223,7 @@ func TestShardWriter_Write_PoolMax(t *testing.T) {
 	ownerID := uint64(2)
 	var points []models.Point
 	points = append(points, models.MustNewPoint(
-		"cpu", models.Tags{"host": "server01"}, map[string]interface{}{"value": int64(100)}, now,
+		"cpu", models.NewTags(map[string]string{"host": "server01"}), map[string]interface{}{"value": int64(100)}, now,
 	))
 
 	go w.WriteShard(shardID, ownerID, points)
@@ -9,16 +9,22 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/freetsdb/freetsdb"
 	"github.com/freetsdb/freetsdb/models"
 	"github.com/freetsdb/freetsdb/monitor"
+	"github.com/freetsdb/freetsdb/pkg/estimator"
 	"github.com/freetsdb/freetsdb/pkg/tracing"
 	"github.com/freetsdb/freetsdb/pkg/tracing/fields"
 	"github.com/freetsdb/freetsdb/query"
+	"github.com/freetsdb/freetsdb/services/archive"
+	"github.com/freetsdb/freetsdb/services/hh"
 	"github.com/freetsdb/freetsdb/services/influxql"
 	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/services/rebalance"
+	"github.com/freetsdb/freetsdb/tcp"
 	"github.com/freetsdb/freetsdb/tsdb"
 )
 
@@ -26,6 +32,10 @@ import (
 // when a database has not been provided.
 var ErrDatabaseNameRequired = errors.New("database name required")
 
+// ErrArchiveDirNotSet is returned when restoring a shard on a node without
+// an archive directory.
+var ErrArchiveDirNotSet = errors.New("archive-dir is not set")
+
 type pointsWriter interface {
 	WritePointsInto(*IntoWriteRequest) error
 }
@@ -42,12 +52,33 @@ type StatementExecutor struct {
 	// TSDB storage for local node.
 	TSDBStore TSDBStore
 
+	// Directory RESTORE SHARD reads shard archives from.
+	ArchiveDir string
+
+	// Dialer connects to the other data nodes.
+	Dialer *tcp.Dialer
+
+	// Executes DROP and UNDROP statements on the other data nodes.
+	MetaExecutor interface {
+		ExecuteStatement(stmt influxql.Statement, database string) error
+	}
+
 	// ShardMapper for mapping shards when executing a SELECT statement.
 	ShardMapper query.ShardMapper
 
 	// Holds monitoring data for SHOW STATS and SHOW DIAGNOSTICS.
 	Monitor *monitor.Monitor
 
+	// Reports shard moves for SHOW REBALANCE.
+	Rebalancer interface {
+		Jobs() []rebalance.Job
+	}
+
+	// Reports the local hinted handoff queues for SHOW HINTED HANDOFF.
+	HintedHandoff interface {
+		Status() ([]hh.NodeStatus, error)
+	}
+
 	// Used for rewriting points back into system for SELECT INTO statements.
 	PointsWriter interface {
 		WritePointsInto(*IntoWriteRequest) error
@@ -70,11 +101,33 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 	var messages []*query.Message
 	var err error
 	switch stmt := stmt.(type) {
+	case *influxql.AlterDatabaseStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeAlterDatabaseStatement(stmt)
+	case *influxql.AlterFieldTypeStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeAlterFieldTypeStatement(stmt, ctx.Database)
 	case *influxql.AlterRetentionPolicyStatement:
+		if stmt.DryRun {
+			rows, err = e.executeAlterRetentionPolicyDryRun(stmt)
+			break
+		}
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeAlterRetentionPolicyStatement(stmt)
+	case *influxql.BackfillContinuousQueryStatement:
+		// Backfills are streamed like the SELECT INTO statement they run.
+		return e.executeBackfillContinuousQueryStatement(stmt, ctx)
+	case *influxql.CompactShardStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeCompactShardStatement(stmt)
 	case *influxql.CreateContinuousQueryStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -85,6 +138,11 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeCreateDatabaseStatement(stmt)
+	case *influxql.CreateDownsampleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeCreateDownsampleStatement(stmt)
 	case *influxql.CreateRetentionPolicyStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -117,6 +175,11 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeDropDatabaseStatement(stmt)
+	case *influxql.DropDownsampleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeDropDownsampleStatement(stmt)
 	case *influxql.DropMeasurementStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -163,6 +226,41 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeGrantAdminStatement(stmt)
+	case *influxql.PauseCompactionsStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeSetCompactionsPausedStatement(stmt, stmt.Database, true)
+	case *influxql.ResumeCompactionsStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeSetCompactionsPausedStatement(stmt, stmt.Database, false)
+	case *influxql.UndropShardGroupStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.MetaClient.UndropShardGroup(stmt.ID)
+	case *influxql.UndropDatabaseStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeUndropDatabaseStatement(stmt)
+	case *influxql.UndropMeasurementStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeUndropMeasurementStatement(stmt, ctx.Database)
+	case *influxql.UndropShardStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeUndropShardStatement(stmt)
+	case *influxql.RestoreShardStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeRestoreShardStatement(stmt)
 	case *influxql.RevokeStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -173,18 +271,30 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeRevokeAdminStatement(stmt)
+	case *influxql.ShowCompactionsStatement:
+		rows, err = e.executeShowCompactionsStatement(stmt)
 	case *influxql.ShowContinuousQueriesStatement:
 		rows, err = e.executeShowContinuousQueriesStatement(stmt)
+	case *influxql.ShowContinuousQueryStatusStatement:
+		rows, err = e.executeShowContinuousQueryStatusStatement(stmt)
 	case *influxql.ShowDatabasesStatement:
 		rows, err = e.executeShowDatabasesStatement(stmt, ctx)
+	case *influxql.ShowDownsamplesStatement:
+		rows, err = e.executeShowDownsamplesStatement(stmt)
+	case *influxql.ShowDroppedStatement:
+		rows, err = e.executeShowDroppedStatement(stmt)
 	case *influxql.ShowDiagnosticsStatement:
 		rows, err = e.executeShowDiagnosticsStatement(stmt)
 	case *influxql.ShowGrantsForUserStatement:
 		rows, err = e.executeShowGrantsForUserStatement(stmt)
 	case *influxql.ShowMeasurementsStatement:
 		return e.executeShowMeasurementsStatement(stmt, ctx)
+	case *influxql.ShowHintedHandoffStatement:
+		rows, err = e.executeShowHintedHandoffStatement(stmt)
 	case *influxql.ShowMeasurementCardinalityStatement:
 		rows, err = e.executeShowMeasurementCardinalityStatement(stmt)
+	case *influxql.ShowRebalanceStatement:
+		rows, err = e.executeShowRebalanceStatement(stmt)
 	case *influxql.ShowRetentionPoliciesStatement:
 		rows, err = e.executeShowRetentionPoliciesStatement(stmt)
 	case *influxql.ShowSeriesCardinalityStatement:
@@ -208,6 +318,11 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeSetPasswordUserStatement(stmt)
+	case *influxql.SetTrashStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.MetaClient.SetTrashEnabled(stmt.Enabled)
 	case *influxql.ShowQueriesStatement, *influxql.KillQueryStatement:
 		// Send query related statements to the task manager.
 		return e.TaskManager.ExecuteStatement(stmt, ctx)
@@ -225,11 +340,45 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 	})
 }
 
+func (e *StatementExecutor) executeAlterDatabaseStatement(stmt *influxql.AlterDatabaseStatement) error {
+	return e.MetaClient.SetDatabaseConsistency(stmt.Name, stmt.Consistency)
+}
+
+// executeAlterFieldTypeStatement converts the values of a field on every node.
+// The nodes convert their shards concurrently and the statement returns once
+// all of them are done.  Queries cast the values of the shards that are not
+// converted yet.
+func (e *StatementExecutor) executeAlterFieldTypeStatement(stmt *influxql.AlterFieldTypeStatement, database string) error {
+	if dbi := e.MetaClient.Database(database); dbi == nil {
+		return query.ErrDatabaseNotFound(database)
+	}
+
+	var remoteErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		remoteErr = e.executeOnRemoteNodes(stmt, database)
+	}()
+
+	err := e.TSDBStore.ConvertFieldType(database, stmt.Measurement, stmt.Field, stmt.Type)
+	wg.Wait()
+	if err != nil {
+		return err
+	}
+	return remoteErr
+}
+
 func (e *StatementExecutor) executeAlterRetentionPolicyStatement(stmt *influxql.AlterRetentionPolicyStatement) error {
 	rpu := &meta.RetentionPolicyUpdate{
 		Duration:           stmt.Duration,
 		ReplicaN:           stmt.Replication,
 		ShardGroupDuration: stmt.ShardGroupDuration,
+		Consistency:        stmt.Consistency,
+		ColdAfter:          stmt.ColdAfter,
+		DuplicatePolicy:    stmt.Duplicates,
+		StringCodec:        stmt.StringCodec,
+		FloatCodec:         stmt.FloatCodec,
 	}
 
 	// Update the retention policy.
@@ -246,6 +395,98 @@ func (e *StatementExecutor) executeAlterRetentionPolicyStatement(stmt *influxql.
 	return nil
 }
 
+// executeAlterRetentionPolicyDryRun returns the shard groups the retention
+// service would delete if the statement was applied, without applying it.
+func (e *StatementExecutor) executeAlterRetentionPolicyDryRun(stmt *influxql.AlterRetentionPolicyStatement) (models.Rows, error) {
+	rpi, err := e.MetaClient.RetentionPolicy(stmt.Database, stmt.Name)
+	if err != nil {
+		return nil, err
+	} else if rpi == nil {
+		return nil, freetsdb.ErrRetentionPolicyNotFound(stmt.Name)
+	}
+
+	rp := *rpi
+	if stmt.Duration != nil {
+		rp.Duration = *stmt.Duration
+	}
+
+	row := &models.Row{Columns: []string{"id", "start_time", "end_time", "expiry_time", "shards", "local_size"}, Name: "shard groups"}
+	for _, sgi := range rp.ExpiredShardGroups(time.Now().UTC()) {
+		ids := make([]uint64, len(sgi.Shards))
+		for i, si := range sgi.Shards {
+			ids[i] = si.ID
+		}
+
+		// Only the size of the shards stored on this node is known.
+		var size int64
+		for _, sh := range e.TSDBStore.Shards(ids) {
+			if n, err := sh.DiskSize(); err == nil {
+				size += n
+			}
+		}
+
+		row.Values = append(row.Values, []interface{}{
+			sgi.ID,
+			sgi.StartTime.UTC().Format(time.RFC3339),
+			sgi.EndTime.UTC().Format(time.RFC3339),
+			sgi.EndTime.Add(rp.Duration).UTC().Format(time.RFC3339),
+			joinUint64(ids),
+			size,
+		})
+	}
+	return []*models.Row{row}, nil
+}
+
+func (e *StatementExecutor) executeBackfillContinuousQueryStatement(stmt *influxql.BackfillContinuousQueryStatement, ctx *query.ExecutionContext) error {
+	dbi := e.MetaClient.Database(stmt.Database)
+	if dbi == nil {
+		return query.ErrDatabaseNotFound(stmt.Database)
+	}
+
+	var cqi *meta.ContinuousQueryInfo
+	for i := range dbi.ContinuousQueries {
+		if dbi.ContinuousQueries[i].Name == stmt.Name {
+			cqi = &dbi.ContinuousQueries[i]
+			break
+		}
+	}
+	if cqi == nil {
+		return meta.ErrContinuousQueryNotFound
+	}
+
+	q, err := influxql.ParseStatement(cqi.Query)
+	if err != nil {
+		return err
+	}
+	cq, ok := q.(*influxql.CreateContinuousQueryStatement)
+	if !ok || cq.Source.Target == nil || cq.Source.Target.Measurement == nil {
+		return errors.New("query isn't a valid continuous query")
+	}
+	sel := cq.Source
+
+	// Use the default retention policy if the query doesn't specify one, as
+	// the continuous query service does.
+	if sel.Target.Measurement.RetentionPolicy == "" {
+		sel.Target.Measurement.RetentionPolicy = dbi.DefaultRetentionPolicy
+	}
+	if err := e.NormalizeStatement(sel, stmt.Database, dbi.DefaultRetentionPolicy); err != nil {
+		return err
+	}
+
+	// The statement only requires write access to the database of the query,
+	// so check the target of the query here.
+	if a := ctx.Authorizer; a != nil {
+		if target := sel.Target.Measurement.Database; target != "" && !a.AuthorizeDatabase(influxql.WritePrivilege, target) {
+			return fmt.Errorf("%s not authorized to write to %s", stmt.Name, target)
+		}
+	}
+
+	if err := sel.SetTimeRange(stmt.StartTime, stmt.EndTime); err != nil {
+		return fmt.Errorf("unable to set time range: %s", err)
+	}
+	return e.executeSelectStatement(sel, ctx)
+}
+
 func (e *StatementExecutor) executeCreateContinuousQueryStatement(q *influxql.CreateContinuousQueryStatement) error {
 	// Verify that retention policies exist.
 	var err error
@@ -283,8 +524,10 @@ func (e *StatementExecutor) executeCreateDatabaseStatement(stmt *influxql.Create
 	}
 
 	if !stmt.RetentionPolicyCreate {
-		_, err := e.MetaClient.CreateDatabase(stmt.Name)
-		return err
+		if _, err := e.MetaClient.CreateDatabase(stmt.Name); err != nil {
+			return err
+		}
+		return e.setDatabaseConsistency(stmt)
 	}
 
 	// If we're doing, for example, CREATE DATABASE "db" WITH DURATION 1d then
@@ -301,8 +544,23 @@ func (e *StatementExecutor) executeCreateDatabaseStatement(stmt *influxql.Create
 		ReplicaN:           *stmt.RetentionPolicyReplication,
 		ShardGroupDuration: stmt.RetentionPolicyShardGroupDuration,
 	}
-	_, err := e.MetaClient.CreateDatabaseWithRetentionPolicy(stmt.Name, &rpi)
-	return err
+	if _, err := e.MetaClient.CreateDatabaseWithRetentionPolicy(stmt.Name, &rpi); err != nil {
+		return err
+	}
+	return e.setDatabaseConsistency(stmt)
+}
+
+// setDatabaseConsistency sets the write consistency level given when
+// creating a database.
+func (e *StatementExecutor) setDatabaseConsistency(stmt *influxql.CreateDatabaseStatement) error {
+	if stmt.Consistency == "" {
+		return nil
+	}
+	return e.MetaClient.SetDatabaseConsistency(stmt.Name, stmt.Consistency)
+}
+
+func (e *StatementExecutor) executeCreateDownsampleStatement(stmt *influxql.CreateDownsampleStatement) error {
+	return e.MetaClient.CreateDownsample(stmt.Database, stmt.Source, stmt.Target, stmt.Interval, stmt.Aggregates)
 }
 
 func (e *StatementExecutor) executeCreateRetentionPolicyStatement(stmt *influxql.CreateRetentionPolicyStatement) error {
@@ -317,6 +575,11 @@ func (e *StatementExecutor) executeCreateRetentionPolicyStatement(stmt *influxql
 		Duration:           stmt.Duration,
 		ReplicaN:           stmt.Replication,
 		ShardGroupDuration: stmt.ShardGroupDuration,
+		Consistency:        stmt.Consistency,
+		ColdAfter:          stmt.ColdAfter,
+		DuplicatePolicy:    stmt.Duplicates,
+		StringCodec:        stmt.StringCodec,
+		FloatCodec:         stmt.FloatCodec,
 	}
 
 	// Create new retention policy.
@@ -359,21 +622,39 @@ func (e *StatementExecutor) executeDropContinuousQueryStatement(q *influxql.Drop
 	return e.MetaClient.DropContinuousQuery(q.Database, q.Name)
 }
 
+func (e *StatementExecutor) executeDropDownsampleStatement(stmt *influxql.DropDownsampleStatement) error {
+	return e.MetaClient.DropDownsample(stmt.Database, stmt.Source, stmt.Target)
+}
+
 // executeDropDatabaseStatement drops a database from the cluster.
 // It does not return an error if the database was not found on any of
 // the nodes, or in the Meta store.
+//
+// The database is removed from the Meta Store before its files, so a node
+// that fails can't leave it half dropped. Removing the files is idempotent,
+// so the statement can be re-run until every node succeeds.
 func (e *StatementExecutor) executeDropDatabaseStatement(stmt *influxql.DropDatabaseStatement) error {
-	if e.MetaClient.Database(stmt.Name) == nil {
-		return nil
+	if e.MetaClient.TrashEnabled() {
+		// Keep the database in the Meta Store so it can be restored, and
+		// move its files to the trash on every node.
+		if err := e.MetaClient.TrashDatabase(stmt.Name); err != nil {
+			return err
+		} else if err := e.TSDBStore.TrashDatabase(stmt.Name); err != nil {
+			return err
+		}
+		return e.executeOnRemoteNodes(stmt, "")
+	}
+
+	// Remove the database from the Meta Store.
+	if err := e.MetaClient.DropDatabase(stmt.Name); err != nil {
+		return err
 	}
 
 	// Locally delete the datababse.
 	if err := e.TSDBStore.DeleteDatabase(stmt.Name); err != nil {
 		return err
 	}
-
-	// Remove the database from the Meta Store.
-	return e.MetaClient.DropDatabase(stmt.Name)
+	return e.executeOnRemoteNodes(stmt, "")
 }
 
 func (e *StatementExecutor) executeDropMeasurementStatement(stmt *influxql.DropMeasurementStatement, database string) error {
@@ -381,8 +662,22 @@ func (e *StatementExecutor) executeDropMeasurementStatement(stmt *influxql.DropM
 		return query.ErrDatabaseNotFound(database)
 	}
 
+	if e.MetaClient.TrashEnabled() {
+		// Record the measurement in the Meta Store so it can be restored,
+		// and move its data to the trash on every node.
+		if err := e.MetaClient.TrashMeasurement(database, stmt.Name); err != nil {
+			return err
+		} else if err := e.TSDBStore.TrashMeasurement(database, stmt.Name); err != nil {
+			return err
+		}
+		return e.executeOnRemoteNodes(stmt, database)
+	}
+
 	// Locally drop the measurement
-	return e.TSDBStore.DeleteMeasurement(database, stmt.Name)
+	if err := e.TSDBStore.DeleteMeasurement(database, stmt.Name); err != nil {
+		return err
+	}
+	return e.executeOnRemoteNodes(stmt, database)
 }
 
 func (e *StatementExecutor) executeDropSeriesStatement(stmt *influxql.DropSeriesStatement, database string) error {
@@ -399,14 +694,189 @@ func (e *StatementExecutor) executeDropSeriesStatement(stmt *influxql.DropSeries
 	return e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition)
 }
 
+// executeDropShardStatement drops a shard from the cluster. Like DROP
+// DATABASE, the shard is removed from the Meta Store before its files.
 func (e *StatementExecutor) executeDropShardStatement(stmt *influxql.DropShardStatement) error {
+	if e.MetaClient.TrashEnabled() {
+		// Keep the shard reference in the Meta Store so it can be restored,
+		// and move the shard to the trash on every owner.
+		if err := e.MetaClient.TrashShard(stmt.ID); err != nil {
+			return err
+		} else if err := e.TSDBStore.TrashShard(stmt.ID); err != nil {
+			return err
+		}
+		return e.executeOnRemoteNodes(stmt, "")
+	}
+
+	// Remove the shard reference from the Meta Store.
+	if err := e.MetaClient.DropShard(stmt.ID); err != nil {
+		return err
+	}
+
 	// Locally delete the shard.
 	if err := e.TSDBStore.DeleteShard(stmt.ID); err != nil {
 		return err
 	}
+	return e.executeOnRemoteNodes(stmt, "")
+}
 
-	// Remove the shard reference from the Meta Store.
-	return e.MetaClient.DropShard(stmt.ID)
+// executeUndropDatabaseStatement restores a dropped database in the Meta
+// Store and moves its files back from the trash on every node. If the
+// database is already restored in the Meta Store, a previous UNDROP failed
+// on some of the nodes and only the nodes are retried.
+func (e *StatementExecutor) executeUndropDatabaseStatement(stmt *influxql.UndropDatabaseStatement) error {
+	if e.MetaClient.Database(stmt.Name) == nil {
+		if err := e.MetaClient.UndropDatabase(stmt.Name); err != nil {
+			return err
+		}
+	}
+
+	if err := ignoreNotInTrash(e.TSDBStore.UndropDatabase(stmt.Name)); err != nil {
+		return err
+	}
+	return e.executeOnRemoteNodes(stmt, "")
+}
+
+// executeUndropMeasurementStatement imports the data of a dropped
+// measurement back from the trash on every node. The nodes are retried if
+// the measurement is no longer recorded as dropped in the Meta Store.
+func (e *StatementExecutor) executeUndropMeasurementStatement(stmt *influxql.UndropMeasurementStatement, database string) error {
+	if database == "" {
+		return ErrDatabaseNameRequired
+	}
+
+	if e.dropped(func(di *meta.DroppedInfo) bool {
+		return di.Type == meta.DroppedMeasurement && di.Database == database && di.Name == stmt.Name
+	}) {
+		if err := e.MetaClient.UndropMeasurement(database, stmt.Name); err != nil {
+			return err
+		}
+	} else if dbi := e.MetaClient.Database(database); dbi == nil {
+		return query.ErrDatabaseNotFound(database)
+	}
+
+	if err := ignoreNotInTrash(e.TSDBStore.UndropMeasurement(database, stmt.Name)); err != nil {
+		return err
+	}
+	return e.executeOnRemoteNodes(stmt, database)
+}
+
+// executeUndropShardStatement restores a dropped shard in the Meta Store and
+// moves its files back from the trash on every owner. If the shard is
+// already restored in the Meta Store, only the owners are retried.
+func (e *StatementExecutor) executeUndropShardStatement(stmt *influxql.UndropShardStatement) error {
+	if _, _, sgi := e.MetaClient.ShardOwner(stmt.ID); sgi == nil {
+		if err := e.MetaClient.UndropShard(stmt.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := ignoreNotInTrash(e.TSDBStore.UndropShard(stmt.ID)); err != nil {
+		return err
+	}
+	return e.executeOnRemoteNodes(stmt, "")
+}
+
+// dropped returns true if the Meta Store holds a dropped object matching fn.
+func (e *StatementExecutor) dropped(fn func(di *meta.DroppedInfo) bool) bool {
+	for _, di := range e.MetaClient.Dropped() {
+		if fn(&di) {
+			return true
+		}
+	}
+	return false
+}
+
+// executeOnRemoteNodes executes a statement on all other data nodes.
+func (e *StatementExecutor) executeOnRemoteNodes(stmt influxql.Statement, database string) error {
+	if e.MetaExecutor == nil {
+		return nil
+	}
+	return e.MetaExecutor.ExecuteStatement(stmt, database)
+}
+
+// ignoreNotInTrash returns nil for errors of nodes that have nothing to
+// restore from the trash.
+func ignoreNotInTrash(err error) error {
+	if err == tsdb.ErrTrashEntryNotFound {
+		return nil
+	}
+	return err
+}
+
+// ignoreShardNotFound returns nil for errors of nodes that do not own the
+// shard.
+func ignoreShardNotFound(err error) error {
+	if err == tsdb.ErrShardNotFound {
+		return nil
+	}
+	return err
+}
+
+// executeCompactShardStatement schedules a compaction of a shard on every
+// node owning it.
+func (e *StatementExecutor) executeCompactShardStatement(stmt *influxql.CompactShardStatement) error {
+	if _, _, sgi := e.MetaClient.ShardOwner(stmt.ID); sgi == nil {
+		return meta.ErrShardNotFound
+	}
+
+	if err := ignoreShardNotFound(e.TSDBStore.CompactShard(stmt.ID, stmt.Optimize)); err != nil {
+		return err
+	}
+	return e.executeOnRemoteNodes(stmt, "")
+}
+
+// executeSetCompactionsPausedStatement pauses or resumes the compactions of
+// the shards of a database on every node.
+func (e *StatementExecutor) executeSetCompactionsPausedStatement(stmt influxql.Statement, database string, paused bool) error {
+	if dbi := e.MetaClient.Database(database); dbi == nil {
+		return query.ErrDatabaseNotFound(database)
+	}
+
+	e.TSDBStore.SetCompactionsPaused(database, paused)
+	return e.executeOnRemoteNodes(stmt, database)
+}
+
+func (e *StatementExecutor) executeRestoreShardStatement(stmt *influxql.RestoreShardStatement) error {
+	if e.ArchiveDir == "" {
+		return ErrArchiveDirNotSet
+	}
+
+	database, policy, sgi := e.MetaClient.ShardOwner(stmt.ID)
+	if sgi == nil {
+		return meta.ErrShardNotFound
+	}
+	for _, si := range sgi.Shards {
+		if si.ID == stmt.ID && !si.Archived() {
+			return meta.ErrShardNotArchived
+		}
+	}
+
+	m, err := archive.ReadManifest(e.ArchiveDir, database, policy, stmt.ID)
+	if err != nil {
+		return err
+	}
+	r, err := archive.Open(e.ArchiveDir, m)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	// Locally restore the shard.
+	if err := e.TSDBStore.CreateShard(database, policy, stmt.ID, true); err != nil {
+		return err
+	}
+	if err := e.TSDBStore.RestoreShard(stmt.ID, r); err != nil {
+		e.TSDBStore.DeleteShard(stmt.ID)
+		return err
+	}
+
+	// Make this node the owner of the shard in the Meta Store.
+	if err := e.MetaClient.UnarchiveShard(stmt.ID, e.Node.ID); err != nil {
+		e.TSDBStore.DeleteShard(stmt.ID)
+		return err
+	}
+	return nil
 }
 
 func (e *StatementExecutor) executeDropRetentionPolicyStatement(stmt *influxql.DropRetentionPolicyStatement) error {
@@ -567,7 +1037,10 @@ func (e *StatementExecutor) executeSetPasswordUserStatement(q *influxql.SetPassw
 
 func (e *StatementExecutor) executeSelectStatement(stmt *influxql.SelectStatement, ctx *query.ExecutionContext) error {
 
-	cur, err := e.createIterators(ctx, stmt, ctx.ExecutionOptions)
+	// Record the points that shard replicas disagree on when reading several
+	// owners of each shard.
+	var divergence replicaDivergence
+	cur, err := e.createIterators(newContextWithReplicaDivergence(ctx, &divergence), stmt, ctx.ExecutionOptions)
 	if err != nil {
 		return err
 	}
@@ -633,6 +1106,9 @@ func (e *StatementExecutor) executeSelectStatement(stmt *influxql.SelectStatemen
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
+		if msg := divergence.Message(); msg != nil {
+			messages = append(messages, msg)
+		}
 
 		return ctx.Send(&query.Result{
 			Messages: messages,
@@ -644,11 +1120,19 @@ func (e *StatementExecutor) executeSelectStatement(stmt *influxql.SelectStatemen
 		})
 	}
 
+	var messages []*query.Message
+	if msg := divergence.Message(); msg != nil {
+		messages = append(messages, msg)
+	}
+
 	// Always emit at least one result.
 	if !emitted {
 		return ctx.Send(&query.Result{
-			Series: make([]*models.Row, 0),
+			Series:   make([]*models.Row, 0),
+			Messages: messages,
 		})
+	} else if len(messages) > 0 {
+		return ctx.Send(&query.Result{Messages: messages})
 	}
 
 	return nil
@@ -661,6 +1145,8 @@ func (e *StatementExecutor) createIterators(ctx context.Context, stmt *influxql.
 		MaxPointN:   e.MaxSelectPointN,
 		MaxBucketsN: e.MaxSelectBucketsN,
 		Authorizer:  opt.Authorizer,
+
+		ReadConsistency: opt.ReadConsistency,
 	}
 
 	// Create a set of iterators from a selection.
@@ -685,6 +1171,42 @@ func (e *StatementExecutor) executeShowContinuousQueriesStatement(stmt *influxql
 	return rows, nil
 }
 
+func (e *StatementExecutor) executeShowContinuousQueryStatusStatement(stmt *influxql.ShowContinuousQueryStatusStatement) (models.Rows, error) {
+	dis, _ := e.MetaClient.Databases()
+
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	rows := []*models.Row{}
+	for _, di := range dis {
+		row := &models.Row{Columns: []string{"name", "last_run", "last_success_start", "last_success_end", "duration", "points_written", "last_error", "last_error_time", "node"}, Name: di.Name}
+		for _, cqi := range di.ContinuousQueries {
+			// Queries that never executed have no outcome to show.
+			if cqi.LastNodeID == 0 {
+				row.Values = append(row.Values, []interface{}{cqi.Name, formatTime(cqi.LastRun), "", "", nil, nil, "", "", nil})
+				continue
+			}
+			row.Values = append(row.Values, []interface{}{
+				cqi.Name,
+				formatTime(cqi.LastRun),
+				formatTime(cqi.LastSuccessStart),
+				formatTime(cqi.LastSuccessEnd),
+				cqi.LastDuration.String(),
+				cqi.LastPointsWritten,
+				cqi.LastError,
+				formatTime(cqi.LastErrorTime),
+				cqi.LastNodeID,
+			})
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
 func (e *StatementExecutor) executeShowDatabasesStatement(q *influxql.ShowDatabasesStatement, ctx *query.ExecutionContext) (models.Rows, error) {
 	dis, _ := e.MetaClient.Databases()
 	a := ctx.ExecutionOptions.Authorizer
@@ -699,6 +1221,47 @@ func (e *StatementExecutor) executeShowDatabasesStatement(q *influxql.ShowDataba
 	return []*models.Row{row}, nil
 }
 
+func (e *StatementExecutor) executeShowDroppedStatement(stmt *influxql.ShowDroppedStatement) (models.Rows, error) {
+	row := &models.Row{Columns: []string{"type", "database", "retention_policy", "name", "dropped_at"}}
+	for _, di := range e.MetaClient.Dropped() {
+		name := di.Name
+		switch di.Type {
+		case meta.DroppedDatabase:
+			name = di.Database
+		case meta.DroppedShard:
+			name = strconv.FormatUint(di.ShardID, 10)
+		}
+		row.Values = append(row.Values, []interface{}{
+			di.Type,
+			di.Database,
+			di.RetentionPolicy,
+			name,
+			di.DroppedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return []*models.Row{row}, nil
+}
+
+func (e *StatementExecutor) executeShowDownsamplesStatement(stmt *influxql.ShowDownsamplesStatement) (models.Rows, error) {
+	dis, _ := e.MetaClient.Databases()
+
+	rows := []*models.Row{}
+	for _, di := range dis {
+		row := &models.Row{Columns: []string{"source", "target", "every", "aggregates", "last_run"}, Name: di.Name}
+		for _, rpi := range di.RetentionPolicies {
+			for _, ds := range rpi.Downsamples {
+				var lastRun string
+				if !ds.LastRun.IsZero() {
+					lastRun = ds.LastRun.Format(time.RFC3339)
+				}
+				row.Values = append(row.Values, []interface{}{rpi.Name, ds.Target, influxql.FormatDuration(ds.Interval), strings.Join(ds.Aggregates, ","), lastRun})
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
 func (e *StatementExecutor) executeShowDiagnosticsStatement(stmt *influxql.ShowDiagnosticsStatement) (models.Rows, error) {
 	diags, err := e.Monitor.Diagnostics()
 	if err != nil {
@@ -746,6 +1309,19 @@ func (e *StatementExecutor) executeShowMeasurementsStatement(q *influxql.ShowMea
 	}
 
 	names, err := e.TSDBStore.MeasurementNames(ctx.Authorizer, q.Database, q.Condition)
+	if err == nil {
+		// Add the measurements only stored on other nodes.
+		all := [][][]byte{names}
+		for _, nodeID := range e.remoteDatabaseOwners(q.Database) {
+			a, rerr := e.remoteStore(nodeID).MeasurementNames(q.Database, q.Condition)
+			if rerr != nil {
+				err = fmt.Errorf("node %d: %s", nodeID, rerr)
+				break
+			}
+			all = append(all, a)
+		}
+		names = mergeMeasurementNames(all...)
+	}
 	if err != nil || len(names) == 0 {
 		return ctx.Send(&query.Result{
 			Err: err,
@@ -810,9 +1386,24 @@ func (e *StatementExecutor) executeShowRetentionPoliciesStatement(q *influxql.Sh
 		return nil, freetsdb.ErrDatabaseNotFound(q.Database)
 	}
 
-	row := &models.Row{Columns: []string{"name", "duration", "shardGroupDuration", "replicaN", "default"}}
+	row := &models.Row{Columns: []string{"name", "duration", "shardGroupDuration", "replicaN", "default", "consistency", "coldAfter", "duplicates", "stringCodec", "floatCodec"}}
 	for _, rpi := range di.RetentionPolicies {
-		row.Values = append(row.Values, []interface{}{rpi.Name, rpi.Duration.String(), rpi.ShardGroupDuration.String(), rpi.ReplicaN, di.DefaultRetentionPolicy == rpi.Name})
+		consistency := rpi.Consistency
+		if consistency == "" {
+			consistency = di.Consistency
+		}
+		duplicates := rpi.DuplicatePolicy
+		if duplicates == "" {
+			duplicates = "last"
+		}
+		stringCodec, floatCodec := rpi.StringCodec, rpi.FloatCodec
+		if stringCodec == "" {
+			stringCodec = "snappy"
+		}
+		if floatCodec == "" {
+			floatCodec = "gorilla"
+		}
+		row.Values = append(row.Values, []interface{}{rpi.Name, rpi.Duration.String(), rpi.ShardGroupDuration.String(), rpi.ReplicaN, di.DefaultRetentionPolicy == rpi.Name, consistency, rpi.ColdAfter.String(), duplicates, stringCodec, floatCodec})
 	}
 	return []*models.Row{row}, nil
 }
@@ -823,10 +1414,14 @@ func (e *StatementExecutor) executeShowServersStatement(q *influxql.ShowServersS
 		return nil, err
 	}
 
-	dataNodes := &models.Row{Columns: []string{"id", "http_addr", "tcp_addr"}}
+	dataNodes := &models.Row{Columns: []string{"id", "http_addr", "tcp_addr", "status", "last_seen"}}
 	dataNodes.Name = "data_nodes"
 	for _, ni := range nis {
-		dataNodes.Values = append(dataNodes.Values, []interface{}{ni.ID, ni.Host, ni.TCPHost})
+		var lastSeen interface{}
+		if !ni.LastSeen.IsZero() {
+			lastSeen = ni.LastSeen.UTC().Format(time.RFC3339)
+		}
+		dataNodes.Values = append(dataNodes.Values, []interface{}{ni.ID, ni.Host, ni.TCPHost, ni.Status, lastSeen})
 	}
 
 	nis, err = e.MetaClient.MetaNodes()
@@ -843,12 +1438,143 @@ func (e *StatementExecutor) executeShowServersStatement(q *influxql.ShowServersS
 	return []*models.Row{dataNodes, metaNodes}, nil
 }
 
+func (e *StatementExecutor) executeShowCompactionsStatement(stmt *influxql.ShowCompactionsStatement) (models.Rows, error) {
+	row := &models.Row{Columns: []string{"node", "shard_id", "database", "retention_policy", "level", "strategy", "files", "bytes_in", "bytes_out", "progress", "started_at", "error"}}
+
+	nis, err := e.MetaClient.DataNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Without other data nodes only the local compactions are listed.
+	var localID uint64
+	if e.Node != nil {
+		localID = e.Node.ID
+	}
+	if len(nis) == 0 {
+		nis = []meta.NodeInfo{{ID: localID}}
+	}
+
+	for _, ni := range nis {
+		var compactions []tsdb.CompactionInfo
+		var err error
+		if ni.ID == localID {
+			compactions = e.TSDBStore.Compactions()
+		} else if ni.Down() {
+			err = errors.New("node is down")
+		} else {
+			compactions, err = e.remoteStore(ni.ID).Compactions()
+		}
+
+		if err != nil {
+			row.Values = append(row.Values, []interface{}{ni.ID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err.Error()})
+			continue
+		}
+
+		for _, c := range compactions {
+			row.Values = append(row.Values, []interface{}{
+				ni.ID,
+				c.ShardID,
+				c.Database,
+				c.RetentionPolicy,
+				c.Level,
+				c.Strategy,
+				c.Files,
+				c.BytesIn,
+				c.BytesOut,
+				c.Progress,
+				c.Started.Format(time.RFC3339),
+				"",
+			})
+		}
+	}
+	return []*models.Row{row}, nil
+}
+
+func (e *StatementExecutor) executeShowRebalanceStatement(stmt *influxql.ShowRebalanceStatement) (models.Rows, error) {
+	row := &models.Row{Columns: []string{"shard_id", "database", "retention_policy", "action", "source", "destination", "state", "started_at", "error"}}
+	if e.Rebalancer == nil {
+		return []*models.Row{row}, nil
+	}
+
+	for _, j := range e.Rebalancer.Jobs() {
+		var startedAt, errMsg string
+		if !j.StartedAt.IsZero() {
+			startedAt = j.StartedAt.Format(time.RFC3339)
+		}
+		if j.Err != nil {
+			errMsg = j.Err.Error()
+		}
+		row.Values = append(row.Values, []interface{}{
+			j.ShardID,
+			j.Database,
+			j.RetentionPolicy,
+			j.Action.String(),
+			j.Source,
+			j.Destination,
+			j.State.String(),
+			startedAt,
+			errMsg,
+		})
+	}
+	return []*models.Row{row}, nil
+}
+
+func (e *StatementExecutor) executeShowHintedHandoffStatement(stmt *influxql.ShowHintedHandoffStatement) (models.Rows, error) {
+	row := &models.Row{Columns: []string{"node", "destination", "active", "bytes", "writes", "points", "max_size", "oldest", "last_modified", "error"}}
+
+	nis, err := e.MetaClient.DataNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ni := range nis {
+		var status []hh.NodeStatus
+		var err error
+		if e.Node != nil && ni.ID == e.Node.ID && e.HintedHandoff != nil {
+			status, err = e.HintedHandoff.Status()
+		} else if ni.Down() {
+			err = errors.New("node is down")
+		} else {
+			status, err = hh.NewClient(ni.TCPHost, e.Dialer).Status()
+		}
+
+		if err != nil {
+			row.Values = append(row.Values, []interface{}{ni.ID, nil, nil, nil, nil, nil, nil, nil, nil, err.Error()})
+			continue
+		}
+
+		for _, st := range status {
+			var oldest, lastModified string
+			if !st.Oldest.IsZero() {
+				oldest = st.Oldest.Format(time.RFC3339)
+			}
+			if !st.LastModified.IsZero() {
+				lastModified = st.LastModified.Format(time.RFC3339)
+			}
+			row.Values = append(row.Values, []interface{}{
+				ni.ID,
+				st.NodeID,
+				st.Active,
+				st.Bytes,
+				st.Writes,
+				st.Points,
+				st.MaxSize,
+				oldest,
+				lastModified,
+				"",
+			})
+		}
+	}
+	return []*models.Row{row}, nil
+}
+
 func (e *StatementExecutor) executeShowShardsStatement(stmt *influxql.ShowShardsStatement) (models.Rows, error) {
 	dis, _ := e.MetaClient.Databases()
 
 	rows := []*models.Row{}
 	for _, di := range dis {
-		row := &models.Row{Columns: []string{"id", "database", "retention_policy", "shard_group", "start_time", "end_time", "expiry_time", "owners"}, Name: di.Name}
+		row := &models.Row{Columns: []string{"id", "database", "retention_policy", "shard_group", "start_time", "end_time", "expiry_time", "owners", "tiers"}, Name: di.Name}
 		for _, rpi := range di.RetentionPolicies {
 			for _, sgi := range rpi.ShardGroups {
 				// Shards associated with deleted shard groups are effectively deleted.
@@ -859,8 +1585,16 @@ func (e *StatementExecutor) executeShowShardsStatement(stmt *influxql.ShowShards
 
 				for _, si := range sgi.Shards {
 					ownerIDs := make([]uint64, len(si.Owners))
+					tiers := make([]string, len(si.Owners))
 					for i, owner := range si.Owners {
 						ownerIDs[i] = owner.NodeID
+						tiers[i] = owner.Tier
+						if tiers[i] == "" {
+							tiers[i] = tsdb.ShardTierHot
+						}
+					}
+					if si.Archived() {
+						tiers = []string{tsdb.ShardTierArchived}
 					}
 
 					row.Values = append(row.Values, []interface{}{
@@ -872,6 +1606,7 @@ func (e *StatementExecutor) executeShowShardsStatement(stmt *influxql.ShowShards
 						sgi.EndTime.UTC().Format(time.RFC3339),
 						sgi.EndTime.Add(rpi.Duration).UTC().Format(time.RFC3339),
 						joinUint64(ownerIDs),
+						strings.Join(tiers, ","),
 					})
 				}
 			}
@@ -886,9 +1621,32 @@ func (e *StatementExecutor) executeShowSeriesCardinalityStatement(stmt *influxql
 		return nil, ErrDatabaseNameRequired
 	}
 
-	n, err := e.TSDBStore.SeriesCardinality(stmt.Database)
-	if err != nil {
-		return nil, err
+	var n int64
+	if nodeIDs := e.remoteDatabaseOwners(stmt.Database); len(nodeIDs) == 0 {
+		// The exact count is only possible when every shard is local, since
+		// series IDs are not comparable across nodes.
+		c, err := e.TSDBStore.SeriesCardinality(stmt.Database)
+		if err != nil {
+			return nil, err
+		}
+		n = c
+	} else {
+		ss, ts, err := e.TSDBStore.SeriesSketches(stmt.Database)
+		if err != nil {
+			return nil, err
+		}
+		for _, nodeID := range nodeIDs {
+			rs, rts, err := e.remoteStore(nodeID).SeriesSketches(stmt.Database)
+			if err != nil {
+				return nil, fmt.Errorf("node %d: %s", nodeID, err)
+			}
+			if err := ss.Merge(rs); err != nil {
+				return nil, err
+			} else if err := ts.Merge(rts); err != nil {
+				return nil, err
+			}
+		}
+		n = int64(ss.Count() - ts.Count())
 	}
 
 	return []*models.Row{&models.Row{
@@ -900,13 +1658,17 @@ func (e *StatementExecutor) executeShowSeriesCardinalityStatement(stmt *influxql
 func (e *StatementExecutor) executeShowShardGroupsStatement(stmt *influxql.ShowShardGroupsStatement) (models.Rows, error) {
 	dis, _ := e.MetaClient.Databases()
 
-	row := &models.Row{Columns: []string{"id", "database", "retention_policy", "start_time", "end_time", "expiry_time"}, Name: "shard groups"}
+	now := time.Now().UTC()
+	row := &models.Row{Columns: []string{"id", "database", "retention_policy", "start_time", "end_time", "expiry_time", "purge_time"}, Name: "shard groups"}
 	for _, di := range dis {
 		for _, rpi := range di.RetentionPolicies {
 			for _, sgi := range rpi.ShardGroups {
 				// Shards associated with deleted shard groups are effectively deleted.
-				// Don't list them.
-				if sgi.Deleted() {
+				// Don't list them, unless they can still be recovered.
+				var purgeTime string
+				if sgi.PendingDeletion(now) {
+					purgeTime = sgi.PurgeAt.UTC().Format(time.RFC3339)
+				} else if sgi.Deleted() {
 					continue
 				}
 
@@ -917,6 +1679,7 @@ func (e *StatementExecutor) executeShowShardGroupsStatement(stmt *influxql.ShowS
 					sgi.StartTime.UTC().Format(time.RFC3339),
 					sgi.EndTime.UTC().Format(time.RFC3339),
 					sgi.EndTime.Add(rpi.Duration).UTC().Format(time.RFC3339),
+					purgeTime,
 				})
 			}
 		}
@@ -1011,14 +1774,21 @@ func (e *StatementExecutor) executeShowTagKeys(q *influxql.ShowTagKeysStatement,
 		allGroups = append(allGroups, sgis...)
 	}
 
-	var shardIDs []uint64
-	for _, sgi := range allGroups {
-		for _, si := range sgi.Shards {
-			shardIDs = append(shardIDs, si.ID)
-		}
-	}
+	shardIDs, remote := e.mapShardOwners(allGroups)
 
 	tagKeys, err := e.TSDBStore.TagKeys(ctx.Authorizer, shardIDs, cond)
+	if err == nil && len(remote) > 0 {
+		all := [][]tsdb.TagKeys{tagKeys}
+		for nodeID, ids := range remote {
+			a, rerr := e.remoteStore(nodeID).TagKeys(ids, cond)
+			if rerr != nil {
+				err = fmt.Errorf("node %d: %s", nodeID, rerr)
+				break
+			}
+			all = append(all, a)
+		}
+		tagKeys = mergeTagKeys(all...)
+	}
 	if err != nil {
 		return ctx.Send(&query.Result{
 			Err: err,
@@ -1098,14 +1868,21 @@ func (e *StatementExecutor) executeShowTagValues(q *influxql.ShowTagValuesStatem
 		allGroups = append(allGroups, sgis...)
 	}
 
-	var shardIDs []uint64
-	for _, sgi := range allGroups {
-		for _, si := range sgi.Shards {
-			shardIDs = append(shardIDs, si.ID)
-		}
-	}
+	shardIDs, remote := e.mapShardOwners(allGroups)
 
 	tagValues, err := e.TSDBStore.TagValues(ctx.Authorizer, shardIDs, cond)
+	if err == nil && len(remote) > 0 {
+		all := [][]tsdb.TagValues{tagValues}
+		for nodeID, ids := range remote {
+			a, rerr := e.remoteStore(nodeID).TagValues(ids, cond)
+			if rerr != nil {
+				err = fmt.Errorf("node %d: %s", nodeID, rerr)
+				break
+			}
+			all = append(all, a)
+		}
+		tagValues = mergeTagValues(all...)
+	}
 	if err != nil {
 		return ctx.Send(&query.Result{Err: err})
 	}
@@ -1156,6 +1933,71 @@ func (e *StatementExecutor) executeShowTagValues(q *influxql.ShowTagValuesStatem
 	return nil
 }
 
+// mapShardOwners splits the shards in groups between the local node and the
+// remote nodes that will be asked for their metadata. Shards owned by the
+// local node are always read locally, the others from a random owner that
+// is not down.
+func (e *StatementExecutor) mapShardOwners(groups []meta.ShardGroupInfo) (local []uint64, remote map[uint64][]uint64) {
+	remote = make(map[uint64][]uint64)
+	for _, sgi := range groups {
+		for _, si := range sgi.Shards {
+			if e.Node == nil || si.OwnedBy(e.Node.ID) || len(si.Owners) == 0 {
+				local = append(local, si.ID)
+				continue
+			}
+			nodeID := shardOwnerIDs(e.MetaClient, si)[0]
+			remote[nodeID] = append(remote[nodeID], si.ID)
+		}
+	}
+	return local, remote
+}
+
+// remoteDatabaseOwners returns the IDs of the remote nodes owning at least
+// one shard of database. Nodes that are down are skipped.
+func (e *StatementExecutor) remoteDatabaseOwners(database string) []uint64 {
+	if e.Node == nil {
+		return nil
+	}
+
+	di := e.MetaClient.Database(database)
+	if di == nil {
+		return nil
+	}
+
+	seen := make(map[uint64]struct{})
+	var ids []uint64
+	for _, rpi := range di.RetentionPolicies {
+		for _, sgi := range rpi.ShardGroups {
+			if sgi.Deleted() {
+				continue
+			}
+			for _, si := range sgi.Shards {
+				for _, owner := range si.Owners {
+					if _, ok := seen[owner.NodeID]; ok || owner.NodeID == e.Node.ID {
+						continue
+					}
+					seen[owner.NodeID] = struct{}{}
+					if ni, err := e.MetaClient.DataNode(owner.NodeID); err == nil && ni.Down() {
+						continue
+					}
+					ids = append(ids, owner.NodeID)
+				}
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// remoteStore returns a store for the metadata held by a remote node.
+func (e *StatementExecutor) remoteStore(nodeID uint64) *remoteTSDBStore {
+	return newRemoteTSDBStore(&NodeDialer{
+		MetaClient: e.MetaClient,
+		Dialer:     e.Dialer,
+		Timeout:    remoteStoreTimeout,
+	}, nodeID)
+}
+
 func (e *StatementExecutor) executeShowUsersStatement(q *influxql.ShowUsersStatement) (models.Rows, error) {
 	row := &models.Row{Columns: []string{"user", "admin"}}
 	for _, ui := range e.MetaClient.Users() {
@@ -1423,14 +2265,29 @@ type TSDBStore interface {
 	DeleteSeries(database string, sources []influxql.Source, condition influxql.Expr) error
 	DeleteShard(id uint64) error
 
+	ConvertFieldType(database, measurement, field string, typ influxql.DataType) error
+
+	CompactShard(id uint64, optimize bool) error
+	Compactions() []tsdb.CompactionInfo
+	SetCompactionsPaused(database string, paused bool)
+
+	TrashDatabase(name string) error
+	TrashMeasurement(database, name string) error
+	TrashShard(id uint64) error
+	UndropDatabase(name string) error
+	UndropMeasurement(database, name string) error
+	UndropShard(id uint64) error
+
 	MeasurementNames(auth query.Authorizer, database string, cond influxql.Expr) ([][]byte, error)
 	TagKeys(auth query.Authorizer, shardIDs []uint64, cond influxql.Expr) ([]tsdb.TagKeys, error)
 	TagValues(auth query.Authorizer, shardIDs []uint64, cond influxql.Expr) ([]tsdb.TagValues, error)
 
 	SeriesCardinality(database string) (int64, error)
+	SeriesSketches(database string) (estimator.Sketch, estimator.Sketch, error)
 	MeasurementsCardinality(database string) (int64, error)
 
 	ShardGroup(ids []uint64) tsdb.ShardGroup
+	Shards(ids []uint64) []*tsdb.Shard
 }
 
 var _ TSDBStore = LocalTSDBStore{}
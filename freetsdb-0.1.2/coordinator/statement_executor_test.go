@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/freetsdb/freetsdb"
 	"github.com/freetsdb/freetsdb/coordinator"
 	"github.com/freetsdb/freetsdb/services/influxql"
 	"github.com/freetsdb/freetsdb/internal"
@@ -326,7 +327,7 @@ func TestStatementExecutor_NormalizeDropSeries(t *testing.T) {
 	stmt := q.Statements[0].(*influxql.DropSeriesStatement)
 
 	s := &coordinator.StatementExecutor{
-		MetaClient: &internal.MetaClientMock{
+		MetaClient: &MetaClient{
 			DatabaseFn: func(name string) *meta.DatabaseInfo {
 				t.Fatal("meta client should not be called")
 				return nil
@@ -359,7 +360,7 @@ func TestStatementExecutor_NormalizeDeleteSeries(t *testing.T) {
 	stmt := q.Statements[0].(*influxql.DeleteSeriesStatement)
 
 	s := &coordinator.StatementExecutor{
-		MetaClient: &internal.MetaClientMock{
+		MetaClient: &MetaClient{
 			DatabaseFn: func(name string) *meta.DatabaseInfo {
 				t.Fatal("meta client should not be called")
 				return nil
@@ -406,11 +407,11 @@ func (m *mockAuthorizer) AuthorizeSeriesWrite(database string, measurement []byt
 func TestQueryExecutor_ExecuteQuery_ShowDatabases(t *testing.T) {
 	qe := query.NewExecutor()
 	qe.StatementExecutor = &coordinator.StatementExecutor{
-		MetaClient: &internal.MetaClientMock{
-			DatabasesFn: func() []meta.DatabaseInfo {
+		MetaClient: &MetaClient{
+			DatabasesFn: func() ([]meta.DatabaseInfo, error) {
 				return []meta.DatabaseInfo{
 					{Name: "db1"}, {Name: "db2"}, {Name: "db3"}, {Name: "db4"},
-				}
+				}, nil
 			},
 		},
 	}
@@ -446,6 +447,133 @@ func TestQueryExecutor_ExecuteQuery_ShowDatabases(t *testing.T) {
 	}
 }
 
+// Ensure SHOW COMPACTIONS lists the compactions of every data node.
+func TestQueryExecutor_ExecuteQuery_ShowCompactions(t *testing.T) {
+	started := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := MustOpenService()
+	defer s.Close()
+	s.TSDBStore.CompactionsFn = func() []tsdb.CompactionInfo {
+		return []tsdb.CompactionInfo{{ShardID: 2, Database: "db0", RetentionPolicy: "rp0", Level: 4, Strategy: "full", Files: 3, BytesIn: 300, BytesOut: 100, Progress: 0.5, Started: started}}
+	}
+
+	e := NewQueryExecutor()
+	e.TSDBStore.CompactionsFn = func() []tsdb.CompactionInfo {
+		return []tsdb.CompactionInfo{{ShardID: 1, Database: "db0", RetentionPolicy: "rp0", Level: 1, Strategy: "level", Files: 8, BytesIn: 800, BytesOut: 0, Progress: 0, Started: started}}
+	}
+	e.MetaClient.DataNodesFn = func() ([]meta.NodeInfo, error) {
+		return []meta.NodeInfo{{ID: 0}, {ID: 1, TCPHost: s.Addr().String()}, {ID: 2, Status: meta.NodeStatusDown}}, nil
+	}
+	e.MetaClient.DataNodeFn = func(id uint64) (*meta.NodeInfo, error) {
+		return &meta.NodeInfo{ID: id, TCPHost: s.Addr().String()}, nil
+	}
+
+	res := <-e.ExecuteQuery(`SHOW COMPACTIONS`, "", 0)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	exp := []*models.Row{{
+		Columns: []string{"node", "shard_id", "database", "retention_policy", "level", "strategy", "files", "bytes_in", "bytes_out", "progress", "started_at", "error"},
+		Values: [][]interface{}{
+			{uint64(0), uint64(1), "db0", "rp0", 1, "level", 8, int64(800), int64(0), float64(0), "2020-01-01T00:00:00Z", ""},
+			{uint64(1), uint64(2), "db0", "rp0", 4, "full", 3, int64(300), int64(100), 0.5, "2020-01-01T00:00:00Z", ""},
+			{uint64(2), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, "node is down"},
+		},
+	}}
+	if !reflect.DeepEqual(res.Series, models.Rows(exp)) {
+		t.Fatalf("unexpected rows: %s", spew.Sdump(res.Series))
+	}
+}
+
+// Ensure a database is dropped from the meta store even if a remote node
+// fails to delete its files, so the statement can be re-run.
+func TestQueryExecutor_ExecuteQuery_DropDatabase_RemoteError(t *testing.T) {
+	e := DefaultQueryExecutor()
+
+	var dropped, deleted bool
+	e.MetaClient.TrashEnabledFn = func() bool { return false }
+	e.MetaClient.DropDatabaseFn = func(name string) error {
+		if deleted {
+			t.Fatal("files deleted before the meta store was updated")
+		}
+		dropped = true
+		return nil
+	}
+	e.TSDBStore.DeleteDatabaseFn = func(name string) error {
+		deleted = true
+		return nil
+	}
+	e.StatementExecutor.MetaExecutor = metaExecutorFunc(func(stmt influxql.Statement, database string) error {
+		return errors.New("node 2 down")
+	})
+
+	res := <-e.ExecuteQuery(`DROP DATABASE db0`, "", 0)
+	if res.Err == nil || res.Err.Error() != "node 2 down" {
+		t.Fatalf("unexpected error: %v", res.Err)
+	} else if !dropped || !deleted {
+		t.Fatalf("unexpected drop: meta=%v files=%v", dropped, deleted)
+	}
+}
+
+// Ensure an UNDROP DATABASE that failed on a remote node can be retried
+// once the database is restored in the meta store.
+func TestQueryExecutor_ExecuteQuery_UndropDatabase_Retry(t *testing.T) {
+	e := DefaultQueryExecutor()
+
+	var remote int
+	e.MetaClient.DatabaseFn = func(name string) *meta.DatabaseInfo {
+		if name != DefaultDatabase {
+			return nil
+		}
+		return DefaultMetaClientDatabaseFn(name)
+	}
+	e.MetaClient.UndropDatabaseFn = func(name string) error {
+		return meta.ErrDroppedNotFound
+	}
+	e.TSDBStore.UndropDatabaseFn = func(name string) error {
+		return tsdb.ErrTrashEntryNotFound
+	}
+	e.StatementExecutor.MetaExecutor = metaExecutorFunc(func(stmt influxql.Statement, database string) error {
+		remote++
+		return nil
+	})
+
+	if res := <-e.ExecuteQuery(`UNDROP DATABASE db0`, "", 0); res.Err != nil {
+		t.Fatal(res.Err)
+	} else if remote != 1 {
+		t.Fatalf("unexpected remote executions: %d", remote)
+	}
+
+	// A database that was never dropped is still reported.
+	if res := <-e.ExecuteQuery(`UNDROP DATABASE db1`, "", 0); res.Err != meta.ErrDroppedNotFound {
+		t.Fatalf("unexpected error: %v", res.Err)
+	}
+}
+
+// Ensure ALTER FIELD TYPE converts the local and the remote shards
+// concurrently and waits for both.
+func TestQueryExecutor_ExecuteQuery_AlterFieldType(t *testing.T) {
+	e := DefaultQueryExecutor()
+
+	local, remote := make(chan struct{}), make(chan struct{})
+	e.TSDBStore.ConvertFieldTypeFn = func(database, measurement, field string, typ influxql.DataType) error {
+		close(local)
+		<-remote
+		return nil
+	}
+	e.StatementExecutor.MetaExecutor = metaExecutorFunc(func(stmt influxql.Statement, database string) error {
+		close(remote)
+		<-local
+		return errors.New("field value out of range")
+	})
+
+	res := <-e.ExecuteQuery(`ALTER MEASUREMENT cpu ALTER FIELD value TYPE float`, "db0", 0)
+	if res.Err == nil || res.Err.Error() != "field value out of range" {
+		t.Fatalf("unexpected error: %v", res.Err)
+	}
+}
+
 // QueryExecutor is a test wrapper for coordinator.QueryExecutor.
 type QueryExecutor struct {
 	*query.Executor
@@ -468,6 +596,10 @@ func NewQueryExecutor() *QueryExecutor {
 		return nil
 	}
 
+	e.TSDBStore.ShardsFn = func(ids []uint64) []*tsdb.Shard {
+		return make([]*tsdb.Shard, len(ids))
+	}
+
 	e.TSDBStore.MeasurementNamesFn = func(auth query.Authorizer, database string, cond influxql.Expr) ([][]byte, error) {
 		return nil, nil
 	}
@@ -477,6 +609,7 @@ func NewQueryExecutor() *QueryExecutor {
 	}
 
 	e.StatementExecutor = &coordinator.StatementExecutor{
+		Node:       &freetsdb.Node{ID: 0},
 		MetaClient: &e.MetaClient,
 		TSDBStore:  e.TSDBStore,
 		ShardMapper: &coordinator.LocalShardMapper{
@@ -563,6 +696,10 @@ func (sh *MockShard) ExpandSources(sources influxql.Sources) (influxql.Sources,
 	return sh.ExpandSourcesFn(sources)
 }
 
+func (sh *MockShard) GetShards() tsdb.Shards {
+	return nil
+}
+
 // MustParseQuery parses s into a query. Panic on error.
 func MustParseQuery(s string) *influxql.Query {
 	q, err := influxql.ParseQuery(s)
@@ -609,6 +746,12 @@ func ts(s string) time.Time {
 	return t
 }
 
+type metaExecutorFunc func(stmt influxql.Statement, database string) error
+
+func (fn metaExecutorFunc) ExecuteStatement(stmt influxql.Statement, database string) error {
+	return fn(stmt, database)
+}
+
 type writePointsIntoFunc func(req *coordinator.IntoWriteRequest) error
 
 func (fn writePointsIntoFunc) WritePointsInto(req *coordinator.IntoWriteRequest) error {
@@ -23,17 +23,22 @@ type MetaClientMock struct {
 
 	DataFn                func() meta.Data
 	DeleteShardGroupFn    func(database string, policy string, id uint64) error
+	ExpireShardGroupFn    func(database string, policy string, id uint64, purgeAt time.Time) error
 	DropContinuousQueryFn func(database, name string) error
 	DropDatabaseFn        func(name string) error
 	DropRetentionPolicyFn func(database, name string) error
 	DropSubscriptionFn    func(database, rp, name string) error
 	DropShardFn           func(id uint64) error
 	DropUserFn            func(name string) error
+	DroppedFn             func() []meta.DroppedInfo
+
+	NodeIDFn func() uint64
 
 	OpenFn func() error
 
 	PrecreateShardGroupsFn func(from, to time.Time) error
 	PruneShardGroupsFn     func() error
+	PruneDroppedFn         func(t time.Time) error
 
 	RetentionPolicyFn func(database, name string) (rpi *meta.RetentionPolicyInfo, err error)
 
@@ -42,9 +47,12 @@ type MetaClientMock struct {
 	SetAdminPrivilegeFn      func(username string, admin bool) error
 	SetDataFn                func(*meta.Data) error
 	SetPrivilegeFn           func(username, database string, p influxql.Privilege) error
+	SetShardOwnerTierFn      func(shardID, nodeID uint64, tier string) error
 	ShardGroupsByTimeRangeFn func(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error)
 	ShardOwnerFn             func(shardID uint64) (database, policy string, sgi *meta.ShardGroupInfo)
 	TruncateShardGroupsFn    func(t time.Time) error
+	UnarchiveShardFn         func(shardID, nodeID uint64) error
+	UndropShardGroupFn       func(id uint64) error
 	UpdateRetentionPolicyFn  func(database, name string, rpu *meta.RetentionPolicyUpdate, makeDefault bool) error
 	UpdateUserFn             func(name, password string) error
 	UserPrivilegeFn          func(username, database string) (*influxql.Privilege, error)
@@ -97,6 +105,10 @@ func (c *MetaClientMock) DeleteShardGroup(database string, policy string, id uin
 	return c.DeleteShardGroupFn(database, policy, id)
 }
 
+func (c *MetaClientMock) ExpireShardGroup(database string, policy string, id uint64, purgeAt time.Time) error {
+	return c.ExpireShardGroupFn(database, policy, id, purgeAt)
+}
+
 func (c *MetaClientMock) DropContinuousQuery(database, name string) error {
 	return c.DropContinuousQueryFn(database, name)
 }
@@ -129,6 +141,14 @@ func (c *MetaClientMock) SetAdminPrivilege(username string, admin bool) error {
 	return c.SetAdminPrivilegeFn(username, admin)
 }
 
+func (c *MetaClientMock) NodeID() uint64 {
+	return c.NodeIDFn()
+}
+
+func (c *MetaClientMock) SetShardOwnerTier(shardID, nodeID uint64, tier string) error {
+	return c.SetShardOwnerTierFn(shardID, nodeID, tier)
+}
+
 func (c *MetaClientMock) SetPrivilege(username, database string, p influxql.Privilege) error {
 	return c.SetPrivilegeFn(username, database, p)
 }
@@ -145,6 +165,14 @@ func (c *MetaClientMock) TruncateShardGroups(t time.Time) error {
 	return c.TruncateShardGroupsFn(t)
 }
 
+func (c *MetaClientMock) UnarchiveShard(shardID, nodeID uint64) error {
+	return c.UnarchiveShardFn(shardID, nodeID)
+}
+
+func (c *MetaClientMock) UndropShardGroup(id uint64) error {
+	return c.UndropShardGroupFn(id)
+}
+
 func (c *MetaClientMock) UpdateRetentionPolicy(database, name string, rpu *meta.RetentionPolicyUpdate, makeDefault bool) error {
 	return c.UpdateRetentionPolicyFn(database, name, rpu, makeDefault)
 }
@@ -177,3 +205,6 @@ func (c *MetaClientMock) PrecreateShardGroups(from, to time.Time) error {
 	return c.PrecreateShardGroupsFn(from, to)
 }
 func (c *MetaClientMock) PruneShardGroups() error { return c.PruneShardGroupsFn() }
+
+func (c *MetaClientMock) Dropped() []meta.DroppedInfo    { return c.DroppedFn() }
+func (c *MetaClientMock) PruneDropped(t time.Time) error { return c.PruneDroppedFn(t) }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/freetsdb/freetsdb/models"
+	"github.com/freetsdb/freetsdb/pkg/estimator"
 	"github.com/freetsdb/freetsdb/query"
 	"github.com/freetsdb/freetsdb/tsdb"
 	"github.com/freetsdb/freetsdb/services/influxql"
@@ -15,8 +16,13 @@ import (
 type TSDBStoreMock struct {
 	BackupShardFn             func(id uint64, since time.Time, w io.Writer) error
 	BackupSeriesFileFn        func(database string, w io.Writer) error
+	BackupShardIncrementalFn  func(id uint64, from *tsdb.BackupPosition, w io.Writer) error
 	ExportShardFn             func(id uint64, ExportStart time.Time, ExportEnd time.Time, w io.Writer) error
 	CloseFn                   func() error
+	ConvertFieldTypeFn        func(database, measurement, field string, typ influxql.DataType) error
+	ColdPathFn                func() string
+	CompactShardFn            func(id uint64, optimize bool) error
+	CompactionsFn             func() []tsdb.CompactionInfo
 	CreateShardFn             func(database, policy string, shardID uint64, enabled bool) error
 	CreateShardSnapshotFn     func(id uint64) (string, error)
 	DatabasesFn               func() []string
@@ -31,20 +37,31 @@ type TSDBStoreMock struct {
 	MeasurementSeriesCountsFn func(database string) (measuments int, series int)
 	MeasurementsCardinalityFn func(database string) (int64, error)
 	MeasurementNamesFn        func(auth query.Authorizer, database string, cond influxql.Expr) ([][]byte, error)
+	MoveShardToColdFn         func(id uint64) error
 	OpenFn                    func() error
 	PathFn                    func() string
+	PurgeTrashFn              func(before time.Time) error
 	RestoreShardFn            func(id uint64, r io.Reader) error
 	SeriesCardinalityFn       func(database string) (int64, error)
+	SeriesSketchesFn          func(database string) (estimator.Sketch, estimator.Sketch, error)
+	SetCompactionsPausedFn    func(database string, paused bool)
 	SetShardEnabledFn         func(shardID uint64, enabled bool) error
 	ShardFn                   func(id uint64) *tsdb.Shard
 	ShardGroupFn              func(ids []uint64) tsdb.ShardGroup
 	ShardIDsFn                func() []uint64
 	ShardNFn                  func() int
 	ShardRelativePathFn       func(id uint64) (string, error)
+	ShardTierFn               func(id uint64) string
 	ShardsFn                  func(ids []uint64) []*tsdb.Shard
 	StatisticsFn              func(tags map[string]string) []models.Statistic
 	TagKeysFn                 func(auth query.Authorizer, shardIDs []uint64, cond influxql.Expr) ([]tsdb.TagKeys, error)
 	TagValuesFn               func(auth query.Authorizer, shardIDs []uint64, cond influxql.Expr) ([]tsdb.TagValues, error)
+	TrashDatabaseFn           func(name string) error
+	TrashMeasurementFn        func(database, name string) error
+	TrashShardFn              func(id uint64) error
+	UndropDatabaseFn          func(name string) error
+	UndropMeasurementFn       func(database, name string) error
+	UndropShardFn             func(id uint64) error
 	WithLoggerFn              func(log *zap.Logger)
 	WriteToShardFn            func(shardID uint64, points []models.Point) error
 }
@@ -55,10 +72,25 @@ func (s *TSDBStoreMock) BackupShard(id uint64, since time.Time, w io.Writer) err
 func (s *TSDBStoreMock) BackupSeriesFile(database string, w io.Writer) error {
 	return s.BackupSeriesFileFn(database, w)
 }
+func (s *TSDBStoreMock) BackupShardIncremental(id uint64, from *tsdb.BackupPosition, w io.Writer) error {
+	return s.BackupShardIncrementalFn(id, from, w)
+}
 func (s *TSDBStoreMock) ExportShard(id uint64, ExportStart time.Time, ExportEnd time.Time, w io.Writer) error {
 	return s.ExportShardFn(id, ExportStart, ExportEnd, w)
 }
 func (s *TSDBStoreMock) Close() error { return s.CloseFn() }
+func (s *TSDBStoreMock) ColdPath() string {
+	return s.ColdPathFn()
+}
+func (s *TSDBStoreMock) CompactShard(id uint64, optimize bool) error {
+	return s.CompactShardFn(id, optimize)
+}
+func (s *TSDBStoreMock) Compactions() []tsdb.CompactionInfo {
+	return s.CompactionsFn()
+}
+func (s *TSDBStoreMock) ConvertFieldType(database, measurement, field string, typ influxql.DataType) error {
+	return s.ConvertFieldTypeFn(database, measurement, field, typ)
+}
 func (s *TSDBStoreMock) CreateShard(database string, retentionPolicy string, shardID uint64, enabled bool) error {
 	return s.CreateShardFn(database, retentionPolicy, shardID, enabled)
 }
@@ -101,18 +133,30 @@ func (s *TSDBStoreMock) MeasurementSeriesCounts(database string) (measuments int
 func (s *TSDBStoreMock) MeasurementsCardinality(database string) (int64, error) {
 	return s.MeasurementsCardinalityFn(database)
 }
+func (s *TSDBStoreMock) MoveShardToCold(id uint64) error {
+	return s.MoveShardToColdFn(id)
+}
 func (s *TSDBStoreMock) Open() error {
 	return s.OpenFn()
 }
 func (s *TSDBStoreMock) Path() string {
 	return s.PathFn()
 }
+func (s *TSDBStoreMock) PurgeTrash(before time.Time) error {
+	return s.PurgeTrashFn(before)
+}
 func (s *TSDBStoreMock) RestoreShard(id uint64, r io.Reader) error {
 	return s.RestoreShardFn(id, r)
 }
 func (s *TSDBStoreMock) SeriesCardinality(database string) (int64, error) {
 	return s.SeriesCardinalityFn(database)
 }
+func (s *TSDBStoreMock) SeriesSketches(database string) (estimator.Sketch, estimator.Sketch, error) {
+	return s.SeriesSketchesFn(database)
+}
+func (s *TSDBStoreMock) SetCompactionsPaused(database string, paused bool) {
+	s.SetCompactionsPausedFn(database, paused)
+}
 func (s *TSDBStoreMock) SetShardEnabled(shardID uint64, enabled bool) error {
 	return s.SetShardEnabledFn(shardID, enabled)
 }
@@ -131,6 +175,9 @@ func (s *TSDBStoreMock) ShardN() int {
 func (s *TSDBStoreMock) ShardRelativePath(id uint64) (string, error) {
 	return s.ShardRelativePathFn(id)
 }
+func (s *TSDBStoreMock) ShardTier(id uint64) string {
+	return s.ShardTierFn(id)
+}
 func (s *TSDBStoreMock) Shards(ids []uint64) []*tsdb.Shard {
 	return s.ShardsFn(ids)
 }
@@ -143,6 +190,24 @@ func (s *TSDBStoreMock) TagKeys(auth query.Authorizer, shardIDs []uint64, cond i
 func (s *TSDBStoreMock) TagValues(auth query.Authorizer, shardIDs []uint64, cond influxql.Expr) ([]tsdb.TagValues, error) {
 	return s.TagValuesFn(auth, shardIDs, cond)
 }
+func (s *TSDBStoreMock) TrashDatabase(name string) error {
+	return s.TrashDatabaseFn(name)
+}
+func (s *TSDBStoreMock) TrashMeasurement(database, name string) error {
+	return s.TrashMeasurementFn(database, name)
+}
+func (s *TSDBStoreMock) TrashShard(id uint64) error {
+	return s.TrashShardFn(id)
+}
+func (s *TSDBStoreMock) UndropDatabase(name string) error {
+	return s.UndropDatabaseFn(name)
+}
+func (s *TSDBStoreMock) UndropMeasurement(database, name string) error {
+	return s.UndropMeasurementFn(database, name)
+}
+func (s *TSDBStoreMock) UndropShard(id uint64) error {
+	return s.UndropShardFn(id)
+}
 func (s *TSDBStoreMock) WithLogger(log *zap.Logger) {
 	s.WithLoggerFn(log)
 }
@@ -138,6 +138,9 @@ type ExecutionOptions struct {
 	// Quiet suppresses non-essential output from the query executor.
 	Quiet bool
 
+	// ReadConsistency is the number of owners read for each shard.
+	ReadConsistency ReadConsistency
+
 	// AbortCh is a channel that signals when results are no longer desired by the caller.
 	AbortCh <-chan struct{}
 }
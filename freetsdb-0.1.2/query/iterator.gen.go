@@ -491,6 +491,156 @@ type floatSortedMergeHeapItem struct {
 	itr   FloatIterator
 }
 
+// floatReplicaMergeIterator merges sorted iterators reading replicas of
+// the same data. For each series and timestamp, it returns the points of the
+// replica that returned the most points.
+type floatReplicaMergeIterator struct {
+	inputs     []*bufFloatIterator
+	opt        IteratorOptions
+	seriesTags int
+	missing    int
+	diverged   func()
+	buf        []*FloatPoint
+}
+
+// newFloatReplicaMergeIterator returns an instance of floatReplicaMergeIterator.
+// The missing replicas are treated as having returned no points.
+func newFloatReplicaMergeIterator(inputs []FloatIterator, opt IteratorOptions, seriesTags, missing int, diverged func()) *floatReplicaMergeIterator {
+	itr := &floatReplicaMergeIterator{
+		inputs:     make([]*bufFloatIterator, len(inputs)),
+		opt:        opt,
+		seriesTags: seriesTags,
+		missing:    missing,
+		diverged:   diverged,
+	}
+	for i, input := range inputs {
+		itr.inputs[i] = newBufFloatIterator(input)
+	}
+	return itr
+}
+
+// Stats returns an aggregation of stats from the underlying iterators.
+func (itr *floatReplicaMergeIterator) Stats() IteratorStats {
+	var stats IteratorStats
+	for _, input := range itr.inputs {
+		stats.Add(input.Stats())
+	}
+	return stats
+}
+
+// Close closes the underlying iterators.
+func (itr *floatReplicaMergeIterator) Close() error {
+	for _, input := range itr.inputs {
+		input.Close()
+	}
+	return nil
+}
+
+// Next returns the next point from the iterator.
+func (itr *floatReplicaMergeIterator) Next() (*FloatPoint, error) {
+	if len(itr.buf) > 0 {
+		p := itr.buf[0]
+		itr.buf = itr.buf[1:]
+		return p, nil
+	}
+
+	// Find the first series and timestamp across the replicas.
+	var key replicaKey
+	var found bool
+	for _, input := range itr.inputs {
+		p, err := input.peek()
+		if err != nil {
+			return nil, err
+		} else if p == nil {
+			continue
+		}
+		if k := newReplicaKey(p.Name, p.Tags, p.Time, itr.opt); !found || k.less(key, itr.opt.Ascending) {
+			key, found = k, true
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	// Read the points each replica has for the key, split them by series and
+	// keep the largest set of each series. Series only some replicas returned
+	// are still included.
+	var series []string
+	best := make(map[string][]*FloatPoint)
+	diverged := itr.missing > 0
+	for i, input := range itr.inputs {
+		var ids []string
+		points := make(map[string][]*FloatPoint)
+		for {
+			p, err := input.Next()
+			if err != nil {
+				return nil, err
+			} else if p == nil {
+				break
+			} else if !key.equals(newReplicaKey(p.Name, p.Tags, p.Time, itr.opt)) {
+				input.unread(p)
+				break
+			}
+
+			id := replicaSeriesID(p.Aux, itr.seriesTags)
+			if _, ok := points[id]; !ok {
+				ids = append(ids, id)
+			}
+			points[id] = append(points[id], p.Clone())
+		}
+
+		if i > 0 && len(ids) != len(best) {
+			diverged = true
+		}
+		for _, id := range ids {
+			prev, ok := best[id]
+			if !ok {
+				series = append(series, id)
+			}
+			if i > 0 && (!ok || !floatPointsEqual(points[id], prev)) {
+				diverged = true
+			}
+			if !ok || len(points[id]) > len(prev) {
+				best[id] = points[id]
+			}
+		}
+	}
+
+	if diverged && itr.diverged != nil {
+		itr.diverged()
+	}
+	for _, id := range series {
+		for _, p := range best[id] {
+			p.Aux = trimReplicaSeriesTags(p.Aux, itr.seriesTags)
+			itr.buf = append(itr.buf, p)
+		}
+	}
+	p := itr.buf[0]
+	itr.buf = itr.buf[1:]
+	return p, nil
+}
+
+// floatPointsEqual returns true if a and b hold the same points in any order.
+func floatPointsEqual(a, b []*FloatPoint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	used := make([]bool, len(b))
+OUTER:
+	for _, p := range a {
+		for i, other := range b {
+			if used[i] || p.Nil != other.Nil || (!p.Nil && p.Value != other.Value) || !auxEqual(p.Aux, other.Aux) {
+				continue
+			}
+			used[i] = true
+			continue OUTER
+		}
+		return false
+	}
+	return true
+}
+
 // floatIteratorScanner scans the results of a FloatIterator into a map.
 type floatIteratorScanner struct {
 	input        *bufFloatIterator
@@ -3115,6 +3265,156 @@ type integerSortedMergeHeapItem struct {
 	itr   IntegerIterator
 }
 
+// integerReplicaMergeIterator merges sorted iterators reading replicas of
+// the same data. For each series and timestamp, it returns the points of the
+// replica that returned the most points.
+type integerReplicaMergeIterator struct {
+	inputs     []*bufIntegerIterator
+	opt        IteratorOptions
+	seriesTags int
+	missing    int
+	diverged   func()
+	buf        []*IntegerPoint
+}
+
+// newIntegerReplicaMergeIterator returns an instance of integerReplicaMergeIterator.
+// The missing replicas are treated as having returned no points.
+func newIntegerReplicaMergeIterator(inputs []IntegerIterator, opt IteratorOptions, seriesTags, missing int, diverged func()) *integerReplicaMergeIterator {
+	itr := &integerReplicaMergeIterator{
+		inputs:     make([]*bufIntegerIterator, len(inputs)),
+		opt:        opt,
+		seriesTags: seriesTags,
+		missing:    missing,
+		diverged:   diverged,
+	}
+	for i, input := range inputs {
+		itr.inputs[i] = newBufIntegerIterator(input)
+	}
+	return itr
+}
+
+// Stats returns an aggregation of stats from the underlying iterators.
+func (itr *integerReplicaMergeIterator) Stats() IteratorStats {
+	var stats IteratorStats
+	for _, input := range itr.inputs {
+		stats.Add(input.Stats())
+	}
+	return stats
+}
+
+// Close closes the underlying iterators.
+func (itr *integerReplicaMergeIterator) Close() error {
+	for _, input := range itr.inputs {
+		input.Close()
+	}
+	return nil
+}
+
+// Next returns the next point from the iterator.
+func (itr *integerReplicaMergeIterator) Next() (*IntegerPoint, error) {
+	if len(itr.buf) > 0 {
+		p := itr.buf[0]
+		itr.buf = itr.buf[1:]
+		return p, nil
+	}
+
+	// Find the first series and timestamp across the replicas.
+	var key replicaKey
+	var found bool
+	for _, input := range itr.inputs {
+		p, err := input.peek()
+		if err != nil {
+			return nil, err
+		} else if p == nil {
+			continue
+		}
+		if k := newReplicaKey(p.Name, p.Tags, p.Time, itr.opt); !found || k.less(key, itr.opt.Ascending) {
+			key, found = k, true
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	// Read the points each replica has for the key, split them by series and
+	// keep the largest set of each series. Series only some replicas returned
+	// are still included.
+	var series []string
+	best := make(map[string][]*IntegerPoint)
+	diverged := itr.missing > 0
+	for i, input := range itr.inputs {
+		var ids []string
+		points := make(map[string][]*IntegerPoint)
+		for {
+			p, err := input.Next()
+			if err != nil {
+				return nil, err
+			} else if p == nil {
+				break
+			} else if !key.equals(newReplicaKey(p.Name, p.Tags, p.Time, itr.opt)) {
+				input.unread(p)
+				break
+			}
+
+			id := replicaSeriesID(p.Aux, itr.seriesTags)
+			if _, ok := points[id]; !ok {
+				ids = append(ids, id)
+			}
+			points[id] = append(points[id], p.Clone())
+		}
+
+		if i > 0 && len(ids) != len(best) {
+			diverged = true
+		}
+		for _, id := range ids {
+			prev, ok := best[id]
+			if !ok {
+				series = append(series, id)
+			}
+			if i > 0 && (!ok || !integerPointsEqual(points[id], prev)) {
+				diverged = true
+			}
+			if !ok || len(points[id]) > len(prev) {
+				best[id] = points[id]
+			}
+		}
+	}
+
+	if diverged && itr.diverged != nil {
+		itr.diverged()
+	}
+	for _, id := range series {
+		for _, p := range best[id] {
+			p.Aux = trimReplicaSeriesTags(p.Aux, itr.seriesTags)
+			itr.buf = append(itr.buf, p)
+		}
+	}
+	p := itr.buf[0]
+	itr.buf = itr.buf[1:]
+	return p, nil
+}
+
+// integerPointsEqual returns true if a and b hold the same points in any order.
+func integerPointsEqual(a, b []*IntegerPoint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	used := make([]bool, len(b))
+OUTER:
+	for _, p := range a {
+		for i, other := range b {
+			if used[i] || p.Nil != other.Nil || (!p.Nil && p.Value != other.Value) || !auxEqual(p.Aux, other.Aux) {
+				continue
+			}
+			used[i] = true
+			continue OUTER
+		}
+		return false
+	}
+	return true
+}
+
 // integerIteratorScanner scans the results of a IntegerIterator into a map.
 type integerIteratorScanner struct {
 	input        *bufIntegerIterator
@@ -5739,6 +6039,156 @@ type unsignedSortedMergeHeapItem struct {
 	itr   UnsignedIterator
 }
 
+// unsignedReplicaMergeIterator merges sorted iterators reading replicas of
+// the same data. For each series and timestamp, it returns the points of the
+// replica that returned the most points.
+type unsignedReplicaMergeIterator struct {
+	inputs     []*bufUnsignedIterator
+	opt        IteratorOptions
+	seriesTags int
+	missing    int
+	diverged   func()
+	buf        []*UnsignedPoint
+}
+
+// newUnsignedReplicaMergeIterator returns an instance of unsignedReplicaMergeIterator.
+// The missing replicas are treated as having returned no points.
+func newUnsignedReplicaMergeIterator(inputs []UnsignedIterator, opt IteratorOptions, seriesTags, missing int, diverged func()) *unsignedReplicaMergeIterator {
+	itr := &unsignedReplicaMergeIterator{
+		inputs:     make([]*bufUnsignedIterator, len(inputs)),
+		opt:        opt,
+		seriesTags: seriesTags,
+		missing:    missing,
+		diverged:   diverged,
+	}
+	for i, input := range inputs {
+		itr.inputs[i] = newBufUnsignedIterator(input)
+	}
+	return itr
+}
+
+// Stats returns an aggregation of stats from the underlying iterators.
+func (itr *unsignedReplicaMergeIterator) Stats() IteratorStats {
+	var stats IteratorStats
+	for _, input := range itr.inputs {
+		stats.Add(input.Stats())
+	}
+	return stats
+}
+
+// Close closes the underlying iterators.
+func (itr *unsignedReplicaMergeIterator) Close() error {
+	for _, input := range itr.inputs {
+		input.Close()
+	}
+	return nil
+}
+
+// Next returns the next point from the iterator.
+func (itr *unsignedReplicaMergeIterator) Next() (*UnsignedPoint, error) {
+	if len(itr.buf) > 0 {
+		p := itr.buf[0]
+		itr.buf = itr.buf[1:]
+		return p, nil
+	}
+
+	// Find the first series and timestamp across the replicas.
+	var key replicaKey
+	var found bool
+	for _, input := range itr.inputs {
+		p, err := input.peek()
+		if err != nil {
+			return nil, err
+		} else if p == nil {
+			continue
+		}
+		if k := newReplicaKey(p.Name, p.Tags, p.Time, itr.opt); !found || k.less(key, itr.opt.Ascending) {
+			key, found = k, true
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	// Read the points each replica has for the key, split them by series and
+	// keep the largest set of each series. Series only some replicas returned
+	// are still included.
+	var series []string
+	best := make(map[string][]*UnsignedPoint)
+	diverged := itr.missing > 0
+	for i, input := range itr.inputs {
+		var ids []string
+		points := make(map[string][]*UnsignedPoint)
+		for {
+			p, err := input.Next()
+			if err != nil {
+				return nil, err
+			} else if p == nil {
+				break
+			} else if !key.equals(newReplicaKey(p.Name, p.Tags, p.Time, itr.opt)) {
+				input.unread(p)
+				break
+			}
+
+			id := replicaSeriesID(p.Aux, itr.seriesTags)
+			if _, ok := points[id]; !ok {
+				ids = append(ids, id)
+			}
+			points[id] = append(points[id], p.Clone())
+		}
+
+		if i > 0 && len(ids) != len(best) {
+			diverged = true
+		}
+		for _, id := range ids {
+			prev, ok := best[id]
+			if !ok {
+				series = append(series, id)
+			}
+			if i > 0 && (!ok || !unsignedPointsEqual(points[id], prev)) {
+				diverged = true
+			}
+			if !ok || len(points[id]) > len(prev) {
+				best[id] = points[id]
+			}
+		}
+	}
+
+	if diverged && itr.diverged != nil {
+		itr.diverged()
+	}
+	for _, id := range series {
+		for _, p := range best[id] {
+			p.Aux = trimReplicaSeriesTags(p.Aux, itr.seriesTags)
+			itr.buf = append(itr.buf, p)
+		}
+	}
+	p := itr.buf[0]
+	itr.buf = itr.buf[1:]
+	return p, nil
+}
+
+// unsignedPointsEqual returns true if a and b hold the same points in any order.
+func unsignedPointsEqual(a, b []*UnsignedPoint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	used := make([]bool, len(b))
+OUTER:
+	for _, p := range a {
+		for i, other := range b {
+			if used[i] || p.Nil != other.Nil || (!p.Nil && p.Value != other.Value) || !auxEqual(p.Aux, other.Aux) {
+				continue
+			}
+			used[i] = true
+			continue OUTER
+		}
+		return false
+	}
+	return true
+}
+
 // unsignedIteratorScanner scans the results of a UnsignedIterator into a map.
 type unsignedIteratorScanner struct {
 	input        *bufUnsignedIterator
@@ -8363,6 +8813,156 @@ type stringSortedMergeHeapItem struct {
 	itr   StringIterator
 }
 
+// stringReplicaMergeIterator merges sorted iterators reading replicas of
+// the same data. For each series and timestamp, it returns the points of the
+// replica that returned the most points.
+type stringReplicaMergeIterator struct {
+	inputs     []*bufStringIterator
+	opt        IteratorOptions
+	seriesTags int
+	missing    int
+	diverged   func()
+	buf        []*StringPoint
+}
+
+// newStringReplicaMergeIterator returns an instance of stringReplicaMergeIterator.
+// The missing replicas are treated as having returned no points.
+func newStringReplicaMergeIterator(inputs []StringIterator, opt IteratorOptions, seriesTags, missing int, diverged func()) *stringReplicaMergeIterator {
+	itr := &stringReplicaMergeIterator{
+		inputs:     make([]*bufStringIterator, len(inputs)),
+		opt:        opt,
+		seriesTags: seriesTags,
+		missing:    missing,
+		diverged:   diverged,
+	}
+	for i, input := range inputs {
+		itr.inputs[i] = newBufStringIterator(input)
+	}
+	return itr
+}
+
+// Stats returns an aggregation of stats from the underlying iterators.
+func (itr *stringReplicaMergeIterator) Stats() IteratorStats {
+	var stats IteratorStats
+	for _, input := range itr.inputs {
+		stats.Add(input.Stats())
+	}
+	return stats
+}
+
+// Close closes the underlying iterators.
+func (itr *stringReplicaMergeIterator) Close() error {
+	for _, input := range itr.inputs {
+		input.Close()
+	}
+	return nil
+}
+
+// Next returns the next point from the iterator.
+func (itr *stringReplicaMergeIterator) Next() (*StringPoint, error) {
+	if len(itr.buf) > 0 {
+		p := itr.buf[0]
+		itr.buf = itr.buf[1:]
+		return p, nil
+	}
+
+	// Find the first series and timestamp across the replicas.
+	var key replicaKey
+	var found bool
+	for _, input := range itr.inputs {
+		p, err := input.peek()
+		if err != nil {
+			return nil, err
+		} else if p == nil {
+			continue
+		}
+		if k := newReplicaKey(p.Name, p.Tags, p.Time, itr.opt); !found || k.less(key, itr.opt.Ascending) {
+			key, found = k, true
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	// Read the points each replica has for the key, split them by series and
+	// keep the largest set of each series. Series only some replicas returned
+	// are still included.
+	var series []string
+	best := make(map[string][]*StringPoint)
+	diverged := itr.missing > 0
+	for i, input := range itr.inputs {
+		var ids []string
+		points := make(map[string][]*StringPoint)
+		for {
+			p, err := input.Next()
+			if err != nil {
+				return nil, err
+			} else if p == nil {
+				break
+			} else if !key.equals(newReplicaKey(p.Name, p.Tags, p.Time, itr.opt)) {
+				input.unread(p)
+				break
+			}
+
+			id := replicaSeriesID(p.Aux, itr.seriesTags)
+			if _, ok := points[id]; !ok {
+				ids = append(ids, id)
+			}
+			points[id] = append(points[id], p.Clone())
+		}
+
+		if i > 0 && len(ids) != len(best) {
+			diverged = true
+		}
+		for _, id := range ids {
+			prev, ok := best[id]
+			if !ok {
+				series = append(series, id)
+			}
+			if i > 0 && (!ok || !stringPointsEqual(points[id], prev)) {
+				diverged = true
+			}
+			if !ok || len(points[id]) > len(prev) {
+				best[id] = points[id]
+			}
+		}
+	}
+
+	if diverged && itr.diverged != nil {
+		itr.diverged()
+	}
+	for _, id := range series {
+		for _, p := range best[id] {
+			p.Aux = trimReplicaSeriesTags(p.Aux, itr.seriesTags)
+			itr.buf = append(itr.buf, p)
+		}
+	}
+	p := itr.buf[0]
+	itr.buf = itr.buf[1:]
+	return p, nil
+}
+
+// stringPointsEqual returns true if a and b hold the same points in any order.
+func stringPointsEqual(a, b []*StringPoint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	used := make([]bool, len(b))
+OUTER:
+	for _, p := range a {
+		for i, other := range b {
+			if used[i] || p.Nil != other.Nil || (!p.Nil && p.Value != other.Value) || !auxEqual(p.Aux, other.Aux) {
+				continue
+			}
+			used[i] = true
+			continue OUTER
+		}
+		return false
+	}
+	return true
+}
+
 // stringIteratorScanner scans the results of a StringIterator into a map.
 type stringIteratorScanner struct {
 	input        *bufStringIterator
@@ -10973,6 +11573,156 @@ type booleanSortedMergeHeapItem struct {
 	itr   BooleanIterator
 }
 
+// booleanReplicaMergeIterator merges sorted iterators reading replicas of
+// the same data. For each series and timestamp, it returns the points of the
+// replica that returned the most points.
+type booleanReplicaMergeIterator struct {
+	inputs     []*bufBooleanIterator
+	opt        IteratorOptions
+	seriesTags int
+	missing    int
+	diverged   func()
+	buf        []*BooleanPoint
+}
+
+// newBooleanReplicaMergeIterator returns an instance of booleanReplicaMergeIterator.
+// The missing replicas are treated as having returned no points.
+func newBooleanReplicaMergeIterator(inputs []BooleanIterator, opt IteratorOptions, seriesTags, missing int, diverged func()) *booleanReplicaMergeIterator {
+	itr := &booleanReplicaMergeIterator{
+		inputs:     make([]*bufBooleanIterator, len(inputs)),
+		opt:        opt,
+		seriesTags: seriesTags,
+		missing:    missing,
+		diverged:   diverged,
+	}
+	for i, input := range inputs {
+		itr.inputs[i] = newBufBooleanIterator(input)
+	}
+	return itr
+}
+
+// Stats returns an aggregation of stats from the underlying iterators.
+func (itr *booleanReplicaMergeIterator) Stats() IteratorStats {
+	var stats IteratorStats
+	for _, input := range itr.inputs {
+		stats.Add(input.Stats())
+	}
+	return stats
+}
+
+// Close closes the underlying iterators.
+func (itr *booleanReplicaMergeIterator) Close() error {
+	for _, input := range itr.inputs {
+		input.Close()
+	}
+	return nil
+}
+
+// Next returns the next point from the iterator.
+func (itr *booleanReplicaMergeIterator) Next() (*BooleanPoint, error) {
+	if len(itr.buf) > 0 {
+		p := itr.buf[0]
+		itr.buf = itr.buf[1:]
+		return p, nil
+	}
+
+	// Find the first series and timestamp across the replicas.
+	var key replicaKey
+	var found bool
+	for _, input := range itr.inputs {
+		p, err := input.peek()
+		if err != nil {
+			return nil, err
+		} else if p == nil {
+			continue
+		}
+		if k := newReplicaKey(p.Name, p.Tags, p.Time, itr.opt); !found || k.less(key, itr.opt.Ascending) {
+			key, found = k, true
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	// Read the points each replica has for the key, split them by series and
+	// keep the largest set of each series. Series only some replicas returned
+	// are still included.
+	var series []string
+	best := make(map[string][]*BooleanPoint)
+	diverged := itr.missing > 0
+	for i, input := range itr.inputs {
+		var ids []string
+		points := make(map[string][]*BooleanPoint)
+		for {
+			p, err := input.Next()
+			if err != nil {
+				return nil, err
+			} else if p == nil {
+				break
+			} else if !key.equals(newReplicaKey(p.Name, p.Tags, p.Time, itr.opt)) {
+				input.unread(p)
+				break
+			}
+
+			id := replicaSeriesID(p.Aux, itr.seriesTags)
+			if _, ok := points[id]; !ok {
+				ids = append(ids, id)
+			}
+			points[id] = append(points[id], p.Clone())
+		}
+
+		if i > 0 && len(ids) != len(best) {
+			diverged = true
+		}
+		for _, id := range ids {
+			prev, ok := best[id]
+			if !ok {
+				series = append(series, id)
+			}
+			if i > 0 && (!ok || !booleanPointsEqual(points[id], prev)) {
+				diverged = true
+			}
+			if !ok || len(points[id]) > len(prev) {
+				best[id] = points[id]
+			}
+		}
+	}
+
+	if diverged && itr.diverged != nil {
+		itr.diverged()
+	}
+	for _, id := range series {
+		for _, p := range best[id] {
+			p.Aux = trimReplicaSeriesTags(p.Aux, itr.seriesTags)
+			itr.buf = append(itr.buf, p)
+		}
+	}
+	p := itr.buf[0]
+	itr.buf = itr.buf[1:]
+	return p, nil
+}
+
+// booleanPointsEqual returns true if a and b hold the same points in any order.
+func booleanPointsEqual(a, b []*BooleanPoint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	used := make([]bool, len(b))
+OUTER:
+	for _, p := range a {
+		for i, other := range b {
+			if used[i] || p.Nil != other.Nil || (!p.Nil && p.Value != other.Value) || !auxEqual(p.Aux, other.Aux) {
+				continue
+			}
+			used[i] = true
+			continue OUTER
+		}
+		return false
+	}
+	return true
+}
+
 // booleanIteratorScanner scans the results of a BooleanIterator into a map.
 type booleanIteratorScanner struct {
 	input        *bufBooleanIterator
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"errors"
@@ -241,6 +242,129 @@ func NewSortedMergeIterator(inputs []Iterator, opt IteratorOptions) Iterator {
 	}
 }
 
+// NewReplicaMergeIterator returns an iterator that merges sorted iterators
+// reading replicas of the same data. For each series and timestamp, the points
+// of the replica returning the most points are used and diverged is called if
+// the replicas did not all return the same points. A nil input represents a
+// replica that returned no data.
+//
+// The last seriesTags auxiliary fields of each point hold the tags that tell
+// its series apart from others with the same dimensions, as added by
+// WithSeriesTags. They are removed from the points returned.
+func NewReplicaMergeIterator(inputs []Iterator, opt IteratorOptions, seriesTags int, diverged func()) Iterator {
+	n := len(inputs)
+	inputs = Iterators(inputs).filterNonNil()
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	switch inputs := Iterators(inputs).coerce().(type) {
+	case []FloatIterator:
+		return newFloatReplicaMergeIterator(inputs, opt, seriesTags, n-len(inputs), diverged)
+	case []IntegerIterator:
+		return newIntegerReplicaMergeIterator(inputs, opt, seriesTags, n-len(inputs), diverged)
+	case []UnsignedIterator:
+		return newUnsignedReplicaMergeIterator(inputs, opt, seriesTags, n-len(inputs), diverged)
+	case []StringIterator:
+		return newStringReplicaMergeIterator(inputs, opt, seriesTags, n-len(inputs), diverged)
+	case []BooleanIterator:
+		return newBooleanReplicaMergeIterator(inputs, opt, seriesTags, n-len(inputs), diverged)
+	default:
+		panic(fmt.Sprintf("unsupported replica merge iterator type: %T", inputs))
+	}
+}
+
+// WithSeriesTags returns a copy of opt that also reads the tags in keys that
+// are not dimensions, as trailing auxiliary fields, and the number of fields
+// added. Without them the points of series that only differ in those tags
+// can't be told apart when merging replicas.
+func WithSeriesTags(opt IteratorOptions, keys []string) (IteratorOptions, int) {
+	dimensions := make(map[string]struct{}, len(opt.Dimensions))
+	for _, d := range opt.Dimensions {
+		dimensions[d] = struct{}{}
+	}
+
+	aux := make([]influxql.VarRef, len(opt.Aux), len(opt.Aux)+len(keys))
+	copy(aux, opt.Aux)
+	for _, k := range keys {
+		if _, ok := dimensions[k]; !ok {
+			aux = append(aux, influxql.VarRef{Val: k, Type: influxql.Tag})
+		}
+	}
+
+	n := len(aux) - len(opt.Aux)
+	opt.Aux = aux
+	return opt, n
+}
+
+// replicaSeriesID returns an identifier for the series of a point from the
+// last n auxiliary fields, which hold its tags.
+func replicaSeriesID(aux []interface{}, n int) string {
+	if n == 0 || len(aux) < n {
+		return ""
+	}
+	var buf bytes.Buffer
+	for _, v := range aux[len(aux)-n:] {
+		fmt.Fprintf(&buf, "%v\x00", v)
+	}
+	return buf.String()
+}
+
+// trimReplicaSeriesTags removes the last n auxiliary fields added by
+// WithSeriesTags.
+func trimReplicaSeriesTags(aux []interface{}, n int) []interface{} {
+	if n == 0 {
+		return aux
+	} else if len(aux) <= n {
+		return nil
+	}
+	return aux[:len(aux)-n]
+}
+
+// replicaKey identifies the points of a series at a timestamp.
+type replicaKey struct {
+	name string
+	tags Tags
+	time int64
+}
+
+// newReplicaKey returns the key of the points grouped by the dimensions of opt
+// at a timestamp. The points of several series can share a key.
+func newReplicaKey(name string, tags Tags, time int64, opt IteratorOptions) replicaKey {
+	return replicaKey{name: name, tags: tags.Subset(opt.Dimensions), time: time}
+}
+
+// less returns true if k sorts before other in the order used by sorted
+// merge iterators.
+func (k replicaKey) less(other replicaKey, ascending bool) bool {
+	if k.name != other.name {
+		return (k.name < other.name) == ascending
+	} else if k.tags.ID() != other.tags.ID() {
+		return (k.tags.ID() < other.tags.ID()) == ascending
+	} else if k.time != other.time {
+		return (k.time < other.time) == ascending
+	}
+	return false
+}
+
+// equals returns true if k and other identify the same points.
+func (k replicaKey) equals(other replicaKey) bool {
+	return k.name == other.name && k.tags.ID() == other.tags.ID() && k.time == other.time
+}
+
+// auxEqual returns true if the auxiliary fields of two points are equal.
+func auxEqual(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // newParallelIterator returns an iterator that runs in a separate goroutine.
 func newParallelIterator(input Iterator) Iterator {
 	if input == nil {
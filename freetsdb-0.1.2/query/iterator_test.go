@@ -624,6 +624,115 @@ func TestSortedMergeIterator_Coerce_Float(t *testing.T) {
 	}
 }
 
+// Ensure that iterators reading replicas are merged without duplicates and
+// that divergent replicas are reported.
+func TestReplicaMergeIterator_Float(t *testing.T) {
+	inputs := []*FloatIterator{
+		{Points: []query.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 0, Value: 1},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 10, Value: 2},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 0, Value: 4},
+		}},
+		{Points: []query.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 0, Value: 1},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 10, Value: 2},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 20, Value: 3},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 0, Value: 4},
+		}},
+	}
+
+	var diverged int
+	itr := query.NewReplicaMergeIterator(FloatIterators(inputs), query.IteratorOptions{
+		Dimensions: []string{"host"},
+		Ascending:  true,
+	}, 0, func() { diverged++ })
+	if a, err := Iterators([]query.Iterator{itr}).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if !deep.Equal(a, [][]query.Point{
+		{&query.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0, Value: 1}},
+		{&query.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 10, Value: 2}},
+		{&query.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 20, Value: 3}},
+		{&query.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 0, Value: 4}},
+	}) {
+		t.Errorf("unexpected points: %s", spew.Sdump(a))
+	}
+
+	if diverged != 1 {
+		t.Errorf("unexpected divergence count: %d", diverged)
+	}
+
+	for i, input := range inputs {
+		if !input.Closed {
+			t.Errorf("iterator %d not closed", i)
+		}
+	}
+}
+
+// Ensure that the points of different series at the same timestamp are merged
+// from every replica when the query doesn't group by the tags telling them apart.
+func TestReplicaMergeIterator_SeriesTags(t *testing.T) {
+	opt, n := query.WithSeriesTags(query.IteratorOptions{
+		Aux:       []influxql.VarRef{{Val: "value", Type: influxql.Float}},
+		Ascending: true,
+	}, []string{"host"})
+	if n != 1 {
+		t.Fatalf("unexpected number of series tags: %d", n)
+	}
+
+	inputs := []*FloatIterator{
+		{Points: []query.FloatPoint{
+			{Name: "cpu", Time: 0, Aux: []interface{}{float64(1), "a"}},
+			{Name: "cpu", Time: 10, Aux: []interface{}{float64(2), "a"}},
+			{Name: "cpu", Time: 10, Aux: []interface{}{float64(3), "b"}},
+		}},
+		{Points: []query.FloatPoint{
+			{Name: "cpu", Time: 0, Aux: []interface{}{float64(4), "b"}},
+			{Name: "cpu", Time: 10, Aux: []interface{}{float64(3), "b"}},
+		}},
+	}
+
+	var diverged int
+	itr := query.NewReplicaMergeIterator(FloatIterators(inputs), opt, n, func() { diverged++ })
+	if a, err := Iterators([]query.Iterator{itr}).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if !deep.Equal(a, [][]query.Point{
+		{&query.FloatPoint{Name: "cpu", Time: 0, Aux: []interface{}{float64(1)}}},
+		{&query.FloatPoint{Name: "cpu", Time: 0, Aux: []interface{}{float64(4)}}},
+		{&query.FloatPoint{Name: "cpu", Time: 10, Aux: []interface{}{float64(2)}}},
+		{&query.FloatPoint{Name: "cpu", Time: 10, Aux: []interface{}{float64(3)}}},
+	}) {
+		t.Errorf("unexpected points: %s", spew.Sdump(a))
+	}
+
+	if diverged != 2 {
+		t.Errorf("unexpected divergence count: %d", diverged)
+	}
+}
+
+// Ensure that a replica without data is reported as divergent.
+func TestReplicaMergeIterator_Missing(t *testing.T) {
+	var diverged int
+	itr := query.NewReplicaMergeIterator([]query.Iterator{
+		&IntegerIterator{Points: []query.IntegerPoint{
+			{Name: "cpu", Time: 0, Value: 1},
+			{Name: "cpu", Time: 10, Value: 2},
+		}},
+		nil,
+	}, query.IteratorOptions{Ascending: true}, 0, func() { diverged++ })
+	if a, err := Iterators([]query.Iterator{itr}).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if !deep.Equal(a, [][]query.Point{
+		{&query.IntegerPoint{Name: "cpu", Time: 0, Value: 1}},
+		{&query.IntegerPoint{Name: "cpu", Time: 10, Value: 2}},
+	}) {
+		t.Errorf("unexpected points: %s", spew.Sdump(a))
+	}
+
+	if diverged != 2 {
+		t.Errorf("unexpected divergence count: %d", diverged)
+	}
+}
+
 // Ensure limit iterators work with limit and offset.
 func TestLimitIterator_Float(t *testing.T) {
 	input := &FloatIterator{Points: []query.FloatPoint{
@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"sort"
@@ -37,6 +38,66 @@ type SelectOptions struct {
 
 	// Maximum number of buckets for a statement.
 	MaxBucketsN int
+
+	// ReadConsistency is the number of owners read for each shard.
+	ReadConsistency ReadConsistency
+}
+
+// ReadConsistency specifies how many owners of a shard are read by a query.
+type ReadConsistency int
+
+const (
+	// ReadConsistencyOne reads a single owner of each shard.
+	ReadConsistencyOne ReadConsistency = iota
+
+	// ReadConsistencyQuorum reads a quorum of the owners of each shard.
+	ReadConsistencyQuorum
+
+	// ReadConsistencyAll reads every owner of each shard.
+	ReadConsistencyAll
+)
+
+// ErrInvalidReadConsistency is returned when parsing the string version of
+// a read consistency.
+var ErrInvalidReadConsistency = errors.New("invalid read consistency")
+
+// ParseReadConsistency converts a read consistency string to the corresponding
+// ReadConsistency const.
+func ParseReadConsistency(s string) (ReadConsistency, error) {
+	switch strings.ToLower(s) {
+	case "one":
+		return ReadConsistencyOne, nil
+	case "quorum":
+		return ReadConsistencyQuorum, nil
+	case "all":
+		return ReadConsistencyAll, nil
+	default:
+		return 0, ErrInvalidReadConsistency
+	}
+}
+
+// String returns the string representation of the read consistency.
+func (c ReadConsistency) String() string {
+	switch c {
+	case ReadConsistencyQuorum:
+		return "quorum"
+	case ReadConsistencyAll:
+		return "all"
+	default:
+		return "one"
+	}
+}
+
+// Owners returns the number of owners that must be read out of n.
+func (c ReadConsistency) Owners(n int) int {
+	switch c {
+	case ReadConsistencyQuorum:
+		return n/2 + 1
+	case ReadConsistencyAll:
+		return n
+	default:
+		return 1
+	}
 }
 
 // ShardMapper retrieves and maps shards into an IteratorCreator that can later be
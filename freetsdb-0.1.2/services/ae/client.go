@@ -0,0 +1,147 @@
+package ae
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/freetsdb/freetsdb/tcp"
+)
+
+// RequestType indicates the type of anti-entropy request.
+type RequestType uint8
+
+const (
+	// RequestShardDigest represents a request for the digest of a shard.
+	RequestShardDigest RequestType = iota
+
+	// RequestShardBlocks represents a request for the blocks of a shard
+	// overlapping a time range.
+	RequestShardBlocks
+
+	// RequestStatus represents a request for the anti-entropy status of
+	// every shard on the node.
+	RequestStatus
+
+	// RequestRepair represents a request to check and repair one or all
+	// shards on the node.
+	RequestRepair
+)
+
+// Request is sent by a Client to the anti-entropy service.
+type Request struct {
+	Type    RequestType
+	ShardID uint64
+	Start   int64
+	End     int64
+}
+
+// Response is sent by the anti-entropy service before any streamed data.
+type Response struct {
+	Error  string
+	Status []ShardStatus
+}
+
+// Client represents a client for the anti-entropy service of a data node.
+type Client struct {
+	host   string
+	dialer *tcp.Dialer
+}
+
+// NewClient returns a new instance of Client connecting with dialer.
+func NewClient(host string, dialer *tcp.Dialer) *Client {
+	return &Client{host: host, dialer: dialer}
+}
+
+// Digest returns the decoded digest of a shard on the remote node.
+func (c *Client) Digest(id uint64) (Digest, error) {
+	conn, _, err := c.exec(&Request{Type: RequestShardDigest, ShardID: id})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return ReadDigest(conn)
+}
+
+// Blocks returns a reader for a tar archive of the remote shard's blocks
+// overlapping tr. Returned ReadCloser must be closed by the caller.
+func (c *Client) Blocks(id uint64, tr TimeRange) (io.ReadCloser, error) {
+	conn, _, err := c.exec(&Request{Type: RequestShardBlocks, ShardID: id, Start: tr.Min, End: tr.Max})
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// Status returns the anti-entropy status of the shards on the remote node.
+func (c *Client) Status() ([]ShardStatus, error) {
+	conn, resp, err := c.exec(&Request{Type: RequestStatus})
+	if err != nil {
+		return nil, err
+	}
+	conn.Close()
+	return resp.Status, nil
+}
+
+// Repair asks the remote node to check and repair a shard, or all of its
+// shards if id is zero. The repair runs in the background.
+func (c *Client) Repair(id uint64) error {
+	conn, _, err := c.exec(&Request{Type: RequestRepair, ShardID: id})
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// exec sends req and reads the response. On success the connection is left
+// open for the caller to read any streamed data and close.
+func (c *Client) exec(req *Request) (net.Conn, *Response, error) {
+	conn, err := c.dialer.Dial("tcp", c.host, MuxHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := writeMessage(conn, req); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("write request: %s", err)
+	}
+
+	var resp Response
+	if err := readMessage(conn, &resp); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("read response: %s", err)
+	} else if resp.Error != "" {
+		conn.Close()
+		return nil, nil, errors.New(resp.Error)
+	}
+	return conn, &resp, nil
+}
+
+// writeMessage writes v to w as length-prefixed JSON.
+func writeMessage(w io.Writer, v interface{}) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(len(buf))); err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// readMessage reads length-prefixed JSON from r into v.
+func readMessage(r io.Reader, v interface{}) error {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, v)
+}
@@ -0,0 +1,67 @@
+package ae
+
+import (
+	"errors"
+	"time"
+
+	"github.com/freetsdb/freetsdb/monitor/diagnostics"
+	"github.com/freetsdb/freetsdb/toml"
+)
+
+const (
+	// DefaultCheckInterval is the anti-entropy check time if none is specified.
+	DefaultCheckInterval = 5 * time.Minute
+
+	// DefaultMaxRangesPerShard is the default number of divergent time ranges
+	// fetched separately before the whole divergent span is fetched at once.
+	DefaultMaxRangesPerShard = 16
+)
+
+// Config represents the configuration for the anti-entropy service.
+// The service always answers requests from other nodes; Enabled only
+// controls the periodic comparison of local shards with their replicas.
+type Config struct {
+	Enabled           bool          `toml:"enabled"`
+	CheckInterval     toml.Duration `toml:"check-interval"`
+	MaxRangesPerShard int           `toml:"max-ranges-per-shard"`
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		Enabled:           false,
+		CheckInterval:     toml.Duration(DefaultCheckInterval),
+		MaxRangesPerShard: DefaultMaxRangesPerShard,
+	}
+}
+
+// Validate returns an error if the Config is invalid.
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.CheckInterval <= 0 {
+		return errors.New("check-interval must be positive")
+	}
+	if c.MaxRangesPerShard <= 0 {
+		return errors.New("max-ranges-per-shard must be positive")
+	}
+
+	return nil
+}
+
+// Diagnostics returns a diagnostics representation of a subset of the Config.
+func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
+	if !c.Enabled {
+		return diagnostics.RowFromMap(map[string]interface{}{
+			"enabled": false,
+		}), nil
+	}
+
+	return diagnostics.RowFromMap(map[string]interface{}{
+		"enabled":              true,
+		"check-interval":       c.CheckInterval,
+		"max-ranges-per-shard": c.MaxRangesPerShard,
+	}), nil
+}
@@ -0,0 +1,55 @@
+package ae_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/freetsdb/freetsdb/services/ae"
+)
+
+func TestConfig_Parse(t *testing.T) {
+	// Parse configuration.
+	var c ae.Config
+	if _, err := toml.Decode(`
+enabled = true
+check-interval = "1m"
+max-ranges-per-shard = 4
+`, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate configuration.
+	if !c.Enabled {
+		t.Fatalf("unexpected enabled state: %v", c.Enabled)
+	} else if time.Duration(c.CheckInterval) != time.Minute {
+		t.Fatalf("unexpected check interval: %s", c.CheckInterval)
+	} else if c.MaxRangesPerShard != 4 {
+		t.Fatalf("unexpected max ranges per shard: %d", c.MaxRangesPerShard)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	c := ae.NewConfig()
+	c.Enabled = true
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation fail from NewConfig: %s", err)
+	}
+
+	c.CheckInterval = 0
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for check-interval = 0, got nil")
+	}
+
+	c = ae.NewConfig()
+	c.Enabled = true
+	c.MaxRangesPerShard = 0
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for max-ranges-per-shard = 0, got nil")
+	}
+
+	c.Enabled = false
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation fail from disabled config: %s", err)
+	}
+}
@@ -0,0 +1,187 @@
+package ae
+
+import (
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/freetsdb/freetsdb/tsdb/engine/tsm1"
+)
+
+// Digest is the decoded contents of a shard digest, keyed by series key.
+type Digest map[string][]tsm1.DigestTimeRange
+
+// ReadDigest decodes a shard digest produced by tsm1.Digest.
+func ReadDigest(r io.Reader) (Digest, error) {
+	dr, err := tsm1.NewDigestReader(ioutil.NopCloser(r))
+	if err != nil {
+		return nil, err
+	}
+	defer dr.Close()
+
+	d := make(Digest)
+	for {
+		key, ts, err := dr.ReadTimeSpan()
+		if err == io.EOF {
+			return d, nil
+		} else if err != nil {
+			return nil, err
+		}
+		d[key] = ts.Ranges
+	}
+}
+
+// TimeRange is an inclusive range of nanosecond timestamps.
+type TimeRange struct {
+	Min, Max int64
+}
+
+// Divergence describes the data present in a remote digest that is missing
+// from a local digest.
+type Divergence struct {
+	// Series is the number of series keys with missing points.
+	Series int
+
+	// Ranges is the merged set of time ranges covering the missing points.
+	Ranges []TimeRange
+}
+
+// Empty returns true if no data is missing.
+func (d Divergence) Empty() bool { return d.Series == 0 }
+
+// Diff returns the time ranges in which remote holds points that local lacks.
+//
+// Blocks are not compared one to one since replicas holding the same points
+// usually have different block layouts after compacting independently.
+// Instead, the blocks of both digests are coalesced per series into windows
+// of overlapping time ranges and the point counts within each window are
+// compared. Overlapping blocks on one side may hold duplicate points until
+// they are compacted, so their counts are only bounds: a window is divergent
+// when the fewest points remote can hold exceeds the most local can hold.
+func Diff(local, remote Digest) Divergence {
+	var div Divergence
+	var ranges []TimeRange
+	for key, rs := range remote {
+		missing := diffSeries(local[key], rs)
+		if len(missing) > 0 {
+			div.Series++
+			ranges = append(ranges, missing...)
+		}
+	}
+
+	div.Ranges = mergeRanges(ranges)
+	return div
+}
+
+// digestBlock is a block of a series in either the local or remote digest.
+type digestBlock struct {
+	tsm1.DigestTimeRange
+	remote bool
+}
+
+// diffSeries returns the windows of a single series in which the remote
+// blocks hold more points than the local blocks.
+func diffSeries(local, remote []tsm1.DigestTimeRange) []TimeRange {
+	blocks := make([]digestBlock, 0, len(local)+len(remote))
+	for _, r := range local {
+		blocks = append(blocks, digestBlock{DigestTimeRange: r})
+	}
+	for _, r := range remote {
+		blocks = append(blocks, digestBlock{DigestTimeRange: r, remote: true})
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Min < blocks[j].Min })
+
+	var missing []TimeRange
+	var w digestWindow
+	for i, b := range blocks {
+		if i > 0 && b.Min > w.Max {
+			if w.divergent() {
+				missing = append(missing, w.TimeRange)
+			}
+			w = digestWindow{}
+		}
+		w.add(b)
+	}
+	if w.divergent() {
+		missing = append(missing, w.TimeRange)
+	}
+	return missing
+}
+
+// digestWindow accumulates the point counts of overlapping blocks.
+type digestWindow struct {
+	TimeRange
+
+	started bool // set once the first block is added
+
+	localN int // sum of local block counts, an upper bound on local points
+
+	remoteN       int   // sum of remote block counts
+	remoteMaxN    int   // largest remote block count
+	remoteMax     int64 // maximum time of remote blocks added so far
+	remoteOverlap bool  // remote blocks overlap and may share points
+	hasRemote     bool
+}
+
+func (w *digestWindow) add(b digestBlock) {
+	if !w.started {
+		w.started = true
+		w.TimeRange = TimeRange{Min: b.Min, Max: b.Max}
+	} else if b.Max > w.Max {
+		w.Max = b.Max
+	}
+
+	if !b.remote {
+		w.localN += b.N
+		return
+	}
+
+	if w.hasRemote && b.Min <= w.remoteMax {
+		w.remoteOverlap = true
+	}
+	if !w.hasRemote || b.Max > w.remoteMax {
+		w.remoteMax = b.Max
+	}
+	w.hasRemote = true
+	w.remoteN += b.N
+	if b.N > w.remoteMaxN {
+		w.remoteMaxN = b.N
+	}
+}
+
+// divergent returns true if remote must hold points that local does not.
+func (w *digestWindow) divergent() bool {
+	if !w.hasRemote {
+		return false
+	}
+
+	// Overlapping remote blocks hold at least as many points as the
+	// largest of them.
+	n := w.remoteN
+	if w.remoteOverlap {
+		n = w.remoteMaxN
+	}
+	return n > w.localN
+}
+
+// mergeRanges sorts a and merges overlapping or adjacent ranges.
+func mergeRanges(a []TimeRange) []TimeRange {
+	if len(a) == 0 {
+		return nil
+	}
+
+	sort.Slice(a, func(i, j int) bool { return a[i].Min < a[j].Min })
+
+	merged := []TimeRange{a[0]}
+	for _, r := range a[1:] {
+		last := &merged[len(merged)-1]
+		if r.Min <= last.Max+1 {
+			if r.Max > last.Max {
+				last.Max = r.Max
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
@@ -0,0 +1,116 @@
+package ae_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/freetsdb/freetsdb/services/ae"
+	"github.com/freetsdb/freetsdb/tsdb/engine/tsm1"
+)
+
+func TestReadDigest(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := tsm1.NewDigestWriter(nopWriteCloser{&buf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteManifest(&tsm1.DigestManifest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := &tsm1.DigestTimeSpan{}
+	ts.Add(1, 10, 10, 0xdead)
+	ts.Add(11, 20, 10, 0xbeef)
+	if err := w.WriteTimeSpan("cpu,host=a#!~#value", ts); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := ae.ReadDigest(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := ae.Digest{"cpu,host=a#!~#value": ts.Ranges}
+	if !reflect.DeepEqual(d, exp) {
+		t.Fatalf("unexpected digest:\n\texp=%v\n\tgot=%v", exp, d)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	local := ae.Digest{
+		"a": {{Min: 0, Max: 9, N: 10, CRC: 1}, {Min: 10, Max: 19, N: 10, CRC: 2}},
+		"b": {{Min: 0, Max: 9, N: 10, CRC: 3}},
+		"d": {{Min: 0, Max: 9, N: 8, CRC: 7}},
+	}
+	remote := ae.Digest{
+		"a": {{Min: 0, Max: 9, N: 10, CRC: 1}, {Min: 10, Max: 19, N: 10, CRC: 2}},
+		"b": {{Min: 0, Max: 9, N: 10, CRC: 4}},
+		"c": {{Min: 5, Max: 12, N: 4, CRC: 5}, {Min: 30, Max: 40, N: 2, CRC: 6}},
+		"d": {{Min: 0, Max: 9, N: 10, CRC: 8}},
+	}
+
+	div := ae.Diff(local, remote)
+	if div.Series != 2 {
+		t.Fatalf("unexpected divergent series: %d", div.Series)
+	}
+
+	exp := []ae.TimeRange{{Min: 0, Max: 12}, {Min: 30, Max: 40}}
+	if !reflect.DeepEqual(div.Ranges, exp) {
+		t.Fatalf("unexpected ranges:\n\texp=%v\n\tgot=%v", exp, div.Ranges)
+	}
+
+	if div := ae.Diff(remote, remote); !div.Empty() {
+		t.Fatalf("expected no divergence, got %+v", div)
+	}
+}
+
+// Ensure replicas holding the same points in differently compacted blocks
+// are not reported as divergent.
+func TestDiff_BlockLayout(t *testing.T) {
+	local := ae.Digest{
+		"a": {{Min: 0, Max: 19, N: 20, CRC: 1}, {Min: 20, Max: 29, N: 10, CRC: 2}},
+	}
+	remote := ae.Digest{
+		"a": {
+			{Min: 0, Max: 4, N: 5, CRC: 3},
+			{Min: 5, Max: 14, N: 10, CRC: 4},
+			{Min: 15, Max: 29, N: 15, CRC: 5},
+		},
+	}
+
+	if div := ae.Diff(local, remote); !div.Empty() {
+		t.Fatalf("expected no divergence, got %+v", div)
+	}
+	if div := ae.Diff(remote, local); !div.Empty() {
+		t.Fatalf("expected no divergence, got %+v", div)
+	}
+}
+
+// Ensure uncompacted remote blocks that may share points are only reported
+// when local cannot hold all of them.
+func TestDiff_Overlap(t *testing.T) {
+	remote := ae.Digest{
+		"a": {{Min: 0, Max: 9, N: 10, CRC: 1}, {Min: 5, Max: 9, N: 5, CRC: 2}},
+	}
+
+	local := ae.Digest{"a": {{Min: 0, Max: 9, N: 10, CRC: 3}}}
+	if div := ae.Diff(local, remote); !div.Empty() {
+		t.Fatalf("expected no divergence, got %+v", div)
+	}
+
+	local = ae.Digest{"a": {{Min: 0, Max: 9, N: 6, CRC: 4}}}
+	div := ae.Diff(local, remote)
+	if exp := []ae.TimeRange{{Min: 0, Max: 9}}; div.Series != 1 || !reflect.DeepEqual(div.Ranges, exp) {
+		t.Fatalf("unexpected divergence: %+v", div)
+	}
+}
+
+type nopWriteCloser struct {
+	*bytes.Buffer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,412 @@
+// Package ae provides the anti-entropy service, which repairs shard
+// replicas that have drifted apart.
+package ae // import "github.com/freetsdb/freetsdb/services/ae"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/freetsdb/freetsdb"
+	"github.com/freetsdb/freetsdb/logger"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tcp"
+	"go.uber.org/zap"
+)
+
+// MuxHeader is the header byte used for the TCP muxer.
+const MuxHeader = 10
+
+// ErrCheckInProgress is returned when a repair is requested while a check
+// is already running.
+var ErrCheckInProgress = errors.New("anti-entropy check already in progress")
+
+// Shard states reported in ShardStatus.
+const (
+	StateConsistent = "consistent"
+	StateDiverged   = "diverged"
+	StateRepairing  = "repairing"
+	StateRepaired   = "repaired"
+	StateError      = "error"
+)
+
+// ShardStatus is the outcome of the last anti-entropy check of a shard.
+type ShardStatus struct {
+	ShardID         uint64    `json:"shardID"`
+	Database        string    `json:"database"`
+	RetentionPolicy string    `json:"retentionPolicy"`
+	State           string    `json:"state"`
+	DivergentSeries int       `json:"divergentSeries"`
+	RepairedRanges  int       `json:"repairedRanges"`
+	LastCheck       time.Time `json:"lastCheck"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// Service compares local shards with the other owners of the same shard
+// and pulls any blocks that are missing locally.
+type Service struct {
+	checkInterval time.Duration
+	maxRanges     int
+	enabled       bool
+
+	Node *freetsdb.Node
+
+	MetaClient interface {
+		DataNode(id uint64) (*meta.NodeInfo, error)
+		ShardOwner(shardID uint64) (database, policy string, sgi *meta.ShardGroupInfo)
+	}
+
+	TSDBStore interface {
+		ShardIDs() []uint64
+		ShardDigest(id uint64) (io.ReadCloser, int64, error)
+		ExportShard(id uint64, start time.Time, end time.Time, w io.Writer) error
+		ImportShard(id uint64, r io.Reader) error
+	}
+
+	// Dialer connects to the anti-entropy service of the other owners.
+	Dialer *tcp.Dialer
+
+	Listener net.Listener
+	Logger   *zap.Logger
+
+	mu       sync.Mutex
+	status   map[uint64]*ShardStatus
+	checking bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewService returns a new instance of Service.
+func NewService(c Config) *Service {
+	return &Service{
+		checkInterval: time.Duration(c.CheckInterval),
+		maxRanges:     c.MaxRangesPerShard,
+		enabled:       c.Enabled,
+		status:        make(map[uint64]*ShardStatus),
+		Logger:        zap.NewNop(),
+	}
+}
+
+// WithLogger sets the logger on the service.
+func (s *Service) WithLogger(log *zap.Logger) {
+	s.Logger = log.With(zap.String("service", "anti-entropy"))
+}
+
+// Open starts the service.
+func (s *Service) Open() error {
+	if s.done != nil {
+		return nil
+	}
+
+	s.Logger.Info("Starting anti-entropy service",
+		zap.Bool("enabled", s.enabled),
+		logger.DurationLiteral("check_interval", s.checkInterval))
+
+	s.done = make(chan struct{})
+
+	if s.Listener != nil {
+		s.wg.Add(1)
+		go s.serve()
+	}
+
+	if s.enabled {
+		s.wg.Add(1)
+		go s.run()
+	}
+	return nil
+}
+
+// Close stops the service.
+func (s *Service) Close() error {
+	if s.done == nil {
+		return nil
+	}
+
+	close(s.done)
+	if s.Listener != nil {
+		s.Listener.Close()
+	}
+	s.wg.Wait()
+	s.done = nil
+
+	return nil
+}
+
+// Status returns the last check result of every local shard, ordered by ID.
+func (s *Service) Status() []ShardStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := make([]ShardStatus, 0, len(s.status))
+	for _, st := range s.status {
+		a = append(a, *st)
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].ShardID < a[j].ShardID })
+	return a
+}
+
+// Repair starts a check of the shard with the given ID, or of all local
+// shards if id is zero, in the background. Unlike the periodic check,
+// shards that are still receiving writes are included.
+func (s *Service) Repair(id uint64) error {
+	ids := []uint64{id}
+	if id == 0 {
+		ids = s.TSDBStore.ShardIDs()
+	}
+
+	if !s.startCheck() {
+		return ErrCheckInProgress
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.endCheck()
+		s.check(ids, true)
+	}()
+	return nil
+}
+
+// run periodically checks all local shards.
+func (s *Service) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !s.startCheck() {
+				continue
+			}
+			s.check(s.TSDBStore.ShardIDs(), false)
+			s.endCheck()
+		case <-s.done:
+			s.Logger.Info("Terminating anti-entropy service")
+			return
+		}
+	}
+}
+
+func (s *Service) startCheck() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.checking {
+		return false
+	}
+	s.checking = true
+	return true
+}
+
+func (s *Service) endCheck() {
+	s.mu.Lock()
+	s.checking = false
+	s.mu.Unlock()
+}
+
+// check compares each shard with its replicas. Hot shards, whose shard group
+// has not yet ended, are skipped unless includeHot is set since in-flight
+// writes make them diverge constantly.
+func (s *Service) check(ids []uint64, includeHot bool) {
+	now := time.Now().UTC()
+	for _, id := range ids {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		database, policy, sgi := s.MetaClient.ShardOwner(id)
+		if sgi == nil {
+			continue
+		} else if !includeHot && sgi.EndTime.After(now) {
+			continue
+		}
+
+		st := &ShardStatus{
+			ShardID:         id,
+			Database:        database,
+			RetentionPolicy: policy,
+			State:           StateConsistent,
+			LastCheck:       now,
+		}
+		if err := s.checkShard(id, sgi, st); err != nil {
+			s.Logger.Info("Anti-entropy check failed", zap.Uint64("id", id), zap.Error(err))
+			st.State, st.Error = StateError, err.Error()
+		}
+		s.setStatus(st)
+	}
+}
+
+func (s *Service) setStatus(st *ShardStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := *st
+	s.status[st.ShardID] = &cp
+}
+
+// checkShard compares the local digest of a shard with the digest of every
+// other owner and imports the blocks that are missing locally. Owners that
+// can't be reached are skipped; the check only fails if none of them can.
+func (s *Service) checkShard(id uint64, sgi *meta.ShardGroupInfo, st *ShardStatus) error {
+	var owners []meta.ShardOwner
+	for _, si := range sgi.Shards {
+		if si.ID == id {
+			owners = si.Owners
+		}
+	}
+	if len(owners) < 2 {
+		return nil
+	}
+
+	rc, _, err := s.TSDBStore.ShardDigest(id)
+	if err != nil {
+		return fmt.Errorf("local digest: %s", err)
+	}
+	local, err := ReadDigest(rc)
+	rc.Close()
+	if err != nil {
+		return fmt.Errorf("local digest: %s", err)
+	}
+
+	var checked, skipped int
+	for _, owner := range owners {
+		if s.Node != nil && owner.NodeID == s.Node.ID {
+			continue
+		}
+
+		n, err := s.MetaClient.DataNode(owner.NodeID)
+		if err != nil {
+			s.Logger.Info("Skipping unknown shard owner",
+				zap.Uint64("id", id), zap.Uint64("node", owner.NodeID), zap.Error(err))
+			skipped++
+			continue
+		}
+		client := NewClient(n.TCPHost, s.Dialer)
+
+		remote, err := client.Digest(id)
+		if err != nil {
+			s.Logger.Info("Skipping unreachable shard owner",
+				zap.Uint64("id", id), zap.Uint64("node", n.ID), zap.Error(err))
+			skipped++
+			continue
+		}
+		checked++
+
+		div := Diff(local, remote)
+		if div.Empty() {
+			continue
+		}
+
+		st.State = StateRepairing
+		st.DivergentSeries += div.Series
+		s.setStatus(st)
+
+		// Fetch the whole divergent span at once if it is too fragmented.
+		ranges := div.Ranges
+		if len(ranges) > s.maxRanges {
+			ranges = []TimeRange{{Min: ranges[0].Min, Max: ranges[len(ranges)-1].Max}}
+		}
+
+		s.Logger.Info("Repairing shard",
+			zap.Uint64("id", id),
+			zap.Uint64("source", n.ID),
+			zap.Int("series", div.Series),
+			zap.Int("ranges", len(ranges)))
+
+		for _, tr := range ranges {
+			if err := s.fetchBlocks(client, id, tr); err != nil {
+				return fmt.Errorf("blocks from node %d: %s", n.ID, err)
+			}
+			st.RepairedRanges++
+		}
+		st.State = StateRepaired
+	}
+
+	if checked == 0 && skipped > 0 {
+		return fmt.Errorf("none of %d other owners could be reached", skipped)
+	}
+	return nil
+}
+
+// fetchBlocks streams the blocks of a shard overlapping tr from a remote node
+// and imports them into the local shard.
+func (s *Service) fetchBlocks(client *Client, id uint64, tr TimeRange) error {
+	r, err := client.Blocks(id, tr)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return s.TSDBStore.ImportShard(id, r)
+}
+
+// serve serves anti-entropy requests from the listener.
+func (s *Service) serve() {
+	defer s.wg.Done()
+
+	for {
+		// Wait for next connection.
+		conn, err := s.Listener.Accept()
+		if err != nil && strings.Contains(err.Error(), "connection closed") {
+			s.Logger.Info("Anti-entropy listener closed")
+			return
+		} else if err != nil {
+			s.Logger.Info("Error accepting anti-entropy request", zap.Error(err))
+			continue
+		}
+
+		// Handle connection in separate goroutine.
+		s.wg.Add(1)
+		go func(conn net.Conn) {
+			defer s.wg.Done()
+			defer conn.Close()
+			if err := s.handleConn(conn); err != nil {
+				s.Logger.Info("Failed to handle anti-entropy request", zap.Error(err))
+			}
+		}(conn)
+	}
+}
+
+// handleConn processes conn. This is run in a separate goroutine.
+func (s *Service) handleConn(conn net.Conn) error {
+	var req Request
+	if err := readMessage(conn, &req); err != nil {
+		return fmt.Errorf("read request: %s", err)
+	}
+
+	switch req.Type {
+	case RequestShardDigest:
+		rc, _, err := s.TSDBStore.ShardDigest(req.ShardID)
+		if err != nil {
+			return writeMessage(conn, &Response{Error: err.Error()})
+		}
+		defer rc.Close()
+
+		if err := writeMessage(conn, &Response{}); err != nil {
+			return err
+		}
+		_, err = io.Copy(conn, rc)
+		return err
+	case RequestShardBlocks:
+		if err := writeMessage(conn, &Response{}); err != nil {
+			return err
+		}
+		return s.TSDBStore.ExportShard(req.ShardID, time.Unix(0, req.Start), time.Unix(0, req.End), conn)
+	case RequestStatus:
+		return writeMessage(conn, &Response{Status: s.Status()})
+	case RequestRepair:
+		var resp Response
+		if err := s.Repair(req.ShardID); err != nil {
+			resp.Error = err.Error()
+		}
+		return writeMessage(conn, &resp)
+	default:
+		return writeMessage(conn, &Response{Error: fmt.Sprintf("request type unknown: %v", req.Type)})
+	}
+}
@@ -0,0 +1,231 @@
+package ae_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/freetsdb/freetsdb"
+	"github.com/freetsdb/freetsdb/services/ae"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tcp"
+	"github.com/freetsdb/freetsdb/tsdb/engine/tsm1"
+)
+
+// Ensure a node pulls blocks that only exist on another owner of a shard.
+func TestService_Repair(t *testing.T) {
+	local := &TSDBStore{digest: MustDigest(t, map[string][]tsm1.DigestTimeRange{
+		"cpu#!~#value": {{Min: 0, Max: 9, N: 10, CRC: 1}},
+	})}
+	remote := &TSDBStore{digest: MustDigest(t, map[string][]tsm1.DigestTimeRange{
+		"cpu#!~#value": {{Min: 0, Max: 9, N: 10, CRC: 1}, {Min: 10, Max: 19, N: 10, CRC: 2}},
+	})}
+
+	mc := &MetaClient{nodes: map[uint64]string{}}
+	s1 := MustOpenService(1, local, mc)
+	defer s1.Close()
+	s2 := MustOpenService(2, remote, mc)
+	defer s2.Close()
+
+	status := MustRepair(t, mc.nodes[1])
+	if len(status) != 1 {
+		t.Fatalf("unexpected status: %+v", status)
+	} else if st := status[0]; st.State != ae.StateRepaired || st.DivergentSeries != 1 || st.RepairedRanges != 1 {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+
+	if remote.exported != [2]int64{10, 19} {
+		t.Fatalf("unexpected export range: %v", remote.exported)
+	} else if string(local.imported) != "blocks" {
+		t.Fatalf("unexpected import: %q", local.imported)
+	}
+}
+
+// Ensure owners that can't be reached are skipped and the shard is still
+// repaired from the others.
+func TestService_Repair_UnreachableOwner(t *testing.T) {
+	local := &TSDBStore{digest: MustDigest(t, map[string][]tsm1.DigestTimeRange{
+		"cpu#!~#value": {{Min: 0, Max: 9, N: 10, CRC: 1}},
+	})}
+	remote := &TSDBStore{digest: MustDigest(t, map[string][]tsm1.DigestTimeRange{
+		"cpu#!~#value": {{Min: 0, Max: 9, N: 10, CRC: 1}, {Min: 10, Max: 19, N: 10, CRC: 2}},
+	})}
+
+	// Node 3 is not listening and node 4 is not a data node anymore.
+	mc := &MetaClient{nodes: map[uint64]string{3: MustClosedAddr(t)}, unknown: []uint64{4}}
+	s1 := MustOpenService(1, local, mc)
+	defer s1.Close()
+	s2 := MustOpenService(2, remote, mc)
+	defer s2.Close()
+
+	status := MustRepair(t, mc.nodes[1])
+	if len(status) != 1 {
+		t.Fatalf("unexpected status: %+v", status)
+	} else if st := status[0]; st.State != ae.StateRepaired || st.RepairedRanges != 1 || st.Error != "" {
+		t.Fatalf("unexpected status: %+v", st)
+	} else if string(local.imported) != "blocks" {
+		t.Fatalf("unexpected import: %q", local.imported)
+	}
+}
+
+// Ensure the check fails if no other owner can be reached.
+func TestService_Repair_NoReachableOwner(t *testing.T) {
+	local := &TSDBStore{digest: MustDigest(t, map[string][]tsm1.DigestTimeRange{
+		"cpu#!~#value": {{Min: 0, Max: 9, N: 10, CRC: 1}},
+	})}
+
+	mc := &MetaClient{nodes: map[uint64]string{2: MustClosedAddr(t)}}
+	s1 := MustOpenService(1, local, mc)
+	defer s1.Close()
+
+	status := MustRepair(t, mc.nodes[1])
+	if len(status) != 1 {
+		t.Fatalf("unexpected status: %+v", status)
+	} else if st := status[0]; st.State != ae.StateError || st.Error != "none of 1 other owners could be reached" {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+}
+
+// MustRepair requests a check of shard 1 from the node at host and returns
+// its status once the check has finished.
+func MustRepair(t *testing.T, host string) []ae.ShardStatus {
+	t.Helper()
+	if err := ae.NewClient(host, nil).Repair(1); err != nil {
+		t.Fatal(err)
+	}
+
+	var status []ae.ShardStatus
+	for i := 0; i < 100; i++ {
+		var err error
+		if status, err = ae.NewClient(host, nil).Status(); err != nil {
+			t.Fatal(err)
+		} else if len(status) == 1 && status[0].State != ae.StateRepairing {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return status
+}
+
+// MustClosedAddr returns the address of a listener that was closed, so
+// connections to it are refused.
+func MustClosedAddr(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr
+}
+
+// Service is a test wrapper for ae.Service.
+type Service struct {
+	*ae.Service
+	ln net.Listener
+}
+
+// MustOpenService returns a new, opened service for node id listening on a
+// random port, and registers its address with mc.
+func MustOpenService(id uint64, store *TSDBStore, mc *MetaClient) *Service {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+
+	mux := tcp.NewMux()
+	go mux.Serve(ln)
+
+	s := &Service{Service: ae.NewService(ae.NewConfig()), ln: ln}
+	s.Node = &freetsdb.Node{ID: id}
+	s.MetaClient = mc
+	s.TSDBStore = store
+	s.Listener = mux.Listen(ae.MuxHeader)
+	mc.nodes[id] = ln.Addr().String()
+
+	if err := s.Open(); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Close shuts down the service and the attached listener.
+func (s *Service) Close() error {
+	s.ln.Close()
+	return s.Service.Close()
+}
+
+// MetaClient is a mock where shard 1 is owned by every registered node and
+// by the unknown nodes, which are not data nodes.
+type MetaClient struct {
+	nodes   map[uint64]string
+	unknown []uint64
+}
+
+func (c *MetaClient) DataNode(id uint64) (*meta.NodeInfo, error) {
+	host, ok := c.nodes[id]
+	if !ok {
+		return nil, meta.ErrNodeNotFound
+	}
+	return &meta.NodeInfo{ID: id, TCPHost: host}, nil
+}
+
+func (c *MetaClient) ShardOwner(shardID uint64) (string, string, *meta.ShardGroupInfo) {
+	si := meta.ShardInfo{ID: shardID}
+	for id := range c.nodes {
+		si.Owners = append(si.Owners, meta.ShardOwner{NodeID: id})
+	}
+	for _, id := range c.unknown {
+		si.Owners = append(si.Owners, meta.ShardOwner{NodeID: id})
+	}
+	return "db", "rp", &meta.ShardGroupInfo{ID: 1, Shards: []meta.ShardInfo{si}}
+}
+
+// TSDBStore is a mock store holding a single shard.
+type TSDBStore struct {
+	digest   []byte
+	exported [2]int64
+	imported []byte
+}
+
+func (s *TSDBStore) ShardIDs() []uint64 { return []uint64{1} }
+
+func (s *TSDBStore) ShardDigest(id uint64) (io.ReadCloser, int64, error) {
+	return ioutil.NopCloser(bytes.NewReader(s.digest)), int64(len(s.digest)), nil
+}
+
+func (s *TSDBStore) ExportShard(id uint64, start, end time.Time, w io.Writer) error {
+	s.exported = [2]int64{start.UnixNano(), end.UnixNano()}
+	_, err := w.Write([]byte("blocks"))
+	return err
+}
+
+func (s *TSDBStore) ImportShard(id uint64, r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	s.imported = b
+	return err
+}
+
+// MustDigest returns an encoded digest of the given series.
+func MustDigest(t *testing.T, series map[string][]tsm1.DigestTimeRange) []byte {
+	var buf bytes.Buffer
+	w, err := tsm1.NewDigestWriter(nopWriteCloser{&buf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteManifest(&tsm1.DigestManifest{}); err != nil {
+		t.Fatal(err)
+	}
+	for key, ranges := range series {
+		if err := w.WriteTimeSpan(key, &tsm1.DigestTimeSpan{Ranges: ranges}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
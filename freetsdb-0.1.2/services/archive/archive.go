@@ -0,0 +1,171 @@
+// Package archive reads and writes shard archives.
+//
+// An archive directory uses an S3-style key layout so it can be synced to,
+// or served by, an object store:
+//
+//	shards/<database>/<policy>/<shard-id>/data.tar
+//	shards/<database>/<policy>/<shard-id>/manifest.json
+//
+// data.tar holds a full backup of a compacted shard, TSM and index files
+// included. The manifest is always written last, so an archive without a
+// manifest is incomplete.
+package archive // import "github.com/freetsdb/freetsdb/services/archive"
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	// DataName is the name of the shard data object of an archive.
+	DataName = "data.tar"
+
+	// ManifestName is the name of the manifest object of an archive.
+	ManifestName = "manifest.json"
+
+	// pendingSuffix is added to objects while they are being written.
+	pendingSuffix = ".pending"
+)
+
+var (
+	// ErrArchiveNotFound is returned when a shard has no complete archive.
+	ErrArchiveNotFound = errors.New("shard archive not found")
+
+	// ErrChecksumMismatch is returned when the shard data of an archive does
+	// not match the checksum in its manifest.
+	ErrChecksumMismatch = errors.New("shard archive checksum mismatch")
+)
+
+// Manifest describes an archived shard.
+type Manifest struct {
+	ShardID      uint64    `json:"shardID"`
+	Database     string    `json:"database"`
+	Policy       string    `json:"policy"`
+	ShardGroupID uint64    `json:"shardGroupID"`
+	StartTime    time.Time `json:"startTime"`
+	EndTime      time.Time `json:"endTime"`
+	ArchivedAt   time.Time `json:"archivedAt"`
+	Size         int64     `json:"size"`
+	SHA256       string    `json:"sha256"`
+}
+
+// Key returns the key prefix of the archive of a shard.
+func Key(database, policy string, id uint64) string {
+	return path.Join("shards", database, policy, strconv.FormatUint(id, 10))
+}
+
+// Write stores the shard data read from r and m in the archive directory
+// dir. The size and checksum of m are set from the data written.
+func Write(dir string, m *Manifest, r io.Reader) error {
+	prefix := filepath.Join(dir, filepath.FromSlash(Key(m.Database, m.Policy, m.ShardID)))
+	if err := os.MkdirAll(prefix, 0777); err != nil {
+		return err
+	}
+
+	// Remove a previous manifest so the archive stays incomplete until the
+	// new data has been written.
+	if err := os.Remove(filepath.Join(prefix, ManifestName)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	h := sha256.New()
+	n, err := writeFile(filepath.Join(prefix, DataName), io.TeeReader(r, h))
+	if err != nil {
+		return fmt.Errorf("write shard data: %s", err)
+	}
+	m.Size = n
+	m.SHA256 = hex.EncodeToString(h.Sum(nil))
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := writeFile(filepath.Join(prefix, ManifestName), bytes.NewReader(b)); err != nil {
+		return fmt.Errorf("write manifest: %s", err)
+	}
+	return nil
+}
+
+// ReadManifest returns the manifest of the archive of a shard.
+func ReadManifest(dir, database, policy string, id uint64) (*Manifest, error) {
+	prefix := filepath.Join(dir, filepath.FromSlash(Key(database, policy, id)))
+	b, err := ioutil.ReadFile(filepath.Join(prefix, ManifestName))
+	if os.IsNotExist(err) {
+		return nil, ErrArchiveNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("read manifest: %s", err)
+	}
+	return &m, nil
+}
+
+// Open returns a reader for the shard data of an archive once its size and
+// checksum have been verified against m. The caller must close the reader.
+func Open(dir string, m *Manifest) (io.ReadCloser, error) {
+	prefix := filepath.Join(dir, filepath.FromSlash(Key(m.Database, m.Policy, m.ShardID)))
+	f, err := os.Open(filepath.Join(prefix, DataName))
+	if os.IsNotExist(err) {
+		return nil, ErrArchiveNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	} else if n != m.Size || hex.EncodeToString(h.Sum(nil)) != m.SHA256 {
+		f.Close()
+		return nil, ErrChecksumMismatch
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// writeFile atomically writes the contents of r to name and returns the
+// number of bytes written.
+func writeFile(name string, r io.Reader) (int64, error) {
+	tmp := name + pendingSuffix
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0666)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := io.Copy(f, r)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return 0, err
+	}
+
+	if err := os.Rename(tmp, name); err != nil {
+		os.Remove(tmp)
+		return 0, err
+	}
+	return n, nil
+}
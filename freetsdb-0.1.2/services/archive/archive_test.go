@@ -0,0 +1,85 @@
+package archive_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/freetsdb/freetsdb/services/archive"
+)
+
+func TestWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := []byte("shard data")
+	m := &archive.Manifest{
+		ShardID:    3,
+		Database:   "db0",
+		Policy:     "rp0",
+		ArchivedAt: time.Unix(0, 0).UTC(),
+	}
+	if err := archive.Write(dir, m, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	} else if m.Size != int64(len(data)) {
+		t.Fatalf("unexpected size: %d", m.Size)
+	}
+
+	// Objects are laid out under S3-style keys.
+	for _, name := range []string{archive.DataName, archive.ManifestName} {
+		if _, err := os.Stat(filepath.Join(dir, "shards", "db0", "rp0", "3", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := archive.ReadManifest(dir, "db0", "rp0", 3)
+	if err != nil {
+		t.Fatal(err)
+	} else if *got != *m {
+		t.Fatalf("unexpected manifest: %+v", got)
+	}
+
+	r, err := archive.Open(dir, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(b, data) {
+		t.Fatalf("unexpected data: %q", b)
+	}
+
+	if _, err := archive.ReadManifest(dir, "db0", "rp0", 4); err != archive.ErrArchiveNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestOpen_ChecksumMismatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	m := &archive.Manifest{ShardID: 3, Database: "db0", Policy: "rp0"}
+	if err := archive.Write(dir, m, bytes.NewReader([]byte("shard data"))); err != nil {
+		t.Fatal(err)
+	}
+
+	name := filepath.Join(dir, "shards", "db0", "rp0", "3", archive.DataName)
+	if err := ioutil.WriteFile(name, []byte("shard dat4"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := archive.Open(dir, m); err != archive.ErrChecksumMismatch {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -12,6 +12,9 @@ import (
 const (
 	// The default value of how often to check whether any CQs need to be run.
 	DefaultRunInterval = time.Second
+
+	// DefaultCatchUpLimit is the default for how far back missed intervals are computed.
+	DefaultCatchUpLimit = 24 * time.Hour
 )
 
 // Config represents a configuration for the continuous query service.
@@ -31,6 +34,10 @@ type Config struct {
 	// every minute, this should be set to 1 minute. The default is set to '1s' so the interval
 	// is compatible with most aggregations.
 	RunInterval toml.Duration `toml:"run-interval"`
+
+	// CatchUpLimit is how far back intervals missed while no node ran a continuous
+	// query are computed when it runs again. Zero computes every missed interval.
+	CatchUpLimit toml.Duration `toml:"catch-up-limit"`
 }
 
 // NewConfig returns a new instance of Config with defaults.
@@ -40,6 +47,7 @@ func NewConfig() Config {
 		Enabled:           true,
 		QueryStatsEnabled: false,
 		RunInterval:       toml.Duration(DefaultRunInterval),
+		CatchUpLimit:      toml.Duration(DefaultCatchUpLimit),
 	}
 }
 
@@ -55,6 +63,10 @@ func (c Config) Validate() error {
 		return errors.New("run-interval must be positive")
 	}
 
+	if c.CatchUpLimit < 0 {
+		return errors.New("catch-up-limit must not be negative")
+	}
+
 	return nil
 }
 
@@ -70,5 +82,6 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 		"enabled":             true,
 		"query-stats-enabled": c.QueryStatsEnabled,
 		"run-interval":        c.RunInterval,
+		"catch-up-limit":      c.CatchUpLimit,
 	}), nil
 }
@@ -13,6 +13,7 @@ func TestConfig_Parse(t *testing.T) {
 	var c continuous_querier.Config
 	if _, err := toml.Decode(`
 run-interval = "1m"
+catch-up-limit = "2h"
 enabled = true
 `, &c); err != nil {
 		t.Fatal(err)
@@ -21,6 +22,8 @@ enabled = true
 	// Validate configuration.
 	if time.Duration(c.RunInterval) != time.Minute {
 		t.Fatalf("unexpected run interval: %v", c.RunInterval)
+	} else if time.Duration(c.CatchUpLimit) != 2*time.Hour {
+		t.Fatalf("unexpected catch-up limit: %v", c.CatchUpLimit)
 	} else if !c.Enabled {
 		t.Fatalf("unexpected enabled: %v", c.Enabled)
 	}
@@ -43,4 +46,10 @@ func TestConfig_Validate(t *testing.T) {
 	if err := c.Validate(); err == nil {
 		t.Fatal("expected error for negative run-interval, got nil")
 	}
+
+	c = continuous_querier.NewConfig()
+	c.CatchUpLimit = -1
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for negative catch-up-limit, got nil")
+	}
 }
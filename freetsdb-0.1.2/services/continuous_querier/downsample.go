@@ -0,0 +1,299 @@
+package continuous_querier
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/freetsdb/freetsdb/logger"
+	"github.com/freetsdb/freetsdb/query"
+	"github.com/freetsdb/freetsdb/services/influxql"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"go.uber.org/zap"
+)
+
+// downsampleFieldTypes are the field types each downsample aggregate supports.
+// Fields of a type none of the aggregates of a rule supports are downsampled
+// with last().
+var downsampleFieldTypes = map[string][]influxql.DataType{
+	"count":  {influxql.Float, influxql.Integer, influxql.Unsigned, influxql.String, influxql.Boolean},
+	"first":  {influxql.Float, influxql.Integer, influxql.Unsigned, influxql.String, influxql.Boolean},
+	"last":   {influxql.Float, influxql.Integer, influxql.Unsigned, influxql.String, influxql.Boolean},
+	"mode":   {influxql.Float, influxql.Integer, influxql.Unsigned, influxql.String, influxql.Boolean},
+	"max":    {influxql.Float, influxql.Integer, influxql.Unsigned, influxql.Boolean},
+	"min":    {influxql.Float, influxql.Integer, influxql.Unsigned, influxql.Boolean},
+	"mean":   {influxql.Float, influxql.Integer, influxql.Unsigned},
+	"median": {influxql.Float, influxql.Integer, influxql.Unsigned},
+	"spread": {influxql.Float, influxql.Integer, influxql.Unsigned},
+	"stddev": {influxql.Float, influxql.Integer, influxql.Unsigned},
+	"sum":    {influxql.Float, influxql.Integer, influxql.Unsigned},
+}
+
+// downsampleAggregates returns the aggregates of aggregates supporting typ.
+func downsampleAggregates(aggregates []string, typ influxql.DataType) []string {
+	var a []string
+	for _, name := range aggregates {
+		for _, t := range downsampleFieldTypes[name] {
+			if t == typ {
+				a = append(a, name)
+				break
+			}
+		}
+	}
+	if len(a) == 0 {
+		return []string{"last"}
+	}
+	return a
+}
+
+// hasDownsamples returns true if a retention policy of db has downsample rules.
+func hasDownsamples(db *meta.DatabaseInfo) bool {
+	for _, rpi := range db.RetentionPolicies {
+		if len(rpi.Downsamples) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// downsampleRetry records the consecutive failures of a downsample rule and
+// when it is run again.
+type downsampleRetry struct {
+	failures int
+	at       time.Time
+}
+
+// runDownsamples runs the downsample rules of every retention policy. A rule
+// that failed is not run again until its backoff has passed, since its last
+// run is not advanced and every run would compute the same intervals again.
+func (s *Service) runDownsamples(now time.Time) {
+	dbs, _ := s.MetaClient.Databases()
+	for _, db := range dbs {
+		for _, rpi := range db.RetentionPolicies {
+			for _, ds := range rpi.Downsamples {
+				id := db.Name + idDelimiter + rpi.Name + idDelimiter + ds.Target
+
+				s.mu.RLock()
+				retry, failed := s.downsampleRetries[id]
+				s.mu.RUnlock()
+				if failed && now.Before(retry.at) {
+					continue
+				}
+
+				ok, err := s.ExecuteDownsample(&db, &rpi, &ds, now)
+				if err != nil {
+					retry.failures++
+					retry.at = now.Add(s.downsampleBackoff(&ds, retry.failures))
+					s.Logger.Info("Error executing downsample",
+						logger.Database(db.Name),
+						logger.RetentionPolicy(rpi.Name),
+						zap.String("target", ds.Target),
+						zap.Int("failures", retry.failures),
+						zap.Time("retry", retry.at),
+						zap.Error(err))
+					atomic.AddInt64(&s.stats.QueryFail, 1)
+				} else if ok {
+					atomic.AddInt64(&s.stats.QueryOK, 1)
+				}
+
+				s.mu.Lock()
+				if err != nil {
+					s.downsampleRetries[id] = retry
+				} else {
+					delete(s.downsampleRetries, id)
+				}
+				s.mu.Unlock()
+			}
+		}
+	}
+}
+
+// downsampleBackoff returns how long a downsample rule that failed n times in
+// a row waits before it runs again. It doubles from the run interval of the
+// service with every failure, up to the interval of the rule.
+func (s *Service) downsampleBackoff(ds *meta.DownsampleInfo, n int) time.Duration {
+	d := s.RunInterval
+	for i := 1; i < n && d < ds.Interval; i++ {
+		d *= 2
+	}
+	if d > ds.Interval && ds.Interval > s.RunInterval {
+		d = ds.Interval
+	}
+	return d
+}
+
+// ExecuteDownsample computes the intervals of a downsample rule that ended
+// since it last ran. It returns false if there were no errors and no interval
+// had to be computed.
+func (s *Service) ExecuteDownsample(dbi *meta.DatabaseInfo, rpi *meta.RetentionPolicyInfo, ds *meta.DownsampleInfo, now time.Time) (bool, error) {
+	endTime := truncate(now.UTC(), ds.Interval)
+
+	// Compute the last interval on the first run, and the intervals missed
+	// since the last run within the catch-up limit otherwise.
+	startTime := endTime.Add(-ds.Interval)
+	if !ds.LastRun.IsZero() {
+		if !endTime.After(ds.LastRun) {
+			return false, nil
+		}
+		startTime = ds.LastRun
+		if s.CatchUpLimit > 0 {
+			if oldest := truncate(now.UTC().Add(-s.CatchUpLimit), ds.Interval); startTime.Before(oldest) {
+				startTime = oldest
+			}
+		}
+	}
+
+	fields, err := s.downsampleFields(dbi.Name, rpi.Name)
+	if err != nil {
+		return false, err
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if s.loggingEnabled {
+		s.Logger.Info("Executing downsample",
+			logger.Database(dbi.Name),
+			logger.RetentionPolicy(rpi.Name),
+			zap.String("target", ds.Target),
+			zap.Int("measurements", len(names)),
+			zap.Time("start", startTime),
+			zap.Time("end", endTime))
+	}
+
+	for _, name := range names {
+		if len(fields[name]) == 0 {
+			continue
+		}
+
+		stmt, err := downsampleStatement(dbi.Name, rpi.Name, name, ds, fields[name])
+		if err != nil {
+			return false, err
+		} else if err := stmt.SetTimeRange(startTime, endTime); err != nil {
+			return false, fmt.Errorf("unable to set time range: %s", err)
+		}
+
+		if res := s.executeStatement(dbi.Name, stmt); res.Err != nil {
+			return false, fmt.Errorf("measurement %s: %s", name, res.Err)
+		}
+	}
+
+	// Store the end of the computed intervals so the next run resumes from
+	// here, even after a restart or on another node.
+	if err := s.MetaClient.SetDownsampleLastRun(dbi.Name, rpi.Name, ds.Target, endTime); err != nil {
+		s.Logger.Info("Unable to store downsample last run",
+			logger.Database(dbi.Name),
+			logger.RetentionPolicy(rpi.Name),
+			zap.String("target", ds.Target),
+			zap.Error(err))
+	}
+	return true, nil
+}
+
+// downsampleFields returns the fields and their types of every measurement
+// in a retention policy, so new measurements and fields are downsampled as
+// soon as they are written. The fields are read from an owner of every shard
+// of the retention policy, not only from the local shards, and the rule
+// fails if one of them can't be reached.
+func (s *Service) downsampleFields(database, rp string) (map[string]map[string]influxql.DataType, error) {
+	if s.ShardMapper == nil {
+		return nil, errors.New("no shard mapper to read the fields of remote shards")
+	}
+
+	stmt, err := influxql.ParseStatement(fmt.Sprintf("SHOW MEASUREMENTS ON %s", influxql.QuoteIdent(database)))
+	if err != nil {
+		return nil, err
+	}
+
+	res := s.executeStatement(database, stmt)
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	sg, err := s.ShardMapper.MapShards(influxql.Sources{&influxql.Measurement{
+		Database:        database,
+		RetentionPolicy: rp,
+	}}, influxql.TimeRange{
+		Min: time.Unix(0, influxql.MinTime),
+		Max: time.Unix(0, influxql.MaxTime),
+	}, query.SelectOptions{NodeID: s.MetaClient.NodeID()})
+	if err != nil {
+		return nil, err
+	}
+	defer sg.Close()
+
+	fields := make(map[string]map[string]influxql.DataType)
+	for _, row := range res.Series {
+		for _, v := range row.Values {
+			name, _ := v[0].(string)
+			m, _, err := sg.FieldDimensions(&influxql.Measurement{
+				Database:        database,
+				RetentionPolicy: rp,
+				Name:            name,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("measurement %s: fields: %s", name, err)
+			}
+			if len(m) > 0 {
+				fields[name] = m
+			}
+		}
+	}
+	return fields, nil
+}
+
+// downsampleStatement returns the SELECT INTO statement computing the
+// aggregates of a downsample rule for a measurement.
+func downsampleStatement(database, rp, name string, ds *meta.DownsampleInfo, fields map[string]influxql.DataType) (*influxql.SelectStatement, error) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var exprs []string
+	for _, key := range keys {
+		for _, agg := range downsampleAggregates(ds.Aggregates, fields[key]) {
+			exprs = append(exprs, fmt.Sprintf("%s(%s) AS %s", agg, influxql.QuoteIdent(key), influxql.QuoteIdent(agg+"_"+key)))
+		}
+	}
+
+	q := fmt.Sprintf("SELECT %s INTO %s FROM %s GROUP BY time(%s), * fill(none)",
+		strings.Join(exprs, ", "),
+		influxql.QuoteIdent(database, ds.Target, name),
+		influxql.QuoteIdent(database, rp, name),
+		influxql.FormatDuration(ds.Interval))
+
+	stmt, err := influxql.ParseStatement(q)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.(*influxql.SelectStatement), nil
+}
+
+// executeStatement executes a statement on database and returns its result.
+func (s *Service) executeStatement(database string, stmt influxql.Statement) *query.Result {
+	q := &influxql.Query{
+		Statements: influxql.Statements([]influxql.Statement{stmt}),
+	}
+
+	closing := make(chan struct{})
+	defer close(closing)
+
+	ch := s.QueryExecutor.ExecuteQuery(q, query.ExecutionOptions{
+		Database: database,
+	}, closing)
+
+	// There is only one statement, so we will only ever receive one result
+	res, ok := <-ch
+	if !ok {
+		return &query.Result{Err: errors.New("result channel was closed")}
+	}
+	return res
+}
@@ -0,0 +1,187 @@
+package continuous_querier
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/freetsdb/freetsdb/models"
+	"github.com/freetsdb/freetsdb/query"
+	"github.com/freetsdb/freetsdb/services/influxql"
+	"github.com/freetsdb/freetsdb/services/meta"
+)
+
+// Ensure each field is downsampled with the aggregates supporting its type.
+func TestDownsampleStatement(t *testing.T) {
+	ds := &meta.DownsampleInfo{Target: "1h", Interval: time.Hour, Aggregates: []string{"mean", "max"}}
+	fields := map[string]influxql.DataType{
+		"value":  influxql.Float,
+		"up":     influxql.Boolean,
+		"status": influxql.String,
+	}
+
+	stmt, err := downsampleStatement("db", "raw", "cpu", ds, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `SELECT last(status) AS last_status, max(up) AS max_up, mean(value) AS mean_value, max(value) AS max_value INTO db."1h".cpu FROM db.raw.cpu GROUP BY time(1h), * fill(none)`
+	if got := stmt.String(); got != exp {
+		t.Fatalf("unexpected statement:\ngot=%s\nexp=%s", got, exp)
+	}
+}
+
+// Ensure a failing downsample rule is retried with a growing backoff, and
+// runs on every interval again once it succeeds.
+func TestService_RunDownsamples_Backoff(t *testing.T) {
+	s := NewTestService(t)
+	s.RunInterval = time.Second
+
+	mc := s.MetaClient.(*MetaClient)
+	mc.Database("db").RetentionPolicies = []meta.RetentionPolicyInfo{{
+		Name:        "rp",
+		Downsamples: []meta.DownsampleInfo{{Target: "1h", Interval: time.Hour, Aggregates: []string{"mean"}}},
+	}}
+
+	s.ShardMapper = &ShardMapper{
+		MapShardsFn: func(sources influxql.Sources, t influxql.TimeRange) query.ShardGroup {
+			return &ShardGroup{}
+		},
+	}
+
+	var runs int
+	fail := true
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+			runs++
+			if fail {
+				return errExpected
+			}
+			return ctx.Send(&query.Result{})
+		},
+	}
+
+	now := mustParseTime(t, "2000-01-01T00:00:00Z")
+	for _, tt := range []struct {
+		offset time.Duration
+		runs   int
+	}{
+		{offset: 0, runs: 1},
+		{offset: 500 * time.Millisecond, runs: 1},
+		{offset: time.Second, runs: 2},
+		{offset: 2 * time.Second, runs: 2},
+		{offset: 3 * time.Second, runs: 3},
+		{offset: 6 * time.Second, runs: 3},
+		{offset: 7 * time.Second, runs: 4},
+	} {
+		s.runDownsamples(now.Add(tt.offset))
+		if runs != tt.runs {
+			t.Fatalf("%s: unexpected runs: got %d, exp %d", tt.offset, runs, tt.runs)
+		}
+	}
+
+	// The backoff is reset once the rule succeeds.
+	fail = false
+	s.runDownsamples(now.Add(15 * time.Second))
+	if runs != 5 {
+		t.Fatalf("unexpected runs: got %d, exp 5", runs)
+	} else if lastRun := mc.Database("db").RetentionPolicies[0].Downsamples[0].LastRun; !lastRun.Equal(now) {
+		t.Fatalf("unexpected last run: %s", lastRun)
+	} else if len(s.downsampleRetries) != 0 {
+		t.Fatalf("unexpected retries: %v", s.downsampleRetries)
+	}
+}
+
+// Ensure the fields to downsample are read through the shard mapper, so the
+// shards of every owner are included, and the rule fails if one of them can't
+// be read.
+func TestService_ExecuteDownsample_Fields(t *testing.T) {
+	s := NewTestService(t)
+
+	mc := s.MetaClient.(*MetaClient)
+	mc.Database("db").RetentionPolicies = []meta.RetentionPolicyInfo{{
+		Name:        "rp",
+		Downsamples: []meta.DownsampleInfo{{Target: "1h", Interval: time.Hour, Aggregates: []string{"mean"}}},
+	}}
+
+	var fieldsErr error
+	s.ShardMapper = &ShardMapper{
+		MapShardsFn: func(sources influxql.Sources, tr influxql.TimeRange) query.ShardGroup {
+			if m := sources[0].(*influxql.Measurement); m.Database != "db" || m.RetentionPolicy != "rp" {
+				t.Fatalf("unexpected source: %s", m)
+			}
+			return &ShardGroup{
+				FieldDimensionsFn: func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+					if fieldsErr != nil {
+						return nil, nil, fieldsErr
+					}
+					// Only cpu has data in the retention policy.
+					if m.Name == "cpu" {
+						return map[string]influxql.DataType{"value": influxql.Float}, nil, nil
+					}
+					return nil, nil, nil
+				},
+			}
+		},
+	}
+
+	var stmts []string
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+			if _, ok := stmt.(*influxql.ShowMeasurementsStatement); ok {
+				return ctx.Send(&query.Result{Series: models.Rows{{
+					Name:    "measurements",
+					Columns: []string{"name"},
+					Values:  [][]interface{}{{"cpu"}, {"mem"}},
+				}}})
+			}
+			stmts = append(stmts, stmt.String())
+			return ctx.Send(&query.Result{})
+		},
+	}
+
+	rpi := &mc.Database("db").RetentionPolicies[0]
+	now := mustParseTime(t, "2000-01-01T00:00:00Z")
+
+	fieldsErr = errors.New("node 2: connection refused")
+	if _, err := s.ExecuteDownsample(mc.Database("db"), rpi, &rpi.Downsamples[0], now); err == nil || err.Error() != "measurement cpu: fields: node 2: connection refused" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(stmts) != 0 {
+		t.Fatalf("unexpected statements: %v", stmts)
+	} else if !rpi.Downsamples[0].LastRun.IsZero() {
+		t.Fatalf("unexpected last run: %s", rpi.Downsamples[0].LastRun)
+	}
+
+	fieldsErr = nil
+	if ok, err := s.ExecuteDownsample(mc.Database("db"), rpi, &rpi.Downsamples[0], now); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("expected the downsample to run")
+	}
+	exp := `SELECT mean(value) AS mean_value INTO db."1h".cpu FROM db.rp.cpu WHERE time >= '1999-12-31T23:00:00Z' AND time < '2000-01-01T00:00:00Z' GROUP BY time(1h), * fill(none)`
+	if len(stmts) != 1 || stmts[0] != exp {
+		t.Fatalf("unexpected statements:\ngot=%v\nexp=%s", stmts, exp)
+	}
+}
+
+// Ensure the downsample backoff doubles up to the interval of the rule.
+func TestService_DownsampleBackoff(t *testing.T) {
+	s := NewService(NewConfig())
+	s.RunInterval = time.Second
+	ds := &meta.DownsampleInfo{Interval: 10 * time.Second}
+
+	for _, tt := range []struct {
+		n   int
+		exp time.Duration
+	}{
+		{n: 1, exp: time.Second},
+		{n: 2, exp: 2 * time.Second},
+		{n: 4, exp: 8 * time.Second},
+		{n: 5, exp: 10 * time.Second},
+		{n: 100, exp: 10 * time.Second},
+	} {
+		if got := s.downsampleBackoff(ds, tt.n); got != tt.exp {
+			t.Fatalf("%d failures: unexpected backoff: got %s, exp %s", tt.n, got, tt.exp)
+		}
+	}
+}
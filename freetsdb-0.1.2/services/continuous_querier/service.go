@@ -45,6 +45,10 @@ type metaClient interface {
 	AcquireLease(name string) (l *meta.Lease, err error)
 	Databases() ([]meta.DatabaseInfo, error)
 	Database(name string) *meta.DatabaseInfo
+	SetContinuousQueryLastRun(database, name string, t time.Time) error
+	SetContinuousQueryStatus(database, name string, status meta.ContinuousQueryStatus) error
+	SetDownsampleLastRun(database, rp, target string, t time.Time) error
+	NodeID() uint64
 }
 
 // RunRequest is a request to run one or more CQs.
@@ -83,20 +87,27 @@ func (nullMonitor) WritePoints(models.Points) error { return nil }
 type Service struct {
 	MetaClient    metaClient
 	QueryExecutor *query.Executor
+	ShardMapper   query.ShardMapper
 	Monitor       Monitor
 	Config        *Config
 	RunInterval   time.Duration
+	// CatchUpLimit is how far back missed intervals are computed. Zero means no limit.
+	CatchUpLimit time.Duration
 	// RunCh can be used by clients to signal service to run CQs.
 	RunCh             chan *RunRequest
 	Logger            *zap.Logger
 	loggingEnabled    bool
 	queryStatsEnabled bool
 	stats             *Statistics
-	// lastRuns maps CQ name to last time it was run.
+	// lastRuns maps CQ name to last time it was run. A zero time means the CQ
+	// must run as if it never ran before.
 	mu       sync.RWMutex
 	lastRuns map[string]time.Time
-	stop     chan struct{}
-	wg       *sync.WaitGroup
+	// downsampleRetries maps downsample rules that failed on their last run
+	// to when they are retried.
+	downsampleRetries map[string]downsampleRetry
+	stop              chan struct{}
+	wg                *sync.WaitGroup
 }
 
 // NewService returns a new instance of Service.
@@ -105,12 +116,14 @@ func NewService(c Config) *Service {
 		Config:            &c,
 		Monitor:           nullMonitor(0),
 		RunInterval:       time.Duration(c.RunInterval),
+		CatchUpLimit:      time.Duration(c.CatchUpLimit),
 		RunCh:             make(chan *RunRequest),
 		loggingEnabled:    c.LogEnabled,
 		queryStatsEnabled: c.QueryStatsEnabled,
 		Logger:            zap.NewNop(),
 		stats:             &Statistics{},
 		lastRuns:          map[string]time.Time{},
+		downsampleRetries: map[string]downsampleRetry{},
 	}
 
 	return s
@@ -192,9 +205,10 @@ func (s *Service) Run(database, name string, t time.Time) error {
 		// Loop through CQs in each DB executing the ones that match name.
 		for _, cq := range db.ContinuousQueries {
 			if name == "" || cq.Name == name {
-				// Remove the last run time for the CQ
+				// Reset the last run time for the CQ so the one stored
+				// in the meta store is ignored as well.
 				id := fmt.Sprintf("%s%s%s", db.Name, idDelimiter, cq.Name)
-				delete(s.lastRuns, id)
+				s.lastRuns[id] = time.Time{}
 			}
 		}
 	}
@@ -237,13 +251,13 @@ func (s *Service) backgroundLoop() {
 	}
 }
 
-// hasContinuousQueries returns true if any CQs exist.
+// hasContinuousQueries returns true if any CQs or downsample rules exist.
 func (s *Service) hasContinuousQueries() bool {
 	// Get list of all databases.
 	dbs, _ := s.MetaClient.Databases()
 	// Loop through all databases executing CQs.
 	for _, db := range dbs {
-		if len(db.ContinuousQueries) > 0 {
+		if len(db.ContinuousQueries) > 0 || hasDownsamples(&db) {
 			return true
 		}
 	}
@@ -269,6 +283,11 @@ func (s *Service) runContinuousQueries(req *RunRequest) {
 			}
 		}
 	}
+
+	// Downsample rules run with every CQ unless specific CQs were requested.
+	if req.CQs == nil {
+		s.runDownsamples(req.Now)
+	}
 }
 
 // ExecuteContinuousQuery may execute a single CQ. This will return false if there were no errors and the CQ was not run.
@@ -288,11 +307,16 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 		now = now.In(cq.q.Location)
 	}
 
-	// Get the last time this CQ was run from the service's cache.
+	// Get the last time this CQ was run from the service's cache, or from the
+	// meta store if the CQ last ran before a restart or on another node.
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	id := fmt.Sprintf("%s%s%s", dbi.Name, idDelimiter, cqi.Name)
-	cq.LastRun, cq.HasRun = s.lastRuns[id]
+	lastRun, ok := s.lastRuns[id]
+	if !ok || (!lastRun.IsZero() && cqi.LastRun.After(lastRun)) {
+		lastRun = cqi.LastRun
+	}
+	cq.LastRun, cq.HasRun = lastRun, !lastRun.IsZero()
 
 	// Set the retention policy to default if it wasn't specified in the query.
 	if cq.intoRP() == "" {
@@ -321,6 +345,23 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 		return false, nil
 	}
 
+	// Intervals missed while no node ran the query are computed along with
+	// the current one, but only as far back as the catch-up limit.
+	if cq.HasRun && s.CatchUpLimit > 0 {
+		oldest := truncate(now.Add(-s.CatchUpLimit-offset), interval).Add(offset + interval)
+		if latest := truncate(now.Add(-offset), interval).Add(offset); oldest.After(latest) {
+			oldest = latest
+		}
+		if nextRun.Before(oldest) {
+			s.Logger.Info("Continuous query missed intervals beyond the catch-up limit",
+				zap.String("name", cqi.Name),
+				logger.Database(dbi.Name),
+				zap.Time("last_run", cq.LastRun),
+				zap.Time("catch_up_from", oldest.Add(-interval)))
+			nextRun = oldest
+		}
+	}
+
 	resampleEvery := interval
 	if cq.Resample.Every != 0 {
 		resampleEvery = cq.Resample.Every
@@ -328,6 +369,8 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 
 	// We're about to run the query so store the current time closest to the nearest interval.
 	// If all is going well, this time should be the same as nextRun.
+	prevLastRun, cached := s.lastRuns[id]
+	prevStoredRun := cq.LastRun
 	cq.LastRun = truncate(now.Add(-offset), resampleEvery).Add(offset)
 	s.lastRuns[id] = cq.LastRun
 
@@ -353,6 +396,7 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 	endTime := truncate(now.Add(interval-resampleEvery-offset), interval).Add(offset)
 	if !endTime.After(startTime) {
 		// Exit early since there is no time interval.
+		s.setContinuousQueryLastRun(dbi.Name, cqi.Name, cq.LastRun)
 		return false, nil
 	}
 
@@ -361,12 +405,9 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 	}
 
 	var (
-		start time.Time
+		start = time.Now()
 		log   = s.Logger
 	)
-	if s.loggingEnabled || s.queryStatsEnabled {
-		start = time.Now()
-	}
 
 	if s.loggingEnabled {
 		var logEnd func()
@@ -382,21 +423,41 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 
 	// Do the actual processing of the query & writing of results.
 	res := s.runContinuousQueryAndWriteResult(cq)
+	execDuration := time.Since(start)
+
+	// The last run is stored with the status so the query resumes from here
+	// after a restart or when another node acquires the lease.
+	status := meta.ContinuousQueryStatus{
+		NodeID:    s.MetaClient.NodeID(),
+		Time:      start,
+		Duration:  execDuration,
+		StartTime: startTime,
+		EndTime:   endTime,
+		LastRun:   cq.LastRun,
+	}
 	if res.Err != nil {
+		// Keep the previous last run so the failed intervals are computed
+		// again by the next run.
+		if cached {
+			s.lastRuns[id] = prevLastRun
+		} else {
+			delete(s.lastRuns, id)
+		}
+		cq.LastRun = prevStoredRun
+		status.LastRun = prevStoredRun
+		status.Err = res.Err.Error()
+		s.setContinuousQueryStatus(dbi.Name, cqi.Name, status)
 		return false, res.Err
 	}
 
-	var execDuration time.Duration
-	if s.loggingEnabled || s.queryStatsEnabled {
-		execDuration = time.Since(start)
-	}
-
 	// extract number of points written from SELECT ... INTO result
 	var written int64 = -1
 	if len(res.Series) == 1 && len(res.Series[0].Values) == 1 {
 		s := res.Series[0]
 		written = s.Values[0][1].(int64)
 	}
+	status.PointsWritten = written
+	s.setContinuousQueryStatus(dbi.Name, cqi.Name, status)
 
 	if s.loggingEnabled {
 		log.Info("Finished continuous query",
@@ -418,27 +479,31 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 	return true, nil
 }
 
-// runContinuousQueryAndWriteResult will run the query against the cluster and write the results back in
-func (s *Service) runContinuousQueryAndWriteResult(cq *ContinuousQuery) *query.Result {
-	// Wrap the CQ's inner SELECT statement in a Query for the Executor.
-	q := &influxql.Query{
-		Statements: influxql.Statements([]influxql.Statement{cq.q}),
+// setContinuousQueryLastRun stores the last run of a CQ that ran without
+// computing an interval in the meta store.
+func (s *Service) setContinuousQueryLastRun(database, name string, t time.Time) {
+	if err := s.MetaClient.SetContinuousQueryLastRun(database, name, t); err != nil {
+		s.Logger.Info("Unable to store continuous query last run",
+			zap.String("name", name),
+			logger.Database(database),
+			zap.Error(err))
 	}
+}
 
-	closing := make(chan struct{})
-	defer close(closing)
-
-	// Execute the SELECT.
-	ch := s.QueryExecutor.ExecuteQuery(q, query.ExecutionOptions{
-		Database: cq.Database,
-	}, closing)
-
-	// There is only one statement, so we will only ever receive one result
-	res, ok := <-ch
-	if !ok {
-		panic("result channel was closed")
+// setContinuousQueryStatus stores the outcome of an execution of a CQ and its
+// last run in the meta store so it can be shown from any node.
+func (s *Service) setContinuousQueryStatus(database, name string, status meta.ContinuousQueryStatus) {
+	if err := s.MetaClient.SetContinuousQueryStatus(database, name, status); err != nil {
+		s.Logger.Info("Unable to store continuous query status",
+			zap.String("name", name),
+			logger.Database(database),
+			zap.Error(err))
 	}
-	return res
+}
+
+// runContinuousQueryAndWriteResult will run the query against the cluster and write the results back in
+func (s *Service) runContinuousQueryAndWriteResult(cq *ContinuousQuery) *query.Result {
+	return s.executeStatement(cq.Database, cq.q)
 }
 
 // ContinuousQuery is a local wrapper / helper around continuous queries.
@@ -1,6 +1,7 @@
 package continuous_querier
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -340,7 +341,7 @@ func TestExecuteContinuousQuery_InvalidQueries(t *testing.T) {
 			return errUnexpected
 		},
 	}
-	dbis := s.MetaClient.Databases()
+	dbis, _ := s.MetaClient.Databases()
 	dbi := dbis[0]
 	cqi := dbi.ContinuousQueries[0]
 
@@ -588,6 +589,66 @@ func TestExecuteContinuousQuery_TimeZone(t *testing.T) {
 	}
 }
 
+// Test that a CQ resumes from the last run stored in the meta store and only
+// catches up on missed intervals within the catch-up limit.
+func TestExecuteContinuousQuery_CatchUp(t *testing.T) {
+	now := mustParseTime(t, "2000-01-01T03:00:00Z")
+	for _, tt := range []struct {
+		name    string
+		lastRun time.Time
+		start   time.Time
+	}{
+		{
+			name:    "WithinLimit",
+			lastRun: mustParseTime(t, "2000-01-01T02:20:00Z"),
+			start:   mustParseTime(t, "2000-01-01T02:20:00Z"),
+		},
+		{
+			name:    "BeyondLimit",
+			lastRun: mustParseTime(t, "2000-01-01T00:00:00Z"),
+			start:   mustParseTime(t, "2000-01-01T02:00:00Z"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewTestService(t)
+			s.CatchUpLimit = time.Hour
+			mc := NewMetaClient(t)
+			mc.CreateDatabase("db", "")
+			mc.CreateContinuousQuery("db", "cq", `CREATE CONTINUOUS QUERY cq ON db BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10m) END`)
+			mc.SetContinuousQueryLastRun("db", "cq", tt.lastRun)
+			s.MetaClient = mc
+
+			s.QueryExecutor.StatementExecutor = &StatementExecutor{
+				ExecuteStatementFn: func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+					s := stmt.(*influxql.SelectStatement)
+					valuer := &influxql.NowValuer{Location: s.Location}
+					_, timeRange, err := influxql.ConditionExpr(s.Condition, valuer)
+					if err != nil {
+						t.Errorf("unexpected error parsing time range: %s", err)
+					} else if !tt.start.Equal(timeRange.Min) || !now.Equal(timeRange.Max.Add(time.Nanosecond)) {
+						t.Errorf("mismatched time range: got=(%s, %s) exp=(%s, %s)", timeRange.Min, timeRange.Max, tt.start, now)
+					}
+					ctx.Results <- &query.Result{}
+					return nil
+				},
+			}
+
+			dbi := mc.Database("db")
+			cqi := dbi.ContinuousQueries[0]
+			if ok, err := s.ExecuteContinuousQuery(dbi, &cqi, now); err != nil {
+				t.Fatal(err)
+			} else if !ok {
+				t.Fatal("expected query to run")
+			}
+
+			// The new last run must be stored in the meta store.
+			if lastRun := mc.Database("db").ContinuousQueries[0].LastRun; !lastRun.Equal(now) {
+				t.Fatalf("unexpected last run: %s", lastRun)
+			}
+		})
+	}
+}
+
 // Test ExecuteContinuousQuery when QueryExecutor returns an error.
 func TestExecuteContinuousQuery_QueryExecutor_Error(t *testing.T) {
 	s := NewTestService(t)
@@ -597,7 +658,7 @@ func TestExecuteContinuousQuery_QueryExecutor_Error(t *testing.T) {
 		},
 	}
 
-	dbis := s.MetaClient.Databases()
+	dbis, _ := s.MetaClient.Databases()
 	dbi := dbis[0]
 	cqi := dbi.ContinuousQueries[0]
 
@@ -605,6 +666,93 @@ func TestExecuteContinuousQuery_QueryExecutor_Error(t *testing.T) {
 	if _, err := s.ExecuteContinuousQuery(&dbi, &cqi, now); err != errExpected {
 		t.Errorf("exp = %s, got = %v", errExpected, err)
 	}
+
+	// The error must be recorded in the meta store.
+	mc := s.MetaClient.(*MetaClient)
+	if cqi := mc.Database(dbi.Name).ContinuousQueries[0]; cqi.LastError != errExpected.Error() || cqi.LastNodeID != 1 {
+		t.Errorf("unexpected status: %+v", cqi)
+	}
+}
+
+// Ensure each execution of a CQ stores its last run and status in a single
+// meta store update, and a successful execution clears an earlier error.
+func TestExecuteContinuousQuery_Status(t *testing.T) {
+	s := NewTestService(t)
+	fail := true
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+			if fail {
+				return errExpected
+			}
+			return ctx.Send(&query.Result{})
+		},
+	}
+
+	mc := s.MetaClient.(*MetaClient)
+	now := mustParseTime(t, "2000-01-01T00:00:00Z")
+	for i, tt := range []struct {
+		fail bool
+		err  string
+	}{
+		{fail: true, err: errExpected.Error()},
+		{fail: false, err: ""},
+	} {
+		fail = tt.fail
+		now = now.Add(time.Second)
+
+		dbi := *mc.Database("db")
+		cqi := dbi.ContinuousQueries[0]
+		if _, err := s.ExecuteContinuousQuery(&dbi, &cqi, now); tt.fail != (err != nil) {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+
+		// A failed execution keeps the previous last run.
+		lastRun := now
+		if tt.fail {
+			lastRun = cqi.LastRun
+		}
+		if mc.StatusN != i+1 || mc.LastRunN != 0 {
+			t.Fatalf("%d: unexpected meta updates: status=%d last run=%d", i, mc.StatusN, mc.LastRunN)
+		} else if cqi := mc.Database("db").ContinuousQueries[0]; !cqi.LastRun.Equal(lastRun) || cqi.LastError != tt.err {
+			t.Fatalf("%d: unexpected status: %+v", i, cqi)
+		}
+	}
+}
+
+// Ensure the intervals of a failed execution of a CQ are computed again by
+// the next run, since its last run is not advanced.
+func TestExecuteContinuousQuery_ErrorKeepsLastRun(t *testing.T) {
+	s := NewTestService(t)
+	var n int
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+			if n++; n == 1 {
+				return errExpected
+			}
+			return ctx.Send(&query.Result{})
+		},
+	}
+
+	mc := s.MetaClient.(*MetaClient)
+	now := mustParseTime(t, "2000-01-01T00:00:00Z")
+	dbi := *mc.Database("db")
+	cqi := dbi.ContinuousQueries[0]
+	if _, err := s.ExecuteContinuousQuery(&dbi, &cqi, now); err != errExpected {
+		t.Fatalf("exp = %s, got = %v", errExpected, err)
+	} else if lastRun := mc.Database("db").ContinuousQueries[0].LastRun; !lastRun.Equal(cqi.LastRun) {
+		t.Fatalf("unexpected last run: %s", lastRun)
+	}
+
+	// The query must run again at the same time.
+	dbi = *mc.Database("db")
+	cqi = dbi.ContinuousQueries[0]
+	if ok, err := s.ExecuteContinuousQuery(&dbi, &cqi, now); err != nil {
+		t.Fatal(err)
+	} else if !ok || n != 2 {
+		t.Fatalf("expected query to run again: ok=%v n=%d", ok, n)
+	} else if lastRun := mc.Database("db").ContinuousQueries[0].LastRun; !lastRun.Equal(now) {
+		t.Fatalf("unexpected last run: %s", lastRun)
+	}
 }
 
 func TestService_ExecuteContinuousQuery_LogsToMonitor(t *testing.T) {
@@ -636,7 +784,7 @@ func TestService_ExecuteContinuousQuery_LogsToMonitor(t *testing.T) {
 		},
 	}
 
-	dbis := s.MetaClient.Databases()
+	dbis, _ := s.MetaClient.Databases()
 	dbi := dbis[0]
 	cqi := dbi.ContinuousQueries[0]
 
@@ -671,7 +819,7 @@ func TestService_ExecuteContinuousQuery_LogToMonitor_DisabledByDefault(t *testin
 		},
 	}
 
-	dbis := s.MetaClient.Databases()
+	dbis, _ := s.MetaClient.Databases()
 	dbi := dbis[0]
 	cqi := dbi.ContinuousQueries[0]
 
@@ -714,6 +862,10 @@ type MetaClient struct {
 	Err           error
 	t             *testing.T
 	nodeID        uint64
+
+	// LastRunN and StatusN count the calls storing the last run and the
+	// status of a CQ.
+	LastRunN, StatusN int
 }
 
 // NewMetaClient returns a *MetaClient.
@@ -744,7 +896,7 @@ func (ms *MetaClient) AcquireLease(name string) (l *meta.Lease, err error) {
 func (ms *MetaClient) Databases() ([]meta.DatabaseInfo, error) {
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
-	return ms.DatabaseInfos
+	return ms.DatabaseInfos, nil
 }
 
 // Database returns a single database by name.
@@ -820,6 +972,85 @@ func (ms *MetaClient) CreateContinuousQuery(database, name, query string) error
 	return nil
 }
 
+// SetContinuousQueryLastRun records the last run of a CQ.
+func (ms *MetaClient) SetContinuousQueryLastRun(database, name string, t time.Time) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.Err != nil {
+		return ms.Err
+	}
+
+	dbi := ms.database(database)
+	if dbi == nil {
+		return fmt.Errorf("database not found: %s", database)
+	}
+	for i := range dbi.ContinuousQueries {
+		if dbi.ContinuousQueries[i].Name == name {
+			ms.LastRunN++
+			dbi.ContinuousQueries[i].LastRun = t
+			return nil
+		}
+	}
+	return fmt.Errorf("continuous query not found: %s", name)
+}
+
+// SetContinuousQueryStatus records the outcome of an execution of a CQ.
+func (ms *MetaClient) SetContinuousQueryStatus(database, name string, status meta.ContinuousQueryStatus) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.Err != nil {
+		return ms.Err
+	}
+
+	dbi := ms.database(database)
+	if dbi == nil {
+		return fmt.Errorf("database not found: %s", database)
+	}
+	for i := range dbi.ContinuousQueries {
+		cqi := &dbi.ContinuousQueries[i]
+		if cqi.Name == name {
+			ms.StatusN++
+			cqi.LastRun = status.LastRun
+			cqi.LastNodeID = status.NodeID
+			cqi.LastDuration = status.Duration
+			if status.Err != "" {
+				cqi.LastError, cqi.LastErrorTime = status.Err, status.Time
+			} else {
+				cqi.LastPointsWritten = status.PointsWritten
+				cqi.LastSuccessStart, cqi.LastSuccessEnd = status.StartTime, status.EndTime
+				cqi.LastError, cqi.LastErrorTime = "", time.Time{}
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("continuous query not found: %s", name)
+}
+
+// SetDownsampleLastRun records the last run of a downsample rule.
+func (ms *MetaClient) SetDownsampleLastRun(database, rp, target string, t time.Time) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.Err != nil {
+		return ms.Err
+	}
+
+	dbi := ms.database(database)
+	if dbi == nil {
+		return fmt.Errorf("database not found: %s", database)
+	}
+	for i := range dbi.RetentionPolicies {
+		if rpi := &dbi.RetentionPolicies[i]; rpi.Name == rp {
+			for j := range rpi.Downsamples {
+				if rpi.Downsamples[j].Target == target {
+					rpi.Downsamples[j].LastRun = t
+					return nil
+				}
+			}
+		}
+	}
+	return fmt.Errorf("downsample not found: %s.%s", rp, target)
+}
+
 // StatementExecutor is a mock statement executor.
 type StatementExecutor struct {
 	ExecuteStatementFn func(stmt influxql.Statement, ctx *query.ExecutionContext) error
@@ -829,6 +1060,38 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 	return e.ExecuteStatementFn(stmt, ctx)
 }
 
+// ShardMapper is a mock shard mapper.
+type ShardMapper struct {
+	MapShardsFn func(sources influxql.Sources, t influxql.TimeRange) query.ShardGroup
+}
+
+func (m *ShardMapper) MapShards(sources influxql.Sources, t influxql.TimeRange, opt query.SelectOptions) (query.ShardGroup, error) {
+	return m.MapShardsFn(sources, t), nil
+}
+
+// ShardGroup is a mock shard group returning the fields of each measurement.
+type ShardGroup struct {
+	FieldDimensionsFn func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error)
+}
+
+func (sg *ShardGroup) CreateIterator(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+	return nil, nil
+}
+
+func (sg *ShardGroup) IteratorCost(m *influxql.Measurement, opt query.IteratorOptions) (query.IteratorCost, error) {
+	return query.IteratorCost{}, nil
+}
+
+func (sg *ShardGroup) FieldDimensions(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+	return sg.FieldDimensionsFn(m)
+}
+
+func (sg *ShardGroup) MapType(m *influxql.Measurement, field string) influxql.DataType {
+	return influxql.Unknown
+}
+
+func (sg *ShardGroup) Close() error { return nil }
+
 func wait(c chan struct{}, d time.Duration) (err error) {
 	select {
 	case <-c:
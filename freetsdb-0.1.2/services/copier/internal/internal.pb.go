@@ -23,15 +23,59 @@ var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
+type Request_Type int32
+
+const (
+	Request_ShardReaderRequest Request_Type = 1
+	Request_CopyShardRequest   Request_Type = 2
+	Request_RemoveShardRequest Request_Type = 3
+	Request_ShardSizeRequest   Request_Type = 4
+)
+
+var Request_Type_name = map[int32]string{
+	1: "ShardReaderRequest",
+	2: "CopyShardRequest",
+	3: "RemoveShardRequest",
+	4: "ShardSizeRequest",
+}
+var Request_Type_value = map[string]int32{
+	"ShardReaderRequest": 1,
+	"CopyShardRequest":   2,
+	"RemoveShardRequest": 3,
+	"ShardSizeRequest":   4,
+}
+
+func (x Request_Type) Enum() *Request_Type {
+	p := new(Request_Type)
+	*p = x
+	return p
+}
+func (x Request_Type) String() string {
+	return proto.EnumName(Request_Type_name, int32(x))
+}
+func (x *Request_Type) UnmarshalJSON(data []byte) error {
+	value, err := proto.UnmarshalJSONEnum(Request_Type_value, data, "Request_Type")
+	if err != nil {
+		return err
+	}
+	*x = Request_Type(value)
+	return nil
+}
+
 type Request struct {
-	ShardID          *uint64 `protobuf:"varint,1,req,name=ShardID" json:"ShardID,omitempty"`
-	XXX_unrecognized []byte  `json:"-"`
+	ShardID          *uint64       `protobuf:"varint,1,req,name=ShardID" json:"ShardID,omitempty"`
+	Type             *Request_Type `protobuf:"varint,2,opt,name=Type,enum=internal.Request_Type,def=1" json:"Type,omitempty"`
+	Source           *string       `protobuf:"bytes,3,opt,name=Source" json:"Source,omitempty"`
+	Compact          *bool         `protobuf:"varint,4,opt,name=Compact" json:"Compact,omitempty"`
+	XXX_unrecognized []byte        `json:"-"`
 }
 
 func (m *Request) Reset()         { *m = Request{} }
 func (m *Request) String() string { return proto.CompactTextString(m) }
 func (*Request) ProtoMessage()    {}
 
+const Default_Request_Type Request_Type = Request_ShardReaderRequest
+
 func (m *Request) GetShardID() uint64 {
 	if m != nil && m.ShardID != nil {
 		return *m.ShardID
@@ -39,8 +83,30 @@ func (m *Request) GetShardID() uint64 {
 	return 0
 }
 
+func (m *Request) GetType() Request_Type {
+	if m != nil && m.Type != nil {
+		return *m.Type
+	}
+	return Default_Request_Type
+}
+
+func (m *Request) GetSource() string {
+	if m != nil && m.Source != nil {
+		return *m.Source
+	}
+	return ""
+}
+
+func (m *Request) GetCompact() bool {
+	if m != nil && m.Compact != nil {
+		return *m.Compact
+	}
+	return false
+}
+
 type Response struct {
 	Error            *string `protobuf:"bytes,1,opt,name=Error" json:"Error,omitempty"`
+	Size             *int64  `protobuf:"varint,2,opt,name=Size" json:"Size,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -54,3 +120,14 @@ func (m *Response) GetError() string {
 	}
 	return ""
 }
+
+func (m *Response) GetSize() int64 {
+	if m != nil && m.Size != nil {
+		return *m.Size
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("internal.Request_Type", Request_Type_name, Request_Type_value)
+}
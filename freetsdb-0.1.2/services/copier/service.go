@@ -8,9 +8,11 @@ import (
 	"net"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/freetsdb/freetsdb/services/copier/internal"
+	"github.com/freetsdb/freetsdb/services/meta"
 	"github.com/freetsdb/freetsdb/tcp"
 	"github.com/freetsdb/freetsdb/tsdb"
 	"go.uber.org/zap"
@@ -21,15 +23,30 @@ import (
 // MuxHeader is the header byte used for the TCP muxer.
 const MuxHeader = 6
 
+// CompactTimeout is how long a shard reader request waits for a shard to
+// be fully compacted before giving up.
+var CompactTimeout = 30 * time.Minute
+
 // Service manages the listener for the endpoint.
 type Service struct {
 	wg  sync.WaitGroup
 	err chan error
 
+	MetaClient interface {
+		ShardOwner(shardID uint64) (database, policy string, sgi *meta.ShardGroupInfo)
+	}
+
 	TSDBStore interface {
 		Shard(id uint64) *tsdb.Shard
+		CreateShard(database, retentionPolicy string, shardID uint64, enabled bool) error
+		DeleteShard(shardID uint64) error
+		BackupShard(id uint64, since time.Time, w io.Writer) error
+		ImportShard(id uint64, r io.Reader) error
 	}
 
+	// Dialer connects to the copier service of the source node.
+	Dialer *tcp.Dialer
+
 	Listener net.Listener
 	Logger   *zap.Logger
 }
@@ -103,6 +120,20 @@ func (s *Service) handleConn(conn net.Conn) error {
 		return fmt.Errorf("read request: %s", err)
 	}
 
+	switch req.GetType() {
+	case internal.Request_CopyShardRequest:
+		return s.handleCopyShard(conn, req)
+	case internal.Request_RemoveShardRequest:
+		return s.handleRemoveShard(conn, req)
+	case internal.Request_ShardSizeRequest:
+		return s.handleShardSize(conn, req)
+	default:
+		return s.handleShardReader(conn, req)
+	}
+}
+
+// handleShardReader streams the contents of a local shard to conn.
+func (s *Service) handleShardReader(conn net.Conn, req *internal.Request) error {
 	// Retrieve shard.
 	sh := s.TSDBStore.Shard(req.GetShardID())
 
@@ -116,19 +147,128 @@ func (s *Service) handleConn(conn net.Conn) error {
 		return nil
 	}
 
+	// Fully compact the shard first if requested.
+	if req.GetCompact() {
+		if err := s.compactShard(sh); err != nil {
+			if err := s.writeResponse(conn, &internal.Response{
+				Error: proto.String(fmt.Sprintf("compact shard: id=%d: %s", req.GetShardID(), err)),
+			}); err != nil {
+				return fmt.Errorf("write error response: %s", err)
+			}
+			return nil
+		}
+	}
+
 	// Write successful response.
 	if err := s.writeResponse(conn, &internal.Response{}); err != nil {
 		return fmt.Errorf("write response: %s", err)
 	}
 
 	// Write shard to response.
-	if _, err := sh.WriteTo(conn); err != nil {
+	if err := s.TSDBStore.BackupShard(req.GetShardID(), time.Time{}, conn); err != nil {
 		return fmt.Errorf("write shard: %s", err)
 	}
 
 	return nil
 }
 
+// compactShard schedules a full compaction of sh and waits until the shard
+// is idle.
+func (s *Service) compactShard(sh *tsdb.Shard) error {
+	if err := sh.ScheduleFullCompaction(); err != nil {
+		return err
+	}
+
+	timeout := time.After(CompactTimeout)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for !sh.IsIdle() {
+		select {
+		case <-ticker.C:
+		case <-timeout:
+			return errors.New("timed out waiting for full compaction")
+		}
+	}
+	return nil
+}
+
+// handleCopyShard pulls a shard from the source node in the request and
+// imports it into the local store.
+func (s *Service) handleCopyShard(conn net.Conn, req *internal.Request) error {
+	var resp internal.Response
+	if err := s.copyShard(req.GetShardID(), req.GetSource()); err != nil {
+		resp.Error = proto.String(err.Error())
+	}
+
+	if err := s.writeResponse(conn, &resp); err != nil {
+		return fmt.Errorf("write response: %s", err)
+	}
+	return nil
+}
+
+// copyShard creates the shard locally and imports its data from src.
+func (s *Service) copyShard(id uint64, src string) error {
+	if s.TSDBStore.Shard(id) != nil {
+		return fmt.Errorf("shard already exists: id=%d", id)
+	}
+
+	database, policy, sgi := s.MetaClient.ShardOwner(id)
+	if sgi == nil {
+		return fmt.Errorf("shard not found: id=%d", id)
+	}
+
+	s.Logger.Info("Copying shard",
+		zap.Uint64("id", id),
+		zap.String("source", src))
+
+	r, err := NewClient(src, s.Dialer).ShardReader(id)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if err := s.TSDBStore.CreateShard(database, policy, id, true); err != nil {
+		return err
+	}
+
+	if err := s.TSDBStore.ImportShard(id, r); err != nil {
+		s.TSDBStore.DeleteShard(id)
+		return err
+	}
+	return nil
+}
+
+// handleRemoveShard deletes a shard from the local store.
+func (s *Service) handleRemoveShard(conn net.Conn, req *internal.Request) error {
+	var resp internal.Response
+	if err := s.TSDBStore.DeleteShard(req.GetShardID()); err != nil {
+		resp.Error = proto.String(err.Error())
+	}
+
+	if err := s.writeResponse(conn, &resp); err != nil {
+		return fmt.Errorf("write response: %s", err)
+	}
+	return nil
+}
+
+// handleShardSize returns the on-disk size of a local shard.
+func (s *Service) handleShardSize(conn net.Conn, req *internal.Request) error {
+	var resp internal.Response
+	if sh := s.TSDBStore.Shard(req.GetShardID()); sh == nil {
+		resp.Error = proto.String(fmt.Sprintf("shard not found: id=%d", req.GetShardID()))
+	} else if size, err := sh.DiskSize(); err != nil {
+		resp.Error = proto.String(err.Error())
+	} else {
+		resp.Size = proto.Int64(size)
+	}
+
+	if err := s.writeResponse(conn, &resp); err != nil {
+		return fmt.Errorf("write response: %s", err)
+	}
+	return nil
+}
+
 // readRequest reads and unmarshals a Request from r.
 func (s *Service) readRequest(r io.Reader) (*internal.Request, error) {
 	// Read request length.
@@ -175,27 +315,45 @@ func (s *Service) writeResponse(w io.Writer, resp *internal.Response) error {
 
 // Client represents a client for connecting remotely to a copier service.
 type Client struct {
-	host string
+	host   string
+	dialer *tcp.Dialer
 }
 
-// NewClient return a new instance of Client.
-func NewClient(host string) *Client {
+// NewClient return a new instance of Client connecting with dialer.
+func NewClient(host string, dialer *tcp.Dialer) *Client {
 	return &Client{
-		host: host,
+		host:   host,
+		dialer: dialer,
 	}
 }
 
 // ShardReader returns a reader for streaming shard data.
 // Returned ReadCloser must be closed by the caller.
 func (c *Client) ShardReader(id uint64) (io.ReadCloser, error) {
+	return c.shardReader(&internal.Request{ShardID: proto.Uint64(id)})
+}
+
+// CompactedShardReader is like ShardReader but has the remote server fully
+// compact the shard before streaming it.
+func (c *Client) CompactedShardReader(id uint64) (io.ReadCloser, error) {
+	return c.shardReader(&internal.Request{
+		ShardID: proto.Uint64(id),
+		Compact: proto.Bool(true),
+	})
+}
+
+// shardReader sends req to the remote server and returns the stream of
+// shard data that follows a successful response.
+func (c *Client) shardReader(req *internal.Request) (io.ReadCloser, error) {
 	// Connect to remote server.
-	conn, err := tcp.Dial("tcp", c.host, MuxHeader)
+	conn, err := c.dialer.Dial("tcp", c.host, MuxHeader)
 	if err != nil {
 		return nil, err
 	}
 
 	// Send request to server.
-	if err := c.writeRequest(conn, &internal.Request{ShardID: proto.Uint64(id)}); err != nil {
+	if err := c.writeRequest(conn, req); err != nil {
+		conn.Close()
 		return nil, fmt.Errorf("write request: %s", err)
 	}
 
@@ -215,6 +373,61 @@ func (c *Client) ShardReader(id uint64) (io.ReadCloser, error) {
 	return conn, nil
 }
 
+// CopyShard asks the remote server to copy a shard from the src node and
+// blocks until the copy has completed.
+func (c *Client) CopyShard(id uint64, src string) error {
+	_, err := c.exec(&internal.Request{
+		ShardID: proto.Uint64(id),
+		Type:    internal.Request_CopyShardRequest.Enum(),
+		Source:  proto.String(src),
+	})
+	return err
+}
+
+// RemoveShard asks the remote server to delete a local shard.
+func (c *Client) RemoveShard(id uint64) error {
+	_, err := c.exec(&internal.Request{
+		ShardID: proto.Uint64(id),
+		Type:    internal.Request_RemoveShardRequest.Enum(),
+	})
+	return err
+}
+
+// ShardSize returns the on-disk size of a shard on the remote server.
+func (c *Client) ShardSize(id uint64) (int64, error) {
+	resp, err := c.exec(&internal.Request{
+		ShardID: proto.Uint64(id),
+		Type:    internal.Request_ShardSizeRequest.Enum(),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return resp.GetSize(), nil
+}
+
+// exec sends req to the remote server and waits for its response.
+func (c *Client) exec(req *internal.Request) (*internal.Response, error) {
+	conn, err := c.dialer.Dial("tcp", c.host, MuxHeader)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := c.writeRequest(conn, req); err != nil {
+		return nil, fmt.Errorf("write request: %s", err)
+	}
+
+	resp, err := c.readResponse(conn)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %s", err)
+	}
+
+	if resp.GetError() != "" {
+		return nil, errors.New(resp.GetError())
+	}
+	return resp, nil
+}
+
 // writeRequest marshals and writes req to w.
 func (c *Client) writeRequest(w io.Writer, req *internal.Request) error {
 	// Marshal request.
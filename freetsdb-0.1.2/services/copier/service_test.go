@@ -2,24 +2,26 @@ package copier_test
 
 import (
 	"bytes"
-	"encoding/binary"
+	"errors"
 	"io"
 	"io/ioutil"
-	"log"
 	"net"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/freetsdb/freetsdb/services/copier"
+	"github.com/freetsdb/freetsdb/services/meta"
 	"github.com/freetsdb/freetsdb/tcp"
 	"github.com/freetsdb/freetsdb/tsdb"
 	_ "github.com/freetsdb/freetsdb/tsdb/engine"
+	_ "github.com/freetsdb/freetsdb/tsdb/index"
+	"github.com/freetsdb/freetsdb/tsdb/index/inmem"
 )
 
 // Ensure the service can return shard data.
 func TestService_handleConn(t *testing.T) {
-	t.Skip("not implemented for tsm1 engine")
 	s := MustOpenService()
 	defer s.Close()
 
@@ -32,9 +34,16 @@ func TestService_handleConn(t *testing.T) {
 		}
 		return sh.Shard
 	}
+	s.TSDBStore.BackupShardFn = func(id uint64, since time.Time, w io.Writer) error {
+		if id != 123 || !since.IsZero() {
+			t.Fatalf("unexpected backup: id=%d since=%s", id, since)
+		}
+		_, err := w.Write([]byte("shard data"))
+		return err
+	}
 
 	// Create client and request shard from service.
-	c := copier.NewClient(s.Addr().String())
+	c := copier.NewClient(s.Addr().String(), nil)
 	r, err := c.ShardReader(123)
 	if err != nil {
 		t.Fatal(err)
@@ -43,28 +52,12 @@ func TestService_handleConn(t *testing.T) {
 	}
 	defer r.Close()
 
-	// Slurp from reader.
-	var n uint64
-	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
-		t.Fatal(err)
-	}
-	buf := make([]byte, n)
-	if _, err := io.ReadFull(r, buf); err != nil {
-		t.Fatal(err)
-	}
-
-	// Read database from disk.
-	exp, err := ioutil.ReadFile(sh.Path())
+	// Compare the backup and reader contents.
+	buf, err := ioutil.ReadAll(r)
 	if err != nil {
 		t.Fatal(err)
-	}
-
-	// Trim expected bytes since bolt won't read beyond the HWM.
-	exp = exp[0:len(buf)]
-
-	// Compare disk and reader contents.
-	if !bytes.Equal(exp, buf) {
-		t.Fatalf("data mismatch: exp=len(%d), got=len(%d)", len(exp), len(buf))
+	} else if !bytes.Equal(buf, []byte("shard data")) {
+		t.Fatalf("data mismatch: %q", buf)
 	}
 }
 
@@ -77,7 +70,7 @@ func TestService_handleConn_Error(t *testing.T) {
 	s.TSDBStore.ShardFn = func(id uint64) *tsdb.Shard { return nil }
 
 	// Create client and request shard from service.
-	c := copier.NewClient(s.Addr().String())
+	c := copier.NewClient(s.Addr().String(), nil)
 	r, err := c.ShardReader(123)
 	if err == nil || err.Error() != `shard not found: id=123` {
 		t.Fatalf("unexpected error: %s", err)
@@ -86,12 +79,130 @@ func TestService_handleConn_Error(t *testing.T) {
 	}
 }
 
+// Ensure the service copies a shard from another node into its store.
+func TestService_CopyShard(t *testing.T) {
+	src := MustOpenService()
+	defer src.Close()
+	src.TSDBStore.ShardFn = func(id uint64) *tsdb.Shard { return &tsdb.Shard{} }
+	src.TSDBStore.BackupShardFn = func(id uint64, since time.Time, w io.Writer) error {
+		_, err := w.Write([]byte("shard data"))
+		return err
+	}
+
+	dst := MustOpenService()
+	defer dst.Close()
+	dst.MetaClient.ShardOwnerFn = func(id uint64) (string, string, *meta.ShardGroupInfo) {
+		return "db", "rp", &meta.ShardGroupInfo{ID: 1}
+	}
+	dst.TSDBStore.ShardFn = func(id uint64) *tsdb.Shard { return nil }
+	var created bool
+	dst.TSDBStore.CreateShardFn = func(database, policy string, id uint64, enabled bool) error {
+		if database != "db" || policy != "rp" || id != 123 {
+			t.Fatalf("unexpected shard: %s.%s %d", database, policy, id)
+		}
+		created = true
+		return nil
+	}
+	var imported []byte
+	dst.TSDBStore.ImportShardFn = func(id uint64, r io.Reader) error {
+		var err error
+		imported, err = ioutil.ReadAll(r)
+		return err
+	}
+
+	if err := copier.NewClient(dst.Addr().String(), nil).CopyShard(123, src.Addr().String()); err != nil {
+		t.Fatal(err)
+	} else if !created {
+		t.Fatal("expected shard to be created")
+	} else if string(imported) != "shard data" {
+		t.Fatalf("unexpected imported data: %q", imported)
+	}
+}
+
+// Ensure a failed import removes the shard created for the copy.
+func TestService_CopyShard_ImportError(t *testing.T) {
+	src := MustOpenService()
+	defer src.Close()
+	src.TSDBStore.ShardFn = func(id uint64) *tsdb.Shard { return &tsdb.Shard{} }
+	src.TSDBStore.BackupShardFn = func(id uint64, since time.Time, w io.Writer) error { return nil }
+
+	dst := MustOpenService()
+	defer dst.Close()
+	dst.MetaClient.ShardOwnerFn = func(id uint64) (string, string, *meta.ShardGroupInfo) {
+		return "db", "rp", &meta.ShardGroupInfo{ID: 1}
+	}
+	dst.TSDBStore.ShardFn = func(id uint64) *tsdb.Shard { return nil }
+	dst.TSDBStore.CreateShardFn = func(database, policy string, id uint64, enabled bool) error { return nil }
+	dst.TSDBStore.ImportShardFn = func(id uint64, r io.Reader) error { return errors.New("disk full") }
+	var deleted bool
+	dst.TSDBStore.DeleteShardFn = func(id uint64) error {
+		deleted = true
+		return nil
+	}
+
+	err := copier.NewClient(dst.Addr().String(), nil).CopyShard(123, src.Addr().String())
+	if err == nil || err.Error() != "disk full" {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !deleted {
+		t.Fatal("expected shard to be deleted")
+	}
+}
+
+// Ensure the service removes a shard from its store.
+func TestService_RemoveShard(t *testing.T) {
+	s := MustOpenService()
+	defer s.Close()
+
+	var deleted uint64
+	s.TSDBStore.DeleteShardFn = func(id uint64) error {
+		deleted = id
+		return nil
+	}
+
+	if err := copier.NewClient(s.Addr().String(), nil).RemoveShard(123); err != nil {
+		t.Fatal(err)
+	} else if deleted != 123 {
+		t.Fatalf("unexpected deleted shard: %d", deleted)
+	}
+}
+
+// Ensure the service returns the size of a shard.
+func TestService_ShardSize(t *testing.T) {
+	s := MustOpenService()
+	defer s.Close()
+
+	sh := MustOpenShard(123)
+	defer sh.Close()
+	s.TSDBStore.ShardFn = func(id uint64) *tsdb.Shard {
+		if id != 123 {
+			return nil
+		}
+		return sh.Shard
+	}
+
+	exp, err := sh.DiskSize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := copier.NewClient(s.Addr().String(), nil)
+	if size, err := c.ShardSize(123); err != nil {
+		t.Fatal(err)
+	} else if size != exp {
+		t.Fatalf("unexpected size: got %d, exp %d", size, exp)
+	}
+
+	if _, err := c.ShardSize(124); err == nil || err.Error() != "shard not found: id=124" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 // Service represents a test wrapper for copier.Service.
 type Service struct {
 	*copier.Service
 
-	ln        net.Listener
-	TSDBStore ServiceTSDBStore
+	ln         net.Listener
+	MetaClient ServiceMetaClient
+	TSDBStore  ServiceTSDBStore
 }
 
 // NewService returns a new instance of Service.
@@ -99,6 +210,7 @@ func NewService() *Service {
 	s := &Service{
 		Service: copier.NewService(),
 	}
+	s.Service.MetaClient = &s.MetaClient
 	s.Service.TSDBStore = &s.TSDBStore
 
 	return s
@@ -138,17 +250,45 @@ func (s *Service) Close() error {
 // Addr returns the address of the service.
 func (s *Service) Addr() net.Addr { return s.ln.Addr() }
 
+// ServiceMetaClient is a mock that implements copier.Service.MetaClient.
+type ServiceMetaClient struct {
+	ShardOwnerFn func(id uint64) (string, string, *meta.ShardGroupInfo)
+}
+
+func (c *ServiceMetaClient) ShardOwner(id uint64) (string, string, *meta.ShardGroupInfo) {
+	return c.ShardOwnerFn(id)
+}
+
 // ServiceTSDBStore is a mock that implements copier.Service.TSDBStore.
 type ServiceTSDBStore struct {
-	ShardFn func(id uint64) *tsdb.Shard
+	ShardFn       func(id uint64) *tsdb.Shard
+	CreateShardFn func(database, policy string, shardID uint64, enabled bool) error
+	DeleteShardFn func(shardID uint64) error
+	BackupShardFn func(id uint64, since time.Time, w io.Writer) error
+	ImportShardFn func(id uint64, r io.Reader) error
 }
 
 func (ss *ServiceTSDBStore) Shard(id uint64) *tsdb.Shard { return ss.ShardFn(id) }
 
+func (ss *ServiceTSDBStore) CreateShard(database, policy string, shardID uint64, enabled bool) error {
+	return ss.CreateShardFn(database, policy, shardID, enabled)
+}
+
+func (ss *ServiceTSDBStore) DeleteShard(shardID uint64) error { return ss.DeleteShardFn(shardID) }
+
+func (ss *ServiceTSDBStore) BackupShard(id uint64, since time.Time, w io.Writer) error {
+	return ss.BackupShardFn(id, since, w)
+}
+
+func (ss *ServiceTSDBStore) ImportShard(id uint64, r io.Reader) error {
+	return ss.ImportShardFn(id, r)
+}
+
 // Shard is a test wrapper for tsdb.Shard.
 type Shard struct {
 	*tsdb.Shard
-	path string
+	sfile *tsdb.SeriesFile
+	path  string
 }
 
 // MustOpenShard returns a temporary, opened shard.
@@ -158,14 +298,25 @@ func MustOpenShard(id uint64) *Shard {
 		panic(err)
 	}
 
+	sfile := tsdb.NewSeriesFile(filepath.Join(path, "series"))
+	if err := sfile.Open(); err != nil {
+		os.RemoveAll(path)
+		panic(err)
+	}
+
+	opt := tsdb.NewEngineOptions()
+	opt.Config.WALDir = filepath.Join(path, "wal")
+	opt.InmemIndex = inmem.NewIndex("db", sfile)
+
 	sh := &Shard{
 		Shard: tsdb.NewShard(id,
-			tsdb.NewDatabaseIndex("db"),
 			filepath.Join(path, "data"),
 			filepath.Join(path, "wal"),
-			tsdb.NewEngineOptions(),
+			sfile,
+			opt,
 		),
-		path: path,
+		sfile: sfile,
+		path:  path,
 	}
 	if err := sh.Open(); err != nil {
 		sh.Close()
@@ -177,6 +328,7 @@ func MustOpenShard(id uint64) *Shard {
 
 func (sh *Shard) Close() error {
 	err := sh.Shard.Close()
-	os.RemoveAll(sh.Path())
+	sh.sfile.Close()
+	os.RemoveAll(sh.path)
 	return err
 }
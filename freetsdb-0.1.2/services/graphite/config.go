@@ -21,7 +21,9 @@ const (
 	DefaultProtocol = "tcp"
 
 	// DefaultConsistencyLevel is the default write consistency for the Graphite input.
-	DefaultConsistencyLevel = "one"
+	// An empty level uses the consistency level of the retention policy or
+	// database, or any if neither sets one.
+	DefaultConsistencyLevel = ""
 
 	// DefaultSeparator is the default join character to use when joining multiple
 	// measurement parts in a template.
@@ -103,9 +105,6 @@ func (c *Config) WithDefaults() *Config {
 	if d.BatchTimeout == 0 {
 		d.BatchTimeout = toml.Duration(DefaultBatchTimeout)
 	}
-	if d.ConsistencyLevel == "" {
-		d.ConsistencyLevel = DefaultConsistencyLevel
-	}
 	if d.Separator == "" {
 		d.Separator = DefaultSeparator
 	}
@@ -55,6 +55,8 @@ type Service struct {
 	batchTimeout    time.Duration
 	udpReadBuffer   int
 
+	consistencyLevel coordinator.ConsistencyLevel
+
 	batcher *tsdb.PointBatcher
 	parser  *Parser
 
@@ -111,6 +113,16 @@ func NewService(c Config) (*Service, error) {
 		defaultTags:     models.StatisticTags{"proto": d.Protocol, "bind": d.BindAddress},
 		tcpConnections:  make(map[string]*tcpConnection),
 		diagsKey:        strings.Join([]string{"graphite", d.Protocol, d.BindAddress}, ":"),
+
+		consistencyLevel: coordinator.ConsistencyLevelDefaultAny,
+	}
+
+	if d.ConsistencyLevel != "" {
+		level, err := coordinator.ParseConsistencyLevel(d.ConsistencyLevel)
+		if err != nil {
+			return nil, err
+		}
+		s.consistencyLevel = level
 	}
 
 	parser, err := NewParserWithOptions(Options{
@@ -464,7 +476,7 @@ func (s *Service) processBatches(batcher *tsdb.PointBatcher) {
 				continue
 			}
 
-			if err := s.PointsWriter.WritePointsPrivileged(s.database, s.retentionPolicy, coordinator.ConsistencyLevelAny, batch); err == nil {
+			if err := s.PointsWriter.WritePointsPrivileged(s.database, s.retentionPolicy, s.consistencyLevel, batch); err == nil {
 				atomic.AddInt64(&s.stats.BatchesTransmitted, 1)
 				atomic.AddInt64(&s.stats.PointsTransmitted, int64(len(batch)))
 			} else {
@@ -0,0 +1,55 @@
+package heartbeat
+
+import (
+	"errors"
+	"time"
+
+	"github.com/freetsdb/freetsdb/monitor/diagnostics"
+	"github.com/freetsdb/freetsdb/toml"
+)
+
+const (
+	// DefaultInterval is the default time between two heartbeats. It should
+	// be well below the data-node-timeout of the meta nodes.
+	DefaultInterval = 10 * time.Second
+)
+
+// Config represents the configuration for the heartbeat service.
+type Config struct {
+	Enabled  bool          `toml:"enabled"`
+	Interval toml.Duration `toml:"interval"`
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		Enabled:  true,
+		Interval: toml.Duration(DefaultInterval),
+	}
+}
+
+// Validate returns an error if the Config is invalid.
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+	return nil
+}
+
+// Diagnostics returns a diagnostics representation of a subset of the Config.
+func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
+	if !c.Enabled {
+		return diagnostics.RowFromMap(map[string]interface{}{
+			"enabled": false,
+		}), nil
+	}
+
+	return diagnostics.RowFromMap(map[string]interface{}{
+		"enabled":  true,
+		"interval": c.Interval,
+	}), nil
+}
@@ -0,0 +1,44 @@
+package heartbeat_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/freetsdb/freetsdb/services/heartbeat"
+)
+
+func TestConfig_Parse(t *testing.T) {
+	// Parse configuration.
+	var c heartbeat.Config
+	if _, err := toml.Decode(`
+enabled = true
+interval = "3s"
+`, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate configuration.
+	if !c.Enabled {
+		t.Fatalf("unexpected enabled state: %v", c.Enabled)
+	} else if time.Duration(c.Interval) != 3*time.Second {
+		t.Fatalf("unexpected interval: %s", c.Interval)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	c := heartbeat.NewConfig()
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation fail from NewConfig: %s", err)
+	}
+
+	c.Interval = 0
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for interval = 0, got nil")
+	}
+
+	c.Enabled = false
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation fail from disabled config: %s", err)
+	}
+}
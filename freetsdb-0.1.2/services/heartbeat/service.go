@@ -0,0 +1,101 @@
+// Package heartbeat provides the service that reports the liveness of a data
+// node to the meta service.
+package heartbeat // import "github.com/freetsdb/freetsdb/services/heartbeat"
+
+import (
+	"sync"
+	"time"
+
+	"github.com/freetsdb/freetsdb"
+	"github.com/freetsdb/freetsdb/logger"
+	"go.uber.org/zap"
+)
+
+// Service periodically sends a heartbeat for the local data node to the
+// meta service.
+type Service struct {
+	interval time.Duration
+
+	Node *freetsdb.Node
+
+	MetaClient interface {
+		DataNodeHeartbeat(id uint64) error
+	}
+
+	Logger *zap.Logger
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewService returns a new instance of Service.
+func NewService(c Config) *Service {
+	return &Service{
+		interval: time.Duration(c.Interval),
+		Logger:   zap.NewNop(),
+	}
+}
+
+// WithLogger sets the logger on the service.
+func (s *Service) WithLogger(log *zap.Logger) {
+	s.Logger = log.With(zap.String("service", "heartbeat"))
+}
+
+// Open starts the service.
+func (s *Service) Open() error {
+	if s.done != nil {
+		return nil
+	}
+
+	s.Logger.Info("Starting heartbeat service",
+		logger.DurationLiteral("interval", s.interval))
+
+	s.done = make(chan struct{})
+
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+// Close stops the service.
+func (s *Service) Close() error {
+	if s.done == nil {
+		return nil
+	}
+
+	close(s.done)
+	s.wg.Wait()
+	s.done = nil
+
+	return nil
+}
+
+// run sends a heartbeat immediately and then every interval.
+func (s *Service) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.beat()
+
+		select {
+		case <-ticker.C:
+		case <-s.done:
+			s.Logger.Info("Terminating heartbeat service")
+			return
+		}
+	}
+}
+
+// beat sends a single heartbeat. Nodes that have not joined a cluster yet
+// have no ID and are skipped.
+func (s *Service) beat() {
+	if s.Node == nil || s.Node.ID == 0 {
+		return
+	}
+
+	if err := s.MetaClient.DataNodeHeartbeat(s.Node.ID); err != nil {
+		s.Logger.Info("Failed to send heartbeat", zap.Error(err))
+	}
+}
@@ -0,0 +1,49 @@
+package heartbeat_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/freetsdb/freetsdb"
+	"github.com/freetsdb/freetsdb/services/heartbeat"
+	"github.com/freetsdb/freetsdb/toml"
+)
+
+func TestService_Heartbeat(t *testing.T) {
+	var mc MetaClient
+	mc.beats = make(chan uint64, 10)
+
+	c := heartbeat.NewConfig()
+	c.Interval = toml.Duration(10 * time.Millisecond)
+	s := heartbeat.NewService(c)
+	s.Node = &freetsdb.Node{ID: 3}
+	s.MetaClient = &mc
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case id := <-mc.beats:
+			if id != 3 {
+				t.Fatalf("unexpected node id: %d", id)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for heartbeat")
+		}
+	}
+}
+
+// MetaClient is a mock that records heartbeats.
+type MetaClient struct {
+	beats chan uint64
+}
+
+func (c *MetaClient) DataNodeHeartbeat(id uint64) error {
+	select {
+	case c.beats <- id:
+	default:
+	}
+	return nil
+}
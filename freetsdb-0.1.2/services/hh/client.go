@@ -0,0 +1,118 @@
+package hh
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/freetsdb/freetsdb/tcp"
+)
+
+// RequestType indicates the type of hinted handoff request.
+type RequestType uint8
+
+const (
+	// RequestStatus represents a request for the state of every queue on
+	// the node.
+	RequestStatus RequestType = iota
+
+	// RequestPurge represents a request to delete the queue held for a node.
+	RequestPurge
+
+	// RequestReplay represents a request to send the queue held for a node
+	// without waiting for the retry interval.
+	RequestReplay
+)
+
+// Request is sent by a Client to the hinted handoff service.
+type Request struct {
+	Type   RequestType
+	NodeID uint64
+}
+
+// Response is sent by the hinted handoff service in reply to a Request.
+type Response struct {
+	Error  string
+	Status []NodeStatus
+}
+
+// Client represents a client for the hinted handoff service of a data node.
+type Client struct {
+	host   string
+	dialer *tcp.Dialer
+}
+
+// NewClient returns a new instance of Client connecting with dialer.
+func NewClient(host string, dialer *tcp.Dialer) *Client {
+	return &Client{host: host, dialer: dialer}
+}
+
+// Status returns the state of the queues held on the remote node.
+func (c *Client) Status() ([]NodeStatus, error) {
+	resp, err := c.exec(&Request{Type: RequestStatus})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Status, nil
+}
+
+// Purge deletes the queue the remote node holds for nodeID.
+func (c *Client) Purge(nodeID uint64) error {
+	_, err := c.exec(&Request{Type: RequestPurge, NodeID: nodeID})
+	return err
+}
+
+// Replay makes the remote node send the queue it holds for nodeID now.
+func (c *Client) Replay(nodeID uint64) error {
+	_, err := c.exec(&Request{Type: RequestReplay, NodeID: nodeID})
+	return err
+}
+
+// exec sends req and reads the response.
+func (c *Client) exec(req *Request) (*Response, error) {
+	conn, err := c.dialer.Dial("tcp", c.host, MuxHeader)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := writeMessage(conn, req); err != nil {
+		return nil, fmt.Errorf("write request: %s", err)
+	}
+
+	var resp Response
+	if err := readMessage(conn, &resp); err != nil {
+		return nil, fmt.Errorf("read response: %s", err)
+	} else if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
+
+// writeMessage writes v to w as length-prefixed JSON.
+func writeMessage(w io.Writer, v interface{}) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(len(buf))); err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// readMessage reads length-prefixed JSON from r into v.
+func readMessage(r io.Reader, v interface{}) error {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, v)
+}
@@ -1,6 +1,7 @@
 package hh
 
 import (
+	"bytes"
 	"encoding/binary"
 	"expvar"
 	"fmt"
@@ -27,9 +28,13 @@ type NodeProcessor struct {
 	nodeID           uint64
 	dir              string
 
-	mu   sync.RWMutex
-	wg   sync.WaitGroup
-	done chan struct{}
+	mu     sync.RWMutex
+	wg     sync.WaitGroup
+	done   chan struct{}
+	replay chan struct{}
+
+	// statsMu keeps the queue statistics in step with the queue contents.
+	statsMu sync.Mutex
 
 	queue  *queue
 	meta   metaClient
@@ -39,6 +44,20 @@ type NodeProcessor struct {
 	Logger  *log.Logger
 }
 
+// NodeStatus describes the hinted handoff queue held for a node.
+type NodeStatus struct {
+	NodeID       uint64    `json:"nodeID"`
+	Active       bool      `json:"active"`
+	Bytes        int64     `json:"bytes"`
+	Writes       int64     `json:"writes"`
+	Points       int64     `json:"points"`
+	MaxSize      int64     `json:"maxSize"`
+	Oldest       time.Time `json:"oldest"`
+	LastModified time.Time `json:"lastModified"`
+	Head         string    `json:"head"`
+	Tail         string    `json:"tail"`
+}
+
 // NewNodeProcessor returns a new NodeProcessor for the given node, using dir for
 // the hinted-handoff data.
 func NewNodeProcessor(nodeID uint64, dir string, w shardWriter, m metaClient) *NodeProcessor {
@@ -72,6 +91,7 @@ func (n *NodeProcessor) Open() error {
 		return nil
 	}
 	n.done = make(chan struct{})
+	n.replay = make(chan struct{}, 1)
 
 	// Create the queue directory if it doesn't already exist.
 	if err := os.MkdirAll(n.dir, 0700); err != nil {
@@ -88,6 +108,10 @@ func (n *NodeProcessor) Open() error {
 	}
 	n.queue = queue
 
+	if err := n.loadQueueStats(); err != nil {
+		return err
+	}
+
 	n.wg.Add(1)
 	go n.run()
 
@@ -122,6 +146,9 @@ func (n *NodeProcessor) Purge() error {
 		return fmt.Errorf("node processor is open")
 	}
 
+	for _, key := range []string{queueBytes, queueWrites, queuePoints, queueOldest} {
+		n.setStat(key, 0)
+	}
 	return os.RemoveAll(n.dir)
 }
 
@@ -139,7 +166,72 @@ func (n *NodeProcessor) WriteShard(shardID uint64, points []models.Point) error
 	n.statMap.Add(writeShardReqPoints, int64(len(points)))
 
 	b := marshalWrite(shardID, points)
-	return n.queue.Append(b)
+
+	n.statsMu.Lock()
+	defer n.statsMu.Unlock()
+	if err := n.queue.Append(b); err != nil {
+		if err == ErrQueueFull {
+			n.statMap.Add(writeShardReqFull, 1)
+		}
+		return err
+	}
+
+	n.statMap.Add(queueWrites, 1)
+	n.statMap.Add(queuePoints, int64(len(points)))
+	n.updateQueueStats()
+	return nil
+}
+
+// Status returns the current state of the node's queue.
+func (n *NodeProcessor) Status() (NodeStatus, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if n.done == nil {
+		return NodeStatus{}, fmt.Errorf("node processor is closed")
+	}
+
+	active, err := n.Active()
+	if err != nil {
+		return NodeStatus{}, err
+	}
+
+	lm, err := n.LastModified()
+	if err != nil {
+		return NodeStatus{}, err
+	}
+
+	st := n.queue.Stats()
+	return NodeStatus{
+		NodeID:       n.nodeID,
+		Active:       active,
+		Bytes:        st.Bytes,
+		Writes:       n.stat(queueWrites),
+		Points:       n.stat(queuePoints),
+		MaxSize:      n.MaxSize,
+		Oldest:       st.Oldest,
+		LastModified: lm,
+		Head:         n.Head(),
+		Tail:         n.Tail(),
+	}, nil
+}
+
+// Replay makes the processor attempt to send its queued data to the node
+// now, rather than waiting for the current retry interval to elapse.
+func (n *NodeProcessor) Replay() error {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if n.done == nil {
+		return fmt.Errorf("node processor is closed")
+	}
+
+	select {
+	case n.replay <- struct{}{}:
+	default:
+		// A replay is already pending.
+	}
+	return nil
 }
 
 // LastModified returns the time the NodeProcessor last receieved hinted-handoff data.
@@ -170,34 +262,45 @@ func (n *NodeProcessor) run() {
 			if err := n.queue.PurgeOlderThan(time.Now().Add(-n.MaxAge)); err != nil {
 				n.Logger.Printf("failed to purge for node %d: %s", n.nodeID, err.Error())
 			}
+			if err := n.loadQueueStats(); err != nil {
+				n.Logger.Printf("failed to read queue for node %d: %s", n.nodeID, err.Error())
+			}
 
 		case <-time.After(currInterval):
-			limiter := NewRateLimiter(n.RetryRateLimit)
-			for {
-				c, err := n.SendWrite()
-				if err != nil {
-					if err == io.EOF {
-						// No more data, return to configured interval
-						currInterval = time.Duration(n.RetryInterval)
-					} else {
-						currInterval = currInterval * 2
-						if currInterval > time.Duration(n.RetryMaxInterval) {
-							currInterval = time.Duration(n.RetryMaxInterval)
-						}
-					}
-					break
-				}
-
-				// Success! Ensure backoff is cancelled.
-				currInterval = time.Duration(n.RetryInterval)
-
-				// Update how many bytes we've sent
-				limiter.Update(c)
-
-				// Block to maintain the throughput rate
-				time.Sleep(limiter.Delay())
+			currInterval = n.sendWrites(currInterval)
+
+		case <-n.replay:
+			currInterval = n.sendWrites(time.Duration(n.RetryInterval))
+		}
+	}
+}
+
+// sendWrites sends queued data to the node until the queue is empty or a
+// write fails, and returns the interval to wait before trying again.
+func (n *NodeProcessor) sendWrites(currInterval time.Duration) time.Duration {
+	limiter := NewRateLimiter(n.RetryRateLimit)
+	for {
+		c, err := n.SendWrite()
+		if err != nil {
+			if err == io.EOF {
+				// No more data, return to configured interval
+				return time.Duration(n.RetryInterval)
+			}
+			currInterval = currInterval * 2
+			if currInterval > time.Duration(n.RetryMaxInterval) {
+				currInterval = time.Duration(n.RetryMaxInterval)
 			}
+			return currInterval
 		}
+
+		// Success! Ensure backoff is cancelled.
+		currInterval = time.Duration(n.RetryInterval)
+
+		// Update how many bytes we've sent
+		limiter.Update(c)
+
+		// Block to maintain the throughput rate
+		time.Sleep(limiter.Delay())
 	}
 }
 
@@ -227,9 +330,14 @@ func (n *NodeProcessor) SendWrite() (int, error) {
 	if err != nil {
 		n.Logger.Printf("unmarshal write failed: %v", err)
 		// Try to skip it.
+		n.statsMu.Lock()
+		defer n.statsMu.Unlock()
 		if err := n.queue.Advance(); err != nil {
 			n.Logger.Printf("failed to advance queue for node %d: %s", n.nodeID, err.Error())
 		}
+		n.statMap.Add(queueWrites, -1)
+		n.statMap.Add(queuePoints, -countPoints(buf))
+		n.updateQueueStats()
 		return 0, err
 	}
 
@@ -240,13 +348,67 @@ func (n *NodeProcessor) SendWrite() (int, error) {
 	n.statMap.Add(writeNodeReq, 1)
 	n.statMap.Add(writeNodeReqPoints, int64(len(points)))
 
+	n.statsMu.Lock()
+	defer n.statsMu.Unlock()
 	if err := n.queue.Advance(); err != nil {
 		n.Logger.Printf("failed to advance queue for node %d: %s", n.nodeID, err.Error())
 	}
+	n.statMap.Add(queueWrites, -1)
+	n.statMap.Add(queuePoints, -int64(len(points)))
+	n.updateQueueStats()
 
 	return len(buf), nil
 }
 
+// loadQueueStats counts the writes and points waiting in the queue.
+func (n *NodeProcessor) loadQueueStats() error {
+	n.statsMu.Lock()
+	defer n.statsMu.Unlock()
+
+	var writes, points int64
+	if err := n.queue.Scan(func(b []byte) error {
+		writes++
+		points += countPoints(b)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	n.setStat(queueWrites, writes)
+	n.setStat(queuePoints, points)
+	n.updateQueueStats()
+	return nil
+}
+
+// updateQueueStats records the size and age of the queue in the statistics.
+func (n *NodeProcessor) updateQueueStats() {
+	st := n.queue.Stats()
+	n.setStat(queueBytes, st.Bytes)
+	if st.Oldest.IsZero() {
+		n.setStat(queueOldest, 0)
+	} else {
+		n.setStat(queueOldest, st.Oldest.UnixNano())
+	}
+}
+
+// setStat sets the value of a gauge statistic.
+func (n *NodeProcessor) setStat(key string, value int64) {
+	v, ok := n.statMap.Get(key).(*expvar.Int)
+	if !ok {
+		v = new(expvar.Int)
+		n.statMap.Set(key, v)
+	}
+	v.Set(value)
+}
+
+// stat returns the value of an integer statistic.
+func (n *NodeProcessor) stat(key string) int64 {
+	if v, ok := n.statMap.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
 // Head returns the head of the processor's queue.
 func (n *NodeProcessor) Head() string {
 	qp, err := n.queue.Position()
@@ -285,6 +447,14 @@ func marshalWrite(shardID uint64, points []models.Point) []byte {
 	return b
 }
 
+// countPoints returns the number of points in a marshaled write.
+func countPoints(b []byte) int64 {
+	if len(b) < 8 {
+		return 0
+	}
+	return int64(bytes.Count(b[8:], []byte{'\n'}))
+}
+
 func unmarshalWrite(b []byte) (uint64, []models.Point, error) {
 	if len(b) < 8 {
 		return 0, nil, fmt.Errorf("too short: len = %d", len(b))
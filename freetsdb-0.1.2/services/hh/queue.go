@@ -78,6 +78,18 @@ type queuePos struct {
 	tail string
 }
 
+// queueStats describes the data waiting to be read from a queue.
+type queueStats struct {
+	// Bytes is the size of the unread blocks, including their length headers.
+	Bytes int64
+
+	// Oldest approximates the time the oldest unread block was appended. It
+	// is the time the first unread block of the head segment was appended,
+	// or the segment's modification time if that was before the queue was
+	// opened. Zero if the queue is empty.
+	Oldest time.Time
+}
+
 type segments []*segment
 
 // newQueue create a queue that will store segments in dir and that will
@@ -233,6 +245,43 @@ func (l *queue) Position() (*queuePos, error) {
 	return qp, nil
 }
 
+// Stats returns the size and age of the unread blocks in the queue.
+func (l *queue) Stats() queueStats {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var st queueStats
+	for _, s := range l.segments {
+		bytes, appended := s.pending()
+		if bytes == 0 {
+			continue
+		}
+		if st.Oldest.IsZero() {
+			st.Oldest = appended
+		}
+		st.Bytes += bytes
+	}
+	return st
+}
+
+// Scan calls fn with each unread block in the queue, oldest first, without
+// advancing the head.
+func (l *queue) Scan(fn func([]byte) error) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.head == nil {
+		return ErrNotOpen
+	}
+
+	for _, s := range l.segments {
+		if err := s.scan(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // diskUsage returns the total size on disk used by the queue
 func (l *queue) diskUsage() int64 {
 	var size int64
@@ -414,6 +463,9 @@ type segment struct {
 	pos         int64
 	currentSize int64
 	maxSize     int64
+
+	// appended is the time the first unread block was appended.
+	appended time.Time
 }
 
 func newSegment(path string, maxSize int64) (*segment, error) {
@@ -481,6 +533,14 @@ func (l *segment) open() error {
 			return err
 		}
 		l.currentSize = int64(currentSize)
+
+		// The append times of existing blocks are not stored, so use the
+		// last time the segment was written.
+		stats, err := l.file.Stat()
+		if err != nil {
+			return err
+		}
+		l.appended = stats.ModTime().UTC()
 	}
 
 	return nil
@@ -523,6 +583,10 @@ func (l *segment) append(b []byte) error {
 		l.currentSize = int64(len(b))
 	}
 
+	if l.pos == l.size-footerSize {
+		l.appended = time.Now().UTC()
+	}
+
 	l.size += int64(len(b)) + 8 // uint64 for slice length
 
 	return nil
@@ -572,6 +636,7 @@ func (l *segment) advance() error {
 	// If we're at the end of the file, can't advance
 	if int64(l.pos) == l.size-footerSize {
 		l.currentSize = 0
+		l.appended = time.Time{}
 		return io.EOF
 	}
 
@@ -601,12 +666,53 @@ func (l *segment) advance() error {
 
 	if int64(l.pos) == l.size-footerSize {
 		l.currentSize = 0
+		l.appended = time.Time{}
 		return io.EOF
 	}
 
 	return nil
 }
 
+// pending returns the size of the unread blocks in the segment and the time
+// the first of them was appended.
+func (l *segment) pending() (int64, time.Time) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.size - footerSize - l.pos, l.appended
+}
+
+// scan calls fn with each unread block in the segment. It reads at explicit
+// offsets so the position used by current and append is not disturbed.
+func (l *segment) scan(fn func([]byte) error) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.file == nil {
+		return ErrNotOpen
+	}
+
+	var hdr [8]byte
+	for pos := l.pos; pos < l.size-footerSize; {
+		if _, err := l.file.ReadAt(hdr[:], pos); err != nil {
+			return err
+		}
+		sz := int64(binary.BigEndian.Uint64(hdr[:]))
+		if sz > l.maxSize || pos+8+sz > l.size-footerSize {
+			return fmt.Errorf("record size out of range: max %d: got %d", l.maxSize, sz)
+		}
+
+		b := make([]byte, sz)
+		if _, err := l.file.ReadAt(b, pos+8); err != nil {
+			return err
+		}
+		if err := fn(b); err != nil {
+			return err
+		}
+		pos += 8 + sz
+	}
+	return nil
+}
+
 func (l *segment) close() error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
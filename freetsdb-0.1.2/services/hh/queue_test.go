@@ -325,3 +325,69 @@ func TestPurgeQueue(t *testing.T) {
 	}
 
 }
+
+func TestQueueStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hh_queue")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	q, err := newQueue(dir, 1024)
+	if err != nil {
+		t.Fatalf("failed to create queue: %v", err)
+	}
+
+	if err := q.Open(); err != nil {
+		t.Fatalf("failed to open queue: %v", err)
+	}
+	q.SetMaxSegmentSize(30)
+
+	if st := q.Stats(); st.Bytes != 0 || !st.Oldest.IsZero() {
+		t.Fatalf("Queue.Stats mismatch: got %+v, exp empty", st)
+	}
+
+	// Spread the blocks over two segments.
+	start := time.Now().UTC()
+	for _, b := range []string{"one", "two", "three"} {
+		if err := q.Append([]byte(b)); err != nil {
+			t.Fatalf("Queue.Append failed: %v", err)
+		}
+	}
+
+	st := q.Stats()
+	if exp := int64(8 + 3 + 8 + 3 + 8 + 5); st.Bytes != exp {
+		t.Errorf("Queue.Stats bytes mismatch: got %v, exp %v", st.Bytes, exp)
+	}
+	if st.Oldest.Before(start) {
+		t.Errorf("Queue.Stats oldest mismatch: got %v, exp after %v", st.Oldest, start)
+	}
+
+	if err := q.Advance(); err != nil {
+		t.Fatalf("Queue.Advance failed: %v", err)
+	}
+
+	var got []string
+	if err := q.Scan(func(b []byte) error {
+		got = append(got, string(b))
+		return nil
+	}); err != nil {
+		t.Fatalf("Queue.Scan failed: %v", err)
+	}
+	if exp := "[two three]"; fmt.Sprint(got) != exp {
+		t.Errorf("Queue.Scan mismatch: got %v, exp %v", got, exp)
+	}
+
+	// Scanning must not move the head.
+	cur, err := q.Current()
+	if err != nil {
+		t.Fatalf("Queue.Current failed: %v", err)
+	}
+	if exp := "two"; string(cur) != exp {
+		t.Errorf("Queue.Current mismatch: got %v, exp %v", string(cur), exp)
+	}
+
+	if exp := int64(8 + 3 + 8 + 5); q.Stats().Bytes != exp {
+		t.Errorf("Queue.Stats bytes mismatch: got %v, exp %v", q.Stats().Bytes, exp)
+	}
+}
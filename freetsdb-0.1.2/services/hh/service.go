@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,6 +20,9 @@ import (
 	"github.com/freetsdb/freetsdb/services/meta"
 )
 
+// MuxHeader is the header byte used for the TCP muxer.
+const MuxHeader = 11
+
 // ErrHintedHandoffDisabled is returned when attempting to use a
 // disabled hinted handoff service.
 var ErrHintedHandoffDisabled = fmt.Errorf("hinted handoff disabled")
@@ -25,9 +30,15 @@ var ErrHintedHandoffDisabled = fmt.Errorf("hinted handoff disabled")
 const (
 	writeShardReq       = "writeShardReq"
 	writeShardReqPoints = "writeShardReqPoints"
+	writeShardReqFull   = "writeShardReqFull"
 	writeNodeReq        = "writeNodeReq"
 	writeNodeReqFail    = "writeNodeReqFail"
 	writeNodeReqPoints  = "writeNodeReqPoints"
+
+	queueBytes  = "queueBytes"
+	queueWrites = "queueWrites"
+	queuePoints = "queuePoints"
+	queueOldest = "queueOldest"
 )
 
 // Service represents a hinted handoff service.
@@ -36,6 +47,9 @@ type Service struct {
 	wg      sync.WaitGroup
 	closing chan struct{}
 
+	// serveWG tracks the listener and its connections, which take mu.
+	serveWG sync.WaitGroup
+
 	processors map[uint64]*NodeProcessor
 
 	statMap *expvar.Map
@@ -45,6 +59,8 @@ type Service struct {
 	shardWriter shardWriter
 	MetaClient  metaClient
 
+	Listener net.Listener
+
 	Monitor interface {
 		RegisterDiagnosticsClient(name string, client diagnostics.Client)
 		DeregisterDiagnosticsClient(name string)
@@ -79,12 +95,18 @@ func NewService(c Config, w shardWriter, m metaClient) *Service {
 func (s *Service) Open() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+
+	s.closing = make(chan struct{})
+	if s.Listener != nil {
+		s.serveWG.Add(1)
+		go s.serve()
+	}
+
 	if !s.cfg.Enabled {
 		// Allow Open to proceed, but don't do anything.
 		return nil
 	}
 	s.Logger.Printf("Starting hinted handoff service")
-	s.closing = make(chan struct{})
 
 	// Register diagnostics if a Monitor service is available.
 	if s.Monitor != nil {
@@ -123,9 +145,67 @@ func (s *Service) Open() error {
 	return nil
 }
 
+// serve serves hinted handoff requests from the listener. A disabled
+// service answers requests but reports no queues.
+func (s *Service) serve() {
+	defer s.serveWG.Done()
+
+	for {
+		// Wait for next connection.
+		conn, err := s.Listener.Accept()
+		if err != nil && strings.Contains(err.Error(), "connection closed") {
+			s.Logger.Println("hinted handoff listener closed")
+			return
+		} else if err != nil {
+			s.Logger.Printf("error accepting hinted handoff request: %s", err)
+			continue
+		}
+
+		// Handle connection in separate goroutine.
+		s.serveWG.Add(1)
+		go func(conn net.Conn) {
+			defer s.serveWG.Done()
+			defer conn.Close()
+			if err := s.handleConn(conn); err != nil {
+				s.Logger.Printf("failed to handle hinted handoff request: %s", err)
+			}
+		}(conn)
+	}
+}
+
+// handleConn processes conn. This is run in a separate goroutine.
+func (s *Service) handleConn(conn net.Conn) error {
+	var req Request
+	if err := readMessage(conn, &req); err != nil {
+		return fmt.Errorf("read request: %s", err)
+	}
+
+	var resp Response
+	var err error
+	switch req.Type {
+	case RequestStatus:
+		resp.Status, err = s.Status()
+	case RequestPurge:
+		err = s.Purge(req.NodeID)
+	case RequestReplay:
+		err = s.Replay(req.NodeID)
+	default:
+		err = fmt.Errorf("request type unknown: %v", req.Type)
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	return writeMessage(conn, &resp)
+}
+
 // Close closes the hinted handoff service.
 func (s *Service) Close() error {
 	s.Logger.Println("shutting down hh service")
+	if s.Listener != nil {
+		s.Listener.Close()
+	}
+	s.serveWG.Wait()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -186,6 +266,55 @@ func (s *Service) WriteShard(shardID, ownerID uint64, points []models.Point) err
 	return nil
 }
 
+// Status returns the state of the queue held for each node, ordered by node ID.
+func (s *Service) Status() ([]NodeStatus, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a := make([]NodeStatus, 0, len(s.processors))
+	for _, p := range s.processors {
+		st, err := p.Status()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, st)
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].NodeID < a[j].NodeID })
+	return a, nil
+}
+
+// Purge deletes the queue held for a node.
+func (s *Service) Purge(nodeID uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.processors[nodeID]
+	if !ok {
+		return fmt.Errorf("no hinted handoff queue for node %d", nodeID)
+	}
+
+	if err := p.Close(); err != nil {
+		return err
+	}
+	if err := p.Purge(); err != nil {
+		return err
+	}
+	delete(s.processors, nodeID)
+	return nil
+}
+
+// Replay makes the queue held for a node attempt to send its data now.
+func (s *Service) Replay(nodeID uint64) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.processors[nodeID]
+	if !ok {
+		return fmt.Errorf("no hinted handoff queue for node %d", nodeID)
+	}
+	return p.Replay()
+}
+
 // Diagnostics returns diagnostic information.
 func (s *Service) Diagnostics() (*diagnostics.Diagnostics, error) {
 	s.mu.RLock()
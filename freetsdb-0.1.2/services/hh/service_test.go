@@ -0,0 +1,117 @@
+package hh_test
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/freetsdb/freetsdb/models"
+	"github.com/freetsdb/freetsdb/services/hh"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tcp"
+)
+
+// Ensure queued writes are reported and can be purged over the mux.
+func TestService_StatusPurge(t *testing.T) {
+	s := MustOpenService()
+	defer s.Close()
+
+	points := []models.Point{
+		models.MustNewPoint("cpu", models.NewTags(map[string]string{"host": "a"}), models.Fields{"value": 1.0}, time.Unix(0, 1)),
+		models.MustNewPoint("cpu", models.NewTags(map[string]string{"host": "b"}), models.Fields{"value": 2.0}, time.Unix(0, 2)),
+	}
+	if err := s.WriteShard(1, 2, points); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WriteShard(1, 2, points[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	client := hh.NewClient(s.ln.Addr().String(), nil)
+	status, err := client.Status()
+	if err != nil {
+		t.Fatal(err)
+	} else if len(status) != 1 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+
+	st := status[0]
+	if st.NodeID != 2 || st.Active || st.Writes != 2 || st.Points != 3 || st.Bytes == 0 || st.Oldest.IsZero() {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+
+	if err := client.Replay(2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Purge(2); err != nil {
+		t.Fatal(err)
+	} else if status, err := client.Status(); err != nil {
+		t.Fatal(err)
+	} else if len(status) != 0 {
+		t.Fatalf("unexpected status after purge: %+v", status)
+	}
+
+	if err := client.Purge(2); err == nil || err.Error() != "no hinted handoff queue for node 2" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Service is a test wrapper for hh.Service.
+type Service struct {
+	*hh.Service
+	ln  net.Listener
+	dir string
+}
+
+// MustOpenService returns a new, opened service listening on a random port.
+// Every destination node is inactive so queued data is never sent.
+func MustOpenService() *Service {
+	dir, err := ioutil.TempDir("", "hh_service")
+	if err != nil {
+		panic(err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+
+	mux := tcp.NewMux()
+	go mux.Serve(ln)
+
+	c := hh.NewConfig()
+	c.Enabled = true
+	c.Dir = dir
+
+	s := &Service{Service: hh.NewService(c, &ShardWriter{}, &MetaClient{}), ln: ln, dir: dir}
+	s.Logger.SetOutput(ioutil.Discard)
+	s.Listener = mux.Listen(hh.MuxHeader)
+	if err := s.Open(); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Close shuts down the service and removes its data.
+func (s *Service) Close() error {
+	s.ln.Close()
+	defer os.RemoveAll(s.dir)
+	return s.Service.Close()
+}
+
+// ShardWriter is a mock that discards writes.
+type ShardWriter struct{}
+
+func (w *ShardWriter) WriteShard(shardID, ownerID uint64, points []models.Point) error {
+	return nil
+}
+
+// MetaClient is a mock that knows no data nodes.
+type MetaClient struct{}
+
+func (c *MetaClient) DataNode(id uint64) (*meta.NodeInfo, error) {
+	return nil, nil
+}
@@ -510,12 +510,22 @@ func (h *Handler) serveQuery(w http.ResponseWriter, r *http.Request, user meta.U
 	// Parse whether this is an async command.
 	async := r.FormValue("async") == "true"
 
+	// Parse the number of shard owners to read.
+	readConsistency := query.ReadConsistencyOne
+	if s := r.FormValue("read_consistency"); s != "" {
+		if readConsistency, err = query.ParseReadConsistency(s); err != nil {
+			h.httpError(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
 	opts := query.ExecutionOptions{
 		Database:        db,
 		RetentionPolicy: r.FormValue("rp"),
 		ChunkSize:       chunkSize,
 		ReadOnly:        r.Method == "GET",
 		NodeID:          nodeID,
+		ReadConsistency: readConsistency,
 	}
 
 	if h.Config.AuthEnabled {
@@ -803,7 +813,7 @@ func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user meta.U
 
 	// Determine required consistency level.
 	level := r.URL.Query().Get("consistency")
-	consistency := coordinator.ConsistencyLevelOne
+	consistency := coordinator.ConsistencyLevelDefault
 	if level != "" {
 		var err error
 		consistency, err = coordinator.ParseConsistencyLevel(level)
@@ -995,7 +1005,7 @@ func (h *Handler) servePromWrite(w http.ResponseWriter, r *http.Request, user me
 
 	// Determine required consistency level.
 	level := r.URL.Query().Get("consistency")
-	consistency := coordinator.ConsistencyLevelOne
+	consistency := coordinator.ConsistencyLevelDefault
 	if level != "" {
 		consistency, err = coordinator.ParseConsistencyLevel(level)
 		if err != nil {
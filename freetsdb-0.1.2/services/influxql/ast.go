@@ -206,9 +206,14 @@ type Node interface {
 func (*Query) node()     {}
 func (Statements) node() {}
 
+func (*AlterDatabaseStatement) node()              {}
+func (*AlterFieldTypeStatement) node()             {}
 func (*AlterRetentionPolicyStatement) node()       {}
+func (*BackfillContinuousQueryStatement) node()    {}
+func (*CompactShardStatement) node()               {}
 func (*CreateContinuousQueryStatement) node()      {}
 func (*CreateDatabaseStatement) node()             {}
+func (*CreateDownsampleStatement) node()           {}
 func (*CreateRetentionPolicyStatement) node()      {}
 func (*CreateSubscriptionStatement) node()         {}
 func (*CreateUserStatement) node()                 {}
@@ -217,6 +222,7 @@ func (*DeleteSeriesStatement) node()               {}
 func (*DeleteStatement) node()                     {}
 func (*DropContinuousQueryStatement) node()        {}
 func (*DropDatabaseStatement) node()               {}
+func (*DropDownsampleStatement) node()             {}
 func (*DropMeasurementStatement) node()            {}
 func (*DropRetentionPolicyStatement) node()        {}
 func (*DropSeriesStatement) node()                 {}
@@ -227,14 +233,24 @@ func (*ExplainStatement) node()                    {}
 func (*GrantStatement) node()                      {}
 func (*GrantAdminStatement) node()                 {}
 func (*KillQueryStatement) node()                  {}
+func (*PauseCompactionsStatement) node()           {}
+func (*RestoreShardStatement) node()               {}
+func (*ResumeCompactionsStatement) node()          {}
 func (*RevokeStatement) node()                     {}
 func (*RevokeAdminStatement) node()                {}
 func (*SelectStatement) node()                     {}
 func (*SetPasswordUserStatement) node()            {}
+func (*SetTrashStatement) node()                   {}
+func (*ShowCompactionsStatement) node()            {}
 func (*ShowContinuousQueriesStatement) node()      {}
+func (*ShowContinuousQueryStatusStatement) node()  {}
 func (*ShowGrantsForUserStatement) node()          {}
 func (*ShowServersStatement) node()                {}
+func (*ShowRebalanceStatement) node()              {}
+func (*ShowHintedHandoffStatement) node()          {}
 func (*ShowDatabasesStatement) node()              {}
+func (*ShowDownsamplesStatement) node()            {}
+func (*ShowDroppedStatement) node()                {}
 func (*ShowFieldKeyCardinalityStatement) node()    {}
 func (*ShowFieldKeysStatement) node()              {}
 func (*ShowRetentionPoliciesStatement) node()      {}
@@ -253,6 +269,10 @@ func (*ShowTagKeysStatement) node()                {}
 func (*ShowTagValuesCardinalityStatement) node()   {}
 func (*ShowTagValuesStatement) node()              {}
 func (*ShowUsersStatement) node()                  {}
+func (*UndropDatabaseStatement) node()             {}
+func (*UndropMeasurementStatement) node()          {}
+func (*UndropShardGroupStatement) node()           {}
+func (*UndropShardStatement) node()                {}
 
 func (*BinaryExpr) node()      {}
 func (*BooleanLiteral) node()  {}
@@ -335,9 +355,13 @@ type ExecutionPrivilege struct {
 // ExecutionPrivileges is a list of privileges required to execute a statement.
 type ExecutionPrivileges []ExecutionPrivilege
 
+func (*AlterDatabaseStatement) stmt()              {}
+func (*AlterFieldTypeStatement) stmt()             {}
 func (*AlterRetentionPolicyStatement) stmt()       {}
+func (*BackfillContinuousQueryStatement) stmt()    {}
 func (*CreateContinuousQueryStatement) stmt()      {}
 func (*CreateDatabaseStatement) stmt()             {}
+func (*CreateDownsampleStatement) stmt()           {}
 func (*CreateRetentionPolicyStatement) stmt()      {}
 func (*CreateSubscriptionStatement) stmt()         {}
 func (*CreateUserStatement) stmt()                 {}
@@ -345,6 +369,7 @@ func (*DeleteSeriesStatement) stmt()               {}
 func (*DeleteStatement) stmt()                     {}
 func (*DropContinuousQueryStatement) stmt()        {}
 func (*DropDatabaseStatement) stmt()               {}
+func (*DropDownsampleStatement) stmt()             {}
 func (*DropMeasurementStatement) stmt()            {}
 func (*DropRetentionPolicyStatement) stmt()        {}
 func (*DropSeriesStatement) stmt()                 {}
@@ -354,10 +379,16 @@ func (*ExplainStatement) stmt()                    {}
 func (*GrantStatement) stmt()                      {}
 func (*GrantAdminStatement) stmt()                 {}
 func (*KillQueryStatement) stmt()                  {}
+func (*ShowCompactionsStatement) stmt()            {}
 func (*ShowContinuousQueriesStatement) stmt()      {}
+func (*ShowContinuousQueryStatusStatement) stmt()  {}
 func (*ShowGrantsForUserStatement) stmt()          {}
 func (*ShowServersStatement) stmt()                {}
+func (*ShowRebalanceStatement) stmt()              {}
+func (*ShowHintedHandoffStatement) stmt()          {}
 func (*ShowDatabasesStatement) stmt()              {}
+func (*ShowDownsamplesStatement) stmt()            {}
+func (*ShowDroppedStatement) stmt()                {}
 func (*ShowFieldKeyCardinalityStatement) stmt()    {}
 func (*ShowFieldKeysStatement) stmt()              {}
 func (*ShowMeasurementCardinalityStatement) stmt() {}
@@ -377,10 +408,19 @@ func (*ShowTagKeysStatement) stmt()                {}
 func (*ShowTagValuesCardinalityStatement) stmt()   {}
 func (*ShowTagValuesStatement) stmt()              {}
 func (*ShowUsersStatement) stmt()                  {}
+func (*UndropDatabaseStatement) stmt()             {}
+func (*UndropMeasurementStatement) stmt()          {}
+func (*UndropShardGroupStatement) stmt()           {}
+func (*UndropShardStatement) stmt()                {}
+func (*RestoreShardStatement) stmt()               {}
+func (*CompactShardStatement) stmt()               {}
+func (*PauseCompactionsStatement) stmt()           {}
+func (*ResumeCompactionsStatement) stmt()          {}
 func (*RevokeStatement) stmt()                     {}
 func (*RevokeAdminStatement) stmt()                {}
 func (*SelectStatement) stmt()                     {}
 func (*SetPasswordUserStatement) stmt()            {}
+func (*SetTrashStatement) stmt()                   {}
 
 // Expr represents an expression that can be evaluated to a value.
 type Expr interface {
@@ -590,6 +630,9 @@ type CreateDatabaseStatement struct {
 
 	// RetentionPolicyShardGroupDuration indicates shard group duration for the new database.
 	RetentionPolicyShardGroupDuration time.Duration
+
+	// Consistency is the default write consistency level for the new database.
+	Consistency string
 }
 
 // String returns a string representation of the create database statement.
@@ -597,8 +640,10 @@ func (s *CreateDatabaseStatement) String() string {
 	var buf bytes.Buffer
 	_, _ = buf.WriteString("CREATE DATABASE ")
 	_, _ = buf.WriteString(QuoteIdent(s.Name))
-	if s.RetentionPolicyCreate {
+	if s.RetentionPolicyCreate || s.Consistency != "" {
 		_, _ = buf.WriteString(" WITH")
+	}
+	if s.RetentionPolicyCreate {
 		if s.RetentionPolicyDuration != nil {
 			_, _ = buf.WriteString(" DURATION ")
 			_, _ = buf.WriteString(s.RetentionPolicyDuration.String())
@@ -616,6 +661,10 @@ func (s *CreateDatabaseStatement) String() string {
 			_, _ = buf.WriteString(QuoteIdent(s.RetentionPolicyName))
 		}
 	}
+	if s.Consistency != "" {
+		_, _ = buf.WriteString(" CONSISTENCY ")
+		_, _ = buf.WriteString(strings.ToUpper(s.Consistency))
+	}
 
 	return buf.String()
 }
@@ -929,6 +978,19 @@ type CreateRetentionPolicyStatement struct {
 
 	// Shard Duration.
 	ShardGroupDuration time.Duration
+
+	// Default write consistency level for the policy.
+	Consistency string
+
+	// Duration after which shard groups move to the cold tier.
+	ColdAfter time.Duration
+
+	// How points with the same series and timestamp are merged.
+	Duplicates string
+
+	// Codecs compressing string and float blocks when they are compacted.
+	StringCodec string
+	FloatCodec  string
 }
 
 // String returns a string representation of the create retention policy.
@@ -946,6 +1008,26 @@ func (s *CreateRetentionPolicyStatement) String() string {
 		_, _ = buf.WriteString(" SHARD DURATION ")
 		_, _ = buf.WriteString(FormatDuration(s.ShardGroupDuration))
 	}
+	if s.Consistency != "" {
+		_, _ = buf.WriteString(" CONSISTENCY ")
+		_, _ = buf.WriteString(strings.ToUpper(s.Consistency))
+	}
+	if s.ColdAfter > 0 {
+		_, _ = buf.WriteString(" COLD AFTER ")
+		_, _ = buf.WriteString(FormatDuration(s.ColdAfter))
+	}
+	if s.Duplicates != "" {
+		_, _ = buf.WriteString(" DUPLICATES ")
+		_, _ = buf.WriteString(strings.ToUpper(s.Duplicates))
+	}
+	if s.StringCodec != "" {
+		_, _ = buf.WriteString(" STRING CODEC ")
+		_, _ = buf.WriteString(strings.ToUpper(s.StringCodec))
+	}
+	if s.FloatCodec != "" {
+		_, _ = buf.WriteString(" FLOAT CODEC ")
+		_, _ = buf.WriteString(strings.ToUpper(s.FloatCodec))
+	}
 	if s.Default {
 		_, _ = buf.WriteString(" DEFAULT")
 	}
@@ -981,6 +1063,25 @@ type AlterRetentionPolicyStatement struct {
 
 	// Duration of the Shard.
 	ShardGroupDuration *time.Duration
+
+	// Default write consistency level for the policy. An empty string
+	// makes the policy use the database's consistency level.
+	Consistency *string
+
+	// Duration after which shard groups move to the cold tier. Zero keeps
+	// them in the data directory.
+	ColdAfter *time.Duration
+
+	// How points with the same series and timestamp are merged.
+	Duplicates *string
+
+	// Codecs compressing string and float blocks when they are compacted.
+	StringCodec *string
+	FloatCodec  *string
+
+	// DryRun reports the shard groups the change would delete instead of
+	// applying it.
+	DryRun bool
 }
 
 // String returns a string representation of the alter retention policy statement.
@@ -1006,10 +1107,39 @@ func (s *AlterRetentionPolicyStatement) String() string {
 		_, _ = buf.WriteString(FormatDuration(*s.ShardGroupDuration))
 	}
 
+	if s.Consistency != nil {
+		_, _ = buf.WriteString(" CONSISTENCY ")
+		_, _ = buf.WriteString(formatConsistencyLevel(*s.Consistency))
+	}
+
+	if s.ColdAfter != nil {
+		_, _ = buf.WriteString(" COLD AFTER ")
+		_, _ = buf.WriteString(FormatDuration(*s.ColdAfter))
+	}
+
+	if s.Duplicates != nil {
+		_, _ = buf.WriteString(" DUPLICATES ")
+		_, _ = buf.WriteString(strings.ToUpper(*s.Duplicates))
+	}
+
+	if s.StringCodec != nil {
+		_, _ = buf.WriteString(" STRING CODEC ")
+		_, _ = buf.WriteString(strings.ToUpper(*s.StringCodec))
+	}
+
+	if s.FloatCodec != nil {
+		_, _ = buf.WriteString(" FLOAT CODEC ")
+		_, _ = buf.WriteString(strings.ToUpper(*s.FloatCodec))
+	}
+
 	if s.Default {
 		_, _ = buf.WriteString(" DEFAULT")
 	}
 
+	if s.DryRun {
+		_, _ = buf.WriteString(" DRY RUN")
+	}
+
 	return buf.String()
 }
 
@@ -1023,6 +1153,70 @@ func (s *AlterRetentionPolicyStatement) DefaultDatabase() string {
 	return s.Database
 }
 
+// AlterDatabaseStatement represents a command to alter an existing database.
+type AlterDatabaseStatement struct {
+	// Name of the database to alter.
+	Name string
+
+	// Default write consistency level for the database. An empty string
+	// makes the database use the configured consistency level.
+	Consistency string
+}
+
+// String returns a string representation of the alter database statement.
+func (s *AlterDatabaseStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("ALTER DATABASE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	_, _ = buf.WriteString(" CONSISTENCY ")
+	_, _ = buf.WriteString(formatConsistencyLevel(s.Consistency))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an AlterDatabaseStatement.
+func (s *AlterDatabaseStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// formatConsistencyLevel returns the InfluxQL form of a consistency level,
+// where an empty level is written as DEFAULT.
+func formatConsistencyLevel(level string) string {
+	if level == "" {
+		return "DEFAULT"
+	}
+	return strings.ToUpper(level)
+}
+
+// AlterFieldTypeStatement represents a command to change the type of a field
+// of a measurement.
+type AlterFieldTypeStatement struct {
+	// Name of the measurement holding the field.
+	Measurement string
+
+	// Name of the field to convert.
+	Field string
+
+	// Type the values of the field are converted to.
+	Type DataType
+}
+
+// String returns a string representation of the alter field type statement.
+func (s *AlterFieldTypeStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("ALTER MEASUREMENT ")
+	_, _ = buf.WriteString(QuoteIdent(s.Measurement))
+	_, _ = buf.WriteString(" ALTER FIELD ")
+	_, _ = buf.WriteString(QuoteIdent(s.Field))
+	_, _ = buf.WriteString(" TYPE ")
+	_, _ = buf.WriteString(s.Type.String())
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an AlterFieldTypeStatement.
+func (s *AlterFieldTypeStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // FillOption represents different options for filling aggregate windows.
 type FillOption int
 
@@ -2273,6 +2467,204 @@ func (s *DropShardStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// RestoreShardStatement represents a command for restoring an archived shard
+// on the node.
+type RestoreShardStatement struct {
+	// ID of the shard to be restored.
+	ID uint64
+}
+
+// String returns a string representation of the restore shard statement.
+func (s *RestoreShardStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("RESTORE SHARD ")
+	buf.WriteString(strconv.FormatUint(s.ID, 10))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a
+// RestoreShardStatement.
+func (s *RestoreShardStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// CompactShardStatement represents a command for compacting a shard on the
+// nodes owning it.
+type CompactShardStatement struct {
+	// ID of the shard to be compacted.
+	ID uint64
+
+	// Optimize the fully compacted files of the shard instead of running a
+	// full compaction.
+	Optimize bool
+}
+
+// String returns a string representation of the compact shard statement.
+func (s *CompactShardStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("COMPACT SHARD ")
+	buf.WriteString(strconv.FormatUint(s.ID, 10))
+	if s.Optimize {
+		buf.WriteString(" OPTIMIZE")
+	} else {
+		buf.WriteString(" FULL")
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a
+// CompactShardStatement.
+func (s *CompactShardStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// PauseCompactionsStatement represents a command for pausing the compactions
+// of the shards of a database.
+type PauseCompactionsStatement struct {
+	// Name of the database whose compactions are paused.
+	Database string
+}
+
+// String returns a string representation of the pause compactions statement.
+func (s *PauseCompactionsStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("PAUSE COMPACTIONS ON ")
+	buf.WriteString(QuoteIdent(s.Database))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a
+// PauseCompactionsStatement.
+func (s *PauseCompactionsStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// ResumeCompactionsStatement represents a command for resuming the paused
+// compactions of the shards of a database.
+type ResumeCompactionsStatement struct {
+	// Name of the database whose compactions are resumed.
+	Database string
+}
+
+// String returns a string representation of the resume compactions statement.
+func (s *ResumeCompactionsStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("RESUME COMPACTIONS ON ")
+	buf.WriteString(QuoteIdent(s.Database))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a
+// ResumeCompactionsStatement.
+func (s *ResumeCompactionsStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// SetTrashStatement represents a command for setting whether the data nodes
+// of the cluster move dropped data to their trash.
+type SetTrashStatement struct {
+	// Enabled is true if dropped data is moved to the trash.
+	Enabled bool
+}
+
+// String returns a string representation of the set trash statement.
+func (s *SetTrashStatement) String() string {
+	if s.Enabled {
+		return "SET TRASH ON"
+	}
+	return "SET TRASH OFF"
+}
+
+// RequiredPrivileges returns the privilege required to execute a
+// SetTrashStatement.
+func (s *SetTrashStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// UndropShardGroupStatement represents a command for recovering a shard
+// group that is pending deletion.
+type UndropShardGroupStatement struct {
+	// ID of the shard group to be recovered.
+	ID uint64
+}
+
+// String returns a string representation of the undrop shard group statement.
+func (s *UndropShardGroupStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("UNDROP SHARD GROUP ")
+	buf.WriteString(strconv.FormatUint(s.ID, 10))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an
+// UndropShardGroupStatement.
+func (s *UndropShardGroupStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// UndropDatabaseStatement represents a command for restoring a dropped
+// database from the trash.
+type UndropDatabaseStatement struct {
+	// Name of the database to be restored.
+	Name string
+}
+
+// String returns a string representation of the undrop database statement.
+func (s *UndropDatabaseStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("UNDROP DATABASE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an
+// UndropDatabaseStatement.
+func (s *UndropDatabaseStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// UndropMeasurementStatement represents a command for restoring a dropped
+// measurement from the trash.
+type UndropMeasurementStatement struct {
+	// Name of the measurement to be restored.
+	Name string
+}
+
+// String returns a string representation of the undrop measurement statement.
+func (s *UndropMeasurementStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("UNDROP MEASUREMENT ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an
+// UndropMeasurementStatement.
+func (s *UndropMeasurementStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// UndropShardStatement represents a command for restoring a dropped shard
+// from the trash.
+type UndropShardStatement struct {
+	// ID of the shard to be restored.
+	ID uint64
+}
+
+// String returns a string representation of the undrop shard statement.
+func (s *UndropShardStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("UNDROP SHARD ")
+	buf.WriteString(strconv.FormatUint(s.ID, 10))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an
+// UndropShardStatement.
+func (s *UndropShardStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // ShowSeriesCardinalityStatement represents a command for listing series cardinality.
 type ShowSeriesCardinalityStatement struct {
 	// Database to query. If blank, use the default database.
@@ -2355,6 +2747,18 @@ func (s *ShowContinuousQueriesStatement) RequiredPrivileges() (ExecutionPrivileg
 	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
 }
 
+// ShowContinuousQueryStatusStatement represents a command for listing the
+// outcome of the last executions of continuous queries.
+type ShowContinuousQueryStatusStatement struct{}
+
+// String returns a string representation of the statement.
+func (s *ShowContinuousQueryStatusStatement) String() string { return "SHOW CONTINUOUS QUERY STATUS" }
+
+// RequiredPrivileges returns the privilege required to execute a ShowContinuousQueryStatusStatement.
+func (s *ShowContinuousQueryStatusStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
+}
+
 // ShowGrantsForUserStatement represents a command for listing user privileges.
 type ShowGrantsForUserStatement struct {
 	// Name of the user to display privileges.
@@ -2386,6 +2790,42 @@ func (s *ShowServersStatement) RequiredPrivileges() (ExecutionPrivileges, error)
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// ShowCompactionsStatement represents a command for listing the compactions
+// running on the shards of the node.
+type ShowCompactionsStatement struct{}
+
+// String returns a string representation of the show compactions command.
+func (s *ShowCompactionsStatement) String() string { return "SHOW COMPACTIONS" }
+
+// RequiredPrivileges returns the privilege required to execute a ShowCompactionsStatement.
+func (s *ShowCompactionsStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// ShowRebalanceStatement represents a command for listing the shard moves
+// planned or running to rebalance the cluster.
+type ShowRebalanceStatement struct{}
+
+// String returns a string representation of the show rebalance command.
+func (s *ShowRebalanceStatement) String() string { return "SHOW REBALANCE" }
+
+// RequiredPrivileges returns the privilege required to execute a ShowRebalanceStatement.
+func (s *ShowRebalanceStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// ShowHintedHandoffStatement represents a command for listing the hinted
+// handoff queues held by each data node.
+type ShowHintedHandoffStatement struct{}
+
+// String returns a string representation of the show hinted handoff command.
+func (s *ShowHintedHandoffStatement) String() string { return "SHOW HINTED HANDOFF" }
+
+// RequiredPrivileges returns the privilege required to execute a ShowHintedHandoffStatement.
+func (s *ShowHintedHandoffStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // ShowDatabasesStatement represents a command for listing all databases in the cluster.
 type ShowDatabasesStatement struct{}
 
@@ -2500,6 +2940,111 @@ func (s *DropContinuousQueryStatement) DefaultDatabase() string {
 	return s.Database
 }
 
+// BackfillContinuousQueryStatement represents a command for computing the
+// results of a continuous query over a past time range.
+type BackfillContinuousQueryStatement struct {
+	Name     string
+	Database string
+
+	// Time range to compute, with an exclusive end time.
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// String returns a string representation of the statement.
+func (s *BackfillContinuousQueryStatement) String() string {
+	return fmt.Sprintf("BACKFILL CONTINUOUS QUERY %s ON %s FROM %s TO %s", QuoteIdent(s.Name), QuoteIdent(s.Database),
+		QuoteString(s.StartTime.UTC().Format(time.RFC3339Nano)), QuoteString(s.EndTime.UTC().Format(time.RFC3339Nano)))
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a BackfillContinuousQueryStatement.
+// The privileges for the target of the query are checked when it is executed.
+func (s *BackfillContinuousQueryStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: WritePrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *BackfillContinuousQueryStatement) DefaultDatabase() string {
+	return s.Database
+}
+
+// CreateDownsampleStatement represents a command for creating a rule that
+// aggregates the data of a retention policy into another retention policy.
+type CreateDownsampleStatement struct {
+	Database string
+
+	// Retention policies to read from and write to.
+	Source string
+	Target string
+
+	// GROUP BY time interval of the aggregates.
+	Interval time.Duration
+
+	// Functions applied to each field.
+	Aggregates []string
+}
+
+// String returns a string representation of the statement.
+func (s *CreateDownsampleStatement) String() string {
+	return fmt.Sprintf("CREATE DOWNSAMPLE ON %s FROM %s TO %s EVERY %s AGGREGATE %s",
+		QuoteIdent(s.Database), QuoteIdent(s.Source), QuoteIdent(s.Target), FormatDuration(s.Interval), strings.Join(s.Aggregates, ", "))
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a CreateDownsampleStatement.
+func (s *CreateDownsampleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *CreateDownsampleStatement) DefaultDatabase() string {
+	return s.Database
+}
+
+// DropDownsampleStatement represents a command for removing a downsample rule.
+type DropDownsampleStatement struct {
+	Database string
+	Source   string
+	Target   string
+}
+
+// String returns a string representation of the statement.
+func (s *DropDownsampleStatement) String() string {
+	return fmt.Sprintf("DROP DOWNSAMPLE ON %s FROM %s TO %s", QuoteIdent(s.Database), QuoteIdent(s.Source), QuoteIdent(s.Target))
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a DropDownsampleStatement.
+func (s *DropDownsampleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *DropDownsampleStatement) DefaultDatabase() string {
+	return s.Database
+}
+
+// ShowDroppedStatement represents a command for listing the databases,
+// measurements and shards that can be restored from the trash.
+type ShowDroppedStatement struct{}
+
+// String returns a string representation of the show dropped statement.
+func (s *ShowDroppedStatement) String() string { return "SHOW DROPPED" }
+
+// RequiredPrivileges returns the privilege required to execute a ShowDroppedStatement.
+func (s *ShowDroppedStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// ShowDownsamplesStatement represents a command for listing downsample rules.
+type ShowDownsamplesStatement struct{}
+
+// String returns a string representation of the statement.
+func (s *ShowDownsamplesStatement) String() string { return "SHOW DOWNSAMPLES" }
+
+// RequiredPrivileges returns the privilege required to execute a ShowDownsamplesStatement.
+func (s *ShowDownsamplesStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
+}
+
 // ShowMeasurementCardinalityStatement represents a command for listing measurement cardinality.
 type ShowMeasurementCardinalityStatement struct {
 	Exact         bool // If false then cardinality estimation will be used.
@@ -107,12 +107,26 @@ func init() {
 		return p.parseDeleteStatement()
 	})
 	Language.Group(SHOW).With(func(show *ParseTree) {
-		show.Group(CONTINUOUS).Handle(QUERIES, func(p *Parser) (Statement, error) {
-			return p.parseShowContinuousQueriesStatement()
+		show.Handle(COMPACTIONS, func(p *Parser) (Statement, error) {
+			return p.parseShowCompactionsStatement()
+		})
+		show.Group(CONTINUOUS).With(func(continuous *ParseTree) {
+			continuous.Handle(QUERIES, func(p *Parser) (Statement, error) {
+				return p.parseShowContinuousQueriesStatement()
+			})
+			continuous.Handle(QUERY, func(p *Parser) (Statement, error) {
+				return p.parseShowContinuousQueryStatusStatement()
+			})
 		})
 		show.Handle(DATABASES, func(p *Parser) (Statement, error) {
 			return p.parseShowDatabasesStatement()
 		})
+		show.Handle(DOWNSAMPLES, func(p *Parser) (Statement, error) {
+			return p.parseShowDownsamplesStatement()
+		})
+		show.Handle(DROPPED, func(p *Parser) (Statement, error) {
+			return p.parseShowDroppedStatement()
+		})
 		show.Handle(SERVERS, func(p *Parser) (Statement, error) {
 			return p.parseShowServersStatement()
 		})
@@ -130,6 +144,9 @@ func init() {
 		show.Group(GRANTS).Handle(FOR, func(p *Parser) (Statement, error) {
 			return p.parseGrantsForUserStatement()
 		})
+		show.Group(HINTED).Handle(HANDOFF, func(p *Parser) (Statement, error) {
+			return p.parseShowHintedHandoffStatement()
+		})
 		show.Group(MEASUREMENT).Handle(EXACT, func(p *Parser) (Statement, error) {
 			return p.parseShowMeasurementCardinalityStatement(true)
 		})
@@ -142,6 +159,9 @@ func init() {
 		show.Handle(QUERIES, func(p *Parser) (Statement, error) {
 			return p.parseShowQueriesStatement()
 		})
+		show.Handle(REBALANCE, func(p *Parser) (Statement, error) {
+			return p.parseShowRebalanceStatement()
+		})
 		show.Group(RETENTION).Handle(POLICIES, func(p *Parser) (Statement, error) {
 			return p.parseShowRetentionPoliciesStatement()
 		})
@@ -182,6 +202,9 @@ func init() {
 		create.Handle(DATABASE, func(p *Parser) (Statement, error) {
 			return p.parseCreateDatabaseStatement()
 		})
+		create.Handle(DOWNSAMPLE, func(p *Parser) (Statement, error) {
+			return p.parseCreateDownsampleStatement()
+		})
 		create.Handle(USER, func(p *Parser) (Statement, error) {
 			return p.parseCreateUserStatement()
 		})
@@ -199,6 +222,9 @@ func init() {
 		drop.Handle(DATABASE, func(p *Parser) (Statement, error) {
 			return p.parseDropDatabaseStatement()
 		})
+		drop.Handle(DOWNSAMPLE, func(p *Parser) (Statement, error) {
+			return p.parseDropDownsampleStatement()
+		})
 		drop.Handle(MEASUREMENT, func(p *Parser) (Statement, error) {
 			return p.parseDropMeasurementStatement()
 		})
@@ -227,13 +253,48 @@ func init() {
 	Language.Handle(REVOKE, func(p *Parser) (Statement, error) {
 		return p.parseRevokeStatement()
 	})
+	Language.Group(COMPACT).Handle(SHARD, func(p *Parser) (Statement, error) {
+		return p.parseCompactShardStatement()
+	})
+	Language.Group(PAUSE).Handle(COMPACTIONS, func(p *Parser) (Statement, error) {
+		return p.parsePauseCompactionsStatement()
+	})
+	Language.Group(RESUME).Handle(COMPACTIONS, func(p *Parser) (Statement, error) {
+		return p.parseResumeCompactionsStatement()
+	})
+	Language.Group(BACKFILL, CONTINUOUS).Handle(QUERY, func(p *Parser) (Statement, error) {
+		return p.parseBackfillContinuousQueryStatement()
+	})
+	Language.Group(ALTER).Handle(DATABASE, func(p *Parser) (Statement, error) {
+		return p.parseAlterDatabaseStatement()
+	})
+	Language.Group(ALTER).Handle(MEASUREMENT, func(p *Parser) (Statement, error) {
+		return p.parseAlterFieldTypeStatement()
+	})
 	Language.Group(ALTER, RETENTION).Handle(POLICY, func(p *Parser) (Statement, error) {
 		return p.parseAlterRetentionPolicyStatement()
 	})
 	Language.Group(SET, PASSWORD).Handle(FOR, func(p *Parser) (Statement, error) {
 		return p.parseSetPasswordUserStatement()
 	})
+	Language.Group(SET).Handle(TRASH, func(p *Parser) (Statement, error) {
+		return p.parseSetTrashStatement()
+	})
 	Language.Group(KILL).Handle(QUERY, func(p *Parser) (Statement, error) {
 		return p.parseKillQueryStatement()
 	})
+	Language.Group(RESTORE).Handle(SHARD, func(p *Parser) (Statement, error) {
+		return p.parseRestoreShardStatement()
+	})
+	Language.Group(UNDROP).With(func(undrop *ParseTree) {
+		undrop.Handle(DATABASE, func(p *Parser) (Statement, error) {
+			return p.parseUndropDatabaseStatement()
+		})
+		undrop.Handle(MEASUREMENT, func(p *Parser) (Statement, error) {
+			return p.parseUndropMeasurementStatement()
+		})
+		undrop.Handle(SHARD, func(p *Parser) (Statement, error) {
+			return p.parseUndropShardStatement()
+		})
+	})
 }
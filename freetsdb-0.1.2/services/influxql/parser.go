@@ -270,6 +270,61 @@ func (p *Parser) parseCreateRetentionPolicyStatement() (*CreateRetentionPolicySt
 		p.Unscan()
 	}
 
+	// Parse optional CONSISTENCY token.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == CONSISTENCY {
+		level, err := p.parseConsistencyLevel(false)
+		if err != nil {
+			return nil, err
+		}
+		stmt.Consistency = level
+	} else {
+		p.Unscan()
+	}
+
+	// Parse optional COLD AFTER option.
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.EqualFold(lit, "COLD") {
+		d, err := p.parseColdAfter()
+		if err != nil {
+			return nil, err
+		}
+		stmt.ColdAfter = d
+	} else {
+		p.Unscan()
+	}
+
+	// Parse optional DUPLICATES option.
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.EqualFold(lit, "DUPLICATES") {
+		policy, err := p.parseDuplicatePolicy()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Duplicates = policy
+	} else {
+		p.Unscan()
+	}
+
+	// Parse optional STRING CODEC option.
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.EqualFold(lit, "STRING") {
+		codec, err := p.parseCodec("snappy", "zstd")
+		if err != nil {
+			return nil, err
+		}
+		stmt.StringCodec = codec
+	} else {
+		p.Unscan()
+	}
+
+	// Parse optional FLOAT CODEC option.
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.EqualFold(lit, "FLOAT") {
+		codec, err := p.parseCodec("gorilla", "zstd")
+		if err != nil {
+			return nil, err
+		}
+		stmt.FloatCodec = codec
+	} else {
+		p.Unscan()
+	}
+
 	// Parse optional DEFAULT token.
 	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == DEFAULT {
 		stmt.Default = true
@@ -307,8 +362,11 @@ func (p *Parser) parseAlterRetentionPolicyStatement() (*AlterRetentionPolicyStat
 	}
 	stmt.Database = ident
 
-	// Loop through option tokens (DURATION, REPLICATION, SHARD DURATION, DEFAULT, etc.).
+	// Loop through option tokens (DURATION, REPLICATION, SHARD DURATION, CONSISTENCY, DUPLICATES, STRING CODEC, DEFAULT, etc.).
+	// Options named by identifiers are not keywords, so they are counted
+	// separately and checked for duplicates one by one.
 	found := make(map[Token]struct{})
+	identOptions := 0
 Loop:
 	for {
 		tok, pos, lit := p.ScanIgnoreWhitespace()
@@ -353,11 +411,72 @@ Loop:
 			} else {
 				return nil, newParseError(tokstr(tok, lit), []string{"DURATION"}, pos)
 			}
+		case CONSISTENCY:
+			level, err := p.parseConsistencyLevel(true)
+			if err != nil {
+				return nil, err
+			}
+			stmt.Consistency = &level
 		case DEFAULT:
 			stmt.Default = true
+		case IDENT:
+			if strings.EqualFold(lit, "DUPLICATES") {
+				if stmt.Duplicates != nil {
+					return nil, &ParseError{
+						Message: "found duplicate DUPLICATES option",
+						Pos:     pos,
+					}
+				}
+
+				policy, err := p.parseDuplicatePolicy()
+				if err != nil {
+					return nil, err
+				}
+				stmt.Duplicates = &policy
+				identOptions++
+				continue
+			} else if strings.EqualFold(lit, "STRING") || strings.EqualFold(lit, "FLOAT") {
+				codec, name, supported := &stmt.StringCodec, "STRING CODEC", []string{"snappy", "zstd"}
+				if strings.EqualFold(lit, "FLOAT") {
+					codec, name, supported = &stmt.FloatCodec, "FLOAT CODEC", []string{"gorilla", "zstd"}
+				}
+				if *codec != nil {
+					return nil, &ParseError{
+						Message: fmt.Sprintf("found duplicate %s option", name),
+						Pos:     pos,
+					}
+				}
+
+				c, err := p.parseCodec(supported...)
+				if err != nil {
+					return nil, err
+				}
+				*codec = &c
+				identOptions++
+				continue
+			} else if !strings.EqualFold(lit, "COLD") {
+				if len(found) == 0 && identOptions == 0 {
+					return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "REPLICATION", "SHARD", "CONSISTENCY", "COLD", "DUPLICATES", "STRING", "FLOAT", "DEFAULT"}, pos)
+				}
+				p.Unscan()
+				break Loop
+			} else if stmt.ColdAfter != nil {
+				return nil, &ParseError{
+					Message: "found duplicate COLD AFTER option",
+					Pos:     pos,
+				}
+			}
+
+			d, err := p.parseColdAfter()
+			if err != nil {
+				return nil, err
+			}
+			stmt.ColdAfter = &d
+			identOptions++
+			continue
 		default:
-			if len(found) == 0 {
-				return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "REPLICATION", "SHARD", "DEFAULT"}, pos)
+			if len(found) == 0 && identOptions == 0 {
+				return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "REPLICATION", "SHARD", "CONSISTENCY", "COLD", "DUPLICATES", "STRING", "FLOAT", "DEFAULT"}, pos)
 			}
 			p.Unscan()
 			break Loop
@@ -365,9 +484,158 @@ Loop:
 		found[tok] = struct{}{}
 	}
 
+	// Parse the optional DRY RUN suffix. DRY and RUN are not keywords so
+	// they can still be used as identifiers.
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.EqualFold(lit, "DRY") {
+		if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || !strings.EqualFold(lit, "RUN") {
+			return nil, newParseError(tokstr(tok, lit), []string{"RUN"}, pos)
+		}
+		stmt.DryRun = true
+	} else {
+		p.Unscan()
+	}
+
 	return stmt, nil
 }
 
+// parseColdAfter parses the duration of a COLD AFTER option. An INF duration
+// keeps shard groups in the data directory.
+// This function assumes the COLD token has already been consumed.
+func (p *Parser) parseColdAfter() (time.Duration, error) {
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || !strings.EqualFold(lit, "AFTER") {
+		return 0, newParseError(tokstr(tok, lit), []string{"AFTER"}, pos)
+	}
+	return p.ParseDuration()
+}
+
+// parseDuplicatePolicy parses the policy of a DUPLICATES option and returns
+// it in lower case.
+// This function assumes the DUPLICATES token has already been consumed.
+func (p *Parser) parseDuplicatePolicy() (string, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok == IDENT {
+		switch policy := strings.ToLower(lit); policy {
+		case "last", "first", "max", "keep":
+			return policy, nil
+		}
+	}
+	return "", newParseError(tokstr(tok, lit), []string{"LAST", "FIRST", "MAX", "KEEP"}, pos)
+}
+
+// parseCodec parses the codec of a STRING CODEC or FLOAT CODEC option, one of
+// supported, and returns it in lower case.
+// This function assumes the STRING or FLOAT token has already been consumed.
+func (p *Parser) parseCodec(supported ...string) (string, error) {
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || !strings.EqualFold(lit, "CODEC") {
+		return "", newParseError(tokstr(tok, lit), []string{"CODEC"}, pos)
+	}
+
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok == IDENT {
+		for _, codec := range supported {
+			if strings.EqualFold(lit, codec) {
+				return codec, nil
+			}
+		}
+	}
+
+	expected := make([]string, len(supported))
+	for i, codec := range supported {
+		expected[i] = strings.ToUpper(codec)
+	}
+	return "", newParseError(tokstr(tok, lit), expected, pos)
+}
+
+// parseAlterDatabaseStatement parses a string and returns an alter database statement.
+// This function assumes the ALTER DATABASE tokens have already been consumed.
+func (p *Parser) parseAlterDatabaseStatement() (*AlterDatabaseStatement, error) {
+	stmt := &AlterDatabaseStatement{}
+
+	// Parse the database name.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = ident
+
+	// Parse required CONSISTENCY token.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != CONSISTENCY {
+		return nil, newParseError(tokstr(tok, lit), []string{"CONSISTENCY"}, pos)
+	}
+
+	stmt.Consistency, err = p.parseConsistencyLevel(true)
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseAlterFieldTypeStatement parses a string and returns an alter field type
+// statement. This function assumes the ALTER MEASUREMENT tokens have already
+// been consumed.
+func (p *Parser) parseAlterFieldTypeStatement() (*AlterFieldTypeStatement, error) {
+	var err error
+	stmt := &AlterFieldTypeStatement{}
+
+	// Parse the measurement name.
+	if stmt.Measurement, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	// Parse the required ALTER FIELD tokens.
+	if err := p.parseTokens([]Token{ALTER, FIELD}); err != nil {
+		return nil, err
+	}
+
+	// Parse the field name.
+	if stmt.Field, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	// Parse the required TYPE keyword and the numeric type to convert to.
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != IDENT || !strings.EqualFold(lit, "TYPE") {
+		return nil, newParseError(tokstr(tok, lit), []string{"TYPE"}, pos)
+	}
+	tok, pos, lit = p.ScanIgnoreWhitespace()
+	if tok == IDENT {
+		switch typ := DataTypeFromString(strings.ToLower(lit)); typ {
+		case Float, Integer, Unsigned:
+			stmt.Type = typ
+			return stmt, nil
+		}
+	}
+	return nil, newParseError(tokstr(tok, lit), []string{"float", "integer", "unsigned"}, pos)
+}
+
+// parseConsistencyLevel parses a write consistency level and returns it in
+// lower case. If allowDefault is true, DEFAULT is accepted and returned as an
+// empty string.
+func (p *Parser) parseConsistencyLevel(allowDefault bool) (string, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch tok {
+	case ANY:
+		return "any", nil
+	case ALL:
+		return "all", nil
+	case IDENT:
+		switch level := strings.ToLower(lit); level {
+		case "one", "quorum":
+			return level, nil
+		}
+	case DEFAULT:
+		if allowDefault {
+			return "", nil
+		}
+	}
+
+	expected := []string{"ANY", "ONE", "QUORUM", "ALL"}
+	if allowDefault {
+		expected = append(expected, "DEFAULT")
+	}
+	return "", newParseError(tokstr(tok, lit), expected, pos)
+}
+
 // ParseInt parses a string representing a base 10 integer and returns the number.
 // It returns an error if the parsed number is outside the range [min, max].
 func (p *Parser) ParseInt(min, max int) (int, error) {
@@ -1614,12 +1882,173 @@ func (p *Parser) parseDropShardStatement() (*DropShardStatement, error) {
 	return stmt, nil
 }
 
+// parseRestoreShardStatement parses a string and returns a
+// RestoreShardStatement. This function assumes the "RESTORE SHARD" tokens
+// have already been consumed.
+func (p *Parser) parseRestoreShardStatement() (*RestoreShardStatement, error) {
+	var err error
+	stmt := &RestoreShardStatement{}
+
+	// Parse the ID of the shard to be restored.
+	if stmt.ID, err = p.ParseUInt64(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseCompactShardStatement parses a string and returns a
+// CompactShardStatement. This function assumes the "COMPACT SHARD" tokens
+// have already been consumed.
+func (p *Parser) parseCompactShardStatement() (*CompactShardStatement, error) {
+	var err error
+	stmt := &CompactShardStatement{}
+
+	// Parse the ID of the shard to be compacted.
+	if stmt.ID, err = p.ParseUInt64(); err != nil {
+		return nil, err
+	}
+
+	// Parse the optional FULL or OPTIMIZE keyword.
+	tok, _, lit := p.ScanIgnoreWhitespace()
+	if tok == IDENT && strings.EqualFold(lit, "OPTIMIZE") {
+		stmt.Optimize = true
+	} else if tok != IDENT || !strings.EqualFold(lit, "FULL") {
+		p.Unscan()
+	}
+	return stmt, nil
+}
+
+// parseShowCompactionsStatement parses a string and returns a
+// ShowCompactionsStatement. This function assumes the "SHOW COMPACTIONS"
+// tokens have already been consumed.
+func (p *Parser) parseShowCompactionsStatement() (*ShowCompactionsStatement, error) {
+	return &ShowCompactionsStatement{}, nil
+}
+
+// parsePauseCompactionsStatement parses a string and returns a
+// PauseCompactionsStatement. This function assumes the "PAUSE COMPACTIONS"
+// tokens have already been consumed.
+func (p *Parser) parsePauseCompactionsStatement() (*PauseCompactionsStatement, error) {
+	var err error
+	stmt := &PauseCompactionsStatement{}
+
+	// Parse the required ON token and the database name.
+	if err := p.parseTokens([]Token{ON}); err != nil {
+		return nil, err
+	}
+	if stmt.Database, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseResumeCompactionsStatement parses a string and returns a
+// ResumeCompactionsStatement. This function assumes the "RESUME COMPACTIONS"
+// tokens have already been consumed.
+func (p *Parser) parseResumeCompactionsStatement() (*ResumeCompactionsStatement, error) {
+	var err error
+	stmt := &ResumeCompactionsStatement{}
+
+	// Parse the required ON token and the database name.
+	if err := p.parseTokens([]Token{ON}); err != nil {
+		return nil, err
+	}
+	if stmt.Database, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseSetTrashStatement parses a string and returns a SetTrashStatement.
+// This function assumes the "SET TRASH" tokens have already been consumed.
+func (p *Parser) parseSetTrashStatement() (*SetTrashStatement, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok == ON {
+		return &SetTrashStatement{Enabled: true}, nil
+	} else if tok == IDENT && strings.EqualFold(lit, "OFF") {
+		return &SetTrashStatement{Enabled: false}, nil
+	}
+	return nil, newParseError(tokstr(tok, lit), []string{"ON", "OFF"}, pos)
+}
+
+// parseUndropDatabaseStatement parses a string and returns an
+// UndropDatabaseStatement. This function assumes the "UNDROP DATABASE" tokens
+// have already been consumed.
+func (p *Parser) parseUndropDatabaseStatement() (*UndropDatabaseStatement, error) {
+	var err error
+	stmt := &UndropDatabaseStatement{}
+
+	// Parse the name of the database to be restored.
+	if stmt.Name, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseUndropMeasurementStatement parses a string and returns an
+// UndropMeasurementStatement. This function assumes the "UNDROP MEASUREMENT"
+// tokens have already been consumed.
+func (p *Parser) parseUndropMeasurementStatement() (*UndropMeasurementStatement, error) {
+	var err error
+	stmt := &UndropMeasurementStatement{}
+
+	// Parse the name of the measurement to be restored.
+	if stmt.Name, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseUndropShardStatement parses a string and returns an
+// UndropShardStatement, or an UndropShardGroupStatement for "UNDROP SHARD
+// GROUP". This function assumes the "UNDROP SHARD" tokens have already been
+// consumed.
+func (p *Parser) parseUndropShardStatement() (Statement, error) {
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == GROUP {
+		return p.parseUndropShardGroupStatement()
+	}
+	p.Unscan()
+
+	var err error
+	stmt := &UndropShardStatement{}
+
+	// Parse the ID of the shard to be restored.
+	if stmt.ID, err = p.ParseUInt64(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseUndropShardGroupStatement parses a string and returns an
+// UndropShardGroupStatement. This function assumes the "UNDROP SHARD GROUP"
+// tokens have already been consumed.
+func (p *Parser) parseUndropShardGroupStatement() (*UndropShardGroupStatement, error) {
+	var err error
+	stmt := &UndropShardGroupStatement{}
+
+	// Parse the ID of the shard group to be recovered.
+	if stmt.ID, err = p.ParseUInt64(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
 // parseShowContinuousQueriesStatement parses a string and returns a ShowContinuousQueriesStatement.
 // This function assumes the "SHOW CONTINUOUS" tokens have already been consumed.
 func (p *Parser) parseShowContinuousQueriesStatement() (*ShowContinuousQueriesStatement, error) {
 	return &ShowContinuousQueriesStatement{}, nil
 }
 
+// parseShowContinuousQueryStatusStatement parses a string and returns a ShowContinuousQueryStatusStatement.
+// This function assumes the "SHOW CONTINUOUS QUERY" tokens have already been consumed.
+func (p *Parser) parseShowContinuousQueryStatusStatement() (*ShowContinuousQueryStatusStatement, error) {
+	// STATUS is not a keyword so it can still be used as an identifier.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || !strings.EqualFold(lit, "STATUS") {
+		return nil, newParseError(tokstr(tok, lit), []string{"STATUS"}, pos)
+	}
+	return &ShowContinuousQueryStatusStatement{}, nil
+}
+
 // parseShowServersStatement parses a string and returns a ShowServersStatement.
 // This function assumes the "SHOW SERVERS" tokens have already been consumed.
 func (p *Parser) parseShowServersStatement() (*ShowServersStatement, error) {
@@ -1627,6 +2056,18 @@ func (p *Parser) parseShowServersStatement() (*ShowServersStatement, error) {
 	return stmt, nil
 }
 
+// parseShowRebalanceStatement parses a string and returns a ShowRebalanceStatement.
+// This function assumes the "SHOW REBALANCE" tokens have already been consumed.
+func (p *Parser) parseShowRebalanceStatement() (*ShowRebalanceStatement, error) {
+	return &ShowRebalanceStatement{}, nil
+}
+
+// parseShowHintedHandoffStatement parses a string and returns a ShowHintedHandoffStatement.
+// This function assumes the "SHOW HINTED HANDOFF" tokens have already been consumed.
+func (p *Parser) parseShowHintedHandoffStatement() (*ShowHintedHandoffStatement, error) {
+	return &ShowHintedHandoffStatement{}, nil
+}
+
 // parseGrantsForUserStatement parses a string and returns a ShowGrantsForUserStatement.
 // This function assumes the "SHOW GRANTS" tokens have already been consumed.
 func (p *Parser) parseGrantsForUserStatement() (*ShowGrantsForUserStatement, error) {
@@ -1734,16 +2175,17 @@ func (p *Parser) parseCreateDatabaseStatement() (*CreateDatabaseStatement, error
 
 	// Look for "WITH"
 	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == WITH {
-		// validate that at least one of DURATION, NAME, REPLICATION or SHARD is provided
+		// validate that at least one of DURATION, NAME, REPLICATION, SHARD or CONSISTENCY is provided
 		tok, pos, lit := p.ScanIgnoreWhitespace()
-		if tok != DURATION && tok != NAME && tok != REPLICATION && tok != SHARD {
-			return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "NAME", "REPLICATION", "SHARD"}, pos)
+		if tok != DURATION && tok != NAME && tok != REPLICATION && tok != SHARD && tok != CONSISTENCY {
+			return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "NAME", "REPLICATION", "SHARD", "CONSISTENCY"}, pos)
 		}
 		// rewind
 		p.Unscan()
 
-		// mark statement as having a RetentionPolicyInfo defined
-		stmt.RetentionPolicyCreate = true
+		// mark statement as having a RetentionPolicyInfo defined, unless
+		// only the database's write consistency is given
+		stmt.RetentionPolicyCreate = tok != CONSISTENCY
 
 		// Look for "DURATION"
 		if err := p.parseTokens([]Token{DURATION}); err != nil {
@@ -1791,6 +2233,16 @@ func (p *Parser) parseCreateDatabaseStatement() (*CreateDatabaseStatement, error
 				return nil, err
 			}
 		}
+
+		// Look for "CONSISTENCY"
+		if err := p.parseTokens([]Token{CONSISTENCY}); err != nil {
+			p.Unscan()
+		} else {
+			stmt.Consistency, err = p.parseConsistencyLevel(false)
+			if err != nil {
+				return nil, err
+			}
+		}
 	} else {
 		p.Unscan()
 	}
@@ -2017,6 +2469,190 @@ func (p *Parser) parseDropContinuousQueryStatement() (*DropContinuousQueryStatem
 	return stmt, nil
 }
 
+// parseBackfillContinuousQueryStatement parses a string and returns a BackfillContinuousQueryStatement.
+// This function assumes the "BACKFILL CONTINUOUS QUERY" tokens have already been consumed.
+func (p *Parser) parseBackfillContinuousQueryStatement() (*BackfillContinuousQueryStatement, error) {
+	stmt := &BackfillContinuousQueryStatement{}
+
+	// Read the id of the query to backfill.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = ident
+
+	// Expect an "ON" keyword.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+
+	// Read the name of the database of the query.
+	if ident, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	stmt.Database = ident
+
+	// Parse the time range to compute.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != FROM {
+		return nil, newParseError(tokstr(tok, lit), []string{"FROM"}, pos)
+	}
+	if stmt.StartTime, err = p.parseTime(); err != nil {
+		return nil, err
+	}
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != TO {
+		return nil, newParseError(tokstr(tok, lit), []string{"TO"}, pos)
+	}
+	if stmt.EndTime, err = p.parseTime(); err != nil {
+		return nil, err
+	}
+
+	if !stmt.EndTime.After(stmt.StartTime) {
+		return nil, &ParseError{Message: "end time must be after start time"}
+	}
+	return stmt, nil
+}
+
+// parseTime parses a time given as a date time string or as an integer of
+// nanoseconds since the epoch.
+func (p *Parser) parseTime() (time.Time, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch tok {
+	case STRING:
+		s := &StringLiteral{Val: lit}
+		if !s.IsTimeLiteral() {
+			return time.Time{}, &ParseError{Message: fmt.Sprintf("invalid time: %s", lit), Pos: pos}
+		}
+		t, err := s.ToTimeLiteral(time.UTC)
+		if err != nil {
+			return time.Time{}, &ParseError{Message: err.Error(), Pos: pos}
+		}
+		return t.Val, nil
+	case INTEGER:
+		n, err := strconv.ParseInt(lit, 10, 64)
+		if err != nil {
+			return time.Time{}, &ParseError{Message: err.Error(), Pos: pos}
+		}
+		return time.Unix(0, n).UTC(), nil
+	default:
+		return time.Time{}, newParseError(tokstr(tok, lit), []string{"time string", "integer"}, pos)
+	}
+}
+
+// downsampleAggregates are the functions a downsample rule can apply. They all
+// return a single point per interval.
+var downsampleAggregates = map[string]struct{}{
+	"count":  {},
+	"first":  {},
+	"last":   {},
+	"max":    {},
+	"mean":   {},
+	"median": {},
+	"min":    {},
+	"mode":   {},
+	"spread": {},
+	"stddev": {},
+	"sum":    {},
+}
+
+// parseCreateDownsampleStatement parses a string and returns a CreateDownsampleStatement.
+// This function assumes the "CREATE DOWNSAMPLE" tokens have already been consumed.
+func (p *Parser) parseCreateDownsampleStatement() (*CreateDownsampleStatement, error) {
+	stmt := &CreateDownsampleStatement{}
+
+	var err error
+	if stmt.Database, stmt.Source, stmt.Target, err = p.parseDownsamplePolicies(); err != nil {
+		return nil, err
+	}
+
+	// Parse the interval of the aggregates.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != EVERY {
+		return nil, newParseError(tokstr(tok, lit), []string{"EVERY"}, pos)
+	}
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != DURATIONVAL {
+		return nil, newParseError(tokstr(tok, lit), []string{"duration"}, pos)
+	}
+	if stmt.Interval, err = ParseDuration(lit); err != nil {
+		return nil, &ParseError{Message: err.Error(), Pos: pos}
+	} else if stmt.Interval <= 0 {
+		return nil, &ParseError{Message: "EVERY duration must be positive", Pos: pos}
+	}
+
+	// Parse the aggregates. AGGREGATE is not a keyword so it can still be
+	// used as an identifier.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || !strings.EqualFold(lit, "AGGREGATE") {
+		return nil, newParseError(tokstr(tok, lit), []string{"AGGREGATE"}, pos)
+	}
+	_, pos, _ = p.ScanIgnoreWhitespace()
+	p.Unscan()
+	names, err := p.ParseIdentList()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if _, ok := downsampleAggregates[name]; !ok {
+			return nil, &ParseError{Message: fmt.Sprintf("unsupported downsample aggregate: %s", name), Pos: pos}
+		} else if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		stmt.Aggregates = append(stmt.Aggregates, name)
+	}
+	return stmt, nil
+}
+
+// parseDropDownsampleStatement parses a string and returns a DropDownsampleStatement.
+// This function assumes the "DROP DOWNSAMPLE" tokens have already been consumed.
+func (p *Parser) parseDropDownsampleStatement() (*DropDownsampleStatement, error) {
+	stmt := &DropDownsampleStatement{}
+
+	var err error
+	if stmt.Database, stmt.Source, stmt.Target, err = p.parseDownsamplePolicies(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseDownsamplePolicies parses the "ON <db> FROM <rp> TO <rp>" clause of
+// the downsample statements.
+func (p *Parser) parseDownsamplePolicies() (database, source, target string, err error) {
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return "", "", "", newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+	if database, err = p.ParseIdent(); err != nil {
+		return "", "", "", err
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != FROM {
+		return "", "", "", newParseError(tokstr(tok, lit), []string{"FROM"}, pos)
+	}
+	if source, err = p.ParseIdent(); err != nil {
+		return "", "", "", err
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != TO {
+		return "", "", "", newParseError(tokstr(tok, lit), []string{"TO"}, pos)
+	}
+	if target, err = p.ParseIdent(); err != nil {
+		return "", "", "", err
+	}
+	return database, source, target, nil
+}
+
+// parseShowDroppedStatement parses a string and returns a ShowDroppedStatement.
+// This function assumes the "SHOW DROPPED" tokens have already been consumed.
+func (p *Parser) parseShowDroppedStatement() (*ShowDroppedStatement, error) {
+	return &ShowDroppedStatement{}, nil
+}
+
+// parseShowDownsamplesStatement parses a string and returns a ShowDownsamplesStatement.
+// This function assumes the "SHOW DOWNSAMPLES" tokens have already been consumed.
+func (p *Parser) parseShowDownsamplesStatement() (*ShowDownsamplesStatement, error) {
+	return &ShowDownsamplesStatement{}, nil
+}
+
 // parseFields parses a list of one or more fields.
 func (p *Parser) parseFields() (Fields, error) {
 	var fields Fields
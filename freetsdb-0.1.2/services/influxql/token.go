@@ -71,9 +71,13 @@ const (
 	ANY
 	AS
 	ASC
+	BACKFILL
 	BEGIN
 	BY
 	CARDINALITY
+	COMPACT
+	COMPACTIONS
+	CONSISTENCY
 	CREATE
 	CONTINUOUS
 	DATABASE
@@ -85,7 +89,10 @@ const (
 	DESTINATIONS
 	DIAGNOSTICS
 	DISTINCT
+	DOWNSAMPLE
+	DOWNSAMPLES
 	DROP
+	DROPPED
 	DURATION
 	END
 	EVERY
@@ -98,6 +105,8 @@ const (
 	GRANTS
 	GROUP
 	GROUPS
+	HANDOFF
+	HINTED
 	IN
 	INF
 	INSERT
@@ -113,14 +122,18 @@ const (
 	ON
 	ORDER
 	PASSWORD
+	PAUSE
 	POLICY
 	POLICIES
 	PRIVILEGES
 	QUERIES
 	QUERY
 	READ
+	REBALANCE
 	REPLICATION
 	RESAMPLE
+	RESTORE
+	RESUME
 	RETENTION
 	REVOKE
 	SELECT
@@ -136,6 +149,8 @@ const (
 	SUBSCRIPTIONS
 	TAG
 	TO
+	TRASH
+	UNDROP
 	USER
 	USERS
 	VALUES
@@ -195,9 +210,13 @@ var tokens = [...]string{
 	ANY:           "ANY",
 	AS:            "AS",
 	ASC:           "ASC",
+	BACKFILL:      "BACKFILL",
 	BEGIN:         "BEGIN",
 	BY:            "BY",
 	CARDINALITY:   "CARDINALITY",
+	COMPACT:       "COMPACT",
+	COMPACTIONS:   "COMPACTIONS",
+	CONSISTENCY:   "CONSISTENCY",
 	CREATE:        "CREATE",
 	CONTINUOUS:    "CONTINUOUS",
 	DATABASE:      "DATABASE",
@@ -209,7 +228,10 @@ var tokens = [...]string{
 	DESTINATIONS:  "DESTINATIONS",
 	DIAGNOSTICS:   "DIAGNOSTICS",
 	DISTINCT:      "DISTINCT",
+	DOWNSAMPLE:    "DOWNSAMPLE",
+	DOWNSAMPLES:   "DOWNSAMPLES",
 	DROP:          "DROP",
+	DROPPED:       "DROPPED",
 	DURATION:      "DURATION",
 	END:           "END",
 	EVERY:         "EVERY",
@@ -222,6 +244,8 @@ var tokens = [...]string{
 	GRANTS:        "GRANTS",
 	GROUP:         "GROUP",
 	GROUPS:        "GROUPS",
+	HANDOFF:       "HANDOFF",
+	HINTED:        "HINTED",
 	IN:            "IN",
 	INF:           "INF",
 	INSERT:        "INSERT",
@@ -237,14 +261,18 @@ var tokens = [...]string{
 	ON:            "ON",
 	ORDER:         "ORDER",
 	PASSWORD:      "PASSWORD",
+	PAUSE:         "PAUSE",
 	POLICY:        "POLICY",
 	POLICIES:      "POLICIES",
 	PRIVILEGES:    "PRIVILEGES",
 	QUERIES:       "QUERIES",
 	QUERY:         "QUERY",
 	READ:          "READ",
+	REBALANCE:     "REBALANCE",
 	REPLICATION:   "REPLICATION",
 	RESAMPLE:      "RESAMPLE",
+	RESTORE:       "RESTORE",
+	RESUME:        "RESUME",
 	RETENTION:     "RETENTION",
 	REVOKE:        "REVOKE",
 	SELECT:        "SELECT",
@@ -260,6 +288,8 @@ var tokens = [...]string{
 	SUBSCRIPTIONS: "SUBSCRIPTIONS",
 	TAG:           "TAG",
 	TO:            "TO",
+	TRASH:         "TRASH",
+	UNDROP:        "UNDROP",
 	USER:          "USER",
 	USERS:         "USERS",
 	VALUES:        "VALUES",
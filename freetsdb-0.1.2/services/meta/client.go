@@ -18,6 +18,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -285,6 +286,47 @@ func (c *Client) CreateDataNode(httpAddr, tcpAddr string) (*NodeInfo, error) {
 	return n, nil
 }
 
+// DataNodeHeartbeat tells the meta leader that the data node with the given
+// ID is alive. Each meta server is tried in turn until one accepts it.
+func (c *Client) DataNodeHeartbeat(id uint64) error {
+	c.mu.RLock()
+	servers := append([]string(nil), c.metaServers...)
+	c.mu.RUnlock()
+
+	var err error
+	for _, server := range servers {
+		if err = c.dataNodeHeartbeat(server, id); err == nil || err == ErrNodeNotFound {
+			return err
+		}
+	}
+	return err
+}
+
+func (c *Client) dataNodeHeartbeat(server string, id uint64) error {
+	url := fmt.Sprintf("%s/heartbeat?nodeid=%d", c.url(server), id)
+
+	resp, err := http.Post(url, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrNodeNotFound
+	case http.StatusServiceUnavailable:
+		return ErrServiceUnavailable
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("meta service: %s", strings.TrimSpace(string(b)))
+}
+
 // DataNodeByHTTPHost returns the data node with the give http bind address
 func (c *Client) DataNodeByHTTPHost(httpAddr string) (*NodeInfo, error) {
 	nodes, _ := c.DataNodes()
@@ -376,19 +418,22 @@ func (c *Client) CreateDatabase(name string) (*DatabaseInfo, error) {
 
 // CreateDatabaseWithRetentionPolicy creates a database with the specified retention policy.
 func (c *Client) CreateDatabaseWithRetentionPolicy(name string, rpi *RetentionPolicyInfo) (*DatabaseInfo, error) {
+	if rpi == nil {
+		return nil, ErrRetentionPolicyRequired
+	}
+
 	if rpi.Duration < MinRetentionPolicyDuration && rpi.Duration != 0 {
 		return nil, ErrRetentionPolicyDurationTooLow
 	}
 
 	if db := c.Database(name); db != nil {
-		// Check if the retention policy already exists. If it does and matches
-		// the desired retention policy, exit with no error.
-		if rp := db.RetentionPolicy(rpi.Name); rp != nil {
-			if rp.ReplicaN != rpi.ReplicaN || rp.Duration != rpi.Duration {
-				return nil, ErrRetentionPolicyConflict
-			}
-			return db, nil
+		// The database already exists. It is only not an error if the retention
+		// policy exists, matches the desired retention policy and is the default.
+		rp := db.RetentionPolicy(rpi.Name)
+		if rp == nil || !rpi.matches(rp) || db.DefaultRetentionPolicy != rp.Name {
+			return nil, ErrRetentionPolicyConflict
 		}
+		return db, nil
 	}
 
 	cmd := &internal.CreateDatabaseCommand{
@@ -415,15 +460,20 @@ func (c *Client) DropDatabase(name string) error {
 
 // CreateRetentionPolicy creates a retention policy on the specified database.
 func (c *Client) CreateRetentionPolicy(database string, rpi *RetentionPolicyInfo) (*RetentionPolicyInfo, error) {
+	if rpi.Duration < MinRetentionPolicyDuration && rpi.Duration != 0 {
+		return nil, ErrRetentionPolicyDurationTooLow
+	} else if rpi.Duration > 0 && rpi.Duration < normalisedShardDuration(rpi.ShardGroupDuration, rpi.Duration) {
+		return nil, ErrIncompatibleDurations
+	}
 
+	// Creating an existing policy is only not an error if nothing differs.
 	if rp, _ := c.RetentionPolicy(database, rpi.Name); rp != nil {
+		if !rpi.matches(rp) {
+			return nil, ErrRetentionPolicyExists
+		}
 		return rp, nil
 	}
 
-	if rpi.Duration < MinRetentionPolicyDuration && rpi.Duration != 0 {
-		return nil, ErrRetentionPolicyDurationTooLow
-	}
-
 	cmd := &internal.CreateRetentionPolicyCommand{
 		Database:        proto.String(database),
 		RetentionPolicy: rpi.marshal(),
@@ -468,6 +518,16 @@ func (c *Client) SetDefaultRetentionPolicy(database, name string) error {
 	return c.retryUntilExec(internal.Command_SetDefaultRetentionPolicyCommand, internal.E_SetDefaultRetentionPolicyCommand_Command, cmd)
 }
 
+// SetDatabaseConsistency sets a database's default write consistency level.
+func (c *Client) SetDatabaseConsistency(name, level string) error {
+	cmd := &internal.SetDatabaseConsistencyCommand{
+		Name:        proto.String(name),
+		Consistency: proto.String(level),
+	}
+
+	return c.retryUntilExec(internal.Command_SetDatabaseConsistencyCommand, internal.E_SetDatabaseConsistencyCommand_Command, cmd)
+}
+
 // UpdateRetentionPolicy updates a retention policy.
 func (c *Client) UpdateRetentionPolicy(database, name string, rpu *RetentionPolicyUpdate) error {
 	var newName *string
@@ -487,12 +547,30 @@ func (c *Client) UpdateRetentionPolicy(database, name string, rpu *RetentionPoli
 		replicaN = &value
 	}
 
+	var coldAfter *int64
+	if rpu.ColdAfter != nil {
+		value := int64(*rpu.ColdAfter)
+		coldAfter = &value
+	}
+
+	var shardGroupDuration *int64
+	if rpu.ShardGroupDuration != nil {
+		value := int64(*rpu.ShardGroupDuration)
+		shardGroupDuration = &value
+	}
+
 	cmd := &internal.UpdateRetentionPolicyCommand{
-		Database: proto.String(database),
-		Name:     proto.String(name),
-		NewName:  newName,
-		Duration: duration,
-		ReplicaN: replicaN,
+		Database:           proto.String(database),
+		Name:               proto.String(name),
+		NewName:            newName,
+		Duration:           duration,
+		ReplicaN:           replicaN,
+		Consistency:        rpu.Consistency,
+		ColdAfter:          coldAfter,
+		DuplicatePolicy:    rpu.DuplicatePolicy,
+		ShardGroupDuration: shardGroupDuration,
+		StringCodec:        rpu.StringCodec,
+		FloatCodec:         rpu.FloatCodec,
 	}
 
 	return c.retryUntilExec(internal.Command_UpdateRetentionPolicyCommand, internal.E_UpdateRetentionPolicyCommand_Command, cmd)
@@ -780,6 +858,58 @@ func (c *Client) DropShard(id uint64) error {
 	return c.commit(data)
 }
 
+// AddShardOwner adds a data node to the owners of a shard.
+func (c *Client) AddShardOwner(shardID, nodeID uint64) error {
+	cmd := &internal.AddShardOwnerCommand{
+		ID:     proto.Uint64(shardID),
+		NodeID: proto.Uint64(nodeID),
+	}
+
+	return c.retryUntilExec(internal.Command_AddShardOwnerCommand, internal.E_AddShardOwnerCommand_Command, cmd)
+}
+
+// RemoveShardOwner removes a data node from the owners of a shard.
+func (c *Client) RemoveShardOwner(shardID, nodeID uint64) error {
+	cmd := &internal.RemoveShardOwnerCommand{
+		ID:     proto.Uint64(shardID),
+		NodeID: proto.Uint64(nodeID),
+	}
+
+	return c.retryUntilExec(internal.Command_RemoveShardOwnerCommand, internal.E_RemoveShardOwnerCommand_Command, cmd)
+}
+
+// SetShardOwnerTier sets the storage tier a data node keeps its copy of a shard on.
+func (c *Client) SetShardOwnerTier(shardID, nodeID uint64, tier string) error {
+	cmd := &internal.SetShardOwnerTierCommand{
+		ID:     proto.Uint64(shardID),
+		NodeID: proto.Uint64(nodeID),
+		Tier:   proto.String(tier),
+	}
+
+	return c.retryUntilExec(internal.Command_SetShardOwnerTierCommand, internal.E_SetShardOwnerTierCommand_Command, cmd)
+}
+
+// ArchiveShard marks a shard as archived and removes all its owners.
+func (c *Client) ArchiveShard(shardID uint64, t time.Time) error {
+	cmd := &internal.ArchiveShardCommand{
+		ID:         proto.Uint64(shardID),
+		ArchivedAt: proto.Int64(MarshalTime(t)),
+	}
+
+	return c.retryUntilExec(internal.Command_ArchiveShardCommand, internal.E_ArchiveShardCommand_Command, cmd)
+}
+
+// UnarchiveShard makes a data node the owner of an archived shard that was
+// restored on it.
+func (c *Client) UnarchiveShard(shardID, nodeID uint64) error {
+	cmd := &internal.UnarchiveShardCommand{
+		ID:     proto.Uint64(shardID),
+		NodeID: proto.Uint64(nodeID),
+	}
+
+	return c.retryUntilExec(internal.Command_UnarchiveShardCommand, internal.E_UnarchiveShardCommand_Command, cmd)
+}
+
 // TruncateShardGroups truncates any shard group that could contain timestamps beyond t.
 func (c *Client) TruncateShardGroups(t time.Time) error {
 	c.mu.Lock()
@@ -801,7 +931,7 @@ func (c *Client) PruneShardGroups() error {
 		for j, rp := range d.RetentionPolicies {
 			var remainingShardGroups []ShardGroupInfo
 			for _, sgi := range rp.ShardGroups {
-				if sgi.DeletedAt.IsZero() || !expiration.After(sgi.DeletedAt) {
+				if sgi.DeletedAt.IsZero() || !expiration.After(sgi.DeletedAt) || !expiration.After(sgi.PurgeAt) {
 					remainingShardGroups = append(remainingShardGroups, sgi)
 					continue
 				}
@@ -853,6 +983,134 @@ func (c *Client) DeleteShardGroup(database, policy string, id uint64) error {
 	return c.retryUntilExec(internal.Command_DeleteShardGroupCommand, internal.E_DeleteShardGroupCommand_Command, cmd)
 }
 
+// ExpireShardGroup deletes a shard group but keeps it pending deletion, and
+// its shards on the data nodes, until purgeAt.
+func (c *Client) ExpireShardGroup(database, policy string, id uint64, purgeAt time.Time) error {
+	cmd := &internal.DeleteShardGroupCommand{
+		Database:     proto.String(database),
+		Policy:       proto.String(policy),
+		ShardGroupID: proto.Uint64(id),
+		PurgeAt:      proto.Int64(MarshalTime(purgeAt)),
+	}
+
+	return c.retryUntilExec(internal.Command_DeleteShardGroupCommand, internal.E_DeleteShardGroupCommand_Command, cmd)
+}
+
+// UndropShardGroup recovers a shard group that is pending deletion.
+func (c *Client) UndropShardGroup(id uint64) error {
+	cmd := &internal.UndropShardGroupCommand{
+		ID:        proto.Uint64(id),
+		Timestamp: proto.Int64(MarshalTime(time.Now().UTC())),
+	}
+
+	return c.retryUntilExec(internal.Command_UndropShardGroupCommand, internal.E_UndropShardGroupCommand_Command, cmd)
+}
+
+// Dropped returns the databases, measurements and shards that were moved to
+// the trash and can still be restored, oldest first.
+func (c *Client) Dropped() []DroppedInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	dropped := make([]DroppedInfo, len(c.cacheData.Dropped))
+	for i := range c.cacheData.Dropped {
+		dropped[i] = c.cacheData.Dropped[i].clone()
+	}
+	return dropped
+}
+
+// TrashDatabase drops a database but keeps its meta data so it can be
+// restored with UndropDatabase.
+func (c *Client) TrashDatabase(name string) error {
+	return c.trash(&internal.TrashCommand{
+		Type:     proto.String(DroppedDatabase),
+		Database: proto.String(name),
+	})
+}
+
+// TrashMeasurement records a dropped measurement so it can be restored with
+// UndropMeasurement.
+func (c *Client) TrashMeasurement(database, name string) error {
+	return c.trash(&internal.TrashCommand{
+		Type:     proto.String(DroppedMeasurement),
+		Database: proto.String(database),
+		Name:     proto.String(name),
+	})
+}
+
+// TrashShard drops a shard but keeps its meta data so it can be restored
+// with UndropShard.
+func (c *Client) TrashShard(id uint64) error {
+	return c.trash(&internal.TrashCommand{
+		Type:     proto.String(DroppedShard),
+		Database: proto.String(""),
+		ShardID:  proto.Uint64(id),
+	})
+}
+
+func (c *Client) trash(cmd *internal.TrashCommand) error {
+	cmd.DroppedAt = proto.Int64(MarshalTime(time.Now().UTC()))
+	return c.retryUntilExec(internal.Command_TrashCommand, internal.E_TrashCommand_Command, cmd)
+}
+
+// UndropDatabase restores the most recently dropped database name.
+func (c *Client) UndropDatabase(name string) error {
+	return c.undrop(&internal.UndropCommand{
+		Type:     proto.String(DroppedDatabase),
+		Database: proto.String(name),
+	})
+}
+
+// UndropMeasurement removes the record of the most recently dropped
+// measurement name of a database.
+func (c *Client) UndropMeasurement(database, name string) error {
+	return c.undrop(&internal.UndropCommand{
+		Type:     proto.String(DroppedMeasurement),
+		Database: proto.String(database),
+		Name:     proto.String(name),
+	})
+}
+
+// UndropShard restores a dropped shard to its shard group.
+func (c *Client) UndropShard(id uint64) error {
+	return c.undrop(&internal.UndropCommand{
+		Type:     proto.String(DroppedShard),
+		Database: proto.String(""),
+		ShardID:  proto.Uint64(id),
+	})
+}
+
+func (c *Client) undrop(cmd *internal.UndropCommand) error {
+	return c.retryUntilExec(internal.Command_UndropCommand, internal.E_UndropCommand_Command, cmd)
+}
+
+// PruneDropped removes the records of objects dropped before t.
+func (c *Client) PruneDropped(t time.Time) error {
+	cmd := &internal.PruneDroppedCommand{
+		Before: proto.Int64(MarshalTime(t)),
+	}
+
+	return c.retryUntilExec(internal.Command_PruneDroppedCommand, internal.E_PruneDroppedCommand_Command, cmd)
+}
+
+// TrashEnabled returns true if dropped databases, measurements and shards are
+// moved to the trash of the data nodes instead of being deleted.
+func (c *Client) TrashEnabled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cacheData.TrashEnabled
+}
+
+// SetTrashEnabled sets whether the data nodes move dropped databases,
+// measurements and shards to their trash.
+func (c *Client) SetTrashEnabled(enabled bool) error {
+	cmd := &internal.SetTrashEnabledCommand{
+		Enabled: proto.Bool(enabled),
+	}
+
+	return c.retryUntilExec(internal.Command_SetTrashEnabledCommand, internal.E_SetTrashEnabledCommand_Command, cmd)
+}
+
 // PrecreateShardGroups creates shard groups whose endtime is before the 'to' time passed in, but
 // is yet to expire before 'from'. This is to avoid the need for these shards to be created when data
 // for the corresponding time range arrives. Shard creation involves Raft consensus, and precreation
@@ -1025,6 +1283,75 @@ func (c *Client) DropContinuousQuery(database, name string) error {
 	)
 }
 
+// SetContinuousQueryLastRun records the time a continuous query last ran.
+func (c *Client) SetContinuousQueryLastRun(database, name string, t time.Time) error {
+	return c.retryUntilExec(internal.Command_SetContinuousQueryLastRunCommand, internal.E_SetContinuousQueryLastRunCommand_Command,
+		&internal.SetContinuousQueryLastRunCommand{
+			Database: proto.String(database),
+			Name:     proto.String(name),
+			LastRun:  proto.Int64(MarshalTime(t)),
+		},
+	)
+}
+
+// SetContinuousQueryStatus records the outcome of an execution of a continuous
+// query, along with its last run.
+func (c *Client) SetContinuousQueryStatus(database, name string, status ContinuousQueryStatus) error {
+	cmd := &internal.SetContinuousQueryStatusCommand{
+		Database:      proto.String(database),
+		Name:          proto.String(name),
+		NodeID:        proto.Uint64(status.NodeID),
+		Time:          proto.Int64(MarshalTime(status.Time)),
+		Duration:      proto.Int64(int64(status.Duration)),
+		PointsWritten: proto.Int64(status.PointsWritten),
+		StartTime:     proto.Int64(MarshalTime(status.StartTime)),
+		EndTime:       proto.Int64(MarshalTime(status.EndTime)),
+		LastRun:       proto.Int64(MarshalTime(status.LastRun)),
+	}
+	if status.Err != "" {
+		cmd.Error = proto.String(status.Err)
+	}
+
+	return c.retryUntilExec(internal.Command_SetContinuousQueryStatusCommand, internal.E_SetContinuousQueryStatusCommand_Command, cmd)
+}
+
+// CreateDownsample adds a rule downsampling the data of a retention policy
+// into another retention policy of the database.
+func (c *Client) CreateDownsample(database, rp, target string, interval time.Duration, aggregates []string) error {
+	return c.retryUntilExec(internal.Command_CreateDownsampleCommand, internal.E_CreateDownsampleCommand_Command,
+		&internal.CreateDownsampleCommand{
+			Database:        proto.String(database),
+			RetentionPolicy: proto.String(rp),
+			Target:          proto.String(target),
+			Interval:        proto.Int64(int64(interval)),
+			Aggregates:      aggregates,
+		},
+	)
+}
+
+// DropDownsample removes a downsample rule.
+func (c *Client) DropDownsample(database, rp, target string) error {
+	return c.retryUntilExec(internal.Command_DropDownsampleCommand, internal.E_DropDownsampleCommand_Command,
+		&internal.DropDownsampleCommand{
+			Database:        proto.String(database),
+			RetentionPolicy: proto.String(rp),
+			Target:          proto.String(target),
+		},
+	)
+}
+
+// SetDownsampleLastRun records the end of the last interval a downsample rule computed.
+func (c *Client) SetDownsampleLastRun(database, rp, target string, t time.Time) error {
+	return c.retryUntilExec(internal.Command_SetDownsampleLastRunCommand, internal.E_SetDownsampleLastRunCommand_Command,
+		&internal.SetDownsampleLastRunCommand{
+			Database:        proto.String(database),
+			RetentionPolicy: proto.String(rp),
+			Target:          proto.String(target),
+			LastRun:         proto.Int64(t.UnixNano()),
+		},
+	)
+}
+
 func (c *Client) CreateSubscription(database, rp, name, mode string, destinations []string) error {
 	return c.retryUntilExec(internal.Command_CreateSubscriptionCommand, internal.E_CreateSubscriptionCommand_Command,
 		&internal.CreateSubscriptionCommand{
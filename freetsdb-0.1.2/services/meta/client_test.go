@@ -1,11 +1,8 @@
 package meta_test
 
 import (
-	"io/ioutil"
 	"os"
-	"path"
 	"reflect"
-	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -19,8 +16,9 @@ import (
 func TestMetaClient_CreateDatabaseOnly(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	if db, err := c.CreateDatabase("db0"); err != nil {
@@ -50,8 +48,9 @@ func TestMetaClient_CreateDatabaseOnly(t *testing.T) {
 func TestMetaClient_CreateDatabaseIfNotExists(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	if _, err := c.CreateDatabase("db0"); err != nil {
@@ -73,8 +72,9 @@ func TestMetaClient_CreateDatabaseIfNotExists(t *testing.T) {
 func TestMetaClient_CreateDatabaseWithRetentionPolicy(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	// Calling CreateDatabaseWithRetentionPolicy with a nil spec should return
@@ -91,7 +91,7 @@ func TestMetaClient_CreateDatabaseWithRetentionPolicy(t *testing.T) {
 		ReplicaN:           &replicaN,
 		ShardGroupDuration: 60 * time.Minute,
 	}
-	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &spec); err != nil {
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", spec.NewRetentionPolicyInfo()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -115,7 +115,7 @@ func TestMetaClient_CreateDatabaseWithRetentionPolicy(t *testing.T) {
 
 	// Recreating the exact same database with retention policy is not
 	// an error.
-	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &spec); err != nil {
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", spec.NewRetentionPolicyInfo()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -134,8 +134,9 @@ func TestMetaClient_CreateDatabaseWithRetentionPolicy(t *testing.T) {
 func TestMetaClient_CreateDatabaseWithRetentionPolicy_Conflict_Fields(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	duration := 1 * time.Hour
@@ -146,14 +147,14 @@ func TestMetaClient_CreateDatabaseWithRetentionPolicy_Conflict_Fields(t *testing
 		ReplicaN:           &replicaN,
 		ShardGroupDuration: 60 * time.Minute,
 	}
-	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &spec); err != nil {
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", spec.NewRetentionPolicyInfo()); err != nil {
 		t.Fatal(err)
 	}
 
 	// If the rp's name is different, and error should be returned.
 	spec2 := spec
 	spec2.Name = spec.Name + "1"
-	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &spec2); err != meta.ErrRetentionPolicyConflict {
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", spec2.NewRetentionPolicyInfo()); err != meta.ErrRetentionPolicyConflict {
 		t.Fatalf("got %v, but expected %v", err, meta.ErrRetentionPolicyConflict)
 	}
 
@@ -161,7 +162,7 @@ func TestMetaClient_CreateDatabaseWithRetentionPolicy_Conflict_Fields(t *testing
 	spec2 = spec
 	duration2 := *spec.Duration + time.Minute
 	spec2.Duration = &duration2
-	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &spec2); err != meta.ErrRetentionPolicyConflict {
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", spec2.NewRetentionPolicyInfo()); err != meta.ErrRetentionPolicyConflict {
 		t.Fatalf("got %v, but expected %v", err, meta.ErrRetentionPolicyConflict)
 	}
 
@@ -169,14 +170,14 @@ func TestMetaClient_CreateDatabaseWithRetentionPolicy_Conflict_Fields(t *testing
 	spec2 = spec
 	replica2 := *spec.ReplicaN + 1
 	spec2.ReplicaN = &replica2
-	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &spec2); err != meta.ErrRetentionPolicyConflict {
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", spec2.NewRetentionPolicyInfo()); err != meta.ErrRetentionPolicyConflict {
 		t.Fatalf("got %v, but expected %v", err, meta.ErrRetentionPolicyConflict)
 	}
 
 	// If the rp's shard group duration is different, an error should be returned.
 	spec2 = spec
 	spec2.ShardGroupDuration = spec.ShardGroupDuration + time.Minute
-	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &spec2); err != meta.ErrRetentionPolicyConflict {
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", spec2.NewRetentionPolicyInfo()); err != meta.ErrRetentionPolicyConflict {
 		t.Fatalf("got %v, but expected %v", err, meta.ErrRetentionPolicyConflict)
 	}
 }
@@ -184,8 +185,9 @@ func TestMetaClient_CreateDatabaseWithRetentionPolicy_Conflict_Fields(t *testing
 func TestMetaClient_CreateDatabaseWithRetentionPolicy_Conflict_NonDefault(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	duration := 1 * time.Hour
@@ -198,20 +200,20 @@ func TestMetaClient_CreateDatabaseWithRetentionPolicy_Conflict_NonDefault(t *tes
 	}
 
 	// Create a default retention policy.
-	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &spec); err != nil {
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", spec.NewRetentionPolicyInfo()); err != nil {
 		t.Fatal(err)
 	}
 
 	// Let's create a non-default retention policy.
 	spec2 := spec
 	spec2.Name = "rp1"
-	if _, err := c.CreateRetentionPolicy("db0", &spec2); err != nil {
+	if _, err := c.CreateRetentionPolicy("db0", spec2.NewRetentionPolicyInfo()); err != nil {
 		t.Fatal(err)
 	}
 
 	// If we try to create a database with the non-default retention policy then
 	// it's an error.
-	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &spec2); err != meta.ErrRetentionPolicyConflict {
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", spec2.NewRetentionPolicyInfo()); err != meta.ErrRetentionPolicyConflict {
 		t.Fatalf("got %v, but expected %v", err, meta.ErrRetentionPolicyConflict)
 	}
 }
@@ -219,8 +221,9 @@ func TestMetaClient_CreateDatabaseWithRetentionPolicy_Conflict_NonDefault(t *tes
 func TestMetaClient_Databases(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	// Create two databases.
@@ -240,7 +243,7 @@ func TestMetaClient_Databases(t *testing.T) {
 		t.Fatalf("db name wrong: %s", db.Name)
 	}
 
-	dbs := c.Databases()
+	dbs, err := c.Databases()
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -256,8 +259,9 @@ func TestMetaClient_Databases(t *testing.T) {
 func TestMetaClient_DropDatabase(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	if _, err := c.CreateDatabase("db0"); err != nil {
@@ -288,8 +292,9 @@ func TestMetaClient_DropDatabase(t *testing.T) {
 func TestMetaClient_CreateRetentionPolicy(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	if _, err := c.CreateDatabase("db0"); err != nil {
@@ -310,12 +315,12 @@ func TestMetaClient_CreateRetentionPolicy(t *testing.T) {
 		ShardGroupDuration: 2 * time.Hour,
 	}
 
-	if _, err := c.CreateRetentionPolicy("db0", &meta.RetentionPolicySpec{
+	if _, err := c.CreateRetentionPolicy("db0", &meta.RetentionPolicyInfo{
 		Name:               rp0.Name,
-		ReplicaN:           &rp0.ReplicaN,
-		Duration:           &rp0.Duration,
+		ReplicaN:           rp0.ReplicaN,
+		Duration:           rp0.Duration,
 		ShardGroupDuration: rp0.ShardGroupDuration,
-	}, true); err != nil {
+	}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -327,12 +332,12 @@ func TestMetaClient_CreateRetentionPolicy(t *testing.T) {
 	}
 
 	// Create the same policy.  Should not error.
-	if _, err := c.CreateRetentionPolicy("db0", &meta.RetentionPolicySpec{
+	if _, err := c.CreateRetentionPolicy("db0", &meta.RetentionPolicyInfo{
 		Name:               rp0.Name,
-		ReplicaN:           &rp0.ReplicaN,
-		Duration:           &rp0.Duration,
+		ReplicaN:           rp0.ReplicaN,
+		Duration:           rp0.Duration,
 		ShardGroupDuration: rp0.ShardGroupDuration,
-	}, true); err != nil {
+	}); err != nil {
 		t.Fatal(err)
 	} else if actual, err = c.RetentionPolicy("db0", "rp0"); err != nil {
 		t.Fatal(err)
@@ -345,12 +350,12 @@ func TestMetaClient_CreateRetentionPolicy(t *testing.T) {
 	rp1 := rp0
 	rp1.Duration = 2 * rp0.Duration
 
-	_, got := c.CreateRetentionPolicy("db0", &meta.RetentionPolicySpec{
+	_, got := c.CreateRetentionPolicy("db0", &meta.RetentionPolicyInfo{
 		Name:               rp1.Name,
-		ReplicaN:           &rp1.ReplicaN,
-		Duration:           &rp1.Duration,
+		ReplicaN:           rp1.ReplicaN,
+		Duration:           rp1.Duration,
 		ShardGroupDuration: rp1.ShardGroupDuration,
-	}, true)
+	})
 	if exp := meta.ErrRetentionPolicyExists; got != exp {
 		t.Fatalf("got error %v, expected error %v", got, exp)
 	}
@@ -360,12 +365,12 @@ func TestMetaClient_CreateRetentionPolicy(t *testing.T) {
 	rp1 = rp0
 	rp1.ReplicaN = rp0.ReplicaN + 1
 
-	_, got = c.CreateRetentionPolicy("db0", &meta.RetentionPolicySpec{
+	_, got = c.CreateRetentionPolicy("db0", &meta.RetentionPolicyInfo{
 		Name:               rp1.Name,
-		ReplicaN:           &rp1.ReplicaN,
-		Duration:           &rp1.Duration,
+		ReplicaN:           rp1.ReplicaN,
+		Duration:           rp1.Duration,
 		ShardGroupDuration: rp1.ShardGroupDuration,
-	}, true)
+	})
 	if exp := meta.ErrRetentionPolicyExists; got != exp {
 		t.Fatalf("got error %v, expected error %v", got, exp)
 	}
@@ -375,12 +380,12 @@ func TestMetaClient_CreateRetentionPolicy(t *testing.T) {
 	rp1 = rp0
 	rp1.ShardGroupDuration = rp0.ShardGroupDuration / 2
 
-	_, got = c.CreateRetentionPolicy("db0", &meta.RetentionPolicySpec{
+	_, got = c.CreateRetentionPolicy("db0", &meta.RetentionPolicyInfo{
 		Name:               rp1.Name,
-		ReplicaN:           &rp1.ReplicaN,
-		Duration:           &rp1.Duration,
+		ReplicaN:           rp1.ReplicaN,
+		Duration:           rp1.Duration,
 		ShardGroupDuration: rp1.ShardGroupDuration,
-	}, true)
+	})
 	if exp := meta.ErrRetentionPolicyExists; got != exp {
 		t.Fatalf("got error %v, expected error %v", got, exp)
 	}
@@ -391,12 +396,12 @@ func TestMetaClient_CreateRetentionPolicy(t *testing.T) {
 	rp1.Duration = 1 * time.Hour
 	rp1.ShardGroupDuration = 2 * time.Hour
 
-	_, got = c.CreateRetentionPolicy("db0", &meta.RetentionPolicySpec{
+	_, got = c.CreateRetentionPolicy("db0", &meta.RetentionPolicyInfo{
 		Name:               rp1.Name,
-		ReplicaN:           &rp1.ReplicaN,
-		Duration:           &rp1.Duration,
+		ReplicaN:           rp1.ReplicaN,
+		Duration:           rp1.Duration,
 		ShardGroupDuration: rp1.ShardGroupDuration,
-	}, true)
+	})
 	if exp := meta.ErrIncompatibleDurations; got != exp {
 		t.Fatalf("got error %v, expected error %v", got, exp)
 	}
@@ -405,16 +410,17 @@ func TestMetaClient_CreateRetentionPolicy(t *testing.T) {
 func TestMetaClient_DefaultRetentionPolicy(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	duration := 1 * time.Hour
 	replicaN := 1
-	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicySpec{
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicyInfo{
 		Name:     "rp0",
-		Duration: &duration,
-		ReplicaN: &replicaN,
+		Duration: duration,
+		ReplicaN: replicaN,
 	}); err != nil {
 		t.Fatal(err)
 	}
@@ -446,12 +452,14 @@ func TestMetaClient_DefaultRetentionPolicy(t *testing.T) {
 func TestMetaClient_UpdateRetentionPolicy(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
-	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicySpec{
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicyInfo{
 		Name:               "rp0",
+		ReplicaN:           1,
 		ShardGroupDuration: 4 * time.Hour,
 	}); err != nil {
 		t.Fatal(err)
@@ -469,7 +477,7 @@ func TestMetaClient_UpdateRetentionPolicy(t *testing.T) {
 	if err := c.UpdateRetentionPolicy("db0", "rp0", &meta.RetentionPolicyUpdate{
 		Duration: &duration,
 		ReplicaN: &replicaN,
-	}, true); err != nil {
+	}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -485,9 +493,9 @@ func TestMetaClient_UpdateRetentionPolicy(t *testing.T) {
 	duration = rpi.ShardGroupDuration / 2
 	if err := c.UpdateRetentionPolicy("db0", "rp0", &meta.RetentionPolicyUpdate{
 		Duration: &duration,
-	}, true); err == nil {
+	}); err == nil {
 		t.Fatal("expected error")
-	} else if err != meta.ErrIncompatibleDurations {
+	} else if err.Error() != meta.ErrIncompatibleDurations.Error() {
 		t.Fatalf("expected error '%s', got '%s'", meta.ErrIncompatibleDurations, err)
 	}
 
@@ -495,9 +503,9 @@ func TestMetaClient_UpdateRetentionPolicy(t *testing.T) {
 	sgDuration := rpi.Duration * 2
 	if err := c.UpdateRetentionPolicy("db0", "rp0", &meta.RetentionPolicyUpdate{
 		ShardGroupDuration: &sgDuration,
-	}, true); err == nil {
+	}); err == nil {
 		t.Fatal("expected error")
-	} else if err != meta.ErrIncompatibleDurations {
+	} else if err.Error() != meta.ErrIncompatibleDurations.Error() {
 		t.Fatalf("expected error '%s', got '%s'", meta.ErrIncompatibleDurations, err)
 	}
 
@@ -507,9 +515,9 @@ func TestMetaClient_UpdateRetentionPolicy(t *testing.T) {
 	if err := c.UpdateRetentionPolicy("db0", "rp0", &meta.RetentionPolicyUpdate{
 		Duration:           &duration,
 		ShardGroupDuration: &sgDuration,
-	}, true); err == nil {
+	}); err == nil {
 		t.Fatal("expected error")
-	} else if err != meta.ErrIncompatibleDurations {
+	} else if err.Error() != meta.ErrIncompatibleDurations.Error() {
 		t.Fatalf("expected error '%s', got '%s'", meta.ErrIncompatibleDurations, err)
 	}
 
@@ -519,16 +527,53 @@ func TestMetaClient_UpdateRetentionPolicy(t *testing.T) {
 	if err := c.UpdateRetentionPolicy("db0", "rp0", &meta.RetentionPolicyUpdate{
 		Duration:           &duration,
 		ShardGroupDuration: &sgDuration,
-	}, true); err != nil {
+	}); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
 
+// Ensure a shard group duration update reaches the meta store.
+func TestMetaClient_UpdateRetentionPolicy_ShardGroupDuration(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	if _, err := c.CreateDatabaseWithRetentionPolicy("db0", &meta.RetentionPolicyInfo{
+		Name:               "rp0",
+		ReplicaN:           1,
+		Duration:           48 * time.Hour,
+		ShardGroupDuration: 1 * time.Hour,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sgDuration := 2 * time.Hour
+	if err := c.UpdateRetentionPolicy("db0", "rp0", &meta.RetentionPolicyUpdate{
+		ShardGroupDuration: &sgDuration,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rpi, err := c.RetentionPolicy("db0", "rp0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, got := 2*time.Hour, rpi.ShardGroupDuration; exp != got {
+		t.Fatalf("shard group duration wrong: \n\texp: %s\n\tgot: %s", exp, got)
+	} else if exp, got := 48*time.Hour, rpi.Duration; exp != got {
+		t.Fatalf("duration wrong: \n\texp: %s\n\tgot: %s", exp, got)
+	}
+}
+
 func TestMetaClient_DropRetentionPolicy(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	if _, err := c.CreateDatabase("db0"); err != nil {
@@ -544,11 +589,11 @@ func TestMetaClient_DropRetentionPolicy(t *testing.T) {
 
 	duration := 1 * time.Hour
 	replicaN := 1
-	if _, err := c.CreateRetentionPolicy("db0", &meta.RetentionPolicySpec{
+	if _, err := c.CreateRetentionPolicy("db0", &meta.RetentionPolicyInfo{
 		Name:     "rp0",
-		Duration: &duration,
-		ReplicaN: &replicaN,
-	}, true); err != nil {
+		Duration: duration,
+		ReplicaN: replicaN,
+	}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -578,8 +623,9 @@ func TestMetaClient_DropRetentionPolicy(t *testing.T) {
 func TestMetaClient_CreateUser(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	// Create an admin user
@@ -748,8 +794,9 @@ func TestMetaClient_CreateUser(t *testing.T) {
 func TestMetaClient_UpdateUser(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	// UpdateUser that doesn't exist should return an error.
@@ -761,8 +808,9 @@ func TestMetaClient_UpdateUser(t *testing.T) {
 func TestMetaClient_ContinuousQueries(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	// Create a database to use
@@ -817,8 +865,9 @@ func TestMetaClient_ContinuousQueries(t *testing.T) {
 func TestMetaClient_Subscriptions_Create(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	// Create a database to use
@@ -874,8 +923,9 @@ func TestMetaClient_Subscriptions_Create(t *testing.T) {
 func TestMetaClient_Subscriptions_Drop(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
 	// Create a database to use
@@ -919,10 +969,16 @@ func TestMetaClient_Subscriptions_Drop(t *testing.T) {
 func TestMetaClient_Shards(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
+	// Shard groups are only created when there are data nodes to own them.
+	if _, err := c.CreateDataNode("foo:8180", "bar:8281"); err != nil {
+		t.Fatal(err)
+	}
+
 	if _, err := c.CreateDatabase("db0"); err != nil {
 		t.Fatal(err)
 	}
@@ -975,10 +1031,16 @@ func TestMetaClient_Shards(t *testing.T) {
 func TestMetaClient_CreateShardGroupIdempotent(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
+	// Shard groups are only created when there are data nodes to own them.
+	if _, err := c.CreateDataNode("foo:8180", "bar:8281"); err != nil {
+		t.Fatal(err)
+	}
+
 	if _, err := c.CreateDatabase("db0"); err != nil {
 		t.Fatal(err)
 	}
@@ -1029,10 +1091,16 @@ func TestMetaClient_CreateShardGroupIdempotent(t *testing.T) {
 func TestMetaClient_PruneShardGroups(t *testing.T) {
 	t.Parallel()
 
-	d, c := newClient()
+	d, s, c := newServiceAndClient()
 	defer os.RemoveAll(d)
+	defer s.Close()
 	defer c.Close()
 
+	// Shard groups are only created when there are data nodes to own them.
+	if _, err := c.CreateDataNode("foo:8180", "bar:8281"); err != nil {
+		t.Fatal(err)
+	}
+
 	if _, err := c.CreateDatabase("db0"); err != nil {
 		t.Fatal(err)
 	}
@@ -1044,11 +1112,11 @@ func TestMetaClient_PruneShardGroups(t *testing.T) {
 	duration := 1 * time.Hour
 	replicaN := 1
 
-	if _, err := c.CreateRetentionPolicy("db1", &meta.RetentionPolicySpec{
+	if _, err := c.CreateRetentionPolicy("db1", &meta.RetentionPolicyInfo{
 		Name:     "rp0",
-		Duration: &duration,
-		ReplicaN: &replicaN,
-	}, true); err != nil {
+		Duration: duration,
+		ReplicaN: replicaN,
+	}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1108,22 +1176,17 @@ func TestMetaClient_PruneShardGroups(t *testing.T) {
 func TestMetaClient_PersistClusterIDAfterRestart(t *testing.T) {
 	t.Parallel()
 
-	cfg := newConfig()
-	defer os.RemoveAll(cfg.Dir)
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
 
-	c := meta.NewClient(cfg)
-	if err := c.Open(); err != nil {
-		t.Fatal(err)
-	}
 	id := c.ClusterID()
 	if id == 0 {
 		t.Fatal("cluster ID can't be zero")
 	}
+	c.Close()
 
-	c = meta.NewClient(cfg)
-	if err := c.Open(); err != nil {
-		t.Fatal(err)
-	}
+	c = newClient(s)
 	defer c.Close()
 
 	idAfter := c.ClusterID()
@@ -1134,36 +1197,6 @@ func TestMetaClient_PersistClusterIDAfterRestart(t *testing.T) {
 	}
 }
 
-func newClient() (string, *meta.Client) {
-	cfg := newConfig()
-	c := meta.NewClient(cfg)
-	if err := c.Open(); err != nil {
-		panic(err)
-	}
-	return cfg.Dir, c
-}
-
-func newConfig() *meta.Config {
-	cfg := meta.NewConfig()
-	cfg.Dir = testTempDir(2)
-	return cfg
-}
-
-func testTempDir(skip int) string {
-	// Get name of the calling function.
-	pc, _, _, ok := runtime.Caller(skip)
-	if !ok {
-		panic("failed to get name of test function")
-	}
-	_, prefix := path.Split(runtime.FuncForPC(pc).Name())
-	// Make a temp dir prefixed with calling function's name.
-	dir, err := ioutil.TempDir(os.TempDir(), prefix)
-	if err != nil {
-		panic(err)
-	}
-	return dir
-}
-
 func isAdmin(u meta.User) bool {
 	ui := u.(*meta.UserInfo)
 	return ui.Admin
@@ -1,11 +1,13 @@
 package meta
 
 import (
+	"crypto/tls"
 	"errors"
 	"net"
 	"time"
 
 	"github.com/freetsdb/freetsdb/monitor/diagnostics"
+	"github.com/freetsdb/freetsdb/tcp"
 	"github.com/freetsdb/freetsdb/toml"
 )
 
@@ -42,6 +44,10 @@ const (
 
 	// DefaultLoggingEnabled determines if log messages are printed for the meta service
 	DefaultLoggingEnabled = true
+
+	// DefaultDataNodeTimeout is the default time after its last heartbeat
+	// that a data node is marked as down.
+	DefaultDataNodeTimeout = 30 * time.Second
 )
 
 // Config represents the meta configuration.
@@ -71,6 +77,23 @@ type Config struct {
 	PprofEnabled         bool          `toml:"pprof-enabled"`
 
 	LeaseDuration toml.Duration `toml:"lease-duration"`
+
+	// DataNodeTimeout is the time after its last heartbeat that a data node
+	// is marked as down. Zero disables the check.
+	DataNodeTimeout toml.Duration `toml:"data-node-timeout"`
+
+	// TLSEnabled secures the raft TCP port with TLS. If TLSCACertificate is
+	// set, meta nodes must also present a certificate signed by it.
+	TLSEnabled            bool   `toml:"tls-enabled"`
+	TLSCertificate        string `toml:"tls-certificate"`
+	TLSPrivateKey         string `toml:"tls-private-key"`
+	TLSCACertificate      string `toml:"tls-ca-certificate"`
+	TLSInsecureSkipVerify bool   `toml:"tls-insecure-skip-verify"`
+
+	// InternalSharedSecret, if set, must be known by every meta node
+	// connecting to the raft TCP port. It only authenticates the start of
+	// each connection, so it requires TLSEnabled to protect the rest.
+	InternalSharedSecret string `toml:"internal-shared-secret"`
 }
 
 // NewConfig builds a new configuration with default values.
@@ -86,6 +109,7 @@ func NewConfig() *Config {
 		RaftPromotionEnabled: DefaultRaftPromotionEnabled,
 		LeaseDuration:        toml.Duration(DefaultLeaseDuration),
 		LoggingEnabled:       DefaultLoggingEnabled,
+		DataNodeTimeout:      toml.Duration(DefaultDataNodeTimeout),
 	}
 
 }
@@ -95,9 +119,25 @@ func (c *Config) Validate() error {
 	if c.Dir == "" {
 		return errors.New("Meta.Dir must be specified")
 	}
+	if c.TLSEnabled && c.TLSCertificate == "" {
+		return errors.New("Meta.TLSCertificate must be specified when TLS is enabled")
+	}
+	if c.InternalSharedSecret != "" && !c.TLSEnabled {
+		return errors.New("Meta.TLSEnabled must be true when InternalSharedSecret is set")
+	}
 	return nil
 }
 
+// MuxTLSConfigs returns the server and client TLS configurations for the
+// raft TCP port, or nil if TLS is disabled. base supplies the cipher and
+// version settings and may be nil.
+func (c *Config) MuxTLSConfigs(base *tls.Config) (server, client *tls.Config, err error) {
+	if !c.TLSEnabled {
+		return nil, nil, nil
+	}
+	return tcp.TLSConfigs(base, c.TLSCertificate, c.TLSPrivateKey, c.TLSCACertificate, c.TLSInsecureSkipVerify)
+}
+
 // Diagnostics returns a diagnostics representation of a subset of the Config.
 func (c *Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 	return diagnostics.RowFromMap(map[string]interface{}{
@@ -2,6 +2,7 @@ package meta_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/freetsdb/freetsdb/services/meta"
@@ -13,6 +14,10 @@ func TestConfig_Parse(t *testing.T) {
 	if _, err := toml.Decode(`
 dir = "/tmp/foo"
 logging-enabled = false
+data-node-timeout = "10s"
+tls-enabled = true
+tls-certificate = "/etc/ssl/meta.pem"
+internal-shared-secret = "secret"
 `, &c); err != nil {
 		t.Fatal(err)
 	}
@@ -22,5 +27,26 @@ logging-enabled = false
 		t.Fatalf("unexpected dir: %s", c.Dir)
 	} else if c.LoggingEnabled {
 		t.Fatalf("unexpected logging enabled: %v", c.LoggingEnabled)
+	} else if time.Duration(c.DataNodeTimeout) != 10*time.Second {
+		t.Fatalf("unexpected data node timeout: %s", c.DataNodeTimeout)
+	} else if !c.TLSEnabled || c.TLSCertificate != "/etc/ssl/meta.pem" {
+		t.Fatalf("unexpected tls config: %v %s", c.TLSEnabled, c.TLSCertificate)
+	} else if c.InternalSharedSecret != "secret" {
+		t.Fatalf("unexpected internal shared secret: %s", c.InternalSharedSecret)
+	}
+}
+
+func TestConfig_Validate_SharedSecret(t *testing.T) {
+	c := meta.NewConfig()
+	c.Dir = "/tmp/foo"
+	c.InternalSharedSecret = "secret"
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for internal-shared-secret without tls-enabled, got nil")
+	}
+
+	c.TLSEnabled = true
+	c.TLSCertificate = "/etc/ssl/meta.pem"
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation fail: %s", err)
 	}
 }
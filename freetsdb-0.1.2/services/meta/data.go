@@ -45,6 +45,14 @@ type Data struct {
 	Databases []DatabaseInfo
 	Users     []UserInfo
 
+	// Dropped holds the databases, measurements and shards that were moved
+	// to the trash of the data nodes and can still be restored.
+	Dropped []DroppedInfo
+
+	// TrashEnabled is true if the data nodes move dropped databases,
+	// measurements and shards to their trash instead of deleting them.
+	TrashEnabled bool
+
 	// adminUserExists provides a constant time mechanism for determining
 	// if there is at least one admin user.
 	adminUserExists bool
@@ -100,6 +108,20 @@ func (data *Data) CreateDataNode(host, tcpHost string) error {
 	return nil
 }
 
+// SetDataNodeStatus sets the status of a data node and, if lastSeen is not
+// zero, the time of its last heartbeat.
+func (data *Data) SetDataNodeStatus(id uint64, status string, lastSeen time.Time) error {
+	ni := data.DataNode(id)
+	if ni == nil {
+		return ErrNodeNotFound
+	}
+	ni.Status = status
+	if lastSeen.After(ni.LastSeen) {
+		ni.LastSeen = lastSeen.UTC()
+	}
+	return nil
+}
+
 // setDataNode adds a data node with a pre-specified nodeID.
 // this should only be used when the cluster is upgrading from 0.9 to 0.10
 func (data *Data) setDataNode(nodeID uint64, host, tcpHost string) error {
@@ -420,7 +442,7 @@ func (data *Data) CreateRetentionPolicy(database string, rpi *RetentionPolicyInf
 		return freetsdb.ErrDatabaseNotFound(database)
 	} else if rp := di.RetentionPolicy(rpi.Name); rp != nil {
 		// RP with that name already exists. Make sure they're the same.
-		if rp.ReplicaN != rpi.ReplicaN || rp.Duration != rpi.Duration || rp.ShardGroupDuration != rpi.ShardGroupDuration {
+		if rp.ReplicaN != rpi.ReplicaN || rp.Duration != rpi.Duration || rp.ShardGroupDuration != rpi.ShardGroupDuration || rp.Consistency != rpi.Consistency || rp.ColdAfter != rpi.ColdAfter || rp.DuplicatePolicy != rpi.DuplicatePolicy || rp.StringCodec != rpi.StringCodec || rp.FloatCodec != rpi.FloatCodec {
 			return ErrRetentionPolicyExists
 		}
 		// if they want to make it default, and it's not the default, it's not an identical command so it's an error
@@ -458,6 +480,17 @@ func (data *Data) DropRetentionPolicy(database, name string) error {
 		}
 	}
 
+	// Remove the downsample rules writing into the policy.
+	for i := range di.RetentionPolicies {
+		rpi := &di.RetentionPolicies[i]
+		for j := 0; j < len(rpi.Downsamples); j++ {
+			if rpi.Downsamples[j].Target == name {
+				rpi.Downsamples = append(rpi.Downsamples[:j], rpi.Downsamples[j+1:]...)
+				j--
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -467,6 +500,11 @@ type RetentionPolicyUpdate struct {
 	Duration           *time.Duration
 	ReplicaN           *int
 	ShardGroupDuration *time.Duration
+	Consistency        *string
+	ColdAfter          *time.Duration
+	DuplicatePolicy    *string
+	StringCodec        *string
+	FloatCodec         *string
 }
 
 // SetName sets the RetentionPolicyUpdate.Name.
@@ -481,6 +519,21 @@ func (rpu *RetentionPolicyUpdate) SetReplicaN(v int) { rpu.ReplicaN = &v }
 // SetShardGroupDuration sets the RetentionPolicyUpdate.ShardGroupDuration.
 func (rpu *RetentionPolicyUpdate) SetShardGroupDuration(v time.Duration) { rpu.ShardGroupDuration = &v }
 
+// SetConsistency sets the RetentionPolicyUpdate.Consistency.
+func (rpu *RetentionPolicyUpdate) SetConsistency(v string) { rpu.Consistency = &v }
+
+// SetColdAfter sets the RetentionPolicyUpdate.ColdAfter.
+func (rpu *RetentionPolicyUpdate) SetColdAfter(v time.Duration) { rpu.ColdAfter = &v }
+
+// SetDuplicatePolicy sets the RetentionPolicyUpdate.DuplicatePolicy.
+func (rpu *RetentionPolicyUpdate) SetDuplicatePolicy(v string) { rpu.DuplicatePolicy = &v }
+
+// SetStringCodec sets the RetentionPolicyUpdate.StringCodec.
+func (rpu *RetentionPolicyUpdate) SetStringCodec(v string) { rpu.StringCodec = &v }
+
+// SetFloatCodec sets the RetentionPolicyUpdate.FloatCodec.
+func (rpu *RetentionPolicyUpdate) SetFloatCodec(v string) { rpu.FloatCodec = &v }
+
 // UpdateRetentionPolicy updates an existing retention policy.
 func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPolicyUpdate, makeDefault bool) error {
 	// Find database.
@@ -516,6 +569,14 @@ func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPol
 
 	// Update fields.
 	if rpu.Name != nil {
+		// Keep the downsample rules writing into the policy.
+		for i := range di.RetentionPolicies {
+			for j := range di.RetentionPolicies[i].Downsamples {
+				if ds := &di.RetentionPolicies[i].Downsamples[j]; ds.Target == name {
+					ds.Target = *rpu.Name
+				}
+			}
+		}
 		rpi.Name = *rpu.Name
 	}
 	if rpu.Duration != nil {
@@ -527,6 +588,21 @@ func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPol
 	if rpu.ShardGroupDuration != nil {
 		rpi.ShardGroupDuration = normalisedShardDuration(*rpu.ShardGroupDuration, rpi.Duration)
 	}
+	if rpu.Consistency != nil {
+		rpi.Consistency = *rpu.Consistency
+	}
+	if rpu.ColdAfter != nil {
+		rpi.ColdAfter = *rpu.ColdAfter
+	}
+	if rpu.DuplicatePolicy != nil {
+		rpi.DuplicatePolicy = *rpu.DuplicatePolicy
+	}
+	if rpu.StringCodec != nil {
+		rpi.StringCodec = *rpu.StringCodec
+	}
+	if rpu.FloatCodec != nil {
+		rpi.FloatCodec = *rpu.FloatCodec
+	}
 
 	if di.DefaultRetentionPolicy != rpi.Name && makeDefault {
 		di.DefaultRetentionPolicy = rpi.Name
@@ -535,6 +611,17 @@ func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPol
 	return nil
 }
 
+// SetDatabaseConsistency sets the default write consistency level for a
+// database. An empty level makes writes use the configured level.
+func (data *Data) SetDatabaseConsistency(name, level string) error {
+	di := data.Database(name)
+	if di == nil {
+		return freetsdb.ErrDatabaseNotFound(name)
+	}
+	di.Consistency = level
+	return nil
+}
+
 // SetDefaultRetentionPolicy sets the default retention policy for a database.
 func (data *Data) SetDefaultRetentionPolicy(database, name string) error {
 	// Find database and verify policy exists.
@@ -583,6 +670,120 @@ func (data *Data) DropShard(id uint64) {
 	}
 }
 
+// shard returns a pointer to the shard with the given id, or nil if
+// no shard in a non-deleted shard group matches.
+func (data *Data) shard(id uint64) *ShardInfo {
+	for dbidx := range data.Databases {
+		for rpidx := range data.Databases[dbidx].RetentionPolicies {
+			rpi := &data.Databases[dbidx].RetentionPolicies[rpidx]
+			for sgidx := range rpi.ShardGroups {
+				if rpi.ShardGroups[sgidx].Deleted() {
+					continue
+				}
+				for sidx := range rpi.ShardGroups[sgidx].Shards {
+					if rpi.ShardGroups[sgidx].Shards[sidx].ID == id {
+						return &rpi.ShardGroups[sgidx].Shards[sidx]
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// AddShardOwner adds a data node to the owners of a shard. Adding a node
+// that already owns the shard is a no-op.
+func (data *Data) AddShardOwner(id, nodeID uint64) error {
+	if data.DataNode(nodeID) == nil {
+		return ErrNodeNotFound
+	}
+
+	si := data.shard(id)
+	if si == nil {
+		return ErrShardNotFound
+	} else if si.Archived() {
+		return ErrShardArchived
+	} else if si.OwnedBy(nodeID) {
+		return nil
+	}
+
+	si.Owners = append(si.Owners, ShardOwner{NodeID: nodeID})
+	return nil
+}
+
+// RemoveShardOwner removes a data node from the owners of a shard. The
+// last owner of a shard cannot be removed.
+func (data *Data) RemoveShardOwner(id, nodeID uint64) error {
+	si := data.shard(id)
+	if si == nil {
+		return ErrShardNotFound
+	}
+
+	for i, owner := range si.Owners {
+		if owner.NodeID != nodeID {
+			continue
+		}
+
+		if len(si.Owners) == 1 {
+			return ErrShardNotReplicated
+		}
+		si.Owners = append(si.Owners[:i], si.Owners[i+1:]...)
+		return nil
+	}
+	return nil
+}
+
+// ArchiveShard marks a shard as archived at t and removes all its owners, so
+// the shard is no longer written to or queried.
+func (data *Data) ArchiveShard(id uint64, t time.Time) error {
+	si := data.shard(id)
+	if si == nil {
+		return ErrShardNotFound
+	} else if si.Archived() {
+		return ErrShardArchived
+	}
+
+	si.ArchivedAt = t.UTC()
+	si.Owners = nil
+	return nil
+}
+
+// UnarchiveShard makes a data node the owner of an archived shard restored
+// on it.
+func (data *Data) UnarchiveShard(id, nodeID uint64) error {
+	if data.DataNode(nodeID) == nil {
+		return ErrNodeNotFound
+	}
+
+	si := data.shard(id)
+	if si == nil {
+		return ErrShardNotFound
+	} else if !si.Archived() {
+		return ErrShardNotArchived
+	}
+
+	si.ArchivedAt = time.Time{}
+	si.Owners = []ShardOwner{{NodeID: nodeID}}
+	return nil
+}
+
+// SetShardOwnerTier sets the storage tier a data node keeps its copy of a
+// shard on.
+func (data *Data) SetShardOwnerTier(id, nodeID uint64, tier string) error {
+	si := data.shard(id)
+	if si == nil {
+		return ErrShardNotFound
+	}
+
+	for i := range si.Owners {
+		if si.Owners[i].NodeID == nodeID {
+			si.Owners[i].Tier = tier
+			return nil
+		}
+	}
+	return ErrShardNotOwned
+}
+
 // ShardGroups returns a list of all shard groups on a database and retention policy.
 func (data *Data) ShardGroups(database, policy string) ([]ShardGroupInfo, error) {
 	// Find retention policy.
@@ -724,6 +925,256 @@ func (data *Data) DeleteShardGroup(database, policy string, id uint64) error {
 	return ErrShardGroupNotFound
 }
 
+// ExpireShardGroup deletes a shard group like DeleteShardGroup, but keeps it
+// pending deletion until purgeAt. Data nodes keep the shards of the group
+// until then, so UndropShardGroup can still recover it.
+func (data *Data) ExpireShardGroup(database, policy string, id uint64, purgeAt time.Time) error {
+	if err := data.DeleteShardGroup(database, policy, id); err != nil {
+		return err
+	}
+
+	rpi, _ := data.RetentionPolicy(database, policy)
+	for i := range rpi.ShardGroups {
+		if rpi.ShardGroups[i].ID == id {
+			rpi.ShardGroups[i].PurgeAt = purgeAt.UTC()
+		}
+	}
+	return nil
+}
+
+// UndropShardGroup recovers a shard group that is pending deletion at t.
+// The group must be within the duration of its retention policy again and
+// must not overlap a shard group created since it was deleted.
+func (data *Data) UndropShardGroup(id uint64, t time.Time) error {
+	for i := range data.Databases {
+		for j := range data.Databases[i].RetentionPolicies {
+			rpi := &data.Databases[i].RetentionPolicies[j]
+			for k := range rpi.ShardGroups {
+				sgi := &rpi.ShardGroups[k]
+				if sgi.ID != id {
+					continue
+				}
+
+				if !sgi.PendingDeletion(t) {
+					return ErrShardGroupNotPending
+				} else if rpi.Duration != 0 && sgi.EndTime.Add(rpi.Duration).Before(t) {
+					return ErrShardGroupExpired
+				}
+
+				for _, other := range rpi.ShardGroups {
+					if other.ID != id && !other.Deleted() && other.Overlaps(sgi.StartTime, sgi.EndTime) {
+						return ErrShardGroupExists
+					}
+				}
+
+				sgi.DeletedAt = time.Time{}
+				sgi.PurgeAt = time.Time{}
+				return nil
+			}
+		}
+	}
+	return ErrShardGroupNotFound
+}
+
+// TrashDatabase removes a database like DropDatabase, but keeps its meta
+// data and the privileges of users on it so it can be restored with
+// UndropDatabase.
+func (data *Data) TrashDatabase(name string, t time.Time) error {
+	di := data.Database(name)
+	if di == nil {
+		return nil
+	}
+
+	dropped := DroppedInfo{Type: DroppedDatabase, Database: name, DroppedAt: t}
+	other := di.clone()
+	dropped.DatabaseInfo = &other
+	for _, ui := range data.Users {
+		if p, ok := ui.Privileges[name]; ok {
+			if dropped.Privileges == nil {
+				dropped.Privileges = make(map[string]influxql.Privilege)
+			}
+			dropped.Privileges[ui.Name] = p
+		}
+	}
+
+	if err := data.DropDatabase(name); err != nil {
+		return err
+	}
+	data.Dropped = append(data.Dropped, dropped)
+	return nil
+}
+
+// TrashMeasurement records a measurement dropped from a database so it can
+// be restored with UndropMeasurement. A measurement that is already recorded
+// only has its drop time updated, so re-running a DROP MEASUREMENT that
+// failed on some nodes does not record it twice.
+func (data *Data) TrashMeasurement(database, name string, t time.Time) error {
+	if data.Database(database) == nil {
+		return freetsdb.ErrDatabaseNotFound(database)
+	}
+
+	if i := data.dropped(func(di *DroppedInfo) bool {
+		return di.Type == DroppedMeasurement && di.Database == database && di.Name == name
+	}); i != -1 {
+		data.Dropped[i].DroppedAt = t
+		return nil
+	}
+
+	data.Dropped = append(data.Dropped, DroppedInfo{
+		Type:      DroppedMeasurement,
+		Database:  database,
+		Name:      name,
+		DroppedAt: t,
+	})
+	return nil
+}
+
+// TrashShard removes a shard like DropShard, but keeps its shard group so it
+// can be restored with UndropShard.
+func (data *Data) TrashShard(id uint64, t time.Time) error {
+	for _, dbi := range data.Databases {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				for _, si := range sgi.Shards {
+					if si.ID != id {
+						continue
+					}
+
+					other := sgi.clone()
+					other.Shards = []ShardInfo{si.clone()}
+					dropped := DroppedInfo{
+						Type:            DroppedShard,
+						Database:        dbi.Name,
+						RetentionPolicy: rpi.Name,
+						ShardID:         id,
+						ShardGroup:      &other,
+						DroppedAt:       t,
+					}
+
+					data.DropShard(id)
+					data.Dropped = append(data.Dropped, dropped)
+					return nil
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// dropped returns the index of the most recently dropped object matching fn.
+func (data *Data) dropped(fn func(di *DroppedInfo) bool) int {
+	for i := len(data.Dropped) - 1; i >= 0; i-- {
+		if fn(&data.Dropped[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeDropped removes the dropped object at index i.
+func (data *Data) removeDropped(i int) {
+	data.Dropped = append(data.Dropped[:i], data.Dropped[i+1:]...)
+}
+
+// UndropDatabase restores the most recently dropped database name along with
+// the privileges of users that still exist.
+func (data *Data) UndropDatabase(name string) error {
+	i := data.dropped(func(di *DroppedInfo) bool {
+		return di.Type == DroppedDatabase && di.Database == name
+	})
+	if i == -1 {
+		return ErrDroppedNotFound
+	} else if data.Database(name) != nil {
+		return ErrDatabaseExists
+	}
+
+	dropped := data.Dropped[i]
+	data.Databases = append(data.Databases, dropped.DatabaseInfo.clone())
+	for j := range data.Users {
+		if p, ok := dropped.Privileges[data.Users[j].Name]; ok {
+			if data.Users[j].Privileges == nil {
+				data.Users[j].Privileges = make(map[string]influxql.Privilege)
+			}
+			data.Users[j].Privileges[name] = p
+		}
+	}
+
+	data.removeDropped(i)
+	return nil
+}
+
+// UndropMeasurement removes the record of the most recently dropped
+// measurement name of a database.
+func (data *Data) UndropMeasurement(database, name string) error {
+	if data.Database(database) == nil {
+		return freetsdb.ErrDatabaseNotFound(database)
+	}
+
+	i := data.dropped(func(di *DroppedInfo) bool {
+		return di.Type == DroppedMeasurement && di.Database == database && di.Name == name
+	})
+	if i == -1 {
+		return ErrDroppedNotFound
+	}
+
+	data.removeDropped(i)
+	return nil
+}
+
+// UndropShard restores a dropped shard to its shard group. The shard group
+// is recreated if it has been removed since.
+func (data *Data) UndropShard(id uint64) error {
+	i := data.dropped(func(di *DroppedInfo) bool {
+		return di.Type == DroppedShard && di.ShardID == id
+	})
+	if i == -1 {
+		return ErrDroppedNotFound
+	}
+
+	dropped := data.Dropped[i]
+	rpi, err := data.RetentionPolicy(dropped.Database, dropped.RetentionPolicy)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return freetsdb.ErrRetentionPolicyNotFound(dropped.RetentionPolicy)
+	}
+
+	var found bool
+	for j := range rpi.ShardGroups {
+		sgi := &rpi.ShardGroups[j]
+		if sgi.ID != dropped.ShardGroup.ID {
+			continue
+		}
+
+		sgi.DeletedAt = time.Time{}
+		sgi.PurgeAt = time.Time{}
+		sgi.Shards = append(sgi.Shards, dropped.ShardGroup.Shards[0].clone())
+		found = true
+		break
+	}
+	if !found {
+		sgi := dropped.ShardGroup.clone()
+		sgi.DeletedAt = time.Time{}
+		sgi.PurgeAt = time.Time{}
+		rpi.ShardGroups = append(rpi.ShardGroups, sgi)
+		sort.Sort(ShardGroupInfos(rpi.ShardGroups))
+	}
+
+	data.removeDropped(i)
+	return nil
+}
+
+// PruneDropped removes the records of objects dropped before t.
+func (data *Data) PruneDropped(t time.Time) {
+	var dropped []DroppedInfo
+	for _, di := range data.Dropped {
+		if !di.DroppedAt.Before(t) {
+			dropped = append(dropped, di)
+		}
+	}
+	data.Dropped = dropped
+}
+
 // CreateContinuousQuery adds a named continuous query to a database.
 func (data *Data) CreateContinuousQuery(database, name, query string) error {
 	di := data.Database(database)
@@ -769,6 +1220,61 @@ func (data *Data) DropContinuousQuery(database, name string) error {
 	return nil
 }
 
+// SetContinuousQueryLastRun records the time a continuous query last ran.
+// Times before the recorded last run are ignored.
+func (data *Data) SetContinuousQueryLastRun(database, name string, t time.Time) error {
+	di := data.Database(database)
+	if di == nil {
+		return freetsdb.ErrDatabaseNotFound(database)
+	}
+
+	for i := range di.ContinuousQueries {
+		cqi := &di.ContinuousQueries[i]
+		if cqi.Name == name {
+			if t.After(cqi.LastRun) {
+				cqi.LastRun = t.UTC()
+			}
+			return nil
+		}
+	}
+	return ErrContinuousQueryNotFound
+}
+
+// SetContinuousQueryStatus records the outcome of an execution of a continuous
+// query and its last run, as SetContinuousQueryLastRun does. A failed
+// execution keeps the last run, so its intervals are computed again, and a
+// successful execution clears the error of an earlier one.
+func (data *Data) SetContinuousQueryStatus(database, name string, status ContinuousQueryStatus) error {
+	di := data.Database(database)
+	if di == nil {
+		return freetsdb.ErrDatabaseNotFound(database)
+	}
+
+	for i := range di.ContinuousQueries {
+		cqi := &di.ContinuousQueries[i]
+		if cqi.Name != name {
+			continue
+		}
+
+		cqi.LastNodeID = status.NodeID
+		cqi.LastDuration = status.Duration
+		if status.Err != "" {
+			cqi.LastError = status.Err
+			cqi.LastErrorTime = status.Time.UTC()
+			return nil
+		}
+		if status.LastRun.After(cqi.LastRun) {
+			cqi.LastRun = status.LastRun.UTC()
+		}
+		cqi.LastPointsWritten = status.PointsWritten
+		cqi.LastSuccessStart = status.StartTime.UTC()
+		cqi.LastSuccessEnd = status.EndTime.UTC()
+		cqi.LastError, cqi.LastErrorTime = "", time.Time{}
+		return nil
+	}
+	return ErrContinuousQueryNotFound
+}
+
 // validateURL returns an error if the URL does not have a port or uses a scheme other than UDP or HTTP.
 func validateURL(input string) error {
 	u, err := url.Parse(input)
@@ -820,6 +1326,83 @@ func (data *Data) CreateSubscription(database, rp, name, mode string, destinatio
 	return nil
 }
 
+// CreateDownsample adds a rule that downsamples the data of a retention policy
+// into another retention policy of the same database.
+func (data *Data) CreateDownsample(database, rp, target string, interval time.Duration, aggregates []string) error {
+	rpi, err := data.RetentionPolicy(database, rp)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return freetsdb.ErrRetentionPolicyNotFound(rp)
+	}
+
+	if target == rp {
+		return ErrDownsampleSameTarget
+	} else if other, err := data.RetentionPolicy(database, target); err != nil {
+		return err
+	} else if other == nil {
+		return freetsdb.ErrRetentionPolicyNotFound(target)
+	}
+
+	// Ensure the rule doesn't already exist. Creating the same rule again is
+	// not an error.
+	for i := range rpi.Downsamples {
+		if ds := rpi.Downsamples[i]; ds.Target == target {
+			if ds.Interval == interval && strings.Join(ds.Aggregates, ",") == strings.Join(aggregates, ",") {
+				return nil
+			}
+			return ErrDownsampleExists
+		}
+	}
+
+	rpi.Downsamples = append(rpi.Downsamples, DownsampleInfo{
+		Target:     target,
+		Interval:   interval,
+		Aggregates: aggregates,
+	})
+
+	return nil
+}
+
+// DropDownsample removes a downsample rule.
+func (data *Data) DropDownsample(database, rp, target string) error {
+	rpi, err := data.RetentionPolicy(database, rp)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return freetsdb.ErrRetentionPolicyNotFound(rp)
+	}
+
+	for i := range rpi.Downsamples {
+		if rpi.Downsamples[i].Target == target {
+			rpi.Downsamples = append(rpi.Downsamples[:i], rpi.Downsamples[i+1:]...)
+			return nil
+		}
+	}
+	return ErrDownsampleNotFound
+}
+
+// SetDownsampleLastRun records the end of the last interval a downsample rule
+// computed. Times before the recorded last run are ignored.
+func (data *Data) SetDownsampleLastRun(database, rp, target string, t time.Time) error {
+	rpi, err := data.RetentionPolicy(database, rp)
+	if err != nil {
+		return err
+	} else if rpi == nil {
+		return freetsdb.ErrRetentionPolicyNotFound(rp)
+	}
+
+	for i := range rpi.Downsamples {
+		if ds := &rpi.Downsamples[i]; ds.Target == target {
+			if t.After(ds.LastRun) {
+				ds.LastRun = t.UTC()
+			}
+			return nil
+		}
+	}
+	return ErrDownsampleNotFound
+}
+
 // DropSubscription removes a subscription.
 func (data *Data) DropSubscription(database, rp, name string) error {
 	rpi, err := data.RetentionPolicy(database, rp)
@@ -995,6 +1578,13 @@ func (data *Data) Clone() *Data {
 	other.Databases = data.CloneDatabases()
 	other.Users = data.CloneUsers()
 
+	if data.Dropped != nil {
+		other.Dropped = make([]DroppedInfo, len(data.Dropped))
+		for i := range data.Dropped {
+			other.Dropped[i] = data.Dropped[i].clone()
+		}
+	}
+
 	return &other
 }
 
@@ -1030,6 +1620,12 @@ func (data *Data) marshal() *internal.Data {
 		pb.Users[i] = data.Users[i].marshal()
 	}
 
+	pb.Dropped = make([]*internal.DroppedInfo, len(data.Dropped))
+	for i := range data.Dropped {
+		pb.Dropped[i] = data.Dropped[i].marshal()
+	}
+	pb.TrashEnabled = proto.Bool(data.TrashEnabled)
+
 	return pb
 }
 
@@ -1070,6 +1666,15 @@ func (data *Data) unmarshal(pb *internal.Data) {
 	for i, x := range pb.GetUsers() {
 		data.Users[i].unmarshal(x)
 	}
+
+	data.Dropped = nil
+	if len(pb.GetDropped()) > 0 {
+		data.Dropped = make([]DroppedInfo, len(pb.GetDropped()))
+		for i, x := range pb.GetDropped() {
+			data.Dropped[i].unmarshal(x)
+		}
+	}
+	data.TrashEnabled = pb.GetTrashEnabled()
 }
 
 // MarshalBinary encodes the metadata to a binary format.
@@ -1227,8 +1832,24 @@ type NodeInfo struct {
 	ID      uint64
 	Host    string
 	TCPHost string
+
+	// Status and LastSeen are only maintained for data nodes. A node that
+	// has never sent a heartbeat has an empty status. LastSeen is the last
+	// heartbeat before the status last changed, as heartbeats themselves
+	// are not stored.
+	Status   string
+	LastSeen time.Time
 }
 
+// Node statuses.
+const (
+	NodeStatusUp   = "up"
+	NodeStatusDown = "down"
+)
+
+// Down returns true if the node has been marked as down.
+func (ni NodeInfo) Down() bool { return ni.Status == NodeStatusDown }
+
 // clone returns a deep copy of ni.
 func (ni NodeInfo) clone() NodeInfo { return ni }
 
@@ -1238,6 +1859,12 @@ func (ni NodeInfo) marshal() *internal.NodeInfo {
 	pb.ID = proto.Uint64(ni.ID)
 	pb.Host = proto.String(ni.Host)
 	pb.TCPHost = proto.String(ni.TCPHost)
+	if ni.Status != "" {
+		pb.Status = proto.String(ni.Status)
+	}
+	if !ni.LastSeen.IsZero() {
+		pb.LastSeen = proto.Int64(ni.LastSeen.UnixNano())
+	}
 	return pb
 }
 
@@ -1246,6 +1873,10 @@ func (ni *NodeInfo) unmarshal(pb *internal.NodeInfo) {
 	ni.ID = pb.GetID()
 	ni.Host = pb.GetHost()
 	ni.TCPHost = pb.GetTCPHost()
+	ni.Status = pb.GetStatus()
+	if pb.LastSeen != nil {
+		ni.LastSeen = time.Unix(0, pb.GetLastSeen()).UTC()
+	}
 }
 
 // NodeInfos is a slice of NodeInfo used for sorting
@@ -1266,6 +1897,10 @@ type DatabaseInfo struct {
 	DefaultRetentionPolicy string
 	RetentionPolicies      []RetentionPolicyInfo
 	ContinuousQueries      []ContinuousQueryInfo
+
+	// Consistency is the default write consistency level of the database.
+	// It is used by retention policies that do not set their own.
+	Consistency string
 }
 
 // RetentionPolicy returns a retention policy by name.
@@ -1337,6 +1972,9 @@ func (di DatabaseInfo) marshal() *internal.DatabaseInfo {
 	pb := &internal.DatabaseInfo{}
 	pb.Name = proto.String(di.Name)
 	pb.DefaultRetentionPolicy = proto.String(di.DefaultRetentionPolicy)
+	if di.Consistency != "" {
+		pb.Consistency = proto.String(di.Consistency)
+	}
 
 	pb.RetentionPolicies = make([]*internal.RetentionPolicyInfo, len(di.RetentionPolicies))
 	for i := range di.RetentionPolicies {
@@ -1354,6 +1992,7 @@ func (di DatabaseInfo) marshal() *internal.DatabaseInfo {
 func (di *DatabaseInfo) unmarshal(pb *internal.DatabaseInfo) {
 	di.Name = pb.GetName()
 	di.DefaultRetentionPolicy = pb.GetDefaultRetentionPolicy()
+	di.Consistency = pb.GetConsistency()
 
 	if len(pb.GetRetentionPolicies()) > 0 {
 		di.RetentionPolicies = make([]RetentionPolicyInfo, len(pb.GetRetentionPolicies()))
@@ -1462,6 +2101,30 @@ type RetentionPolicyInfo struct {
 	ShardGroupDuration time.Duration
 	ShardGroups        []ShardGroupInfo
 	Subscriptions      []SubscriptionInfo
+
+	// Consistency is the default write consistency level of the policy.
+	// An empty level means the database's level is used.
+	Consistency string
+
+	// Downsamples are the rules aggregating the data of the policy into
+	// other policies of the database.
+	Downsamples []DownsampleInfo
+
+	// ColdAfter is how long after its end time a shard group is moved to
+	// the cold tier. Zero keeps shard groups in the data directory.
+	ColdAfter time.Duration
+
+	// DuplicatePolicy is how points written with the same series and
+	// timestamp are merged: last, first, max or keep. An empty policy
+	// means the last point written wins.
+	DuplicatePolicy string
+
+	// StringCodec and FloatCodec compress the values of the string and
+	// float blocks of the policy's shards when they are compacted: snappy
+	// or zstd for strings and gorilla or zstd for floats. An empty codec
+	// means the default one, snappy or gorilla.
+	StringCodec string
+	FloatCodec  string
 }
 
 // NewRetentionPolicyInfo returns a new instance of RetentionPolicyInfo
@@ -1487,6 +2150,11 @@ func (rpi *RetentionPolicyInfo) Apply(spec *RetentionPolicySpec) *RetentionPolic
 		ReplicaN:           rpi.ReplicaN,
 		Duration:           rpi.Duration,
 		ShardGroupDuration: rpi.ShardGroupDuration,
+		Consistency:        rpi.Consistency,
+		ColdAfter:          rpi.ColdAfter,
+		DuplicatePolicy:    rpi.DuplicatePolicy,
+		StringCodec:        rpi.StringCodec,
+		FloatCodec:         rpi.FloatCodec,
 	}
 	if spec.Name != "" {
 		rp.Name = spec.Name
@@ -1501,6 +2169,20 @@ func (rpi *RetentionPolicyInfo) Apply(spec *RetentionPolicySpec) *RetentionPolic
 	return rp
 }
 
+// matches returns true if rpi would create the existing retention policy other.
+// The shard group duration of rpi is normalised before comparing.
+func (rpi *RetentionPolicyInfo) matches(other *RetentionPolicyInfo) bool {
+	return rpi.Name == other.Name &&
+		rpi.ReplicaN == other.ReplicaN &&
+		rpi.Duration == other.Duration &&
+		normalisedShardDuration(rpi.ShardGroupDuration, rpi.Duration) == other.ShardGroupDuration &&
+		rpi.Consistency == other.Consistency &&
+		rpi.ColdAfter == other.ColdAfter &&
+		rpi.DuplicatePolicy == other.DuplicatePolicy &&
+		rpi.StringCodec == other.StringCodec &&
+		rpi.FloatCodec == other.FloatCodec
+}
+
 // ShardGroupByTimestamp returns the shard group in the policy that contains the timestamp,
 // or nil if no shard group matches.
 func (rpi *RetentionPolicyInfo) ShardGroupByTimestamp(timestamp time.Time) *ShardGroupInfo {
@@ -1528,6 +2210,24 @@ func (rpi *RetentionPolicyInfo) ExpiredShardGroups(t time.Time) []*ShardGroupInf
 	return groups
 }
 
+// ColdShardGroups returns the Shard Groups which are due to move to the cold
+// tier, for the given time.
+func (rpi *RetentionPolicyInfo) ColdShardGroups(t time.Time) []*ShardGroupInfo {
+	var groups = make([]*ShardGroupInfo, 0)
+	if rpi.ColdAfter == 0 {
+		return groups
+	}
+	for i := range rpi.ShardGroups {
+		if rpi.ShardGroups[i].Deleted() {
+			continue
+		}
+		if rpi.ShardGroups[i].EndTime.Add(rpi.ColdAfter).Before(t) {
+			groups = append(groups, &rpi.ShardGroups[i])
+		}
+	}
+	return groups
+}
+
 // DeletedShardGroups returns the Shard Groups which are marked as deleted.
 func (rpi *RetentionPolicyInfo) DeletedShardGroups() []*ShardGroupInfo {
 	var groups = make([]*ShardGroupInfo, 0)
@@ -1547,6 +2247,21 @@ func (rpi *RetentionPolicyInfo) marshal() *internal.RetentionPolicyInfo {
 		Duration:           proto.Int64(int64(rpi.Duration)),
 		ShardGroupDuration: proto.Int64(int64(rpi.ShardGroupDuration)),
 	}
+	if rpi.Consistency != "" {
+		pb.Consistency = proto.String(rpi.Consistency)
+	}
+	if rpi.ColdAfter != 0 {
+		pb.ColdAfter = proto.Int64(int64(rpi.ColdAfter))
+	}
+	if rpi.DuplicatePolicy != "" {
+		pb.DuplicatePolicy = proto.String(rpi.DuplicatePolicy)
+	}
+	if rpi.StringCodec != "" {
+		pb.StringCodec = proto.String(rpi.StringCodec)
+	}
+	if rpi.FloatCodec != "" {
+		pb.FloatCodec = proto.String(rpi.FloatCodec)
+	}
 
 	pb.ShardGroups = make([]*internal.ShardGroupInfo, len(rpi.ShardGroups))
 	for i, sgi := range rpi.ShardGroups {
@@ -1558,6 +2273,11 @@ func (rpi *RetentionPolicyInfo) marshal() *internal.RetentionPolicyInfo {
 		pb.Subscriptions[i] = sub.marshal()
 	}
 
+	pb.Downsamples = make([]*internal.DownsampleInfo, len(rpi.Downsamples))
+	for i, ds := range rpi.Downsamples {
+		pb.Downsamples[i] = ds.marshal()
+	}
+
 	return pb
 }
 
@@ -1567,6 +2287,11 @@ func (rpi *RetentionPolicyInfo) unmarshal(pb *internal.RetentionPolicyInfo) {
 	rpi.ReplicaN = int(pb.GetReplicaN())
 	rpi.Duration = time.Duration(pb.GetDuration())
 	rpi.ShardGroupDuration = time.Duration(pb.GetShardGroupDuration())
+	rpi.Consistency = pb.GetConsistency()
+	rpi.ColdAfter = time.Duration(pb.GetColdAfter())
+	rpi.DuplicatePolicy = pb.GetDuplicatePolicy()
+	rpi.StringCodec = pb.GetStringCodec()
+	rpi.FloatCodec = pb.GetFloatCodec()
 
 	if len(pb.GetShardGroups()) > 0 {
 		rpi.ShardGroups = make([]ShardGroupInfo, len(pb.GetShardGroups()))
@@ -1580,6 +2305,12 @@ func (rpi *RetentionPolicyInfo) unmarshal(pb *internal.RetentionPolicyInfo) {
 			rpi.Subscriptions[i].unmarshal(x)
 		}
 	}
+	if len(pb.GetDownsamples()) > 0 {
+		rpi.Downsamples = make([]DownsampleInfo, len(pb.GetDownsamples()))
+		for i, x := range pb.GetDownsamples() {
+			rpi.Downsamples[i].unmarshal(x)
+		}
+	}
 }
 
 // clone returns a deep copy of rpi.
@@ -1593,6 +2324,13 @@ func (rpi RetentionPolicyInfo) clone() RetentionPolicyInfo {
 		}
 	}
 
+	if rpi.Downsamples != nil {
+		other.Downsamples = make([]DownsampleInfo, len(rpi.Downsamples))
+		for i := range rpi.Downsamples {
+			other.Downsamples[i] = rpi.Downsamples[i].clone()
+		}
+	}
+
 	return other
 }
 
@@ -1646,6 +2384,10 @@ type ShardGroupInfo struct {
 	DeletedAt   time.Time
 	Shards      []ShardInfo
 	TruncatedAt time.Time
+
+	// PurgeAt is the time until which a deleted shard group is pending
+	// deletion. Data nodes keep its shards until then.
+	PurgeAt time.Time
 }
 
 // ShardGroupInfos implements sort.Interface on []ShardGroupInfo, based
@@ -1692,6 +2434,12 @@ func (sgi *ShardGroupInfo) Deleted() bool {
 	return !sgi.DeletedAt.IsZero()
 }
 
+// PendingDeletion returns true if this ShardGroup has been deleted but its
+// shards are kept at t.
+func (sgi *ShardGroupInfo) PendingDeletion(t time.Time) bool {
+	return sgi.Deleted() && sgi.PurgeAt.After(t)
+}
+
 // Truncated returns true if this ShardGroup has been truncated (no new writes).
 func (sgi *ShardGroupInfo) Truncated() bool {
 	return !sgi.TruncatedAt.IsZero()
@@ -1729,6 +2477,10 @@ func (sgi *ShardGroupInfo) marshal() *internal.ShardGroupInfo {
 		pb.TruncatedAt = proto.Int64(MarshalTime(sgi.TruncatedAt))
 	}
 
+	if !sgi.PurgeAt.IsZero() {
+		pb.PurgeAt = proto.Int64(MarshalTime(sgi.PurgeAt))
+	}
+
 	pb.Shards = make([]*internal.ShardInfo, len(sgi.Shards))
 	for i := range sgi.Shards {
 		pb.Shards[i] = sgi.Shards[i].marshal()
@@ -1756,6 +2508,10 @@ func (sgi *ShardGroupInfo) unmarshal(pb *internal.ShardGroupInfo) {
 		sgi.TruncatedAt = UnmarshalTime(pb.GetTruncatedAt())
 	}
 
+	if pb != nil && pb.PurgeAt != nil {
+		sgi.PurgeAt = UnmarshalTime(pb.GetPurgeAt())
+	}
+
 	if len(pb.GetShards()) > 0 {
 		sgi.Shards = make([]ShardInfo, len(pb.GetShards()))
 		for i, x := range pb.GetShards() {
@@ -1768,6 +2524,15 @@ func (sgi *ShardGroupInfo) unmarshal(pb *internal.ShardGroupInfo) {
 type ShardInfo struct {
 	ID     uint64
 	Owners []ShardOwner
+
+	// ArchivedAt is the time the shard was moved to the archive, or zero
+	// if the shard is not archived.
+	ArchivedAt time.Time
+}
+
+// Archived returns true if the shard was moved to the archive.
+func (si ShardInfo) Archived() bool {
+	return !si.ArchivedAt.IsZero()
 }
 
 // OwnedBy determines whether the shard's owner IDs includes nodeID.
@@ -1805,6 +2570,10 @@ func (si ShardInfo) marshal() *internal.ShardInfo {
 		pb.Owners[i] = si.Owners[i].marshal()
 	}
 
+	if si.Archived() {
+		pb.ArchivedAt = proto.Int64(MarshalTime(si.ArchivedAt))
+	}
+
 	return pb
 }
 
@@ -1836,6 +2605,10 @@ func (si *ShardInfo) unmarshal(pb *internal.ShardInfo) {
 			si.Owners[i].unmarshal(x)
 		}
 	}
+
+	if pb.ArchivedAt != nil {
+		si.ArchivedAt = UnmarshalTime(pb.GetArchivedAt())
+	}
 }
 
 // SubscriptionInfo holds the subscription information.
@@ -1870,9 +2643,66 @@ func (si *SubscriptionInfo) unmarshal(pb *internal.SubscriptionInfo) {
 	}
 }
 
+// DownsampleInfo represents a rule aggregating the data of a retention policy
+// into another retention policy of the same database.
+type DownsampleInfo struct {
+	// Target is the retention policy the aggregates are written to.
+	Target string
+
+	// Interval is the GROUP BY time interval of the aggregates.
+	Interval time.Duration
+
+	// Aggregates are the functions applied to each field.
+	Aggregates []string
+
+	// LastRun is the end of the last interval computed, or zero if the rule
+	// never ran.
+	LastRun time.Time
+}
+
+// clone returns a deep copy of ds.
+func (ds DownsampleInfo) clone() DownsampleInfo {
+	other := ds
+	if ds.Aggregates != nil {
+		other.Aggregates = make([]string, len(ds.Aggregates))
+		copy(other.Aggregates, ds.Aggregates)
+	}
+	return other
+}
+
+// marshal serializes to a protobuf representation.
+func (ds DownsampleInfo) marshal() *internal.DownsampleInfo {
+	pb := &internal.DownsampleInfo{
+		Target:     proto.String(ds.Target),
+		Interval:   proto.Int64(int64(ds.Interval)),
+		Aggregates: ds.Aggregates,
+	}
+	if !ds.LastRun.IsZero() {
+		pb.LastRun = proto.Int64(ds.LastRun.UnixNano())
+	}
+	return pb
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (ds *DownsampleInfo) unmarshal(pb *internal.DownsampleInfo) {
+	ds.Target = pb.GetTarget()
+	ds.Interval = time.Duration(pb.GetInterval())
+	if len(pb.GetAggregates()) > 0 {
+		ds.Aggregates = make([]string, len(pb.GetAggregates()))
+		copy(ds.Aggregates, pb.GetAggregates())
+	}
+	if pb.LastRun != nil {
+		ds.LastRun = time.Unix(0, pb.GetLastRun()).UTC()
+	}
+}
+
 // ShardOwner represents a node that owns a shard.
 type ShardOwner struct {
 	NodeID uint64
+
+	// Tier is the storage tier the node keeps the shard on. An empty tier
+	// means the shard is in the node's data directory.
+	Tier string
 }
 
 // clone returns a deep copy of so.
@@ -1882,20 +2712,59 @@ func (so ShardOwner) clone() ShardOwner {
 
 // marshal serializes to a protobuf representation.
 func (so ShardOwner) marshal() *internal.ShardOwner {
-	return &internal.ShardOwner{
+	pb := &internal.ShardOwner{
 		NodeID: proto.Uint64(so.NodeID),
 	}
+	if so.Tier != "" {
+		pb.Tier = proto.String(so.Tier)
+	}
+	return pb
 }
 
 // unmarshal deserializes from a protobuf representation.
 func (so *ShardOwner) unmarshal(pb *internal.ShardOwner) {
 	so.NodeID = pb.GetNodeID()
+	so.Tier = pb.GetTier()
 }
 
 // ContinuousQueryInfo represents metadata about a continuous query.
 type ContinuousQueryInfo struct {
 	Name  string
 	Query string
+
+	// LastRun is the time the query last ran, or zero if it never ran.
+	LastRun time.Time
+
+	// Outcome of the last executions of the query.
+	LastNodeID        uint64
+	LastDuration      time.Duration
+	LastPointsWritten int64
+	LastSuccessStart  time.Time
+	LastSuccessEnd    time.Time
+	LastError         string
+	LastErrorTime     time.Time
+}
+
+// ContinuousQueryStatus is the outcome of an execution of a continuous query.
+type ContinuousQueryStatus struct {
+	// NodeID is the node that executed the query.
+	NodeID uint64
+
+	// Time is when the query was executed.
+	Time     time.Time
+	Duration time.Duration
+
+	// Interval computed and points written by the query.
+	StartTime     time.Time
+	EndTime       time.Time
+	PointsWritten int64
+
+	// Err is the error of a failed execution.
+	Err string
+
+	// LastRun is the time the query is recorded to have last run. A zero
+	// time leaves the last run unchanged.
+	LastRun time.Time
 }
 
 // clone returns a deep copy of cqi.
@@ -1903,16 +2772,149 @@ func (cqi ContinuousQueryInfo) clone() ContinuousQueryInfo { return cqi }
 
 // marshal serializes to a protobuf representation.
 func (cqi ContinuousQueryInfo) marshal() *internal.ContinuousQueryInfo {
-	return &internal.ContinuousQueryInfo{
-		Name:  proto.String(cqi.Name),
-		Query: proto.String(cqi.Query),
+	pb := &internal.ContinuousQueryInfo{
+		Name:    proto.String(cqi.Name),
+		Query:   proto.String(cqi.Query),
+		LastRun: proto.Int64(MarshalTime(cqi.LastRun)),
+	}
+	if cqi.LastNodeID != 0 {
+		pb.LastNodeID = proto.Uint64(cqi.LastNodeID)
+		pb.LastDuration = proto.Int64(int64(cqi.LastDuration))
+		pb.LastPointsWritten = proto.Int64(cqi.LastPointsWritten)
+		pb.LastSuccessStart = proto.Int64(MarshalTime(cqi.LastSuccessStart))
+		pb.LastSuccessEnd = proto.Int64(MarshalTime(cqi.LastSuccessEnd))
+		pb.LastError = proto.String(cqi.LastError)
+		pb.LastErrorTime = proto.Int64(MarshalTime(cqi.LastErrorTime))
 	}
+	return pb
 }
 
 // unmarshal deserializes from a protobuf representation.
 func (cqi *ContinuousQueryInfo) unmarshal(pb *internal.ContinuousQueryInfo) {
 	cqi.Name = pb.GetName()
 	cqi.Query = pb.GetQuery()
+	cqi.LastRun = UnmarshalTime(pb.GetLastRun())
+	cqi.LastNodeID = pb.GetLastNodeID()
+	cqi.LastDuration = time.Duration(pb.GetLastDuration())
+	cqi.LastPointsWritten = pb.GetLastPointsWritten()
+	cqi.LastSuccessStart = UnmarshalTime(pb.GetLastSuccessStart())
+	cqi.LastSuccessEnd = UnmarshalTime(pb.GetLastSuccessEnd())
+	cqi.LastError = pb.GetLastError()
+	cqi.LastErrorTime = UnmarshalTime(pb.GetLastErrorTime())
+}
+
+// Types of dropped objects.
+const (
+	DroppedDatabase    = "database"
+	DroppedMeasurement = "measurement"
+	DroppedShard       = "shard"
+)
+
+// DroppedInfo represents a database, measurement or shard that was moved to
+// the trash of the data nodes by a DROP statement.
+type DroppedInfo struct {
+	Type      string
+	Database  string
+	Name      string // measurement name
+	ShardID   uint64
+	DroppedAt time.Time
+
+	// DatabaseInfo and Privileges hold the database and the privileges of
+	// users on it when a database is dropped.
+	DatabaseInfo *DatabaseInfo
+	Privileges   map[string]influxql.Privilege
+
+	// RetentionPolicy and ShardGroup hold the retention policy and shard
+	// group, with only the dropped shard, when a shard is dropped.
+	RetentionPolicy string
+	ShardGroup      *ShardGroupInfo
+}
+
+// clone returns a deep copy of di.
+func (di DroppedInfo) clone() DroppedInfo {
+	other := di
+
+	if di.DatabaseInfo != nil {
+		dbi := di.DatabaseInfo.clone()
+		other.DatabaseInfo = &dbi
+	}
+
+	if di.Privileges != nil {
+		other.Privileges = make(map[string]influxql.Privilege, len(di.Privileges))
+		for k, v := range di.Privileges {
+			other.Privileges[k] = v
+		}
+	}
+
+	if di.ShardGroup != nil {
+		sgi := di.ShardGroup.clone()
+		other.ShardGroup = &sgi
+	}
+
+	return other
+}
+
+// marshal serializes to a protobuf representation.
+func (di DroppedInfo) marshal() *internal.DroppedInfo {
+	pb := &internal.DroppedInfo{
+		Type:      proto.String(di.Type),
+		Database:  proto.String(di.Database),
+		DroppedAt: proto.Int64(MarshalTime(di.DroppedAt)),
+	}
+
+	if di.Name != "" {
+		pb.Name = proto.String(di.Name)
+	}
+	if di.ShardID != 0 {
+		pb.ShardID = proto.Uint64(di.ShardID)
+	}
+	if di.DatabaseInfo != nil {
+		pb.DatabaseInfo = di.DatabaseInfo.marshal()
+	}
+
+	pb.Privileges = make([]*internal.DroppedPrivilege, 0, len(di.Privileges))
+	for user, p := range di.Privileges {
+		pb.Privileges = append(pb.Privileges, &internal.DroppedPrivilege{
+			User:      proto.String(user),
+			Privilege: proto.Int32(int32(p)),
+		})
+	}
+
+	if di.RetentionPolicy != "" {
+		pb.RetentionPolicy = proto.String(di.RetentionPolicy)
+	}
+	if di.ShardGroup != nil {
+		pb.ShardGroup = di.ShardGroup.marshal()
+	}
+
+	return pb
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (di *DroppedInfo) unmarshal(pb *internal.DroppedInfo) {
+	di.Type = pb.GetType()
+	di.Database = pb.GetDatabase()
+	di.Name = pb.GetName()
+	di.ShardID = pb.GetShardID()
+	di.DroppedAt = UnmarshalTime(pb.GetDroppedAt())
+
+	if pb.DatabaseInfo != nil {
+		di.DatabaseInfo = &DatabaseInfo{}
+		di.DatabaseInfo.unmarshal(pb.GetDatabaseInfo())
+	}
+
+	if len(pb.GetPrivileges()) > 0 {
+		di.Privileges = make(map[string]influxql.Privilege)
+		for _, p := range pb.GetPrivileges() {
+			di.Privileges[p.GetUser()] = influxql.Privilege(p.GetPrivilege())
+		}
+	}
+
+	di.RetentionPolicy = pb.GetRetentionPolicy()
+	if pb.ShardGroup != nil {
+		di.ShardGroup = &ShardGroupInfo{}
+		di.ShardGroup.unmarshal(pb.GetShardGroup())
+	}
 }
 
 var _ query.Authorizer = (*UserInfo)(nil)
@@ -226,6 +226,7 @@ func TestData_TruncateShardGroups(t *testing.T) {
 		}
 	}
 
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
 	must(data.CreateDatabase("db"))
 	rp := meta.NewRetentionPolicyInfo("rp")
 	rp.ShardGroupDuration = 24 * time.Hour
@@ -315,6 +316,58 @@ func TestData_TruncateShardGroups(t *testing.T) {
 	}
 }
 
+func TestData_AddRemoveShardOwner(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
+	must(data.CreateDataNode("host1:8086", "host1:8088"))
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.ReplicaN = 2
+	rp.ShardGroupDuration = 24 * time.Hour
+	must(data.CreateRetentionPolicy("db", rp, true))
+	must(data.CreateShardGroup("db", "rp", time.Unix(0, 0)))
+
+	sg, err := data.ShardGroupByTimestamp("db", "rp", time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := sg.Shards[0].ID
+
+	owners := func() []meta.ShardOwner {
+		sg, _ := data.ShardGroupByTimestamp("db", "rp", time.Unix(0, 0))
+		return sg.Shards[0].Owners
+	}
+
+	must(data.RemoveShardOwner(id, 1))
+	if got := owners(); len(got) != 1 || got[0].NodeID != 2 {
+		t.Fatalf("unexpected owners after remove: %v", got)
+	}
+
+	if err := data.RemoveShardOwner(id, 2); err != meta.ErrShardNotReplicated {
+		t.Fatalf("unexpected error removing last owner: %v", err)
+	}
+
+	must(data.AddShardOwner(id, 1))
+	must(data.AddShardOwner(id, 1))
+	if got := owners(); len(got) != 2 {
+		t.Fatalf("unexpected owners after add: %v", got)
+	}
+
+	if err := data.AddShardOwner(id, 3); err != meta.ErrNodeNotFound {
+		t.Fatalf("unexpected error adding unknown node: %v", err)
+	}
+	if err := data.AddShardOwner(id+100, 1); err != meta.ErrShardNotFound {
+		t.Fatalf("unexpected error adding owner to unknown shard: %v", err)
+	}
+}
+
 func TestUserInfo_AuthorizeDatabase(t *testing.T) {
 	emptyUser := &meta.UserInfo{}
 	if !emptyUser.AuthorizeDatabase(influxql.NoPrivileges, "anydb") {
@@ -353,3 +406,604 @@ func TestShardGroupInfo_Contains(t *testing.T) {
 		})
 	}
 }
+
+func TestData_SetDataNodeStatus(t *testing.T) {
+	data := &meta.Data{}
+	if err := data.CreateDataNode("host0:8086", "host0:8088"); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(100, 0).UTC()
+	if err := data.SetDataNodeStatus(1, meta.NodeStatusUp, now); err != nil {
+		t.Fatal(err)
+	} else if n := data.DataNode(1); n.Status != meta.NodeStatusUp || !n.LastSeen.Equal(now) {
+		t.Fatalf("unexpected node after status change: %+v", n)
+	}
+
+	if err := data.SetDataNodeStatus(1, meta.NodeStatusDown, time.Time{}); err != nil {
+		t.Fatal(err)
+	} else if n := data.DataNode(1); !n.Down() || !n.LastSeen.Equal(now) {
+		t.Fatalf("expected node to be down: %+v", n)
+	}
+
+	// A late status change brings the node back up without moving last-seen back.
+	if err := data.SetDataNodeStatus(1, meta.NodeStatusUp, now.Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	} else if n := data.DataNode(1); n.Down() || !n.LastSeen.Equal(now) {
+		t.Fatalf("unexpected node after late status change: %+v", n)
+	}
+
+	// Ensure the status survives encoding.
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other meta.Data
+	if err := other.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(other.DataNodes, data.DataNodes) {
+		t.Fatalf("unexpected nodes after decode:\n\texp=%+v\n\tgot=%+v", data.DataNodes, other.DataNodes)
+	}
+
+	if err := data.SetDataNodeStatus(2, meta.NodeStatusUp, now); err != meta.ErrNodeNotFound {
+		t.Fatalf("unexpected error for unknown node: %v", err)
+	}
+}
+
+func TestData_Consistency(t *testing.T) {
+	data := &meta.Data{}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+
+	rpi := &meta.RetentionPolicyInfo{Name: "rp0", ReplicaN: 1, Consistency: "quorum"}
+	if err := data.CreateRetentionPolicy("db0", rpi, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// A policy that only differs by consistency level is a different policy.
+	other := &meta.RetentionPolicyInfo{Name: "rp0", ReplicaN: 1, Consistency: "all"}
+	if err := data.CreateRetentionPolicy("db0", other, true); err != meta.ErrRetentionPolicyExists {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rpu := &meta.RetentionPolicyUpdate{}
+	rpu.SetConsistency("all")
+	if err := data.UpdateRetentionPolicy("db0", "rp0", rpu, false); err != nil {
+		t.Fatal(err)
+	} else if rp := data.Database("db0").RetentionPolicy("rp0"); rp.Consistency != "all" {
+		t.Fatalf("unexpected policy consistency: %q", rp.Consistency)
+	}
+
+	if err := data.SetDatabaseConsistency("db0", "one"); err != nil {
+		t.Fatal(err)
+	} else if err := data.SetDatabaseConsistency("db1", "one"); err == nil || err.Error() != freetsdb.ErrDatabaseNotFound("db1").Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Ensure the levels survive encoding.
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded meta.Data
+	if err := decoded.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	di := decoded.Database("db0")
+	if di.Consistency != "one" {
+		t.Fatalf("unexpected database consistency: %q", di.Consistency)
+	} else if rp := di.RetentionPolicy("rp0"); rp.Consistency != "all" {
+		t.Fatalf("unexpected policy consistency: %q", rp.Consistency)
+	}
+}
+
+func TestData_SetContinuousQueryLastRun(t *testing.T) {
+	data := &meta.Data{}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateContinuousQuery("db0", "cq0", "SELECT count(value) INTO foo FROM bar GROUP BY time(1m)"); err != nil {
+		t.Fatal(err)
+	}
+
+	t0 := time.Unix(0, int64(10*time.Minute)).UTC()
+	if err := data.SetContinuousQueryLastRun("db0", "cq0", t0); err != nil {
+		t.Fatal(err)
+	}
+
+	// An older time must not move the last run back.
+	if err := data.SetContinuousQueryLastRun("db0", "cq0", t0.Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	} else if err := data.SetContinuousQueryLastRun("db0", "cq1", t0); err != meta.ErrContinuousQueryNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded meta.Data
+	if err := decoded.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	if cqi := decoded.Database("db0").ContinuousQueries[0]; !cqi.LastRun.Equal(t0) {
+		t.Fatalf("unexpected last run: %s", cqi.LastRun)
+	}
+}
+
+func TestData_SetContinuousQueryStatus(t *testing.T) {
+	data := &meta.Data{}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateContinuousQuery("db0", "cq0", "SELECT count(value) INTO foo FROM bar GROUP BY time(1m)"); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Unix(0, int64(10*time.Minute)).UTC()
+	if err := data.SetContinuousQueryStatus("db0", "cq0", meta.ContinuousQueryStatus{
+		NodeID:        2,
+		Time:          start.Add(time.Minute),
+		Duration:      time.Second,
+		StartTime:     start,
+		EndTime:       start.Add(time.Minute),
+		PointsWritten: 10,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed execution keeps the last successful interval and last run.
+	if err := data.SetContinuousQueryStatus("db0", "cq0", meta.ContinuousQueryStatus{
+		NodeID:    3,
+		Time:      start.Add(2 * time.Minute),
+		Duration:  2 * time.Second,
+		StartTime: start.Add(time.Minute),
+		EndTime:   start.Add(2 * time.Minute),
+		Err:       "shard not found",
+		LastRun:   start.Add(2 * time.Minute),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded meta.Data
+	if err := decoded.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := meta.ContinuousQueryInfo{
+		Name:              "cq0",
+		Query:             "SELECT count(value) INTO foo FROM bar GROUP BY time(1m)",
+		LastNodeID:        3,
+		LastDuration:      2 * time.Second,
+		LastPointsWritten: 10,
+		LastSuccessStart:  start,
+		LastSuccessEnd:    start.Add(time.Minute),
+		LastError:         "shard not found",
+		LastErrorTime:     start.Add(2 * time.Minute),
+	}
+	if cqi := decoded.Database("db0").ContinuousQueries[0]; !reflect.DeepEqual(cqi, exp) {
+		t.Fatalf("unexpected continuous query:\ngot=%#v\nexp=%#v", cqi, exp)
+	}
+
+	// A later successful execution clears the error.
+	if err := decoded.SetContinuousQueryStatus("db0", "cq0", meta.ContinuousQueryStatus{
+		NodeID:        2,
+		Time:          start.Add(3 * time.Minute),
+		Duration:      time.Second,
+		StartTime:     start.Add(time.Minute),
+		EndTime:       start.Add(3 * time.Minute),
+		PointsWritten: 20,
+		LastRun:       start.Add(3 * time.Minute),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	exp.LastNodeID, exp.LastDuration, exp.LastPointsWritten = 2, time.Second, 20
+	exp.LastSuccessStart, exp.LastSuccessEnd = start.Add(time.Minute), start.Add(3*time.Minute)
+	exp.LastError, exp.LastErrorTime = "", time.Time{}
+	exp.LastRun = start.Add(3 * time.Minute)
+	if cqi := decoded.Database("db0").ContinuousQueries[0]; !reflect.DeepEqual(cqi, exp) {
+		t.Fatalf("unexpected continuous query:\ngot=%#v\nexp=%#v", cqi, exp)
+	}
+}
+
+func TestData_Downsample(t *testing.T) {
+	data := &meta.Data{}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"raw", "hourly"} {
+		if err := data.CreateRetentionPolicy("db0", &meta.RetentionPolicyInfo{Name: name, ReplicaN: 1}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := data.CreateDownsample("db0", "raw", "hourly", time.Hour, []string{"mean", "max"}); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateDownsample("db0", "raw", "hourly", time.Hour, []string{"mean", "max"}); err != nil {
+		t.Fatalf("unexpected error creating the same rule: %v", err)
+	} else if err := data.CreateDownsample("db0", "raw", "hourly", time.Minute, []string{"mean"}); err != meta.ErrDownsampleExists {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := data.CreateDownsample("db0", "raw", "raw", time.Hour, []string{"mean"}); err != meta.ErrDownsampleSameTarget {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := data.CreateDownsample("db0", "raw", "daily", time.Hour, []string{"mean"}); err == nil || err.Error() != freetsdb.ErrRetentionPolicyNotFound("daily").Error() {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lastRun := time.Unix(0, int64(2*time.Hour)).UTC()
+	if err := data.SetDownsampleLastRun("db0", "raw", "hourly", lastRun); err != nil {
+		t.Fatal(err)
+	}
+
+	// Renaming the target policy must keep the rule.
+	rpu := &meta.RetentionPolicyUpdate{}
+	rpu.SetName("1h")
+	if err := data.UpdateRetentionPolicy("db0", "hourly", rpu, false); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded meta.Data
+	if err := decoded.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	exp := []meta.DownsampleInfo{{Target: "1h", Interval: time.Hour, Aggregates: []string{"mean", "max"}, LastRun: lastRun}}
+	if rpi, _ := decoded.RetentionPolicy("db0", "raw"); !reflect.DeepEqual(rpi.Downsamples, exp) {
+		t.Fatalf("unexpected downsamples: %#v", rpi.Downsamples)
+	}
+
+	// Dropping the target policy must drop the rule.
+	if err := data.DropRetentionPolicy("db0", "1h"); err != nil {
+		t.Fatal(err)
+	} else if rpi, _ := data.RetentionPolicy("db0", "raw"); len(rpi.Downsamples) != 0 {
+		t.Fatalf("unexpected downsamples: %#v", rpi.Downsamples)
+	} else if err := data.DropDownsample("db0", "raw", "1h"); err != meta.ErrDownsampleNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestData_ColdTier(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.ShardGroupDuration = 24 * time.Hour
+	must(data.CreateRetentionPolicy("db", rp, true))
+	must(data.CreateShardGroup("db", "rp", time.Unix(0, 0)))
+
+	// Shard groups only become cold once the policy has a cold duration.
+	rpi, _ := data.RetentionPolicy("db", "rp")
+	now := time.Unix(0, 0).Add(72 * time.Hour)
+	if groups := rpi.ColdShardGroups(now); len(groups) != 0 {
+		t.Fatalf("unexpected cold shard groups: %d", len(groups))
+	}
+
+	rpu := &meta.RetentionPolicyUpdate{}
+	rpu.SetColdAfter(24 * time.Hour)
+	must(data.UpdateRetentionPolicy("db", "rp", rpu, false))
+
+	rpi, _ = data.RetentionPolicy("db", "rp")
+	groups := rpi.ColdShardGroups(now)
+	if len(groups) != 1 {
+		t.Fatalf("unexpected cold shard groups: %d", len(groups))
+	} else if groups := rpi.ColdShardGroups(now.Add(-24 * time.Hour)); len(groups) != 0 {
+		t.Fatalf("unexpected cold shard groups: %d", len(groups))
+	}
+
+	si := groups[0].Shards[0]
+	must(data.SetShardOwnerTier(si.ID, si.Owners[0].NodeID, "cold"))
+	if err := data.SetShardOwnerTier(si.ID, si.Owners[0].NodeID+1, "cold"); err != meta.ErrShardNotOwned {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := data.SetShardOwnerTier(si.ID+1, si.Owners[0].NodeID, "cold"); err != meta.ErrShardNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded meta.Data
+	must(decoded.UnmarshalBinary(buf))
+
+	rpi, _ = decoded.RetentionPolicy("db", "rp")
+	if rpi.ColdAfter != 24*time.Hour {
+		t.Fatalf("unexpected cold after: %s", rpi.ColdAfter)
+	} else if tier := rpi.ShardGroups[0].Shards[0].Owners[0].Tier; tier != "cold" {
+		t.Fatalf("unexpected tier: %q", tier)
+	}
+}
+
+func TestData_RetentionPolicyDuplicatePolicy(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.DuplicatePolicy = "first"
+	must(data.CreateRetentionPolicy("db", rp, true))
+
+	// Creating the policy again with another duplicate policy conflicts.
+	other := *rp
+	other.DuplicatePolicy = "max"
+	if err := data.CreateRetentionPolicy("db", &other, false); err != meta.ErrRetentionPolicyExists {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rpu := &meta.RetentionPolicyUpdate{}
+	rpu.SetDuplicatePolicy("keep")
+	must(data.UpdateRetentionPolicy("db", "rp", rpu, false))
+
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded meta.Data
+	must(decoded.UnmarshalBinary(buf))
+
+	rpi, _ := decoded.RetentionPolicy("db", "rp")
+	if rpi.DuplicatePolicy != "keep" {
+		t.Fatalf("unexpected duplicate policy: %q", rpi.DuplicatePolicy)
+	}
+}
+
+func TestData_RetentionPolicyCodecs(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.StringCodec = "zstd"
+	must(data.CreateRetentionPolicy("db", rp, true))
+
+	// Creating the policy again with another codec conflicts.
+	other := *rp
+	other.StringCodec = "snappy"
+	if err := data.CreateRetentionPolicy("db", &other, false); err != meta.ErrRetentionPolicyExists {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rpu := &meta.RetentionPolicyUpdate{}
+	rpu.SetFloatCodec("zstd")
+	must(data.UpdateRetentionPolicy("db", "rp", rpu, false))
+
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded meta.Data
+	must(decoded.UnmarshalBinary(buf))
+
+	rpi, _ := decoded.RetentionPolicy("db", "rp")
+	if rpi.StringCodec != "zstd" || rpi.FloatCodec != "zstd" {
+		t.Fatalf("unexpected codecs: string=%q float=%q", rpi.StringCodec, rpi.FloatCodec)
+	}
+}
+
+func TestData_ArchiveShard(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
+	must(data.CreateDataNode("host1:8086", "host1:8088"))
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.ShardGroupDuration = 24 * time.Hour
+	must(data.CreateRetentionPolicy("db", rp, true))
+	must(data.CreateShardGroup("db", "rp", time.Unix(0, 0)))
+
+	rpi, _ := data.RetentionPolicy("db", "rp")
+	id := rpi.ShardGroups[0].Shards[0].ID
+
+	if err := data.UnarchiveShard(id, 1); err != meta.ErrShardNotArchived {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	at := time.Unix(0, 0).Add(72 * time.Hour).UTC()
+	must(data.ArchiveShard(id, at))
+	if err := data.ArchiveShard(id, at); err != meta.ErrShardArchived {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := data.AddShardOwner(id, 1); err != meta.ErrShardArchived {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := data.ArchiveShard(100, at); err != meta.ErrShardNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded meta.Data
+	must(decoded.UnmarshalBinary(buf))
+
+	rpi, _ = decoded.RetentionPolicy("db", "rp")
+	if si := rpi.ShardGroups[0].Shards[0]; !si.ArchivedAt.Equal(at) {
+		t.Fatalf("unexpected archived at: %s", si.ArchivedAt)
+	} else if len(si.Owners) != 0 {
+		t.Fatalf("unexpected owners: %v", si.Owners)
+	}
+
+	if err := decoded.UnarchiveShard(id, 3); err != meta.ErrNodeNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	must(decoded.UnarchiveShard(id, 2))
+
+	rpi, _ = decoded.RetentionPolicy("db", "rp")
+	if si := rpi.ShardGroups[0].Shards[0]; si.Archived() {
+		t.Fatal("expected shard to be unarchived")
+	} else if len(si.Owners) != 1 || si.Owners[0].NodeID != 2 {
+		t.Fatalf("unexpected owners: %v", si.Owners)
+	}
+}
+
+func TestData_UndropShardGroup(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
+	must(data.CreateDatabase("db"))
+	rp := meta.NewRetentionPolicyInfo("rp")
+	rp.Duration = 48 * time.Hour
+	rp.ShardGroupDuration = 24 * time.Hour
+	must(data.CreateRetentionPolicy("db", rp, true))
+	must(data.CreateShardGroup("db", "rp", time.Unix(0, 0)))
+
+	rpi, _ := data.RetentionPolicy("db", "rp")
+	id := rpi.ShardGroups[0].ID
+	now := time.Unix(0, 0).Add(96 * time.Hour).UTC()
+
+	if err := data.UndropShardGroup(id, now); err != meta.ErrShardGroupNotPending {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	must(data.ExpireShardGroup("db", "rp", id, now.Add(time.Hour)))
+
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded meta.Data
+	must(decoded.UnmarshalBinary(buf))
+
+	rpi, _ = decoded.RetentionPolicy("db", "rp")
+	if sgi := rpi.ShardGroups[0]; !sgi.Deleted() || !sgi.PendingDeletion(now) {
+		t.Fatal("expected shard group to be pending deletion")
+	} else if sgi.PendingDeletion(now.Add(time.Hour)) {
+		t.Fatal("expected shard group to be purged")
+	}
+
+	// The shard group is still outside the retention policy duration.
+	if err := decoded.UndropShardGroup(id, now); err != meta.ErrShardGroupExpired {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rpu := &meta.RetentionPolicyUpdate{}
+	rpu.SetDuration(7 * 24 * time.Hour)
+	must(decoded.UpdateRetentionPolicy("db", "rp", rpu, false))
+
+	if err := decoded.UndropShardGroup(id, now.Add(time.Hour)); err != meta.ErrShardGroupNotPending {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := decoded.UndropShardGroup(id+100, now); err != meta.ErrShardGroupNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	must(decoded.UndropShardGroup(id, now))
+
+	rpi, _ = decoded.RetentionPolicy("db", "rp")
+	if sgi := rpi.ShardGroups[0]; sgi.Deleted() || !sgi.PurgeAt.IsZero() {
+		t.Fatal("expected shard group to be recovered")
+	}
+}
+
+func TestData_Trash(t *testing.T) {
+	data := &meta.Data{}
+
+	must := func(err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(data.CreateDataNode("host0:8086", "host0:8088"))
+	must(data.CreateDatabase("db"))
+	must(data.CreateRetentionPolicy("db", meta.NewRetentionPolicyInfo("rp"), true))
+	must(data.CreateShardGroup("db", "rp", time.Unix(0, 0)))
+	must(data.CreateUser("susy", "pass", false))
+	must(data.SetPrivilege("susy", "db", influxql.ReadPrivilege))
+
+	rpi, _ := data.RetentionPolicy("db", "rp")
+	sgID, shardID := rpi.ShardGroups[0].ID, rpi.ShardGroups[0].Shards[0].ID
+	now := time.Unix(0, 0).UTC()
+
+	// Drop and restore the only shard of a shard group.
+	must(data.TrashShard(shardID, now))
+	rpi, _ = data.RetentionPolicy("db", "rp")
+	if sgi := rpi.ShardGroups[0]; !sgi.Deleted() || len(sgi.Shards) != 0 {
+		t.Fatalf("unexpected shard group: %+v", sgi)
+	}
+	must(data.UndropShard(shardID))
+	rpi, _ = data.RetentionPolicy("db", "rp")
+	if sgi := rpi.ShardGroups[0]; sgi.Deleted() || sgi.ID != sgID || len(sgi.Shards) != 1 || sgi.Shards[0].ID != shardID {
+		t.Fatalf("unexpected shard group: %+v", sgi)
+	} else if err := data.UndropShard(shardID); err != meta.ErrDroppedNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Drop a measurement twice, as when a failed DROP MEASUREMENT is re-run,
+	// and then the database.
+	must(data.TrashMeasurement("db", "cpu", now))
+	must(data.TrashMeasurement("db", "cpu", now))
+	must(data.TrashDatabase("db", now.Add(time.Hour)))
+	data.TrashEnabled = true
+	if data.Database("db") != nil {
+		t.Fatal("expected database to be dropped")
+	} else if p, _ := data.UserPrivilege("susy", "db"); *p != influxql.NoPrivileges {
+		t.Fatalf("unexpected privilege: %v", *p)
+	}
+
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded meta.Data
+	must(decoded.UnmarshalBinary(buf))
+	if len(decoded.Dropped) != 2 {
+		t.Fatalf("unexpected dropped: %+v", decoded.Dropped)
+	} else if !decoded.TrashEnabled {
+		t.Fatal("expected trash to be enabled")
+	}
+
+	if err := decoded.UndropMeasurement("db", "cpu"); err == nil {
+		t.Fatal("expected error undropping a measurement of a dropped database")
+	}
+	must(decoded.UndropDatabase("db"))
+	if err := decoded.UndropDatabase("db"); err != meta.ErrDroppedNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rpi, _ = decoded.RetentionPolicy("db", "rp")
+	if rpi == nil || len(rpi.ShardGroups) != 1 || rpi.ShardGroups[0].Shards[0].ID != shardID {
+		t.Fatalf("unexpected retention policy: %+v", rpi)
+	} else if p, _ := decoded.UserPrivilege("susy", "db"); *p != influxql.ReadPrivilege {
+		t.Fatalf("unexpected privilege: %v", *p)
+	}
+	must(decoded.UndropMeasurement("db", "cpu"))
+
+	// Records of objects dropped before the given time are pruned.
+	must(decoded.TrashShard(shardID, now))
+	decoded.PruneDropped(now.Add(time.Second))
+	if len(decoded.Dropped) != 0 {
+		t.Fatalf("unexpected dropped: %+v", decoded.Dropped)
+	}
+}
@@ -48,6 +48,12 @@ var (
 	ErrInvalidName = errors.New("invalid name")
 )
 
+var (
+	// ErrDroppedNotFound is returned when undropping a database, measurement
+	// or shard that was not dropped, or whose trash has been purged.
+	ErrDroppedNotFound = errors.New("dropped object not found")
+)
+
 var (
 	// ErrRetentionPolicyExists is returned when creating an already existing policy.
 	ErrRetentionPolicyExists = errors.New("retention policy already exists")
@@ -95,9 +101,32 @@ var (
 	// ErrShardGroupNotFound is returned when mutating a shard group that doesn't exist.
 	ErrShardGroupNotFound = errors.New("shard group not found")
 
+	// ErrShardGroupNotPending is returned when undropping a shard group that
+	// is not pending deletion.
+	ErrShardGroupNotPending = errors.New("shard group is not pending deletion")
+
+	// ErrShardGroupExpired is returned when undropping a shard group that is
+	// still outside the duration of its retention policy.
+	ErrShardGroupExpired = errors.New("shard group is outside the retention policy duration")
+
 	// ErrShardNotReplicated is returned if the node requested to be dropped has
 	// the last copy of a shard present and the force keyword was not used
 	ErrShardNotReplicated = errors.New("shard not replicated")
+
+	// ErrShardNotFound is returned when mutating a shard that doesn't exist.
+	ErrShardNotFound = errors.New("shard not found")
+
+	// ErrShardNotOwned is returned when mutating the owner of a shard on a
+	// node that does not own the shard.
+	ErrShardNotOwned = errors.New("shard not owned by node")
+
+	// ErrShardArchived is returned when writing to or changing the owners
+	// of an archived shard.
+	ErrShardArchived = errors.New("shard is archived")
+
+	// ErrShardNotArchived is returned when restoring a shard that is not
+	// archived.
+	ErrShardNotArchived = errors.New("shard is not archived")
 )
 
 var (
@@ -108,6 +137,18 @@ var (
 	ErrContinuousQueryNotFound = errors.New("continuous query not found")
 )
 
+var (
+	// ErrDownsampleExists is returned when creating an already existing downsample rule.
+	ErrDownsampleExists = errors.New("downsample already exists")
+
+	// ErrDownsampleNotFound is returned when removing a downsample rule that doesn't exist.
+	ErrDownsampleNotFound = errors.New("downsample not found")
+
+	// ErrDownsampleSameTarget is returned when a downsample rule would write
+	// into the retention policy it reads from.
+	ErrDownsampleSameTarget = errors.New("downsample target must differ from its source")
+)
+
 var (
 	// ErrSubscriptionExists is returned when creating an already existing subscription.
 	ErrSubscriptionExists = errors.New("subscription already exists")
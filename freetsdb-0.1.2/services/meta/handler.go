@@ -42,6 +42,7 @@ type handler struct {
 		peers() []string
 		metaServersHTTP() []string
 		dataServers() []string
+		dataNodeHeartbeat(id uint64) error
 	}
 	s *Service
 
@@ -114,6 +115,8 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			h.WrapHandler("remove-meta", h.serveRemoveMeta).ServeHTTP(w, r)
 		case "/join-cluster":
 			h.WrapHandler("join-cluster", h.serveJoinCluster).ServeHTTP(w, r)
+		case "/heartbeat":
+			h.WrapHandler("heartbeat", h.serveHeartbeat).ServeHTTP(w, r)
 		}
 	default:
 		http.Error(w, "", http.StatusBadRequest)
@@ -521,6 +524,52 @@ func (h *handler) serveLease(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+// serveHeartbeat records a heartbeat from a data node on the leader.
+func (h *handler) serveHeartbeat(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	// Get the ID of the data node.
+	nodeIDStr := q.Get("nodeid")
+	if nodeIDStr == "" {
+		http.Error(w, "node ID required", http.StatusBadRequest)
+		return
+	}
+
+	// Redirect to leader if necessary.
+	leader := h.store.leaderHTTP()
+	if leader != h.s.remoteAddr(h.s.httpAddr) {
+		if leader == "" {
+			// No cluster leader. Client will have to try again later.
+			h.httpError(errors.New("no leader"), w, http.StatusServiceUnavailable)
+			return
+		}
+		scheme := "http://"
+		if h.config.HTTPSEnabled {
+			scheme = "https://"
+		}
+
+		leader = scheme + leader + "/heartbeat?" + q.Encode()
+		http.Redirect(w, r, leader, http.StatusTemporaryRedirect)
+		return
+	}
+
+	// Convert node ID to an int.
+	nodeID, err := strconv.ParseUint(nodeIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid node ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.store.dataNodeHeartbeat(nodeID); err == ErrNodeNotFound {
+		h.httpError(err, w, http.StatusNotFound)
+		return
+	} else if err != nil {
+		h.httpError(err, w, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
 type gzipResponseWriter struct {
 	io.Writer
 	http.ResponseWriter
@@ -14,6 +14,7 @@ It has these top-level messages:
 	DatabaseInfo
 	RetentionPolicySpec
 	RetentionPolicyInfo
+	DownsampleInfo
 	ShardGroupInfo
 	ShardInfo
 	SubscriptionInfo
@@ -21,6 +22,8 @@ It has these top-level messages:
 	ContinuousQueryInfo
 	UserInfo
 	UserPrivilege
+	DroppedInfo
+	DroppedPrivilege
 	Command
 	CreateNodeCommand
 	DeleteNodeCommand
@@ -52,6 +55,23 @@ It has these top-level messages:
 	Response
 	SetMetaNodeCommand
 	DropShardCommand
+	AddShardOwnerCommand
+	RemoveShardOwnerCommand
+	SetDataNodeStatusCommand
+	SetDatabaseConsistencyCommand
+	SetContinuousQueryLastRunCommand
+	SetContinuousQueryStatusCommand
+	CreateDownsampleCommand
+	DropDownsampleCommand
+	SetDownsampleLastRunCommand
+	SetShardOwnerTierCommand
+	ArchiveShardCommand
+	UnarchiveShardCommand
+	UndropShardGroupCommand
+	TrashCommand
+	UndropCommand
+	PruneDroppedCommand
+	SetTrashEnabledCommand
 */
 package internal
 
@@ -102,6 +122,23 @@ const (
 	Command_DeleteDataNodeCommand            Command_Type = 28
 	Command_SetMetaNodeCommand               Command_Type = 29
 	Command_DropShardCommand                 Command_Type = 30
+	Command_AddShardOwnerCommand             Command_Type = 31
+	Command_RemoveShardOwnerCommand          Command_Type = 32
+	Command_SetDataNodeStatusCommand         Command_Type = 34
+	Command_SetDatabaseConsistencyCommand    Command_Type = 35
+	Command_SetContinuousQueryLastRunCommand Command_Type = 36
+	Command_SetContinuousQueryStatusCommand  Command_Type = 37
+	Command_CreateDownsampleCommand          Command_Type = 38
+	Command_DropDownsampleCommand            Command_Type = 39
+	Command_SetDownsampleLastRunCommand      Command_Type = 40
+	Command_SetShardOwnerTierCommand         Command_Type = 41
+	Command_ArchiveShardCommand              Command_Type = 42
+	Command_UnarchiveShardCommand            Command_Type = 43
+	Command_UndropShardGroupCommand          Command_Type = 44
+	Command_TrashCommand                     Command_Type = 45
+	Command_UndropCommand                    Command_Type = 46
+	Command_PruneDroppedCommand              Command_Type = 47
+	Command_SetTrashEnabledCommand           Command_Type = 48
 )
 
 var Command_Type_name = map[int32]string{
@@ -134,6 +171,23 @@ var Command_Type_name = map[int32]string{
 	28: "DeleteDataNodeCommand",
 	29: "SetMetaNodeCommand",
 	30: "DropShardCommand",
+	31: "AddShardOwnerCommand",
+	32: "RemoveShardOwnerCommand",
+	34: "SetDataNodeStatusCommand",
+	35: "SetDatabaseConsistencyCommand",
+	36: "SetContinuousQueryLastRunCommand",
+	37: "SetContinuousQueryStatusCommand",
+	38: "CreateDownsampleCommand",
+	39: "DropDownsampleCommand",
+	40: "SetDownsampleLastRunCommand",
+	41: "SetShardOwnerTierCommand",
+	42: "ArchiveShardCommand",
+	43: "UnarchiveShardCommand",
+	44: "UndropShardGroupCommand",
+	45: "TrashCommand",
+	46: "UndropCommand",
+	47: "PruneDroppedCommand",
+	48: "SetTrashEnabledCommand",
 }
 var Command_Type_value = map[string]int32{
 	"CreateNodeCommand":                1,
@@ -165,6 +219,23 @@ var Command_Type_value = map[string]int32{
 	"DeleteDataNodeCommand":            28,
 	"SetMetaNodeCommand":               29,
 	"DropShardCommand":                 30,
+	"AddShardOwnerCommand":             31,
+	"RemoveShardOwnerCommand":          32,
+	"SetDataNodeStatusCommand":         34,
+	"SetDatabaseConsistencyCommand":    35,
+	"SetContinuousQueryLastRunCommand": 36,
+	"SetContinuousQueryStatusCommand":  37,
+	"CreateDownsampleCommand":          38,
+	"DropDownsampleCommand":            39,
+	"SetDownsampleLastRunCommand":      40,
+	"SetShardOwnerTierCommand":         41,
+	"ArchiveShardCommand":              42,
+	"UnarchiveShardCommand":            43,
+	"UndropShardGroupCommand":          44,
+	"TrashCommand":                     45,
+	"UndropCommand":                    46,
+	"PruneDroppedCommand":              47,
+	"SetTrashEnabledCommand":           48,
 }
 
 func (x Command_Type) Enum() *Command_Type {
@@ -183,7 +254,7 @@ func (x *Command_Type) UnmarshalJSON(data []byte) error {
 	*x = Command_Type(value)
 	return nil
 }
-func (Command_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptorMeta, []int{12, 0} }
+func (Command_Type) EnumDescriptor() ([]byte, []int) { return fileDescriptorMeta, []int{15, 0} }
 
 type Data struct {
 	Term            *uint64         `protobuf:"varint,1,req,name=Term" json:"Term,omitempty"`
@@ -196,9 +267,11 @@ type Data struct {
 	MaxShardGroupID *uint64         `protobuf:"varint,8,req,name=MaxShardGroupID" json:"MaxShardGroupID,omitempty"`
 	MaxShardID      *uint64         `protobuf:"varint,9,req,name=MaxShardID" json:"MaxShardID,omitempty"`
 	// added for 0.10.0
-	DataNodes        []*NodeInfo `protobuf:"bytes,10,rep,name=DataNodes" json:"DataNodes,omitempty"`
-	MetaNodes        []*NodeInfo `protobuf:"bytes,11,rep,name=MetaNodes" json:"MetaNodes,omitempty"`
-	XXX_unrecognized []byte      `json:"-"`
+	DataNodes        []*NodeInfo    `protobuf:"bytes,10,rep,name=DataNodes" json:"DataNodes,omitempty"`
+	MetaNodes        []*NodeInfo    `protobuf:"bytes,11,rep,name=MetaNodes" json:"MetaNodes,omitempty"`
+	Dropped          []*DroppedInfo `protobuf:"bytes,12,rep,name=Dropped" json:"Dropped,omitempty"`
+	TrashEnabled     *bool          `protobuf:"varint,13,opt,name=TrashEnabled" json:"TrashEnabled,omitempty"`
+	XXX_unrecognized []byte         `json:"-"`
 }
 
 func (m *Data) Reset()                    { *m = Data{} }
@@ -283,10 +356,26 @@ func (m *Data) GetMetaNodes() []*NodeInfo {
 	return nil
 }
 
+func (m *Data) GetDropped() []*DroppedInfo {
+	if m != nil {
+		return m.Dropped
+	}
+	return nil
+}
+
+func (m *Data) GetTrashEnabled() bool {
+	if m != nil && m.TrashEnabled != nil {
+		return *m.TrashEnabled
+	}
+	return false
+}
+
 type NodeInfo struct {
 	ID               *uint64 `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	Host             *string `protobuf:"bytes,2,req,name=Host" json:"Host,omitempty"`
 	TCPHost          *string `protobuf:"bytes,3,opt,name=TCPHost" json:"TCPHost,omitempty"`
+	Status           *string `protobuf:"bytes,4,opt,name=Status" json:"Status,omitempty"`
+	LastSeen         *int64  `protobuf:"varint,5,opt,name=LastSeen" json:"LastSeen,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -316,11 +405,26 @@ func (m *NodeInfo) GetTCPHost() string {
 	return ""
 }
 
+func (m *NodeInfo) GetStatus() string {
+	if m != nil && m.Status != nil {
+		return *m.Status
+	}
+	return ""
+}
+
+func (m *NodeInfo) GetLastSeen() int64 {
+	if m != nil && m.LastSeen != nil {
+		return *m.LastSeen
+	}
+	return 0
+}
+
 type DatabaseInfo struct {
 	Name                   *string                `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	DefaultRetentionPolicy *string                `protobuf:"bytes,2,req,name=DefaultRetentionPolicy" json:"DefaultRetentionPolicy,omitempty"`
 	RetentionPolicies      []*RetentionPolicyInfo `protobuf:"bytes,3,rep,name=RetentionPolicies" json:"RetentionPolicies,omitempty"`
 	ContinuousQueries      []*ContinuousQueryInfo `protobuf:"bytes,4,rep,name=ContinuousQueries" json:"ContinuousQueries,omitempty"`
+	Consistency            *string                `protobuf:"bytes,5,opt,name=Consistency" json:"Consistency,omitempty"`
 	XXX_unrecognized       []byte                 `json:"-"`
 }
 
@@ -357,6 +461,13 @@ func (m *DatabaseInfo) GetContinuousQueries() []*ContinuousQueryInfo {
 	return nil
 }
 
+func (m *DatabaseInfo) GetConsistency() string {
+	if m != nil && m.Consistency != nil {
+		return *m.Consistency
+	}
+	return ""
+}
+
 type RetentionPolicySpec struct {
 	Name               *string `protobuf:"bytes,1,opt,name=Name" json:"Name,omitempty"`
 	Duration           *int64  `protobuf:"varint,2,opt,name=Duration" json:"Duration,omitempty"`
@@ -405,6 +516,12 @@ type RetentionPolicyInfo struct {
 	ReplicaN           *uint32             `protobuf:"varint,4,req,name=ReplicaN" json:"ReplicaN,omitempty"`
 	ShardGroups        []*ShardGroupInfo   `protobuf:"bytes,5,rep,name=ShardGroups" json:"ShardGroups,omitempty"`
 	Subscriptions      []*SubscriptionInfo `protobuf:"bytes,6,rep,name=Subscriptions" json:"Subscriptions,omitempty"`
+	Consistency        *string             `protobuf:"bytes,7,opt,name=Consistency" json:"Consistency,omitempty"`
+	Downsamples        []*DownsampleInfo   `protobuf:"bytes,8,rep,name=Downsamples" json:"Downsamples,omitempty"`
+	ColdAfter          *int64              `protobuf:"varint,9,opt,name=ColdAfter" json:"ColdAfter,omitempty"`
+	DuplicatePolicy    *string             `protobuf:"bytes,10,opt,name=DuplicatePolicy" json:"DuplicatePolicy,omitempty"`
+	StringCodec        *string             `protobuf:"bytes,11,opt,name=StringCodec" json:"StringCodec,omitempty"`
+	FloatCodec         *string             `protobuf:"bytes,12,opt,name=FloatCodec" json:"FloatCodec,omitempty"`
 	XXX_unrecognized   []byte              `json:"-"`
 }
 
@@ -455,6 +572,89 @@ func (m *RetentionPolicyInfo) GetSubscriptions() []*SubscriptionInfo {
 	return nil
 }
 
+func (m *RetentionPolicyInfo) GetConsistency() string {
+	if m != nil && m.Consistency != nil {
+		return *m.Consistency
+	}
+	return ""
+}
+
+func (m *RetentionPolicyInfo) GetDownsamples() []*DownsampleInfo {
+	if m != nil {
+		return m.Downsamples
+	}
+	return nil
+}
+
+func (m *RetentionPolicyInfo) GetColdAfter() int64 {
+	if m != nil && m.ColdAfter != nil {
+		return *m.ColdAfter
+	}
+	return 0
+}
+
+func (m *RetentionPolicyInfo) GetDuplicatePolicy() string {
+	if m != nil && m.DuplicatePolicy != nil {
+		return *m.DuplicatePolicy
+	}
+	return ""
+}
+
+func (m *RetentionPolicyInfo) GetStringCodec() string {
+	if m != nil && m.StringCodec != nil {
+		return *m.StringCodec
+	}
+	return ""
+}
+
+func (m *RetentionPolicyInfo) GetFloatCodec() string {
+	if m != nil && m.FloatCodec != nil {
+		return *m.FloatCodec
+	}
+	return ""
+}
+
+type DownsampleInfo struct {
+	Target           *string  `protobuf:"bytes,1,req,name=Target" json:"Target,omitempty"`
+	Interval         *int64   `protobuf:"varint,2,req,name=Interval" json:"Interval,omitempty"`
+	Aggregates       []string `protobuf:"bytes,3,rep,name=Aggregates" json:"Aggregates,omitempty"`
+	LastRun          *int64   `protobuf:"varint,4,opt,name=LastRun" json:"LastRun,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *DownsampleInfo) Reset()                    { *m = DownsampleInfo{} }
+func (m *DownsampleInfo) String() string            { return proto.CompactTextString(m) }
+func (*DownsampleInfo) ProtoMessage()               {}
+func (*DownsampleInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{5} }
+
+func (m *DownsampleInfo) GetTarget() string {
+	if m != nil && m.Target != nil {
+		return *m.Target
+	}
+	return ""
+}
+
+func (m *DownsampleInfo) GetInterval() int64 {
+	if m != nil && m.Interval != nil {
+		return *m.Interval
+	}
+	return 0
+}
+
+func (m *DownsampleInfo) GetAggregates() []string {
+	if m != nil {
+		return m.Aggregates
+	}
+	return nil
+}
+
+func (m *DownsampleInfo) GetLastRun() int64 {
+	if m != nil && m.LastRun != nil {
+		return *m.LastRun
+	}
+	return 0
+}
+
 type ShardGroupInfo struct {
 	ID               *uint64      `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	StartTime        *int64       `protobuf:"varint,2,req,name=StartTime" json:"StartTime,omitempty"`
@@ -462,13 +662,14 @@ type ShardGroupInfo struct {
 	DeletedAt        *int64       `protobuf:"varint,4,req,name=DeletedAt" json:"DeletedAt,omitempty"`
 	Shards           []*ShardInfo `protobuf:"bytes,5,rep,name=Shards" json:"Shards,omitempty"`
 	TruncatedAt      *int64       `protobuf:"varint,6,opt,name=TruncatedAt" json:"TruncatedAt,omitempty"`
+	PurgeAt          *int64       `protobuf:"varint,7,opt,name=PurgeAt" json:"PurgeAt,omitempty"`
 	XXX_unrecognized []byte       `json:"-"`
 }
 
 func (m *ShardGroupInfo) Reset()                    { *m = ShardGroupInfo{} }
 func (m *ShardGroupInfo) String() string            { return proto.CompactTextString(m) }
 func (*ShardGroupInfo) ProtoMessage()               {}
-func (*ShardGroupInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{5} }
+func (*ShardGroupInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{6} }
 
 func (m *ShardGroupInfo) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -512,17 +713,25 @@ func (m *ShardGroupInfo) GetTruncatedAt() int64 {
 	return 0
 }
 
+func (m *ShardGroupInfo) GetPurgeAt() int64 {
+	if m != nil && m.PurgeAt != nil {
+		return *m.PurgeAt
+	}
+	return 0
+}
+
 type ShardInfo struct {
 	ID               *uint64       `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	OwnerIDs         []uint64      `protobuf:"varint,2,rep,name=OwnerIDs" json:"OwnerIDs,omitempty"`
 	Owners           []*ShardOwner `protobuf:"bytes,3,rep,name=Owners" json:"Owners,omitempty"`
+	ArchivedAt       *int64        `protobuf:"varint,4,opt,name=ArchivedAt" json:"ArchivedAt,omitempty"`
 	XXX_unrecognized []byte        `json:"-"`
 }
 
 func (m *ShardInfo) Reset()                    { *m = ShardInfo{} }
 func (m *ShardInfo) String() string            { return proto.CompactTextString(m) }
 func (*ShardInfo) ProtoMessage()               {}
-func (*ShardInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{6} }
+func (*ShardInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{7} }
 
 func (m *ShardInfo) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -545,6 +754,13 @@ func (m *ShardInfo) GetOwners() []*ShardOwner {
 	return nil
 }
 
+func (m *ShardInfo) GetArchivedAt() int64 {
+	if m != nil && m.ArchivedAt != nil {
+		return *m.ArchivedAt
+	}
+	return 0
+}
+
 type SubscriptionInfo struct {
 	Name             *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Mode             *string  `protobuf:"bytes,2,req,name=Mode" json:"Mode,omitempty"`
@@ -555,7 +771,7 @@ type SubscriptionInfo struct {
 func (m *SubscriptionInfo) Reset()                    { *m = SubscriptionInfo{} }
 func (m *SubscriptionInfo) String() string            { return proto.CompactTextString(m) }
 func (*SubscriptionInfo) ProtoMessage()               {}
-func (*SubscriptionInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{7} }
+func (*SubscriptionInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{8} }
 
 func (m *SubscriptionInfo) GetName() string {
 	if m != nil && m.Name != nil {
@@ -580,13 +796,14 @@ func (m *SubscriptionInfo) GetDestinations() []string {
 
 type ShardOwner struct {
 	NodeID           *uint64 `protobuf:"varint,1,req,name=NodeID" json:"NodeID,omitempty"`
+	Tier             *string `protobuf:"bytes,2,opt,name=Tier" json:"Tier,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
 func (m *ShardOwner) Reset()                    { *m = ShardOwner{} }
 func (m *ShardOwner) String() string            { return proto.CompactTextString(m) }
 func (*ShardOwner) ProtoMessage()               {}
-func (*ShardOwner) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{8} }
+func (*ShardOwner) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{9} }
 
 func (m *ShardOwner) GetNodeID() uint64 {
 	if m != nil && m.NodeID != nil {
@@ -595,16 +812,31 @@ func (m *ShardOwner) GetNodeID() uint64 {
 	return 0
 }
 
+func (m *ShardOwner) GetTier() string {
+	if m != nil && m.Tier != nil {
+		return *m.Tier
+	}
+	return ""
+}
+
 type ContinuousQueryInfo struct {
-	Name             *string `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	Query            *string `protobuf:"bytes,2,req,name=Query" json:"Query,omitempty"`
-	XXX_unrecognized []byte  `json:"-"`
+	Name              *string `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Query             *string `protobuf:"bytes,2,req,name=Query" json:"Query,omitempty"`
+	LastRun           *int64  `protobuf:"varint,3,opt,name=LastRun" json:"LastRun,omitempty"`
+	LastNodeID        *uint64 `protobuf:"varint,4,opt,name=LastNodeID" json:"LastNodeID,omitempty"`
+	LastDuration      *int64  `protobuf:"varint,5,opt,name=LastDuration" json:"LastDuration,omitempty"`
+	LastPointsWritten *int64  `protobuf:"varint,6,opt,name=LastPointsWritten" json:"LastPointsWritten,omitempty"`
+	LastSuccessStart  *int64  `protobuf:"varint,7,opt,name=LastSuccessStart" json:"LastSuccessStart,omitempty"`
+	LastSuccessEnd    *int64  `protobuf:"varint,8,opt,name=LastSuccessEnd" json:"LastSuccessEnd,omitempty"`
+	LastError         *string `protobuf:"bytes,9,opt,name=LastError" json:"LastError,omitempty"`
+	LastErrorTime     *int64  `protobuf:"varint,10,opt,name=LastErrorTime" json:"LastErrorTime,omitempty"`
+	XXX_unrecognized  []byte  `json:"-"`
 }
 
 func (m *ContinuousQueryInfo) Reset()                    { *m = ContinuousQueryInfo{} }
 func (m *ContinuousQueryInfo) String() string            { return proto.CompactTextString(m) }
 func (*ContinuousQueryInfo) ProtoMessage()               {}
-func (*ContinuousQueryInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{9} }
+func (*ContinuousQueryInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{10} }
 
 func (m *ContinuousQueryInfo) GetName() string {
 	if m != nil && m.Name != nil {
@@ -620,6 +852,62 @@ func (m *ContinuousQueryInfo) GetQuery() string {
 	return ""
 }
 
+func (m *ContinuousQueryInfo) GetLastRun() int64 {
+	if m != nil && m.LastRun != nil {
+		return *m.LastRun
+	}
+	return 0
+}
+
+func (m *ContinuousQueryInfo) GetLastNodeID() uint64 {
+	if m != nil && m.LastNodeID != nil {
+		return *m.LastNodeID
+	}
+	return 0
+}
+
+func (m *ContinuousQueryInfo) GetLastDuration() int64 {
+	if m != nil && m.LastDuration != nil {
+		return *m.LastDuration
+	}
+	return 0
+}
+
+func (m *ContinuousQueryInfo) GetLastPointsWritten() int64 {
+	if m != nil && m.LastPointsWritten != nil {
+		return *m.LastPointsWritten
+	}
+	return 0
+}
+
+func (m *ContinuousQueryInfo) GetLastSuccessStart() int64 {
+	if m != nil && m.LastSuccessStart != nil {
+		return *m.LastSuccessStart
+	}
+	return 0
+}
+
+func (m *ContinuousQueryInfo) GetLastSuccessEnd() int64 {
+	if m != nil && m.LastSuccessEnd != nil {
+		return *m.LastSuccessEnd
+	}
+	return 0
+}
+
+func (m *ContinuousQueryInfo) GetLastError() string {
+	if m != nil && m.LastError != nil {
+		return *m.LastError
+	}
+	return ""
+}
+
+func (m *ContinuousQueryInfo) GetLastErrorTime() int64 {
+	if m != nil && m.LastErrorTime != nil {
+		return *m.LastErrorTime
+	}
+	return 0
+}
+
 type UserInfo struct {
 	Name             *string          `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Hash             *string          `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
@@ -631,7 +919,7 @@ type UserInfo struct {
 func (m *UserInfo) Reset()                    { *m = UserInfo{} }
 func (m *UserInfo) String() string            { return proto.CompactTextString(m) }
 func (*UserInfo) ProtoMessage()               {}
-func (*UserInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{10} }
+func (*UserInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{11} }
 
 func (m *UserInfo) GetName() string {
 	if m != nil && m.Name != nil {
@@ -670,7 +958,7 @@ type UserPrivilege struct {
 func (m *UserPrivilege) Reset()                    { *m = UserPrivilege{} }
 func (m *UserPrivilege) String() string            { return proto.CompactTextString(m) }
 func (*UserPrivilege) ProtoMessage()               {}
-func (*UserPrivilege) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{11} }
+func (*UserPrivilege) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{12} }
 
 func (m *UserPrivilege) GetDatabase() string {
 	if m != nil && m.Database != nil {
@@ -686,6 +974,112 @@ func (m *UserPrivilege) GetPrivilege() int32 {
 	return 0
 }
 
+type DroppedInfo struct {
+	Type             *string             `protobuf:"bytes,1,req,name=Type" json:"Type,omitempty"`
+	Database         *string             `protobuf:"bytes,2,req,name=Database" json:"Database,omitempty"`
+	Name             *string             `protobuf:"bytes,3,opt,name=Name" json:"Name,omitempty"`
+	ShardID          *uint64             `protobuf:"varint,4,opt,name=ShardID" json:"ShardID,omitempty"`
+	DroppedAt        *int64              `protobuf:"varint,5,req,name=DroppedAt" json:"DroppedAt,omitempty"`
+	DatabaseInfo     *DatabaseInfo       `protobuf:"bytes,6,opt,name=DatabaseInfo" json:"DatabaseInfo,omitempty"`
+	Privileges       []*DroppedPrivilege `protobuf:"bytes,7,rep,name=Privileges" json:"Privileges,omitempty"`
+	RetentionPolicy  *string             `protobuf:"bytes,8,opt,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
+	ShardGroup       *ShardGroupInfo     `protobuf:"bytes,9,opt,name=ShardGroup" json:"ShardGroup,omitempty"`
+	XXX_unrecognized []byte              `json:"-"`
+}
+
+func (m *DroppedInfo) Reset()                    { *m = DroppedInfo{} }
+func (m *DroppedInfo) String() string            { return proto.CompactTextString(m) }
+func (*DroppedInfo) ProtoMessage()               {}
+func (*DroppedInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{13} }
+
+func (m *DroppedInfo) GetType() string {
+	if m != nil && m.Type != nil {
+		return *m.Type
+	}
+	return ""
+}
+
+func (m *DroppedInfo) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *DroppedInfo) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *DroppedInfo) GetShardID() uint64 {
+	if m != nil && m.ShardID != nil {
+		return *m.ShardID
+	}
+	return 0
+}
+
+func (m *DroppedInfo) GetDroppedAt() int64 {
+	if m != nil && m.DroppedAt != nil {
+		return *m.DroppedAt
+	}
+	return 0
+}
+
+func (m *DroppedInfo) GetDatabaseInfo() *DatabaseInfo {
+	if m != nil {
+		return m.DatabaseInfo
+	}
+	return nil
+}
+
+func (m *DroppedInfo) GetPrivileges() []*DroppedPrivilege {
+	if m != nil {
+		return m.Privileges
+	}
+	return nil
+}
+
+func (m *DroppedInfo) GetRetentionPolicy() string {
+	if m != nil && m.RetentionPolicy != nil {
+		return *m.RetentionPolicy
+	}
+	return ""
+}
+
+func (m *DroppedInfo) GetShardGroup() *ShardGroupInfo {
+	if m != nil {
+		return m.ShardGroup
+	}
+	return nil
+}
+
+type DroppedPrivilege struct {
+	User             *string `protobuf:"bytes,1,req,name=User" json:"User,omitempty"`
+	Privilege        *int32  `protobuf:"varint,2,req,name=Privilege" json:"Privilege,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *DroppedPrivilege) Reset()                    { *m = DroppedPrivilege{} }
+func (m *DroppedPrivilege) String() string            { return proto.CompactTextString(m) }
+func (*DroppedPrivilege) ProtoMessage()               {}
+func (*DroppedPrivilege) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{14} }
+
+func (m *DroppedPrivilege) GetUser() string {
+	if m != nil && m.User != nil {
+		return *m.User
+	}
+	return ""
+}
+
+func (m *DroppedPrivilege) GetPrivilege() int32 {
+	if m != nil && m.Privilege != nil {
+		return *m.Privilege
+	}
+	return 0
+}
+
 type Command struct {
 	Type                         *Command_Type `protobuf:"varint,1,req,name=type,enum=internal.Command_Type" json:"type,omitempty"`
 	proto.XXX_InternalExtensions `json:"-"`
@@ -695,7 +1089,7 @@ type Command struct {
 func (m *Command) Reset()                    { *m = Command{} }
 func (m *Command) String() string            { return proto.CompactTextString(m) }
 func (*Command) ProtoMessage()               {}
-func (*Command) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{12} }
+func (*Command) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{15} }
 
 var extRange_Command = []proto.ExtensionRange{
 	{Start: 100, End: 536870911},
@@ -723,7 +1117,7 @@ type CreateNodeCommand struct {
 func (m *CreateNodeCommand) Reset()                    { *m = CreateNodeCommand{} }
 func (m *CreateNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateNodeCommand) ProtoMessage()               {}
-func (*CreateNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{13} }
+func (*CreateNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{16} }
 
 func (m *CreateNodeCommand) GetHost() string {
 	if m != nil && m.Host != nil {
@@ -757,7 +1151,7 @@ type DeleteNodeCommand struct {
 func (m *DeleteNodeCommand) Reset()                    { *m = DeleteNodeCommand{} }
 func (m *DeleteNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*DeleteNodeCommand) ProtoMessage()               {}
-func (*DeleteNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{14} }
+func (*DeleteNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{17} }
 
 func (m *DeleteNodeCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -791,7 +1185,7 @@ type CreateDatabaseCommand struct {
 func (m *CreateDatabaseCommand) Reset()                    { *m = CreateDatabaseCommand{} }
 func (m *CreateDatabaseCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateDatabaseCommand) ProtoMessage()               {}
-func (*CreateDatabaseCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{15} }
+func (*CreateDatabaseCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{18} }
 
 func (m *CreateDatabaseCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -824,7 +1218,7 @@ type DropDatabaseCommand struct {
 func (m *DropDatabaseCommand) Reset()                    { *m = DropDatabaseCommand{} }
 func (m *DropDatabaseCommand) String() string            { return proto.CompactTextString(m) }
 func (*DropDatabaseCommand) ProtoMessage()               {}
-func (*DropDatabaseCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{16} }
+func (*DropDatabaseCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{19} }
 
 func (m *DropDatabaseCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -852,7 +1246,7 @@ func (m *CreateRetentionPolicyCommand) Reset()         { *m = CreateRetentionPol
 func (m *CreateRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateRetentionPolicyCommand) ProtoMessage()    {}
 func (*CreateRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptorMeta, []int{17}
+	return fileDescriptorMeta, []int{20}
 }
 
 func (m *CreateRetentionPolicyCommand) GetDatabase() string {
@@ -887,7 +1281,7 @@ type DropRetentionPolicyCommand struct {
 func (m *DropRetentionPolicyCommand) Reset()                    { *m = DropRetentionPolicyCommand{} }
 func (m *DropRetentionPolicyCommand) String() string            { return proto.CompactTextString(m) }
 func (*DropRetentionPolicyCommand) ProtoMessage()               {}
-func (*DropRetentionPolicyCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{18} }
+func (*DropRetentionPolicyCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{21} }
 
 func (m *DropRetentionPolicyCommand) GetDatabase() string {
 	if m != nil && m.Database != nil {
@@ -922,7 +1316,7 @@ func (m *SetDefaultRetentionPolicyCommand) Reset()         { *m = SetDefaultRete
 func (m *SetDefaultRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*SetDefaultRetentionPolicyCommand) ProtoMessage()    {}
 func (*SetDefaultRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptorMeta, []int{19}
+	return fileDescriptorMeta, []int{22}
 }
 
 func (m *SetDefaultRetentionPolicyCommand) GetDatabase() string {
@@ -949,19 +1343,25 @@ var E_SetDefaultRetentionPolicyCommand_Command = &proto.ExtensionDesc{
 }
 
 type UpdateRetentionPolicyCommand struct {
-	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
-	Name             *string `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
-	NewName          *string `protobuf:"bytes,3,opt,name=NewName" json:"NewName,omitempty"`
-	Duration         *int64  `protobuf:"varint,4,opt,name=Duration" json:"Duration,omitempty"`
-	ReplicaN         *uint32 `protobuf:"varint,5,opt,name=ReplicaN" json:"ReplicaN,omitempty"`
-	XXX_unrecognized []byte  `json:"-"`
+	Database           *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Name               *string `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+	NewName            *string `protobuf:"bytes,3,opt,name=NewName" json:"NewName,omitempty"`
+	Duration           *int64  `protobuf:"varint,4,opt,name=Duration" json:"Duration,omitempty"`
+	ReplicaN           *uint32 `protobuf:"varint,5,opt,name=ReplicaN" json:"ReplicaN,omitempty"`
+	Consistency        *string `protobuf:"bytes,6,opt,name=Consistency" json:"Consistency,omitempty"`
+	ColdAfter          *int64  `protobuf:"varint,7,opt,name=ColdAfter" json:"ColdAfter,omitempty"`
+	DuplicatePolicy    *string `protobuf:"bytes,8,opt,name=DuplicatePolicy" json:"DuplicatePolicy,omitempty"`
+	ShardGroupDuration *int64  `protobuf:"varint,9,opt,name=ShardGroupDuration" json:"ShardGroupDuration,omitempty"`
+	StringCodec        *string `protobuf:"bytes,10,opt,name=StringCodec" json:"StringCodec,omitempty"`
+	FloatCodec         *string `protobuf:"bytes,11,opt,name=FloatCodec" json:"FloatCodec,omitempty"`
+	XXX_unrecognized   []byte  `json:"-"`
 }
 
 func (m *UpdateRetentionPolicyCommand) Reset()         { *m = UpdateRetentionPolicyCommand{} }
 func (m *UpdateRetentionPolicyCommand) String() string { return proto.CompactTextString(m) }
 func (*UpdateRetentionPolicyCommand) ProtoMessage()    {}
 func (*UpdateRetentionPolicyCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptorMeta, []int{20}
+	return fileDescriptorMeta, []int{23}
 }
 
 func (m *UpdateRetentionPolicyCommand) GetDatabase() string {
@@ -999,6 +1399,48 @@ func (m *UpdateRetentionPolicyCommand) GetReplicaN() uint32 {
 	return 0
 }
 
+func (m *UpdateRetentionPolicyCommand) GetConsistency() string {
+	if m != nil && m.Consistency != nil {
+		return *m.Consistency
+	}
+	return ""
+}
+
+func (m *UpdateRetentionPolicyCommand) GetColdAfter() int64 {
+	if m != nil && m.ColdAfter != nil {
+		return *m.ColdAfter
+	}
+	return 0
+}
+
+func (m *UpdateRetentionPolicyCommand) GetDuplicatePolicy() string {
+	if m != nil && m.DuplicatePolicy != nil {
+		return *m.DuplicatePolicy
+	}
+	return ""
+}
+
+func (m *UpdateRetentionPolicyCommand) GetShardGroupDuration() int64 {
+	if m != nil && m.ShardGroupDuration != nil {
+		return *m.ShardGroupDuration
+	}
+	return 0
+}
+
+func (m *UpdateRetentionPolicyCommand) GetStringCodec() string {
+	if m != nil && m.StringCodec != nil {
+		return *m.StringCodec
+	}
+	return ""
+}
+
+func (m *UpdateRetentionPolicyCommand) GetFloatCodec() string {
+	if m != nil && m.FloatCodec != nil {
+		return *m.FloatCodec
+	}
+	return ""
+}
+
 var E_UpdateRetentionPolicyCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*UpdateRetentionPolicyCommand)(nil),
@@ -1018,7 +1460,7 @@ type CreateShardGroupCommand struct {
 func (m *CreateShardGroupCommand) Reset()                    { *m = CreateShardGroupCommand{} }
 func (m *CreateShardGroupCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateShardGroupCommand) ProtoMessage()               {}
-func (*CreateShardGroupCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{21} }
+func (*CreateShardGroupCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{24} }
 
 func (m *CreateShardGroupCommand) GetDatabase() string {
 	if m != nil && m.Database != nil {
@@ -1054,13 +1496,14 @@ type DeleteShardGroupCommand struct {
 	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
 	Policy           *string `protobuf:"bytes,2,req,name=Policy" json:"Policy,omitempty"`
 	ShardGroupID     *uint64 `protobuf:"varint,3,req,name=ShardGroupID" json:"ShardGroupID,omitempty"`
+	PurgeAt          *int64  `protobuf:"varint,4,opt,name=PurgeAt" json:"PurgeAt,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
 func (m *DeleteShardGroupCommand) Reset()                    { *m = DeleteShardGroupCommand{} }
 func (m *DeleteShardGroupCommand) String() string            { return proto.CompactTextString(m) }
 func (*DeleteShardGroupCommand) ProtoMessage()               {}
-func (*DeleteShardGroupCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{22} }
+func (*DeleteShardGroupCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{25} }
 
 func (m *DeleteShardGroupCommand) GetDatabase() string {
 	if m != nil && m.Database != nil {
@@ -1083,6 +1526,13 @@ func (m *DeleteShardGroupCommand) GetShardGroupID() uint64 {
 	return 0
 }
 
+func (m *DeleteShardGroupCommand) GetPurgeAt() int64 {
+	if m != nil && m.PurgeAt != nil {
+		return *m.PurgeAt
+	}
+	return 0
+}
+
 var E_DeleteShardGroupCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
 	ExtensionType: (*DeleteShardGroupCommand)(nil),
@@ -1103,7 +1553,7 @@ func (m *CreateContinuousQueryCommand) Reset()         { *m = CreateContinuousQu
 func (m *CreateContinuousQueryCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateContinuousQueryCommand) ProtoMessage()    {}
 func (*CreateContinuousQueryCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptorMeta, []int{23}
+	return fileDescriptorMeta, []int{26}
 }
 
 func (m *CreateContinuousQueryCommand) GetDatabase() string {
@@ -1145,7 +1595,7 @@ type DropContinuousQueryCommand struct {
 func (m *DropContinuousQueryCommand) Reset()                    { *m = DropContinuousQueryCommand{} }
 func (m *DropContinuousQueryCommand) String() string            { return proto.CompactTextString(m) }
 func (*DropContinuousQueryCommand) ProtoMessage()               {}
-func (*DropContinuousQueryCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{24} }
+func (*DropContinuousQueryCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{27} }
 
 func (m *DropContinuousQueryCommand) GetDatabase() string {
 	if m != nil && m.Database != nil {
@@ -1180,7 +1630,7 @@ type CreateUserCommand struct {
 func (m *CreateUserCommand) Reset()                    { *m = CreateUserCommand{} }
 func (m *CreateUserCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateUserCommand) ProtoMessage()               {}
-func (*CreateUserCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{25} }
+func (*CreateUserCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{28} }
 
 func (m *CreateUserCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -1220,7 +1670,7 @@ type DropUserCommand struct {
 func (m *DropUserCommand) Reset()                    { *m = DropUserCommand{} }
 func (m *DropUserCommand) String() string            { return proto.CompactTextString(m) }
 func (*DropUserCommand) ProtoMessage()               {}
-func (*DropUserCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{26} }
+func (*DropUserCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{29} }
 
 func (m *DropUserCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -1247,7 +1697,7 @@ type UpdateUserCommand struct {
 func (m *UpdateUserCommand) Reset()                    { *m = UpdateUserCommand{} }
 func (m *UpdateUserCommand) String() string            { return proto.CompactTextString(m) }
 func (*UpdateUserCommand) ProtoMessage()               {}
-func (*UpdateUserCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{27} }
+func (*UpdateUserCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{30} }
 
 func (m *UpdateUserCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -1282,7 +1732,7 @@ type SetPrivilegeCommand struct {
 func (m *SetPrivilegeCommand) Reset()                    { *m = SetPrivilegeCommand{} }
 func (m *SetPrivilegeCommand) String() string            { return proto.CompactTextString(m) }
 func (*SetPrivilegeCommand) ProtoMessage()               {}
-func (*SetPrivilegeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{28} }
+func (*SetPrivilegeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{31} }
 
 func (m *SetPrivilegeCommand) GetUsername() string {
 	if m != nil && m.Username != nil {
@@ -1322,7 +1772,7 @@ type SetDataCommand struct {
 func (m *SetDataCommand) Reset()                    { *m = SetDataCommand{} }
 func (m *SetDataCommand) String() string            { return proto.CompactTextString(m) }
 func (*SetDataCommand) ProtoMessage()               {}
-func (*SetDataCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{29} }
+func (*SetDataCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{32} }
 
 func (m *SetDataCommand) GetData() *Data {
 	if m != nil {
@@ -1349,7 +1799,7 @@ type SetAdminPrivilegeCommand struct {
 func (m *SetAdminPrivilegeCommand) Reset()                    { *m = SetAdminPrivilegeCommand{} }
 func (m *SetAdminPrivilegeCommand) String() string            { return proto.CompactTextString(m) }
 func (*SetAdminPrivilegeCommand) ProtoMessage()               {}
-func (*SetAdminPrivilegeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{30} }
+func (*SetAdminPrivilegeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{33} }
 
 func (m *SetAdminPrivilegeCommand) GetUsername() string {
 	if m != nil && m.Username != nil {
@@ -1383,7 +1833,7 @@ type UpdateNodeCommand struct {
 func (m *UpdateNodeCommand) Reset()                    { *m = UpdateNodeCommand{} }
 func (m *UpdateNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*UpdateNodeCommand) ProtoMessage()               {}
-func (*UpdateNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{31} }
+func (*UpdateNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{34} }
 
 func (m *UpdateNodeCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1420,7 +1870,7 @@ type CreateSubscriptionCommand struct {
 func (m *CreateSubscriptionCommand) Reset()                    { *m = CreateSubscriptionCommand{} }
 func (m *CreateSubscriptionCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateSubscriptionCommand) ProtoMessage()               {}
-func (*CreateSubscriptionCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{32} }
+func (*CreateSubscriptionCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{35} }
 
 func (m *CreateSubscriptionCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -1476,7 +1926,7 @@ type DropSubscriptionCommand struct {
 func (m *DropSubscriptionCommand) Reset()                    { *m = DropSubscriptionCommand{} }
 func (m *DropSubscriptionCommand) String() string            { return proto.CompactTextString(m) }
 func (*DropSubscriptionCommand) ProtoMessage()               {}
-func (*DropSubscriptionCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{33} }
+func (*DropSubscriptionCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{36} }
 
 func (m *DropSubscriptionCommand) GetName() string {
 	if m != nil && m.Name != nil {
@@ -1517,7 +1967,7 @@ type RemovePeerCommand struct {
 func (m *RemovePeerCommand) Reset()                    { *m = RemovePeerCommand{} }
 func (m *RemovePeerCommand) String() string            { return proto.CompactTextString(m) }
 func (*RemovePeerCommand) ProtoMessage()               {}
-func (*RemovePeerCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{34} }
+func (*RemovePeerCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{37} }
 
 func (m *RemovePeerCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1552,7 +2002,7 @@ type CreateMetaNodeCommand struct {
 func (m *CreateMetaNodeCommand) Reset()                    { *m = CreateMetaNodeCommand{} }
 func (m *CreateMetaNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateMetaNodeCommand) ProtoMessage()               {}
-func (*CreateMetaNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{35} }
+func (*CreateMetaNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{38} }
 
 func (m *CreateMetaNodeCommand) GetHTTPAddr() string {
 	if m != nil && m.HTTPAddr != nil {
@@ -1593,7 +2043,7 @@ type CreateDataNodeCommand struct {
 func (m *CreateDataNodeCommand) Reset()                    { *m = CreateDataNodeCommand{} }
 func (m *CreateDataNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*CreateDataNodeCommand) ProtoMessage()               {}
-func (*CreateDataNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{36} }
+func (*CreateDataNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{39} }
 
 func (m *CreateDataNodeCommand) GetHTTPAddr() string {
 	if m != nil && m.HTTPAddr != nil {
@@ -1628,7 +2078,7 @@ type UpdateDataNodeCommand struct {
 func (m *UpdateDataNodeCommand) Reset()                    { *m = UpdateDataNodeCommand{} }
 func (m *UpdateDataNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*UpdateDataNodeCommand) ProtoMessage()               {}
-func (*UpdateDataNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{37} }
+func (*UpdateDataNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{40} }
 
 func (m *UpdateDataNodeCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1668,7 +2118,7 @@ type DeleteMetaNodeCommand struct {
 func (m *DeleteMetaNodeCommand) Reset()                    { *m = DeleteMetaNodeCommand{} }
 func (m *DeleteMetaNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*DeleteMetaNodeCommand) ProtoMessage()               {}
-func (*DeleteMetaNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{38} }
+func (*DeleteMetaNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{41} }
 
 func (m *DeleteMetaNodeCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1694,7 +2144,7 @@ type DeleteDataNodeCommand struct {
 func (m *DeleteDataNodeCommand) Reset()                    { *m = DeleteDataNodeCommand{} }
 func (m *DeleteDataNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*DeleteDataNodeCommand) ProtoMessage()               {}
-func (*DeleteDataNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{39} }
+func (*DeleteDataNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{42} }
 
 func (m *DeleteDataNodeCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1722,7 +2172,7 @@ type Response struct {
 func (m *Response) Reset()                    { *m = Response{} }
 func (m *Response) String() string            { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()               {}
-func (*Response) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{40} }
+func (*Response) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{43} }
 
 func (m *Response) GetOK() bool {
 	if m != nil && m.OK != nil {
@@ -1757,7 +2207,7 @@ type SetMetaNodeCommand struct {
 func (m *SetMetaNodeCommand) Reset()                    { *m = SetMetaNodeCommand{} }
 func (m *SetMetaNodeCommand) String() string            { return proto.CompactTextString(m) }
 func (*SetMetaNodeCommand) ProtoMessage()               {}
-func (*SetMetaNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{41} }
+func (*SetMetaNodeCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{44} }
 
 func (m *SetMetaNodeCommand) GetHTTPAddr() string {
 	if m != nil && m.HTTPAddr != nil {
@@ -1797,7 +2247,7 @@ type DropShardCommand struct {
 func (m *DropShardCommand) Reset()                    { *m = DropShardCommand{} }
 func (m *DropShardCommand) String() string            { return proto.CompactTextString(m) }
 func (*DropShardCommand) ProtoMessage()               {}
-func (*DropShardCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{42} }
+func (*DropShardCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{45} }
 
 func (m *DropShardCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
@@ -1815,12 +2265,759 @@ var E_DropShardCommand_Command = &proto.ExtensionDesc{
 	Filename:      "internal/meta.proto",
 }
 
+type AddShardOwnerCommand struct {
+	ID               *uint64 `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	NodeID           *uint64 `protobuf:"varint,2,req,name=NodeID" json:"NodeID,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *AddShardOwnerCommand) Reset()                    { *m = AddShardOwnerCommand{} }
+func (m *AddShardOwnerCommand) String() string            { return proto.CompactTextString(m) }
+func (*AddShardOwnerCommand) ProtoMessage()               {}
+func (*AddShardOwnerCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{46} }
+
+func (m *AddShardOwnerCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+func (m *AddShardOwnerCommand) GetNodeID() uint64 {
+	if m != nil && m.NodeID != nil {
+		return *m.NodeID
+	}
+	return 0
+}
+
+var E_AddShardOwnerCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*AddShardOwnerCommand)(nil),
+	Field:         131,
+	Name:          "internal.AddShardOwnerCommand.command",
+	Tag:           "bytes,131,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type RemoveShardOwnerCommand struct {
+	ID               *uint64 `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	NodeID           *uint64 `protobuf:"varint,2,req,name=NodeID" json:"NodeID,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *RemoveShardOwnerCommand) Reset()                    { *m = RemoveShardOwnerCommand{} }
+func (m *RemoveShardOwnerCommand) String() string            { return proto.CompactTextString(m) }
+func (*RemoveShardOwnerCommand) ProtoMessage()               {}
+func (*RemoveShardOwnerCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{47} }
+
+func (m *RemoveShardOwnerCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+func (m *RemoveShardOwnerCommand) GetNodeID() uint64 {
+	if m != nil && m.NodeID != nil {
+		return *m.NodeID
+	}
+	return 0
+}
+
+var E_RemoveShardOwnerCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*RemoveShardOwnerCommand)(nil),
+	Field:         132,
+	Name:          "internal.RemoveShardOwnerCommand.command",
+	Tag:           "bytes,132,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetDataNodeStatusCommand struct {
+	ID               *uint64 `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	Status           *string `protobuf:"bytes,2,req,name=Status" json:"Status,omitempty"`
+	LastSeen         *int64  `protobuf:"varint,3,opt,name=LastSeen" json:"LastSeen,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SetDataNodeStatusCommand) Reset()                    { *m = SetDataNodeStatusCommand{} }
+func (m *SetDataNodeStatusCommand) String() string            { return proto.CompactTextString(m) }
+func (*SetDataNodeStatusCommand) ProtoMessage()               {}
+func (*SetDataNodeStatusCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{48} }
+
+func (m *SetDataNodeStatusCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+func (m *SetDataNodeStatusCommand) GetStatus() string {
+	if m != nil && m.Status != nil {
+		return *m.Status
+	}
+	return ""
+}
+
+func (m *SetDataNodeStatusCommand) GetLastSeen() int64 {
+	if m != nil && m.LastSeen != nil {
+		return *m.LastSeen
+	}
+	return 0
+}
+
+var E_SetDataNodeStatusCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetDataNodeStatusCommand)(nil),
+	Field:         134,
+	Name:          "internal.SetDataNodeStatusCommand.command",
+	Tag:           "bytes,134,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetDatabaseConsistencyCommand struct {
+	Name             *string `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Consistency      *string `protobuf:"bytes,2,req,name=Consistency" json:"Consistency,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SetDatabaseConsistencyCommand) Reset()         { *m = SetDatabaseConsistencyCommand{} }
+func (m *SetDatabaseConsistencyCommand) String() string { return proto.CompactTextString(m) }
+func (*SetDatabaseConsistencyCommand) ProtoMessage()    {}
+func (*SetDatabaseConsistencyCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptorMeta, []int{49}
+}
+
+func (m *SetDatabaseConsistencyCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *SetDatabaseConsistencyCommand) GetConsistency() string {
+	if m != nil && m.Consistency != nil {
+		return *m.Consistency
+	}
+	return ""
+}
+
+var E_SetDatabaseConsistencyCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetDatabaseConsistencyCommand)(nil),
+	Field:         135,
+	Name:          "internal.SetDatabaseConsistencyCommand.command",
+	Tag:           "bytes,135,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetContinuousQueryLastRunCommand struct {
+	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Name             *string `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+	LastRun          *int64  `protobuf:"varint,3,req,name=LastRun" json:"LastRun,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SetContinuousQueryLastRunCommand) Reset()         { *m = SetContinuousQueryLastRunCommand{} }
+func (m *SetContinuousQueryLastRunCommand) String() string { return proto.CompactTextString(m) }
+func (*SetContinuousQueryLastRunCommand) ProtoMessage()    {}
+func (*SetContinuousQueryLastRunCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptorMeta, []int{50}
+}
+
+func (m *SetContinuousQueryLastRunCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SetContinuousQueryLastRunCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *SetContinuousQueryLastRunCommand) GetLastRun() int64 {
+	if m != nil && m.LastRun != nil {
+		return *m.LastRun
+	}
+	return 0
+}
+
+var E_SetContinuousQueryLastRunCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetContinuousQueryLastRunCommand)(nil),
+	Field:         136,
+	Name:          "internal.SetContinuousQueryLastRunCommand.command",
+	Tag:           "bytes,136,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetContinuousQueryStatusCommand struct {
+	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Name             *string `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+	NodeID           *uint64 `protobuf:"varint,3,req,name=NodeID" json:"NodeID,omitempty"`
+	Time             *int64  `protobuf:"varint,4,req,name=Time" json:"Time,omitempty"`
+	Duration         *int64  `protobuf:"varint,5,req,name=Duration" json:"Duration,omitempty"`
+	PointsWritten    *int64  `protobuf:"varint,6,req,name=PointsWritten" json:"PointsWritten,omitempty"`
+	StartTime        *int64  `protobuf:"varint,7,req,name=StartTime" json:"StartTime,omitempty"`
+	EndTime          *int64  `protobuf:"varint,8,req,name=EndTime" json:"EndTime,omitempty"`
+	Error            *string `protobuf:"bytes,9,opt,name=Error" json:"Error,omitempty"`
+	LastRun          *int64  `protobuf:"varint,10,opt,name=LastRun" json:"LastRun,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SetContinuousQueryStatusCommand) Reset()         { *m = SetContinuousQueryStatusCommand{} }
+func (m *SetContinuousQueryStatusCommand) String() string { return proto.CompactTextString(m) }
+func (*SetContinuousQueryStatusCommand) ProtoMessage()    {}
+func (*SetContinuousQueryStatusCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptorMeta, []int{51}
+}
+
+func (m *SetContinuousQueryStatusCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SetContinuousQueryStatusCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *SetContinuousQueryStatusCommand) GetNodeID() uint64 {
+	if m != nil && m.NodeID != nil {
+		return *m.NodeID
+	}
+	return 0
+}
+
+func (m *SetContinuousQueryStatusCommand) GetTime() int64 {
+	if m != nil && m.Time != nil {
+		return *m.Time
+	}
+	return 0
+}
+
+func (m *SetContinuousQueryStatusCommand) GetDuration() int64 {
+	if m != nil && m.Duration != nil {
+		return *m.Duration
+	}
+	return 0
+}
+
+func (m *SetContinuousQueryStatusCommand) GetPointsWritten() int64 {
+	if m != nil && m.PointsWritten != nil {
+		return *m.PointsWritten
+	}
+	return 0
+}
+
+func (m *SetContinuousQueryStatusCommand) GetStartTime() int64 {
+	if m != nil && m.StartTime != nil {
+		return *m.StartTime
+	}
+	return 0
+}
+
+func (m *SetContinuousQueryStatusCommand) GetEndTime() int64 {
+	if m != nil && m.EndTime != nil {
+		return *m.EndTime
+	}
+	return 0
+}
+
+func (m *SetContinuousQueryStatusCommand) GetError() string {
+	if m != nil && m.Error != nil {
+		return *m.Error
+	}
+	return ""
+}
+
+func (m *SetContinuousQueryStatusCommand) GetLastRun() int64 {
+	if m != nil && m.LastRun != nil {
+		return *m.LastRun
+	}
+	return 0
+}
+
+var E_SetContinuousQueryStatusCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetContinuousQueryStatusCommand)(nil),
+	Field:         137,
+	Name:          "internal.SetContinuousQueryStatusCommand.command",
+	Tag:           "bytes,137,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type CreateDownsampleCommand struct {
+	Database         *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	RetentionPolicy  *string  `protobuf:"bytes,2,req,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
+	Target           *string  `protobuf:"bytes,3,req,name=Target" json:"Target,omitempty"`
+	Interval         *int64   `protobuf:"varint,4,req,name=Interval" json:"Interval,omitempty"`
+	Aggregates       []string `protobuf:"bytes,5,rep,name=Aggregates" json:"Aggregates,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *CreateDownsampleCommand) Reset()                    { *m = CreateDownsampleCommand{} }
+func (m *CreateDownsampleCommand) String() string            { return proto.CompactTextString(m) }
+func (*CreateDownsampleCommand) ProtoMessage()               {}
+func (*CreateDownsampleCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{52} }
+
+func (m *CreateDownsampleCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *CreateDownsampleCommand) GetRetentionPolicy() string {
+	if m != nil && m.RetentionPolicy != nil {
+		return *m.RetentionPolicy
+	}
+	return ""
+}
+
+func (m *CreateDownsampleCommand) GetTarget() string {
+	if m != nil && m.Target != nil {
+		return *m.Target
+	}
+	return ""
+}
+
+func (m *CreateDownsampleCommand) GetInterval() int64 {
+	if m != nil && m.Interval != nil {
+		return *m.Interval
+	}
+	return 0
+}
+
+func (m *CreateDownsampleCommand) GetAggregates() []string {
+	if m != nil {
+		return m.Aggregates
+	}
+	return nil
+}
+
+var E_CreateDownsampleCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*CreateDownsampleCommand)(nil),
+	Field:         138,
+	Name:          "internal.CreateDownsampleCommand.command",
+	Tag:           "bytes,138,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type DropDownsampleCommand struct {
+	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	RetentionPolicy  *string `protobuf:"bytes,2,req,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
+	Target           *string `protobuf:"bytes,3,req,name=Target" json:"Target,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *DropDownsampleCommand) Reset()                    { *m = DropDownsampleCommand{} }
+func (m *DropDownsampleCommand) String() string            { return proto.CompactTextString(m) }
+func (*DropDownsampleCommand) ProtoMessage()               {}
+func (*DropDownsampleCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{53} }
+
+func (m *DropDownsampleCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *DropDownsampleCommand) GetRetentionPolicy() string {
+	if m != nil && m.RetentionPolicy != nil {
+		return *m.RetentionPolicy
+	}
+	return ""
+}
+
+func (m *DropDownsampleCommand) GetTarget() string {
+	if m != nil && m.Target != nil {
+		return *m.Target
+	}
+	return ""
+}
+
+var E_DropDownsampleCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*DropDownsampleCommand)(nil),
+	Field:         139,
+	Name:          "internal.DropDownsampleCommand.command",
+	Tag:           "bytes,139,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetDownsampleLastRunCommand struct {
+	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	RetentionPolicy  *string `protobuf:"bytes,2,req,name=RetentionPolicy" json:"RetentionPolicy,omitempty"`
+	Target           *string `protobuf:"bytes,3,req,name=Target" json:"Target,omitempty"`
+	LastRun          *int64  `protobuf:"varint,4,req,name=LastRun" json:"LastRun,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SetDownsampleLastRunCommand) Reset()         { *m = SetDownsampleLastRunCommand{} }
+func (m *SetDownsampleLastRunCommand) String() string { return proto.CompactTextString(m) }
+func (*SetDownsampleLastRunCommand) ProtoMessage()    {}
+func (*SetDownsampleLastRunCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptorMeta, []int{54}
+}
+
+func (m *SetDownsampleLastRunCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SetDownsampleLastRunCommand) GetRetentionPolicy() string {
+	if m != nil && m.RetentionPolicy != nil {
+		return *m.RetentionPolicy
+	}
+	return ""
+}
+
+func (m *SetDownsampleLastRunCommand) GetTarget() string {
+	if m != nil && m.Target != nil {
+		return *m.Target
+	}
+	return ""
+}
+
+func (m *SetDownsampleLastRunCommand) GetLastRun() int64 {
+	if m != nil && m.LastRun != nil {
+		return *m.LastRun
+	}
+	return 0
+}
+
+var E_SetDownsampleLastRunCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetDownsampleLastRunCommand)(nil),
+	Field:         140,
+	Name:          "internal.SetDownsampleLastRunCommand.command",
+	Tag:           "bytes,140,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetShardOwnerTierCommand struct {
+	ID               *uint64 `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	NodeID           *uint64 `protobuf:"varint,2,req,name=NodeID" json:"NodeID,omitempty"`
+	Tier             *string `protobuf:"bytes,3,req,name=Tier" json:"Tier,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SetShardOwnerTierCommand) Reset()                    { *m = SetShardOwnerTierCommand{} }
+func (m *SetShardOwnerTierCommand) String() string            { return proto.CompactTextString(m) }
+func (*SetShardOwnerTierCommand) ProtoMessage()               {}
+func (*SetShardOwnerTierCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{55} }
+
+func (m *SetShardOwnerTierCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+func (m *SetShardOwnerTierCommand) GetNodeID() uint64 {
+	if m != nil && m.NodeID != nil {
+		return *m.NodeID
+	}
+	return 0
+}
+
+func (m *SetShardOwnerTierCommand) GetTier() string {
+	if m != nil && m.Tier != nil {
+		return *m.Tier
+	}
+	return ""
+}
+
+var E_SetShardOwnerTierCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetShardOwnerTierCommand)(nil),
+	Field:         141,
+	Name:          "internal.SetShardOwnerTierCommand.command",
+	Tag:           "bytes,141,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type ArchiveShardCommand struct {
+	ID               *uint64 `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	ArchivedAt       *int64  `protobuf:"varint,2,req,name=ArchivedAt" json:"ArchivedAt,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *ArchiveShardCommand) Reset()                    { *m = ArchiveShardCommand{} }
+func (m *ArchiveShardCommand) String() string            { return proto.CompactTextString(m) }
+func (*ArchiveShardCommand) ProtoMessage()               {}
+func (*ArchiveShardCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{56} }
+
+func (m *ArchiveShardCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+func (m *ArchiveShardCommand) GetArchivedAt() int64 {
+	if m != nil && m.ArchivedAt != nil {
+		return *m.ArchivedAt
+	}
+	return 0
+}
+
+var E_ArchiveShardCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*ArchiveShardCommand)(nil),
+	Field:         142,
+	Name:          "internal.ArchiveShardCommand.command",
+	Tag:           "bytes,142,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type UnarchiveShardCommand struct {
+	ID               *uint64 `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	NodeID           *uint64 `protobuf:"varint,2,req,name=NodeID" json:"NodeID,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *UnarchiveShardCommand) Reset()                    { *m = UnarchiveShardCommand{} }
+func (m *UnarchiveShardCommand) String() string            { return proto.CompactTextString(m) }
+func (*UnarchiveShardCommand) ProtoMessage()               {}
+func (*UnarchiveShardCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{57} }
+
+func (m *UnarchiveShardCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+func (m *UnarchiveShardCommand) GetNodeID() uint64 {
+	if m != nil && m.NodeID != nil {
+		return *m.NodeID
+	}
+	return 0
+}
+
+var E_UnarchiveShardCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*UnarchiveShardCommand)(nil),
+	Field:         143,
+	Name:          "internal.UnarchiveShardCommand.command",
+	Tag:           "bytes,143,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type UndropShardGroupCommand struct {
+	ID               *uint64 `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	Timestamp        *int64  `protobuf:"varint,2,req,name=Timestamp" json:"Timestamp,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *UndropShardGroupCommand) Reset()                    { *m = UndropShardGroupCommand{} }
+func (m *UndropShardGroupCommand) String() string            { return proto.CompactTextString(m) }
+func (*UndropShardGroupCommand) ProtoMessage()               {}
+func (*UndropShardGroupCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{58} }
+
+func (m *UndropShardGroupCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+func (m *UndropShardGroupCommand) GetTimestamp() int64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
+}
+
+var E_UndropShardGroupCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*UndropShardGroupCommand)(nil),
+	Field:         144,
+	Name:          "internal.UndropShardGroupCommand.command",
+	Tag:           "bytes,144,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type TrashCommand struct {
+	Type             *string `protobuf:"bytes,1,req,name=Type" json:"Type,omitempty"`
+	Database         *string `protobuf:"bytes,2,req,name=Database" json:"Database,omitempty"`
+	Name             *string `protobuf:"bytes,3,opt,name=Name" json:"Name,omitempty"`
+	ShardID          *uint64 `protobuf:"varint,4,opt,name=ShardID" json:"ShardID,omitempty"`
+	DroppedAt        *int64  `protobuf:"varint,5,req,name=DroppedAt" json:"DroppedAt,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *TrashCommand) Reset()                    { *m = TrashCommand{} }
+func (m *TrashCommand) String() string            { return proto.CompactTextString(m) }
+func (*TrashCommand) ProtoMessage()               {}
+func (*TrashCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{59} }
+
+func (m *TrashCommand) GetType() string {
+	if m != nil && m.Type != nil {
+		return *m.Type
+	}
+	return ""
+}
+
+func (m *TrashCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *TrashCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *TrashCommand) GetShardID() uint64 {
+	if m != nil && m.ShardID != nil {
+		return *m.ShardID
+	}
+	return 0
+}
+
+func (m *TrashCommand) GetDroppedAt() int64 {
+	if m != nil && m.DroppedAt != nil {
+		return *m.DroppedAt
+	}
+	return 0
+}
+
+var E_TrashCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*TrashCommand)(nil),
+	Field:         145,
+	Name:          "internal.TrashCommand.command",
+	Tag:           "bytes,145,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type UndropCommand struct {
+	Type             *string `protobuf:"bytes,1,req,name=Type" json:"Type,omitempty"`
+	Database         *string `protobuf:"bytes,2,req,name=Database" json:"Database,omitempty"`
+	Name             *string `protobuf:"bytes,3,opt,name=Name" json:"Name,omitempty"`
+	ShardID          *uint64 `protobuf:"varint,4,opt,name=ShardID" json:"ShardID,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *UndropCommand) Reset()                    { *m = UndropCommand{} }
+func (m *UndropCommand) String() string            { return proto.CompactTextString(m) }
+func (*UndropCommand) ProtoMessage()               {}
+func (*UndropCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{60} }
+
+func (m *UndropCommand) GetType() string {
+	if m != nil && m.Type != nil {
+		return *m.Type
+	}
+	return ""
+}
+
+func (m *UndropCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *UndropCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *UndropCommand) GetShardID() uint64 {
+	if m != nil && m.ShardID != nil {
+		return *m.ShardID
+	}
+	return 0
+}
+
+var E_UndropCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*UndropCommand)(nil),
+	Field:         146,
+	Name:          "internal.UndropCommand.command",
+	Tag:           "bytes,146,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type PruneDroppedCommand struct {
+	Before           *int64 `protobuf:"varint,1,req,name=Before" json:"Before,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *PruneDroppedCommand) Reset()                    { *m = PruneDroppedCommand{} }
+func (m *PruneDroppedCommand) String() string            { return proto.CompactTextString(m) }
+func (*PruneDroppedCommand) ProtoMessage()               {}
+func (*PruneDroppedCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{61} }
+
+func (m *PruneDroppedCommand) GetBefore() int64 {
+	if m != nil && m.Before != nil {
+		return *m.Before
+	}
+	return 0
+}
+
+var E_PruneDroppedCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*PruneDroppedCommand)(nil),
+	Field:         147,
+	Name:          "internal.PruneDroppedCommand.command",
+	Tag:           "bytes,147,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
+type SetTrashEnabledCommand struct {
+	Enabled          *bool  `protobuf:"varint,1,req,name=Enabled" json:"Enabled,omitempty"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *SetTrashEnabledCommand) Reset()                    { *m = SetTrashEnabledCommand{} }
+func (m *SetTrashEnabledCommand) String() string            { return proto.CompactTextString(m) }
+func (*SetTrashEnabledCommand) ProtoMessage()               {}
+func (*SetTrashEnabledCommand) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{62} }
+
+func (m *SetTrashEnabledCommand) GetEnabled() bool {
+	if m != nil && m.Enabled != nil {
+		return *m.Enabled
+	}
+	return false
+}
+
+var E_SetTrashEnabledCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetTrashEnabledCommand)(nil),
+	Field:         148,
+	Name:          "internal.SetTrashEnabledCommand.command",
+	Tag:           "bytes,148,opt,name=command",
+	Filename:      "internal/meta.proto",
+}
+
 func init() {
 	proto.RegisterType((*Data)(nil), "meta.Data")
 	proto.RegisterType((*NodeInfo)(nil), "meta.NodeInfo")
 	proto.RegisterType((*DatabaseInfo)(nil), "meta.DatabaseInfo")
 	proto.RegisterType((*RetentionPolicySpec)(nil), "meta.RetentionPolicySpec")
 	proto.RegisterType((*RetentionPolicyInfo)(nil), "meta.RetentionPolicyInfo")
+	proto.RegisterType((*DownsampleInfo)(nil), "meta.DownsampleInfo")
 	proto.RegisterType((*ShardGroupInfo)(nil), "meta.ShardGroupInfo")
 	proto.RegisterType((*ShardInfo)(nil), "meta.ShardInfo")
 	proto.RegisterType((*SubscriptionInfo)(nil), "meta.SubscriptionInfo")
@@ -1828,6 +3025,8 @@ func init() {
 	proto.RegisterType((*ContinuousQueryInfo)(nil), "meta.ContinuousQueryInfo")
 	proto.RegisterType((*UserInfo)(nil), "meta.UserInfo")
 	proto.RegisterType((*UserPrivilege)(nil), "meta.UserPrivilege")
+	proto.RegisterType((*DroppedInfo)(nil), "meta.DroppedInfo")
+	proto.RegisterType((*DroppedPrivilege)(nil), "meta.DroppedPrivilege")
 	proto.RegisterType((*Command)(nil), "meta.Command")
 	proto.RegisterType((*CreateNodeCommand)(nil), "meta.CreateNodeCommand")
 	proto.RegisterType((*DeleteNodeCommand)(nil), "meta.DeleteNodeCommand")
@@ -1859,6 +3058,23 @@ func init() {
 	proto.RegisterType((*Response)(nil), "meta.Response")
 	proto.RegisterType((*SetMetaNodeCommand)(nil), "meta.SetMetaNodeCommand")
 	proto.RegisterType((*DropShardCommand)(nil), "meta.DropShardCommand")
+	proto.RegisterType((*AddShardOwnerCommand)(nil), "meta.AddShardOwnerCommand")
+	proto.RegisterType((*RemoveShardOwnerCommand)(nil), "meta.RemoveShardOwnerCommand")
+	proto.RegisterType((*SetDataNodeStatusCommand)(nil), "meta.SetDataNodeStatusCommand")
+	proto.RegisterType((*SetDatabaseConsistencyCommand)(nil), "meta.SetDatabaseConsistencyCommand")
+	proto.RegisterType((*SetContinuousQueryLastRunCommand)(nil), "meta.SetContinuousQueryLastRunCommand")
+	proto.RegisterType((*SetContinuousQueryStatusCommand)(nil), "meta.SetContinuousQueryStatusCommand")
+	proto.RegisterType((*CreateDownsampleCommand)(nil), "meta.CreateDownsampleCommand")
+	proto.RegisterType((*DropDownsampleCommand)(nil), "meta.DropDownsampleCommand")
+	proto.RegisterType((*SetDownsampleLastRunCommand)(nil), "meta.SetDownsampleLastRunCommand")
+	proto.RegisterType((*SetShardOwnerTierCommand)(nil), "meta.SetShardOwnerTierCommand")
+	proto.RegisterType((*ArchiveShardCommand)(nil), "meta.ArchiveShardCommand")
+	proto.RegisterType((*UnarchiveShardCommand)(nil), "meta.UnarchiveShardCommand")
+	proto.RegisterType((*UndropShardGroupCommand)(nil), "meta.UndropShardGroupCommand")
+	proto.RegisterType((*TrashCommand)(nil), "meta.TrashCommand")
+	proto.RegisterType((*UndropCommand)(nil), "meta.UndropCommand")
+	proto.RegisterType((*PruneDroppedCommand)(nil), "meta.PruneDroppedCommand")
+	proto.RegisterType((*SetTrashEnabledCommand)(nil), "meta.SetTrashEnabledCommand")
 	proto.RegisterEnum("meta.Command_Type", Command_Type_name, Command_Type_value)
 	proto.RegisterExtension(E_CreateNodeCommand_Command)
 	proto.RegisterExtension(E_DeleteNodeCommand_Command)
@@ -1889,123 +3105,212 @@ func init() {
 	proto.RegisterExtension(E_DeleteDataNodeCommand_Command)
 	proto.RegisterExtension(E_SetMetaNodeCommand_Command)
 	proto.RegisterExtension(E_DropShardCommand_Command)
+	proto.RegisterExtension(E_AddShardOwnerCommand_Command)
+	proto.RegisterExtension(E_RemoveShardOwnerCommand_Command)
+	proto.RegisterExtension(E_SetDataNodeStatusCommand_Command)
+	proto.RegisterExtension(E_SetDatabaseConsistencyCommand_Command)
+	proto.RegisterExtension(E_SetContinuousQueryLastRunCommand_Command)
+	proto.RegisterExtension(E_SetContinuousQueryStatusCommand_Command)
+	proto.RegisterExtension(E_CreateDownsampleCommand_Command)
+	proto.RegisterExtension(E_DropDownsampleCommand_Command)
+	proto.RegisterExtension(E_SetDownsampleLastRunCommand_Command)
+	proto.RegisterExtension(E_SetShardOwnerTierCommand_Command)
+	proto.RegisterExtension(E_ArchiveShardCommand_Command)
+	proto.RegisterExtension(E_UnarchiveShardCommand_Command)
+	proto.RegisterExtension(E_UndropShardGroupCommand_Command)
+	proto.RegisterExtension(E_TrashCommand_Command)
+	proto.RegisterExtension(E_UndropCommand_Command)
+	proto.RegisterExtension(E_PruneDroppedCommand_Command)
+	proto.RegisterExtension(E_SetTrashEnabledCommand_Command)
 }
 
 func init() { proto.RegisterFile("internal/meta.proto", fileDescriptorMeta) }
 
 var fileDescriptorMeta = []byte{
-	// 1808 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0x4b, 0x6f, 0x1c, 0xc7,
-	0x11, 0x46, 0xcf, 0x3e, 0xb8, 0x5b, 0x7c, 0xaa, 0xf9, 0x1a, 0x4a, 0x14, 0xb3, 0x18, 0x08, 0xca,
-	0x22, 0x08, 0x98, 0x60, 0x03, 0xe8, 0x94, 0x97, 0xc4, 0x95, 0xc4, 0x85, 0xc0, 0x47, 0x66, 0xa9,
-	0x6b, 0x80, 0x11, 0xb7, 0x25, 0x6e, 0xb2, 0x3b, 0xb3, 0x99, 0x99, 0x95, 0xc4, 0x28, 0x4c, 0x18,
-	0x5f, 0x7c, 0xb5, 0x61, 0x18, 0x3e, 0xe8, 0x66, 0x1f, 0x7c, 0x34, 0x0c, 0x03, 0x06, 0x0c, 0x9f,
-	0x7c, 0xf7, 0x1f, 0xf0, 0x7f, 0xb0, 0xcf, 0xbe, 0x1a, 0xdd, 0x3d, 0x3d, 0xdd, 0x33, 0xd3, 0x3d,
-	0x24, 0x65, 0xf9, 0x36, 0x5d, 0x55, 0xdd, 0xf5, 0x55, 0x75, 0x75, 0x75, 0x55, 0x0f, 0x2c, 0x0f,
-	0xfd, 0x98, 0x84, 0xbe, 0x37, 0xfa, 0xdd, 0x98, 0xc4, 0xde, 0xf6, 0x24, 0x0c, 0xe2, 0x00, 0x57,
-	0xe9, 0xb7, 0xf3, 0x5e, 0x05, 0xaa, 0x5d, 0x2f, 0xf6, 0x30, 0x86, 0xea, 0x11, 0x09, 0xc7, 0x36,
-	0x6a, 0x59, 0xed, 0xaa, 0xcb, 0xbe, 0xf1, 0x0a, 0xd4, 0x7a, 0xfe, 0x80, 0xbc, 0xb4, 0x2d, 0x46,
-	0xe4, 0x03, 0xbc, 0x09, 0xcd, 0x9d, 0xd1, 0x34, 0x8a, 0x49, 0xd8, 0xeb, 0xda, 0x15, 0xc6, 0x91,
-	0x04, 0x7c, 0x0b, 0x6a, 0xfb, 0xc1, 0x80, 0x44, 0x76, 0xb5, 0x55, 0x69, 0xcf, 0x76, 0x16, 0xb6,
-	0x99, 0x4a, 0x4a, 0xea, 0xf9, 0x4f, 0x03, 0x97, 0x33, 0xf1, 0xef, 0xa1, 0x49, 0xb5, 0x3e, 0xf1,
-	0x22, 0x12, 0xd9, 0x35, 0x26, 0x89, 0xb9, 0xa4, 0x20, 0x33, 0x69, 0x29, 0x44, 0xd7, 0x7d, 0x1c,
-	0x91, 0x30, 0xb2, 0xeb, 0xea, 0xba, 0x94, 0xc4, 0xd7, 0x65, 0x4c, 0x8a, 0x6d, 0xcf, 0x7b, 0xc9,
-	0xb4, 0x75, 0xed, 0x19, 0x8e, 0x2d, 0x25, 0xe0, 0x36, 0x2c, 0xee, 0x79, 0x2f, 0xfb, 0x27, 0x5e,
-	0x38, 0x78, 0x18, 0x06, 0xd3, 0x49, 0xaf, 0x6b, 0x37, 0x98, 0x4c, 0x9e, 0x8c, 0xb7, 0x00, 0x04,
-	0xa9, 0xd7, 0xb5, 0x9b, 0x4c, 0x48, 0xa1, 0xe0, 0xdf, 0x72, 0xfc, 0xdc, 0x52, 0xd0, 0x5a, 0x2a,
-	0x05, 0xa8, 0xf4, 0x1e, 0x11, 0xd2, 0xb3, 0x7a, 0xe9, 0x54, 0xc0, 0xd9, 0x85, 0x86, 0x20, 0xe3,
-	0x05, 0xb0, 0x7a, 0xdd, 0x64, 0x4f, 0xac, 0x5e, 0x97, 0xee, 0xd2, 0x6e, 0x10, 0xc5, 0x6c, 0x43,
-	0x9a, 0x2e, 0xfb, 0xc6, 0x36, 0xcc, 0x1c, 0xed, 0x1c, 0x32, 0x72, 0xa5, 0x85, 0xda, 0x4d, 0x57,
-	0x0c, 0x9d, 0xef, 0x11, 0xcc, 0xa9, 0xfe, 0xa4, 0xd3, 0xf7, 0xbd, 0x31, 0x61, 0x0b, 0x36, 0x5d,
-	0xf6, 0x8d, 0xef, 0xc0, 0x5a, 0x97, 0x3c, 0xf5, 0xa6, 0xa3, 0xd8, 0x25, 0x31, 0xf1, 0xe3, 0x61,
-	0xe0, 0x1f, 0x06, 0xa3, 0xe1, 0xf1, 0x69, 0xa2, 0xc4, 0xc0, 0xc5, 0x0f, 0xe1, 0x5a, 0x96, 0x34,
-	0x24, 0x91, 0x5d, 0x61, 0xc6, 0x6d, 0x70, 0xe3, 0x72, 0x33, 0x98, 0x9d, 0xc5, 0x39, 0x74, 0xa1,
-	0x9d, 0xc0, 0x8f, 0x87, 0xfe, 0x34, 0x98, 0x46, 0x7f, 0x9b, 0x92, 0x70, 0x98, 0x46, 0x4f, 0xb2,
-	0x50, 0x96, 0x9d, 0x2c, 0x54, 0x98, 0xe3, 0xbc, 0x8f, 0x60, 0x39, 0xa7, 0xb3, 0x3f, 0x21, 0xc7,
-	0x8a, 0xd5, 0x28, 0xb5, 0xfa, 0x3a, 0x34, 0xba, 0xd3, 0xd0, 0xa3, 0x92, 0xb6, 0xd5, 0x42, 0xed,
-	0x8a, 0x9b, 0x8e, 0xf1, 0x36, 0x60, 0x19, 0x0c, 0xa9, 0x54, 0x85, 0x49, 0x69, 0x38, 0x74, 0x2d,
-	0x97, 0x4c, 0x46, 0xc3, 0x63, 0x6f, 0xdf, 0xae, 0xb6, 0x50, 0x7b, 0xde, 0x4d, 0xc7, 0xce, 0xbb,
-	0x56, 0x01, 0x93, 0x71, 0x27, 0xb2, 0x98, 0xac, 0x4b, 0x61, 0xb2, 0x2e, 0x85, 0xc9, 0x52, 0x31,
-	0xe1, 0x3b, 0x30, 0x2b, 0x67, 0x88, 0xe3, 0xb7, 0xc2, 0x5d, 0xad, 0x9c, 0x02, 0xea, 0x65, 0x55,
-	0x10, 0xff, 0x11, 0xe6, 0xfb, 0xd3, 0x27, 0xd1, 0x71, 0x38, 0x9c, 0x50, 0x1d, 0xe2, 0x28, 0xae,
-	0x25, 0x33, 0x15, 0x16, 0x9b, 0x9b, 0x15, 0x76, 0xbe, 0x41, 0xb0, 0x90, 0x5d, 0xbd, 0x10, 0xdd,
-	0x9b, 0xd0, 0xec, 0xc7, 0x5e, 0x18, 0x1f, 0x0d, 0xc7, 0x24, 0xf1, 0x80, 0x24, 0xd0, 0x38, 0xbf,
-	0xef, 0x0f, 0x18, 0x8f, 0xdb, 0x2d, 0x86, 0x74, 0x5e, 0x97, 0x8c, 0x48, 0x4c, 0x06, 0x77, 0x63,
-	0x66, 0x6d, 0xc5, 0x95, 0x04, 0xfc, 0x6b, 0xa8, 0x33, 0xbd, 0xc2, 0xd2, 0x45, 0xc5, 0x52, 0x06,
-	0x34, 0x61, 0xe3, 0x16, 0xcc, 0x1e, 0x85, 0x53, 0xff, 0xd8, 0xe3, 0x0b, 0xd5, 0xd9, 0x86, 0xab,
-	0x24, 0x87, 0x40, 0x33, 0x9d, 0x56, 0x40, 0xbf, 0x05, 0x8d, 0x83, 0x17, 0x3e, 0x4d, 0x82, 0x91,
-	0x6d, 0xb5, 0x2a, 0xed, 0xea, 0x3d, 0xcb, 0x46, 0x6e, 0x4a, 0xc3, 0x6d, 0xa8, 0xb3, 0x6f, 0x71,
-	0x4a, 0x96, 0x14, 0x1c, 0x8c, 0xe1, 0x26, 0x7c, 0xe7, 0xef, 0xb0, 0x94, 0xf7, 0xa6, 0x36, 0x60,
-	0x30, 0x54, 0xf7, 0x82, 0x01, 0x11, 0xd9, 0x80, 0x7e, 0x63, 0x07, 0xe6, 0xba, 0x24, 0x8a, 0x87,
-	0xbe, 0xc7, 0xf7, 0x88, 0xea, 0x6a, 0xba, 0x19, 0x9a, 0x73, 0x0b, 0x40, 0x6a, 0xc5, 0x6b, 0x50,
-	0x4f, 0x12, 0x26, 0xb7, 0x25, 0x19, 0x39, 0x7f, 0x81, 0x65, 0xcd, 0xc1, 0xd3, 0x02, 0x59, 0x81,
-	0x1a, 0x13, 0x48, 0x90, 0xf0, 0x81, 0x73, 0x06, 0x0d, 0x91, 0x9f, 0x4d, 0xf0, 0x77, 0xbd, 0xe8,
-	0x24, 0x4d, 0x66, 0x5e, 0x74, 0x42, 0x57, 0xba, 0x3b, 0x18, 0x0f, 0x79, 0x68, 0x37, 0x5c, 0x3e,
-	0xc0, 0x7f, 0x00, 0x38, 0x0c, 0x87, 0xcf, 0x87, 0x23, 0xf2, 0x2c, 0xcd, 0x0d, 0xcb, 0xf2, 0x06,
-	0x48, 0x79, 0xae, 0x22, 0xe6, 0xf4, 0x60, 0x3e, 0xc3, 0x64, 0xe7, 0x2b, 0xc9, 0x86, 0x09, 0x8e,
-	0x74, 0x4c, 0x43, 0x28, 0x15, 0x64, 0x80, 0x6a, 0xae, 0x24, 0x38, 0xdf, 0xd5, 0x61, 0x66, 0x27,
-	0x18, 0x8f, 0x3d, 0x7f, 0x80, 0x6f, 0x43, 0x35, 0x3e, 0x9d, 0xf0, 0x15, 0x16, 0xc4, 0xad, 0x95,
-	0x30, 0xb7, 0x8f, 0x4e, 0x27, 0xc4, 0x65, 0x7c, 0xe7, 0x75, 0x1d, 0xaa, 0x74, 0x88, 0x57, 0xe1,
-	0xda, 0x4e, 0x48, 0xbc, 0x98, 0x50, 0xbf, 0x26, 0x82, 0x4b, 0x88, 0x92, 0x79, 0x8c, 0xaa, 0x64,
-	0x0b, 0x6f, 0xc0, 0x2a, 0x97, 0x16, 0xd0, 0x04, 0xab, 0x82, 0xd7, 0x61, 0xb9, 0x1b, 0x06, 0x93,
-	0x3c, 0xa3, 0x8a, 0x5b, 0xb0, 0xc9, 0xe7, 0xe4, 0x32, 0x8d, 0x90, 0xa8, 0xe1, 0x2d, 0xb8, 0x4e,
-	0xa7, 0x1a, 0xf8, 0x75, 0x7c, 0x0b, 0x5a, 0x7d, 0x12, 0xeb, 0x33, 0xbd, 0x90, 0x9a, 0xa1, 0x7a,
-	0x1e, 0x4f, 0x06, 0x66, 0x3d, 0x0d, 0x7c, 0x03, 0xd6, 0x39, 0x12, 0x79, 0xd2, 0x05, 0xb3, 0x49,
-	0x99, 0xdc, 0xe2, 0x22, 0x13, 0xa4, 0x0d, 0xb9, 0x98, 0x13, 0x12, 0xb3, 0xc2, 0x06, 0x03, 0x7f,
-	0x4e, 0xfa, 0x99, 0xee, 0xba, 0x20, 0xcf, 0xe3, 0x65, 0x58, 0xa4, 0xd3, 0x54, 0xe2, 0x02, 0x95,
-	0xe5, 0x96, 0xa8, 0xe4, 0x45, 0xea, 0xe1, 0x3e, 0x89, 0xd3, 0x7d, 0x17, 0x8c, 0x25, 0x8c, 0x61,
-	0x81, 0xfa, 0xc7, 0x8b, 0x3d, 0x41, 0xbb, 0x86, 0x37, 0xc1, 0xee, 0x93, 0x98, 0x05, 0x68, 0x61,
-	0x06, 0x96, 0x1a, 0xd4, 0xed, 0x5d, 0xc6, 0x37, 0x61, 0x23, 0x71, 0x90, 0x72, 0xc0, 0x05, 0x7b,
-	0x95, 0xb9, 0x28, 0x0c, 0x26, 0x3a, 0xe6, 0x1a, 0x5d, 0xd2, 0x25, 0xe3, 0xe0, 0x39, 0x39, 0x24,
-	0x12, 0xf4, 0xba, 0x8c, 0x18, 0x51, 0x42, 0x08, 0x96, 0x9d, 0x0d, 0x26, 0x95, 0xb5, 0x41, 0x59,
-	0x1c, 0x5f, 0x9e, 0x75, 0x9d, 0xb2, 0xf8, 0x3e, 0xe5, 0x17, 0xbc, 0x21, 0x59, 0xf9, 0x59, 0x9b,
-	0x78, 0x0d, 0x70, 0x9f, 0xc4, 0xf9, 0x29, 0x37, 0xf1, 0x0a, 0x2c, 0x31, 0x93, 0xe8, 0x9e, 0x0b,
-	0xea, 0xd6, 0x6f, 0x1a, 0x8d, 0xc1, 0xd2, 0xf9, 0xf9, 0xf9, 0xb9, 0xe5, 0x9c, 0x69, 0x8e, 0x47,
-	0x5a, 0xe7, 0x20, 0xa5, 0xce, 0xc1, 0x50, 0x75, 0x3d, 0x7f, 0x90, 0x14, 0xa3, 0xec, 0xbb, 0xf3,
-	0x57, 0x98, 0x39, 0x4e, 0xa6, 0xcc, 0x67, 0x4e, 0xa2, 0x4d, 0x5a, 0xa8, 0x3d, 0xdb, 0x59, 0x4f,
-	0x88, 0x79, 0x05, 0xae, 0x98, 0xe6, 0xbc, 0xd2, 0x1c, 0xc3, 0x42, 0x6a, 0x5f, 0x81, 0xda, 0x83,
-	0x20, 0x3c, 0xe6, 0x99, 0xa1, 0xe1, 0xf2, 0x41, 0x89, 0xf2, 0xa7, 0xaa, 0xf2, 0xc2, 0xf2, 0x52,
-	0xf9, 0x97, 0xc8, 0x70, 0xda, 0xb5, 0xf9, 0x72, 0x07, 0x16, 0x8b, 0x25, 0x1a, 0x2a, 0xaf, 0xb7,
-	0xf2, 0x33, 0x3a, 0x5d, 0x23, 0xe8, 0x67, 0x6c, 0xad, 0x1b, 0xaa, 0xc7, 0x72, 0xa8, 0x24, 0xf0,
-	0xb1, 0x36, 0x15, 0xe9, 0x50, 0x77, 0xee, 0x19, 0x15, 0x9e, 0xa8, 0xe0, 0x35, 0xcb, 0x49, 0x75,
-	0xdf, 0xa2, 0xf2, 0x0c, 0x57, 0x9a, 0xda, 0xb5, 0x6e, 0xb3, 0xae, 0xe8, 0xb6, 0x47, 0x46, 0x2b,
-	0x86, 0xcc, 0x0a, 0x47, 0x75, 0x9b, 0x1e, 0xa4, 0x34, 0xe7, 0x23, 0x54, 0x96, 0x8e, 0x4b, 0x8d,
-	0x11, 0x1e, 0xb6, 0x14, 0x0f, 0xf7, 0x8c, 0xd8, 0xfe, 0xc1, 0xb0, 0xb5, 0xa4, 0x87, 0x2f, 0x42,
-	0xf6, 0x09, 0xba, 0xf8, 0x22, 0xb8, 0x32, 0xbe, 0x03, 0x23, 0xbe, 0x7f, 0x32, 0x7c, 0xb7, 0x93,
-	0x42, 0xe8, 0x02, 0xbd, 0x12, 0xe5, 0x0f, 0xa8, 0xfc, 0x22, 0xba, 0x2a, 0x42, 0x5a, 0x5a, 0xee,
-	0x93, 0x17, 0x8c, 0x9c, 0xb4, 0x50, 0xc9, 0x30, 0x53, 0x93, 0x57, 0x73, 0x7d, 0x82, 0x5a, 0x63,
-	0xd7, 0xb2, 0x75, 0x7f, 0x49, 0xbc, 0x8c, 0xd4, 0x78, 0x29, 0xb3, 0x42, 0xda, 0xfb, 0x05, 0x32,
-	0x5e, 0xab, 0xa5, 0xa6, 0xae, 0x41, 0x3d, 0xd3, 0xca, 0x25, 0x23, 0x5a, 0xec, 0xd0, 0xba, 0x39,
-	0x8a, 0xbd, 0xf1, 0x24, 0xa9, 0xa5, 0x25, 0xa1, 0xf3, 0xc0, 0x08, 0x7d, 0xcc, 0xa0, 0xdf, 0x54,
-	0x43, 0xbd, 0x00, 0x48, 0xa2, 0xfe, 0x0a, 0x19, 0xef, 0xfb, 0x37, 0x42, 0xed, 0xc0, 0x5c, 0xa6,
-	0x75, 0xe7, 0x4f, 0x0f, 0x19, 0x5a, 0x09, 0x76, 0x5f, 0xc5, 0x6e, 0x80, 0x25, 0xb1, 0x7f, 0x8e,
-	0xca, 0xcb, 0x91, 0x2b, 0x47, 0x58, 0x5a, 0x21, 0x57, 0x94, 0x0a, 0xb9, 0x24, 0x4a, 0x82, 0x62,
-	0x56, 0xd1, 0x23, 0x29, 0x66, 0x95, 0xb7, 0x83, 0xb8, 0x24, 0xab, 0x4c, 0xf2, 0x59, 0xe5, 0x22,
-	0x64, 0x1f, 0x20, 0x4d, 0x69, 0xf6, 0xf3, 0x5a, 0x82, 0x92, 0xcb, 0xf7, 0x5f, 0xc5, 0x9b, 0x5f,
-	0x51, 0x2b, 0x51, 0x91, 0x42, 0x61, 0xa8, 0xbd, 0xbf, 0xfe, 0x6c, 0x54, 0x14, 0x32, 0x45, 0xab,
-	0xd2, 0x0f, 0x5a, 0x35, 0x67, 0x9a, 0x52, 0xf3, 0xb2, 0xb6, 0x97, 0x58, 0x19, 0xa9, 0x56, 0x16,
-	0x14, 0x48, 0xf5, 0x9f, 0x21, 0x6d, 0x4d, 0x4b, 0xc3, 0x81, 0xca, 0xfb, 0x12, 0x45, 0x3a, 0xce,
-	0x84, 0x8a, 0x55, 0xd6, 0x28, 0x55, 0x72, 0x8d, 0x52, 0xc9, 0x65, 0x1f, 0xab, 0x97, 0xbd, 0x06,
-	0x90, 0x44, 0x1c, 0xe4, 0x6b, 0x6d, 0xbc, 0xc5, 0xdf, 0x28, 0x19, 0xce, 0xd9, 0x0e, 0xc8, 0x87,
-	0x42, 0x97, 0xd1, 0x3b, 0x7f, 0x32, 0x6a, 0x9d, 0x32, 0xad, 0x2b, 0xf2, 0x82, 0x91, 0xab, 0x4a,
-	0x85, 0x1f, 0x22, 0x73, 0x25, 0x5f, 0xea, 0xa7, 0x34, 0x32, 0x2d, 0x35, 0x32, 0x1f, 0x1a, 0xd1,
-	0x3c, 0x67, 0x68, 0xb6, 0x52, 0x34, 0x5a, 0x8d, 0x12, 0xd7, 0xa9, 0xa6, 0x85, 0xb8, 0xcc, 0x8b,
-	0x60, 0x49, 0xd4, 0xbc, 0x28, 0x46, 0x8d, 0xb6, 0x30, 0xfd, 0x11, 0x95, 0xf4, 0x29, 0xc6, 0xc7,
-	0x2b, 0x53, 0xcc, 0xb4, 0x8b, 0x15, 0x18, 0x4f, 0x83, 0x79, 0x72, 0xfa, 0xa2, 0x51, 0x2d, 0x79,
-	0xd1, 0xa8, 0x15, 0x5f, 0x34, 0x3a, 0xbb, 0x46, 0x8b, 0x4f, 0x99, 0xc5, 0xbf, 0xca, 0xdc, 0x59,
-	0x45, 0x93, 0xa4, 0xe5, 0x5f, 0x23, 0x63, 0x0b, 0xf6, 0xcb, 0xd9, 0x5d, 0x72, 0x6f, 0xfd, 0x3b,
-	0x73, 0x6f, 0xe9, 0x81, 0x65, 0x42, 0xa6, 0xd0, 0x22, 0xa6, 0x21, 0x83, 0x64, 0xc8, 0xdc, 0x1d,
-	0x0c, 0x42, 0x11, 0x32, 0xf4, 0xbb, 0x24, 0x64, 0x5e, 0xa9, 0x21, 0x53, 0x58, 0x5c, 0xaa, 0xfe,
-	0x14, 0x19, 0xfa, 0x50, 0xea, 0xa2, 0xdd, 0xa3, 0xa3, 0x43, 0xa6, 0x33, 0x39, 0x42, 0x62, 0x9c,
-	0x3c, 0x5e, 0x2b, 0x70, 0xc4, 0x30, 0x6d, 0xf7, 0x2a, 0x4a, 0xbb, 0x67, 0x6e, 0x5e, 0xfe, 0x53,
-	0x6c, 0x5e, 0x72, 0x30, 0x32, 0xd7, 0x91, 0xbe, 0x2d, 0x7e, 0x33, 0xa4, 0x25, 0xa8, 0xce, 0xf4,
-	0x2d, 0x95, 0x16, 0xd5, 0x6b, 0x64, 0xe8, 0xc8, 0xaf, 0xfe, 0x13, 0xc0, 0x52, 0x7e, 0x02, 0x94,
-	0xa0, 0xfb, 0xaf, 0x8a, 0x4e, 0xab, 0x5a, 0x6d, 0xf8, 0xf4, 0x6f, 0x02, 0x79, 0x70, 0x25, 0xea,
-	0xfe, 0xa7, 0xaa, 0xd3, 0x2e, 0x26, 0xd5, 0xf9, 0x86, 0x77, 0x86, 0x82, 0xba, 0xfb, 0x46, 0x75,
-	0xe7, 0xa8, 0xa8, 0xcf, 0x68, 0xde, 0x03, 0x5a, 0xca, 0x47, 0x93, 0xc0, 0x8f, 0x08, 0x55, 0x71,
-	0xf0, 0x88, 0xa9, 0x68, 0xb8, 0xd6, 0xc1, 0x23, 0x9a, 0xe5, 0xef, 0x87, 0x61, 0x10, 0xb2, 0x66,
-	0xbb, 0xe9, 0xf2, 0x81, 0xfc, 0x37, 0x56, 0x61, 0xe7, 0x8a, 0x0f, 0x9c, 0x8f, 0x91, 0xee, 0x15,
-	0xe4, 0x2d, 0x9e, 0x00, 0xf3, 0x05, 0xfb, 0x7f, 0x6e, 0xaf, 0x9d, 0xde, 0x2e, 0x46, 0xe7, 0x0e,
-	0x8a, 0x2f, 0x32, 0x05, 0xbf, 0x9a, 0xf3, 0xc1, 0x3b, 0x5c, 0xcf, 0x9a, 0x92, 0x91, 0x94, 0x85,
-	0x52, 0x2d, 0x3f, 0x05, 0x00, 0x00, 0xff, 0xff, 0xc7, 0x04, 0x86, 0xd9, 0x75, 0x1c, 0x00, 0x00,
+	// 2958 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x5a, 0xcd, 0x8f, 0x1c, 0x47,
+	0x15, 0x57, 0x75, 0xcf, 0xec, 0xce, 0xd4, 0x7e, 0x64, 0xb7, 0x76, 0xbd, 0x6e, 0xaf, 0xd7, 0xf6,
+	0xa4, 0xe3, 0x38, 0x1b, 0xc7, 0xac, 0xa3, 0x11, 0x02, 0xe4, 0x13, 0x9b, 0x1d, 0x3b, 0x5e, 0x1c,
+	0xdb, 0x9b, 0x9e, 0xb5, 0xc2, 0x09, 0xa9, 0x3d, 0x53, 0xde, 0x1d, 0x98, 0xe9, 0x1e, 0xba, 0x7b,
+	0xd6, 0x76, 0x12, 0xc2, 0x3a, 0x09, 0x1f, 0x81, 0x10, 0x48, 0x40, 0x08, 0x09, 0x21, 0x0e, 0x5c,
+	0x10, 0x20, 0xc1, 0x01, 0x09, 0x21, 0x6e, 0x01, 0x71, 0x86, 0x1b, 0x12, 0x07, 0x0e, 0x88, 0x0b,
+	0xff, 0x00, 0xe2, 0x04, 0xaa, 0xaa, 0xae, 0xae, 0x8f, 0xae, 0xee, 0x5e, 0x9b, 0x58, 0xdc, 0xba,
+	0xde, 0x7b, 0xd5, 0xef, 0xf7, 0x5e, 0xbd, 0x7a, 0x55, 0xf5, 0xaa, 0xe0, 0xd2, 0x20, 0x48, 0x70,
+	0x14, 0xf8, 0xc3, 0x8b, 0x23, 0x9c, 0xf8, 0x1b, 0xe3, 0x28, 0x4c, 0x42, 0xd4, 0xe0, 0x44, 0xf7,
+	0xdf, 0x36, 0xac, 0x75, 0xfc, 0xc4, 0x47, 0x08, 0xd6, 0x76, 0x71, 0x34, 0x72, 0x40, 0xcb, 0x5a,
+	0xaf, 0x79, 0xf4, 0x1b, 0x2d, 0xc3, 0xfa, 0x76, 0xd0, 0xc7, 0xf7, 0x1c, 0x8b, 0x12, 0x59, 0x03,
+	0xad, 0xc1, 0xe6, 0xd6, 0x70, 0x12, 0x27, 0x38, 0xda, 0xee, 0x38, 0x36, 0xe5, 0x08, 0x02, 0x5a,
+	0x87, 0xf5, 0x1b, 0x61, 0x1f, 0xc7, 0x4e, 0xad, 0x65, 0xaf, 0xcf, 0xb4, 0xd1, 0x06, 0x57, 0xb5,
+	0x41, 0xc8, 0xdb, 0xc1, 0x9d, 0xd0, 0x63, 0x02, 0xe8, 0xe3, 0xb0, 0x49, 0x34, 0xdf, 0xf6, 0x63,
+	0x1c, 0x3b, 0x75, 0x2a, 0xbd, 0x22, 0xa4, 0x39, 0x8b, 0xf6, 0x10, 0x82, 0xe4, 0xff, 0xb7, 0x62,
+	0x1c, 0xc5, 0xce, 0x94, 0xfe, 0x7f, 0x42, 0x66, 0xff, 0xa7, 0x02, 0x04, 0xe7, 0x75, 0xff, 0x1e,
+	0xd5, 0xda, 0x71, 0xa6, 0x19, 0xce, 0x8c, 0x80, 0xd6, 0xe1, 0x13, 0xd7, 0xfd, 0x7b, 0xdd, 0x7d,
+	0x3f, 0xea, 0xbf, 0x18, 0x85, 0x93, 0xf1, 0x76, 0xc7, 0x69, 0x50, 0x19, 0x9d, 0x8c, 0x4e, 0x43,
+	0xc8, 0x49, 0xdb, 0x1d, 0xa7, 0x49, 0x85, 0x24, 0x0a, 0x7a, 0x9e, 0xd9, 0xc1, 0xac, 0x86, 0x85,
+	0x56, 0x0b, 0x21, 0xd2, 0xe3, 0x3a, 0xe6, 0x3d, 0x66, 0x8a, 0x7b, 0x64, 0x42, 0xe8, 0x22, 0x9c,
+	0xee, 0x44, 0xe1, 0x78, 0x8c, 0xfb, 0xce, 0x2c, 0x95, 0x3f, 0x26, 0x79, 0x8a, 0x31, 0x68, 0x17,
+	0x2e, 0x85, 0x5c, 0x38, 0xbb, 0x1b, 0xf9, 0xf1, 0xfe, 0xe5, 0xc0, 0xbf, 0x3d, 0xc4, 0x7d, 0x67,
+	0xae, 0x05, 0xd6, 0x1b, 0x9e, 0x42, 0x73, 0x5f, 0x87, 0x0d, 0xae, 0x0b, 0xcd, 0x43, 0x6b, 0xbb,
+	0x93, 0x0e, 0xbe, 0xb5, 0xdd, 0x21, 0xe1, 0x70, 0x35, 0x8c, 0x13, 0x3a, 0xf2, 0x4d, 0x8f, 0x7e,
+	0x23, 0x07, 0x4e, 0xef, 0x6e, 0xed, 0x50, 0xb2, 0xdd, 0x02, 0xeb, 0x4d, 0x8f, 0x37, 0xd1, 0x0a,
+	0x9c, 0xea, 0x26, 0x7e, 0x32, 0x21, 0xa3, 0x4e, 0x18, 0x69, 0x0b, 0xad, 0xc2, 0xc6, 0x4b, 0x7e,
+	0x9c, 0x74, 0x31, 0x0e, 0x9c, 0x7a, 0x0b, 0xac, 0xdb, 0x5e, 0xd6, 0x76, 0xbf, 0x6f, 0xc1, 0x59,
+	0x79, 0x90, 0x89, 0xca, 0x1b, 0xfe, 0x08, 0x53, 0x10, 0x4d, 0x8f, 0x7e, 0xa3, 0x4f, 0xc0, 0x95,
+	0x0e, 0xbe, 0xe3, 0x4f, 0x86, 0x89, 0x87, 0x13, 0x1c, 0x24, 0x83, 0x30, 0xd8, 0x09, 0x87, 0x83,
+	0xde, 0xfd, 0x14, 0x58, 0x01, 0x17, 0x5d, 0x83, 0x8b, 0x2a, 0x69, 0x80, 0x63, 0xc7, 0xa6, 0x9e,
+	0x3b, 0x25, 0x3c, 0xa7, 0xf5, 0xa2, 0x1e, 0xcc, 0xf7, 0x23, 0x3f, 0xdb, 0x0a, 0x83, 0x64, 0x10,
+	0x4c, 0xc2, 0x49, 0xfc, 0xf2, 0x04, 0x47, 0x83, 0x2c, 0xbc, 0xa5, 0x9f, 0xa9, 0x22, 0xe9, 0xcf,
+	0x72, 0xfd, 0x50, 0x0b, 0xce, 0x6c, 0x85, 0x41, 0x3c, 0x88, 0x13, 0x1c, 0xf4, 0xee, 0x53, 0xaf,
+	0x34, 0x3d, 0x99, 0xe4, 0xbe, 0x0f, 0xe0, 0x92, 0x86, 0xac, 0x3b, 0xc6, 0x3d, 0xc9, 0x3f, 0x20,
+	0xf3, 0xcf, 0x2a, 0x6c, 0x74, 0x26, 0x91, 0x4f, 0x24, 0x1d, 0x8b, 0x39, 0x98, 0xb7, 0xd1, 0x06,
+	0x44, 0x22, 0x8e, 0x33, 0x29, 0x9b, 0x4a, 0x19, 0x38, 0xe4, 0x5f, 0x1e, 0x1e, 0x0f, 0x07, 0x3d,
+	0xff, 0x06, 0x1d, 0xc6, 0x39, 0x2f, 0x6b, 0xbb, 0xff, 0xb2, 0x73, 0x98, 0x0a, 0xc7, 0x4c, 0xc5,
+	0x64, 0x1d, 0x09, 0x93, 0x75, 0x24, 0x4c, 0x96, 0x8c, 0x09, 0x5d, 0x82, 0x33, 0xa2, 0x07, 0xcf,
+	0x20, 0x8e, 0x18, 0x10, 0x69, 0x12, 0x93, 0xb1, 0x90, 0x85, 0xd1, 0xa7, 0xe1, 0x5c, 0x77, 0x72,
+	0x3b, 0xee, 0x45, 0x83, 0x31, 0xd1, 0xc3, 0xb3, 0xc9, 0xaa, 0xd4, 0x5b, 0x62, 0xd3, 0xfe, 0x6a,
+	0x07, 0x7d, 0x1c, 0xa7, 0x73, 0xe3, 0x48, 0xf0, 0x75, 0xc2, 0xbb, 0x41, 0xec, 0x8f, 0xc6, 0x43,
+	0x1c, 0x3b, 0x0d, 0x1d, 0x9f, 0x60, 0x32, 0x7c, 0x92, 0x30, 0xcd, 0xb1, 0xe1, 0xb0, 0xbf, 0x79,
+	0x27, 0xc1, 0x91, 0xd3, 0xa4, 0x43, 0x26, 0x08, 0x24, 0x77, 0x75, 0x26, 0xd4, 0x0b, 0x09, 0x4e,
+	0xa7, 0x03, 0xa4, 0xfa, 0x75, 0x32, 0x41, 0xd9, 0x4d, 0xa2, 0x41, 0xb0, 0xb7, 0x15, 0xf6, 0x71,
+	0xcf, 0x99, 0x61, 0x28, 0x25, 0x12, 0xc9, 0x6e, 0x57, 0x86, 0xa1, 0x9f, 0x30, 0x81, 0x59, 0x2a,
+	0x20, 0x51, 0xdc, 0x37, 0xe0, 0xbc, 0x0a, 0x94, 0x4c, 0xf6, 0x5d, 0x3f, 0xda, 0xc3, 0x49, 0x3a,
+	0xea, 0x69, 0x8b, 0x8c, 0xd5, 0x36, 0xb1, 0xed, 0xc0, 0x1f, 0xf2, 0x71, 0xe7, 0x6d, 0xa2, 0x65,
+	0x73, 0x6f, 0x2f, 0xc2, 0x7b, 0x7e, 0x92, 0x4e, 0xc4, 0xa6, 0x27, 0x51, 0x48, 0x6a, 0x21, 0x89,
+	0xc1, 0x9b, 0x04, 0x34, 0xf4, 0x6c, 0x8f, 0x37, 0xdd, 0xbf, 0x01, 0x38, 0xaf, 0x8e, 0x64, 0x2e,
+	0x57, 0xad, 0xc1, 0x66, 0x37, 0xf1, 0xa3, 0x64, 0x77, 0x30, 0xc2, 0xa9, 0x66, 0x41, 0x20, 0xbf,
+	0xbe, 0x1c, 0xf4, 0x29, 0x8f, 0xc5, 0x19, 0x6f, 0x92, 0x7e, 0x1d, 0x3c, 0xc4, 0x09, 0xee, 0x6f,
+	0x26, 0x34, 0xba, 0x6c, 0x4f, 0x10, 0xd0, 0x73, 0x70, 0x8a, 0xea, 0xe5, 0x91, 0xb5, 0xa4, 0x45,
+	0x16, 0x1d, 0xb4, 0x54, 0x84, 0xf8, 0x79, 0x37, 0x9a, 0x04, 0x3d, 0x9f, 0xfd, 0x6c, 0x8a, 0xda,
+	0x20, 0x93, 0x08, 0x8c, 0x9d, 0x49, 0xb4, 0x87, 0x37, 0x13, 0x1a, 0x2b, 0xb6, 0xc7, 0x9b, 0xee,
+	0x3b, 0x00, 0x36, 0xb3, 0x3f, 0xe6, 0x8c, 0x3b, 0x0d, 0x1b, 0x37, 0xef, 0x06, 0x64, 0x69, 0x8d,
+	0x1d, 0xab, 0x65, 0xaf, 0xd7, 0x5e, 0xb0, 0x1c, 0xe0, 0x65, 0x34, 0x74, 0x01, 0x4e, 0xd1, 0x6f,
+	0x9e, 0xde, 0x96, 0x35, 0x98, 0x94, 0xe9, 0xa5, 0x32, 0x74, 0x1c, 0xa2, 0xde, 0xfe, 0xe0, 0x20,
+	0xb5, 0x99, 0x00, 0x91, 0x28, 0xee, 0xe7, 0xe0, 0x82, 0x1e, 0xf8, 0xc6, 0x39, 0x8e, 0x60, 0xed,
+	0x7a, 0xd8, 0xc7, 0x7c, 0x79, 0x20, 0xdf, 0x64, 0xc9, 0xe9, 0xe0, 0x38, 0x19, 0x04, 0x3e, 0x9b,
+	0x52, 0x6c, 0x94, 0x15, 0x9a, 0xfb, 0x29, 0x08, 0x05, 0x2a, 0x12, 0x49, 0xe9, 0xf2, 0xcc, 0xec,
+	0x4d, 0x5b, 0x74, 0x2f, 0x32, 0xc0, 0x11, 0xcd, 0x68, 0x4d, 0x8f, 0x7e, 0xbb, 0x7f, 0xb7, 0xe0,
+	0x92, 0x21, 0xc5, 0x1a, 0xd1, 0x2d, 0xc3, 0x3a, 0x15, 0x48, 0xe1, 0xb1, 0x86, 0x1c, 0x63, 0xb6,
+	0x12, 0x63, 0xc4, 0x2b, 0xe4, 0x33, 0xc5, 0x42, 0xbc, 0x52, 0xf3, 0x24, 0x0a, 0xb1, 0x8c, 0xb4,
+	0xb2, 0x7c, 0xc5, 0x96, 0x32, 0x85, 0x86, 0x2e, 0xc0, 0x45, 0xd2, 0xde, 0x09, 0x07, 0x41, 0x12,
+	0xbf, 0x12, 0x0d, 0x92, 0x04, 0x07, 0x69, 0x1c, 0xe4, 0x19, 0xe8, 0x3c, 0x5c, 0xa0, 0x0b, 0xe1,
+	0xa4, 0xd7, 0xc3, 0x71, 0x4c, 0x83, 0x35, 0x0d, 0x8b, 0x1c, 0x1d, 0x9d, 0x83, 0xf3, 0x12, 0xed,
+	0x72, 0xd0, 0x77, 0x1a, 0x54, 0x52, 0xa3, 0x92, 0x70, 0x26, 0x94, 0xcb, 0x51, 0x14, 0xb2, 0x9c,
+	0xd1, 0xf4, 0x04, 0x01, 0x9d, 0x85, 0x73, 0x59, 0x83, 0x4e, 0x06, 0x48, 0x7f, 0xa2, 0x12, 0xdd,
+	0x07, 0x00, 0x36, 0xf8, 0x3e, 0xaa, 0x68, 0xe0, 0xaf, 0xfa, 0xf1, 0x7e, 0xb6, 0x2f, 0xf0, 0xe3,
+	0x7d, 0xe2, 0xee, 0xcd, 0xfe, 0x68, 0xc0, 0xf2, 0x78, 0xc3, 0x63, 0x0d, 0xf4, 0x49, 0x08, 0x77,
+	0xa2, 0xc1, 0xc1, 0x60, 0x88, 0xf7, 0xb2, 0xe5, 0xf2, 0xb8, 0xba, 0x5b, 0xcb, 0xf8, 0x9e, 0x24,
+	0xea, 0x6e, 0xc3, 0x39, 0x85, 0x49, 0x17, 0x94, 0x74, 0xa3, 0x90, 0x62, 0xc9, 0xda, 0xc4, 0xe8,
+	0x4c, 0x90, 0x82, 0xaa, 0x7b, 0x82, 0xe0, 0xfe, 0xc3, 0x82, 0x33, 0xd2, 0xf6, 0x88, 0x06, 0xd6,
+	0xfd, 0x71, 0x66, 0x11, 0xf9, 0x56, 0xfe, 0x6e, 0x69, 0x7f, 0xe7, 0x1e, 0xb0, 0xa5, 0x25, 0xd7,
+	0x81, 0xd3, 0x7c, 0x2f, 0xc8, 0x22, 0x85, 0x37, 0x69, 0x3e, 0x61, 0xca, 0x36, 0x13, 0xa7, 0x9e,
+	0xe6, 0x13, 0x4e, 0x40, 0x97, 0xd4, 0xed, 0x0e, 0x8d, 0x8d, 0xe2, 0x1d, 0xaf, 0x22, 0x8b, 0x2e,
+	0x29, 0xbe, 0x9c, 0xd6, 0xd7, 0xaa, 0x54, 0x89, 0xd1, 0x9d, 0x64, 0xb1, 0xd0, 0xf7, 0x4e, 0x0d,
+	0xb6, 0x58, 0x68, 0x64, 0xc4, 0x27, 0x27, 0xcd, 0xb4, 0x34, 0x82, 0xca, 0xd6, 0x53, 0x49, 0xd6,
+	0xed, 0xc0, 0x05, 0x1d, 0x03, 0xf1, 0x1d, 0x19, 0x46, 0xee, 0x6b, 0xf2, 0x5d, 0x31, 0x5a, 0x1f,
+	0x42, 0x38, 0xbd, 0x15, 0x8e, 0x46, 0x7e, 0xd0, 0x47, 0xe7, 0x61, 0x2d, 0xe1, 0x23, 0x35, 0x2f,
+	0x7b, 0x29, 0x15, 0xd8, 0x20, 0x63, 0xe7, 0x51, 0x19, 0xf7, 0x10, 0xb2, 0x61, 0x45, 0xc7, 0xe0,
+	0xe2, 0x56, 0x84, 0xfd, 0x04, 0x93, 0x79, 0x9b, 0x0a, 0x2e, 0x00, 0x42, 0x66, 0x69, 0x5d, 0x26,
+	0x5b, 0xe8, 0x04, 0x3c, 0xc6, 0xa4, 0xb9, 0xab, 0x39, 0xcb, 0x46, 0xc7, 0xe1, 0x12, 0xb1, 0x47,
+	0x67, 0xd4, 0x50, 0x0b, 0xae, 0xb1, 0x3e, 0x9a, 0xef, 0xb8, 0x44, 0x1d, 0x9d, 0x86, 0xab, 0xa4,
+	0x6b, 0x01, 0x7f, 0x0a, 0x9d, 0x85, 0xad, 0x2e, 0x4e, 0xcc, 0xdb, 0x56, 0x2e, 0x35, 0x4d, 0xf4,
+	0xdc, 0x1a, 0xf7, 0x8b, 0xf5, 0x34, 0xd0, 0x49, 0x78, 0x9c, 0x21, 0x11, 0xc3, 0xc0, 0x99, 0x4d,
+	0xc2, 0x64, 0x16, 0xe7, 0x99, 0x50, 0xd8, 0xa0, 0xa5, 0x53, 0x2e, 0x31, 0xc3, 0x6d, 0x28, 0xe0,
+	0xcf, 0x0a, 0x3f, 0x93, 0x41, 0xe5, 0xe4, 0x39, 0xb4, 0x04, 0x9f, 0x20, 0xdd, 0x64, 0xe2, 0x3c,
+	0x91, 0x65, 0x96, 0xc8, 0xe4, 0x27, 0x88, 0x87, 0xbb, 0x38, 0xc9, 0xc6, 0x9e, 0x33, 0x16, 0x10,
+	0x82, 0xf3, 0xc4, 0x3f, 0x7e, 0xe2, 0x73, 0xda, 0x22, 0x5a, 0x83, 0x4e, 0x17, 0x27, 0x34, 0xad,
+	0xe4, 0x7a, 0x20, 0xa1, 0x41, 0x1e, 0xde, 0x25, 0x74, 0x0a, 0x9e, 0x48, 0x1d, 0x24, 0x2d, 0x68,
+	0x9c, 0x7d, 0x8c, 0xba, 0x28, 0x0a, 0xc7, 0x26, 0xe6, 0x0a, 0xf9, 0xa5, 0x87, 0x47, 0xe1, 0x01,
+	0xde, 0xc1, 0x02, 0xf4, 0x71, 0x11, 0x31, 0xfc, 0x60, 0xc6, 0x59, 0x8e, 0x1a, 0x4c, 0x32, 0xeb,
+	0x04, 0x61, 0x31, 0x7c, 0x3a, 0x6b, 0x95, 0xb0, 0xd8, 0x38, 0xe9, 0x3f, 0x3c, 0x29, 0x58, 0x7a,
+	0xaf, 0x35, 0xb4, 0x02, 0x51, 0x17, 0x27, 0x7a, 0x97, 0x53, 0x68, 0x99, 0xcd, 0x42, 0x3a, 0xe6,
+	0x9c, 0x7a, 0x1a, 0x39, 0x70, 0x79, 0xb3, 0xdf, 0x17, 0xab, 0x2e, 0xe7, 0x9c, 0x21, 0x2e, 0x60,
+	0x56, 0xe6, 0x99, 0xad, 0xd4, 0xe7, 0x5c, 0x39, 0x3b, 0xcf, 0x71, 0xae, 0x8b, 0x9e, 0x84, 0xa7,
+	0x52, 0x2e, 0x9b, 0x1f, 0xd9, 0xae, 0x97, 0x8b, 0x3c, 0x95, 0x06, 0xba, 0x16, 0x43, 0xe9, 0x8a,
+	0xcb, 0xa5, 0xce, 0xa2, 0xa7, 0xe0, 0x99, 0xbc, 0x94, 0xaa, 0xed, 0x69, 0x11, 0xeb, 0x62, 0x27,
+	0xca, 0x99, 0xe7, 0xa8, 0xa3, 0xc8, 0x5c, 0xcd, 0xb1, 0x9e, 0x41, 0x67, 0xe0, 0x49, 0x82, 0x32,
+	0xe3, 0x68, 0xda, 0xd7, 0x53, 0x23, 0x85, 0xf9, 0x64, 0xa7, 0xc1, 0xb9, 0xcf, 0x92, 0x18, 0x4d,
+	0xb7, 0x46, 0x8a, 0x4b, 0xcf, 0xd3, 0x11, 0x0d, 0x7c, 0x03, 0xeb, 0x39, 0x02, 0xf5, 0x56, 0xd0,
+	0xe7, 0xa3, 0xa0, 0xcc, 0xbc, 0x0b, 0x68, 0x21, 0x3d, 0x94, 0x73, 0xca, 0xc7, 0xd0, 0x22, 0x9c,
+	0x63, 0xe2, 0x9c, 0xb4, 0x41, 0xb4, 0xee, 0x44, 0x93, 0x00, 0xa7, 0x09, 0x95, 0x33, 0x2e, 0xa2,
+	0x55, 0xb8, 0xd2, 0xc5, 0x89, 0x7c, 0x82, 0xe7, 0xbc, 0xe7, 0xcf, 0x37, 0x1a, 0xfd, 0x85, 0xc3,
+	0xc3, 0xc3, 0x43, 0xcb, 0xfd, 0x0a, 0x30, 0x24, 0xc1, 0xec, 0x38, 0x0f, 0xa4, 0xe3, 0x3c, 0x82,
+	0x35, 0xcf, 0x0f, 0xfa, 0x69, 0x71, 0x87, 0x7e, 0xb7, 0xaf, 0xc2, 0xe9, 0x5e, 0xda, 0x65, 0x31,
+	0x97, 0x73, 0x1d, 0x4c, 0x97, 0x84, 0x93, 0x12, 0x43, 0x57, 0xe4, 0xf1, 0xee, 0xee, 0x5b, 0xc0,
+	0x90, 0x75, 0x73, 0xbb, 0xdb, 0x65, 0x58, 0xbf, 0x12, 0x46, 0x3d, 0xb6, 0x18, 0x34, 0x3c, 0xd6,
+	0xa8, 0x40, 0x71, 0x47, 0x47, 0x91, 0x53, 0x23, 0x50, 0x7c, 0x08, 0x0a, 0x92, 0xbc, 0x71, 0x73,
+	0xf3, 0x62, 0x7e, 0xa9, 0xb4, 0x5a, 0x40, 0x3d, 0xe6, 0x9b, 0x6a, 0x06, 0x7a, 0xaf, 0xf6, 0x4b,
+	0xa5, 0x06, 0xec, 0xd1, 0x7f, 0x9e, 0xd1, 0xdd, 0xa8, 0x21, 0x14, 0x46, 0x4c, 0x8c, 0xab, 0x91,
+	0xc9, 0x82, 0xf6, 0x67, 0x4a, 0x15, 0xef, 0xeb, 0xc6, 0x18, 0x7e, 0x2b, 0xd4, 0xfe, 0x05, 0x94,
+	0x2f, 0x76, 0xa5, 0xfb, 0x32, 0xa3, 0x2b, 0xad, 0x47, 0x70, 0x65, 0xb7, 0xd4, 0xa2, 0x01, 0xb5,
+	0xe8, 0x9c, 0xee, 0x4a, 0x33, 0x60, 0x61, 0xda, 0x8f, 0x41, 0xd9, 0x2a, 0x5d, 0x6a, 0x18, 0xf7,
+	0xba, 0x25, 0x79, 0xfd, 0xe5, 0x52, 0x8c, 0x9f, 0xa7, 0x18, 0xcf, 0xaa, 0x5e, 0xaf, 0x42, 0xf8,
+	0x73, 0x50, 0xbd, 0x4f, 0x78, 0x68, 0x9c, 0xaf, 0x94, 0xe2, 0xfc, 0x02, 0xc5, 0x79, 0x5e, 0x30,
+	0xaa, 0xf4, 0x0b, 0xb4, 0x7f, 0xb6, 0xcb, 0xf7, 0x2b, 0x0f, 0x8b, 0x94, 0x6c, 0xb2, 0x6f, 0xe0,
+	0xbb, 0xd2, 0xde, 0x9b, 0x37, 0x95, 0xea, 0x52, 0x4d, 0xab, 0x78, 0xc9, 0xd5, 0xa2, 0xba, 0x5a,
+	0xc1, 0xd2, 0xeb, 0x35, 0x53, 0xf9, 0x7a, 0x8d, 0x52, 0x73, 0x99, 0x3e, 0x42, 0xcd, 0xa5, 0x61,
+	0xae, 0xb9, 0x98, 0x6b, 0x5c, 0xcd, 0xc2, 0xba, 0x9b, 0x56, 0xa3, 0x81, 0x55, 0x35, 0x9a, 0x19,
+	0xbd, 0x46, 0x53, 0x31, 0x47, 0x86, 0xfa, 0x1c, 0x29, 0x1b, 0x29, 0x31, 0xa6, 0xbf, 0x03, 0x85,
+	0x3b, 0xcc, 0xd2, 0xe1, 0x5c, 0x81, 0x53, 0x4a, 0x89, 0x36, 0x6d, 0x11, 0xf7, 0x92, 0x23, 0x66,
+	0x9c, 0xf8, 0xa3, 0x71, 0x5a, 0x89, 0x11, 0x84, 0xf6, 0x8d, 0x52, 0x13, 0x46, 0xd4, 0x84, 0x27,
+	0xf5, 0x69, 0x9e, 0x03, 0x26, 0xd0, 0xff, 0x15, 0x14, 0x6e, 0x81, 0x1f, 0x09, 0xbd, 0x0b, 0x67,
+	0x95, 0xbb, 0x02, 0x76, 0xef, 0xa1, 0xd0, 0xe4, 0x12, 0x4f, 0x4d, 0x29, 0xf1, 0x54, 0x58, 0x17,
+	0xe8, 0xd6, 0x15, 0x00, 0x17, 0xd6, 0xfd, 0x16, 0x94, 0xef, 0xe1, 0x1f, 0x7a, 0xbe, 0x65, 0x15,
+	0x13, 0x5b, 0xaa, 0x98, 0x54, 0xc4, 0x55, 0x68, 0xce, 0xbd, 0x66, 0x44, 0xf9, 0xdc, 0xfb, 0xd1,
+	0x20, 0xaf, 0xc8, 0xbd, 0x63, 0x53, 0xee, 0xad, 0x42, 0xf8, 0x43, 0x60, 0x38, 0xdf, 0xfc, 0x6f,
+	0xd5, 0x90, 0x8a, 0x2d, 0xcd, 0x17, 0xcd, 0x1b, 0x2b, 0x49, 0xbd, 0x40, 0x37, 0xca, 0x9d, 0xb2,
+	0x8c, 0x3b, 0x81, 0x2b, 0xa5, 0x0a, 0x23, 0xaa, 0xf0, 0x84, 0xea, 0x17, 0xa3, 0x3a, 0xb2, 0x9f,
+	0xcc, 0x1d, 0xe0, 0x8e, 0xea, 0x8c, 0x0a, 0xb3, 0x63, 0xdd, 0xec, 0x9c, 0x22, 0x81, 0xe3, 0x37,
+	0xc0, 0x78, 0x62, 0x24, 0xf1, 0x42, 0xe4, 0x03, 0x81, 0x26, 0x6b, 0x97, 0x96, 0x76, 0x94, 0x52,
+	0x84, 0xad, 0x95, 0x22, 0x2a, 0xf6, 0x51, 0x89, 0xbe, 0x8f, 0x32, 0x00, 0x13, 0xc8, 0x5f, 0xd3,
+	0x4f, 0xb4, 0xc8, 0x65, 0x77, 0xae, 0x14, 0xef, 0x4c, 0x7b, 0x5e, 0x2d, 0x01, 0x79, 0x94, 0xd7,
+	0xbe, 0x5c, 0x8a, 0x60, 0x92, 0x2b, 0xce, 0x28, 0x1a, 0x84, 0xf2, 0x1f, 0x81, 0xe2, 0xb3, 0x73,
+	0xa9, 0xef, 0xb2, 0x30, 0xb6, 0xe4, 0x30, 0xbe, 0x59, 0x8a, 0xea, 0x80, 0xa2, 0x72, 0x15, 0x54,
+	0x46, 0xcd, 0x02, 0xdf, 0x03, 0x60, 0x38, 0xbd, 0x1f, 0xe5, 0x36, 0xb2, 0x22, 0xb4, 0xee, 0x9a,
+	0x43, 0xcb, 0x78, 0x48, 0xf8, 0x0f, 0x28, 0x29, 0x15, 0x14, 0x5e, 0x71, 0x15, 0x05, 0x96, 0xa1,
+	0xde, 0xc6, 0x92, 0xaa, 0x4e, 0xce, 0x8a, 0xe8, 0xb5, 0x92, 0x22, 0x7a, 0x3d, 0x5f, 0x44, 0x6f,
+	0xef, 0x94, 0x5a, 0x7e, 0x9f, 0x5a, 0xfe, 0x54, 0x6e, 0xad, 0xcc, 0x9b, 0x26, 0x3c, 0xf0, 0x7b,
+	0x50, 0x58, 0x0d, 0x79, 0x7c, 0xf6, 0x57, 0xac, 0x8a, 0xaf, 0xe6, 0x56, 0x45, 0x33, 0x40, 0x35,
+	0x96, 0x72, 0x65, 0x9b, 0x2c, 0x96, 0x80, 0x88, 0xa5, 0xcd, 0x7e, 0x3f, 0xe2, 0xb1, 0x44, 0xbe,
+	0x2b, 0x62, 0xe9, 0x35, 0x3d, 0x96, 0x72, 0x4a, 0x04, 0x86, 0x5f, 0x82, 0x82, 0x1a, 0x11, 0xf1,
+	0xd9, 0xd5, 0xdd, 0xdd, 0x1d, 0xaa, 0x3b, 0x9d, 0x6c, 0xbc, 0x9d, 0xde, 0xac, 0x4b, 0xb0, 0x78,
+	0x33, 0x3b, 0xa4, 0xdb, 0xd2, 0x21, 0xbd, 0xfc, 0x74, 0xf9, 0xba, 0xf9, 0x74, 0xa9, 0xc1, 0x51,
+	0x56, 0x3b, 0x73, 0xe9, 0xea, 0xd1, 0x10, 0x57, 0xa0, 0xfb, 0x52, 0xf1, 0xd9, 0xd7, 0x88, 0xee,
+	0x27, 0xa0, 0xa0, 0x7a, 0xf6, 0xf0, 0x2f, 0x16, 0x2c, 0xe9, 0xc5, 0x42, 0x05, 0xca, 0x37, 0x74,
+	0x94, 0x46, 0x08, 0xf2, 0x09, 0xdd, 0x5c, 0xc7, 0xd3, 0x41, 0x56, 0xa8, 0xfd, 0xb2, 0xae, 0xd6,
+	0xf8, 0x53, 0xa1, 0xf6, 0xa0, 0xa0, 0x46, 0x98, 0x53, 0x7b, 0xbd, 0x54, 0xed, 0x21, 0x30, 0xeb,
+	0x2d, 0x34, 0xf7, 0x0a, 0x39, 0x67, 0xc5, 0xe3, 0x30, 0x88, 0x31, 0x51, 0x75, 0xf3, 0x1a, 0x55,
+	0xd5, 0xf0, 0xac, 0x9b, 0xd7, 0xc8, 0xba, 0xc1, 0x6e, 0xa0, 0xd8, 0xe5, 0x1d, 0x6b, 0x88, 0x97,
+	0x44, 0x36, 0x9d, 0x87, 0xac, 0xe1, 0xfe, 0x0c, 0x98, 0x2a, 0x99, 0x1f, 0xe1, 0x4c, 0x29, 0x5f,
+	0xc6, 0x1f, 0x30, 0xbb, 0xd7, 0x94, 0xf5, 0xaa, 0xd0, 0xd9, 0xc3, 0x7c, 0x75, 0x35, 0xe7, 0xe7,
+	0xf2, 0x3c, 0xf2, 0x26, 0xd3, 0xa7, 0x5d, 0xdc, 0xc8, 0x3f, 0x14, 0xda, 0xde, 0x05, 0xe6, 0xb2,
+	0x6d, 0x2e, 0xec, 0xc5, 0x1d, 0xaa, 0x25, 0xdf, 0xa1, 0x56, 0x44, 0xda, 0x5b, 0x0c, 0xca, 0x69,
+	0xc1, 0x31, 0x29, 0x13, 0x70, 0x3e, 0x00, 0x85, 0xb5, 0xe2, 0x23, 0x23, 0x2a, 0xdf, 0x3b, 0xbc,
+	0x0d, 0xf4, 0x7c, 0x5f, 0xa0, 0x4f, 0x80, 0xfa, 0x15, 0x28, 0xae, 0x51, 0x9b, 0x50, 0x31, 0x01,
+	0x7e, 0xb0, 0x33, 0x3c, 0x51, 0xb2, 0xd5, 0x27, 0x4a, 0x15, 0x0b, 0xed, 0x57, 0x81, 0x61, 0xbb,
+	0x63, 0x04, 0x23, 0x20, 0xff, 0x02, 0x54, 0x14, 0xce, 0x8d, 0xcb, 0xad, 0x56, 0xbb, 0x60, 0x06,
+	0xc8, 0xa4, 0xf6, 0xad, 0x52, 0xa4, 0x5f, 0x63, 0x48, 0x9f, 0xc9, 0x21, 0x35, 0x63, 0x10, 0x70,
+	0xff, 0x08, 0xaa, 0x8b, 0xf8, 0x8f, 0x52, 0xdb, 0x11, 0xf7, 0xf0, 0x96, 0x74, 0x0f, 0xdf, 0xfe,
+	0x6c, 0xa9, 0x15, 0x5f, 0x07, 0x86, 0x02, 0x55, 0x29, 0x34, 0x61, 0xc8, 0xa1, 0x5d, 0x79, 0xcf,
+	0xf0, 0xd0, 0x76, 0x88, 0x38, 0xb7, 0xf3, 0xaf, 0x17, 0x46, 0x38, 0x7d, 0x51, 0x42, 0xbf, 0x95,
+	0xaa, 0x55, 0x5d, 0x7b, 0x13, 0x75, 0x16, 0xce, 0xe9, 0xaf, 0x06, 0x88, 0x80, 0x4a, 0x54, 0x1f,
+	0xb9, 0x4c, 0x97, 0x3c, 0x72, 0x69, 0xa8, 0x8f, 0x5c, 0xb2, 0x7c, 0xdc, 0x94, 0xf3, 0xb1, 0x34,
+	0x06, 0x50, 0x79, 0x0b, 0x51, 0x51, 0x23, 0x7c, 0x87, 0x8d, 0xc1, 0xb3, 0x65, 0x63, 0x50, 0x10,
+	0xfa, 0x6f, 0x5b, 0x85, 0xb7, 0x38, 0xa5, 0xae, 0x5f, 0x37, 0x57, 0x92, 0x0d, 0xfb, 0x69, 0xf1,
+	0x30, 0xc9, 0x2e, 0x7c, 0x98, 0x54, 0x2b, 0x7d, 0x98, 0x54, 0xd7, 0x1f, 0x26, 0x55, 0x24, 0xad,
+	0x6f, 0x00, 0x73, 0x61, 0x2a, 0x67, 0xa1, 0x70, 0xc3, 0x1f, 0x40, 0xc1, 0x7d, 0xd5, 0xe3, 0x75,
+	0x42, 0xc5, 0x16, 0xe0, 0x9b, 0xf9, 0x2d, 0x80, 0x09, 0xa3, 0x30, 0xe3, 0x9f, 0xa0, 0xf4, 0x6e,
+	0xed, 0x31, 0x8f, 0xa8, 0xf2, 0x5c, 0x4c, 0x49, 0x21, 0xe5, 0x25, 0xab, 0x77, 0x99, 0x99, 0x4f,
+	0xab, 0x89, 0xb0, 0xc0, 0x06, 0x61, 0xec, 0x4f, 0x41, 0xf1, 0x3d, 0xe1, 0x51, 0x97, 0xbf, 0xec,
+	0x51, 0x93, 0x9d, 0xbe, 0x3d, 0x19, 0xe0, 0xa8, 0x62, 0x81, 0xf9, 0x96, 0x69, 0x81, 0x31, 0x82,
+	0x50, 0x16, 0x6a, 0xd3, 0xa5, 0xa5, 0xe1, 0x59, 0x99, 0xfc, 0x10, 0x8c, 0x3d, 0x9a, 0x93, 0x28,
+	0xed, 0x6b, 0xa5, 0xc8, 0xde, 0x03, 0x7a, 0x05, 0xc4, 0xa0, 0x53, 0x80, 0x7a, 0x0f, 0x14, 0x5c,
+	0x98, 0x1e, 0x79, 0xef, 0x50, 0x1e, 0xbd, 0xdf, 0xce, 0x45, 0xaf, 0x51, 0x9b, 0x00, 0xf4, 0x03,
+	0x50, 0x78, 0x4d, 0x6b, 0x7a, 0x5d, 0x28, 0xea, 0xd6, 0x96, 0x5e, 0xb7, 0x2e, 0xcf, 0x0f, 0xdf,
+	0xc9, 0xe5, 0x87, 0x02, 0xad, 0x02, 0xda, 0x9f, 0x80, 0x7a, 0x49, 0xfc, 0xff, 0x7c, 0xb3, 0xd4,
+	0xee, 0x94, 0x5a, 0xf7, 0x3e, 0xd0, 0x9f, 0x30, 0xc9, 0xc0, 0x85, 0x49, 0xbf, 0x06, 0xda, 0x2d,
+	0xf7, 0xe3, 0xb5, 0xa9, 0xa2, 0x74, 0xf6, 0x01, 0x43, 0x7d, 0x5c, 0x1f, 0x93, 0x1c, 0xec, 0x57,
+	0x8d, 0x17, 0xf1, 0x24, 0x44, 0x5f, 0xc0, 0x77, 0xc2, 0x88, 0xa1, 0xb7, 0xbd, 0xb4, 0x55, 0x31,
+	0x63, 0xbe, 0x9b, 0x9b, 0x31, 0x86, 0x7f, 0x0b, 0xdd, 0x6f, 0x82, 0xa2, 0xcb, 0x7e, 0xb6, 0xd4,
+	0x53, 0x4a, 0x7a, 0xea, 0xe2, 0xcd, 0x8a, 0x7a, 0xca, 0xf7, 0x18, 0x82, 0x96, 0x92, 0x4d, 0x0c,
+	0x0a, 0x32, 0x10, 0xff, 0x1d, 0x00, 0xb5, 0xfe, 0xea, 0x57, 0x3b, 0x32, 0x00, 0x00,
 }
@@ -1,6 +1,7 @@
 package meta
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -9,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/freetsdb/freetsdb/tcp"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/raft-boltdb"
@@ -32,7 +34,10 @@ type raftState struct {
 	transport *raft.NetworkTransport
 	raftStore *raftboltdb.BoltStore
 	raftLayer *raftLayer
+	dialer    *tcp.Dialer
 	ln        net.Listener
+	conns     chan net.Conn
+	done      chan struct{}
 	addr      string
 	logger    hclog.Logger
 	path      string
@@ -51,6 +56,33 @@ func newRaftState(c *Config, addr string) *raftState {
 
 func (r *raftState) open(s *store, ln net.Listener) error {
 	r.ln = ln
+	r.conns = make(chan net.Conn)
+	r.done = make(chan struct{})
+	go r.accept()
+
+	return r.openRaft(s, true)
+}
+
+// accept hands the connections of the raft listener to the current raft
+// layer, so raft can be restarted without giving up the listener.
+func (r *raftState) accept() {
+	for {
+		conn, err := r.ln.Accept()
+		if err != nil {
+			return
+		}
+		select {
+		case r.conns <- conn:
+		case <-r.done:
+			conn.Close()
+			return
+		}
+	}
+}
+
+// openRaft starts raft on top of the raft listener. A node without a raft
+// log bootstraps a cluster of its own when bootstrap is true.
+func (r *raftState) openRaft(s *store, bootstrap bool) error {
 	r.closing = make(chan struct{})
 
 	// Setup raft configuration.
@@ -72,7 +104,7 @@ func (r *raftState) open(s *store, ln net.Listener) error {
 	config.ShutdownOnRemove = false
 
 	// Build raft layer to multiplex listener.
-	r.raftLayer = newRaftLayer(r.addr, r.ln)
+	r.raftLayer = newRaftLayer(r.addr, r.conns, r.dialer)
 
 	// Create a transport layer
 	r.transport = raft.NewNetworkTransport(r.raftLayer, 3, 10*time.Second, config.LogOutput)
@@ -96,7 +128,7 @@ func (r *raftState) open(s *store, ln net.Listener) error {
 	}
 	r.raft = ra
 
-	if newNode {
+	if newNode && bootstrap {
 		r.logger.Info("bootstrap needed")
 		configuration := raft.Configuration{
 			Servers: []raft.Server{
@@ -140,6 +172,33 @@ func (r *raftState) close() error {
 	if r == nil {
 		return nil
 	}
+	err := r.closeRaft()
+
+	if r.done != nil {
+		close(r.done)
+		r.ln.Close()
+	}
+	return err
+}
+
+// reset drops the raft log, snapshots and configuration of this node and
+// restarts raft without bootstrapping, so the node can be added to another
+// cluster and receive its log from the start.
+func (r *raftState) reset(s *store) error {
+	if err := r.closeRaft(); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(r.path, "raft.db")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(r.path, "snapshots")); err != nil {
+		return err
+	}
+	return r.openRaft(s, false)
+}
+
+// closeRaft shuts down raft, leaving the raft listener open.
+func (r *raftState) closeRaft() error {
 	if r.closing != nil {
 		close(r.closing)
 	}
@@ -272,8 +331,9 @@ func (r *raftState) isLeader() bool {
 // raftLayer wraps the connection so it can be re-used for forwarding.
 type raftLayer struct {
 	addr   *raftLayerAddr
-	ln     net.Listener
-	conn   chan net.Conn
+	dialer *tcp.Dialer
+	conn   <-chan net.Conn
+	once   sync.Once
 	closed chan struct{}
 }
 
@@ -290,11 +350,11 @@ func (r *raftLayerAddr) String() string {
 }
 
 // newRaftLayer returns a new instance of raftLayer.
-func newRaftLayer(addr string, ln net.Listener) *raftLayer {
+func newRaftLayer(addr string, conn <-chan net.Conn, dialer *tcp.Dialer) *raftLayer {
 	return &raftLayer{
 		addr:   &raftLayerAddr{addr},
-		ln:     ln,
-		conn:   make(chan net.Conn),
+		dialer: dialer,
+		conn:   conn,
 		closed: make(chan struct{}),
 	}
 }
@@ -306,24 +366,24 @@ func (l *raftLayer) Addr() net.Addr {
 
 // Dial creates a new network connection.
 func (l *raftLayer) Dial(addr raft.ServerAddress, timeout time.Duration) (net.Conn, error) {
-	conn, err := net.DialTimeout("tcp", string(addr), timeout)
-	if err != nil {
-		return nil, err
-	}
-	// Write a marker byte for raft messages.
-	_, err = conn.Write([]byte{MuxHeader})
-	if err != nil {
-		conn.Close()
-		return nil, err
-	}
-	return conn, err
+	return l.dialer.DialTimeout("tcp", string(addr), MuxHeader, timeout)
 }
 
 // Accept waits for the next connection.
-func (l *raftLayer) Accept() (net.Conn, error) { return l.ln.Accept() }
+func (l *raftLayer) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conn:
+		return conn, nil
+	case <-l.closed:
+		return nil, errors.New("raft layer closed")
+	}
+}
 
-// Close closes the layer.
-func (l *raftLayer) Close() error { return l.ln.Close() }
+// Close closes the layer. The raft listener is left open.
+func (l *raftLayer) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
 
 func (r *raftState) Peers() ([]string, error) {
 
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/freetsdb/freetsdb"
+	"github.com/freetsdb/freetsdb/tcp"
 	"go.uber.org/zap"
 )
 
@@ -20,12 +21,16 @@ const (
 type Service struct {
 	RaftListener net.Listener
 
+	// Dialer connects to the raft port of the other meta nodes.
+	Dialer *tcp.Dialer
+
 	Version string
 
 	mu       sync.RWMutex
 	config   *Config
 	handler  *handler
 	ln       net.Listener
+	server   *http.Server
 	httpAddr string
 	raftAddr string
 	https    bool
@@ -120,11 +125,13 @@ func (s *Service) Open() error {
 	// Open the store.  The addresses passed in are remotely accessible.
 	s.store = newStore(s.config, s.remoteAddr(s.httpAddr), s.remoteAddr(s.raftAddr))
 	s.store.node = s.Node
+	s.store.dialer = s.Dialer
 
 	handler := newHandler(s.config, s)
 	handler.WithLogger(s.Logger)
 	handler.store = s.store
 	s.handler = handler
+	s.server = &http.Server{Handler: handler}
 
 	// Begin listening for requests in a separate goroutine.
 	go s.serve()
@@ -152,7 +159,7 @@ func (s *Service) remoteAddr(addr string) string {
 func (s *Service) serve() {
 	// The listener was closed so exit
 	// See https://github.com/golang/go/issues/4373
-	err := http.Serve(s.ln, s.handler)
+	err := s.server.Serve(s.ln)
 	if err != nil && !strings.Contains(err.Error(), "closed") {
 		s.err <- fmt.Errorf("listener failed: addr=%s, err=%s", s.ln.Addr(), err)
 	}
@@ -168,8 +175,10 @@ func (s *Service) Close() error {
 		return err
 	}
 
-	if s.ln != nil {
-		if err := s.ln.Close(); err != nil {
+	// Closing the server also drops idle keep-alive connections, which
+	// would otherwise keep reaching the closed handler.
+	if s.server != nil {
+		if err := s.server.Close(); err != nil {
 			return err
 		}
 	}
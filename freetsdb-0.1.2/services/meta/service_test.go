@@ -1,10 +1,12 @@
 package meta_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net"
+	"net/http"
 	"os"
 	"path"
 	"reflect"
@@ -34,18 +36,18 @@ func TestMetaService_CreateDatabase(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	db, err := c.Database("db0")
-	if err != nil {
-		t.Fatal(err)
+	db := c.Database("db0")
+	if db == nil {
+		t.Fatal("database not found")
 	} else if db.Name != "db0" {
 		t.Fatalf("db name wrong: %s", db.Name)
 	}
 
 	// Make sure a default retention policy was created.
-	_, err = c.RetentionPolicy("db0", "default")
+	_, err := c.RetentionPolicy("db0", "autogen")
 	if err != nil {
 		t.Fatal(err)
-	} else if db.DefaultRetentionPolicy != "default" {
+	} else if db.DefaultRetentionPolicy != "autogen" {
 		t.Fatalf("rp name wrong: %s", db.DefaultRetentionPolicy)
 	}
 }
@@ -62,9 +64,9 @@ func TestMetaService_CreateDatabaseIfNotExists(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	db, err := c.Database("db0")
-	if err != nil {
-		t.Fatal(err)
+	db := c.Database("db0")
+	if db == nil {
+		t.Fatal("database not found")
 	} else if db.Name != "db0" {
 		t.Fatalf("db name wrong: %s", db.Name)
 	}
@@ -90,16 +92,16 @@ func TestMetaService_CreateDatabaseWithRetentionPolicy(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	db, err := c.Database("db0")
-	if err != nil {
-		t.Fatal(err)
+	db := c.Database("db0")
+	if db == nil {
+		t.Fatal("database not found")
 	} else if db.Name != "db0" {
 		t.Fatalf("db name wrong: %s", db.Name)
 	}
 
 	rp := db.RetentionPolicy("rp0")
-	if err != nil {
-		t.Fatal(err)
+	if rp == nil {
+		t.Fatal("retention policy not found")
 	} else if rp.Name != "rp0" {
 		t.Fatalf("rp name wrong: %s", rp.Name)
 	} else if rp.Duration != time.Hour {
@@ -157,9 +159,9 @@ func TestMetaService_DropDatabase(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	db, err := c.Database("db0")
-	if err != nil {
-		t.Fatal(err)
+	db := c.Database("db0")
+	if db == nil {
+		t.Fatal("database not found")
 	} else if db.Name != "db0" {
 		t.Fatalf("db name wrong: %s", db.Name)
 	}
@@ -168,7 +170,7 @@ func TestMetaService_DropDatabase(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if db, _ = c.Database("db0"); db != nil {
+	if db = c.Database("db0"); db != nil {
 		t.Fatalf("expected database to not return: %v", db)
 	}
 
@@ -190,9 +192,9 @@ func TestMetaService_CreateRetentionPolicy(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	db, err := c.Database("db0")
-	if err != nil {
-		t.Fatal(err)
+	db := c.Database("db0")
+	if db == nil {
+		t.Fatal("database not found")
 	} else if db.Name != "db0" {
 		t.Fatalf("db name wrong: %s", db.Name)
 	}
@@ -253,9 +255,9 @@ func TestMetaService_SetDefaultRetentionPolicy(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	db, err := c.Database("db0")
-	if err != nil {
-		t.Fatal(err)
+	db := c.Database("db0")
+	if db == nil {
+		t.Fatal("database not found")
 	} else if db.Name != "db0" {
 		t.Fatalf("db name wrong: %s", db.Name)
 	}
@@ -289,9 +291,9 @@ func TestMetaService_DropRetentionPolicy(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	db, err := c.Database("db0")
-	if err != nil {
-		t.Fatal(err)
+	db := c.Database("db0")
+	if db == nil {
+		t.Fatal("database not found")
 	} else if db.Name != "db0" {
 		t.Fatalf("db name wrong: %s", db.Name)
 	}
@@ -349,15 +351,15 @@ func TestMetaService_CreateUser(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if exp, got := "fred", u.Name; exp != got {
+	if exp, got := "fred", u.ID(); exp != got {
 		t.Fatalf("unexpected user name: exp: %s got: %s", exp, got)
 	}
-	if !u.Admin {
+	if !isAdmin(u) {
 		t.Fatalf("expected user to be admin")
 	}
 
 	u, err = c.Authenticate("fred", "supersecure")
-	if u == nil || err != nil || u.Name != "fred" {
+	if u == nil || err != nil || u.ID() != "fred" {
 		t.Fatalf("failed to authenticate")
 	}
 
@@ -386,7 +388,7 @@ func TestMetaService_CreateUser(t *testing.T) {
 
 	// Auth for new password should succeed.
 	u, err = c.Authenticate("fred", "moresupersecure")
-	if u == nil || err != nil || u.Name != "fred" {
+	if u == nil || err != nil || u.ID() != "fred" {
 		t.Fatalf("failed to authenticate")
 	}
 
@@ -400,10 +402,10 @@ func TestMetaService_CreateUser(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if exp, got := "wilma", u.Name; exp != got {
+	if exp, got := "wilma", u.ID(); exp != got {
 		t.Fatalf("unexpected user name: exp: %s got: %s", exp, got)
 	}
-	if u.Admin {
+	if isAdmin(u) {
 		t.Fatalf("expected user not to be an admin")
 	}
 
@@ -420,10 +422,10 @@ func TestMetaService_CreateUser(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if exp, got := "wilma", u.Name; exp != got {
+	if exp, got := "wilma", u.ID(); exp != got {
 		t.Fatalf("unexpected user name: exp: %s got: %s", exp, got)
 	}
-	if !u.Admin {
+	if !isAdmin(u) {
 		t.Fatalf("expected user to be an admin")
 	}
 
@@ -436,10 +438,10 @@ func TestMetaService_CreateUser(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if exp, got := "wilma", u.Name; exp != got {
+	if exp, got := "wilma", u.ID(); exp != got {
 		t.Fatalf("unexpected user name: exp: %s got: %s", exp, got)
 	}
-	if u.Admin {
+	if isAdmin(u) {
 		t.Fatalf("expected user not to be an admin")
 	}
 
@@ -448,9 +450,9 @@ func TestMetaService_CreateUser(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	db, err := c.Database("db0")
-	if err != nil {
-		t.Fatal(err)
+	db := c.Database("db0")
+	if db == nil {
+		t.Fatal("database not found")
 	} else if db.Name != "db0" {
 		t.Fatalf("db name wrong: %s", db.Name)
 	}
@@ -513,9 +515,9 @@ func TestMetaService_ContinuousQueries(t *testing.T) {
 	if _, err := c.CreateDatabase("db0"); err != nil {
 		t.Fatal(err)
 	}
-	db, err := c.Database("db0")
-	if err != nil {
-		t.Fatal(err)
+	db := c.Database("db0")
+	if db == nil {
+		t.Fatal("database not found")
 	} else if db.Name != "db0" {
 		t.Fatalf("db name wrong: %s", db.Name)
 	}
@@ -556,25 +558,25 @@ func TestMetaService_Subscriptions_Create(t *testing.T) {
 	if _, err := c.CreateDatabase("db0"); err != nil {
 		t.Fatal(err)
 	}
-	db, err := c.Database("db0")
-	if err != nil {
-		t.Fatal(err)
+	db := c.Database("db0")
+	if db == nil {
+		t.Fatal("database not found")
 	} else if db.Name != "db0" {
 		t.Fatalf("db name wrong: %s", db.Name)
 	}
 
 	// Create a subscription
-	if err := c.CreateSubscription("db0", "default", "sub0", "ALL", []string{"udp://example.com:9090"}); err != nil {
+	if err := c.CreateSubscription("db0", "autogen", "sub0", "ALL", []string{"udp://example.com:9090"}); err != nil {
 		t.Fatal(err)
 	}
 
 	// Re-create a subscription
-	if err := c.CreateSubscription("db0", "default", "sub0", "ALL", []string{"udp://example.com:9090"}); err == nil || err.Error() != `subscription already exists` {
+	if err := c.CreateSubscription("db0", "autogen", "sub0", "ALL", []string{"udp://example.com:9090"}); err == nil || err.Error() != `subscription already exists` {
 		t.Fatalf("unexpected error: %s", err)
 	}
 
 	// Create another subscription.
-	if err := c.CreateSubscription("db0", "default", "sub1", "ALL", []string{"udp://example.com:6060"}); err != nil {
+	if err := c.CreateSubscription("db0", "autogen", "sub1", "ALL", []string{"udp://example.com:6060"}); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -594,19 +596,19 @@ func TestMetaService_Subscriptions_Drop(t *testing.T) {
 
 	// DROP SUBSCRIPTION returns ErrSubscriptionNotFound when the
 	// subscription is unknown.
-	err := c.DropSubscription("db0", "default", "foo")
+	err := c.DropSubscription("db0", "autogen", "foo")
 	if got, exp := err, meta.ErrSubscriptionNotFound; got.Error() != exp.Error() {
 		t.Fatalf("got: %s, exp: %s", got, exp)
 	}
 
 	// Create a subscription.
-	if err := c.CreateSubscription("db0", "default", "sub0", "ALL", []string{"udp://example.com:9090"}); err != nil {
+	if err := c.CreateSubscription("db0", "autogen", "sub0", "ALL", []string{"udp://example.com:9090"}); err != nil {
 		t.Fatal(err)
 	}
 
 	// DROP SUBSCRIPTION returns an freetsdb.ErrDatabaseNotFound when
 	// the database is unknown.
-	err = c.DropSubscription("foo", "default", "sub0")
+	err = c.DropSubscription("foo", "autogen", "sub0")
 	if got, exp := err, freetsdb.ErrDatabaseNotFound("foo"); got.Error() != exp.Error() {
 		t.Fatalf("got: %s, exp: %s", got, exp)
 	}
@@ -619,7 +621,7 @@ func TestMetaService_Subscriptions_Drop(t *testing.T) {
 	}
 
 	// DROP SUBSCRIPTION drops the subsciption if it can find it.
-	err = c.DropSubscription("db0", "default", "sub0")
+	err = c.DropSubscription("db0", "autogen", "sub0")
 	if got := err; got != nil {
 		t.Fatalf("got: %s, exp: %v", got, nil)
 	}
@@ -649,7 +651,7 @@ func TestMetaService_Shards(t *testing.T) {
 
 	// Test creating a shard group.
 	tmin := time.Now()
-	sg, err := c.CreateShardGroup("db0", "default", tmin)
+	sg, err := c.CreateShardGroup("db0", "autogen", tmin)
 	if err != nil {
 		t.Fatal(err)
 	} else if sg == nil {
@@ -664,7 +666,7 @@ func TestMetaService_Shards(t *testing.T) {
 	}
 
 	// Test finding shard groups by time range.
-	groups, err := c.ShardGroupsByTimeRange("db0", "default", tmin, tmax)
+	groups, err := c.ShardGroupsByTimeRange("db0", "autogen", tmin, tmax)
 	if err != nil {
 		t.Fatal(err)
 	} else if len(groups) != 2 {
@@ -675,16 +677,16 @@ func TestMetaService_Shards(t *testing.T) {
 	db, rp, owner := c.ShardOwner(groups[0].Shards[0].ID)
 	if db != "db0" {
 		t.Fatalf("wrong db name: %s", db)
-	} else if rp != "default" {
+	} else if rp != "autogen" {
 		t.Fatalf("wrong rp name: %s", rp)
 	} else if owner.ID != groups[0].ID {
 		t.Fatalf("wrong owner: exp %d got %d", groups[0].ID, owner.ID)
 	}
 
 	// Test deleting a shard group.
-	if err := c.DeleteShardGroup("db0", "default", groups[0].ID); err != nil {
+	if err := c.DeleteShardGroup("db0", "autogen", groups[0].ID); err != nil {
 		t.Fatal(err)
-	} else if groups, err = c.ShardGroupsByTimeRange("db0", "default", tmin, tmax); err != nil {
+	} else if groups, err = c.ShardGroupsByTimeRange("db0", "autogen", tmin, tmax); err != nil {
 		t.Fatal(err)
 	} else if len(groups) != 1 {
 		t.Fatalf("wrong number of shard groups after delete: %d", len(groups))
@@ -706,28 +708,20 @@ func TestMetaService_CreateRemoveMetaNode(t *testing.T) {
 	cfg2.BindAddress = raftPeers[1]
 	defer os.RemoveAll(cfg2.Dir)
 
-	var wg sync.WaitGroup
-	wg.Add(2)
-	cfg1.JoinPeers = joinPeers[0:2]
 	s1 := newService(cfg1)
-	go func() {
-		defer wg.Done()
-		if err := s1.Open(); err != nil {
-			t.Fatal(err)
-		}
-	}()
+	if err := s1.Open(); err != nil {
+		t.Fatal(err)
+	}
 	defer s1.Close()
 
-	cfg2.JoinPeers = joinPeers[0:2]
 	s2 := newService(cfg2)
-	go func() {
-		defer wg.Done()
-		if err := s2.Open(); err != nil {
-			t.Fatal(err)
-		}
-	}()
+	if err := s2.Open(); err != nil {
+		t.Fatal(err)
+	}
 	defer s2.Close()
-	wg.Wait()
+	if err := joinCluster(s2, joinPeers[0:1]); err != nil {
+		t.Fatal(err)
+	}
 
 	cfg3 := newConfig()
 	joinPeers[2] = freePort()
@@ -736,61 +730,62 @@ func TestMetaService_CreateRemoveMetaNode(t *testing.T) {
 	cfg3.BindAddress = raftPeers[2]
 	defer os.RemoveAll(cfg3.Dir)
 
-	cfg3.JoinPeers = joinPeers[0:3]
 	s3 := newService(cfg3)
 	if err := s3.Open(); err != nil {
 		t.Fatal(err)
 	}
 	defer s3.Close()
+	if err := joinCluster(s3, joinPeers[0:2]); err != nil {
+		t.Fatal(err)
+	}
 
-	c1 := meta.NewClient()
+	c1 := meta.NewClient(nil)
 	c1.SetMetaServers(joinPeers[0:3])
 	if err := c1.Open(); err != nil {
 		t.Fatal(err)
 	}
 	defer c1.Close()
 
-	metaNodes, _ := c1.MetaNodes()
-	if len(metaNodes) != 3 {
+	if metaNodes := waitForMetaNodes(c1, 3); len(metaNodes) != 3 {
 		t.Fatalf("meta nodes wrong: %v", metaNodes)
 	}
 
-	c := meta.NewClient()
+	c := meta.NewClient(nil)
 	c.SetMetaServers([]string{s1.HTTPAddr()})
 	if err := c.Open(); err != nil {
 		t.Fatal(err)
 	}
 	defer c.Close()
 
-	if err := c.DeleteMetaNode(3); err != nil {
+	if err := removeMeta(s1, s3.HTTPAddr()); err != nil {
 		t.Fatal(err)
 	}
 
-	metaNodes, _ = c.MetaNodes()
-	if len(metaNodes) != 2 {
+	if metaNodes := waitForMetaNodes(c, 2); len(metaNodes) != 2 {
 		t.Fatalf("meta nodes wrong: %v", metaNodes)
 	}
 
 	cfg4 := newConfig()
 	cfg4.HTTPBindAddress = freePort()
 	cfg4.BindAddress = freePort()
-	cfg4.JoinPeers = []string{joinPeers[0], joinPeers[1], cfg4.HTTPBindAddress}
 	defer os.RemoveAll(cfg4.Dir)
 	s4 := newService(cfg4)
 	if err := s4.Open(); err != nil {
 		t.Fatal(err)
 	}
 	defer s4.Close()
+	if err := joinCluster(s4, joinPeers[0:2]); err != nil {
+		t.Fatal(err)
+	}
 
-	c2 := meta.NewClient()
-	c2.SetMetaServers(cfg4.JoinPeers)
+	c2 := meta.NewClient(nil)
+	c2.SetMetaServers([]string{joinPeers[0], joinPeers[1], s4.HTTPAddr()})
 	if err := c2.Open(); err != nil {
 		t.Fatal(err)
 	}
 	defer c2.Close()
 
-	metaNodes, _ = c2.MetaNodes()
-	if len(metaNodes) != 3 {
+	if metaNodes := waitForMetaNodes(c2, 3); len(metaNodes) != 3 {
 		t.Fatalf("meta nodes wrong: %v", metaNodes)
 	}
 }
@@ -805,37 +800,34 @@ func TestMetaService_CommandAgainstNonLeader(t *testing.T) {
 	srvs := make([]*testService, 3)
 	joinPeers := freePorts(len(cfgs))
 
-	var wg sync.WaitGroup
-	wg.Add(len(cfgs))
-
-	for i, _ := range cfgs {
+	for i := range cfgs {
 		c := newConfig()
 		c.HTTPBindAddress = joinPeers[i]
-		c.JoinPeers = joinPeers
 		cfgs[i] = c
 
 		srvs[i] = newService(c)
-		go func(srv *testService) {
-			defer wg.Done()
-			if err := srv.Open(); err != nil {
-				t.Fatal(err)
-			}
-		}(srvs[i])
+		if err := srvs[i].Open(); err != nil {
+			t.Fatal(err)
+		}
 		defer srvs[i].Close()
 		defer os.RemoveAll(c.Dir)
+
+		if i > 0 {
+			if err := joinCluster(srvs[i], joinPeers[:i]); err != nil {
+				t.Fatal(err)
+			}
+		}
 	}
-	wg.Wait()
 
 	for i := range cfgs {
-		c := meta.NewClient()
+		c := meta.NewClient(nil)
 		c.SetMetaServers([]string{joinPeers[i]})
 		if err := c.Open(); err != nil {
 			t.Fatal(err)
 		}
 		defer c.Close()
 
-		metaNodes, _ := c.MetaNodes()
-		if len(metaNodes) != 3 {
+		if metaNodes := waitForMetaNodes(c, 3); len(metaNodes) != 3 {
 			t.Fatalf("node %d - meta nodes wrong: %v", i, metaNodes)
 		}
 
@@ -843,8 +835,8 @@ func TestMetaService_CommandAgainstNonLeader(t *testing.T) {
 			t.Fatalf("node %d: %s", i, err)
 		}
 
-		if db, err := c.Database(fmt.Sprintf("foo%d", i)); db == nil || err != nil {
-			t.Fatalf("node %d: database foo wasn't created: %s", i, err)
+		if db := c.Database(fmt.Sprintf("foo%d", i)); db == nil {
+			t.Fatalf("node %d: database foo wasn't created", i)
 		}
 	}
 }
@@ -859,30 +851,27 @@ func TestMetaService_FailureAndRestartCluster(t *testing.T) {
 	joinPeers := freePorts(len(cfgs))
 	raftPeers := freePorts(len(cfgs))
 
-	var swg sync.WaitGroup
-	swg.Add(len(cfgs))
-	for i, _ := range cfgs {
+	for i := range cfgs {
 		c := newConfig()
 		c.HTTPBindAddress = joinPeers[i]
 		c.BindAddress = raftPeers[i]
-		c.JoinPeers = joinPeers
 		cfgs[i] = c
 
 		srvs[i] = newService(c)
-		go func(i int, srv *testService) {
-			defer swg.Done()
-			if err := srv.Open(); err != nil {
-				t.Logf("opening server %d", i)
-				t.Fatal(err)
-			}
-		}(i, srvs[i])
-
+		if err := srvs[i].Open(); err != nil {
+			t.Fatalf("error opening server %d: %s", i, err)
+		}
 		defer srvs[i].Close()
 		defer os.RemoveAll(c.Dir)
+
+		if i > 0 {
+			if err := joinCluster(srvs[i], joinPeers[:i]); err != nil {
+				t.Fatal(err)
+			}
+		}
 	}
-	swg.Wait()
 
-	c := meta.NewClient()
+	c := meta.NewClient(nil)
 	c.SetMetaServers(joinPeers)
 	if err := c.Open(); err != nil {
 		t.Fatal(err)
@@ -899,8 +888,8 @@ func TestMetaService_FailureAndRestartCluster(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if db, err := c.Database("foo"); db == nil || err != nil {
-		t.Fatalf("database foo wasn't created: %s", err)
+	if db := c.Database("foo"); db == nil {
+		t.Fatal("database foo wasn't created")
 	}
 
 	if err := srvs[0].Close(); err != nil {
@@ -911,8 +900,8 @@ func TestMetaService_FailureAndRestartCluster(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if db, err := c.Database("bar"); db == nil || err != nil {
-		t.Fatalf("database bar wasn't created: %s", err)
+	if db := c.Database("bar"); db == nil {
+		t.Fatal("database bar wasn't created")
 	}
 
 	if err := srvs[1].Close(); err != nil {
@@ -941,7 +930,7 @@ func TestMetaService_FailureAndRestartCluster(t *testing.T) {
 	wg.Wait()
 	time.Sleep(time.Second)
 
-	c2 := meta.NewClient()
+	c2 := meta.NewClient(nil)
 	c2.SetMetaServers(joinPeers)
 	if err := c2.Open(); err != nil {
 		t.Fatal(err)
@@ -953,16 +942,16 @@ func TestMetaService_FailureAndRestartCluster(t *testing.T) {
 		t.Fatalf("invalid cluster id. got: %d, exp: %d", c2ID, c1ID)
 	}
 
-	if db, err := c2.Database("bar"); db == nil || err != nil {
-		t.Fatalf("database bar wasn't created: %s", err)
+	if db := c2.Database("bar"); db == nil {
+		t.Fatal("database bar wasn't created")
 	}
 
 	if _, err := c2.CreateDatabase("asdf"); err != nil {
 		t.Fatal(err)
 	}
 
-	if db, err := c2.Database("asdf"); db == nil || err != nil {
-		t.Fatalf("database bar wasn't created: %s", err)
+	if db := c2.Database("asdf"); db == nil {
+		t.Fatal("database bar wasn't created")
 	}
 }
 
@@ -983,7 +972,7 @@ func TestMetaService_NameChangeSingleNode(t *testing.T) {
 	}
 	defer s.Close()
 
-	c := meta.NewClient()
+	c := meta.NewClient(nil)
 	c.SetMetaServers([]string{s.HTTPAddr()})
 	if err := c.Open(); err != nil {
 		t.Fatal(err)
@@ -1005,16 +994,16 @@ func TestMetaService_NameChangeSingleNode(t *testing.T) {
 	}
 	defer s.Close()
 
-	c2 := meta.NewClient()
+	c2 := meta.NewClient(nil)
 	c2.SetMetaServers([]string{s.HTTPAddr()})
 	if err := c2.Open(); err != nil {
 		t.Fatal(err)
 	}
 	defer c2.Close()
 
-	db, err := c2.Database("foo")
-	if db == nil || err != nil {
-		t.Fatal(err)
+	db := c2.Database("foo")
+	if db == nil {
+		t.Fatal("database foo wasn't created")
 	}
 
 	nodes, err := c2.MetaNodes()
@@ -1037,8 +1026,10 @@ func TestMetaService_CreateDataNode(t *testing.T) {
 	defer s.Close()
 	defer c.Close()
 
+	// Node IDs are shared with meta nodes and the service's own meta node
+	// already holds ID 1.
 	exp := &meta.NodeInfo{
-		ID:      1,
+		ID:      2,
 		Host:    "foo:8180",
 		TCPHost: "bar:8281",
 	}
@@ -1062,6 +1053,57 @@ func TestMetaService_CreateDataNode(t *testing.T) {
 	}
 }
 
+func TestMetaService_DataNodeHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	d, s, c := newServiceAndClient()
+	defer os.RemoveAll(d)
+	defer s.Close()
+	defer c.Close()
+
+	n, err := c.CreateDataNode("foo:8180", "bar:8281")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first heartbeat marks the node as up.
+	if err := c.DataNodeHeartbeat(n.ID); err != nil {
+		t.Fatal(err)
+	}
+	timeout := time.After(5 * time.Second)
+	for {
+		if ni, err := c.DataNode(n.ID); err != nil {
+			t.Fatal(err)
+		} else if ni.Status == meta.NodeStatusUp {
+			break
+		}
+		select {
+		case <-c.WaitForDataChanged():
+		case <-timeout:
+			t.Fatal("timed out waiting for data node to be marked up")
+		}
+	}
+
+	// Later heartbeats must not be written to the raft log, so the next
+	// command gets the next index.
+	index := c.Data().Index
+	for i := 0; i < 3; i++ {
+		if err := c.DataNodeHeartbeat(n.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := c.Data().Index, index+1; got != exp {
+		t.Fatalf("unexpected index: got %d, exp %d", got, exp)
+	}
+
+	if err := c.DataNodeHeartbeat(n.ID + 100); err != meta.ErrNodeNotFound {
+		t.Fatalf("unexpected error for unknown node: %v", err)
+	}
+}
+
 func TestMetaService_DropDataNode(t *testing.T) {
 	t.Parallel()
 
@@ -1095,7 +1137,7 @@ func TestMetaService_DropDataNode(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	sg, err := c.CreateShardGroup("foo", "default", time.Now())
+	sg, err := c.CreateShardGroup("foo", "autogen", time.Now())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1107,13 +1149,13 @@ func TestMetaService_DropDataNode(t *testing.T) {
 	}
 
 	// Retrieve updated shard group data from the Meta Store.
-	rp, _ := c.RetentionPolicy("foo", "default")
+	rp, _ := c.RetentionPolicy("foo", "autogen")
 	sg = &rp.ShardGroups[0]
 
 	// The first data node should be removed as an owner of the shard on
 	// the shard group
-	if !reflect.DeepEqual(sg.Shards[0].Owners, []meta.ShardOwner{{n2.ID}}) {
-		t.Errorf("owners for shard are %v, expected %v", sg.Shards[0].Owners, []meta.ShardOwner{{2}})
+	if !reflect.DeepEqual(sg.Shards[0].Owners, []meta.ShardOwner{{NodeID: n2.ID}}) {
+		t.Errorf("owners for shard are %v, expected %v", sg.Shards[0].Owners, []meta.ShardOwner{{NodeID: 2}})
 	}
 
 	// The shard group should still be marked as active because it still
@@ -1130,7 +1172,7 @@ func TestMetaService_DropDataNode(t *testing.T) {
 	}
 
 	// Retrieve updated data.
-	rp, _ = c.RetentionPolicy("foo", "default")
+	rp, _ = c.RetentionPolicy("foo", "autogen")
 	sg = &rp.ShardGroups[0]
 
 	if got, exp := sg.Deleted(), true; got != exp {
@@ -1188,8 +1230,8 @@ func TestMetaService_DropDataNode_Reassign(t *testing.T) {
 
 	// The second data node should be the owner of both shards.
 	for _, s := range sg.Shards {
-		if !reflect.DeepEqual(s.Owners, []meta.ShardOwner{{n2.ID}}) {
-			t.Errorf("owners for shard are %v, expected %v", s.Owners, []meta.ShardOwner{{2}})
+		if !reflect.DeepEqual(s.Owners, []meta.ShardOwner{{NodeID: n2.ID}}) {
+			t.Errorf("owners for shard are %v, expected %v", s.Owners, []meta.ShardOwner{{NodeID: 2}})
 		}
 	}
 
@@ -1203,15 +1245,18 @@ func TestMetaService_DropDataNode_Reassign(t *testing.T) {
 func TestMetaService_PersistClusterIDAfterRestart(t *testing.T) {
 	t.Parallel()
 
+	// The restarted service must come back on the same raft address or it
+	// won't find itself in the raft configuration.
 	cfg := newConfig()
+	cfg.BindAddress = freePort()
+	cfg.HTTPBindAddress = freePort()
 	defer os.RemoveAll(cfg.Dir)
 	s := newService(cfg)
 	if err := s.Open(); err != nil {
 		t.Fatal(err)
 	}
-	defer s.Close()
 
-	c := meta.NewClient()
+	c := meta.NewClient(nil)
 	c.SetMetaServers([]string{s.HTTPAddr()})
 	if err := c.Open(); err != nil {
 		t.Fatal(err)
@@ -1220,14 +1265,16 @@ func TestMetaService_PersistClusterIDAfterRestart(t *testing.T) {
 	if id == 0 {
 		t.Fatal("cluster ID can't be zero")
 	}
+	c.Close()
 
 	s.Close()
 	s = newService(cfg)
 	if err := s.Open(); err != nil {
 		t.Fatal(err)
 	}
+	defer s.Close()
 
-	c = meta.NewClient()
+	c = meta.NewClient(nil)
 	c.SetMetaServers([]string{s.HTTPAddr()})
 	if err := c.Open(); err != nil {
 		t.Fatal(err)
@@ -1247,28 +1294,26 @@ func TestMetaService_Ping(t *testing.T) {
 	srvs := make([]*testService, 3)
 	joinPeers := freePorts(len(cfgs))
 
-	var swg sync.WaitGroup
-	swg.Add(len(cfgs))
-
-	for i, _ := range cfgs {
+	for i := range cfgs {
 		c := newConfig()
 		c.HTTPBindAddress = joinPeers[i]
-		c.JoinPeers = joinPeers
 		cfgs[i] = c
 
 		srvs[i] = newService(c)
-		go func(i int, srv *testService) {
-			defer swg.Done()
-			if err := srv.Open(); err != nil {
-				t.Fatalf("error opening server %d: %s", i, err)
-			}
-		}(i, srvs[i])
+		if err := srvs[i].Open(); err != nil {
+			t.Fatalf("error opening server %d: %s", i, err)
+		}
 		defer srvs[i].Close()
 		defer os.RemoveAll(c.Dir)
+
+		if i > 0 {
+			if err := joinCluster(srvs[i], joinPeers[:i]); err != nil {
+				t.Fatal(err)
+			}
+		}
 	}
-	swg.Wait()
 
-	c := meta.NewClient()
+	c := meta.NewClient(nil)
 	c.SetMetaServers(joinPeers)
 	if err := c.Open(); err != nil {
 		t.Fatal(err)
@@ -1365,7 +1410,7 @@ func newServiceAndClient() (string, *testService, *meta.Client) {
 }
 
 func newClient(s *testService) *meta.Client {
-	c := meta.NewClient()
+	c := meta.NewClient(nil)
 	c.SetMetaServers([]string{s.HTTPAddr()})
 	if err := c.Open(); err != nil {
 		panic(err)
@@ -1431,6 +1476,59 @@ func newService(cfg *meta.Config) *testService {
 	return &testService{Service: s, ln: ln}
 }
 
+// joinCluster asks s to join the meta cluster formed by peers, the same way
+// freetsd-ctl add-meta does.
+func joinCluster(s *testService, peers []string) error {
+	b, err := json.Marshal(peers)
+	if err != nil {
+		return err
+	}
+	return postMeta(s, "/join-cluster", b)
+}
+
+// removeMeta asks s to remove the meta node at addr from the cluster, the
+// same way freetsd-ctl remove-meta does.
+func removeMeta(s *testService, addr string) error {
+	b, err := json.Marshal(meta.NodeInfo{Host: addr})
+	if err != nil {
+		return err
+	}
+	return postMeta(s, "/remove-meta", b)
+}
+
+// postMeta posts body to path on the meta service's HTTP API.
+func postMeta(s *testService, path string, body []byte) error {
+	resp, err := http.Post(fmt.Sprintf("http://%s%s", s.HTTPAddr(), path), "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%s: %s: %s", path, resp.Status, strings.TrimSpace(string(b)))
+	}
+	return nil
+}
+
+// waitForMetaNodes waits for the client's cached meta data to hold n meta
+// nodes and returns the nodes it last saw.
+func waitForMetaNodes(c *meta.Client, n int) []meta.NodeInfo {
+	timeout := time.After(5 * time.Second)
+	for {
+		nodes, _ := c.MetaNodes()
+		if len(nodes) == n {
+			return nodes
+		}
+
+		select {
+		case <-c.WaitForDataChanged():
+		case <-timeout:
+			return nodes
+		}
+	}
+}
+
 func mustParseStatement(s string) influxql.Statement {
 	stmt, err := influxql.ParseStatement(s)
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/freetsdb/freetsdb"
 	"github.com/freetsdb/freetsdb/services/meta/internal"
+	"github.com/freetsdb/freetsdb/tcp"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/hashicorp/raft"
@@ -48,7 +49,13 @@ type store struct {
 	raftAddr string
 	httpAddr string
 
-	node *freetsdb.Node
+	node   *freetsdb.Node
+	dialer *tcp.Dialer
+
+	// lastSeen holds the time of the last heartbeat of each data node. It is
+	// only kept by the leader and never written to raft.
+	seenMu   sync.Mutex
+	lastSeen map[uint64]time.Time
 }
 
 // newStore will create a new metastore with the passed in config
@@ -63,6 +70,7 @@ func newStore(c *Config, httpAddr, raftAddr string) *store {
 		config:      c,
 		httpAddr:    httpAddr,
 		raftAddr:    raftAddr,
+		lastSeen:    make(map[uint64]time.Time),
 	}
 	if c.LoggingEnabled {
 		s.logger = log.New(os.Stderr, "[metastore] ", log.LstdFlags)
@@ -115,6 +123,8 @@ func (s *store) open(raftln net.Listener) error {
 		}
 	}
 
+	go s.checkDataNodes()
+
 	return nil
 }
 
@@ -148,6 +158,7 @@ func (s *store) openRaft(raftln net.Listener) error {
 	defer s.mu.Unlock()
 	rs := newRaftState(s.config, s.raftAddr)
 	rs.path = s.path
+	rs.dialer = s.dialer
 
 	if err := rs.open(s, raftln); err != nil {
 		return err
@@ -157,6 +168,20 @@ func (s *store) openRaft(raftln net.Listener) error {
 	return nil
 }
 
+// resetRaft restarts raft with an empty log and clears the meta data.
+func (s *store) resetRaft() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logger.Printf("Dropping the local raft log before joining the cluster")
+	if err := s.raftState.reset(s); err != nil {
+		return fmt.Errorf("raft: %s", err)
+	}
+	s.data = &Data{Index: 1}
+
+	return nil
+}
+
 func (s *store) close() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -397,6 +422,15 @@ func (s *store) removeMeta(host string) (*NodeInfo, error) {
 func (s *store) joinCluster(peers []string) (*NodeInfo, error) {
 
 	if len(peers) > 0 {
+		// A node that is still a cluster of its own drops its raft log
+		// first, otherwise the entries it bootstrapped itself would be
+		// kept instead of the ones of the cluster it joins.
+		if len(s.peers()) <= 1 {
+			if err := s.resetRaft(); err != nil {
+				return nil, err
+			}
+		}
+
 		c := NewClient(nil)
 		c.SetMetaServers(peers)
 		c.SetTLS(s.config.HTTPSEnabled)
@@ -466,6 +500,109 @@ func (s *store) removeMetaNode(id uint64) error {
 	return s.apply(b)
 }
 
+// checkDataNodes periodically marks the data nodes that have not sent a
+// heartbeat within the data node timeout as down. Only the leader changes
+// the status of a node.
+func (s *store) checkDataNodes() {
+	timeout := time.Duration(s.config.DataNodeTimeout)
+	if timeout <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(timeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+		}
+
+		if !s.isLeader() {
+			// Heartbeats go to the leader, so what was recorded while this
+			// node last led is stale by the time it leads again.
+			s.seenMu.Lock()
+			s.lastSeen = make(map[uint64]time.Time)
+			s.seenMu.Unlock()
+			continue
+		}
+
+		data, _ := s.snapshot()
+		now := time.Now()
+		for _, n := range data.DataNodes {
+			if n.Status != NodeStatusUp {
+				continue
+			}
+
+			seen := s.dataNodeLastSeen(n.ID, now)
+			if now.Sub(seen) < timeout {
+				continue
+			}
+
+			s.logger.Printf("data node %d not seen since %s, marking it down", n.ID, seen)
+			if err := s.setDataNodeStatus(n.ID, NodeStatusDown, seen); err != nil {
+				s.logger.Printf("failed to mark data node %d down: %s", n.ID, err)
+			}
+		}
+	}
+}
+
+// dataNodeHeartbeat records a heartbeat from a data node. Heartbeats are
+// only kept in memory; raft is used only when a node comes back up.
+func (s *store) dataNodeHeartbeat(id uint64) error {
+	data, _ := s.snapshot()
+	n := data.DataNode(id)
+	if n == nil {
+		return ErrNodeNotFound
+	}
+
+	now := time.Now().UTC()
+	s.seenMu.Lock()
+	s.lastSeen[id] = now
+	s.seenMu.Unlock()
+
+	if n.Status == NodeStatusUp {
+		return nil
+	}
+	return s.setDataNodeStatus(id, NodeStatusUp, now)
+}
+
+// dataNodeLastSeen returns the time of the last heartbeat of a data node.
+// A node without one is given until now, so after a leader change every
+// node has a full timeout to check in with the new leader.
+func (s *store) dataNodeLastSeen(id uint64, now time.Time) time.Time {
+	s.seenMu.Lock()
+	defer s.seenMu.Unlock()
+	t, ok := s.lastSeen[id]
+	if !ok {
+		t = now
+		s.lastSeen[id] = t
+	}
+	return t
+}
+
+// setDataNodeStatus sets the status of a data node and the time it was
+// last seen.
+func (s *store) setDataNodeStatus(id uint64, status string, lastSeen time.Time) error {
+	val := &internal.SetDataNodeStatusCommand{
+		ID:       proto.Uint64(id),
+		Status:   proto.String(status),
+		LastSeen: proto.Int64(lastSeen.UnixNano()),
+	}
+	t := internal.Command_SetDataNodeStatusCommand
+	cmd := &internal.Command{Type: &t}
+	if err := proto.SetExtension(cmd, internal.E_SetDataNodeStatusCommand_Command, val); err != nil {
+		panic(err)
+	}
+
+	b, err := proto.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+
+	return s.apply(b)
+}
+
 // setMetaNode is used when the raft group has only a single peer. It will
 // either create a metanode or update the information for the one metanode
 // that is there. It's used because hostnames can change
@@ -88,6 +88,40 @@ func (fsm *storeFSM) Apply(l *raft.Log) interface{} {
 			return fsm.applyCreateDataNodeCommand(&cmd)
 		case internal.Command_DeleteDataNodeCommand:
 			return fsm.applyDeleteDataNodeCommand(&cmd)
+		case internal.Command_AddShardOwnerCommand:
+			return fsm.applyAddShardOwnerCommand(&cmd)
+		case internal.Command_RemoveShardOwnerCommand:
+			return fsm.applyRemoveShardOwnerCommand(&cmd)
+		case internal.Command_SetDataNodeStatusCommand:
+			return fsm.applySetDataNodeStatusCommand(&cmd)
+		case internal.Command_SetDatabaseConsistencyCommand:
+			return fsm.applySetDatabaseConsistencyCommand(&cmd)
+		case internal.Command_SetContinuousQueryLastRunCommand:
+			return fsm.applySetContinuousQueryLastRunCommand(&cmd)
+		case internal.Command_SetContinuousQueryStatusCommand:
+			return fsm.applySetContinuousQueryStatusCommand(&cmd)
+		case internal.Command_CreateDownsampleCommand:
+			return fsm.applyCreateDownsampleCommand(&cmd)
+		case internal.Command_DropDownsampleCommand:
+			return fsm.applyDropDownsampleCommand(&cmd)
+		case internal.Command_SetDownsampleLastRunCommand:
+			return fsm.applySetDownsampleLastRunCommand(&cmd)
+		case internal.Command_SetShardOwnerTierCommand:
+			return fsm.applySetShardOwnerTierCommand(&cmd)
+		case internal.Command_ArchiveShardCommand:
+			return fsm.applyArchiveShardCommand(&cmd)
+		case internal.Command_UnarchiveShardCommand:
+			return fsm.applyUnarchiveShardCommand(&cmd)
+		case internal.Command_UndropShardGroupCommand:
+			return fsm.applyUndropShardGroupCommand(&cmd)
+		case internal.Command_TrashCommand:
+			return fsm.applyTrashCommand(&cmd)
+		case internal.Command_UndropCommand:
+			return fsm.applyUndropCommand(&cmd)
+		case internal.Command_PruneDroppedCommand:
+			return fsm.applyPruneDroppedCommand(&cmd)
+		case internal.Command_SetTrashEnabledCommand:
+			return fsm.applySetTrashEnabledCommand(&cmd)
 		default:
 			panic(fmt.Errorf("cannot apply command: %x", l.Data))
 		}
@@ -214,6 +248,11 @@ func (fsm *storeFSM) applyCreateDatabaseCommand(cmd *internal.Command) interface
 			ReplicaN:           int(rpi.GetReplicaN()),
 			Duration:           time.Duration(rpi.GetDuration()),
 			ShardGroupDuration: time.Duration(rpi.GetShardGroupDuration()),
+			Consistency:        rpi.GetConsistency(),
+			ColdAfter:          time.Duration(rpi.GetColdAfter()),
+			DuplicatePolicy:    rpi.GetDuplicatePolicy(),
+			StringCodec:        rpi.GetStringCodec(),
+			FloatCodec:         rpi.GetFloatCodec(),
 		}, false); err != nil {
 			if err == ErrRetentionPolicyExists {
 				return ErrRetentionPolicyConflict
@@ -281,6 +320,11 @@ func (fsm *storeFSM) applyCreateRetentionPolicyCommand(cmd *internal.Command) in
 			ReplicaN:           int(pb.GetReplicaN()),
 			Duration:           time.Duration(pb.GetDuration()),
 			ShardGroupDuration: time.Duration(pb.GetShardGroupDuration()),
+			Consistency:        pb.GetConsistency(),
+			ColdAfter:          time.Duration(pb.GetColdAfter()),
+			DuplicatePolicy:    pb.GetDuplicatePolicy(),
+			StringCodec:        pb.GetStringCodec(),
+			FloatCodec:         pb.GetFloatCodec(),
 		}, false); err != nil {
 		return err
 	}
@@ -331,6 +375,30 @@ func (fsm *storeFSM) applyUpdateRetentionPolicyCommand(cmd *internal.Command) in
 		value := int(v.GetReplicaN())
 		rpu.ReplicaN = &value
 	}
+	if v.Consistency != nil {
+		value := v.GetConsistency()
+		rpu.Consistency = &value
+	}
+	if v.ColdAfter != nil {
+		value := time.Duration(v.GetColdAfter())
+		rpu.ColdAfter = &value
+	}
+	if v.DuplicatePolicy != nil {
+		value := v.GetDuplicatePolicy()
+		rpu.DuplicatePolicy = &value
+	}
+	if v.StringCodec != nil {
+		value := v.GetStringCodec()
+		rpu.StringCodec = &value
+	}
+	if v.FloatCodec != nil {
+		value := v.GetFloatCodec()
+		rpu.FloatCodec = &value
+	}
+	if v.ShardGroupDuration != nil {
+		value := time.Duration(v.GetShardGroupDuration())
+		rpu.ShardGroupDuration = &value
+	}
 
 	// Copy data and update.
 	other := fsm.data.Clone()
@@ -362,7 +430,11 @@ func (fsm *storeFSM) applyDeleteShardGroupCommand(cmd *internal.Command) interfa
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-	if err := other.DeleteShardGroup(v.GetDatabase(), v.GetPolicy(), v.GetShardGroupID()); err != nil {
+	if v.PurgeAt != nil {
+		if err := other.ExpireShardGroup(v.GetDatabase(), v.GetPolicy(), v.GetShardGroupID(), UnmarshalTime(v.GetPurgeAt())); err != nil {
+			return err
+		}
+	} else if err := other.DeleteShardGroup(v.GetDatabase(), v.GetPolicy(), v.GetShardGroupID()); err != nil {
 		return err
 	}
 	fsm.data = other
@@ -581,6 +653,249 @@ func (fsm *storeFSM) applyDeleteDataNodeCommand(cmd *internal.Command) interface
 	return nil
 }
 
+func (fsm *storeFSM) applyAddShardOwnerCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_AddShardOwnerCommand_Command)
+	v := ext.(*internal.AddShardOwnerCommand)
+
+	other := fsm.data.Clone()
+	if err := other.AddShardOwner(v.GetID(), v.GetNodeID()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyRemoveShardOwnerCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_RemoveShardOwnerCommand_Command)
+	v := ext.(*internal.RemoveShardOwnerCommand)
+
+	other := fsm.data.Clone()
+	if err := other.RemoveShardOwner(v.GetID(), v.GetNodeID()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applySetDataNodeStatusCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetDataNodeStatusCommand_Command)
+	v := ext.(*internal.SetDataNodeStatusCommand)
+
+	var lastSeen time.Time
+	if v.LastSeen != nil {
+		lastSeen = time.Unix(0, v.GetLastSeen())
+	}
+
+	other := fsm.data.Clone()
+	if err := other.SetDataNodeStatus(v.GetID(), v.GetStatus(), lastSeen); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applySetDatabaseConsistencyCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetDatabaseConsistencyCommand_Command)
+	v := ext.(*internal.SetDatabaseConsistencyCommand)
+
+	other := fsm.data.Clone()
+	if err := other.SetDatabaseConsistency(v.GetName(), v.GetConsistency()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applySetContinuousQueryLastRunCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetContinuousQueryLastRunCommand_Command)
+	v := ext.(*internal.SetContinuousQueryLastRunCommand)
+
+	other := fsm.data.Clone()
+	if err := other.SetContinuousQueryLastRun(v.GetDatabase(), v.GetName(), UnmarshalTime(v.GetLastRun())); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applySetContinuousQueryStatusCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetContinuousQueryStatusCommand_Command)
+	v := ext.(*internal.SetContinuousQueryStatusCommand)
+
+	status := ContinuousQueryStatus{
+		NodeID:        v.GetNodeID(),
+		Time:          UnmarshalTime(v.GetTime()),
+		Duration:      time.Duration(v.GetDuration()),
+		StartTime:     UnmarshalTime(v.GetStartTime()),
+		EndTime:       UnmarshalTime(v.GetEndTime()),
+		PointsWritten: v.GetPointsWritten(),
+		Err:           v.GetError(),
+		LastRun:       UnmarshalTime(v.GetLastRun()),
+	}
+
+	other := fsm.data.Clone()
+	if err := other.SetContinuousQueryStatus(v.GetDatabase(), v.GetName(), status); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyCreateDownsampleCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_CreateDownsampleCommand_Command)
+	v := ext.(*internal.CreateDownsampleCommand)
+
+	other := fsm.data.Clone()
+	if err := other.CreateDownsample(v.GetDatabase(), v.GetRetentionPolicy(), v.GetTarget(), time.Duration(v.GetInterval()), v.GetAggregates()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyDropDownsampleCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_DropDownsampleCommand_Command)
+	v := ext.(*internal.DropDownsampleCommand)
+
+	other := fsm.data.Clone()
+	if err := other.DropDownsample(v.GetDatabase(), v.GetRetentionPolicy(), v.GetTarget()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applySetDownsampleLastRunCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetDownsampleLastRunCommand_Command)
+	v := ext.(*internal.SetDownsampleLastRunCommand)
+
+	other := fsm.data.Clone()
+	if err := other.SetDownsampleLastRun(v.GetDatabase(), v.GetRetentionPolicy(), v.GetTarget(), time.Unix(0, v.GetLastRun())); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applySetShardOwnerTierCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetShardOwnerTierCommand_Command)
+	v := ext.(*internal.SetShardOwnerTierCommand)
+
+	other := fsm.data.Clone()
+	if err := other.SetShardOwnerTier(v.GetID(), v.GetNodeID(), v.GetTier()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyArchiveShardCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_ArchiveShardCommand_Command)
+	v := ext.(*internal.ArchiveShardCommand)
+
+	other := fsm.data.Clone()
+	if err := other.ArchiveShard(v.GetID(), UnmarshalTime(v.GetArchivedAt())); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyUnarchiveShardCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_UnarchiveShardCommand_Command)
+	v := ext.(*internal.UnarchiveShardCommand)
+
+	other := fsm.data.Clone()
+	if err := other.UnarchiveShard(v.GetID(), v.GetNodeID()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyUndropShardGroupCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_UndropShardGroupCommand_Command)
+	v := ext.(*internal.UndropShardGroupCommand)
+
+	other := fsm.data.Clone()
+	if err := other.UndropShardGroup(v.GetID(), UnmarshalTime(v.GetTimestamp())); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyTrashCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_TrashCommand_Command)
+	v := ext.(*internal.TrashCommand)
+
+	other := fsm.data.Clone()
+	t := UnmarshalTime(v.GetDroppedAt())
+	switch v.GetType() {
+	case DroppedDatabase:
+		if err := other.TrashDatabase(v.GetDatabase(), t); err != nil {
+			return err
+		}
+	case DroppedMeasurement:
+		if err := other.TrashMeasurement(v.GetDatabase(), v.GetName(), t); err != nil {
+			return err
+		}
+	case DroppedShard:
+		if err := other.TrashShard(v.GetShardID(), t); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown dropped type: %q", v.GetType())
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyUndropCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_UndropCommand_Command)
+	v := ext.(*internal.UndropCommand)
+
+	other := fsm.data.Clone()
+	switch v.GetType() {
+	case DroppedDatabase:
+		if err := other.UndropDatabase(v.GetDatabase()); err != nil {
+			return err
+		}
+	case DroppedMeasurement:
+		if err := other.UndropMeasurement(v.GetDatabase(), v.GetName()); err != nil {
+			return err
+		}
+	case DroppedShard:
+		if err := other.UndropShard(v.GetShardID()); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown dropped type: %q", v.GetType())
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyPruneDroppedCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_PruneDroppedCommand_Command)
+	v := ext.(*internal.PruneDroppedCommand)
+
+	other := fsm.data.Clone()
+	other.PruneDropped(UnmarshalTime(v.GetBefore()))
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applySetTrashEnabledCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetTrashEnabledCommand_Command)
+	v := ext.(*internal.SetTrashEnabledCommand)
+
+	other := fsm.data.Clone()
+	other.TrashEnabled = v.GetEnabled()
+	fsm.data = other
+	return nil
+}
+
 func (fsm *storeFSM) Snapshot() (raft.FSMSnapshot, error) {
 	s := (*store)(fsm)
 	s.mu.Lock()
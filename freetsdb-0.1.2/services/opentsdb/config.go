@@ -19,7 +19,9 @@ const (
 	DefaultRetentionPolicy = ""
 
 	// DefaultConsistencyLevel is the default write consistency level.
-	DefaultConsistencyLevel = "one"
+	// An empty level uses the consistency level of the retention policy or
+	// database, or any if neither sets one.
+	DefaultConsistencyLevel = ""
 
 	// DefaultBatchSize is the default OpenTSDB batch size.
 	DefaultBatchSize = 1000
@@ -79,9 +81,6 @@ func (c *Config) WithDefaults() *Config {
 	if d.RetentionPolicy == "" {
 		d.RetentionPolicy = DefaultRetentionPolicy
 	}
-	if d.ConsistencyLevel == "" {
-		d.ConsistencyLevel = DefaultConsistencyLevel
-	}
 	if d.Certificate == "" {
 		d.Certificate = DefaultCertificate
 	}
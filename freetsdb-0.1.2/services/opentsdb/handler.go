@@ -20,8 +20,9 @@ import (
 
 // Handler is an http.Handler for the OpenTSDB service.
 type Handler struct {
-	Database        string
-	RetentionPolicy string
+	Database         string
+	RetentionPolicy  string
+	ConsistencyLevel coordinator.ConsistencyLevel
 
 	PointsWriter interface {
 		WritePointsPrivileged(database, retentionPolicy string, consistencyLevel coordinator.ConsistencyLevel, points []models.Point) error
@@ -126,7 +127,7 @@ func (h *Handler) servePut(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Write points.
-	if err := h.PointsWriter.WritePointsPrivileged(h.Database, h.RetentionPolicy, coordinator.ConsistencyLevelAny, points); freetsdb.IsClientError(err) {
+	if err := h.PointsWriter.WritePointsPrivileged(h.Database, h.RetentionPolicy, h.ConsistencyLevel, points); freetsdb.IsClientError(err) {
 		h.Logger.Info("Write series error", zap.Error(err))
 		http.Error(w, "write series error: "+err.Error(), http.StatusBadRequest)
 		return
@@ -57,9 +57,10 @@ type Service struct {
 	ready bool          // Has the required database been created?
 	done  chan struct{} // Is the service closing or closed?
 
-	BindAddress     string
-	Database        string
-	RetentionPolicy string
+	BindAddress      string
+	Database         string
+	RetentionPolicy  string
+	ConsistencyLevel coordinator.ConsistencyLevel
 
 	PointsWriter interface {
 		WritePointsPrivileged(database, retentionPolicy string, consistencyLevel coordinator.ConsistencyLevel, points []models.Point) error
@@ -100,11 +101,21 @@ func NewService(c Config) (*Service, error) {
 		LogPointErrors:  d.LogPointErrors,
 		stats:           &Statistics{},
 		defaultTags:     models.StatisticTags{"bind": d.BindAddress},
+
+		ConsistencyLevel: coordinator.ConsistencyLevelDefaultAny,
 	}
 	if s.tlsConfig == nil {
 		s.tlsConfig = new(tls.Config)
 	}
 
+	if d.ConsistencyLevel != "" {
+		level, err := coordinator.ParseConsistencyLevel(d.ConsistencyLevel)
+		if err != nil {
+			return nil, err
+		}
+		s.ConsistencyLevel = level
+	}
+
 	return s, nil
 }
 
@@ -454,11 +465,12 @@ func (s *Service) handleTelnetConn(conn net.Conn) {
 // serveHTTP handles connections in HTTP format.
 func (s *Service) serveHTTP() {
 	handler := &Handler{
-		Database:        s.Database,
-		RetentionPolicy: s.RetentionPolicy,
-		PointsWriter:    s.PointsWriter,
-		Logger:          s.Logger,
-		stats:           s.stats,
+		Database:         s.Database,
+		RetentionPolicy:  s.RetentionPolicy,
+		ConsistencyLevel: s.ConsistencyLevel,
+		PointsWriter:     s.PointsWriter,
+		Logger:           s.Logger,
+		stats:            s.stats,
 	}
 	srv := &http.Server{Handler: handler}
 	srv.Serve(s.httpln)
@@ -477,7 +489,7 @@ func (s *Service) processBatches(batcher *tsdb.PointBatcher) {
 				continue
 			}
 
-			if err := s.PointsWriter.WritePointsPrivileged(s.Database, s.RetentionPolicy, coordinator.ConsistencyLevelAny, batch); err == nil {
+			if err := s.PointsWriter.WritePointsPrivileged(s.Database, s.RetentionPolicy, s.ConsistencyLevel, batch); err == nil {
 				atomic.AddInt64(&s.stats.BatchesTransmitted, 1)
 				atomic.AddInt64(&s.stats.PointsTransmitted, int64(len(batch)))
 			} else {
@@ -0,0 +1,67 @@
+package rebalance
+
+import (
+	"errors"
+	"time"
+
+	"github.com/freetsdb/freetsdb/monitor/diagnostics"
+	"github.com/freetsdb/freetsdb/toml"
+)
+
+const (
+	// DefaultCheckInterval is how long to wait before checking again after a
+	// rebalance deferred shards or had moves fail, if none is specified.
+	DefaultCheckInterval = 10 * time.Minute
+
+	// DefaultMaxConcurrentMoves is the default number of shard moves that
+	// may run at the same time.
+	DefaultMaxConcurrentMoves = 1
+)
+
+// Config represents the configuration for the rebalance service. Rebalancing
+// is disabled by default; when enabled it runs when data nodes join or leave.
+type Config struct {
+	Enabled            bool          `toml:"enabled"`
+	CheckInterval      toml.Duration `toml:"check-interval"`
+	MaxConcurrentMoves int           `toml:"max-concurrent-moves"`
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		Enabled:            false,
+		CheckInterval:      toml.Duration(DefaultCheckInterval),
+		MaxConcurrentMoves: DefaultMaxConcurrentMoves,
+	}
+}
+
+// Validate returns an error if the Config is invalid.
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.CheckInterval <= 0 {
+		return errors.New("check-interval must be positive")
+	}
+	if c.MaxConcurrentMoves <= 0 {
+		return errors.New("max-concurrent-moves must be positive")
+	}
+
+	return nil
+}
+
+// Diagnostics returns a diagnostics representation of a subset of the Config.
+func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
+	if !c.Enabled {
+		return diagnostics.RowFromMap(map[string]interface{}{
+			"enabled": false,
+		}), nil
+	}
+
+	return diagnostics.RowFromMap(map[string]interface{}{
+		"enabled":              true,
+		"check-interval":       c.CheckInterval,
+		"max-concurrent-moves": c.MaxConcurrentMoves,
+	}), nil
+}
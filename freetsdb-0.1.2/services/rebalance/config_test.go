@@ -0,0 +1,60 @@
+package rebalance_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/freetsdb/freetsdb/services/rebalance"
+)
+
+func TestConfig_Parse(t *testing.T) {
+	// Parse configuration.
+	var c rebalance.Config
+	if _, err := toml.Decode(`
+enabled = true
+check-interval = "2m"
+max-concurrent-moves = 3
+`, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate configuration.
+	if !c.Enabled {
+		t.Fatalf("unexpected enabled state: %v", c.Enabled)
+	} else if time.Duration(c.CheckInterval) != 2*time.Minute {
+		t.Fatalf("unexpected check interval: %s", c.CheckInterval)
+	} else if c.MaxConcurrentMoves != 3 {
+		t.Fatalf("unexpected max concurrent moves: %d", c.MaxConcurrentMoves)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	c := rebalance.NewConfig()
+	if c.Enabled {
+		t.Fatal("expected rebalancing to be disabled by default")
+	}
+	c.Enabled = true
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation fail from NewConfig: %s", err)
+	}
+
+	c = rebalance.NewConfig()
+	c.Enabled = true
+	c.CheckInterval = 0
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for check-interval = 0, got nil")
+	}
+
+	c = rebalance.NewConfig()
+	c.Enabled = true
+	c.MaxConcurrentMoves = 0
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for max-concurrent-moves = 0, got nil")
+	}
+
+	c.Enabled = false
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation fail from disabled config: %s", err)
+	}
+}
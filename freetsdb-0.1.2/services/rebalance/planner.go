@@ -0,0 +1,269 @@
+package rebalance
+
+import (
+	"sort"
+	"time"
+
+	"github.com/freetsdb/freetsdb/services/meta"
+)
+
+// Action is the kind of work a ShardMove performs.
+type Action int
+
+const (
+	// Copy adds a new replica of a shard to the destination node.
+	Copy Action = iota
+
+	// Move copies a shard to the destination node and then removes it
+	// from the source node.
+	Move
+)
+
+// String returns the string representation of the action.
+func (a Action) String() string {
+	switch a {
+	case Copy:
+		return "copy"
+	case Move:
+		return "move"
+	}
+	return "unknown"
+}
+
+// ShardMove describes a single shard transfer between two data nodes.
+type ShardMove struct {
+	ShardID         uint64
+	Database        string
+	RetentionPolicy string
+	Action          Action
+	Source          uint64
+	Destination     uint64
+}
+
+// PlanOptions controls which shards a plan may touch and how they weigh.
+type PlanOptions struct {
+	// Now is compared with the end time of shard groups. Groups that have
+	// not ended yet still receive writes, which would be lost or missed by
+	// the new owner while the shard is copied, so they are never planned.
+	Now time.Time
+
+	// Sizes holds the size in bytes of each shard. Shards without a size
+	// weigh the mean of the known sizes, so with no sizes at all shards are
+	// balanced by count.
+	Sizes map[uint64]int64
+}
+
+// shardState tracks the owners of a shard while a plan is being built.
+type shardState struct {
+	id       uint64
+	database string
+	policy   string
+	replicaN int
+	size     int64
+	hot      bool
+	owners   []uint64
+}
+
+func (s *shardState) ownedBy(nodeID uint64) bool {
+	for _, id := range s.owners {
+		if id == nodeID {
+			return true
+		}
+	}
+	return false
+}
+
+// Plan returns the moves required to bring every shard in data back to its
+// retention policy's replication factor and to spread the stored bytes evenly
+// across data nodes. Shards without any remaining owner cannot be recovered
+// and are left alone. Nodes that are down are neither copied from nor moved
+// to. deferred is true if an under-replicated shard was skipped because its
+// shard group is still being written or none of its owners is up.
+func Plan(data *meta.Data, opt PlanOptions) (moves []ShardMove, deferred bool) {
+	if len(data.DataNodes) == 0 {
+		return nil, false
+	}
+
+	nodes := make([]uint64, 0, len(data.DataNodes))
+	down := make(map[uint64]bool)
+	for _, n := range data.DataNodes {
+		nodes = append(nodes, n.ID)
+		if n.Down() {
+			down[n.ID] = true
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+
+	var shards []*shardState
+	load := make(map[uint64]int64, len(nodes))
+	for _, id := range nodes {
+		load[id] = 0
+	}
+
+	// Collect the current owners of every live shard.
+	for _, dbi := range data.Databases {
+		for _, rpi := range dbi.RetentionPolicies {
+			n := rpi.ReplicaN
+			if n < 1 {
+				n = 1
+			} else if n > len(nodes) {
+				n = len(nodes)
+			}
+
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+				for _, si := range sgi.Shards {
+					sh := &shardState{
+						id:       si.ID,
+						database: dbi.Name,
+						policy:   rpi.Name,
+						replicaN: n,
+						hot:      sgi.EndTime.After(opt.Now),
+					}
+					for _, o := range si.Owners {
+						if _, ok := load[o.NodeID]; ok {
+							sh.owners = append(sh.owners, o.NodeID)
+						}
+					}
+					shards = append(shards, sh)
+				}
+			}
+		}
+	}
+
+	// Weigh every shard and compute the load of each node.
+	var known, total int64
+	for _, sh := range shards {
+		if size, ok := opt.Sizes[sh.id]; ok {
+			known++
+			total += size
+		}
+	}
+	mean := int64(1)
+	if known > 0 {
+		mean = total / known
+	}
+	for _, sh := range shards {
+		sh.size = mean
+		if size, ok := opt.Sizes[sh.id]; ok {
+			sh.size = size
+		}
+		for _, id := range sh.owners {
+			load[id] += sh.size
+		}
+	}
+
+	// leastLoaded returns the least loaded node that is up and does not own sh.
+	leastLoaded := func(sh *shardState) (uint64, bool) {
+		var best uint64
+		var found bool
+		for _, id := range nodes {
+			if down[id] || sh.ownedBy(id) {
+				continue
+			}
+			if !found || load[id] < load[best] {
+				best, found = id, true
+			}
+		}
+		return best, found
+	}
+
+	// Restore the replication factor of under-replicated shards.
+	for _, sh := range shards {
+		if len(sh.owners) == 0 {
+			continue
+		} else if sh.hot {
+			deferred = deferred || len(sh.owners) < sh.replicaN
+			continue
+		}
+		src, ok := liveOwner(sh, down)
+		if !ok {
+			deferred = deferred || len(sh.owners) < sh.replicaN
+			continue
+		}
+		for len(sh.owners) < sh.replicaN {
+			dst, ok := leastLoaded(sh)
+			if !ok {
+				break
+			}
+			moves = append(moves, ShardMove{
+				ShardID:         sh.id,
+				Database:        sh.database,
+				RetentionPolicy: sh.policy,
+				Action:          Copy,
+				Source:          src,
+				Destination:     dst,
+			})
+			sh.owners = append(sh.owners, dst)
+			load[dst] += sh.size
+		}
+	}
+
+	// Move the largest shard that narrows the gap from the most loaded node
+	// to the least loaded one, until no shard would. Every move lowers the
+	// spread of the loads, so this terminates. Among shards of equal size the
+	// newest is moved since it will live the longest. Only nodes that are up
+	// take part, since a node that is down can't send or receive a shard.
+	var live []uint64
+	for _, id := range nodes {
+		if !down[id] {
+			live = append(live, id)
+		}
+	}
+	for len(live) > 1 {
+		hi, lo := live[0], live[0]
+		for _, id := range live {
+			if load[id] > load[hi] {
+				hi = id
+			}
+			if load[id] < load[lo] {
+				lo = id
+			}
+		}
+		gap := load[hi] - load[lo]
+
+		var sh *shardState
+		for i := len(shards) - 1; i >= 0; i-- {
+			c := shards[i]
+			if c.hot || c.size <= 0 || c.size >= gap || !c.ownedBy(hi) || c.ownedBy(lo) {
+				continue
+			}
+			if sh == nil || c.size > sh.size {
+				sh = c
+			}
+		}
+		if sh == nil {
+			break
+		}
+
+		moves = append(moves, ShardMove{
+			ShardID:         sh.id,
+			Database:        sh.database,
+			RetentionPolicy: sh.policy,
+			Action:          Move,
+			Source:          hi,
+			Destination:     lo,
+		})
+		for i, id := range sh.owners {
+			if id == hi {
+				sh.owners[i] = lo
+			}
+		}
+		load[hi] -= sh.size
+		load[lo] += sh.size
+	}
+
+	return moves, deferred
+}
+
+// liveOwner returns the first owner of sh that is not down.
+func liveOwner(sh *shardState, down map[uint64]bool) (uint64, bool) {
+	for _, id := range sh.owners {
+		if !down[id] {
+			return id, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,185 @@
+package rebalance_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/services/rebalance"
+)
+
+// NewData returns meta data with n data nodes and a database "db" whose
+// default retention policy has the given replication factor.
+func NewData(t *testing.T, n, replicaN int) *meta.Data {
+	data := &meta.Data{}
+	for i := 0; i < n; i++ {
+		host := string(rune('a' + i))
+		if err := data.CreateDataNode(host+":8086", host+":8088"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := data.CreateDatabase("db"); err != nil {
+		t.Fatal(err)
+	}
+
+	rpi := meta.NewRetentionPolicyInfo("rp")
+	rpi.ReplicaN = replicaN
+	rpi.ShardGroupDuration = time.Hour
+	if err := data.CreateRetentionPolicy("db", rpi, true); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// now is after the end of every shard group created by the tests, unless a
+// test creates one at now to have it still being written.
+var now = time.Unix(0, 0).Add(24 * time.Hour)
+
+// Ensure the planner restores the replication factor after a node is removed.
+func TestPlan_UnderReplicated(t *testing.T) {
+	data := NewData(t, 3, 2)
+	if err := data.CreateShardGroup("db", "rp", time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Node 1 owns shard 1 along with another node.
+	if err := data.DeleteDataNode(1); err != nil {
+		t.Fatal(err)
+	}
+
+	moves, deferred := rebalance.Plan(data, rebalance.PlanOptions{Now: now})
+	if deferred {
+		t.Fatal("unexpected deferred shards")
+	} else if len(moves) != 1 {
+		t.Fatalf("unexpected moves: %+v", moves)
+	}
+
+	m := moves[0]
+	if m.Action != rebalance.Copy || m.ShardID != 1 || m.Source == m.Destination {
+		t.Fatalf("unexpected move: %+v", m)
+	}
+}
+
+// Ensure the planner spreads shards onto a newly added node.
+func TestPlan_NewNode(t *testing.T) {
+	data := NewData(t, 1, 1)
+	for i := 0; i < 4; i++ {
+		if err := data.CreateShardGroup("db", "rp", time.Unix(0, 0).Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := data.CreateDataNode("b:8086", "b:8088"); err != nil {
+		t.Fatal(err)
+	}
+
+	moves, _ := rebalance.Plan(data, rebalance.PlanOptions{Now: now})
+	exp := []rebalance.ShardMove{
+		{ShardID: 4, Database: "db", RetentionPolicy: "rp", Action: rebalance.Move, Source: 1, Destination: 2},
+		{ShardID: 3, Database: "db", RetentionPolicy: "rp", Action: rebalance.Move, Source: 1, Destination: 2},
+	}
+	if !reflect.DeepEqual(moves, exp) {
+		t.Fatalf("unexpected moves:\n\texp=%+v\n\tgot=%+v", exp, moves)
+	}
+}
+
+// Ensure a balanced cluster needs no moves.
+func TestPlan_Balanced(t *testing.T) {
+	data := NewData(t, 2, 2)
+	if err := data.CreateShardGroup("db", "rp", time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	if moves, _ := rebalance.Plan(data, rebalance.PlanOptions{Now: now}); len(moves) != 0 {
+		t.Fatalf("unexpected moves: %+v", moves)
+	}
+}
+
+// Ensure shard groups that are still being written are neither copied nor
+// moved, and that skipping an under-replicated one is reported.
+func TestPlan_HotShardGroup(t *testing.T) {
+	data := NewData(t, 3, 2)
+	if err := data.CreateShardGroup("db", "rp", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.DeleteDataNode(1); err != nil {
+		t.Fatal(err)
+	}
+
+	moves, deferred := rebalance.Plan(data, rebalance.PlanOptions{Now: now})
+	if len(moves) != 0 {
+		t.Fatalf("unexpected moves: %+v", moves)
+	} else if !deferred {
+		t.Fatal("expected the under-replicated shard to be deferred")
+	}
+
+	// Once the group has ended the shard is copied.
+	moves, deferred = rebalance.Plan(data, rebalance.PlanOptions{Now: now.Add(time.Hour)})
+	if len(moves) != 1 || moves[0].Action != rebalance.Copy || deferred {
+		t.Fatalf("unexpected moves: %+v, deferred=%v", moves, deferred)
+	}
+}
+
+// Ensure shards are balanced by size rather than count.
+func TestPlan_Sizes(t *testing.T) {
+	data := NewData(t, 1, 1)
+	for i := 0; i < 4; i++ {
+		if err := data.CreateShardGroup("db", "rp", time.Unix(0, 0).Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := data.CreateDataNode("b:8086", "b:8088"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Moving the large shard alone balances the nodes; by count two of the
+	// small ones would have moved.
+	moves, _ := rebalance.Plan(data, rebalance.PlanOptions{
+		Now:   now,
+		Sizes: map[uint64]int64{1: 300, 2: 100, 3: 100, 4: 100},
+	})
+	exp := []rebalance.ShardMove{
+		{ShardID: 1, Database: "db", RetentionPolicy: "rp", Action: rebalance.Move, Source: 1, Destination: 2},
+	}
+	if !reflect.DeepEqual(moves, exp) {
+		t.Fatalf("unexpected moves:\n\texp=%+v\n\tgot=%+v", exp, moves)
+	}
+}
+
+// Ensure an under-replicated shard is copied from an owner that is up, and
+// deferred while none of its owners is.
+func TestPlan_DownOwner(t *testing.T) {
+	data := NewData(t, 4, 3)
+	if err := data.CreateShardGroup("db", "rp", time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Shard 1 is owned by nodes 1, 2 and 3. Node 3 is removed and node 1 is
+	// down, so the shard must be copied from node 2 to node 4.
+	if err := data.DeleteDataNode(3); err != nil {
+		t.Fatal(err)
+	} else if err := data.SetDataNodeStatus(1, meta.NodeStatusDown, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	moves, deferred := rebalance.Plan(data, rebalance.PlanOptions{Now: now})
+	exp := []rebalance.ShardMove{
+		{ShardID: 1, Database: "db", RetentionPolicy: "rp", Action: rebalance.Copy, Source: 2, Destination: 4},
+	}
+	if deferred {
+		t.Fatal("unexpected deferred shards")
+	} else if !reflect.DeepEqual(moves, exp) {
+		t.Fatalf("unexpected moves:\n\texp=%+v\n\tgot=%+v", exp, moves)
+	}
+
+	// With every owner down the shard can't be copied yet.
+	if err := data.SetDataNodeStatus(2, meta.NodeStatusDown, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	moves, deferred = rebalance.Plan(data, rebalance.PlanOptions{Now: now})
+	if len(moves) != 0 {
+		t.Fatalf("unexpected moves: %+v", moves)
+	} else if !deferred {
+		t.Fatal("expected the shard without a live owner to be deferred")
+	}
+}
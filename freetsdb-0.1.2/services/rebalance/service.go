@@ -0,0 +1,424 @@
+// Package rebalance provides the shard rebalancing service.
+package rebalance // import "github.com/freetsdb/freetsdb/services/rebalance"
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/freetsdb/freetsdb"
+	"github.com/freetsdb/freetsdb/logger"
+	"github.com/freetsdb/freetsdb/services/copier"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tcp"
+	"go.uber.org/zap"
+)
+
+// JobState is the execution state of a rebalance job.
+type JobState int
+
+const (
+	// Pending jobs are planned but not yet started.
+	Pending JobState = iota
+
+	// Running jobs are copying shard data.
+	Running
+
+	// Done jobs have completed successfully.
+	Done
+
+	// Failed jobs returned an error.
+	Failed
+)
+
+// String returns the string representation of the state.
+func (s JobState) String() string {
+	switch s {
+	case Pending:
+		return "pending"
+	case Running:
+		return "running"
+	case Done:
+		return "done"
+	case Failed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// Job is a planned shard move and its progress.
+type Job struct {
+	ShardMove
+	State     JobState
+	StartedAt time.Time
+	Err       error
+}
+
+// ShardCopier transfers shards between data nodes.
+type ShardCopier interface {
+	CopyShard(dst, src string, id uint64) error
+	RemoveShard(host string, id uint64) error
+	ShardSize(host string, id uint64) (int64, error)
+}
+
+// Service manages the shard rebalance service. The data node with the lowest
+// ID plans and runs the moves whenever the set of data nodes changes; every
+// other node only reports the plan.
+type Service struct {
+	enabled       bool
+	checkInterval time.Duration
+	maxMoves      int
+
+	Node *freetsdb.Node
+
+	MetaClient interface {
+		Data() meta.Data
+		DataNode(id uint64) (*meta.NodeInfo, error)
+		AddShardOwner(shardID, nodeID uint64) error
+		RemoveShardOwner(shardID, nodeID uint64) error
+		WaitForDataChanged() chan struct{}
+	}
+
+	ShardCopier ShardCopier
+
+	Logger *zap.Logger
+
+	mu   sync.Mutex
+	jobs []*Job
+
+	// plan is the plan reported while this node has not run any jobs, and
+	// planAt is when it was computed. It is computed at most once per check
+	// interval, since it asks every shard owner for the size of its shards.
+	plan   []Job
+	planAt time.Time
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewService returns an instance of the rebalance service.
+func NewService(c Config) *Service {
+	return &Service{
+		enabled:       c.Enabled,
+		checkInterval: time.Duration(c.CheckInterval),
+		maxMoves:      c.MaxConcurrentMoves,
+		ShardCopier:   NewShardCopier(nil),
+		Logger:        zap.NewNop(),
+	}
+}
+
+// WithLogger sets the logger for the service.
+func (s *Service) WithLogger(log *zap.Logger) {
+	s.Logger = log.With(zap.String("service", "rebalance"))
+}
+
+// Open starts the rebalance service.
+func (s *Service) Open() error {
+	if s.done != nil {
+		return nil
+	}
+
+	s.Logger.Info("Starting rebalance service",
+		logger.DurationLiteral("check_interval", s.checkInterval),
+		zap.Int("max_concurrent_moves", s.maxMoves))
+
+	s.done = make(chan struct{})
+
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+// Close stops the rebalance service.
+func (s *Service) Close() error {
+	if s.done == nil {
+		return nil
+	}
+
+	close(s.done)
+	s.wg.Wait()
+	s.done = nil
+
+	return nil
+}
+
+// Jobs returns the jobs of the current or last rebalance run by this node,
+// along with their errors. If this node has not run any jobs the plan is
+// computed from the current meta data and reported as pending. A disabled
+// service reports no jobs.
+func (s *Service) Jobs() []Job {
+	if !s.enabled || s.MetaClient == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	var jobs []Job
+	for _, j := range s.jobs {
+		jobs = append(jobs, *j)
+	}
+	if len(jobs) > 0 || (s.plan != nil && time.Since(s.planAt) < s.checkInterval) {
+		if len(jobs) == 0 {
+			jobs = append(jobs, s.plan...)
+		}
+		s.mu.Unlock()
+		return jobs
+	}
+	s.mu.Unlock()
+
+	data := s.MetaClient.Data()
+	moves, _ := Plan(&data, s.planOptions(&data))
+	plan := make([]Job, 0, len(moves))
+	for _, m := range moves {
+		plan = append(plan, Job{ShardMove: m})
+	}
+
+	s.mu.Lock()
+	s.plan, s.planAt = plan, time.Now()
+	s.mu.Unlock()
+	return append([]Job(nil), plan...)
+}
+
+// run rebalances shards whenever the set of data nodes changes. A rebalance
+// that deferred shards or had moves fail is retried after the check
+// interval, as is the first check after the service starts.
+func (s *Service) run() {
+	defer s.wg.Done()
+
+	retry := time.After(s.checkInterval)
+	nodes := s.dataNodes()
+	for {
+		select {
+		case <-retry:
+		case <-s.MetaClient.WaitForDataChanged():
+			n := s.dataNodes()
+			if n == nodes {
+				continue
+			}
+			nodes = n
+		case <-s.done:
+			s.Logger.Info("Terminating rebalance service")
+			return
+		}
+
+		retry = nil
+		if pending := s.rebalance(); pending {
+			retry = time.After(s.checkInterval)
+		}
+	}
+}
+
+// dataNodes returns a key identifying the current set of data nodes. Only
+// the node IDs are used, so status changes don't trigger a rebalance.
+func (s *Service) dataNodes() string {
+	data := s.MetaClient.Data()
+	ids := make([]uint64, len(data.DataNodes))
+	for i, n := range data.DataNodes {
+		ids[i] = n.ID
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return fmt.Sprint(ids)
+}
+
+// isPlanner returns true if this node is responsible for running moves.
+func (s *Service) isPlanner(data *meta.Data) bool {
+	if s.Node == nil || len(data.DataNodes) == 0 {
+		return false
+	}
+	for _, n := range data.DataNodes {
+		if n.ID < s.Node.ID {
+			return false
+		}
+	}
+	return data.DataNode(s.Node.ID) != nil
+}
+
+// planOptions returns the options to plan data with at the current time.
+func (s *Service) planOptions(data *meta.Data) PlanOptions {
+	return PlanOptions{
+		Now:   time.Now().UTC(),
+		Sizes: s.shardSizes(data),
+	}
+}
+
+// shardSizes returns the size of every shard in data, as reported by the
+// first owner that answers. Shards no owner reports a size for are left out.
+func (s *Service) shardSizes(data *meta.Data) map[uint64]int64 {
+	sizes := make(map[uint64]int64)
+	for _, dbi := range data.Databases {
+		for _, rpi := range dbi.RetentionPolicies {
+			for _, sgi := range rpi.ShardGroups {
+				if sgi.Deleted() {
+					continue
+				}
+				for _, si := range sgi.Shards {
+					for _, o := range si.Owners {
+						n := data.DataNode(o.NodeID)
+						if n == nil {
+							continue
+						}
+						if size, err := s.ShardCopier.ShardSize(n.TCPHost, si.ID); err == nil {
+							sizes[si.ID] = size
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+	return sizes
+}
+
+// rebalance plans and executes the moves needed to balance the cluster.
+// It blocks until every job has finished and returns true if the cluster
+// should be checked again: shards were deferred or a move failed.
+func (s *Service) rebalance() bool {
+	data := s.MetaClient.Data()
+	if !s.isPlanner(&data) {
+		return false
+	}
+
+	moves, deferred := Plan(&data, s.planOptions(&data))
+	if deferred {
+		s.Logger.Info("Deferring rebalance of shard groups still being written")
+	}
+	if len(moves) == 0 {
+		return deferred
+	}
+
+	s.Logger.Info("Rebalancing shards", zap.Int("moves", len(moves)))
+
+	jobs := make([]*Job, len(moves))
+	for i, m := range moves {
+		jobs[i] = &Job{ShardMove: m}
+	}
+	s.mu.Lock()
+	s.jobs = jobs
+	s.mu.Unlock()
+
+	// Jobs for the same shard must run in plan order, so group them by
+	// shard and run the groups with limited concurrency.
+	var order []uint64
+	byShard := make(map[uint64][]*Job)
+	for _, j := range jobs {
+		if _, ok := byShard[j.ShardID]; !ok {
+			order = append(order, j.ShardID)
+		}
+		byShard[j.ShardID] = append(byShard[j.ShardID], j)
+	}
+
+	var wg sync.WaitGroup
+	var failed int32
+	throttle := make(chan struct{}, s.maxMoves)
+	for _, id := range order {
+		select {
+		case throttle <- struct{}{}:
+		case <-s.done:
+			wg.Wait()
+			return false
+		}
+
+		wg.Add(1)
+		go func(jobs []*Job) {
+			defer wg.Done()
+			defer func() { <-throttle }()
+			for _, j := range jobs {
+				if err := s.runJob(j); err != nil {
+					atomic.StoreInt32(&failed, 1)
+					return
+				}
+			}
+		}(byShard[id])
+	}
+	wg.Wait()
+
+	// The jobs are kept so their outcome and errors are reported until the
+	// next rebalance.
+	return deferred || atomic.LoadInt32(&failed) != 0
+}
+
+// runJob executes a single shard move and records its outcome.
+func (s *Service) runJob(j *Job) error {
+	s.setState(j, Running, nil)
+
+	err := s.execute(j.ShardMove)
+	if err != nil {
+		s.Logger.Info("Failed to rebalance shard",
+			zap.Uint64("id", j.ShardID),
+			zap.Uint64("source", j.Source),
+			zap.Uint64("destination", j.Destination),
+			zap.Error(err))
+		s.setState(j, Failed, err)
+		return err
+	}
+
+	s.Logger.Info("Rebalanced shard",
+		zap.Uint64("id", j.ShardID),
+		zap.String("action", j.Action.String()),
+		zap.Uint64("source", j.Source),
+		zap.Uint64("destination", j.Destination))
+	s.setState(j, Done, nil)
+	return nil
+}
+
+func (s *Service) setState(j *Job, state JobState, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state == Running {
+		j.StartedAt = time.Now().UTC()
+	}
+	j.State, j.Err = state, err
+}
+
+// execute copies the shard to the destination, registers the new owner and,
+// for moves, removes the shard from the source.
+func (s *Service) execute(m ShardMove) error {
+	src, err := s.MetaClient.DataNode(m.Source)
+	if err != nil {
+		return err
+	}
+	dst, err := s.MetaClient.DataNode(m.Destination)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ShardCopier.CopyShard(dst.TCPHost, src.TCPHost, m.ShardID); err != nil {
+		return err
+	}
+	if err := s.MetaClient.AddShardOwner(m.ShardID, dst.ID); err != nil {
+		return err
+	}
+
+	if m.Action != Move {
+		return nil
+	}
+
+	if err := s.MetaClient.RemoveShardOwner(m.ShardID, src.ID); err != nil {
+		return err
+	}
+	return s.ShardCopier.RemoveShard(src.TCPHost, m.ShardID)
+}
+
+// NewShardCopier returns a ShardCopier using the copier service of each node,
+// connecting with dialer.
+func NewShardCopier(dialer *tcp.Dialer) ShardCopier {
+	return copierClient{dialer: dialer}
+}
+
+// copierClient implements ShardCopier using the copier service.
+type copierClient struct {
+	dialer *tcp.Dialer
+}
+
+func (c copierClient) CopyShard(dst, src string, id uint64) error {
+	return copier.NewClient(dst, c.dialer).CopyShard(id, src)
+}
+
+func (c copierClient) RemoveShard(host string, id uint64) error {
+	return copier.NewClient(host, c.dialer).RemoveShard(id)
+}
+
+func (c copierClient) ShardSize(host string, id uint64) (int64, error) {
+	return copier.NewClient(host, c.dialer).ShardSize(id)
+}
@@ -0,0 +1,233 @@
+package rebalance_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/freetsdb/freetsdb"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/services/rebalance"
+	"github.com/freetsdb/freetsdb/toml"
+)
+
+func TestService_Rebalance(t *testing.T) {
+	data := NewData(t, 1, 1)
+	for i := 0; i < 2; i++ {
+		if err := data.CreateShardGroup("db", "rp", time.Unix(0, 0).Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := data.CreateDataNode("b:8086", "b:8088"); err != nil {
+		t.Fatal(err)
+	}
+
+	mc := &MetaClient{data: data, changed: make(chan struct{})}
+	sc := &ShardCopier{done: make(chan struct{})}
+
+	config := rebalance.NewConfig()
+	config.Enabled = true
+	config.CheckInterval = toml.Duration(10 * time.Millisecond)
+	s := rebalance.NewService(config)
+	s.Node = &freetsdb.Node{ID: 1}
+	s.MetaClient = mc
+	s.ShardCopier = sc
+
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	select {
+	case <-sc.done:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for shard move")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if len(sc.copied) != 1 || sc.copied[0] != "a:8088 -> b:8088 2" {
+		t.Fatalf("unexpected copies: %v", sc.copied)
+	} else if len(sc.removed) != 1 || sc.removed[0] != "a:8088 2" {
+		t.Fatalf("unexpected removals: %v", sc.removed)
+	}
+
+	d := mc.Data()
+	if moves, _ := rebalance.Plan(&d, rebalance.PlanOptions{Now: time.Now()}); len(moves) != 0 {
+		t.Fatalf("cluster not balanced after rebalance: %+v", moves)
+	}
+}
+
+// Ensure the jobs of the last rebalance are reported with their errors after
+// it finished.
+func TestService_Jobs_Failed(t *testing.T) {
+	data := NewData(t, 1, 1)
+	if err := data.CreateShardGroup("db", "rp", time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateShardGroup("db", "rp", time.Unix(0, 0).Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateDataNode("b:8086", "b:8088"); err != nil {
+		t.Fatal(err)
+	}
+
+	errCopy := errors.New("connection refused")
+	sc := &ShardCopier{done: make(chan struct{}), err: errCopy}
+
+	config := rebalance.NewConfig()
+	config.Enabled = true
+	config.CheckInterval = toml.Duration(10 * time.Millisecond)
+	s := rebalance.NewService(config)
+	s.Node = &freetsdb.Node{ID: 1}
+	s.MetaClient = &MetaClient{data: data, changed: make(chan struct{})}
+	s.ShardCopier = sc
+
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	select {
+	case <-sc.done:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for shard copy")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := s.Jobs()
+	if len(jobs) != 1 || jobs[0].State != rebalance.Failed || jobs[0].Err != errCopy {
+		t.Fatalf("unexpected jobs: %+v", jobs)
+	}
+}
+
+// Ensure a disabled service reports no jobs without asking the shard owners
+// for their sizes.
+func TestService_Jobs_Disabled(t *testing.T) {
+	data := NewData(t, 1, 1)
+	if err := data.CreateShardGroup("db", "rp", time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	sc := &ShardCopier{}
+	s := rebalance.NewService(rebalance.NewConfig())
+	s.MetaClient = &MetaClient{data: data, changed: make(chan struct{})}
+	s.ShardCopier = sc
+
+	if jobs := s.Jobs(); len(jobs) != 0 {
+		t.Fatalf("unexpected jobs: %+v", jobs)
+	} else if sc.sizeN != 0 {
+		t.Fatalf("unexpected shard size requests: %d", sc.sizeN)
+	}
+}
+
+// Ensure the plan reported by a node that runs no jobs is computed once per
+// check interval.
+func TestService_Jobs_CachedPlan(t *testing.T) {
+	data := NewData(t, 1, 1)
+	if err := data.CreateShardGroup("db", "rp", time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateShardGroup("db", "rp", time.Unix(0, 0).Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateDataNode("b:8086", "b:8088"); err != nil {
+		t.Fatal(err)
+	}
+
+	sc := &ShardCopier{}
+	config := rebalance.NewConfig()
+	config.Enabled = true
+	config.CheckInterval = toml.Duration(time.Hour)
+	s := rebalance.NewService(config)
+	s.MetaClient = &MetaClient{data: data, changed: make(chan struct{})}
+	s.ShardCopier = sc
+
+	for i := 0; i < 2; i++ {
+		if jobs := s.Jobs(); len(jobs) != 1 || jobs[0].State != rebalance.Pending {
+			t.Fatalf("%d: unexpected jobs: %+v", i, jobs)
+		}
+	}
+	if sc.sizeN != 2 {
+		t.Fatalf("unexpected shard size requests: %d", sc.sizeN)
+	}
+}
+
+// MetaClient is a mock meta client that applies owner changes to data.
+type MetaClient struct {
+	mu      sync.Mutex
+	data    *meta.Data
+	changed chan struct{}
+}
+
+func (c *MetaClient) Data() meta.Data {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return *c.data.Clone()
+}
+
+func (c *MetaClient) DataNode(id uint64) (*meta.NodeInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n := c.data.DataNode(id); n != nil {
+		return n, nil
+	}
+	return nil, meta.ErrNodeNotFound
+}
+
+func (c *MetaClient) AddShardOwner(shardID, nodeID uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.data.AddShardOwner(shardID, nodeID)
+}
+
+func (c *MetaClient) RemoveShardOwner(shardID, nodeID uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.data.RemoveShardOwner(shardID, nodeID)
+}
+
+func (c *MetaClient) WaitForDataChanged() chan struct{} { return c.changed }
+
+// ShardCopier is a mock that records shard transfers.
+type ShardCopier struct {
+	mu      sync.Mutex
+	copied  []string
+	removed []string
+	sizeN   int
+	err     error
+	done    chan struct{}
+}
+
+func (c *ShardCopier) CopyShard(dst, src string, id uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		select {
+		case <-c.done:
+		default:
+			close(c.done)
+		}
+		return c.err
+	}
+	c.copied = append(c.copied, fmt.Sprintf("%s -> %s %d", src, dst, id))
+	return nil
+}
+
+func (c *ShardCopier) ShardSize(host string, id uint64) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sizeN++
+	return 1, nil
+}
+
+func (c *ShardCopier) RemoveShard(host string, id uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removed = append(c.removed, fmt.Sprintf("%s %d", host, id))
+	close(c.done)
+	return nil
+}
@@ -12,11 +12,25 @@ import (
 type Config struct {
 	Enabled       bool          `toml:"enabled"`
 	CheckInterval toml.Duration `toml:"check-interval"`
+
+	// GracePeriod is how long expired shard groups are kept pending
+	// deletion, and can be recovered with UNDROP SHARD GROUP, before their
+	// shards are removed. Zero removes them right away.
+	GracePeriod toml.Duration `toml:"grace-period"`
+
+	// TrashRetention is how long dropped databases, measurements and shards
+	// are kept in the trash, and can be restored with UNDROP, before they
+	// are removed. Zero keeps them until removed by hand.
+	TrashRetention toml.Duration `toml:"trash-retention"`
 }
 
 // NewConfig returns an instance of Config with defaults.
 func NewConfig() Config {
-	return Config{Enabled: true, CheckInterval: toml.Duration(30 * time.Minute)}
+	return Config{
+		Enabled:        true,
+		CheckInterval:  toml.Duration(30 * time.Minute),
+		TrashRetention: toml.Duration(7 * 24 * time.Hour),
+	}
 }
 
 // Validate returns an error if the Config is invalid.
@@ -31,6 +45,14 @@ func (c Config) Validate() error {
 		return errors.New("check-interval must be positive")
 	}
 
+	if c.GracePeriod < 0 {
+		return errors.New("grace-period must not be negative")
+	}
+
+	if c.TrashRetention < 0 {
+		return errors.New("trash-retention must not be negative")
+	}
+
 	return nil
 }
 
@@ -43,7 +65,9 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 	}
 
 	return diagnostics.RowFromMap(map[string]interface{}{
-		"enabled":        true,
-		"check-interval": c.CheckInterval,
+		"enabled":         true,
+		"check-interval":  c.CheckInterval,
+		"grace-period":    c.GracePeriod,
+		"trash-retention": c.TrashRetention,
 	}), nil
 }
@@ -14,6 +14,8 @@ func TestConfig_Parse(t *testing.T) {
 	if _, err := toml.Decode(`
 enabled = true
 check-interval = "1s"
+grace-period = "24h"
+trash-retention = "48h"
 `, &c); err != nil {
 		t.Fatal(err)
 	}
@@ -23,6 +25,10 @@ check-interval = "1s"
 		t.Fatalf("unexpected enabled state: %v", c.Enabled)
 	} else if time.Duration(c.CheckInterval) != time.Second {
 		t.Fatalf("unexpected check interval: %v", c.CheckInterval)
+	} else if time.Duration(c.GracePeriod) != 24*time.Hour {
+		t.Fatalf("unexpected grace period: %v", c.GracePeriod)
+	} else if time.Duration(c.TrashRetention) != 48*time.Hour {
+		t.Fatalf("unexpected trash retention: %v", c.TrashRetention)
 	}
 }
 
@@ -44,6 +50,18 @@ func TestConfig_Validate(t *testing.T) {
 		t.Fatal("expected error for negative check-interval, got nil")
 	}
 
+	c = retention.NewConfig()
+	c.GracePeriod = -1
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for negative grace-period, got nil")
+	}
+
+	c = retention.NewConfig()
+	c.TrashRetention = -1
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for negative trash-retention, got nil")
+	}
+
 	c.Enabled = false
 	if err := c.Validate(); err != nil {
 		t.Fatalf("unexpected validation fail from disabled config: %s", err)
@@ -7,19 +7,29 @@ import (
 
 	"github.com/freetsdb/freetsdb/logger"
 	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tsdb"
 	"go.uber.org/zap"
 )
 
 // Service represents the retention policy enforcement service.
 type Service struct {
 	MetaClient interface {
+		NodeID() uint64
 		Databases() ([]meta.DatabaseInfo, error)
 		DeleteShardGroup(database, policy string, id uint64) error
+		ExpireShardGroup(database, policy string, id uint64, purgeAt time.Time) error
 		PruneShardGroups() error
+		SetShardOwnerTier(shardID, nodeID uint64, tier string) error
+		Dropped() []meta.DroppedInfo
+		PruneDropped(t time.Time) error
 	}
 	TSDBStore interface {
 		ShardIDs() []uint64
 		DeleteShard(shardID uint64) error
+		ColdPath() string
+		ShardTier(id uint64) string
+		MoveShardToCold(id uint64) error
+		PurgeTrash(before time.Time) error
 	}
 
 	config Config
@@ -93,18 +103,43 @@ func (s *Service) run() {
 			// Without the message, they may see the error message and assume they
 			// have to do it manually.
 			var retryNeeded bool
+			now := time.Now().UTC()
 			dbs, _ := s.MetaClient.Databases()
 			for _, d := range dbs {
 				for _, r := range d.RetentionPolicies {
-					// Build list of already deleted shards.
+					// Build list of already deleted shards. Shards of groups
+					// pending deletion are kept until their grace period ends.
 					for _, g := range r.DeletedShardGroups() {
+						if g.PendingDeletion(now) {
+							continue
+						}
 						for _, sh := range g.Shards {
 							deletedShardIDs[sh.ID] = deletionInfo{db: d.Name, rp: r.Name}
 						}
 					}
 
 					// Determine all shards that have expired and need to be deleted.
-					for _, g := range r.ExpiredShardGroups(time.Now().UTC()) {
+					for _, g := range r.ExpiredShardGroups(now) {
+						if s.config.GracePeriod > 0 {
+							purgeAt := now.Add(time.Duration(s.config.GracePeriod))
+							if err := s.MetaClient.ExpireShardGroup(d.Name, r.Name, g.ID, purgeAt); err != nil {
+								log.Info("Failed to expire shard group",
+									logger.Database(d.Name),
+									logger.ShardGroup(g.ID),
+									logger.RetentionPolicy(r.Name),
+									zap.Error(err))
+								retryNeeded = true
+								continue
+							}
+
+							log.Info("Expired shard group, pending deletion",
+								logger.Database(d.Name),
+								logger.ShardGroup(g.ID),
+								logger.RetentionPolicy(r.Name),
+								zap.Time("purge_at", purgeAt))
+							continue
+						}
+
 						if err := s.MetaClient.DeleteShardGroup(d.Name, r.Name, g.ID); err != nil {
 							log.Info("Failed to delete shard group",
 								logger.Database(d.Name),
@@ -152,6 +187,14 @@ func (s *Service) run() {
 				retryNeeded = true
 			}
 
+			if s.TSDBStore.ColdPath() != "" {
+				s.moveColdShards(log, dbs)
+			}
+
+			if s.config.TrashRetention > 0 && !s.purgeTrash(log, now) {
+				retryNeeded = true
+			}
+
 			if retryNeeded {
 				log.Info("One or more errors occurred during shard deletion and will be retried on the next check", logger.DurationLiteral("check_interval", time.Duration(s.config.CheckInterval)))
 			}
@@ -160,3 +203,82 @@ func (s *Service) run() {
 		}
 	}
 }
+
+// purgeTrash removes the dropped objects older than the trash retention from
+// the local trash dir and from the meta store. It returns false if either
+// failed.
+func (s *Service) purgeTrash(log *zap.Logger, now time.Time) bool {
+	before := now.Add(-time.Duration(s.config.TrashRetention))
+	if err := s.TSDBStore.PurgeTrash(before); err != nil {
+		log.Info("Problem purging trash", zap.Error(err))
+		return false
+	}
+
+	for _, d := range s.MetaClient.Dropped() {
+		if !d.DroppedAt.Before(before) {
+			continue
+		}
+		if err := s.MetaClient.PruneDropped(before); err != nil {
+			log.Info("Problem pruning dropped objects", zap.Error(err))
+			return false
+		}
+		break
+	}
+	return true
+}
+
+// moveColdShards moves the local shards of the shard groups past the cold
+// duration of their retention policy to the cold dir. Shards that are not
+// fully compacted yet are moved on a later check.
+func (s *Service) moveColdShards(log *zap.Logger, dbs []meta.DatabaseInfo) {
+	local := make(map[uint64]struct{})
+	for _, id := range s.TSDBStore.ShardIDs() {
+		local[id] = struct{}{}
+	}
+
+	nodeID := s.MetaClient.NodeID()
+	now := time.Now().UTC()
+	for _, d := range dbs {
+		for _, r := range d.RetentionPolicies {
+			for _, g := range r.ColdShardGroups(now) {
+				for _, sh := range g.Shards {
+					if _, ok := local[sh.ID]; !ok {
+						continue
+					}
+
+					if s.TSDBStore.ShardTier(sh.ID) != tsdb.ShardTierCold {
+						if err := s.TSDBStore.MoveShardToCold(sh.ID); err == tsdb.ErrShardNotIdle {
+							continue
+						} else if err != nil {
+							log.Info("Failed to move shard to cold dir",
+								logger.Database(d.Name),
+								logger.Shard(sh.ID),
+								logger.RetentionPolicy(r.Name),
+								zap.Error(err))
+							continue
+						}
+						log.Info("Moved shard to cold dir",
+							logger.Database(d.Name),
+							logger.Shard(sh.ID),
+							logger.RetentionPolicy(r.Name))
+					}
+
+					// Record the tier in the meta store so every node can
+					// report it.
+					for _, owner := range sh.Owners {
+						if owner.NodeID != nodeID || owner.Tier == tsdb.ShardTierCold {
+							continue
+						}
+						if err := s.MetaClient.SetShardOwnerTier(sh.ID, nodeID, tsdb.ShardTierCold); err != nil {
+							log.Info("Failed to record shard tier",
+								logger.Database(d.Name),
+								logger.Shard(sh.ID),
+								logger.RetentionPolicy(r.Name),
+								zap.Error(err))
+						}
+					}
+				}
+			}
+		}
+	}
+}
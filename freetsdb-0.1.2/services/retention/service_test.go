@@ -116,8 +116,8 @@ func TestService_CheckShards(t *testing.T) {
 	config := retention.NewConfig()
 	config.CheckInterval = toml.Duration(10 * time.Millisecond)
 	s := NewService(config)
-	s.MetaClient.DatabasesFn = func() []meta.DatabaseInfo {
-		return data
+	s.MetaClient.DatabasesFn = func() ([]meta.DatabaseInfo, error) {
+		return data, nil
 	}
 
 	done := make(chan struct{})
@@ -206,6 +206,277 @@ func TestService_CheckShards(t *testing.T) {
 	}
 }
 
+func TestService_MoveColdShards(t *testing.T) {
+	now := time.Now().UTC()
+	data := []meta.DatabaseInfo{
+		{
+			Name: "db0",
+
+			DefaultRetentionPolicy: "rp0",
+			RetentionPolicies: []meta.RetentionPolicyInfo{
+				{
+					Name:               "rp0",
+					ReplicaN:           1,
+					ShardGroupDuration: time.Hour,
+					ColdAfter:          time.Hour,
+					ShardGroups: []meta.ShardGroupInfo{
+						{
+							ID:        1,
+							StartTime: now.Add(-3 * time.Hour),
+							EndTime:   now.Add(-2 * time.Hour),
+							Shards: []meta.ShardInfo{
+								{ID: 2, Owners: []meta.ShardOwner{{NodeID: 1}}},
+							},
+						},
+						{
+							ID:        3,
+							StartTime: now.Add(-1 * time.Hour),
+							EndTime:   now,
+							Shards: []meta.ShardInfo{
+								{ID: 4, Owners: []meta.ShardOwner{{NodeID: 1}}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	config := retention.NewConfig()
+	config.CheckInterval = toml.Duration(10 * time.Millisecond)
+	s := NewService(config)
+	s.MetaClient.NodeIDFn = func() uint64 { return 1 }
+	s.MetaClient.DatabasesFn = func() ([]meta.DatabaseInfo, error) {
+		return data, nil
+	}
+	s.MetaClient.PruneShardGroupsFn = func() error { return nil }
+
+	var mu sync.Mutex
+	cold := make(map[uint64]bool)
+	s.TSDBStore.ShardIDsFn = func() []uint64 { return []uint64{2, 4} }
+	s.TSDBStore.ColdPathFn = func() string { return "/cold" }
+	s.TSDBStore.ShardTierFn = func(id uint64) string {
+		mu.Lock()
+		defer mu.Unlock()
+		if cold[id] {
+			return "cold"
+		}
+		return "hot"
+	}
+	s.TSDBStore.MoveShardToColdFn = func(id uint64) error {
+		mu.Lock()
+		defer mu.Unlock()
+		cold[id] = true
+		return nil
+	}
+
+	done := make(chan struct{})
+	s.MetaClient.SetShardOwnerTierFn = func(shardID, nodeID uint64, tier string) error {
+		if shardID != 2 || nodeID != 1 || tier != "cold" {
+			t.Errorf("unexpected shard tier: shard=%d node=%d tier=%s", shardID, nodeID, tier)
+		}
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+		return nil
+	}
+
+	if err := s.Open(); err != nil {
+		t.Fatalf("unexpected open error: %s", err)
+	}
+	defer func() {
+		if err := s.Close(); err != nil {
+			t.Fatalf("unexpected close error: %s", err)
+		}
+	}()
+
+	timer := time.NewTimer(time.Second)
+	select {
+	case <-done:
+		timer.Stop()
+	case <-timer.C:
+		t.Fatal("timeout waiting for shard to be moved")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if exp := map[uint64]bool{2: true}; !reflect.DeepEqual(cold, exp) {
+		t.Fatalf("unexpected cold shards: %v", cold)
+	}
+}
+
+func TestService_GracePeriod(t *testing.T) {
+	now := time.Now().UTC()
+	data := []meta.DatabaseInfo{
+		{
+			Name: "db0",
+
+			DefaultRetentionPolicy: "rp0",
+			RetentionPolicies: []meta.RetentionPolicyInfo{
+				{
+					Name:               "rp0",
+					ReplicaN:           1,
+					Duration:           time.Hour,
+					ShardGroupDuration: time.Hour,
+					ShardGroups: []meta.ShardGroupInfo{
+						{
+							ID:        1,
+							StartTime: now.Add(-4 * time.Hour),
+							EndTime:   now.Add(-3 * time.Hour),
+							Shards:    []meta.ShardInfo{{ID: 2}},
+						},
+						{
+							ID:        3,
+							StartTime: now.Add(-5 * time.Hour),
+							EndTime:   now.Add(-4 * time.Hour),
+							DeletedAt: now.Add(-time.Hour),
+							PurgeAt:   now.Add(time.Hour),
+							Shards:    []meta.ShardInfo{{ID: 4}},
+						},
+						{
+							ID:        5,
+							StartTime: now.Add(-6 * time.Hour),
+							EndTime:   now.Add(-5 * time.Hour),
+							DeletedAt: now.Add(-2 * time.Hour),
+							PurgeAt:   now.Add(-time.Hour),
+							Shards:    []meta.ShardInfo{{ID: 6}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	config := retention.NewConfig()
+	config.CheckInterval = toml.Duration(10 * time.Millisecond)
+	config.GracePeriod = toml.Duration(24 * time.Hour)
+	s := NewService(config)
+	s.MetaClient.DatabasesFn = func() ([]meta.DatabaseInfo, error) {
+		return data, nil
+	}
+
+	var mu sync.Mutex
+	expired := make(map[uint64]time.Time)
+	s.MetaClient.ExpireShardGroupFn = func(database, policy string, id uint64, purgeAt time.Time) error {
+		mu.Lock()
+		defer mu.Unlock()
+		expired[id] = purgeAt
+		return nil
+	}
+	s.MetaClient.DeleteShardGroupFn = func(database, policy string, id uint64) error {
+		t.Errorf("unexpected deletion of shard group %d", id)
+		return nil
+	}
+
+	deletedShards := make(map[uint64]struct{})
+	s.TSDBStore.ShardIDsFn = func() []uint64 { return []uint64{2, 4, 6} }
+	s.TSDBStore.DeleteShardFn = func(shardID uint64) error {
+		mu.Lock()
+		defer mu.Unlock()
+		deletedShards[shardID] = struct{}{}
+		return nil
+	}
+
+	done := make(chan struct{})
+	s.MetaClient.PruneShardGroupsFn = func() error {
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+		return nil
+	}
+
+	if err := s.Open(); err != nil {
+		t.Fatalf("unexpected open error: %s", err)
+	}
+
+	timer := time.NewTimer(time.Second)
+	select {
+	case <-done:
+		timer.Stop()
+	case <-timer.C:
+		t.Fatal("timeout waiting for retention check")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("unexpected close error: %s", err)
+	}
+
+	if purgeAt, ok := expired[1]; !ok || purgeAt.Before(now.Add(24*time.Hour)) {
+		t.Fatalf("unexpected expired shard groups: %v", expired)
+	} else if len(expired) != 1 {
+		t.Fatalf("unexpected expired shard groups: %v", expired)
+	}
+	if exp := map[uint64]struct{}{6: {}}; !reflect.DeepEqual(deletedShards, exp) {
+		t.Fatalf("unexpected deleted shards: %v", deletedShards)
+	}
+}
+
+func TestService_PurgeTrash(t *testing.T) {
+	now := time.Now().UTC()
+	config := retention.NewConfig()
+	config.CheckInterval = toml.Duration(10 * time.Millisecond)
+	config.TrashRetention = toml.Duration(24 * time.Hour)
+	s := NewService(config)
+	s.MetaClient.DatabasesFn = func() ([]meta.DatabaseInfo, error) {
+		return nil, nil
+	}
+	s.MetaClient.PruneShardGroupsFn = func() error { return nil }
+	s.TSDBStore.ShardIDsFn = func() []uint64 { return nil }
+	s.MetaClient.DroppedFn = func() []meta.DroppedInfo {
+		return []meta.DroppedInfo{
+			{Type: meta.DroppedDatabase, Database: "db0", DroppedAt: now.Add(-48 * time.Hour)},
+			{Type: meta.DroppedDatabase, Database: "db1", DroppedAt: now},
+		}
+	}
+
+	var mu sync.Mutex
+	var purged time.Time
+	s.TSDBStore.PurgeTrashFn = func(before time.Time) error {
+		mu.Lock()
+		defer mu.Unlock()
+		purged = before
+		return nil
+	}
+
+	done := make(chan time.Time, 1)
+	s.MetaClient.PruneDroppedFn = func(before time.Time) error {
+		select {
+		case done <- before:
+		default:
+		}
+		return nil
+	}
+
+	if err := s.Open(); err != nil {
+		t.Fatalf("unexpected open error: %s", err)
+	}
+
+	var pruned time.Time
+	timer := time.NewTimer(time.Second)
+	select {
+	case pruned = <-done:
+		timer.Stop()
+	case <-timer.C:
+		t.Fatal("timeout waiting for trash purge")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("unexpected close error: %s", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !purged.Equal(pruned) {
+		t.Fatalf("unexpected purge times: store=%s meta=%s", purged, pruned)
+	} else if !pruned.Before(now.Add(-23*time.Hour)) || pruned.Before(now.Add(-25*time.Hour)) {
+		t.Fatalf("unexpected prune time: %s", pruned)
+	}
+}
+
 // This reproduces https://github.com/freetsdb/freetsdb/issues/8819
 func TestService_8819_repro(t *testing.T) {
 	for i := 0; i < 1000; i++ {
@@ -278,10 +549,10 @@ func testService_8819_repro(t *testing.T) (*Service, chan error, chan struct{})
 		}
 	}
 
-	s.MetaClient.DatabasesFn = func() []meta.DatabaseInfo {
+	s.MetaClient.DatabasesFn = func() ([]meta.DatabaseInfo, error) {
 		mu.Lock()
 		defer mu.Unlock()
-		return databases
+		return databases, nil
 	}
 
 	s.MetaClient.DeleteShardGroupFn = func(database string, policy string, id uint64) error {
@@ -391,6 +662,13 @@ func NewService(c retention.Config) *Service {
 	l := logger.New(&s.LogBuf)
 	s.WithLogger(l)
 
+	// Shards are only moved to a cold dir when configured.
+	s.TSDBStore.ColdPathFn = func() string { return "" }
+
+	// Nothing is in the trash unless a test adds it.
+	s.MetaClient.DroppedFn = func() []meta.DroppedInfo { return nil }
+	s.TSDBStore.PurgeTrashFn = func(before time.Time) error { return nil }
+
 	s.Service.MetaClient = s.MetaClient
 	s.Service.TSDBStore = s.TSDBStore
 	return s
@@ -20,12 +20,13 @@ import (
 
 // Client provides an API for the snapshotter service.
 type Client struct {
-	host string
+	host   string
+	dialer *tcp.Dialer
 }
 
-// NewClient returns a new *Client.
-func NewClient(host string) *Client {
-	return &Client{host: host}
+// NewClient returns a new *Client connecting with dialer.
+func NewClient(host string, dialer *tcp.Dialer) *Client {
+	return &Client{host: host, dialer: dialer}
 }
 
 // takes a request object, writes a Base64 encoding to the tcp connection, and then sends the request to the snapshotter service.
@@ -34,7 +35,7 @@ func (c *Client) UpdateMeta(req *Request, upStream io.Reader) (map[uint64]uint64
 	var err error
 
 	// Connect to snapshotter service.
-	conn, err := tcp.Dial("tcp", c.host, MuxHeader)
+	conn, err := c.dialer.Dial("tcp", c.host, MuxHeader)
 	if err != nil {
 		return nil, err
 	}
@@ -102,14 +103,26 @@ func decodeUintPair(bits []byte) (uint64, uint64, error) {
 }
 
 func (c *Client) UploadShard(shardID, newShardID uint64, destinationDatabase, restoreRetention string, tr *tar.Reader) error {
-	conn, err := tcp.Dial("tcp", c.host, MuxHeader)
+	return c.uploadShard(RequestShardUpdate, newShardID, destinationDatabase, restoreRetention, tr)
+}
+
+// ImportShard uploads a shard tar file and adds its data to newShardID,
+// keeping the data already in the shard. The shard is created if it does not
+// exist on the node.
+func (c *Client) ImportShard(newShardID uint64, destinationDatabase, restoreRetention string, tr *tar.Reader) error {
+	return c.uploadShard(RequestShardImport, newShardID, destinationDatabase, restoreRetention, tr)
+}
+
+// uploadShard sends a shard tar file, renaming its files to newShardID.
+func (c *Client) uploadShard(typ RequestType, newShardID uint64, destinationDatabase, restoreRetention string, tr *tar.Reader) error {
+	conn, err := c.dialer.Dial("tcp", c.host, MuxHeader)
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
 
 	var shardBytes [9]byte
-	shardBytes[0] = byte(RequestShardUpdate)
+	shardBytes[0] = byte(typ)
 	binary.BigEndian.PutUint64(shardBytes[1:], newShardID)
 	if _, err := conn.Write(shardBytes[:]); err != nil {
 		return err
@@ -191,7 +204,7 @@ func (c *Client) MetastoreBackup() (*meta.Data, error) {
 // doRequest sends a request to the snapshotter service and returns the result.
 func (c *Client) doRequest(req *Request) ([]byte, error) {
 	// Connect to snapshotter service.
-	conn, err := tcp.Dial("tcp", c.host, MuxHeader)
+	conn, err := c.dialer.Dial("tcp", c.host, MuxHeader)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +73,7 @@ func TestClient_MetastoreBackup_InvalidMetadata(t *testing.T) {
 		conn.Write(buf.Bytes())
 	}()
 
-	c := snapshotter.NewClient(l.Addr().String())
+	c := snapshotter.NewClient(l.Addr().String(), nil)
 	_, err = c.MetastoreBackup()
 	if err == nil || err.Error() != "invalid metadata received" {
 		t.Errorf("unexpected error: got=%q want=%q", err, "invalid metadata received")
@@ -37,15 +37,18 @@ type Service struct {
 	MetaClient interface {
 		encoding.BinaryMarshaler
 		Database(name string) *meta.DatabaseInfo
+		ShardOwner(shardID uint64) (database, policy string, sgi *meta.ShardGroupInfo)
 	}
 
 	TSDBStore interface {
 		BackupShard(id uint64, since time.Time, w io.Writer) error
 		ExportShard(id uint64, ExportStart time.Time, ExportEnd time.Time, w io.Writer) error
+		BackupShardIncremental(id uint64, from *tsdb.BackupPosition, w io.Writer) error
 		Shard(id uint64) *tsdb.Shard
 		ShardRelativePath(id uint64) (string, error)
 		SetShardEnabled(shardID uint64, enabled bool) error
 		RestoreShard(id uint64, r io.Reader) error
+		ImportShard(id uint64, r io.Reader) error
 		CreateShard(database, retentionPolicy string, shardID uint64, enabled bool) error
 	}
 
@@ -121,8 +124,11 @@ func (s *Service) handleConn(conn net.Conn) error {
 		return err
 	}
 
-	if RequestType(typ[0]) == RequestShardUpdate {
+	switch RequestType(typ[0]) {
+	case RequestShardUpdate:
 		return s.updateShardsLive(conn)
+	case RequestShardImport:
+		return s.importShardLive(conn)
 	}
 
 	r, bytes, err := s.readRequest(conn)
@@ -139,6 +145,10 @@ func (s *Service) handleConn(conn net.Conn) error {
 		if err := s.TSDBStore.ExportShard(r.ShardID, r.ExportStart, r.ExportEnd, conn); err != nil {
 			return err
 		}
+	case RequestShardIncrementalBackup:
+		if err := s.TSDBStore.BackupShardIncremental(r.ShardID, r.Position, conn); err != nil {
+			return err
+		}
 	case RequestMetastoreBackup:
 		if err := s.writeMetaStore(conn); err != nil {
 			return err
@@ -172,6 +182,33 @@ func (s *Service) updateShardsLive(conn net.Conn) error {
 	return s.TSDBStore.RestoreShard(sid, conn)
 }
 
+// importShardLive adds the data of a shard tar file to a shard, creating the
+// shard first if it does not exist on this node yet.
+func (s *Service) importShardLive(conn net.Conn) error {
+	var sidBytes [8]byte
+	if _, err := io.ReadFull(conn, sidBytes[:]); err != nil {
+		return err
+	}
+	sid := binary.BigEndian.Uint64(sidBytes[:])
+
+	if s.TSDBStore.Shard(sid) == nil {
+		database, policy, sgi := s.MetaClient.ShardOwner(sid)
+		if sgi == nil {
+			return fmt.Errorf("shard not found: id=%d", sid)
+		}
+		if err := s.TSDBStore.CreateShard(database, policy, sid, true); err != nil {
+			return err
+		}
+	}
+
+	if err := s.TSDBStore.SetShardEnabled(sid, false); err != nil {
+		return err
+	}
+	defer s.TSDBStore.SetShardEnabled(sid, true)
+
+	return s.TSDBStore.ImportShard(sid, conn)
+}
+
 func (s *Service) updateMetaStore(conn net.Conn, bits []byte, backupDBName, restoreDBName, backupRPName, restoreRPName string) error {
 	md := meta.Data{}
 	err := md.UnmarshalBinary(bits)
@@ -440,6 +477,15 @@ const (
 	// RequestShardUpdate will initiate the upload of a shard data tar file
 	// and have the engine import the data.
 	RequestShardUpdate
+
+	// RequestShardImport will initiate the upload of a shard data tar file
+	// and have the engine add its files to the shard as new files, so the
+	// data of several backed up shards can be combined into one shard.
+	RequestShardImport
+
+	// RequestShardIncrementalBackup represents a request for the data added to
+	// a shard since the position of a previous incremental backup.
+	RequestShardIncrementalBackup
 )
 
 // Request represents a request for a specific backup or for information
@@ -455,6 +501,7 @@ type Request struct {
 	ExportStart            time.Time
 	ExportEnd              time.Time
 	UploadSize             int64
+	Position               *tsdb.BackupPosition
 }
 
 // Response contains the relative paths for all the shards on this server
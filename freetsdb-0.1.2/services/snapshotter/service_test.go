@@ -1,6 +1,8 @@
 package snapshotter_test
 
 import (
+	"archive/tar"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -176,7 +178,7 @@ func TestSnapshotter_RequestMetastoreBackup(t *testing.T) {
 	}
 	defer conn.Close()
 
-	c := snapshotter.NewClient(l.Addr().String())
+	c := snapshotter.NewClient(l.Addr().String(), nil)
 	if got, err := c.MetastoreBackup(); err != nil {
 		t.Errorf("unable to obtain metastore backup: %s", err)
 		return
@@ -379,6 +381,75 @@ func TestSnapshotter_RequestRetentionPolicyInfo(t *testing.T) {
 	}
 }
 
+func TestSnapshotter_ImportShard(t *testing.T) {
+	s, l, err := NewTestService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var created bool
+	done := make(chan []string, 1)
+
+	var tsdbStore internal.TSDBStoreMock
+	tsdbStore.ShardFn = func(id uint64) *tsdb.Shard { return nil }
+	tsdbStore.CreateShardFn = func(database, policy string, shardID uint64, enabled bool) error {
+		if database != "db0" || policy != "rp0" || shardID != 2 {
+			t.Errorf("unexpected shard: %s.%s %d", database, policy, shardID)
+		}
+		created = true
+		return nil
+	}
+	tsdbStore.SetShardEnabledFn = func(shardID uint64, enabled bool) error { return nil }
+	tsdbStore.ImportShardFn = func(id uint64, r io.Reader) error {
+		var names []string
+		tr := tar.NewReader(r)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+			names = append(names, hdr.Name)
+		}
+		done <- names
+		return nil
+	}
+
+	s.MetaClient = &MetaClient{Data: data}
+	s.TSDBStore = &tsdbStore
+
+	if err := s.Open(); err != nil {
+		t.Fatalf("unexpected open error: %s", err)
+	}
+	defer s.Close()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	tw.WriteHeader(&tar.Header{Name: "db1/autogen/7/000000001-000000001.tsm", Mode: 0600, Size: 4})
+	tw.Write([]byte("data"))
+	tw.Close()
+
+	client := snapshotter.NewClient(l.Addr().String(), nil)
+	if err := client.ImportShard(2, "db0", "rp0", tar.NewReader(&buf)); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case names := <-done:
+		if exp := []string{"db0/rp0/2/000000001-000000001.tsm"}; !reflect.DeepEqual(names, exp) {
+			t.Errorf("unexpected files: got=%v want=%v", names, exp)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for import")
+	}
+
+	if !created {
+		t.Error("expected shard to be created")
+	}
+}
+
 func TestSnapshotter_InvalidRequest(t *testing.T) {
 	s, l, err := NewTestService()
 	if err != nil {
@@ -439,6 +510,21 @@ func (m *MetaClient) MarshalBinary() ([]byte, error) {
 	return m.Data.MarshalBinary()
 }
 
+func (m *MetaClient) ShardOwner(shardID uint64) (database, policy string, sgi *meta.ShardGroupInfo) {
+	for _, dbi := range m.Data.Databases {
+		for _, rpi := range dbi.RetentionPolicies {
+			for i := range rpi.ShardGroups {
+				for _, si := range rpi.ShardGroups[i].Shards {
+					if si.ID == shardID {
+						return dbi.Name, rpi.Name, &rpi.ShardGroups[i]
+					}
+				}
+			}
+		}
+	}
+	return "", "", nil
+}
+
 func (m *MetaClient) Database(name string) *meta.DatabaseInfo {
 	for _, dbi := range m.Data.Databases {
 		if dbi.Name == name {
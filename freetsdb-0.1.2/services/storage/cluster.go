@@ -0,0 +1,296 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/freetsdb/freetsdb/models"
+	"github.com/freetsdb/freetsdb/platform/storage/reads"
+	"github.com/freetsdb/freetsdb/platform/storage/reads/datatypes"
+	"github.com/freetsdb/freetsdb/platform/tsdb/cursors"
+	"github.com/freetsdb/freetsdb/services/influxql"
+	"github.com/freetsdb/freetsdb/services/meta"
+	"github.com/freetsdb/freetsdb/tsdb"
+)
+
+// RemoteStore reads series data from shards held by other data nodes.
+type RemoteStore interface {
+	// SeriesCursor returns a cursor over the series and fields matching
+	// predicate in shards on a node. Rows only have Name, SeriesTags and
+	// Field set, and are ordered as the local series cursor orders them.
+	SeriesCursor(ctx context.Context, nodeID uint64, shardIDs []uint64, predicate *datatypes.Predicate) (reads.SeriesCursor, error)
+
+	// CursorIterators returns an iterator over each list of shards on a
+	// node. The iterators may share a connection, so only the most recently
+	// created cursor can be read. Connections are released when ctx is done.
+	CursorIterators(ctx context.Context, nodeID uint64, shardIDs [][]uint64) cursors.CursorIterators
+}
+
+// NewSeriesCursor returns a cursor over the series and fields matching
+// predicate in shards, or nil if there are none.
+func NewSeriesCursor(ctx context.Context, predicate *datatypes.Predicate, shards []*tsdb.Shard) (reads.SeriesCursor, error) {
+	cur, err := newIndexSeriesCursor(ctx, predicate, shards)
+	if cur == nil || err != nil {
+		return nil, err
+	}
+	return cur, nil
+}
+
+// shardMapping holds the shards a read covers, split by where they are held.
+type shardMapping struct {
+	// local holds the shards on this node.
+	local []*tsdb.Shard
+
+	// remote holds the shards read from each other node.
+	remote map[uint64][]uint64
+
+	// query holds a cursor iterator for every shard, local or remote, in
+	// shard group order.
+	query cursors.CursorIterators
+}
+
+// mapShards returns the shards of database and rp overlapping the time range,
+// resolving shards not held locally to one of their remote owners.
+func (s *Store) mapShards(ctx context.Context, database, rp string, desc bool, start, end int64) (*shardMapping, error) {
+	groups, err := s.MetaClient.ShardGroupsByTimeRange(database, rp, time.Unix(0, start), time.Unix(0, end))
+	if err != nil {
+		return nil, err
+	}
+
+	if desc {
+		sort.Sort(sort.Reverse(meta.ShardGroupInfos(groups)))
+	} else {
+		sort.Sort(meta.ShardGroupInfos(groups))
+	}
+
+	m := &shardMapping{remote: make(map[uint64][]uint64)}
+	local := make([]cursors.CursorIterators, len(groups))
+	remote := make(map[uint64][][]uint64)
+	for i, g := range groups {
+		for _, si := range g.Shards {
+			if sh := s.TSDBStore.Shard(si.ID); sh != nil {
+				m.local = append(m.local, sh)
+				if itr, err := sh.CreateCursorIterator(ctx); itr != nil && err == nil {
+					local[i] = append(local[i], itr)
+				}
+				continue
+			}
+
+			nodeID := s.shardOwner(si)
+			if nodeID == 0 {
+				continue
+			}
+			if remote[nodeID] == nil {
+				remote[nodeID] = make([][]uint64, len(groups))
+			}
+			remote[nodeID][i] = append(remote[nodeID][i], si.ID)
+			m.remote[nodeID] = append(m.remote[nodeID], si.ID)
+		}
+	}
+
+	// Interleave the remote iterators with the local ones so that cursors
+	// are read in shard group order.
+	query := local
+	for nodeID, shardIDs := range remote {
+		itrs := s.Remote.CursorIterators(ctx, nodeID, shardIDs)
+		for i := range shardIDs {
+			if len(shardIDs[i]) > 0 {
+				query[i] = append(query[i], itrs[i])
+			}
+		}
+	}
+	for _, itrs := range query {
+		m.query = append(m.query, itrs...)
+	}
+	return m, nil
+}
+
+// shardOwner returns a remote owner of a shard, preferring owners that are
+// not marked down. Returns zero if there is no remote owner or the store has
+// no RemoteStore.
+func (s *Store) shardOwner(si meta.ShardInfo) uint64 {
+	if s.Remote == nil {
+		return 0
+	}
+
+	var down uint64
+	for _, owner := range si.Owners {
+		if s.Node != nil && owner.NodeID == s.Node.ID {
+			continue
+		}
+		ni, err := s.MetaClient.DataNode(owner.NodeID)
+		if err != nil || ni == nil {
+			continue
+		} else if !ni.Down() {
+			return owner.NodeID
+		} else if down == 0 {
+			down = owner.NodeID
+		}
+	}
+	return down
+}
+
+// newSeriesCursor returns a cursor over the series matching predicate in the
+// shards of m, or nil if there are none.
+func (s *Store) newSeriesCursor(ctx context.Context, predicate *datatypes.Predicate, m *shardMapping) (reads.SeriesCursor, error) {
+	if len(m.remote) == 0 {
+		return NewSeriesCursor(ctx, predicate, m.local)
+	}
+
+	var a []reads.SeriesCursor
+	closeAll := func() {
+		for _, cur := range a {
+			cur.Close()
+		}
+	}
+
+	if len(m.local) > 0 {
+		cur, err := NewSeriesCursor(ctx, predicate, m.local)
+		if err != nil {
+			return nil, err
+		} else if cur != nil {
+			a = append(a, cur)
+		}
+	}
+
+	for nodeID, shardIDs := range m.remote {
+		cur, err := s.Remote.SeriesCursor(ctx, nodeID, shardIDs, predicate)
+		if err != nil {
+			closeAll()
+			return nil, err
+		} else if cur != nil {
+			a = append(a, cur)
+		}
+	}
+
+	if len(a) == 0 {
+		return nil, nil
+	}
+
+	cur, err := newClusterSeriesCursor(predicate, a, m.query)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	return cur, nil
+}
+
+// clusterSeriesCursor merges the ordered series of local and remote series
+// cursors. Every row reads from the cursor iterators of all shards.
+type clusterSeriesCursor struct {
+	cursors []reads.SeriesCursor
+	heads   []*reads.SeriesRow
+	query   cursors.CursorIterators
+
+	cond         influxql.Expr
+	hasValueExpr bool
+
+	row  reads.SeriesRow
+	tags models.Tags
+	err  error
+}
+
+func newClusterSeriesCursor(predicate *datatypes.Predicate, a []reads.SeriesCursor, query cursors.CursorIterators) (*clusterSeriesCursor, error) {
+	c := &clusterSeriesCursor{
+		cursors: a,
+		heads:   make([]*reads.SeriesRow, len(a)),
+		query:   query,
+	}
+
+	if root := predicate.GetRoot(); root != nil {
+		cond, err := reads.NodeToExpr(root, measurementRemap)
+		if err != nil {
+			return nil, err
+		}
+		c.cond = cond
+		_, c.hasValueExpr = HasFieldKeyOrValue(cond)
+	}
+
+	for i, cur := range a {
+		c.heads[i] = cur.Next()
+	}
+	return c, nil
+}
+
+func (c *clusterSeriesCursor) Close() {
+	for _, cur := range c.cursors {
+		cur.Close()
+	}
+}
+
+func (c *clusterSeriesCursor) Err() error {
+	return c.err
+}
+
+func (c *clusterSeriesCursor) Next() *reads.SeriesRow {
+	// Find the lowest series and field across all cursors.
+	var min *reads.SeriesRow
+	for i, head := range c.heads {
+		if head == nil {
+			if err := c.cursors[i].Err(); err != nil && c.err == nil {
+				c.err = err
+			}
+			continue
+		}
+		if min == nil || compareSeriesRows(head, min) < 0 {
+			min = head
+		}
+	}
+	if min == nil || c.err != nil {
+		return nil
+	}
+
+	c.row.Name = append(c.row.Name[:0], min.Name...)
+	c.row.SeriesTags = copyTags(c.row.SeriesTags, min.SeriesTags)
+	c.row.Field = min.Field
+	c.row.Query = c.query
+
+	// Advance every cursor positioned on the same series and field.
+	for i, head := range c.heads {
+		if head != nil && compareSeriesRows(head, &c.row) == 0 {
+			c.heads[i] = c.cursors[i].Next()
+		}
+	}
+
+	c.tags = copyTags(c.tags, c.row.SeriesTags)
+	c.tags.Set(measurementKeyBytes, c.row.Name)
+	c.tags.Set(fieldKeyBytes, []byte(c.row.Field))
+	c.row.Tags = copyTags(c.row.Tags, c.tags)
+
+	c.row.ValueCond = nil
+	if c.cond != nil && c.hasValueExpr {
+		c.row.ValueCond = influxql.Reduce(c.cond, c)
+		if reads.IsTrueBooleanLiteral(c.row.ValueCond) {
+			c.row.ValueCond = nil
+		}
+	}
+
+	return &c.row
+}
+
+func (c *clusterSeriesCursor) Value(key string) (interface{}, bool) {
+	switch key {
+	case "_name":
+		return string(c.row.Name), true
+	case fieldKey:
+		return c.row.Field, true
+	case "$":
+		return nil, false
+	default:
+		return c.row.SeriesTags.GetString(key), true
+	}
+}
+
+// compareSeriesRows orders rows by measurement, tags and field.
+func compareSeriesRows(a, b *reads.SeriesRow) int {
+	if cmp := bytes.Compare(a.Name, b.Name); cmp != 0 {
+		return cmp
+	}
+	if cmp := models.CompareTags(a.SeriesTags, b.SeriesTags); cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(a.Field, b.Field)
+}
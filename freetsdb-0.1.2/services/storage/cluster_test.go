@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/freetsdb/freetsdb/platform/models"
+	"github.com/freetsdb/freetsdb/platform/storage/reads"
+	"github.com/google/go-cmp/cmp"
+)
+
+type sliceSeriesCursor struct {
+	rows []reads.SeriesRow
+}
+
+func newSliceSeriesCursor(keys ...string) *sliceSeriesCursor {
+	c := &sliceSeriesCursor{}
+	for _, key := range keys {
+		name, tags := models.ParseKeyBytes([]byte(key))
+		field := tags.GetString("_field")
+		tags.Delete([]byte("_field"))
+		c.rows = append(c.rows, reads.SeriesRow{Name: name, SeriesTags: tags, Field: field})
+	}
+	return c
+}
+
+func (c *sliceSeriesCursor) Close()     {}
+func (c *sliceSeriesCursor) Err() error { return nil }
+
+func (c *sliceSeriesCursor) Next() *reads.SeriesRow {
+	if len(c.rows) == 0 {
+		return nil
+	}
+	row := &c.rows[0]
+	c.rows = c.rows[1:]
+	return row
+}
+
+func TestClusterSeriesCursor_Next(t *testing.T) {
+	cur, err := newClusterSeriesCursor(nil, []reads.SeriesCursor{
+		newSliceSeriesCursor(
+			"cpu,_field=usage,host=a",
+			"cpu,_field=usage,host=c",
+			"mem,_field=free,host=a",
+		),
+		newSliceSeriesCursor(
+			"cpu,_field=idle,host=a",
+			"cpu,_field=usage,host=a",
+			"cpu,_field=usage,host=b",
+		),
+		newSliceSeriesCursor(),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cur.Close()
+
+	var got []string
+	for row := cur.Next(); row != nil; row = cur.Next() {
+		got = append(got, string(models.MakeKey(row.Name, row.Tags)))
+	}
+
+	exp := []string{
+		"cpu,_field=idle,_measurement=cpu,host=a",
+		"cpu,_field=usage,_measurement=cpu,host=a",
+		"cpu,_field=usage,_measurement=cpu,host=b",
+		"cpu,_field=usage,_measurement=cpu,host=c",
+		"mem,_field=free,_measurement=mem,host=a",
+	}
+	if !cmp.Equal(got, exp) {
+		t.Fatalf("unexpected series:\n%s", cmp.Diff(got, exp))
+	}
+}
@@ -4,11 +4,11 @@ import (
 	"context"
 	"errors"
 	"math"
-	"sort"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/types"
+	"github.com/freetsdb/freetsdb"
 	"github.com/freetsdb/freetsdb/models"
 	"github.com/freetsdb/freetsdb/services/meta"
 	"github.com/freetsdb/freetsdb/tsdb"
@@ -39,12 +39,19 @@ func GetReadSource(req *datatypes.ReadRequest) (*ReadSource, error) {
 type MetaClient interface {
 	Database(name string) *meta.DatabaseInfo
 	ShardGroupsByTimeRange(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error)
+	DataNode(id uint64) (*meta.NodeInfo, error)
 }
 
 type Store struct {
 	TSDBStore  *tsdb.Store
 	MetaClient MetaClient
 	Logger     *zap.Logger
+
+	// Node is the data node the store runs on.
+	Node *freetsdb.Node
+
+	// Remote, if set, is used to read shards not held by this node.
+	Remote RemoteStore
 }
 
 func NewStore(store *tsdb.Store, metaClient MetaClient) *Store {
@@ -60,31 +67,6 @@ func (s *Store) WithLogger(log *zap.Logger) {
 	s.Logger = log.With(zap.String("service", "store"))
 }
 
-func (s *Store) findShardIDs(database, rp string, desc bool, start, end int64) ([]uint64, error) {
-	groups, err := s.MetaClient.ShardGroupsByTimeRange(database, rp, time.Unix(0, start), time.Unix(0, end))
-	if err != nil {
-		return nil, err
-	}
-
-	if len(groups) == 0 {
-		return nil, nil
-	}
-
-	if desc {
-		sort.Sort(sort.Reverse(meta.ShardGroupInfos(groups)))
-	} else {
-		sort.Sort(meta.ShardGroupInfos(groups))
-	}
-
-	shardIDs := make([]uint64, 0, len(groups[0].Shards)*len(groups))
-	for _, g := range groups {
-		for _, si := range g.Shards {
-			shardIDs = append(shardIDs, si.ID)
-		}
-	}
-	return shardIDs, nil
-}
-
 func (s *Store) validateArgs(database, rp string, start, end int64) (string, string, int64, int64, error) {
 	di := s.MetaClient.Database(database)
 	if di == nil {
@@ -132,21 +114,19 @@ func (s *Store) Read(ctx context.Context, req *datatypes.ReadRequest) (reads.Res
 		return nil, err
 	}
 
-	shardIDs, err := s.findShardIDs(database, rp, req.Descending, start, end)
+	m, err := s.mapShards(ctx, database, rp, req.Descending, start, end)
 	if err != nil {
 		return nil, err
 	}
-	if len(shardIDs) == 0 { // TODO(jeff): this was a typed nil
+	if len(m.local) == 0 && len(m.remote) == 0 {
 		return nil, nil
 	}
 
-	var cur reads.SeriesCursor
-	if ic, err := newIndexSeriesCursor(ctx, req.Predicate, s.TSDBStore.Shards(shardIDs)); err != nil {
+	cur, err := s.newSeriesCursor(ctx, req.Predicate, m)
+	if err != nil {
 		return nil, err
-	} else if ic == nil { // TODO(jeff): this was a typed nil
+	} else if cur == nil {
 		return nil, nil
-	} else {
-		cur = ic
 	}
 
 	if req.SeriesLimit > 0 || req.SeriesOffset > 0 {
@@ -182,25 +162,19 @@ func (s *Store) GroupRead(ctx context.Context, req *datatypes.ReadRequest) (read
 		return nil, err
 	}
 
-	shardIDs, err := s.findShardIDs(database, rp, req.Descending, start, end)
+	m, err := s.mapShards(ctx, database, rp, req.Descending, start, end)
 	if err != nil {
 		return nil, err
 	}
-	if len(shardIDs) == 0 {
+	if len(m.local) == 0 && len(m.remote) == 0 {
 		return nil, nil
 	}
 
-	shards := s.TSDBStore.Shards(shardIDs)
-
 	req.TimestampRange.Start = start
 	req.TimestampRange.End = end
 
 	newCursor := func() (reads.SeriesCursor, error) {
-		cur, err := newIndexSeriesCursor(ctx, req.Predicate, shards)
-		if cur == nil || err != nil {
-			return nil, err
-		}
-		return cur, nil
+		return s.newSeriesCursor(ctx, req.Predicate, m)
 	}
 
 	rs := reads.NewGroupResultSet(ctx, req, newCursor)
@@ -0,0 +1,150 @@
+package tcp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// nonceSize is the size of the challenge sent to authenticating clients.
+const nonceSize = 32
+
+// ErrAuthenticationFailed is returned when a peer fails to answer the shared
+// secret challenge.
+var ErrAuthenticationFailed = errors.New("authentication failed")
+
+// Dialer connects to remote mux listeners, performing the TLS and shared
+// secret handshakes expected by a Mux with the same settings.  A nil Dialer
+// dials plain TCP without a shared secret.
+type Dialer struct {
+	// TLSConfig, if set, wraps connections in TLS.
+	TLSConfig *tls.Config
+
+	// Secret, if set, is used to answer the mux's HMAC challenge.
+	Secret []byte
+
+	// Timeout limits the time to connect and complete the handshakes.
+	// Zero means no timeout.
+	Timeout time.Duration
+}
+
+// Dial connects to a remote mux listener with a given header byte.
+func (d *Dialer) Dial(network, address string, header byte) (net.Conn, error) {
+	if d == nil {
+		return Dial(network, address, header)
+	}
+	return d.DialTimeout(network, address, header, d.Timeout)
+}
+
+// DialTimeout connects to a remote mux listener with a given header byte,
+// failing if the handshakes are not complete within timeout.
+func (d *Dialer) DialTimeout(network, address string, header byte, timeout time.Duration) (net.Conn, error) {
+	if d == nil {
+		return DialTimeout(network, address, header, timeout)
+	}
+
+	conn, err := net.DialTimeout(network, address, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
+
+	if d.TLSConfig != nil {
+		config := d.TLSConfig
+		if config.ServerName == "" && !config.InsecureSkipVerify {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				conn.Close()
+				return nil, err
+			}
+			config = config.Clone()
+			config.ServerName = host
+		}
+
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("tls handshake: %s", err)
+		}
+		conn = tlsConn
+	}
+
+	if _, err := conn.Write([]byte{header}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("write mux header: %s", err)
+	}
+
+	if len(d.Secret) > 0 {
+		if err := respond(conn, d.Secret, header); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if timeout > 0 {
+		conn.SetDeadline(time.Time{})
+	}
+	return conn, nil
+}
+
+// Dial connects to a remote mux listener with a given header byte over
+// plain TCP, without TLS or a shared secret.
+func Dial(network, address string, header byte) (net.Conn, error) {
+	return DialTimeout(network, address, header, 0)
+}
+
+// DialTimeout connects to a remote mux listener with a given header byte over
+// plain TCP, failing if the connection takes longer than timeout.
+func DialTimeout(network, address string, header byte, timeout time.Duration) (net.Conn, error) {
+	return (&Dialer{}).DialTimeout(network, address, header, timeout)
+}
+
+// challenge sends a random nonce to conn and verifies the peer answers with
+// its HMAC under secret.
+func challenge(conn net.Conn, secret []byte, header byte) error {
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	if _, err := conn.Write(nonce); err != nil {
+		return fmt.Errorf("write nonce: %s", err)
+	}
+
+	mac := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(conn, mac); err != nil {
+		return fmt.Errorf("read mac: %s", err)
+	}
+	if !hmac.Equal(mac, sign(secret, nonce, header)) {
+		return ErrAuthenticationFailed
+	}
+	return nil
+}
+
+// respond reads a nonce from conn and answers it with its HMAC under secret.
+func respond(conn net.Conn, secret []byte, header byte) error {
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(conn, nonce); err != nil {
+		return fmt.Errorf("read nonce: %s", err)
+	}
+	if _, err := conn.Write(sign(secret, nonce, header)); err != nil {
+		return fmt.Errorf("write mac: %s", err)
+	}
+	return nil
+}
+
+// sign returns the HMAC-SHA256 of the nonce and header byte under secret.
+func sign(secret, nonce []byte, header byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write(nonce)
+	h.Write([]byte{header})
+	return h.Sum(nil)
+}
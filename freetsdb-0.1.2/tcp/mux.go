@@ -2,6 +2,7 @@
 package tcp // import "github.com/freetsdb/freetsdb/tcp"
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -30,6 +31,15 @@ type Mux struct {
 	// The amount of time to wait for the first header byte.
 	Timeout time.Duration
 
+	// TLSConfig, if set, requires every connection to complete a TLS
+	// handshake before the header byte is read.
+	TLSConfig *tls.Config
+
+	// Secret, if set, requires connections to registered listeners to
+	// answer an HMAC challenge signed with the shared secret after the
+	// header byte. Connections passed to the default listener are exempt.
+	Secret []byte
+
 	// Out-of-band error logger
 	Logger *log.Logger
 }
@@ -121,6 +131,17 @@ func (mux *Mux) handleConn(conn net.Conn) {
 		return
 	}
 
+	// Complete the TLS handshake before anything else is read.
+	if mux.TLSConfig != nil {
+		tlsConn := tls.Server(conn, mux.TLSConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			mux.Logger.Printf("tcp.Mux: tls handshake from %s failed: %s", conn.RemoteAddr(), err)
+			return
+		}
+		conn = tlsConn
+	}
+
 	// Read first byte from connection to determine handler.
 	var typ [1]byte
 	if _, err := io.ReadFull(conn, typ[:]); err != nil {
@@ -129,6 +150,20 @@ func (mux *Mux) handleConn(conn net.Conn) {
 		return
 	}
 
+	// Retrieve handler based on first byte.
+	mux.mu.RLock()
+	handler := mux.m[typ[0]]
+	mux.mu.RUnlock()
+
+	// Authenticate connections to registered listeners.
+	if handler != nil && len(mux.Secret) > 0 {
+		if err := challenge(conn, mux.Secret, typ[0]); err != nil {
+			conn.Close()
+			mux.Logger.Printf("tcp.Mux: authentication from %s failed: %s", conn.RemoteAddr(), err)
+			return
+		}
+	}
+
 	// Reset read deadline and let the listener handle that.
 	if err := conn.SetReadDeadline(time.Time{}); err != nil {
 		conn.Close()
@@ -136,11 +171,6 @@ func (mux *Mux) handleConn(conn net.Conn) {
 		return
 	}
 
-	// Retrieve handler based on first byte.
-	mux.mu.RLock()
-	handler := mux.m[typ[0]]
-	mux.mu.RUnlock()
-
 	if handler == nil {
 		if mux.defaultListener == nil {
 			conn.Close()
@@ -293,17 +323,3 @@ func (ln *listener) Addr() net.Addr {
 
 	return ln.mux.ln.Addr()
 }
-
-// Dial connects to a remote mux listener with a given header byte.
-func Dial(network, address string, header byte) (net.Conn, error) {
-	conn, err := net.Dial(network, address)
-	if err != nil {
-		return nil, err
-	}
-
-	if _, err := conn.Write([]byte{header}); err != nil {
-		return nil, fmt.Errorf("write mux header: %s", err)
-	}
-
-	return conn, nil
-}
@@ -2,11 +2,20 @@ package tcp_test
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"math/big"
 	"net"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -210,3 +219,148 @@ func TestMux_Close(t *testing.T) {
 		t.Errorf("timeout while waiting for the mux to close")
 	}
 }
+
+// Ensure a connection is only handed to a listener once the dialer answers
+// the shared secret challenge.
+func TestMux_Secret(t *testing.T) {
+	mux, addr, closer := MustOpenMux(t, func(mux *tcp.Mux) {
+		mux.Secret = []byte("secret")
+	})
+	defer closer()
+	ln := mux.Listen(5)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("OK"))
+			conn.Close()
+		}
+	}()
+
+	d := &tcp.Dialer{Secret: []byte("secret"), Timeout: time.Second}
+	if resp := MustDialResponse(t, d, addr, 5); resp != "OK" {
+		t.Fatalf("unexpected response: %q", resp)
+	}
+
+	d = &tcp.Dialer{Secret: []byte("wrong"), Timeout: time.Second}
+	if resp := MustDialResponse(t, d, addr, 5); resp != "" {
+		t.Fatalf("unexpected response with wrong secret: %q", resp)
+	}
+}
+
+// Ensure the mux completes a mutual TLS handshake before reading the header.
+func TestMux_TLS(t *testing.T) {
+	certFile, keyFile, closer := MustWriteCertificate(t)
+	defer closer()
+
+	serverConfig, clientConfig, err := tcp.TLSConfigs(nil, certFile, keyFile, certFile, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux, addr, closeMux := MustOpenMux(t, func(mux *tcp.Mux) {
+		mux.TLSConfig = serverConfig
+	})
+	defer closeMux()
+	ln := mux.Listen(5)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("OK"))
+			conn.Close()
+		}
+	}()
+
+	d := &tcp.Dialer{TLSConfig: clientConfig, Timeout: time.Second}
+	if resp := MustDialResponse(t, d, addr, 5); resp != "OK" {
+		t.Fatalf("unexpected response: %q", resp)
+	}
+
+	// A plain connection must not reach the listener.
+	if resp := MustDialResponse(t, &tcp.Dialer{Timeout: time.Second}, addr, 5); resp != "" {
+		t.Fatalf("unexpected response without tls: %q", resp)
+	}
+}
+
+// MustOpenMux serves a new mux on a random port, applying fn before serving.
+// Returns the mux, its address and a function to close it.
+func MustOpenMux(t *testing.T, fn func(mux *tcp.Mux)) (*tcp.Mux, string, func()) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := tcp.NewMux()
+	mux.Timeout = time.Second
+	if !testing.Verbose() {
+		mux.Logger = log.New(ioutil.Discard, "", 0)
+	}
+	fn(mux)
+	go mux.Serve(ln)
+
+	return mux, ln.Addr().String(), func() { ln.Close() }
+}
+
+// MustDialResponse dials addr with d and returns everything read from the
+// connection. Returns an empty string if the dial or read fails.
+func MustDialResponse(t *testing.T, d *tcp.Dialer, addr string, header byte) string {
+	conn, err := d.Dial("tcp", addr, header)
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	b, _ := ioutil.ReadAll(conn)
+	return string(b)
+}
+
+// MustWriteCertificate writes a self-signed certificate for 127.0.0.1 and
+// its key to a temporary directory. Returns the file paths and a function to
+// remove them.
+func MustWriteCertificate(t *testing.T) (string, string, func()) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "freetsdb"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "tcp-tls-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile, func() { os.RemoveAll(dir) }
+}
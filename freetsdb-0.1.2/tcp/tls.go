@@ -0,0 +1,51 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// TLSConfigs returns the server and client TLS configurations for a mux
+// using the certificate and private key files. base supplies the cipher and
+// version settings and may be nil. If caFile is set, both ends verify their
+// peer's certificate against it, which gives mutual TLS.
+func TLSConfigs(base *tls.Config, certFile, keyFile, caFile string, insecureSkipVerify bool) (server, client *tls.Config, err error) {
+	if certFile == "" {
+		return nil, nil, errors.New("tls certificate must be specified")
+	}
+	if keyFile == "" {
+		keyFile = certFile
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load certificate: %s", err)
+	}
+
+	if base == nil {
+		base = new(tls.Config)
+	}
+	server, client = base.Clone(), base.Clone()
+	server.Certificates = []tls.Certificate{cert}
+	client.Certificates = []tls.Certificate{cert}
+	client.InsecureSkipVerify = insecureSkipVerify
+
+	if caFile != "" {
+		buf, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read ca certificate: %s", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(buf) {
+			return nil, nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+		server.ClientCAs = pool
+		server.ClientAuth = tls.RequireAndVerifyClientCert
+		client.RootCAs = pool
+	}
+
+	return server, client, nil
+}
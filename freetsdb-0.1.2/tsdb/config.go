@@ -3,6 +3,7 @@ package tsdb
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/freetsdb/freetsdb/monitor/diagnostics"
@@ -77,6 +78,21 @@ type Config struct {
 	Engine string `toml:"-"`
 	Index  string `toml:"index-version"`
 
+	// ColdDir is the directory shards of retention policies with a cold
+	// duration are moved to, typically on cheaper storage. Moving shards
+	// is disabled when empty.
+	ColdDir string `toml:"cold-dir"`
+
+	// ArchiveDir is the directory RESTORE SHARD reads shard archives
+	// written by "freetsd-ctl archive-shard" from.
+	ArchiveDir string `toml:"archive-dir"`
+
+	// TrashDir is the directory DROP DATABASE, DROP MEASUREMENT and DROP
+	// SHARD move dropped data to so it can be restored with UNDROP, once
+	// enabled for the cluster with SET TRASH ON. It defaults to a "trash"
+	// directory next to Dir.
+	TrashDir string `toml:"trash-dir"`
+
 	// General WAL configuration options
 	WALDir string `toml:"wal-dir"`
 
@@ -170,6 +186,10 @@ func (c *Config) Validate() error {
 		return errors.New("Data.Dir must be specified")
 	} else if c.WALDir == "" {
 		return errors.New("Data.WALDir must be specified")
+	} else if c.ColdDir != "" && filepath.Clean(c.ColdDir) == filepath.Clean(c.Dir) {
+		return errors.New("Data.ColdDir must differ from Data.Dir")
+	} else if c.TrashDir != "" && filepath.Clean(c.TrashDir) == filepath.Clean(c.Dir) {
+		return errors.New("Data.TrashDir must differ from Data.Dir")
 	}
 
 	if c.MaxConcurrentCompactions < 0 {
@@ -210,6 +230,9 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 	return diagnostics.RowFromMap(map[string]interface{}{
 		"dir":                                c.Dir,
 		"wal-dir":                            c.WALDir,
+		"cold-dir":                           c.ColdDir,
+		"archive-dir":                        c.ArchiveDir,
+		"trash-dir":                          c.TrashDir,
 		"wal-fsync-delay":                    c.WALFsyncDelay,
 		"cache-max-memory-size":              c.CacheMaxMemorySize,
 		"cache-snapshot-memory-size":         c.CacheSnapshotMemorySize,
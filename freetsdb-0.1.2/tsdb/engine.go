@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"runtime"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/freetsdb/freetsdb/models"
@@ -35,7 +36,11 @@ type Engine interface {
 	Close() error
 	SetEnabled(enabled bool)
 	SetCompactionsEnabled(enabled bool)
+	SetCompactionsPaused(paused bool)
+	SetDuplicatePolicy(policy string)
 	ScheduleFullCompaction() error
+	ScheduleOptimizeCompaction() error
+	Compactions() []CompactionInfo
 
 	WithLogger(*zap.Logger)
 
@@ -44,6 +49,8 @@ type Engine interface {
 	CreateSnapshot() (string, error)
 	Backup(w io.Writer, basePath string, since time.Time) error
 	Export(w io.Writer, basePath string, start time.Time, end time.Time) error
+	ExportMeasurement(w io.Writer, basePath string, name []byte) error
+	BackupIncremental(w io.Writer, basePath string, from *BackupPosition) error
 	Restore(r io.Reader, basePath string) error
 	Import(r io.Reader, basePath string) error
 	Digest() (io.ReadCloser, int64, error)
@@ -69,6 +76,7 @@ type Engine interface {
 	MeasurementFields(measurement []byte) *MeasurementFields
 	ForEachMeasurementName(fn func(name []byte) error) error
 	DeleteMeasurement(name []byte) error
+	ConvertFieldType(measurement, field []byte, typ influxql.DataType, writes sync.Locker) error
 
 	HasTagKey(name, key []byte) (bool, error)
 	MeasurementTagKeysByExpr(name []byte, expr influxql.Expr) (map[string]struct{}, error)
@@ -84,6 +92,44 @@ type Engine interface {
 	io.WriterTo
 }
 
+const (
+	// BackupPositionFile is the name of the archive entry holding the
+	// BackupPosition of an incremental backup.
+	BackupPositionFile = "position.json"
+
+	// BackupWALDir is the archive dir holding the WAL segment tails of an
+	// incremental backup.
+	BackupWALDir = "wal"
+)
+
+// BackupPosition records what an incremental shard backup captured. Files
+// maps the TSM and tombstone files of the shard to their sizes and WAL maps
+// each WAL segment to the offset it was written up to. Tails maps the WAL
+// segments with a tail in the archive to the offset the tail starts at.
+type BackupPosition struct {
+	Files map[string]int64 `json:"files"`
+	WAL   map[string]int64 `json:"wal"`
+	Tails map[string]int64 `json:"tails,omitempty"`
+}
+
+// CompactionInfo describes a compaction running on a shard. Level is 1 to 3
+// for level compactions and 4 for full and optimize compactions. BytesIn is
+// the size of the compacted files and BytesOut the size of the data read
+// from them so far. Progress is the percentage of their keys read so far.
+type CompactionInfo struct {
+	ShardID         uint64
+	Database        string
+	RetentionPolicy string
+
+	Level    int
+	Strategy string
+	Files    int
+	BytesIn  int64
+	BytesOut int64
+	Progress float64
+	Started  time.Time
+}
+
 // SeriesIDSets provides access to the total set of series IDs
 type SeriesIDSets interface {
 	ForEach(f func(ids *SeriesIDSet)) error
@@ -187,6 +233,18 @@ type EngineOptions struct {
 	// nil will allow all combinations to pass.
 	ShardFilter func(database, rp string, id uint64) bool
 
+	// DuplicatePolicy returns how points with the same series and timestamp are merged
+	// in the shards of a retention policy: last, first, max or keep.
+	// nil or an empty policy keeps the last point written.  It is called when a
+	// shard is opened and by Store.UpdateDuplicatePolicies.
+	DuplicatePolicy func(database, rp string) string
+
+	// Codecs returns the codecs compressing the values of the string and float
+	// blocks of the shards of a retention policy: snappy or zstd, and gorilla or
+	// zstd.  nil keeps blocks as they are written.  It is called when a shard
+	// compacts its files, so an update applies from the next compaction.
+	Codecs func(database, rp string) (str, float string)
+
 	Config         Config
 	SeriesIDSets   SeriesIDSets
 	FieldValidator FieldValidator
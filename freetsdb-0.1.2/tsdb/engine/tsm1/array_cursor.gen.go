@@ -41,7 +41,7 @@ func newFloatArrayAscendingCursor() *floatArrayAscendingCursor {
 
 func (c *floatArrayAscendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
 	c.end = end
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -82,10 +82,7 @@ func (c *floatArrayAscendingCursor) Next() *tsdb.FloatArray {
 		ckey := cvals[c.cache.pos].UnixNano()
 		tkey := tvals.Timestamps[c.tsm.pos]
 		if ckey == tkey {
-			c.res.Timestamps[pos] = ckey
-			c.res.Values[pos] = cvals[c.cache.pos].(FloatValue).value
-			c.cache.pos++
-			c.tsm.pos++
+			c.nextDuplicate(pos)
 		} else if ckey < tkey {
 			c.res.Timestamps[pos] = ckey
 			c.res.Values[pos] = cvals[c.cache.pos].(FloatValue).value
@@ -154,6 +151,23 @@ func (c *floatArrayAscendingCursor) nextTSM() *tsdb.FloatArray {
 	return c.tsm.values
 }
 
+// nextDuplicate sets the result at pos to the value of a key held by both the
+// cache and the TSM files, merged with the duplicate policy.
+func (c *floatArrayAscendingCursor) nextDuplicate(pos int) {
+	t := c.tsm.values.Timestamps[c.tsm.pos]
+	cv := c.cache.values[c.cache.pos].(FloatValue)
+	tv := FloatValue{unixnano: t, value: c.tsm.values.Values[c.tsm.pos]}
+
+	c.res.Timestamps[pos] = t
+	if cacheValueWins(c.tsm.keyCursor.duplicates, cv, tv) {
+		c.res.Values[pos] = cv.value
+	} else {
+		c.res.Values[pos] = tv.value
+	}
+	c.cache.pos++
+	c.tsm.pos++
+}
+
 type floatArrayDescendingCursor struct {
 	cache struct {
 		values Values
@@ -181,7 +195,7 @@ func newFloatArrayDescendingCursor() *floatArrayDescendingCursor {
 
 func (c *floatArrayDescendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
 	c.end = end
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	if len(c.cache.values) > 0 {
 		c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 			return c.cache.values[i].UnixNano() >= seek
@@ -238,10 +252,7 @@ func (c *floatArrayDescendingCursor) Next() *tsdb.FloatArray {
 		ckey := cvals[c.cache.pos].UnixNano()
 		tkey := tvals.Timestamps[c.tsm.pos]
 		if ckey == tkey {
-			c.res.Timestamps[pos] = ckey
-			c.res.Values[pos] = cvals[c.cache.pos].(FloatValue).value
-			c.cache.pos--
-			c.tsm.pos--
+			c.nextDuplicate(pos)
 		} else if ckey > tkey {
 			c.res.Timestamps[pos] = ckey
 			c.res.Values[pos] = cvals[c.cache.pos].(FloatValue).value
@@ -305,6 +316,23 @@ func (c *floatArrayDescendingCursor) nextTSM() *tsdb.FloatArray {
 	return c.tsm.values
 }
 
+// nextDuplicate sets the result at pos to the value of a key held by both the
+// cache and the TSM files, merged with the duplicate policy.
+func (c *floatArrayDescendingCursor) nextDuplicate(pos int) {
+	t := c.tsm.values.Timestamps[c.tsm.pos]
+	cv := c.cache.values[c.cache.pos].(FloatValue)
+	tv := FloatValue{unixnano: t, value: c.tsm.values.Values[c.tsm.pos]}
+
+	c.res.Timestamps[pos] = t
+	if cacheValueWins(c.tsm.keyCursor.duplicates, cv, tv) {
+		c.res.Values[pos] = cv.value
+	} else {
+		c.res.Values[pos] = tv.value
+	}
+	c.cache.pos--
+	c.tsm.pos--
+}
+
 type integerArrayAscendingCursor struct {
 	cache struct {
 		values Values
@@ -332,7 +360,7 @@ func newIntegerArrayAscendingCursor() *integerArrayAscendingCursor {
 
 func (c *integerArrayAscendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
 	c.end = end
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -373,10 +401,7 @@ func (c *integerArrayAscendingCursor) Next() *tsdb.IntegerArray {
 		ckey := cvals[c.cache.pos].UnixNano()
 		tkey := tvals.Timestamps[c.tsm.pos]
 		if ckey == tkey {
-			c.res.Timestamps[pos] = ckey
-			c.res.Values[pos] = cvals[c.cache.pos].(IntegerValue).value
-			c.cache.pos++
-			c.tsm.pos++
+			c.nextDuplicate(pos)
 		} else if ckey < tkey {
 			c.res.Timestamps[pos] = ckey
 			c.res.Values[pos] = cvals[c.cache.pos].(IntegerValue).value
@@ -445,6 +470,23 @@ func (c *integerArrayAscendingCursor) nextTSM() *tsdb.IntegerArray {
 	return c.tsm.values
 }
 
+// nextDuplicate sets the result at pos to the value of a key held by both the
+// cache and the TSM files, merged with the duplicate policy.
+func (c *integerArrayAscendingCursor) nextDuplicate(pos int) {
+	t := c.tsm.values.Timestamps[c.tsm.pos]
+	cv := c.cache.values[c.cache.pos].(IntegerValue)
+	tv := IntegerValue{unixnano: t, value: c.tsm.values.Values[c.tsm.pos]}
+
+	c.res.Timestamps[pos] = t
+	if cacheValueWins(c.tsm.keyCursor.duplicates, cv, tv) {
+		c.res.Values[pos] = cv.value
+	} else {
+		c.res.Values[pos] = tv.value
+	}
+	c.cache.pos++
+	c.tsm.pos++
+}
+
 type integerArrayDescendingCursor struct {
 	cache struct {
 		values Values
@@ -472,7 +514,7 @@ func newIntegerArrayDescendingCursor() *integerArrayDescendingCursor {
 
 func (c *integerArrayDescendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
 	c.end = end
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	if len(c.cache.values) > 0 {
 		c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 			return c.cache.values[i].UnixNano() >= seek
@@ -529,10 +571,7 @@ func (c *integerArrayDescendingCursor) Next() *tsdb.IntegerArray {
 		ckey := cvals[c.cache.pos].UnixNano()
 		tkey := tvals.Timestamps[c.tsm.pos]
 		if ckey == tkey {
-			c.res.Timestamps[pos] = ckey
-			c.res.Values[pos] = cvals[c.cache.pos].(IntegerValue).value
-			c.cache.pos--
-			c.tsm.pos--
+			c.nextDuplicate(pos)
 		} else if ckey > tkey {
 			c.res.Timestamps[pos] = ckey
 			c.res.Values[pos] = cvals[c.cache.pos].(IntegerValue).value
@@ -596,6 +635,23 @@ func (c *integerArrayDescendingCursor) nextTSM() *tsdb.IntegerArray {
 	return c.tsm.values
 }
 
+// nextDuplicate sets the result at pos to the value of a key held by both the
+// cache and the TSM files, merged with the duplicate policy.
+func (c *integerArrayDescendingCursor) nextDuplicate(pos int) {
+	t := c.tsm.values.Timestamps[c.tsm.pos]
+	cv := c.cache.values[c.cache.pos].(IntegerValue)
+	tv := IntegerValue{unixnano: t, value: c.tsm.values.Values[c.tsm.pos]}
+
+	c.res.Timestamps[pos] = t
+	if cacheValueWins(c.tsm.keyCursor.duplicates, cv, tv) {
+		c.res.Values[pos] = cv.value
+	} else {
+		c.res.Values[pos] = tv.value
+	}
+	c.cache.pos--
+	c.tsm.pos--
+}
+
 type unsignedArrayAscendingCursor struct {
 	cache struct {
 		values Values
@@ -623,7 +679,7 @@ func newUnsignedArrayAscendingCursor() *unsignedArrayAscendingCursor {
 
 func (c *unsignedArrayAscendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
 	c.end = end
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -664,10 +720,7 @@ func (c *unsignedArrayAscendingCursor) Next() *tsdb.UnsignedArray {
 		ckey := cvals[c.cache.pos].UnixNano()
 		tkey := tvals.Timestamps[c.tsm.pos]
 		if ckey == tkey {
-			c.res.Timestamps[pos] = ckey
-			c.res.Values[pos] = cvals[c.cache.pos].(UnsignedValue).value
-			c.cache.pos++
-			c.tsm.pos++
+			c.nextDuplicate(pos)
 		} else if ckey < tkey {
 			c.res.Timestamps[pos] = ckey
 			c.res.Values[pos] = cvals[c.cache.pos].(UnsignedValue).value
@@ -736,6 +789,23 @@ func (c *unsignedArrayAscendingCursor) nextTSM() *tsdb.UnsignedArray {
 	return c.tsm.values
 }
 
+// nextDuplicate sets the result at pos to the value of a key held by both the
+// cache and the TSM files, merged with the duplicate policy.
+func (c *unsignedArrayAscendingCursor) nextDuplicate(pos int) {
+	t := c.tsm.values.Timestamps[c.tsm.pos]
+	cv := c.cache.values[c.cache.pos].(UnsignedValue)
+	tv := UnsignedValue{unixnano: t, value: c.tsm.values.Values[c.tsm.pos]}
+
+	c.res.Timestamps[pos] = t
+	if cacheValueWins(c.tsm.keyCursor.duplicates, cv, tv) {
+		c.res.Values[pos] = cv.value
+	} else {
+		c.res.Values[pos] = tv.value
+	}
+	c.cache.pos++
+	c.tsm.pos++
+}
+
 type unsignedArrayDescendingCursor struct {
 	cache struct {
 		values Values
@@ -763,7 +833,7 @@ func newUnsignedArrayDescendingCursor() *unsignedArrayDescendingCursor {
 
 func (c *unsignedArrayDescendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
 	c.end = end
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	if len(c.cache.values) > 0 {
 		c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 			return c.cache.values[i].UnixNano() >= seek
@@ -820,10 +890,7 @@ func (c *unsignedArrayDescendingCursor) Next() *tsdb.UnsignedArray {
 		ckey := cvals[c.cache.pos].UnixNano()
 		tkey := tvals.Timestamps[c.tsm.pos]
 		if ckey == tkey {
-			c.res.Timestamps[pos] = ckey
-			c.res.Values[pos] = cvals[c.cache.pos].(UnsignedValue).value
-			c.cache.pos--
-			c.tsm.pos--
+			c.nextDuplicate(pos)
 		} else if ckey > tkey {
 			c.res.Timestamps[pos] = ckey
 			c.res.Values[pos] = cvals[c.cache.pos].(UnsignedValue).value
@@ -887,6 +954,23 @@ func (c *unsignedArrayDescendingCursor) nextTSM() *tsdb.UnsignedArray {
 	return c.tsm.values
 }
 
+// nextDuplicate sets the result at pos to the value of a key held by both the
+// cache and the TSM files, merged with the duplicate policy.
+func (c *unsignedArrayDescendingCursor) nextDuplicate(pos int) {
+	t := c.tsm.values.Timestamps[c.tsm.pos]
+	cv := c.cache.values[c.cache.pos].(UnsignedValue)
+	tv := UnsignedValue{unixnano: t, value: c.tsm.values.Values[c.tsm.pos]}
+
+	c.res.Timestamps[pos] = t
+	if cacheValueWins(c.tsm.keyCursor.duplicates, cv, tv) {
+		c.res.Values[pos] = cv.value
+	} else {
+		c.res.Values[pos] = tv.value
+	}
+	c.cache.pos--
+	c.tsm.pos--
+}
+
 type stringArrayAscendingCursor struct {
 	cache struct {
 		values Values
@@ -914,7 +998,7 @@ func newStringArrayAscendingCursor() *stringArrayAscendingCursor {
 
 func (c *stringArrayAscendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
 	c.end = end
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -955,10 +1039,7 @@ func (c *stringArrayAscendingCursor) Next() *tsdb.StringArray {
 		ckey := cvals[c.cache.pos].UnixNano()
 		tkey := tvals.Timestamps[c.tsm.pos]
 		if ckey == tkey {
-			c.res.Timestamps[pos] = ckey
-			c.res.Values[pos] = cvals[c.cache.pos].(StringValue).value
-			c.cache.pos++
-			c.tsm.pos++
+			c.nextDuplicate(pos)
 		} else if ckey < tkey {
 			c.res.Timestamps[pos] = ckey
 			c.res.Values[pos] = cvals[c.cache.pos].(StringValue).value
@@ -1027,6 +1108,23 @@ func (c *stringArrayAscendingCursor) nextTSM() *tsdb.StringArray {
 	return c.tsm.values
 }
 
+// nextDuplicate sets the result at pos to the value of a key held by both the
+// cache and the TSM files, merged with the duplicate policy.
+func (c *stringArrayAscendingCursor) nextDuplicate(pos int) {
+	t := c.tsm.values.Timestamps[c.tsm.pos]
+	cv := c.cache.values[c.cache.pos].(StringValue)
+	tv := StringValue{unixnano: t, value: c.tsm.values.Values[c.tsm.pos]}
+
+	c.res.Timestamps[pos] = t
+	if cacheValueWins(c.tsm.keyCursor.duplicates, cv, tv) {
+		c.res.Values[pos] = cv.value
+	} else {
+		c.res.Values[pos] = tv.value
+	}
+	c.cache.pos++
+	c.tsm.pos++
+}
+
 type stringArrayDescendingCursor struct {
 	cache struct {
 		values Values
@@ -1054,7 +1152,7 @@ func newStringArrayDescendingCursor() *stringArrayDescendingCursor {
 
 func (c *stringArrayDescendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
 	c.end = end
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	if len(c.cache.values) > 0 {
 		c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 			return c.cache.values[i].UnixNano() >= seek
@@ -1111,10 +1209,7 @@ func (c *stringArrayDescendingCursor) Next() *tsdb.StringArray {
 		ckey := cvals[c.cache.pos].UnixNano()
 		tkey := tvals.Timestamps[c.tsm.pos]
 		if ckey == tkey {
-			c.res.Timestamps[pos] = ckey
-			c.res.Values[pos] = cvals[c.cache.pos].(StringValue).value
-			c.cache.pos--
-			c.tsm.pos--
+			c.nextDuplicate(pos)
 		} else if ckey > tkey {
 			c.res.Timestamps[pos] = ckey
 			c.res.Values[pos] = cvals[c.cache.pos].(StringValue).value
@@ -1178,6 +1273,23 @@ func (c *stringArrayDescendingCursor) nextTSM() *tsdb.StringArray {
 	return c.tsm.values
 }
 
+// nextDuplicate sets the result at pos to the value of a key held by both the
+// cache and the TSM files, merged with the duplicate policy.
+func (c *stringArrayDescendingCursor) nextDuplicate(pos int) {
+	t := c.tsm.values.Timestamps[c.tsm.pos]
+	cv := c.cache.values[c.cache.pos].(StringValue)
+	tv := StringValue{unixnano: t, value: c.tsm.values.Values[c.tsm.pos]}
+
+	c.res.Timestamps[pos] = t
+	if cacheValueWins(c.tsm.keyCursor.duplicates, cv, tv) {
+		c.res.Values[pos] = cv.value
+	} else {
+		c.res.Values[pos] = tv.value
+	}
+	c.cache.pos--
+	c.tsm.pos--
+}
+
 type booleanArrayAscendingCursor struct {
 	cache struct {
 		values Values
@@ -1205,7 +1317,7 @@ func newBooleanArrayAscendingCursor() *booleanArrayAscendingCursor {
 
 func (c *booleanArrayAscendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
 	c.end = end
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -1246,10 +1358,7 @@ func (c *booleanArrayAscendingCursor) Next() *tsdb.BooleanArray {
 		ckey := cvals[c.cache.pos].UnixNano()
 		tkey := tvals.Timestamps[c.tsm.pos]
 		if ckey == tkey {
-			c.res.Timestamps[pos] = ckey
-			c.res.Values[pos] = cvals[c.cache.pos].(BooleanValue).value
-			c.cache.pos++
-			c.tsm.pos++
+			c.nextDuplicate(pos)
 		} else if ckey < tkey {
 			c.res.Timestamps[pos] = ckey
 			c.res.Values[pos] = cvals[c.cache.pos].(BooleanValue).value
@@ -1318,6 +1427,23 @@ func (c *booleanArrayAscendingCursor) nextTSM() *tsdb.BooleanArray {
 	return c.tsm.values
 }
 
+// nextDuplicate sets the result at pos to the value of a key held by both the
+// cache and the TSM files, merged with the duplicate policy.
+func (c *booleanArrayAscendingCursor) nextDuplicate(pos int) {
+	t := c.tsm.values.Timestamps[c.tsm.pos]
+	cv := c.cache.values[c.cache.pos].(BooleanValue)
+	tv := BooleanValue{unixnano: t, value: c.tsm.values.Values[c.tsm.pos]}
+
+	c.res.Timestamps[pos] = t
+	if cacheValueWins(c.tsm.keyCursor.duplicates, cv, tv) {
+		c.res.Values[pos] = cv.value
+	} else {
+		c.res.Values[pos] = tv.value
+	}
+	c.cache.pos++
+	c.tsm.pos++
+}
+
 type booleanArrayDescendingCursor struct {
 	cache struct {
 		values Values
@@ -1345,7 +1471,7 @@ func newBooleanArrayDescendingCursor() *booleanArrayDescendingCursor {
 
 func (c *booleanArrayDescendingCursor) reset(seek, end int64, cacheValues Values, tsmKeyCursor *KeyCursor) {
 	c.end = end
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	if len(c.cache.values) > 0 {
 		c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 			return c.cache.values[i].UnixNano() >= seek
@@ -1402,10 +1528,7 @@ func (c *booleanArrayDescendingCursor) Next() *tsdb.BooleanArray {
 		ckey := cvals[c.cache.pos].UnixNano()
 		tkey := tvals.Timestamps[c.tsm.pos]
 		if ckey == tkey {
-			c.res.Timestamps[pos] = ckey
-			c.res.Values[pos] = cvals[c.cache.pos].(BooleanValue).value
-			c.cache.pos--
-			c.tsm.pos--
+			c.nextDuplicate(pos)
 		} else if ckey > tkey {
 			c.res.Timestamps[pos] = ckey
 			c.res.Values[pos] = cvals[c.cache.pos].(BooleanValue).value
@@ -1468,3 +1591,20 @@ func (c *booleanArrayDescendingCursor) nextTSM() *tsdb.BooleanArray {
 	c.tsm.pos = len(c.tsm.values.Timestamps) - 1
 	return c.tsm.values
 }
+
+// nextDuplicate sets the result at pos to the value of a key held by both the
+// cache and the TSM files, merged with the duplicate policy.
+func (c *booleanArrayDescendingCursor) nextDuplicate(pos int) {
+	t := c.tsm.values.Timestamps[c.tsm.pos]
+	cv := c.cache.values[c.cache.pos].(BooleanValue)
+	tv := BooleanValue{unixnano: t, value: c.tsm.values.Values[c.tsm.pos]}
+
+	c.res.Timestamps[pos] = t
+	if cacheValueWins(c.tsm.keyCursor.duplicates, cv, tv) {
+		c.res.Values[pos] = cv.value
+	} else {
+		c.res.Values[pos] = tv.value
+	}
+	c.cache.pos--
+	c.tsm.pos--
+}
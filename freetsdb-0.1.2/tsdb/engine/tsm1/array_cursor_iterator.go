@@ -37,6 +37,10 @@ func (q *arrayCursorIterator) Stats() tsdb.CursorStats {
 }
 
 func (q *arrayCursorIterator) Next(ctx context.Context, r *tsdb.CursorRequest) (tsdb.Cursor, error) {
+	// The TSM files must be opened with the field type they hold.
+	q.e.fieldTypesMu.RLock()
+	defer q.e.fieldTypesMu.RUnlock()
+
 	// Look up fields for measurement.
 	mf := q.e.fieldset.Fields(r.Name)
 	if mf == nil {
@@ -276,6 +276,9 @@ func init() {
 }
 
 func FloatArrayDecodeAll(b []byte, buf []float64) ([]float64, error) {
+	if len(b) > 0 && b[0]>>4 == floatCompressedZstd {
+		return floatArrayDecodeZstd(b, buf)
+	}
 	if len(b) < 9 {
 		return []float64{}, nil
 	}
@@ -286,7 +289,7 @@ func FloatArrayDecodeAll(b []byte, buf []float64) ([]float64, error) {
 		meaningfulN uint8  = 64 // meaningful bit count
 	)
 
-	// first byte is the compression type; always Gorilla
+	// first byte is the compression type; Gorilla as zstd is handled above
 	b = b[1:]
 
 	val = binary.BigEndian.Uint64(b)
@@ -0,0 +1,165 @@
+package tsm1
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/freetsdb/freetsdb/tsdb"
+)
+
+const (
+	// Size in bytes of the block statistics following an index entry
+	indexStatsSize = 28
+
+	// indexStatsFlag is set in the block type stored in the index for keys
+	// whose index entries are followed by the statistics of their blocks.
+	indexStatsFlag byte = 0x80
+)
+
+// BlockStats holds summary statistics of the values in a block.  They are
+// stored in the index entries of float, integer and unsigned blocks so
+// count(), sum(), min() and max() can be answered without decoding blocks.
+//
+// Min, Max and Sum hold the bits of float64, int64 or uint64 values depending
+// on the block type.  Integer sums wrap around like the sum() function does.
+// A Count of zero means the block has no statistics.
+type BlockStats struct {
+	Count         uint32
+	Min, Max, Sum uint64
+}
+
+// add adds the bits of a value to the statistics.
+func (s *BlockStats) add(ops *statsOps, v uint64) {
+	if s.Count == 0 {
+		s.Min, s.Max, s.Sum = v, v, v
+	} else {
+		if ops.less(v, s.Min) {
+			s.Min = v
+		}
+		if ops.less(s.Max, v) {
+			s.Max = v
+		}
+		s.Sum = ops.add(s.Sum, v)
+	}
+	s.Count++
+}
+
+// appendTo writes the binary-encoded statistics to the first indexStatsSize
+// bytes of b.
+func (s *BlockStats) appendTo(b []byte) {
+	binary.BigEndian.PutUint32(b[:4], s.Count)
+	binary.BigEndian.PutUint64(b[4:12], s.Min)
+	binary.BigEndian.PutUint64(b[12:20], s.Max)
+	binary.BigEndian.PutUint64(b[20:28], s.Sum)
+}
+
+// unmarshalBinary decodes the statistics from the first indexStatsSize bytes
+// of b.
+func (s *BlockStats) unmarshalBinary(b []byte) {
+	s.Count = binary.BigEndian.Uint32(b[:4])
+	s.Min = binary.BigEndian.Uint64(b[4:12])
+	s.Max = binary.BigEndian.Uint64(b[12:20])
+	s.Sum = binary.BigEndian.Uint64(b[20:28])
+}
+
+// statsOps compares and adds the bits of the values of a numeric block type.
+type statsOps struct {
+	less func(a, b uint64) bool
+	add  func(a, b uint64) uint64
+}
+
+var (
+	floatStatsOps = statsOps{
+		less: func(a, b uint64) bool { return math.Float64frombits(a) < math.Float64frombits(b) },
+		add: func(a, b uint64) uint64 {
+			return math.Float64bits(math.Float64frombits(a) + math.Float64frombits(b))
+		},
+	}
+
+	integerStatsOps = statsOps{
+		less: func(a, b uint64) bool { return int64(a) < int64(b) },
+		add:  func(a, b uint64) uint64 { return uint64(int64(a) + int64(b)) },
+	}
+
+	unsignedStatsOps = statsOps{
+		less: func(a, b uint64) bool { return a < b },
+		add:  func(a, b uint64) uint64 { return a + b },
+	}
+)
+
+// valueBits returns the bits of a numeric value as stored in BlockStats.
+func valueBits(v Value) (uint64, bool) {
+	switch v := v.(type) {
+	case FloatValue:
+		return math.Float64bits(v.value), true
+	case IntegerValue:
+		return uint64(v.value), true
+	case UnsignedValue:
+		return v.value, true
+	}
+	return 0, false
+}
+
+// valuesStats returns the statistics of values, or no statistics if the
+// values are not numeric.
+func valuesStats(values Values) BlockStats {
+	var s BlockStats
+	if len(values) == 0 {
+		return s
+	}
+
+	var ops *statsOps
+	switch values[0].(type) {
+	case FloatValue:
+		ops = &floatStatsOps
+	case IntegerValue:
+		ops = &integerStatsOps
+	case UnsignedValue:
+		ops = &unsignedStatsOps
+	default:
+		return s
+	}
+
+	for _, v := range values {
+		bits, _ := valueBits(v)
+		s.add(ops, bits)
+	}
+	return s
+}
+
+// blockStats decodes block and returns the statistics of its values, or no
+// statistics if the values are not numeric.
+func blockStats(block []byte) (BlockStats, error) {
+	var s BlockStats
+	if len(block) == 0 {
+		return s, nil
+	}
+
+	switch block[0] {
+	case BlockFloat64:
+		var a tsdb.FloatArray
+		if err := DecodeFloatArrayBlock(block, &a); err != nil {
+			return s, err
+		}
+		for _, v := range a.Values {
+			s.add(&floatStatsOps, math.Float64bits(v))
+		}
+	case BlockInteger:
+		var a tsdb.IntegerArray
+		if err := DecodeIntegerArrayBlock(block, &a); err != nil {
+			return s, err
+		}
+		for _, v := range a.Values {
+			s.add(&integerStatsOps, uint64(v))
+		}
+	case BlockUnsigned:
+		var a tsdb.UnsignedArray
+		if err := DecodeUnsignedArrayBlock(block, &a); err != nil {
+			return s, err
+		}
+		for _, v := range a.Values {
+			s.add(&unsignedStatsOps, v)
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,454 @@
+package tsm1
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/freetsdb/freetsdb/models"
+	"github.com/freetsdb/freetsdb/query"
+	"github.com/freetsdb/freetsdb/services/influxql"
+	"github.com/freetsdb/freetsdb/tsdb"
+)
+
+// blockStatsType returns the type of the field call is applied to if the call
+// can be answered from the statistics of blocks, or influxql.Unknown if it
+// can't.  Only count(), sum(), min() and max() of float, integer and unsigned
+// fields that don't need to be cast can.
+func (e *Engine) blockStatsType(measurement string, call *influxql.Call, opt query.IteratorOptions) influxql.DataType {
+	switch call.Name {
+	case "count", "sum", "min", "max":
+	default:
+		return influxql.Unknown
+	}
+
+	// Selectors with auxiliary fields need the values of the selected points.
+	ref, ok := call.Args[0].(*influxql.VarRef)
+	if !ok || len(opt.Aux) > 0 {
+		return influxql.Unknown
+	}
+
+	mf := e.fieldset.FieldsByString(measurement)
+	if mf == nil {
+		return influxql.Unknown
+	}
+	f := mf.Field(ref.Val)
+	if f == nil {
+		return influxql.Unknown
+	}
+
+	switch f.Type {
+	case influxql.Float, influxql.Integer, influxql.Unsigned:
+	default:
+		return influxql.Unknown
+	}
+	if ref.Type != influxql.Unknown && ref.Type != influxql.AnyField && ref.Type != f.Type {
+		return influxql.Unknown
+	}
+	return f.Type
+}
+
+// createBlockStatsIterators creates an iterator for each series of a tag set
+// that answers call from the statistics of the blocks of the series.  Points
+// in blocks that can't be summarized and in the cache are read as usual.
+func (e *Engine) createBlockStatsIterators(ctx context.Context, call *influxql.Call, measurement string, t *query.TagSet, typ influxql.DataType, opt query.IteratorOptions) ([]query.Iterator, error) {
+	ref := call.Args[0].(*influxql.VarRef)
+
+	name := measurement
+	if opt.StripName {
+		name = ""
+	}
+	dimensions := opt.GetDimensions()
+
+	itrs := make([]query.Iterator, 0, len(t.SeriesKeys))
+	for _, seriesKey := range t.SeriesKeys {
+		_, tfs := models.ParseKey([]byte(seriesKey))
+		tags := query.NewTags(tfs.Map())
+		tags = tags.Subset(dimensions)
+
+		key := SeriesFieldKeyBytes(seriesKey, ref.Val)
+		itr := &blockStatsIterator{
+			name:        name,
+			tags:        tags,
+			call:        call.Name,
+			typ:         typ,
+			opt:         opt,
+			cacheValues: e.Cache.Values(key),
+			keyCursor:   e.KeyCursor(ctx, key, opt.SeekTime(), opt.Ascending),
+		}
+		itrs = append(itrs, itr.typed())
+
+		// Enforce series limit at creation time.
+		if opt.MaxSeriesN > 0 && len(itrs) > opt.MaxSeriesN {
+			query.Iterators(itrs).Close()
+			return nil, fmt.Errorf("max-select-series limit exceeded: (%d/%d)", len(itrs), opt.MaxSeriesN)
+		}
+	}
+	return itrs, nil
+}
+
+// hasFilters returns true if any of the series of a tag set has a filter.
+func hasFilters(t *query.TagSet) bool {
+	for _, f := range t.Filters {
+		if f != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// blockStatsPoint is a point emitted by a blockStatsIterator.  The value holds
+// the bits of the value as stored in BlockStats.
+type blockStatsPoint struct {
+	time       int64
+	value      uint64
+	aggregated uint32
+}
+
+// blockStatsWindow accumulates the points and summarized blocks of a window.
+type blockStatsWindow struct {
+	start int64
+	count int64
+	sum   uint64
+
+	// The selected value and time of the points read, and the selected value
+	// of the summarized blocks with the block it's in.
+	value, blockValue uint64
+	time              int64
+	hasValue          bool
+	block             *location
+}
+
+// blockStatsIterator computes count(), sum(), min() or max() for a series by
+// window, summarizing the blocks that are entirely in a window and reading
+// the points of the other blocks and of the cache.  Windows are aggregated one
+// at a time as the iterator is read.
+type blockStatsIterator struct {
+	name string
+	tags query.Tags
+	call string
+	typ  influxql.DataType
+	opt  query.IteratorOptions
+
+	cacheValues Values
+	keyCursor   *KeyCursor
+
+	ops    *statsOps
+	better func(a, b uint64) bool
+
+	// The summarized blocks and the next point of the cursor not yet
+	// aggregated, in the order of the iterator.
+	blocks     []*location
+	cur        func() (int64, uint64)
+	pointTime  int64
+	pointValue uint64
+	point      blockStatsPoint
+
+	statsLock sync.Mutex
+	stats     query.IteratorStats
+}
+
+// typed returns itr as an iterator of the type of points it emits.
+func (itr *blockStatsIterator) typed() query.Iterator {
+	switch itr.typ {
+	case influxql.Float:
+		itr.ops = &floatStatsOps
+	case influxql.Integer:
+		itr.ops = &integerStatsOps
+	case influxql.Unsigned:
+		itr.ops = &unsignedStatsOps
+	}
+
+	itr.better = itr.ops.less
+	if itr.call == "max" {
+		itr.better = func(a, b uint64) bool { return itr.ops.less(b, a) }
+	}
+
+	if itr.call == "count" {
+		return &integerBlockStatsIterator{blockStatsIterator: itr}
+	}
+	switch itr.typ {
+	case influxql.Integer:
+		return &integerBlockStatsIterator{blockStatsIterator: itr}
+	case influxql.Unsigned:
+		return &unsignedBlockStatsIterator{blockStatsIterator: itr}
+	default:
+		return &floatBlockStatsIterator{blockStatsIterator: itr}
+	}
+}
+
+// equal returns true if neither a nor b is better than the other.
+func (itr *blockStatsIterator) equal(a, b uint64) bool {
+	return !itr.better(a, b) && !itr.better(b, a)
+}
+
+// addPoint adds a point read from the cursor to w.
+func (itr *blockStatsIterator) addPoint(w *blockStatsWindow, t int64, v uint64) {
+	if w.count == 0 && w.block == nil {
+		w.sum = v
+	} else {
+		w.sum = itr.ops.add(w.sum, v)
+	}
+	w.count++
+
+	if !w.hasValue || itr.better(v, w.value) || (itr.equal(v, w.value) && t < w.time) {
+		w.value, w.time, w.hasValue = v, t, true
+	}
+}
+
+// addBlock adds a summarized block to w.  As the time of the selected value
+// of a block isn't known, ties between blocks go to the earliest block, which
+// has the earliest values as summarized blocks don't overlap.
+func (itr *blockStatsIterator) addBlock(w *blockStatsWindow, l *location) {
+	if w.count == 0 && w.block == nil {
+		w.sum = l.entry.Stats.Sum
+	} else {
+		w.sum = itr.ops.add(w.sum, l.entry.Stats.Sum)
+	}
+	w.count += int64(l.entry.Stats.Count)
+
+	v := l.entry.Stats.Min
+	if itr.call == "max" {
+		v = l.entry.Stats.Max
+	}
+	if w.block == nil || itr.better(v, w.blockValue) || (itr.equal(v, w.blockValue) && l.entry.MinTime < w.block.entry.MinTime) {
+		w.blockValue, w.block = v, l
+	}
+}
+
+// resolve decodes the block holding the value selected from the summarized
+// blocks of w, if it is the value selected for the window, to find its time.
+func (itr *blockStatsIterator) resolve(w *blockStatsWindow) error {
+	if w.block == nil || (w.hasValue && itr.better(w.value, w.blockValue)) {
+		return nil
+	}
+
+	values, err := w.block.r.ReadAt(&w.block.entry, nil)
+	if err != nil {
+		return err
+	}
+	for _, value := range values {
+		v, _ := valueBits(value)
+		if !itr.equal(v, w.blockValue) {
+			continue
+		}
+
+		t := value.UnixNano()
+		if !w.hasValue || itr.better(v, w.value) || t < w.time {
+			w.value, w.time, w.hasValue = v, t, true
+		}
+		return nil
+	}
+	return fmt.Errorf("block statistics: selected value not found in block: %s", w.block.entry.String())
+}
+
+// cursor returns a function reading the points of the blocks that weren't
+// summarized and of the cache, in the order of the iterator.
+func (itr *blockStatsIterator) cursor() func() (int64, uint64) {
+	switch itr.typ {
+	case influxql.Integer:
+		c := newIntegerCursor(itr.opt.SeekTime(), itr.opt.Ascending, itr.cacheValues, itr.keyCursor)
+		return func() (int64, uint64) {
+			t, v := c.nextInteger()
+			return t, uint64(v)
+		}
+	case influxql.Unsigned:
+		c := newUnsignedCursor(itr.opt.SeekTime(), itr.opt.Ascending, itr.cacheValues, itr.keyCursor)
+		return c.nextUnsigned
+	default:
+		c := newFloatCursor(itr.opt.SeekTime(), itr.opt.Ascending, itr.cacheValues, itr.keyCursor)
+		return func() (int64, uint64) {
+			t, v := c.nextFloat()
+			return t, math.Float64bits(v)
+		}
+	}
+}
+
+// init summarizes the blocks that are entirely within the time range and a
+// single window and don't overlap points in the cache, and starts reading the
+// remaining points.
+func (itr *blockStatsIterator) init() {
+	itr.blocks = itr.keyCursor.summarize(func(e *IndexEntry) bool {
+		if e.MinTime < itr.opt.StartTime || e.MaxTime > itr.opt.EndTime {
+			return false
+		} else if _, end := itr.opt.Window(e.MinTime); e.MaxTime >= end {
+			return false
+		}
+
+		i := sort.Search(len(itr.cacheValues), func(i int) bool {
+			return itr.cacheValues[i].UnixNano() >= e.MinTime
+		})
+		return i == len(itr.cacheValues) || itr.cacheValues[i].UnixNano() > e.MaxTime
+	})
+	if !itr.opt.Ascending {
+		for i, j := 0, len(itr.blocks)-1; i < j; i, j = i+1, j-1 {
+			itr.blocks[i], itr.blocks[j] = itr.blocks[j], itr.blocks[i]
+		}
+	}
+
+	itr.cur = itr.cursor()
+	itr.read()
+}
+
+// read reads the next point from the cursor, setting pointTime to tsdb.EOF
+// once the points of the time range are read.
+func (itr *blockStatsIterator) read() {
+	itr.pointTime, itr.pointValue = itr.cur()
+	if itr.pointTime != tsdb.EOF && (itr.pointTime < itr.opt.StartTime || itr.pointTime > itr.opt.EndTime) {
+		itr.pointTime = tsdb.EOF
+	}
+}
+
+// nextWindow aggregates the summarized blocks and the points of the next
+// window in the order of the iterator, or returns nil once all are read.
+func (itr *blockStatsIterator) nextWindow() *blockStatsWindow {
+	// The next window is the earliest, or latest if descending, of the
+	// windows of the next block and point.
+	var w *blockStatsWindow
+	if len(itr.blocks) > 0 {
+		start, _ := itr.opt.Window(itr.blocks[0].entry.MinTime)
+		w = &blockStatsWindow{start: start}
+	}
+	if itr.pointTime != tsdb.EOF {
+		start, _ := itr.opt.Window(itr.pointTime)
+		if w == nil || (itr.opt.Ascending && start < w.start) || (!itr.opt.Ascending && start > w.start) {
+			w = &blockStatsWindow{start: start}
+		}
+	}
+	if w == nil {
+		return nil
+	}
+
+	var pointN int
+	for len(itr.blocks) > 0 {
+		l := itr.blocks[0]
+		if start, _ := itr.opt.Window(l.entry.MinTime); start != w.start {
+			break
+		}
+		itr.addBlock(w, l)
+		pointN += int(l.entry.Stats.Count)
+		itr.blocks = itr.blocks[1:]
+	}
+
+	_, end := itr.opt.Window(w.start)
+	for itr.pointTime != tsdb.EOF && itr.pointTime >= w.start && itr.pointTime < end {
+		itr.addPoint(w, itr.pointTime, itr.pointValue)
+		pointN++
+		itr.read()
+	}
+
+	itr.statsLock.Lock()
+	itr.stats.SeriesN = 1
+	itr.stats.PointN += pointN
+	itr.statsLock.Unlock()
+	return w
+}
+
+// next returns the point of the next window.
+func (itr *blockStatsIterator) next() (*blockStatsPoint, error) {
+	if itr.cur == nil {
+		itr.init()
+	}
+
+	w := itr.nextWindow()
+	if w == nil {
+		return nil, nil
+	}
+
+	itr.point = blockStatsPoint{time: w.start, aggregated: uint32(w.count)}
+	switch itr.call {
+	case "count":
+		itr.point.value = uint64(w.count)
+	case "sum":
+		itr.point.value = w.sum
+	default:
+		if err := itr.resolve(w); err != nil {
+			return nil, err
+		}
+		itr.point.time, itr.point.value = w.time, w.value
+	}
+	return &itr.point, nil
+}
+
+// Stats returns stats on the points processed.
+func (itr *blockStatsIterator) Stats() query.IteratorStats {
+	itr.statsLock.Lock()
+	stats := itr.stats
+	itr.statsLock.Unlock()
+	return stats
+}
+
+// Close closes the iterator.
+func (itr *blockStatsIterator) Close() error {
+	if itr.keyCursor != nil {
+		itr.keyCursor.Close()
+		itr.keyCursor = nil
+	}
+	itr.cacheValues = nil
+	return nil
+}
+
+type floatBlockStatsIterator struct {
+	*blockStatsIterator
+	point query.FloatPoint
+}
+
+// Next returns the next point from the iterator.
+func (itr *floatBlockStatsIterator) Next() (*query.FloatPoint, error) {
+	p, err := itr.next()
+	if p == nil || err != nil {
+		return nil, err
+	}
+	itr.point = query.FloatPoint{
+		Name:       itr.name,
+		Tags:       itr.tags,
+		Time:       p.time,
+		Value:      math.Float64frombits(p.value),
+		Aggregated: p.aggregated,
+	}
+	return &itr.point, nil
+}
+
+type integerBlockStatsIterator struct {
+	*blockStatsIterator
+	point query.IntegerPoint
+}
+
+// Next returns the next point from the iterator.
+func (itr *integerBlockStatsIterator) Next() (*query.IntegerPoint, error) {
+	p, err := itr.next()
+	if p == nil || err != nil {
+		return nil, err
+	}
+	itr.point = query.IntegerPoint{
+		Name:       itr.name,
+		Tags:       itr.tags,
+		Time:       p.time,
+		Value:      int64(p.value),
+		Aggregated: p.aggregated,
+	}
+	return &itr.point, nil
+}
+
+type unsignedBlockStatsIterator struct {
+	*blockStatsIterator
+	point query.UnsignedPoint
+}
+
+// Next returns the next point from the iterator.
+func (itr *unsignedBlockStatsIterator) Next() (*query.UnsignedPoint, error) {
+	p, err := itr.next()
+	if p == nil || err != nil {
+		return nil, err
+	}
+	itr.point = query.UnsignedPoint{
+		Name:       itr.name,
+		Tags:       itr.tags,
+		Time:       p.time,
+		Value:      p.value,
+		Aggregated: p.aggregated,
+	}
+	return &itr.point, nil
+}
@@ -98,16 +98,17 @@ func (e *entry) add(values []Value) error {
 	return nil
 }
 
-// deduplicate sorts and orders the entry's values. If values are already deduped and sorted,
-// the function does no work and simply returns.
-func (e *entry) deduplicate() {
+// deduplicate sorts and orders the entry's values, merging values with the same
+// timestamp using policy. If values are already deduped and sorted, the function
+// does no work and simply returns.
+func (e *entry) deduplicate(policy DuplicatePolicy) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
 	if len(e.values) <= 1 {
 		return
 	}
-	e.values = e.values.Deduplicate()
+	e.values = e.values.DeduplicateWith(policy)
 }
 
 // count returns the number of values in this entry.
@@ -119,10 +120,10 @@ func (e *entry) count() int {
 }
 
 // filter removes all values with timestamps between min and max inclusive.
-func (e *entry) filter(min, max int64) {
+func (e *entry) filter(min, max int64, policy DuplicatePolicy) {
 	e.mu.Lock()
 	if len(e.values) > 1 {
-		e.values = e.values.Deduplicate()
+		e.values = e.values.DeduplicateWith(policy)
 	}
 	e.values = e.values.Exclude(min, max)
 	e.mu.Unlock()
@@ -194,6 +195,9 @@ type Cache struct {
 	snapshot     *Cache
 	snapshotting bool
 
+	// duplicates is the DuplicatePolicy merging values with the same timestamp.
+	duplicates int32
+
 	// This number is the number of pending or failed WriteSnaphot attempts since the last successful one.
 	snapshotAttempts int
 
@@ -397,6 +401,7 @@ func (c *Cache) Snapshot() (*Cache, error) {
 			store: store,
 		}
 	}
+	atomic.StoreInt32(&c.snapshot.duplicates, atomic.LoadInt32(&c.duplicates))
 
 	// Did a prior snapshot exist that failed?  If so, return the existing
 	// snapshot to retry.
@@ -432,7 +437,18 @@ func (c *Cache) Deduplicate() {
 
 	// Apply a function that simply calls deduplicate on each entry in the ring.
 	// apply cannot return an error in this invocation.
-	_ = store.apply(func(_ []byte, e *entry) error { e.deduplicate(); return nil })
+	policy := c.duplicatePolicy()
+	_ = store.apply(func(_ []byte, e *entry) error { e.deduplicate(policy); return nil })
+}
+
+// SetDuplicatePolicy sets the policy merging values with the same timestamp.
+func (c *Cache) SetDuplicatePolicy(policy DuplicatePolicy) {
+	atomic.StoreInt32(&c.duplicates, int32(policy))
+}
+
+// duplicatePolicy returns the policy merging values with the same timestamp.
+func (c *Cache) duplicatePolicy() DuplicatePolicy {
+	return DuplicatePolicy(atomic.LoadInt32(&c.duplicates))
 }
 
 // ClearSnapshot removes the snapshot cache from the list of flushing caches and
@@ -562,13 +578,14 @@ func (c *Cache) Values(key []byte) Values {
 	}
 	c.mu.RUnlock()
 
+	policy := c.duplicatePolicy()
 	if e == nil {
 		if snapshotEntries == nil {
 			// No values in hot cache or snapshots.
 			return nil
 		}
 	} else {
-		e.deduplicate()
+		e.deduplicate(policy)
 	}
 
 	// Build the sequence of entries that will be returned, in the correct order.
@@ -577,7 +594,7 @@ func (c *Cache) Values(key []byte) Values {
 	sz := 0
 
 	if snapshotEntries != nil {
-		snapshotEntries.deduplicate() // guarantee we are deduplicated
+		snapshotEntries.deduplicate(policy) // guarantee we are deduplicated
 		entries = append(entries, snapshotEntries)
 		sz += snapshotEntries.count()
 	}
@@ -603,7 +620,7 @@ func (c *Cache) Values(key []byte) Values {
 		e.mu.RUnlock()
 	}
 	values = values[:n]
-	values = values.Deduplicate()
+	values = values.DeduplicateWith(policy)
 
 	return values
 }
@@ -637,7 +654,7 @@ func (c *Cache) DeleteRange(keys [][]byte, min, max int64) {
 			continue
 		}
 
-		e.filter(min, max)
+		e.filter(min, max, c.duplicatePolicy())
 		if e.count() == 0 {
 			c.store.remove(k)
 			c.decreaseSize(origSize + uint64(len(k)))
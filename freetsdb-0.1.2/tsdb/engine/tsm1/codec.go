@@ -0,0 +1,271 @@
+package tsm1
+
+// String and float blocks may have their values compressed with zstd instead
+// of their default codecs, snappy and gorilla.  The codec of the values is
+// stored in the upper 4 bits of the first byte of the encoded values like the
+// other encodings.  zstd compressed strings hold the same bytes as snappy
+// compressed strings, and zstd compressed floats hold the 8 byte big endian
+// representation of each value.
+//
+// Blocks are always encoded with the default codecs and recompressed with the
+// codecs of the retention policy of their shard when they are written by the
+// Compactor.
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/freetsdb/freetsdb/tsdb"
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// stringCompressedZstd is a compressed encoding using zstd compression
+	stringCompressedZstd = 2
+
+	// floatCompressedZstd is a compressed encoding using zstd compression
+	floatCompressedZstd = 2
+)
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+// zstdCodec returns the shared zstd encoder and decoder, which are safe for
+// concurrent use by EncodeAll and DecodeAll.
+func zstdCodec() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil)
+		if zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+// zstdEncode appends the zstd compressed src to dst.
+func zstdEncode(dst, src []byte) ([]byte, error) {
+	enc, _, err := zstdCodec()
+	if err != nil {
+		return nil, err
+	}
+	return enc.EncodeAll(src, dst), nil
+}
+
+// zstdDecode returns a newly allocated slice holding the decompressed src.
+func zstdDecode(src []byte) ([]byte, error) {
+	_, dec, err := zstdCodec()
+	if err != nil {
+		return nil, err
+	}
+	return dec.DecodeAll(src, nil)
+}
+
+// decodeStringBytes returns a newly allocated slice holding the uncompressed
+// bytes of encoded strings.
+func decodeStringBytes(b []byte) ([]byte, error) {
+	var data []byte
+	var err error
+	switch b[0] >> 4 {
+	case stringCompressedZstd:
+		data, err = zstdDecode(b[1:])
+	default:
+		data, err = snappy.Decode(nil, b[1:])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode string block: %v", err.Error())
+	}
+	return data, nil
+}
+
+// floatArrayDecodeZstd decodes zstd compressed floats into buf.
+func floatArrayDecodeZstd(b []byte, buf []float64) ([]float64, error) {
+	data, err := zstdDecode(b[1:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode float block: %v", err.Error())
+	} else if len(data)%8 != 0 {
+		return nil, fmt.Errorf("failed to decode float block: invalid length %d", len(data))
+	}
+
+	n := len(data) / 8
+	if cap(buf) < n {
+		buf = make([]float64, n)
+	} else {
+		buf = buf[:n]
+	}
+	for i := range buf {
+		buf[i] = math.Float64frombits(binary.BigEndian.Uint64(data[i*8:]))
+	}
+	return buf, nil
+}
+
+// floatArrayEncodeZstd encodes src compressed with zstd.
+func floatArrayEncodeZstd(src []float64) ([]byte, error) {
+	data := make([]byte, len(src)*8)
+	for i, v := range src {
+		binary.BigEndian.PutUint64(data[i*8:], math.Float64bits(v))
+	}
+	return zstdEncode([]byte{floatCompressedZstd << 4}, data)
+}
+
+// blockCodecs are the codecs of the values of string and float blocks.
+type blockCodecs struct {
+	str, float byte
+}
+
+// defaultBlockCodecs are the codecs blocks are encoded with.
+var defaultBlockCodecs = blockCodecs{str: stringCompressedSnappy, float: floatCompressedGorilla}
+
+// recompress returns block with its values compressed with the codecs, or
+// block itself if they are already.
+func (c blockCodecs) recompress(block []byte) ([]byte, error) {
+	if len(block) == 0 || (block[0] != BlockString && block[0] != BlockFloat64) {
+		return block, nil
+	}
+
+	tb, vb, err := unpackBlock(block[1:])
+	if err != nil {
+		return nil, err
+	} else if len(vb) == 0 {
+		return block, nil
+	}
+
+	switch block[0] {
+	case BlockString:
+		if vb[0]>>4 == c.str {
+			return block, nil
+		}
+
+		data, err := decodeStringBytes(vb)
+		if err != nil {
+			return nil, err
+		}
+		if c.str == stringCompressedZstd {
+			vb, err = zstdEncode([]byte{stringCompressedZstd << 4}, data)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			vb = append([]byte{stringCompressedSnappy << 4}, snappy.Encode(nil, data)...)
+		}
+	case BlockFloat64:
+		if vb[0]>>4 == c.float {
+			return block, nil
+		}
+
+		values, err := FloatArrayDecodeAll(vb, nil)
+		if err != nil {
+			return nil, err
+		}
+		if c.float == floatCompressedZstd {
+			vb, err = floatArrayEncodeZstd(values)
+		} else {
+			vb, err = FloatArrayEncodeAll(values, nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return packBlock(nil, block[0], tb, vb), nil
+}
+
+// parseBlockCodecs returns the codecs named str and float.  Empty or unknown
+// names select the default codecs, snappy and gorilla.
+func parseBlockCodecs(str, float string) blockCodecs {
+	c := defaultBlockCodecs
+	if str == "zstd" {
+		c.str = stringCompressedZstd
+	}
+	if float == "zstd" {
+		c.float = floatCompressedZstd
+	}
+	return c
+}
+
+// CodecStat describes how the values of a block are compressed.
+type CodecStat struct {
+	// Codec is the name of the codec of the values, prefixed by their type.
+	Codec string
+
+	// N is the number of values.
+	N int
+
+	// Size is the size in bytes of the compressed values and RawSize the
+	// size in bytes of the uncompressed values.
+	Size, RawSize int
+}
+
+// BlockCodecStat returns how the values of block are compressed.  Timestamps
+// are not included.
+func BlockCodecStat(block []byte) (CodecStat, error) {
+	var stat CodecStat
+	if len(block) <= encodedBlockHeaderSize {
+		return stat, fmt.Errorf("short block: got %v, exp > %v", len(block), encodedBlockHeaderSize)
+	}
+
+	_, vb, err := unpackBlock(block[1:])
+	if err != nil {
+		return stat, err
+	}
+	stat.Size = len(vb)
+
+	var encoding byte
+	if len(vb) > 0 {
+		encoding = vb[0] >> 4
+	}
+
+	switch block[0] {
+	case BlockFloat64:
+		var a tsdb.FloatArray
+		if err := DecodeFloatArrayBlock(block, &a); err != nil {
+			return stat, err
+		}
+		stat.N, stat.RawSize = a.Len(), a.Len()*8
+		stat.Codec = "float/gorilla"
+		if encoding == floatCompressedZstd {
+			stat.Codec = "float/zstd"
+		}
+	case BlockInteger, BlockUnsigned:
+		stat.N = BlockCount(block)
+		stat.RawSize = stat.N * 8
+		typ := "integer"
+		if block[0] == BlockUnsigned {
+			typ = "unsigned"
+		}
+		switch encoding {
+		case intCompressedSimple:
+			stat.Codec = typ + "/simple8b"
+		case intCompressedRLE:
+			stat.Codec = typ + "/rle"
+		default:
+			stat.Codec = typ + "/uncompressed"
+		}
+	case BlockBoolean:
+		stat.N = BlockCount(block)
+		stat.RawSize = stat.N
+		stat.Codec = "boolean/bitpacked"
+	case BlockString:
+		var a tsdb.StringArray
+		if err := DecodeStringArrayBlock(block, &a); err != nil {
+			return stat, err
+		}
+		stat.N = a.Len()
+		for _, v := range a.Values {
+			stat.RawSize += len(v)
+		}
+		stat.Codec = "string/snappy"
+		if encoding == stringCompressedZstd {
+			stat.Codec = "string/zstd"
+		}
+	default:
+		return stat, fmt.Errorf("unknown block type: %d", block[0])
+	}
+	return stat, nil
+}
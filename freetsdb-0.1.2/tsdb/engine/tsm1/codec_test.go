@@ -0,0 +1,133 @@
+package tsm1
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/freetsdb/freetsdb/tsdb"
+)
+
+func TestBlockCodecs_Recompress_Float(t *testing.T) {
+	values := make([]Value, 100)
+	for i := range values {
+		values[i] = NewValue(int64(i), float64(i)*1.5)
+	}
+
+	block, err := Values(values).Encode(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	codecs := parseBlockCodecs("", "zstd")
+	zb, err := codecs.recompress(block)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stat, err := BlockCodecStat(zb)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if got, exp := stat.Codec, "float/zstd"; got != exp {
+		t.Fatalf("codec mismatch: got %v, exp %v", got, exp)
+	} else if got, exp := stat.N, len(values); got != exp {
+		t.Fatalf("count mismatch: got %v, exp %v", got, exp)
+	}
+
+	decoded, err := DecodeBlock(zb, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(decoded, values) {
+		t.Fatalf("values mismatch:\ngot %v\nexp %v", decoded, values)
+	}
+
+	var a tsdb.FloatArray
+	if err := DecodeFloatArrayBlock(zb, &a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, v := range values {
+		if a.Timestamps[i] != v.UnixNano() || a.Values[i] != v.Value() {
+			t.Fatalf("value %d mismatch: got %v=%v, exp %v", i, a.Timestamps[i], a.Values[i], v)
+		}
+	}
+
+	// Recompressing back to the default codec returns the original block.
+	b, err := defaultBlockCodecs.recompress(zb)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(b, block) {
+		t.Fatalf("block mismatch after recompressing with gorilla")
+	}
+}
+
+func TestBlockCodecs_Recompress_String(t *testing.T) {
+	values := make([]Value, 100)
+	for i := range values {
+		values[i] = NewValue(int64(i), fmt.Sprintf(`{"level":"info","msg":"request %d"}`, i))
+	}
+
+	block, err := Values(values).Encode(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	codecs := parseBlockCodecs("zstd", "")
+	zb, err := codecs.recompress(block)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stat, err := BlockCodecStat(zb)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if got, exp := stat.Codec, "string/zstd"; got != exp {
+		t.Fatalf("codec mismatch: got %v, exp %v", got, exp)
+	} else if stat.Size >= stat.RawSize {
+		t.Fatalf("values not compressed: size %v, raw size %v", stat.Size, stat.RawSize)
+	}
+
+	decoded, err := DecodeBlock(zb, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(decoded, values) {
+		t.Fatalf("values mismatch:\ngot %v\nexp %v", decoded, values)
+	}
+
+	var a tsdb.StringArray
+	if err := DecodeStringArrayBlock(zb, &a); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, v := range values {
+		if a.Timestamps[i] != v.UnixNano() || a.Values[i] != v.Value() {
+			t.Fatalf("value %d mismatch: got %v=%v, exp %v", i, a.Timestamps[i], a.Values[i], v)
+		}
+	}
+
+	// Blocks of other types are left alone.
+	ib, err := Values([]Value{NewValue(0, int64(1))}).Encode(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b, err := codecs.recompress(ib); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !reflect.DeepEqual(b, ib) {
+		t.Fatalf("integer block changed")
+	}
+}
+
+func TestParseBlockCodecs(t *testing.T) {
+	for _, tt := range []struct {
+		str, float string
+		exp        blockCodecs
+	}{
+		{"", "", defaultBlockCodecs},
+		{"snappy", "gorilla", defaultBlockCodecs},
+		{"zstd", "", blockCodecs{str: stringCompressedZstd, float: floatCompressedGorilla}},
+		{"", "zstd", blockCodecs{str: stringCompressedSnappy, float: floatCompressedZstd}},
+		{"zstd", "zstd", blockCodecs{str: stringCompressedZstd, float: floatCompressedZstd}},
+	} {
+		if got := parseBlockCodecs(tt.str, tt.float); got != tt.exp {
+			t.Fatalf("parseBlockCodecs(%q, %q): got %+v, exp %+v", tt.str, tt.float, got, tt.exp)
+		}
+	}
+}
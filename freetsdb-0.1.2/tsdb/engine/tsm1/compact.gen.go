@@ -1057,7 +1057,13 @@ func (k *tsmBatchKeyIterator) combineFloat(dedup bool) blocks {
 					if k.blocks[i].minTime < minTime {
 						minTime = k.blocks[i].minTime
 					}
-					if k.blocks[i].maxTime > minTime && k.blocks[i].maxTime < maxTime {
+					if k.duplicates == DuplicateKeep {
+						// Values moved by keep must be merged with every value of the
+						// overlapping blocks at once, to be moved the same way as by reads.
+						if k.blocks[i].maxTime > maxTime {
+							maxTime = k.blocks[i].maxTime
+						}
+					} else if k.blocks[i].maxTime > minTime && k.blocks[i].maxTime < maxTime {
 						maxTime = k.blocks[i].maxTime
 					}
 				}
@@ -1099,7 +1105,7 @@ func (k *tsmBatchKeyIterator) combineFloat(dedup bool) blocks {
 					v.Exclude(ts.Min, ts.Max)
 				}
 
-				k.mergedFloatValues.Merge(&v)
+				k.mergeFloatArray(&v)
 			}
 		}
 
@@ -1172,7 +1178,7 @@ func (k *tsmBatchKeyIterator) combineFloat(dedup bool) blocks {
 
 		k.blocks[i].markRead(k.blocks[i].minTime, k.blocks[i].maxTime)
 
-		k.mergedFloatValues.Merge(&v)
+		k.mergeFloatArray(&v)
 		i++
 	}
 
@@ -1181,6 +1187,30 @@ func (k *tsmBatchKeyIterator) combineFloat(dedup bool) blocks {
 	return k.chunkFloat(k.merged)
 }
 
+// mergeFloatArray merges v into the merged values, where the values of v were
+// written after the merged values, using the duplicate policy of the iterator.
+func (k *tsmBatchKeyIterator) mergeFloatArray(v *tsdb.FloatArray) {
+	if k.duplicates == DuplicateLast {
+		k.mergedFloatValues.Merge(v)
+		return
+	}
+
+	a := k.mergedFloatValues
+	values := make(FloatValues, 0, a.Len()+v.Len())
+	for _, b := range []*tsdb.FloatArray{a, v} {
+		for i, t := range b.Timestamps {
+			values = append(values, FloatValue{unixnano: t, value: b.Values[i]})
+		}
+	}
+	values = values.DeduplicateWith(k.duplicates)
+
+	a.Timestamps, a.Values = a.Timestamps[:0], a.Values[:0]
+	for _, v := range values {
+		a.Timestamps = append(a.Timestamps, v.unixnano)
+		a.Values = append(a.Values, v.value)
+	}
+}
+
 func (k *tsmBatchKeyIterator) chunkFloat(dst blocks) blocks {
 	if k.mergedFloatValues.Len() > k.size {
 		var values tsdb.FloatArray
@@ -1276,7 +1306,13 @@ func (k *tsmBatchKeyIterator) combineInteger(dedup bool) blocks {
 					if k.blocks[i].minTime < minTime {
 						minTime = k.blocks[i].minTime
 					}
-					if k.blocks[i].maxTime > minTime && k.blocks[i].maxTime < maxTime {
+					if k.duplicates == DuplicateKeep {
+						// Values moved by keep must be merged with every value of the
+						// overlapping blocks at once, to be moved the same way as by reads.
+						if k.blocks[i].maxTime > maxTime {
+							maxTime = k.blocks[i].maxTime
+						}
+					} else if k.blocks[i].maxTime > minTime && k.blocks[i].maxTime < maxTime {
 						maxTime = k.blocks[i].maxTime
 					}
 				}
@@ -1318,7 +1354,7 @@ func (k *tsmBatchKeyIterator) combineInteger(dedup bool) blocks {
 					v.Exclude(ts.Min, ts.Max)
 				}
 
-				k.mergedIntegerValues.Merge(&v)
+				k.mergeIntegerArray(&v)
 			}
 		}
 
@@ -1391,7 +1427,7 @@ func (k *tsmBatchKeyIterator) combineInteger(dedup bool) blocks {
 
 		k.blocks[i].markRead(k.blocks[i].minTime, k.blocks[i].maxTime)
 
-		k.mergedIntegerValues.Merge(&v)
+		k.mergeIntegerArray(&v)
 		i++
 	}
 
@@ -1400,6 +1436,30 @@ func (k *tsmBatchKeyIterator) combineInteger(dedup bool) blocks {
 	return k.chunkInteger(k.merged)
 }
 
+// mergeIntegerArray merges v into the merged values, where the values of v were
+// written after the merged values, using the duplicate policy of the iterator.
+func (k *tsmBatchKeyIterator) mergeIntegerArray(v *tsdb.IntegerArray) {
+	if k.duplicates == DuplicateLast {
+		k.mergedIntegerValues.Merge(v)
+		return
+	}
+
+	a := k.mergedIntegerValues
+	values := make(IntegerValues, 0, a.Len()+v.Len())
+	for _, b := range []*tsdb.IntegerArray{a, v} {
+		for i, t := range b.Timestamps {
+			values = append(values, IntegerValue{unixnano: t, value: b.Values[i]})
+		}
+	}
+	values = values.DeduplicateWith(k.duplicates)
+
+	a.Timestamps, a.Values = a.Timestamps[:0], a.Values[:0]
+	for _, v := range values {
+		a.Timestamps = append(a.Timestamps, v.unixnano)
+		a.Values = append(a.Values, v.value)
+	}
+}
+
 func (k *tsmBatchKeyIterator) chunkInteger(dst blocks) blocks {
 	if k.mergedIntegerValues.Len() > k.size {
 		var values tsdb.IntegerArray
@@ -1495,7 +1555,13 @@ func (k *tsmBatchKeyIterator) combineUnsigned(dedup bool) blocks {
 					if k.blocks[i].minTime < minTime {
 						minTime = k.blocks[i].minTime
 					}
-					if k.blocks[i].maxTime > minTime && k.blocks[i].maxTime < maxTime {
+					if k.duplicates == DuplicateKeep {
+						// Values moved by keep must be merged with every value of the
+						// overlapping blocks at once, to be moved the same way as by reads.
+						if k.blocks[i].maxTime > maxTime {
+							maxTime = k.blocks[i].maxTime
+						}
+					} else if k.blocks[i].maxTime > minTime && k.blocks[i].maxTime < maxTime {
 						maxTime = k.blocks[i].maxTime
 					}
 				}
@@ -1537,7 +1603,7 @@ func (k *tsmBatchKeyIterator) combineUnsigned(dedup bool) blocks {
 					v.Exclude(ts.Min, ts.Max)
 				}
 
-				k.mergedUnsignedValues.Merge(&v)
+				k.mergeUnsignedArray(&v)
 			}
 		}
 
@@ -1610,7 +1676,7 @@ func (k *tsmBatchKeyIterator) combineUnsigned(dedup bool) blocks {
 
 		k.blocks[i].markRead(k.blocks[i].minTime, k.blocks[i].maxTime)
 
-		k.mergedUnsignedValues.Merge(&v)
+		k.mergeUnsignedArray(&v)
 		i++
 	}
 
@@ -1619,6 +1685,30 @@ func (k *tsmBatchKeyIterator) combineUnsigned(dedup bool) blocks {
 	return k.chunkUnsigned(k.merged)
 }
 
+// mergeUnsignedArray merges v into the merged values, where the values of v were
+// written after the merged values, using the duplicate policy of the iterator.
+func (k *tsmBatchKeyIterator) mergeUnsignedArray(v *tsdb.UnsignedArray) {
+	if k.duplicates == DuplicateLast {
+		k.mergedUnsignedValues.Merge(v)
+		return
+	}
+
+	a := k.mergedUnsignedValues
+	values := make(UnsignedValues, 0, a.Len()+v.Len())
+	for _, b := range []*tsdb.UnsignedArray{a, v} {
+		for i, t := range b.Timestamps {
+			values = append(values, UnsignedValue{unixnano: t, value: b.Values[i]})
+		}
+	}
+	values = values.DeduplicateWith(k.duplicates)
+
+	a.Timestamps, a.Values = a.Timestamps[:0], a.Values[:0]
+	for _, v := range values {
+		a.Timestamps = append(a.Timestamps, v.unixnano)
+		a.Values = append(a.Values, v.value)
+	}
+}
+
 func (k *tsmBatchKeyIterator) chunkUnsigned(dst blocks) blocks {
 	if k.mergedUnsignedValues.Len() > k.size {
 		var values tsdb.UnsignedArray
@@ -1714,7 +1804,13 @@ func (k *tsmBatchKeyIterator) combineString(dedup bool) blocks {
 					if k.blocks[i].minTime < minTime {
 						minTime = k.blocks[i].minTime
 					}
-					if k.blocks[i].maxTime > minTime && k.blocks[i].maxTime < maxTime {
+					if k.duplicates == DuplicateKeep {
+						// Values moved by keep must be merged with every value of the
+						// overlapping blocks at once, to be moved the same way as by reads.
+						if k.blocks[i].maxTime > maxTime {
+							maxTime = k.blocks[i].maxTime
+						}
+					} else if k.blocks[i].maxTime > minTime && k.blocks[i].maxTime < maxTime {
 						maxTime = k.blocks[i].maxTime
 					}
 				}
@@ -1756,7 +1852,7 @@ func (k *tsmBatchKeyIterator) combineString(dedup bool) blocks {
 					v.Exclude(ts.Min, ts.Max)
 				}
 
-				k.mergedStringValues.Merge(&v)
+				k.mergeStringArray(&v)
 			}
 		}
 
@@ -1829,7 +1925,7 @@ func (k *tsmBatchKeyIterator) combineString(dedup bool) blocks {
 
 		k.blocks[i].markRead(k.blocks[i].minTime, k.blocks[i].maxTime)
 
-		k.mergedStringValues.Merge(&v)
+		k.mergeStringArray(&v)
 		i++
 	}
 
@@ -1838,6 +1934,30 @@ func (k *tsmBatchKeyIterator) combineString(dedup bool) blocks {
 	return k.chunkString(k.merged)
 }
 
+// mergeStringArray merges v into the merged values, where the values of v were
+// written after the merged values, using the duplicate policy of the iterator.
+func (k *tsmBatchKeyIterator) mergeStringArray(v *tsdb.StringArray) {
+	if k.duplicates == DuplicateLast {
+		k.mergedStringValues.Merge(v)
+		return
+	}
+
+	a := k.mergedStringValues
+	values := make(StringValues, 0, a.Len()+v.Len())
+	for _, b := range []*tsdb.StringArray{a, v} {
+		for i, t := range b.Timestamps {
+			values = append(values, StringValue{unixnano: t, value: b.Values[i]})
+		}
+	}
+	values = values.DeduplicateWith(k.duplicates)
+
+	a.Timestamps, a.Values = a.Timestamps[:0], a.Values[:0]
+	for _, v := range values {
+		a.Timestamps = append(a.Timestamps, v.unixnano)
+		a.Values = append(a.Values, v.value)
+	}
+}
+
 func (k *tsmBatchKeyIterator) chunkString(dst blocks) blocks {
 	if k.mergedStringValues.Len() > k.size {
 		var values tsdb.StringArray
@@ -1933,7 +2053,13 @@ func (k *tsmBatchKeyIterator) combineBoolean(dedup bool) blocks {
 					if k.blocks[i].minTime < minTime {
 						minTime = k.blocks[i].minTime
 					}
-					if k.blocks[i].maxTime > minTime && k.blocks[i].maxTime < maxTime {
+					if k.duplicates == DuplicateKeep {
+						// Values moved by keep must be merged with every value of the
+						// overlapping blocks at once, to be moved the same way as by reads.
+						if k.blocks[i].maxTime > maxTime {
+							maxTime = k.blocks[i].maxTime
+						}
+					} else if k.blocks[i].maxTime > minTime && k.blocks[i].maxTime < maxTime {
 						maxTime = k.blocks[i].maxTime
 					}
 				}
@@ -1975,7 +2101,7 @@ func (k *tsmBatchKeyIterator) combineBoolean(dedup bool) blocks {
 					v.Exclude(ts.Min, ts.Max)
 				}
 
-				k.mergedBooleanValues.Merge(&v)
+				k.mergeBooleanArray(&v)
 			}
 		}
 
@@ -2048,7 +2174,7 @@ func (k *tsmBatchKeyIterator) combineBoolean(dedup bool) blocks {
 
 		k.blocks[i].markRead(k.blocks[i].minTime, k.blocks[i].maxTime)
 
-		k.mergedBooleanValues.Merge(&v)
+		k.mergeBooleanArray(&v)
 		i++
 	}
 
@@ -2057,6 +2183,30 @@ func (k *tsmBatchKeyIterator) combineBoolean(dedup bool) blocks {
 	return k.chunkBoolean(k.merged)
 }
 
+// mergeBooleanArray merges v into the merged values, where the values of v were
+// written after the merged values, using the duplicate policy of the iterator.
+func (k *tsmBatchKeyIterator) mergeBooleanArray(v *tsdb.BooleanArray) {
+	if k.duplicates == DuplicateLast {
+		k.mergedBooleanValues.Merge(v)
+		return
+	}
+
+	a := k.mergedBooleanValues
+	values := make(BooleanValues, 0, a.Len()+v.Len())
+	for _, b := range []*tsdb.BooleanArray{a, v} {
+		for i, t := range b.Timestamps {
+			values = append(values, BooleanValue{unixnano: t, value: b.Values[i]})
+		}
+	}
+	values = values.DeduplicateWith(k.duplicates)
+
+	a.Timestamps, a.Values = a.Timestamps[:0], a.Values[:0]
+	for _, v := range values {
+		a.Timestamps = append(a.Timestamps, v.unixnano)
+		a.Values = append(a.Values, v.value)
+	}
+}
+
 func (k *tsmBatchKeyIterator) chunkBoolean(dst blocks) blocks {
 	if k.mergedBooleanValues.Len() > k.size {
 		var values tsdb.BooleanArray
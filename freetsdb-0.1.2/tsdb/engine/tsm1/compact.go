@@ -84,6 +84,11 @@ type CompactionPlanner interface {
 	// time Plan() is called if there are files that could be compacted.
 	ForceFull()
 
+	// ForceOptimize causes the planner to return an optimize plan the next
+	// time PlanOptimize() is called even if optimizing would not be
+	// worthwhile yet.
+	ForceOptimize()
+
 	SetFileStore(fs *FileStore)
 }
 
@@ -115,6 +120,10 @@ type DefaultPlanner struct {
 	// infrequently as the plans are more expensive to run.
 	forceFull bool
 
+	// forceOptimize causes the next optimize plan request to plan the level 4
+	// generations even if there are too few of them to be worth optimizing.
+	forceOptimize bool
+
 	// filesInUse is the set of files that have been returned as part of a plan and might
 	// be being compacted.  Two plans should not return the same file at any given time.
 	filesInUse map[string]struct{}
@@ -212,6 +221,14 @@ func (c *DefaultPlanner) ForceFull() {
 	c.forceFull = true
 }
 
+// ForceOptimize causes the planner to return an optimize plan of the level 4
+// generations the next time one is requested, however few there are.
+func (c *DefaultPlanner) ForceOptimize() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forceOptimize = true
+}
+
 // PlanLevel returns a set of TSM files to rewrite for a specific level.
 func (c *DefaultPlanner) PlanLevel(level int) []CompactionGroup {
 	// If a full plan has been requested, don't plan any levels which will prevent
@@ -312,12 +329,14 @@ func (c *DefaultPlanner) PlanLevel(level int) []CompactionGroup {
 func (c *DefaultPlanner) PlanOptimize() []CompactionGroup {
 	// If a full plan has been requested, don't plan any levels which will prevent
 	// the full plan from acquiring them.
-	c.mu.RLock()
+	c.mu.Lock()
 	if c.forceFull {
-		c.mu.RUnlock()
+		c.mu.Unlock()
 		return nil
 	}
-	c.mu.RUnlock()
+	forceOptimize := c.forceOptimize
+	c.forceOptimize = false
+	c.mu.Unlock()
 
 	// Determine the generations from all files on disk.  We need to treat
 	// a generation conceptually as a single file even though it may be
@@ -376,8 +395,9 @@ func (c *DefaultPlanner) PlanOptimize() []CompactionGroup {
 
 	var cGroups []CompactionGroup
 	for _, group := range levelGroups {
-		// Skip the group if it's not worthwhile to optimize it
-		if len(group) < 4 && !group.hasTombstones() {
+		// Skip the group if it's not worthwhile to optimize it, unless an
+		// optimize plan of several generations has been requested.
+		if len(group) < 4 && !group.hasTombstones() && (!forceOptimize || len(group) < 2) {
 			continue
 		}
 
@@ -392,6 +412,12 @@ func (c *DefaultPlanner) PlanOptimize() []CompactionGroup {
 	}
 
 	if !c.acquire(cGroups) {
+		// Retry a requested plan once the files are no longer in use.
+		if forceOptimize {
+			c.mu.Lock()
+			c.forceOptimize = true
+			c.mu.Unlock()
+		}
 		return nil
 	}
 
@@ -695,6 +721,13 @@ type Compactor struct {
 	formatFileName FormatFileNameFunc
 	parseFileName  ParseFileNameFunc
 
+	// codecs returns the codecs blocks are recompressed with when written.
+	// nil writes blocks as they are.
+	codecs func() blockCodecs
+
+	// duplicates returns the policy merging values with the same timestamp.
+	duplicates func() DuplicatePolicy
+
 	mu                 sync.RWMutex
 	snapshotsEnabled   bool
 	compactionsEnabled bool
@@ -710,6 +743,9 @@ type Compactor struct {
 	compactionsInterrupt chan struct{}
 
 	files map[string]struct{}
+
+	// running tracks the compactions in progress by their first file.
+	running map[string]*compactionProgress
 }
 
 // NewCompactor returns a new instance of Compactor.
@@ -720,6 +756,14 @@ func NewCompactor() *Compactor {
 	}
 }
 
+// duplicatePolicy returns the policy merging values with the same timestamp.
+func (c *Compactor) duplicatePolicy() DuplicatePolicy {
+	if c.duplicates == nil {
+		return DuplicateLast
+	}
+	return c.duplicates()
+}
+
 func (c *Compactor) WithFormatFileNameFunc(formatFileNameFunc FormatFileNameFunc) {
 	c.formatFileName = formatFileNameFunc
 }
@@ -743,6 +787,7 @@ func (c *Compactor) Open() {
 	c.snapshotLatencies = &latencies{values: make([]time.Duration, 4)}
 
 	c.files = make(map[string]struct{})
+	c.running = make(map[string]*compactionProgress)
 }
 
 // Close disables the Compactor.
@@ -877,8 +922,9 @@ func (c *Compactor) WriteSnapshot(cache *Cache) ([]string, error) {
 	return files, err
 }
 
-// compact writes multiple smaller TSM files into 1 or more larger files.
-func (c *Compactor) compact(fast bool, tsmFiles []string) ([]string, error) {
+// compact writes multiple smaller TSM files into 1 or more larger files.  If
+// conv is not nil the values of its field are converted while writing.
+func (c *Compactor) compact(fast bool, tsmFiles []string, conv *fieldConverter) ([]string, error) {
 	size := c.Size
 	if size <= 0 {
 		size = tsdb.DefaultMaxPointsPerBlock
@@ -932,11 +978,15 @@ func (c *Compactor) compact(fast bool, tsmFiles []string) ([]string, error) {
 		return nil, nil
 	}
 
-	tsm, err := NewTSMBatchKeyIterator(size, fast, intC, trs...)
-	if err != nil {
-		return nil, err
+	var tsm KeyIterator = newTSMBatchKeyIterator(size, fast, c.duplicatePolicy(), intC, trs...)
+	if conv != nil {
+		tsm = &convertKeyIterator{KeyIterator: tsm, conv: conv}
 	}
 
+	p := c.track(tsmFiles, trs)
+	defer c.untrack(tsmFiles)
+	tsm = &progressKeyIterator{KeyIterator: tsm, progress: p}
+
 	return c.writeNewFiles(maxGeneration, maxSequence, tsmFiles, tsm, true)
 }
 
@@ -955,7 +1005,7 @@ func (c *Compactor) CompactFull(tsmFiles []string) ([]string, error) {
 	}
 	defer c.remove(tsmFiles)
 
-	files, err := c.compact(false, tsmFiles)
+	files, err := c.compact(false, tsmFiles, nil)
 
 	// See if we were disabled while writing a snapshot
 	c.mu.RLock()
@@ -987,7 +1037,7 @@ func (c *Compactor) CompactFast(tsmFiles []string) ([]string, error) {
 	}
 	defer c.remove(tsmFiles)
 
-	files, err := c.compact(true, tsmFiles)
+	files, err := c.compact(true, tsmFiles, nil)
 
 	// See if we were disabled while writing a snapshot
 	c.mu.RLock()
@@ -1005,6 +1055,18 @@ func (c *Compactor) CompactFast(tsmFiles []string) ([]string, error) {
 
 }
 
+// compactConvert rewrites tsmFiles, which must hold every file of their
+// generation, with the values of the field of conv converted to its type.  It
+// runs while level compactions are disabled.
+func (c *Compactor) compactConvert(tsmFiles []string, conv *fieldConverter) ([]string, error) {
+	if !c.add(tsmFiles) {
+		return nil, errCompactionInProgress{}
+	}
+	defer c.remove(tsmFiles)
+
+	return c.compact(false, tsmFiles, conv)
+}
+
 // removeTmpFiles is responsible for cleaning up a compaction that
 // was started, but then abandoned before the temporary files were dealt with.
 func (c *Compactor) removeTmpFiles(files []string) error {
@@ -1125,6 +1187,13 @@ func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err err
 		}
 	}()
 
+	// Resolve the codecs once so an update applies to whole files.
+	var codecs *blockCodecs
+	if c.codecs != nil {
+		bc := c.codecs()
+		codecs = &bc
+	}
+
 	for iter.Next() {
 		c.mu.RLock()
 		enabled := c.snapshotsEnabled || c.compactionsEnabled
@@ -1145,8 +1214,15 @@ func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err err
 			return fmt.Errorf("invalid index entry for block. min=%d, max=%d", minTime, maxTime)
 		}
 
-		// Write the key and value
-		if err := w.WriteBlock(key, minTime, maxTime, block); err == ErrMaxBlocksExceeded {
+		if codecs != nil {
+			if block, err = codecs.recompress(block); err != nil {
+				return err
+			}
+		}
+
+		// Write the key and value.  Blocks copied unchanged keep the statistics
+		// of their index entry instead of being decoded again.
+		if err := w.WriteBlockWithStats(key, minTime, maxTime, block, iter.BlockStats()); err == ErrMaxBlocksExceeded {
 			if err := w.WriteIndex(); err != nil {
 				return err
 			}
@@ -1204,6 +1280,114 @@ func (c *Compactor) remove(files []string) {
 	}
 }
 
+// track registers the compaction of files read by trs as running.
+func (c *Compactor) track(files []string, trs []*TSMReader) *compactionProgress {
+	p := &compactionProgress{trs: trs}
+	for _, tr := range trs {
+		p.bytesIn += int64(tr.Size())
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.running != nil {
+		c.running[files[0]] = p
+	}
+	return p
+}
+
+// untrack removes the compaction of files registered by track.  It must be
+// called before the readers of the files are released.
+func (c *Compactor) untrack(files []string) {
+	c.mu.Lock()
+	p := c.running[files[0]]
+	delete(c.running, files[0])
+	c.mu.Unlock()
+
+	if p != nil {
+		p.mu.Lock()
+		p.trs = nil
+		p.mu.Unlock()
+	}
+}
+
+// progress returns the progress of the running compaction of group, or nil
+// if it is not running.
+func (c *Compactor) progress(group CompactionGroup) *compactionProgress {
+	if len(group) == 0 {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.running[group[0]]
+}
+
+// compactionProgress tracks how far a running compaction has got.
+type compactionProgress struct {
+	bytesOut int64 // size of the blocks read so far, updated atomically
+	bytesIn  int64 // size of the compacted files
+	trs      []*TSMReader
+
+	mu  sync.Mutex // guards key and trs, which untrack clears
+	key []byte     // last key read
+}
+
+// read records that block of key has been read.
+func (p *compactionProgress) read(key, block []byte) {
+	atomic.AddInt64(&p.bytesOut, int64(len(block)))
+
+	p.mu.Lock()
+	if !bytes.Equal(p.key, key) {
+		p.key = append(p.key[:0], key...)
+	}
+	p.mu.Unlock()
+}
+
+// written returns the size of the blocks read so far.
+func (p *compactionProgress) written() int64 {
+	return atomic.LoadInt64(&p.bytesOut)
+}
+
+// percent returns the percentage of the keys of the compacted files read so
+// far, counting the keys up to and including the last key read.
+func (p *compactionProgress) percent() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.key == nil || p.trs == nil {
+		return 0
+	}
+
+	var n, total int
+	for _, tr := range p.trs {
+		i := tr.Seek(p.key)
+		if key, _ := tr.KeyAt(i); bytes.Equal(key, p.key) {
+			i++
+		}
+		n += i
+		total += tr.KeyCount()
+	}
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+// progressKeyIterator records the blocks read from a KeyIterator in a
+// compactionProgress.
+type progressKeyIterator struct {
+	KeyIterator
+	progress *compactionProgress
+}
+
+// Read returns the next block and records it.
+func (k *progressKeyIterator) Read() ([]byte, int64, int64, []byte, error) {
+	key, minTime, maxTime, block, err := k.KeyIterator.Read()
+	if err == nil {
+		k.progress.read(key, block)
+	}
+	return key, minTime, maxTime, block, err
+}
+
 // KeyIterator allows iteration over set of keys and values in sorted order.
 type KeyIterator interface {
 	// Next returns true if there are any values remaining in the iterator.
@@ -1222,6 +1406,10 @@ type KeyIterator interface {
 	// EstimatedIndexSize returns the estimated size of the index that would
 	// be required to store all the series and entries in the KeyIterator.
 	EstimatedIndexSize() int
+
+	// BlockStats returns the statistics of the values of the block returned
+	// by Read if they are known without decoding it, or no statistics.
+	BlockStats() BlockStats
 }
 
 // tsmKeyIterator implements the KeyIterator for set of TSMReaders.  Iteration produces
@@ -1282,6 +1470,10 @@ type block struct {
 	b                []byte
 	tombstones       []TimeRange
 
+	// stats are the statistics of the values in b from the index entry of
+	// the block, as long as the block is not re-encoded.
+	stats BlockStats
+
 	// readMin, readMax are the timestamps range of values have been
 	// read and encoded from this block.
 	readMin, readMax int64
@@ -1421,6 +1613,7 @@ RETRY:
 				blk.key = key
 				blk.typ = typ
 				blk.b = b
+				blk.stats = iter.Stats()
 				blk.tombstones = tombstones
 				blk.readMin = math.MaxInt64
 				blk.readMax = math.MinInt64
@@ -1453,6 +1646,7 @@ RETRY:
 					blk.key = key
 					blk.typ = typ
 					blk.b = b
+					blk.stats = iter.Stats()
 					blk.tombstones = tombstones
 					blk.readMin = math.MaxInt64
 					blk.readMax = math.MinInt64
@@ -1543,6 +1737,15 @@ func (k *tsmKeyIterator) Read() ([]byte, int64, int64, []byte, error) {
 	return block.key, block.minTime, block.maxTime, block.b, k.err
 }
 
+// BlockStats returns the statistics of the block returned by Read if it is
+// copied unchanged from a TSM file.
+func (k *tsmKeyIterator) BlockStats() BlockStats {
+	if len(k.merged) == 0 {
+		return BlockStats{}
+	}
+	return k.merged[0].stats
+}
+
 func (k *tsmKeyIterator) Close() error {
 	k.values = nil
 	k.pos = nil
@@ -1588,6 +1791,9 @@ type tsmBatchKeyIterator struct {
 	// size is the maximum number of values to encode in a single block
 	size int
 
+	// duplicates is the policy merging values with the same timestamp.
+	duplicates DuplicatePolicy
+
 	// key is the current key lowest key across all readers that has not be fully exhausted
 	// of values.
 	key []byte
@@ -1614,6 +1820,12 @@ type tsmBatchKeyIterator struct {
 // NewTSMBatchKeyIterator returns a new TSM key iterator from readers.
 // size indicates the maximum number of values to encode in a single block.
 func NewTSMBatchKeyIterator(size int, fast bool, interrupt chan struct{}, readers ...*TSMReader) (KeyIterator, error) {
+	return newTSMBatchKeyIterator(size, fast, DuplicateLast, interrupt, readers...), nil
+}
+
+// newTSMBatchKeyIterator returns a new TSM key iterator from readers merging values
+// with the same timestamp using duplicates.
+func newTSMBatchKeyIterator(size int, fast bool, duplicates DuplicatePolicy, interrupt chan struct{}, readers ...*TSMReader) *tsmBatchKeyIterator {
 	var iter []*BlockIterator
 	for _, r := range readers {
 		iter = append(iter, r.BlockIterator())
@@ -1624,6 +1836,7 @@ func NewTSMBatchKeyIterator(size int, fast bool, interrupt chan struct{}, reader
 		values:               map[string][]Value{},
 		pos:                  make([]int, len(readers)),
 		size:                 size,
+		duplicates:           duplicates,
 		iterators:            iter,
 		fast:                 fast,
 		buf:                  make([]blocks, len(iter)),
@@ -1633,7 +1846,7 @@ func NewTSMBatchKeyIterator(size int, fast bool, interrupt chan struct{}, reader
 		mergedBooleanValues:  &tsdb.BooleanArray{},
 		mergedStringValues:   &tsdb.StringArray{},
 		interrupt:            interrupt,
-	}, nil
+	}
 }
 
 func (k *tsmBatchKeyIterator) hasMergedValues() bool {
@@ -1713,6 +1926,7 @@ RETRY:
 			blk.key = key
 			blk.typ = typ
 			blk.b = b
+			blk.stats = iter.Stats()
 			blk.tombstones = tombstones
 			blk.readMin = math.MaxInt64
 			blk.readMax = math.MinInt64
@@ -1745,6 +1959,7 @@ RETRY:
 				blk.key = key
 				blk.typ = typ
 				blk.b = b
+				blk.stats = iter.Stats()
 				blk.tombstones = tombstones
 				blk.readMin = math.MaxInt64
 				blk.readMax = math.MinInt64
@@ -1834,6 +2049,15 @@ func (k *tsmBatchKeyIterator) Read() ([]byte, int64, int64, []byte, error) {
 	return block.key, block.minTime, block.maxTime, block.b, k.err
 }
 
+// BlockStats returns the statistics of the block returned by Read if it is
+// copied unchanged from a TSM file.
+func (k *tsmBatchKeyIterator) BlockStats() BlockStats {
+	if len(k.merged) == 0 {
+		return BlockStats{}
+	}
+	return k.merged[0].stats
+}
+
 func (k *tsmBatchKeyIterator) Close() error {
 	k.values = nil
 	k.pos = nil
@@ -1867,6 +2091,7 @@ type cacheBlock struct {
 	k                []byte
 	minTime, maxTime int64
 	b                []byte
+	stats            BlockStats
 	err              error
 }
 
@@ -1961,6 +2186,7 @@ func (c *cacheKeyIterator) encode() {
 						b, err = Values(values[:end]).Encode(nil)
 					}
 
+					stats := valuesStats(values[:end])
 					values = values[end:]
 
 					c.blocks[i] = append(c.blocks[i], cacheBlock{
@@ -1968,6 +2194,7 @@ func (c *cacheKeyIterator) encode() {
 						minTime: minTime,
 						maxTime: maxTime,
 						b:       b,
+						stats:   stats,
 						err:     err,
 					})
 
@@ -2012,6 +2239,12 @@ func (c *cacheKeyIterator) Read() ([]byte, int64, int64, []byte, error) {
 	return blk.k, blk.minTime, blk.maxTime, blk.b, blk.err
 }
 
+// BlockStats returns the statistics of the values of the block returned by
+// Read, which are computed when the block is encoded.
+func (c *cacheKeyIterator) BlockStats() BlockStats {
+	return c.blocks[c.i][0].stats
+}
+
 func (c *cacheKeyIterator) Close() error {
 	return nil
 }
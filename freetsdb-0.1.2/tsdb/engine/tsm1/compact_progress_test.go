@@ -0,0 +1,58 @@
+package tsm1
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCompactor_Progress(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+
+	r1 := mustDuplicatesTSMReader(t, dir, []Value{NewValue(1, 1.0), NewValue(2, 2.0)})
+	r2 := mustDuplicatesTSMReader(t, dir, []Value{NewValue(3, 3.0)})
+	defer r1.Close()
+	defer r2.Close()
+
+	c := NewCompactor()
+	c.Open()
+	defer c.Close()
+
+	files := []string{r1.Path(), r2.Path()}
+	p := c.track(files, []*TSMReader{r1, r2})
+	if got := c.progress(files); got != p {
+		t.Fatalf("progress mismatch: got %v, exp %v", got, p)
+	}
+	if got, exp := p.bytesIn, int64(r1.Size()+r2.Size()); got != exp {
+		t.Fatalf("bytes in mismatch: got %v, exp %v", got, exp)
+	} else if got := p.percent(); got != 0 {
+		t.Fatalf("percent mismatch: got %v, exp %v", got, 0)
+	}
+
+	iter := &progressKeyIterator{
+		KeyIterator: newTSMBatchKeyIterator(1000, false, DuplicateLast, nil, r1, r2),
+		progress:    p,
+	}
+
+	var written int64
+	for iter.Next() {
+		_, _, _, block, err := iter.Read()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		written += int64(len(block))
+	}
+
+	if got := p.written(); got != written {
+		t.Fatalf("bytes out mismatch: got %v, exp %v", got, written)
+	} else if got := p.percent(); got != 100 {
+		t.Fatalf("percent mismatch: got %v, exp %v", got, 100)
+	}
+
+	c.untrack(files)
+	if got := c.progress(files); got != nil {
+		t.Fatalf("expected compaction to be untracked, got %v", got)
+	} else if got := p.percent(); got != 0 {
+		t.Fatalf("percent mismatch after untrack: got %v, exp %v", got, 0)
+	}
+}
@@ -2325,6 +2325,53 @@ func TestDefaultPlanner_PlanOptimize_Tombstones(t *testing.T) {
 
 }
 
+// Ensure that a forced optimize plans level 4 generations too few to be worth
+// optimizing once.
+func TestDefaultPlanner_PlanOptimize_Forced(t *testing.T) {
+	data := []tsm1.FileStat{
+		{
+			Path: "01-04.tsm1",
+			Size: 251 * 1024 * 1024,
+		},
+		{
+			Path: "02-04.tsm1",
+			Size: 1 * 1024 * 1024,
+		},
+	}
+
+	cp := tsm1.NewDefaultPlanner(
+		&fakeFileStore{
+			PathsFn: func() []tsm1.FileStat {
+				return data
+			},
+		}, tsdb.DefaultCompactFullWriteColdDuration,
+	)
+
+	if tsm := cp.PlanOptimize(); len(tsm) != 0 {
+		t.Fatalf("tsm file length mismatch: got %v, exp %v", len(tsm), 0)
+	}
+
+	cp.ForceOptimize()
+	tsm := cp.PlanOptimize()
+	if exp, got := 1, len(tsm); got != exp {
+		t.Fatalf("group length mismatch: got %v, exp %v", got, exp)
+	}
+	if exp, got := len(data), len(tsm[0]); got != exp {
+		t.Fatalf("tsm file length mismatch: got %v, exp %v", got, exp)
+	}
+	for i, p := range tsm[0] {
+		if got, exp := p, data[i].Path; got != exp {
+			t.Fatalf("tsm file mismatch: got %v, exp %v", got, exp)
+		}
+	}
+	cp.Release(tsm)
+
+	// The request is reset once planned.
+	if tsm := cp.PlanOptimize(); len(tsm) != 0 {
+		t.Fatalf("tsm file length mismatch: got %v, exp %v", len(tsm), 0)
+	}
+}
+
 // Ensure that the planner will compact all files if no writes
 // have happened in some interval
 func TestDefaultPlanner_Plan_FullOnCold(t *testing.T) {
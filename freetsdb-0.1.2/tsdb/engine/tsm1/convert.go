@@ -0,0 +1,156 @@
+package tsm1
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/freetsdb/freetsdb/models"
+	"github.com/freetsdb/freetsdb/services/influxql"
+	"github.com/freetsdb/freetsdb/tsdb"
+)
+
+// fieldConverter converts the values of a field of a measurement to another
+// numeric type.  Values are converted like the casting cursors of queries do,
+// except that values the new type cannot represent are rejected.
+type fieldConverter struct {
+	name  []byte // escaped measurement name
+	field []byte
+	typ   influxql.DataType
+}
+
+// newFieldConverter returns a converter of the values of field of measurement
+// to typ.
+func newFieldConverter(measurement, field []byte, typ influxql.DataType) *fieldConverter {
+	return &fieldConverter{
+		name:  models.EscapeMeasurement(measurement),
+		field: field,
+		typ:   typ,
+	}
+}
+
+// matches returns true if key is a composite key of the converted field.
+func (c *fieldConverter) matches(key []byte) bool {
+	sep := len(c.name)
+	if len(key) <= sep || !bytes.HasPrefix(key, c.name) || (key[sep] != ',' && key[sep] != keyFieldSeparator[0]) {
+		return false
+	}
+	_, field := SeriesAndFieldFromCompositeKey(key)
+	return bytes.Equal(field, c.field)
+}
+
+// contains returns true if f holds values of the converted field.
+func (c *fieldConverter) contains(f TSMFile) bool {
+	for i, n := f.Seek(c.name), f.KeyCount(); i < n; i++ {
+		key, _ := f.KeyAt(i)
+		if !bytes.HasPrefix(key, c.name) {
+			return false
+		} else if c.matches(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// convertBlock returns block with its values converted, or block itself if
+// they already have the type.
+func (c *fieldConverter) convertBlock(block []byte) ([]byte, error) {
+	values, err := DecodeBlock(block, nil)
+	if err != nil {
+		return nil, err
+	} else if typ, err := Values(values).InfluxQLType(); err != nil || typ == c.typ {
+		return block, err
+	}
+
+	for i, v := range values {
+		if values[i], err = convertValue(v, c.typ); err == tsdb.ErrFieldValueOutOfRange {
+			return nil, fmt.Errorf("%w: cannot convert %v at %d of field %q to %s", err, v.Value(), v.UnixNano(), c.field, c.typ)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return Values(values).Encode(nil)
+}
+
+// convertValue returns v converted to typ.  Floats are truncated.  It returns
+// tsdb.ErrFieldValueOutOfRange for NaN, infinite and negative values and values
+// too large for typ, which would otherwise wrap around.
+func convertValue(v Value, typ influxql.DataType) (Value, error) {
+	t := v.UnixNano()
+	switch v := v.(type) {
+	case FloatValue:
+		switch typ {
+		case influxql.Float:
+			return v, nil
+		case influxql.Integer:
+			// NaN fails both comparisons.
+			if !(v.value >= math.MinInt64 && v.value < -math.MinInt64) {
+				return nil, tsdb.ErrFieldValueOutOfRange
+			}
+			return NewIntegerValue(t, int64(v.value)), nil
+		case influxql.Unsigned:
+			if !(v.value > -1 && v.value < 1<<64) {
+				return nil, tsdb.ErrFieldValueOutOfRange
+			}
+			return NewUnsignedValue(t, uint64(v.value)), nil
+		}
+	case IntegerValue:
+		switch typ {
+		case influxql.Float:
+			return NewFloatValue(t, float64(v.value)), nil
+		case influxql.Integer:
+			return v, nil
+		case influxql.Unsigned:
+			if v.value < 0 {
+				return nil, tsdb.ErrFieldValueOutOfRange
+			}
+			return NewUnsignedValue(t, uint64(v.value)), nil
+		}
+	case UnsignedValue:
+		switch typ {
+		case influxql.Float:
+			return NewFloatValue(t, float64(v.value)), nil
+		case influxql.Integer:
+			if v.value > math.MaxInt64 {
+				return nil, tsdb.ErrFieldValueOutOfRange
+			}
+			return NewIntegerValue(t, int64(v.value)), nil
+		case influxql.Unsigned:
+			return v, nil
+		}
+	}
+	return nil, tsdb.ErrFieldTypeConversion
+}
+
+// convertKeyIterator converts the blocks of the converted field read from a
+// KeyIterator.
+type convertKeyIterator struct {
+	KeyIterator
+	conv *fieldConverter
+
+	// converted is set if the block last read was converted.
+	converted bool
+}
+
+// Read returns the next block, converted if it holds values of the field.
+func (k *convertKeyIterator) Read() ([]byte, int64, int64, []byte, error) {
+	key, minTime, maxTime, block, err := k.KeyIterator.Read()
+	k.converted = false
+	if err != nil || !k.conv.matches(key) {
+		return key, minTime, maxTime, block, err
+	}
+
+	k.converted = true
+
+	block, err = k.conv.convertBlock(block)
+	return key, minTime, maxTime, block, err
+}
+
+// BlockStats returns the statistics of the block returned by Read, or no
+// statistics if the block was converted.
+func (k *convertKeyIterator) BlockStats() BlockStats {
+	if k.converted {
+		return BlockStats{}
+	}
+	return k.KeyIterator.BlockStats()
+}
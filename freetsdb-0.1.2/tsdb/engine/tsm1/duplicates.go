@@ -0,0 +1,174 @@
+package tsm1
+
+// DuplicatePolicy determines how values of a key with the same timestamp are
+// merged by the cache, by compactions and by queries.  Values are always merged in the
+// order they were written: values in the cache were written after values in
+// TSM files, and values in newer TSM files after values in older ones.
+type DuplicatePolicy int32
+
+const (
+	// DuplicateLast keeps the value written last.
+	DuplicateLast DuplicatePolicy = iota
+
+	// DuplicateFirst keeps the value written first.
+	DuplicateFirst
+
+	// DuplicateMax keeps the greatest value.
+	DuplicateMax
+
+	// DuplicateKeep keeps values written later by moving them to the next
+	// free nanosecond after their timestamp.  Values are never dropped, and
+	// the first value written at a timestamp is never moved.
+	DuplicateKeep
+)
+
+// ParseDuplicatePolicy returns the policy with the given name.  An empty or
+// unknown name returns DuplicateLast.
+func ParseDuplicatePolicy(name string) DuplicatePolicy {
+	switch name {
+	case "first":
+		return DuplicateFirst
+	case "max":
+		return DuplicateMax
+	case "keep":
+		return DuplicateKeep
+	}
+	return DuplicateLast
+}
+
+// String returns the name of the policy.
+func (p DuplicatePolicy) String() string {
+	switch p {
+	case DuplicateFirst:
+		return "first"
+	case DuplicateMax:
+		return "max"
+	case DuplicateKeep:
+		return "keep"
+	}
+	return "last"
+}
+
+// valueGreater returns true if the value of a is greater than the value of b.
+// Both values must be of the same type.  True is greater than false.
+func valueGreater(a, b Value) bool {
+	switch a := a.(type) {
+	case FloatValue:
+		return a.value > b.(FloatValue).value
+	case IntegerValue:
+		return a.value > b.(IntegerValue).value
+	case UnsignedValue:
+		return a.value > b.(UnsignedValue).value
+	case StringValue:
+		return a.value > b.(StringValue).value
+	case BooleanValue:
+		return a.value && !b.(BooleanValue).value
+	}
+	return false
+}
+
+// cacheValueWins returns true if the cache value replaces the TSM value with
+// the same timestamp under policy.  Values in the cache were written after the
+// values in TSM files.  DuplicateKeep keeps the TSM value at its timestamp.
+func cacheValueWins(policy DuplicatePolicy, cache, tsm Value) bool {
+	switch policy {
+	case DuplicateFirst, DuplicateKeep:
+		return false
+	case DuplicateMax:
+		return !valueGreater(tsm, cache)
+	}
+	return true
+}
+
+// keepCacheValues returns the cache values of the key of c moved the way
+// DuplicateKeep moves them when the cache is merged with the TSM files, so
+// cursors merging them never see the same timestamp in both.  Cursors read
+// the values in either direction, and a value can be moved past values that
+// a descending read has returned already, so the values are resolved up front
+// by reading the TSM values from the first cache value on.
+func (c *KeyCursor) keepCacheValues(values Values) Values {
+	if c == nil || c.fs == nil || c.duplicates != DuplicateKeep || len(values) == 0 {
+		return values
+	}
+
+	tc := c.fs.KeyCursor(c.ctx, c.key, values[0].UnixNano(), true)
+	defer tc.Close()
+
+	var tsm Values
+	for {
+		n := len(tsm)
+		var err error
+		if tsm, err = tc.appendBlock(tsm, values[0]); err != nil {
+			return values
+		} else if len(tsm) == n {
+			break
+		}
+		tc.Next()
+	}
+	if len(tsm) == 0 {
+		return values
+	}
+
+	// The TSM values were written first and are deduplicated already, so
+	// they keep their timestamps and only cache values are moved.
+	merged := make(Values, 0, len(tsm)+len(values))
+	merged = append(merged, tsm...)
+	merged = append(merged, values...)
+	merged = merged.DeduplicateWith(DuplicateKeep)
+
+	out := make(Values, 0, len(values))
+	var j int
+	for _, v := range merged {
+		for j < len(tsm) && tsm[j].UnixNano() < v.UnixNano() {
+			j++
+		}
+		if j < len(tsm) && tsm[j].UnixNano() == v.UnixNano() {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// appendBlock appends the values of the current block of c to dst.  typ is a
+// value of the type of the key.
+func (c *KeyCursor) appendBlock(dst Values, typ Value) (Values, error) {
+	switch typ.(type) {
+	case FloatValue:
+		values, err := c.ReadFloatBlock(&[]FloatValue{})
+		for _, v := range values {
+			dst = append(dst, v)
+		}
+		return dst, err
+	case IntegerValue:
+		values, err := c.ReadIntegerBlock(&[]IntegerValue{})
+		for _, v := range values {
+			dst = append(dst, v)
+		}
+		return dst, err
+	case UnsignedValue:
+		values, err := c.ReadUnsignedBlock(&[]UnsignedValue{})
+		for _, v := range values {
+			dst = append(dst, v)
+		}
+		return dst, err
+	case StringValue:
+		values, err := c.ReadStringBlock(&[]StringValue{})
+		for _, v := range values {
+			dst = append(dst, v)
+		}
+		return dst, err
+	case BooleanValue:
+		values, err := c.ReadBooleanBlock(&[]BooleanValue{})
+		for _, v := range values {
+			dst = append(dst, v)
+		}
+		return dst, err
+	}
+	return dst, nil
+}
+
+// valueWithTime returns v with its timestamp set to t.
+func valueWithTime(v Value, t int64) Value {
+	return NewValue(t, v.Value())
+}
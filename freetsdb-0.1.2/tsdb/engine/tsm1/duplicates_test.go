@@ -0,0 +1,410 @@
+package tsm1
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/freetsdb/freetsdb/tsdb"
+)
+
+func TestValues_DeduplicateWith(t *testing.T) {
+	tests := []struct {
+		policy DuplicatePolicy
+		exp    Values
+	}{
+		{DuplicateLast, Values{NewValue(1, 3.0), NewValue(4, 4.0)}},
+		{DuplicateFirst, Values{NewValue(1, 1.0), NewValue(4, 4.0)}},
+		{DuplicateMax, Values{NewValue(1, 5.0), NewValue(4, 4.0)}},
+		{DuplicateKeep, Values{NewValue(1, 1.0), NewValue(2, 5.0), NewValue(3, 3.0), NewValue(4, 4.0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			values := Values{NewValue(1, 1.0), NewValue(1, 5.0), NewValue(1, 3.0), NewValue(4, 4.0)}
+			if got := values.DeduplicateWith(tt.policy); !reflect.DeepEqual(got, tt.exp) {
+				t.Fatalf("values mismatch:\ngot %v\nexp %v", got, tt.exp)
+			}
+		})
+	}
+}
+
+// Ensure keep moves a duplicate past points at the next nanoseconds instead of
+// dropping it, and never moves the first value written at a timestamp.
+func TestValues_DeduplicateWith_KeepOccupied(t *testing.T) {
+	tests := []struct {
+		values Values
+		exp    Values
+	}{
+		{
+			values: Values{NewValue(100, 1.0), NewValue(100, 2.0), NewValue(101, 3.0)},
+			exp:    Values{NewValue(100, 1.0), NewValue(101, 3.0), NewValue(102, 2.0)},
+		},
+		{
+			values: Values{NewValue(100, 1.0), NewValue(100, 2.0), NewValue(102, 3.0)},
+			exp:    Values{NewValue(100, 1.0), NewValue(101, 2.0), NewValue(102, 3.0)},
+		},
+		{
+			values: Values{NewValue(100, 1.0), NewValue(100, 2.0), NewValue(100, 3.0), NewValue(102, 4.0)},
+			exp:    Values{NewValue(100, 1.0), NewValue(101, 2.0), NewValue(102, 4.0), NewValue(103, 3.0)},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.values.DeduplicateWith(DuplicateKeep); !reflect.DeepEqual(got, tt.exp) {
+			t.Fatalf("values mismatch:\ngot %v\nexp %v", got, tt.exp)
+		}
+	}
+
+	a := FloatValues{{unixnano: 100, value: 1.0}, {unixnano: 100, value: 2.0}, {unixnano: 101, value: 3.0}}
+	exp := FloatValues{{unixnano: 100, value: 1.0}, {unixnano: 101, value: 3.0}, {unixnano: 102, value: 2.0}}
+	if got := a.DeduplicateWith(DuplicateKeep); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("values mismatch:\ngot %v\nexp %v", got, exp)
+	}
+}
+
+func TestParseDuplicatePolicy(t *testing.T) {
+	for _, p := range []DuplicatePolicy{DuplicateLast, DuplicateFirst, DuplicateMax, DuplicateKeep} {
+		if got := ParseDuplicatePolicy(p.String()); got != p {
+			t.Fatalf("policy mismatch: got %v, exp %v", got, p)
+		}
+	}
+	if got, exp := ParseDuplicatePolicy(""), DuplicateLast; got != exp {
+		t.Fatalf("policy mismatch: got %v, exp %v", got, exp)
+	}
+}
+
+func TestCache_DuplicatePolicy(t *testing.T) {
+	c := NewCache(0)
+	c.SetDuplicatePolicy(DuplicateFirst)
+
+	if err := c.Write([]byte("cpu,host=A#!~#value"), []Value{NewValue(1, 1.0), NewValue(2, 2.0)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Write([]byte("cpu,host=A#!~#value"), []Value{NewValue(1, 3.0)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := Values{NewValue(1, 1.0), NewValue(2, 2.0)}
+	if got := c.Values([]byte("cpu,host=A#!~#value")); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("values mismatch:\ngot %v\nexp %v", got, exp)
+	}
+
+	snapshot, err := c.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	snapshot.Deduplicate()
+	if got := snapshot.Values([]byte("cpu,host=A#!~#value")); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("snapshot values mismatch:\ngot %v\nexp %v", got, exp)
+	}
+}
+
+func TestTSMBatchKeyIterator_DuplicatePolicy(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+
+	// The second file was written after the first.
+	r1 := mustDuplicatesTSMReader(t, dir, []Value{NewValue(1, 1.0), NewValue(2, 2.0)})
+	r2 := mustDuplicatesTSMReader(t, dir, []Value{NewValue(1, 5.0), NewValue(2, 0.5)})
+	defer r1.Close()
+	defer r2.Close()
+
+	tests := []struct {
+		policy DuplicatePolicy
+		exp    []Value
+	}{
+		{DuplicateLast, []Value{NewValue(1, 5.0), NewValue(2, 0.5)}},
+		{DuplicateFirst, []Value{NewValue(1, 1.0), NewValue(2, 2.0)}},
+		{DuplicateMax, []Value{NewValue(1, 5.0), NewValue(2, 2.0)}},
+		{DuplicateKeep, []Value{NewValue(1, 1.0), NewValue(2, 2.0), NewValue(3, 5.0), NewValue(4, 0.5)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			if got := mustCompactDuplicates(t, tt.policy, r1, r2); !reflect.DeepEqual(got, tt.exp) {
+				t.Fatalf("values mismatch:\ngot %v\nexp %v", got, tt.exp)
+			}
+		})
+	}
+}
+
+// Ensure overlapping blocks of TSM files are merged with the duplicate policy
+// when they are read.
+func TestKeyCursor_DuplicatePolicy(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+
+	// The second file was written after the first.
+	fs := mustDuplicatesFileStore(t, dir,
+		[]Value{NewValue(1, 1.0), NewValue(2, 2.0)},
+		[]Value{NewValue(1, 5.0), NewValue(2, 0.5)},
+	)
+	defer fs.Close()
+
+	for _, tt := range duplicatePolicyTests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			fs.SetDuplicatePolicy(tt.policy)
+
+			for _, ascending := range []bool{true, false} {
+				seek := int64(0)
+				if !ascending {
+					seek = 10
+				}
+
+				c := fs.KeyCursor(context.Background(), []byte("cpu,host=A#!~#value"), seek, ascending)
+				var buf []FloatValue
+				values, err := c.ReadFloatBlock(&buf)
+				c.Close()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				got := make([]Value, 0, len(values))
+				for _, v := range values {
+					got = append(got, v)
+				}
+				if !reflect.DeepEqual(got, tt.exp) {
+					t.Fatalf("values mismatch (ascending=%v):\ngot %v\nexp %v", ascending, got, tt.exp)
+				}
+
+				c = fs.KeyCursor(context.Background(), []byte("cpu,host=A#!~#value"), seek, ascending)
+				a, err := c.ReadFloatArrayBlock(tsdb.NewFloatArrayLen(0))
+				c.Close()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got := arrayValues(a); !reflect.DeepEqual(got, tt.exp) {
+					t.Fatalf("array values mismatch (ascending=%v):\ngot %v\nexp %v", ascending, got, tt.exp)
+				}
+			}
+		})
+	}
+}
+
+// Ensure values of the cache are merged with values of TSM files with the
+// duplicate policy by cursors.
+func TestCursor_DuplicatePolicy(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+
+	fs := mustDuplicatesFileStore(t, dir, []Value{NewValue(1, 1.0), NewValue(2, 2.0)})
+	defer fs.Close()
+
+	// The cache values were written after the TSM file.
+	cacheValues := func() Values { return Values{NewValue(1, 5.0), NewValue(2, 0.5)} }
+
+	for _, tt := range duplicatePolicyTests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			fs.SetDuplicatePolicy(tt.policy)
+			checkDuplicatesCursors(t, fs, cacheValues, tt.exp)
+		})
+	}
+}
+
+// Ensure a value written at the timestamp of a TSM value is moved past the
+// next nanosecond under keep when a point is already there, by every cursor
+// and by compactions.
+func TestCursor_DuplicateKeepOccupied(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+
+	fs := mustDuplicatesFileStore(t, dir, []Value{NewValue(100, 1.0), NewValue(101, 3.0)})
+	defer fs.Close()
+	fs.SetDuplicatePolicy(DuplicateKeep)
+
+	exp := []Value{NewValue(100, 1.0), NewValue(101, 3.0), NewValue(102, 2.0)}
+	checkDuplicatesCursors(t, fs, func() Values { return Values{NewValue(100, 2.0)} }, exp)
+
+	r1 := mustDuplicatesTSMReader(t, dir, []Value{NewValue(100, 1.0), NewValue(101, 3.0)})
+	r2 := mustDuplicatesTSMReader(t, dir, []Value{NewValue(100, 2.0)})
+	defer r1.Close()
+	defer r2.Close()
+	if got := mustCompactDuplicates(t, DuplicateKeep, r1, r2); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("compacted values mismatch:\ngot %v\nexp %v", got, exp)
+	}
+}
+
+// Ensure three values written at the same timestamp to a TSM file and twice
+// to the cache are all kept under keep, and are read the same by every cursor
+// as after the cache is compacted.  The cache moved the third value to the
+// next nanosecond already, so the second value is moved past it.
+func TestCursor_DuplicateKeepThreeWrites(t *testing.T) {
+	dir := mustTempDir()
+	defer os.RemoveAll(dir)
+
+	fs := mustDuplicatesFileStore(t, dir, []Value{NewValue(100, 1.0)})
+	defer fs.Close()
+	fs.SetDuplicatePolicy(DuplicateKeep)
+
+	key := []byte("cpu,host=A#!~#value")
+	c := NewCache(0)
+	c.SetDuplicatePolicy(DuplicateKeep)
+	for _, v := range []float64{2.0, 3.0} {
+		if err := c.Write(key, []Value{NewValue(100, v)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	exp := []Value{NewValue(100, 1.0), NewValue(101, 3.0), NewValue(102, 2.0)}
+	checkDuplicatesCursors(t, fs, func() Values { return c.Values(key) }, exp)
+
+	r1 := mustDuplicatesTSMReader(t, dir, []Value{NewValue(100, 1.0)})
+	r2 := mustDuplicatesTSMReader(t, dir, c.Values(key))
+	defer r1.Close()
+	defer r2.Close()
+	if got := mustCompactDuplicates(t, DuplicateKeep, r1, r2); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("compacted values mismatch:\ngot %v\nexp %v", got, exp)
+	}
+}
+
+// checkDuplicatesCursors ensures every cursor merging the values returned by
+// cacheValues with the TSM files of fs reads exp.
+func checkDuplicatesCursors(t *testing.T, fs *FileStore, cacheValues func() Values, exp []Value) {
+	t.Helper()
+	key := []byte("cpu,host=A#!~#value")
+
+	// Cursors used by the query engine.
+	for _, ascending := range []bool{true, false} {
+		seek := int64(0)
+		if !ascending {
+			seek = 1000
+		}
+
+		c := newFloatCursor(seek, ascending, cacheValues(), fs.KeyCursor(context.Background(), key, seek, ascending))
+		var got []Value
+		for {
+			ts, v := c.nextFloat()
+			if ts == tsdb.EOF {
+				break
+			}
+			got = append(got, NewValue(ts, v))
+		}
+		c.close()
+
+		if !ascending {
+			reverseValues(got)
+		}
+		if !reflect.DeepEqual(got, exp) {
+			t.Fatalf("values mismatch (ascending=%v):\ngot %v\nexp %v", ascending, got, exp)
+		}
+	}
+
+	// Array cursors used by the storage service and remote reads.
+	asc := newFloatArrayAscendingCursor()
+	asc.reset(0, 1000, cacheValues(), fs.KeyCursor(context.Background(), key, 0, true))
+	got := arrayValues(asc.Next())
+	asc.Close()
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("ascending array values mismatch:\ngot %v\nexp %v", got, exp)
+	}
+
+	desc := newFloatArrayDescendingCursor()
+	desc.reset(1000, 0, cacheValues(), fs.KeyCursor(context.Background(), key, 1000, false))
+	got = arrayValues(desc.Next())
+	desc.Close()
+	reverseValues(got)
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("descending array values mismatch:\ngot %v\nexp %v", got, exp)
+	}
+}
+
+// duplicatePolicyTests are the values read from {1: 1.0, 2: 2.0} followed by
+// {1: 5.0, 2: 0.5} with each policy.
+var duplicatePolicyTests = []struct {
+	policy DuplicatePolicy
+	exp    []Value
+}{
+	{DuplicateLast, []Value{NewValue(1, 5.0), NewValue(2, 0.5)}},
+	{DuplicateFirst, []Value{NewValue(1, 1.0), NewValue(2, 2.0)}},
+	{DuplicateMax, []Value{NewValue(1, 5.0), NewValue(2, 2.0)}},
+	{DuplicateKeep, []Value{NewValue(1, 1.0), NewValue(2, 2.0), NewValue(3, 5.0), NewValue(4, 0.5)}},
+}
+
+// mustCompactDuplicates returns the values of readers merged by a compaction
+// with policy.
+func mustCompactDuplicates(t *testing.T, policy DuplicatePolicy, readers ...*TSMReader) []Value {
+	t.Helper()
+	iter := newTSMBatchKeyIterator(1000, false, policy, nil, readers...)
+
+	var got []Value
+	for iter.Next() {
+		key, _, _, block, err := iter.Read()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		} else if string(key) != "cpu,host=A#!~#value" {
+			t.Fatalf("key mismatch: got %s", key)
+		}
+
+		values, err := DecodeBlock(block, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, values...)
+	}
+	if err := iter.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return got
+}
+
+func arrayValues(a *tsdb.FloatArray) []Value {
+	values := make([]Value, 0, a.Len())
+	for i, ts := range a.Timestamps {
+		values = append(values, NewValue(ts, a.Values[i]))
+	}
+	return values
+}
+
+func reverseValues(values []Value) {
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+}
+
+// mustDuplicatesFileStore returns an open FileStore with a TSM file for each
+// set of values, in generation order.
+func mustDuplicatesFileStore(t *testing.T, dir string, values ...[]Value) *FileStore {
+	for i, v := range values {
+		r := mustDuplicatesTSMReader(t, dir, v)
+		path := r.Path()
+		r.Close()
+		if err := os.Rename(path, filepath.Join(dir, DefaultFormatFileName(i+1, 1)+"."+TSMFileExtension)); err != nil {
+			t.Fatalf("unexpected error renaming: %v", err)
+		}
+	}
+
+	fs := NewFileStore(dir)
+	if err := fs.Open(); err != nil {
+		t.Fatalf("unexpected error opening file store: %v", err)
+	}
+	return fs
+}
+
+func mustDuplicatesTSMReader(t *testing.T, dir string, values []Value) *TSMReader {
+	f := mustTempFile(dir)
+	w, err := NewTSMWriter(f)
+	if err != nil {
+		t.Fatalf("unexpected error creating writer: %v", err)
+	}
+	if err := w.Write([]byte("cpu,host=A#!~#value"), values); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatalf("unexpected error writing index: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	f, err = os.Open(f.Name())
+	if err != nil {
+		t.Fatalf("unexpected error opening: %v", err)
+	}
+	r, err := NewTSMReader(f)
+	if err != nil {
+		t.Fatalf("unexpected error creating reader: %v", err)
+	}
+	return r
+}
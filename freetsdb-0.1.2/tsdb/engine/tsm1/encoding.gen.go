@@ -89,6 +89,68 @@ func (a Values) Deduplicate() Values {
 	return a[:i+1]
 }
 
+// DeduplicateWith returns a new slice with any values that have the same timestamp
+// merged with policy, where values that appear later in the slice were written later.
+// The returned Values are sorted if necessary.
+func (a Values) DeduplicateWith(policy DuplicatePolicy) Values {
+	if policy == DuplicateLast || a.ordered() {
+		return a.Deduplicate()
+	}
+
+	sort.Stable(a)
+	if policy == DuplicateKeep {
+		return a.deduplicateKeep()
+	}
+
+	var i int
+	for j := 1; j < len(a); j++ {
+		v := a[j]
+		if v.UnixNano() > a[i].UnixNano() {
+			i++
+			a[i] = v
+			continue
+		}
+
+		if policy == DuplicateMax && valueGreater(v, a[i]) {
+			a[i] = v
+		}
+	}
+	return a[:i+1]
+}
+
+// deduplicateKeep moves the values of a sorted slice written at a timestamp
+// that is already taken to the next free nanoseconds, in the order they were
+// written.  Values are never dropped and the first value written at a
+// timestamp is never moved.
+func (a Values) deduplicateKeep() Values {
+	var pending Values
+	var i int
+	next := a[0].UnixNano()
+	for _, v := range a {
+		t := v.UnixNano()
+
+		// Move the waiting values to the free nanoseconds before t.
+		for len(pending) > 0 && next < t {
+			a[i] = valueWithTime(pending[0], next)
+			pending = pending[1:]
+			i, next = i+1, next+1
+		}
+
+		if t < next {
+			pending = append(pending, v)
+			continue
+		}
+		a[i] = v
+		i, next = i+1, t+1
+	}
+
+	for _, v := range pending {
+		a[i] = valueWithTime(v, next)
+		i, next = i+1, next+1
+	}
+	return a[:i]
+}
+
 // Exclude returns the subset of values not in [min, max].  The values must
 // be deduplicated and sorted before calling Exclude or the results are undefined.
 func (a Values) Exclude(min, max int64) Values {
@@ -225,6 +287,19 @@ func (a Values) Merge(b Values) Values {
 	return append(out, b...)
 }
 
+// MergeWith merges a and b with policy, where the values of b were written after
+// the values of a.  Both a and b must be sorted in ascending order.
+func (a Values) MergeWith(b Values, policy DuplicatePolicy) Values {
+	if policy == DuplicateLast {
+		return a.Merge(b)
+	}
+
+	out := make(Values, 0, len(a)+len(b))
+	out = append(out, a...)
+	out = append(out, b...)
+	return out.DeduplicateWith(policy)
+}
+
 // Sort methods
 func (a Values) Len() int           { return len(a) }
 func (a Values) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
@@ -315,6 +390,68 @@ func (a FloatValues) Deduplicate() FloatValues {
 	return a[:i+1]
 }
 
+// DeduplicateWith returns a new slice with any values that have the same timestamp
+// merged with policy, where values that appear later in the slice were written later.
+// The returned Values are sorted if necessary.
+func (a FloatValues) DeduplicateWith(policy DuplicatePolicy) FloatValues {
+	if policy == DuplicateLast || a.ordered() {
+		return a.Deduplicate()
+	}
+
+	sort.Stable(a)
+	if policy == DuplicateKeep {
+		return a.deduplicateKeep()
+	}
+
+	var i int
+	for j := 1; j < len(a); j++ {
+		v := a[j]
+		if v.UnixNano() > a[i].UnixNano() {
+			i++
+			a[i] = v
+			continue
+		}
+
+		if policy == DuplicateMax && valueGreater(v, a[i]) {
+			a[i] = v
+		}
+	}
+	return a[:i+1]
+}
+
+// deduplicateKeep moves the values of a sorted slice written at a timestamp
+// that is already taken to the next free nanoseconds, in the order they were
+// written.  Values are never dropped and the first value written at a
+// timestamp is never moved.
+func (a FloatValues) deduplicateKeep() FloatValues {
+	var pending FloatValues
+	var i int
+	next := a[0].UnixNano()
+	for _, v := range a {
+		t := v.UnixNano()
+
+		// Move the waiting values to the free nanoseconds before t.
+		for len(pending) > 0 && next < t {
+			a[i] = FloatValue{unixnano: next, value: pending[0].value}
+			pending = pending[1:]
+			i, next = i+1, next+1
+		}
+
+		if t < next {
+			pending = append(pending, v)
+			continue
+		}
+		a[i] = v
+		i, next = i+1, t+1
+	}
+
+	for _, v := range pending {
+		a[i] = FloatValue{unixnano: next, value: v.value}
+		i, next = i+1, next+1
+	}
+	return a[:i]
+}
+
 // Exclude returns the subset of values not in [min, max].  The values must
 // be deduplicated and sorted before calling Exclude or the results are undefined.
 func (a FloatValues) Exclude(min, max int64) FloatValues {
@@ -451,6 +588,19 @@ func (a FloatValues) Merge(b FloatValues) FloatValues {
 	return append(out, b...)
 }
 
+// MergeWith merges a and b with policy, where the values of b were written after
+// the values of a.  Both a and b must be sorted in ascending order.
+func (a FloatValues) MergeWith(b FloatValues, policy DuplicatePolicy) FloatValues {
+	if policy == DuplicateLast {
+		return a.Merge(b)
+	}
+
+	out := make(FloatValues, 0, len(a)+len(b))
+	out = append(out, a...)
+	out = append(out, b...)
+	return out.DeduplicateWith(policy)
+}
+
 func (a FloatValues) Encode(buf []byte) ([]byte, error) {
 	return encodeFloatValuesBlock(buf, a)
 }
@@ -608,6 +758,68 @@ func (a IntegerValues) Deduplicate() IntegerValues {
 	return a[:i+1]
 }
 
+// DeduplicateWith returns a new slice with any values that have the same timestamp
+// merged with policy, where values that appear later in the slice were written later.
+// The returned Values are sorted if necessary.
+func (a IntegerValues) DeduplicateWith(policy DuplicatePolicy) IntegerValues {
+	if policy == DuplicateLast || a.ordered() {
+		return a.Deduplicate()
+	}
+
+	sort.Stable(a)
+	if policy == DuplicateKeep {
+		return a.deduplicateKeep()
+	}
+
+	var i int
+	for j := 1; j < len(a); j++ {
+		v := a[j]
+		if v.UnixNano() > a[i].UnixNano() {
+			i++
+			a[i] = v
+			continue
+		}
+
+		if policy == DuplicateMax && valueGreater(v, a[i]) {
+			a[i] = v
+		}
+	}
+	return a[:i+1]
+}
+
+// deduplicateKeep moves the values of a sorted slice written at a timestamp
+// that is already taken to the next free nanoseconds, in the order they were
+// written.  Values are never dropped and the first value written at a
+// timestamp is never moved.
+func (a IntegerValues) deduplicateKeep() IntegerValues {
+	var pending IntegerValues
+	var i int
+	next := a[0].UnixNano()
+	for _, v := range a {
+		t := v.UnixNano()
+
+		// Move the waiting values to the free nanoseconds before t.
+		for len(pending) > 0 && next < t {
+			a[i] = IntegerValue{unixnano: next, value: pending[0].value}
+			pending = pending[1:]
+			i, next = i+1, next+1
+		}
+
+		if t < next {
+			pending = append(pending, v)
+			continue
+		}
+		a[i] = v
+		i, next = i+1, t+1
+	}
+
+	for _, v := range pending {
+		a[i] = IntegerValue{unixnano: next, value: v.value}
+		i, next = i+1, next+1
+	}
+	return a[:i]
+}
+
 // Exclude returns the subset of values not in [min, max].  The values must
 // be deduplicated and sorted before calling Exclude or the results are undefined.
 func (a IntegerValues) Exclude(min, max int64) IntegerValues {
@@ -744,6 +956,19 @@ func (a IntegerValues) Merge(b IntegerValues) IntegerValues {
 	return append(out, b...)
 }
 
+// MergeWith merges a and b with policy, where the values of b were written after
+// the values of a.  Both a and b must be sorted in ascending order.
+func (a IntegerValues) MergeWith(b IntegerValues, policy DuplicatePolicy) IntegerValues {
+	if policy == DuplicateLast {
+		return a.Merge(b)
+	}
+
+	out := make(IntegerValues, 0, len(a)+len(b))
+	out = append(out, a...)
+	out = append(out, b...)
+	return out.DeduplicateWith(policy)
+}
+
 func (a IntegerValues) Encode(buf []byte) ([]byte, error) {
 	return encodeIntegerValuesBlock(buf, a)
 }
@@ -901,6 +1126,68 @@ func (a UnsignedValues) Deduplicate() UnsignedValues {
 	return a[:i+1]
 }
 
+// DeduplicateWith returns a new slice with any values that have the same timestamp
+// merged with policy, where values that appear later in the slice were written later.
+// The returned Values are sorted if necessary.
+func (a UnsignedValues) DeduplicateWith(policy DuplicatePolicy) UnsignedValues {
+	if policy == DuplicateLast || a.ordered() {
+		return a.Deduplicate()
+	}
+
+	sort.Stable(a)
+	if policy == DuplicateKeep {
+		return a.deduplicateKeep()
+	}
+
+	var i int
+	for j := 1; j < len(a); j++ {
+		v := a[j]
+		if v.UnixNano() > a[i].UnixNano() {
+			i++
+			a[i] = v
+			continue
+		}
+
+		if policy == DuplicateMax && valueGreater(v, a[i]) {
+			a[i] = v
+		}
+	}
+	return a[:i+1]
+}
+
+// deduplicateKeep moves the values of a sorted slice written at a timestamp
+// that is already taken to the next free nanoseconds, in the order they were
+// written.  Values are never dropped and the first value written at a
+// timestamp is never moved.
+func (a UnsignedValues) deduplicateKeep() UnsignedValues {
+	var pending UnsignedValues
+	var i int
+	next := a[0].UnixNano()
+	for _, v := range a {
+		t := v.UnixNano()
+
+		// Move the waiting values to the free nanoseconds before t.
+		for len(pending) > 0 && next < t {
+			a[i] = UnsignedValue{unixnano: next, value: pending[0].value}
+			pending = pending[1:]
+			i, next = i+1, next+1
+		}
+
+		if t < next {
+			pending = append(pending, v)
+			continue
+		}
+		a[i] = v
+		i, next = i+1, t+1
+	}
+
+	for _, v := range pending {
+		a[i] = UnsignedValue{unixnano: next, value: v.value}
+		i, next = i+1, next+1
+	}
+	return a[:i]
+}
+
 // Exclude returns the subset of values not in [min, max].  The values must
 // be deduplicated and sorted before calling Exclude or the results are undefined.
 func (a UnsignedValues) Exclude(min, max int64) UnsignedValues {
@@ -1037,6 +1324,19 @@ func (a UnsignedValues) Merge(b UnsignedValues) UnsignedValues {
 	return append(out, b...)
 }
 
+// MergeWith merges a and b with policy, where the values of b were written after
+// the values of a.  Both a and b must be sorted in ascending order.
+func (a UnsignedValues) MergeWith(b UnsignedValues, policy DuplicatePolicy) UnsignedValues {
+	if policy == DuplicateLast {
+		return a.Merge(b)
+	}
+
+	out := make(UnsignedValues, 0, len(a)+len(b))
+	out = append(out, a...)
+	out = append(out, b...)
+	return out.DeduplicateWith(policy)
+}
+
 func (a UnsignedValues) Encode(buf []byte) ([]byte, error) {
 	return encodeUnsignedValuesBlock(buf, a)
 }
@@ -1194,6 +1494,68 @@ func (a StringValues) Deduplicate() StringValues {
 	return a[:i+1]
 }
 
+// DeduplicateWith returns a new slice with any values that have the same timestamp
+// merged with policy, where values that appear later in the slice were written later.
+// The returned Values are sorted if necessary.
+func (a StringValues) DeduplicateWith(policy DuplicatePolicy) StringValues {
+	if policy == DuplicateLast || a.ordered() {
+		return a.Deduplicate()
+	}
+
+	sort.Stable(a)
+	if policy == DuplicateKeep {
+		return a.deduplicateKeep()
+	}
+
+	var i int
+	for j := 1; j < len(a); j++ {
+		v := a[j]
+		if v.UnixNano() > a[i].UnixNano() {
+			i++
+			a[i] = v
+			continue
+		}
+
+		if policy == DuplicateMax && valueGreater(v, a[i]) {
+			a[i] = v
+		}
+	}
+	return a[:i+1]
+}
+
+// deduplicateKeep moves the values of a sorted slice written at a timestamp
+// that is already taken to the next free nanoseconds, in the order they were
+// written.  Values are never dropped and the first value written at a
+// timestamp is never moved.
+func (a StringValues) deduplicateKeep() StringValues {
+	var pending StringValues
+	var i int
+	next := a[0].UnixNano()
+	for _, v := range a {
+		t := v.UnixNano()
+
+		// Move the waiting values to the free nanoseconds before t.
+		for len(pending) > 0 && next < t {
+			a[i] = StringValue{unixnano: next, value: pending[0].value}
+			pending = pending[1:]
+			i, next = i+1, next+1
+		}
+
+		if t < next {
+			pending = append(pending, v)
+			continue
+		}
+		a[i] = v
+		i, next = i+1, t+1
+	}
+
+	for _, v := range pending {
+		a[i] = StringValue{unixnano: next, value: v.value}
+		i, next = i+1, next+1
+	}
+	return a[:i]
+}
+
 // Exclude returns the subset of values not in [min, max].  The values must
 // be deduplicated and sorted before calling Exclude or the results are undefined.
 func (a StringValues) Exclude(min, max int64) StringValues {
@@ -1330,6 +1692,19 @@ func (a StringValues) Merge(b StringValues) StringValues {
 	return append(out, b...)
 }
 
+// MergeWith merges a and b with policy, where the values of b were written after
+// the values of a.  Both a and b must be sorted in ascending order.
+func (a StringValues) MergeWith(b StringValues, policy DuplicatePolicy) StringValues {
+	if policy == DuplicateLast {
+		return a.Merge(b)
+	}
+
+	out := make(StringValues, 0, len(a)+len(b))
+	out = append(out, a...)
+	out = append(out, b...)
+	return out.DeduplicateWith(policy)
+}
+
 func (a StringValues) Encode(buf []byte) ([]byte, error) {
 	return encodeStringValuesBlock(buf, a)
 }
@@ -1487,6 +1862,68 @@ func (a BooleanValues) Deduplicate() BooleanValues {
 	return a[:i+1]
 }
 
+// DeduplicateWith returns a new slice with any values that have the same timestamp
+// merged with policy, where values that appear later in the slice were written later.
+// The returned Values are sorted if necessary.
+func (a BooleanValues) DeduplicateWith(policy DuplicatePolicy) BooleanValues {
+	if policy == DuplicateLast || a.ordered() {
+		return a.Deduplicate()
+	}
+
+	sort.Stable(a)
+	if policy == DuplicateKeep {
+		return a.deduplicateKeep()
+	}
+
+	var i int
+	for j := 1; j < len(a); j++ {
+		v := a[j]
+		if v.UnixNano() > a[i].UnixNano() {
+			i++
+			a[i] = v
+			continue
+		}
+
+		if policy == DuplicateMax && valueGreater(v, a[i]) {
+			a[i] = v
+		}
+	}
+	return a[:i+1]
+}
+
+// deduplicateKeep moves the values of a sorted slice written at a timestamp
+// that is already taken to the next free nanoseconds, in the order they were
+// written.  Values are never dropped and the first value written at a
+// timestamp is never moved.
+func (a BooleanValues) deduplicateKeep() BooleanValues {
+	var pending BooleanValues
+	var i int
+	next := a[0].UnixNano()
+	for _, v := range a {
+		t := v.UnixNano()
+
+		// Move the waiting values to the free nanoseconds before t.
+		for len(pending) > 0 && next < t {
+			a[i] = BooleanValue{unixnano: next, value: pending[0].value}
+			pending = pending[1:]
+			i, next = i+1, next+1
+		}
+
+		if t < next {
+			pending = append(pending, v)
+			continue
+		}
+		a[i] = v
+		i, next = i+1, t+1
+	}
+
+	for _, v := range pending {
+		a[i] = BooleanValue{unixnano: next, value: v.value}
+		i, next = i+1, next+1
+	}
+	return a[:i]
+}
+
 // Exclude returns the subset of values not in [min, max].  The values must
 // be deduplicated and sorted before calling Exclude or the results are undefined.
 func (a BooleanValues) Exclude(min, max int64) BooleanValues {
@@ -1623,6 +2060,19 @@ func (a BooleanValues) Merge(b BooleanValues) BooleanValues {
 	return append(out, b...)
 }
 
+// MergeWith merges a and b with policy, where the values of b were written after
+// the values of a.  Both a and b must be sorted in ascending order.
+func (a BooleanValues) MergeWith(b BooleanValues, policy DuplicatePolicy) BooleanValues {
+	if policy == DuplicateLast {
+		return a.Merge(b)
+	}
+
+	out := make(BooleanValues, 0, len(a)+len(b))
+	out = append(out, a...)
+	out = append(out, b...)
+	return out.DeduplicateWith(policy)
+}
+
 func (a BooleanValues) Encode(buf []byte) ([]byte, error) {
 	return encodeBooleanValuesBlock(buf, a)
 }
@@ -5,6 +5,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -14,6 +15,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -145,6 +147,14 @@ type Engine struct {
 	done         chan struct{}   // channel to signal level compactions to stop
 	levelWorkers int             // Number of "workers" that expect compactions to be in a disabled state
 
+	// compactionsPaused is 1 while level compactions are paused by SetCompactionsPaused.
+	compactionsPaused int32
+
+	// fieldTypesMu is held for writing while ConvertFieldType swaps the TSM
+	// files and the type of a field, and for reading while cursors look up
+	// the type of a field and open the TSM files.
+	fieldTypesMu sync.RWMutex
+
 	snapDone chan struct{}   // channel to signal snapshot compactions to stop
 	snapWG   *sync.WaitGroup // waitgroup for running snapshot compactions
 
@@ -196,6 +206,17 @@ type Engine struct {
 
 	// seriesTypeMap maps a series key to field type
 	seriesTypeMap *radix.Tree
+
+	// duplicates is the DuplicatePolicy merging values with the same timestamp.
+	duplicates int32
+
+	// resolveDuplicates returns the duplicate policy of the shard's retention
+	// policy when the engine is opened.
+	resolveDuplicates func() DuplicatePolicy
+
+	// running holds the compaction strategies being applied.
+	runningMu sync.Mutex
+	running   map[*compactionStrategy]struct{}
 }
 
 // NewEngine returns a new instance of Engine.
@@ -220,6 +241,22 @@ func NewEngine(id uint64, idx tsdb.Index, path string, walPath string, sfile *ts
 	c.FileStore = fs
 	c.RateLimit = opt.CompactionThroughputLimiter
 
+	// The shard path ends with /:database/:retentionPolicy/:shardID.
+	rpPath := filepath.Dir(filepath.Clean(path))
+	database, rp := filepath.Base(filepath.Dir(rpPath)), filepath.Base(rpPath)
+	if opt.Codecs != nil {
+		c.codecs = func() blockCodecs {
+			return parseBlockCodecs(opt.Codecs(database, rp))
+		}
+	}
+
+	var resolveDuplicates func() DuplicatePolicy
+	if opt.DuplicatePolicy != nil {
+		resolveDuplicates = func() DuplicatePolicy {
+			return ParseDuplicatePolicy(opt.DuplicatePolicy(database, rp))
+		}
+	}
+
 	var planner CompactionPlanner = NewDefaultPlanner(fs, time.Duration(opt.Config.CompactFullWriteColdDuration))
 	if opt.CompactionPlannerCreator != nil {
 		planner = opt.CompactionPlannerCreator(opt.Config).(CompactionPlanner)
@@ -253,7 +290,9 @@ func NewEngine(id uint64, idx tsdb.Index, path string, walPath string, sfile *ts
 		compactionLimiter:             opt.CompactionLimiter,
 		scheduler:                     newScheduler(stats, opt.CompactionLimiter.Capacity()),
 		seriesIDSets:                  opt.SeriesIDSets,
+		resolveDuplicates:             resolveDuplicates,
 	}
+	c.duplicates = e.duplicatePolicy
 
 	// Feature flag to enable per-series type checking, by default this is off and
 	// e.seriesTypeMap will be nil.
@@ -372,6 +411,24 @@ func (e *Engine) SetEnabled(enabled bool) {
 	e.SetCompactionsEnabled(enabled)
 }
 
+// SetDuplicatePolicy sets the policy merging values with the same timestamp
+// in the cache, in compactions and in queries.  An unknown policy is treated
+// as "last".
+func (e *Engine) SetDuplicatePolicy(policy string) {
+	e.setDuplicatePolicy(ParseDuplicatePolicy(policy))
+}
+
+func (e *Engine) setDuplicatePolicy(policy DuplicatePolicy) {
+	atomic.StoreInt32(&e.duplicates, int32(policy))
+	e.Cache.SetDuplicatePolicy(policy)
+	e.FileStore.SetDuplicatePolicy(policy)
+}
+
+// duplicatePolicy returns the policy merging values with the same timestamp.
+func (e *Engine) duplicatePolicy() DuplicatePolicy {
+	return DuplicatePolicy(atomic.LoadInt32(&e.duplicates))
+}
+
 // SetCompactionsEnabled enables compactions on the engine.  When disabled
 // all running compactions are aborted and new compactions stop running.
 func (e *Engine) SetCompactionsEnabled(enabled bool) {
@@ -384,6 +441,20 @@ func (e *Engine) SetCompactionsEnabled(enabled bool) {
 	}
 }
 
+// SetCompactionsPaused pauses or resumes the level, full and optimize
+// compactions of the engine.  Pausing aborts the running compactions and keeps
+// new ones from starting until the compactions are resumed, regardless of
+// SetCompactionsEnabled.  Snapshots of the cache keep running.
+func (e *Engine) SetCompactionsPaused(paused bool) {
+	if paused {
+		if atomic.CompareAndSwapInt32(&e.compactionsPaused, 0, 1) {
+			e.disableLevelCompactions(true)
+		}
+	} else if atomic.CompareAndSwapInt32(&e.compactionsPaused, 1, 0) {
+		e.enableLevelCompactions(true)
+	}
+}
+
 // enableLevelCompactions will request that level compactions start back up again
 //
 // 'wait' signifies that a corresponding call to disableLevelCompactions(true) was made at some
@@ -540,6 +611,20 @@ func (e *Engine) disableSnapshotCompactions() {
 // This will cancel and running compactions and snapshot any data in the cache to
 // TSM files.  This is an expensive operation.
 func (e *Engine) ScheduleFullCompaction() error {
+	return e.scheduleCompaction(e.CompactionPlan.ForceFull)
+}
+
+// ScheduleOptimizeCompaction will force the engine to optimize the fully
+// compacted generations of TSM files even if the planner would not consider it
+// worthwhile yet.  Like ScheduleFullCompaction, it cancels running compactions
+// and snapshots the cache first.
+func (e *Engine) ScheduleOptimizeCompaction() error {
+	return e.scheduleCompaction(e.CompactionPlan.ForceOptimize)
+}
+
+// scheduleCompaction snapshots the cache and cancels running compactions
+// before calling force to request a plan from the planner.
+func (e *Engine) scheduleCompaction(force func()) error {
 	// Snapshot any data in the cache
 	if err := e.WriteSnapshot(); err != nil {
 		return err
@@ -551,11 +636,56 @@ func (e *Engine) ScheduleFullCompaction() error {
 	// Ensure compactions are restarted
 	defer e.SetCompactionsEnabled(true)
 
-	// Force the planner to only create a full plan.
-	e.CompactionPlan.ForceFull()
+	// Force the planner to create the requested plan.
+	force()
 	return nil
 }
 
+// Compactions returns the level, full and optimize compactions running on
+// the engine.
+func (e *Engine) Compactions() []tsdb.CompactionInfo {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+
+	compactions := make([]tsdb.CompactionInfo, 0, len(e.running))
+	for s := range e.running {
+		info := tsdb.CompactionInfo{
+			Level:    s.level,
+			Strategy: s.strategy,
+			Files:    len(s.group),
+			Started:  s.started,
+		}
+		if p := e.Compactor.progress(s.group); p != nil {
+			info.BytesIn = p.bytesIn
+			info.BytesOut = p.written()
+			info.Progress = p.percent()
+		}
+		compactions = append(compactions, info)
+	}
+
+	sort.Slice(compactions, func(i, j int) bool {
+		return compactions[i].Started.Before(compactions[j].Started)
+	})
+	return compactions
+}
+
+// track registers s as running until untrack is called.
+func (e *Engine) track(s *compactionStrategy) {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+	if e.running == nil {
+		e.running = make(map[*compactionStrategy]struct{})
+	}
+	e.running[s] = struct{}{}
+}
+
+// untrack removes s registered by track.
+func (e *Engine) untrack(s *compactionStrategy) {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+	delete(e.running, s)
+}
+
 // Path returns the path the engine was opened with.
 func (e *Engine) Path() string { return e.path }
 
@@ -741,6 +871,9 @@ func (e *Engine) Open() error {
 		return err
 	}
 
+	if e.resolveDuplicates != nil {
+		e.setDuplicatePolicy(e.resolveDuplicates())
+	}
 	if e.WALEnabled {
 		if err := e.reloadCache(); err != nil {
 			return err
@@ -982,7 +1115,209 @@ func (e *Engine) Export(w io.Writer, basePath string, start time.Time, end time.
 	return intar.Stream(w, path, basePath, e.timeStampFilterTarFile(start, end))
 }
 
+// ExportMeasurement writes a tar archive of the blocks of the measurement
+// name to the passed in writer, in the same format as Backup. Index and
+// tombstone files are not included; Import rebuilds the index.
+func (e *Engine) ExportMeasurement(w io.Writer, basePath string, name []byte) error {
+	path, err := e.CreateSnapshot()
+	if err != nil {
+		return err
+	}
+	// Remove the temporary snapshot dir
+	defer os.RemoveAll(path)
+
+	return intar.Stream(w, path, basePath, e.measurementFilterTarFile(name))
+}
+
+func (e *Engine) measurementFilterTarFile(name []byte) func(f os.FileInfo, shardRelativePath, fullPath string, tw *tar.Writer) error {
+	return func(fi os.FileInfo, shardRelativePath, fullPath string, tw *tar.Writer) error {
+		if !strings.HasSuffix(fi.Name(), "."+TSMFileExtension) {
+			return nil
+		}
+
+		f, err := os.Open(fullPath)
+		if err != nil {
+			return err
+		}
+		r, err := NewTSMReader(f)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		// Skip files without any key of the measurement.
+		var found bool
+		for i := 0; i < r.KeyCount() && !found; i++ {
+			key, _ := r.KeyAt(i)
+			found = bytes.Equal(measurementFromCompositeKey(key), name)
+		}
+		if !found {
+			return nil
+		}
+
+		return e.writeFilteredFile(r, fi, shardRelativePath, fullPath, tw, func(key []byte, minTime, maxTime int64) bool {
+			return bytes.Equal(measurementFromCompositeKey(key), name)
+		})
+	}
+}
+
+// measurementFromCompositeKey returns the measurement name of a TSM key.
+func measurementFromCompositeKey(key []byte) []byte {
+	seriesKey, _ := SeriesAndFieldFromCompositeKey(key)
+	return models.ParseName(seriesKey)
+}
+
+// BackupIncremental writes a tar archive of the data added to the shard since
+// the position from to the passed in writer. The archive starts with the
+// position it brings a backup to, followed by the TSM and tombstone files that
+// are new or changed since from and the tails of the WAL segments past their
+// offsets in from. Unlike Backup, the cache is not snapshotted first so recent
+// writes are taken from the WAL. A nil from backs up the whole shard.
+func (e *Engine) BackupIncremental(w io.Writer, basePath string, from *tsdb.BackupPosition) error {
+	path, walSizes, err := e.createIncrementalSnapshot()
+	if err != nil {
+		return err
+	}
+	// Remove the temporary snapshot dir
+	defer os.RemoveAll(path)
+
+	if from == nil {
+		from = &tsdb.BackupPosition{}
+	}
+
+	fis, err := ioutil.ReadDir(path)
+	if err != nil {
+		return err
+	}
+
+	pos := &tsdb.BackupPosition{
+		Files: make(map[string]int64, len(fis)),
+		WAL:   walSizes,
+		Tails: make(map[string]int64),
+	}
+	var files []os.FileInfo
+	for _, fi := range fis {
+		if fi.IsDir() {
+			continue
+		}
+		pos.Files[fi.Name()] = fi.Size()
+
+		// TSM files never change but tombstone files are appended to.
+		if size, ok := from.Files[fi.Name()]; !ok || size != fi.Size() {
+			files = append(files, fi)
+		}
+	}
+
+	segments := make([]string, 0, len(walSizes))
+	for name, size := range walSizes {
+		// A segment smaller than its offset was removed and created again.
+		offset := from.WAL[name]
+		if offset > size {
+			offset = 0
+		}
+		if offset < size {
+			pos.Tails[name] = offset
+			segments = append(segments, name)
+		}
+	}
+	sort.Strings(segments)
+
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	b, err := json.Marshal(pos)
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    filepath.ToSlash(filepath.Join(basePath, tsdb.BackupPositionFile)),
+		Mode:    0644,
+		Size:    int64(len(b)),
+		ModTime: time.Now().UTC(),
+	}); err != nil {
+		return err
+	} else if _, err := tw.Write(b); err != nil {
+		return err
+	}
+
+	for _, fi := range files {
+		if err := intar.StreamFile(fi, basePath, filepath.Join(path, fi.Name()), tw); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range segments {
+		fullPath := filepath.Join(path, tsdb.BackupWALDir, name)
+		if err := streamWALTail(tw, basePath, fullPath, pos.Tails[name], walSizes[name]); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// createIncrementalSnapshot creates a temp directory holding hard links to
+// the TSM and tombstone files of the shard, and to its WAL segments in the
+// BackupWALDir sub dir. It returns the size of each WAL segment when linked.
+// The engine is locked so the cache can not be written to a TSM file, and its
+// segments removed, in between.
+func (e *Engine) createIncrementalSnapshot() (string, map[string]int64, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	path, err := e.FileStore.CreateSnapshot()
+	if err != nil {
+		return "", nil, err
+	}
+
+	walDir := filepath.Join(path, tsdb.BackupWALDir)
+	if err := os.Mkdir(walDir, 0777); err != nil {
+		os.RemoveAll(path)
+		return "", nil, err
+	}
+
+	var sizes map[string]int64
+	if e.WALEnabled {
+		if sizes, err = e.WAL.LinkSegments(walDir); err != nil {
+			os.RemoveAll(path)
+			return "", nil, err
+		}
+	}
+	return path, sizes, nil
+}
+
+// streamWALTail writes the bytes of the WAL segment at fullPath from offset up
+// to size to tw, under the BackupWALDir of relativePath.
+func streamWALTail(tw *tar.Writer, relativePath, fullPath string, offset, size int64) error {
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    filepath.ToSlash(filepath.Join(relativePath, tsdb.BackupWALDir, filepath.Base(fullPath))),
+		Mode:    0644,
+		Size:    size - offset,
+		ModTime: time.Now().UTC(),
+	}); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, io.NewSectionReader(f, offset, size-offset))
+	return err
+}
+
 func (e *Engine) filterFileToBackup(r *TSMReader, fi os.FileInfo, shardRelativePath, fullPath string, start, end int64, tw *tar.Writer) error {
+	return e.writeFilteredFile(r, fi, shardRelativePath, fullPath, tw, func(key []byte, minTime, maxTime int64) bool {
+		return minTime >= start && minTime <= end ||
+			maxTime >= start && maxTime <= end ||
+			minTime <= start && maxTime >= end
+	})
+}
+
+// writeFilteredFile streams a copy of the TSM file r holding only the blocks
+// for which keep returns true to tw.
+func (e *Engine) writeFilteredFile(r *TSMReader, fi os.FileInfo, shardRelativePath, fullPath string, tw *tar.Writer, keep func(key []byte, minTime, maxTime int64) bool) error {
 	path := fullPath + ".tmp"
 	out, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0666)
 	if err != nil {
@@ -1005,10 +1340,8 @@ func (e *Engine) filterFileToBackup(r *TSMReader, fi os.FileInfo, shardRelativeP
 		if err != nil {
 			return err
 		}
-		if minTime >= start && minTime <= end ||
-			maxTime >= start && maxTime <= end ||
-			minTime <= start && maxTime >= end {
-			err := w.WriteBlock(key, minTime, maxTime, buf)
+		if keep(key, minTime, maxTime) {
+			err := w.WriteBlockWithStats(key, minTime, maxTime, buf, bi.Stats())
 			if err != nil {
 				return err
 			}
@@ -1770,6 +2103,116 @@ func (e *Engine) DeleteMeasurement(name []byte) error {
 	return e.DeleteSeriesRange(tsdb.NewSeriesIteratorAdapter(e.sfile, itr), math.MinInt64, math.MaxInt64)
 }
 
+// ConvertFieldType converts the values of a field of a measurement to typ.
+// The TSM files holding the field are rewritten by a dedicated compaction
+// while level compactions are disabled.  writes is locked once the existing
+// files are converted so that the files written meanwhile can be converted,
+// and the files and the field type replaced atomically.
+func (e *Engine) ConvertFieldType(measurement, field []byte, typ influxql.DataType, writes sync.Locker) error {
+	mf := e.fieldset.Fields(measurement)
+	if mf == nil {
+		return nil
+	}
+	if f := mf.FieldBytes(field); f == nil || f.Type == typ {
+		return nil
+	} else if !tsdb.CanConvertFieldType(f.Type, typ) {
+		return tsdb.ErrFieldTypeConversion
+	}
+
+	// Disable level compactions so the set of TSM files only grows by
+	// snapshots until the converted files replace the existing ones.
+	e.disableLevelCompactions(true)
+	defer e.enableLevelCompactions(true)
+
+	conv := newFieldConverter(measurement, field, typ)
+	if err := e.flushCache(); err != nil {
+		return err
+	}
+	oldFiles, newFiles, err := e.convertFiles(conv, nil)
+	if err != nil {
+		return err
+	}
+
+	writes.Lock()
+	defer writes.Unlock()
+
+	if err := e.flushCache(); err != nil {
+		e.Compactor.removeTmpFiles(newFiles)
+		return err
+	}
+	old, converted, err := e.convertFiles(conv, oldFiles)
+	if err != nil {
+		e.Compactor.removeTmpFiles(newFiles)
+		return err
+	}
+	oldFiles, newFiles = append(oldFiles, old...), append(newFiles, converted...)
+
+	// Cursors must not see the converted files with the old field type, or
+	// the old files with the new one.
+	e.fieldTypesMu.Lock()
+	if err := e.FileStore.Replace(oldFiles, newFiles); err != nil {
+		e.fieldTypesMu.Unlock()
+		e.Compactor.removeTmpFiles(newFiles)
+		return err
+	}
+	err = mf.SetFieldType(field, typ)
+	e.fieldTypesMu.Unlock()
+	if err != nil {
+		return err
+	}
+	return e.fieldset.Save()
+}
+
+// flushCache writes the cache to TSM files, waiting for a snapshot in
+// progress to complete first.
+func (e *Engine) flushCache() error {
+	for {
+		if err := e.WriteSnapshot(); err != ErrSnapshotInProgress {
+			return err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// convertFiles writes converted copies of the TSM files holding values of the
+// field of conv, except for the files in skip.  The files are converted by
+// generation since the copies are written after the last sequence of their
+// generation.  It returns the files converted and their copies.
+func (e *Engine) convertFiles(conv *fieldConverter, skip []string) (oldFiles, newFiles []string, err error) {
+	skipped := make(map[string]struct{}, len(skip))
+	for _, path := range skip {
+		skipped[path] = struct{}{}
+	}
+
+	generations := make(map[int][]string)
+	convert := make(map[int]bool)
+	for _, f := range e.FileStore.Files() {
+		if _, ok := skipped[f.Path()]; ok {
+			continue
+		}
+
+		gen, _, err := e.FileStore.parseFileName(f.Path())
+		if err != nil {
+			return nil, nil, err
+		}
+		generations[gen] = append(generations[gen], f.Path())
+		if !convert[gen] && conv.contains(f) {
+			convert[gen] = true
+		}
+	}
+
+	for gen := range convert {
+		files, err := e.Compactor.compactConvert(generations[gen], conv)
+		if err != nil {
+			e.Compactor.removeTmpFiles(newFiles)
+			return nil, nil, err
+		}
+		oldFiles = append(oldFiles, generations[gen]...)
+		newFiles = append(newFiles, files...)
+	}
+	return oldFiles, newFiles, nil
+}
+
 // ForEachMeasurementName iterates over each measurement name in the engine.
 func (e *Engine) ForEachMeasurementName(fn func(name []byte) error) error {
 	return e.index.ForEachMeasurementName(fn)
@@ -2108,8 +2551,10 @@ func (e *Engine) compactFull(grp CompactionGroup, wg *sync.WaitGroup) bool {
 type compactionStrategy struct {
 	group CompactionGroup
 
-	fast  bool
-	level int
+	fast     bool
+	level    int
+	strategy string
+	started  time.Time
 
 	durationStat *int64
 	activeStat   *int64
@@ -2126,6 +2571,11 @@ type compactionStrategy struct {
 // Apply concurrently compacts all the groups in a compaction strategy.
 func (s *compactionStrategy) Apply() {
 	start := time.Now()
+
+	s.started = start
+	s.engine.track(s)
+	defer s.engine.untrack(s)
+
 	s.compactGroup()
 	atomic.AddInt64(s.durationStat, time.Since(start).Nanoseconds())
 }
@@ -2195,6 +2645,7 @@ func (e *Engine) levelCompactionStrategy(group CompactionGroup, fast bool, level
 		fast:      fast,
 		engine:    e,
 		level:     level,
+		strategy:  "level",
 
 		activeStat:   &e.stats.TSMCompactionsActive[level-1],
 		successStat:  &e.stats.TSMCompactions[level-1],
@@ -2214,9 +2665,11 @@ func (e *Engine) fullCompactionStrategy(group CompactionGroup, optimize bool) *c
 		fast:      optimize,
 		engine:    e,
 		level:     4,
+		strategy:  "full",
 	}
 
 	if optimize {
+		s.strategy = "optimize"
 		s.activeStat = &e.stats.TSMOptimizeCompactionsActive
 		s.successStat = &e.stats.TSMOptimizeCompactions
 		s.errorStat = &e.stats.TSMOptimizeCompactionErrors
@@ -2392,6 +2845,9 @@ func (e *Engine) createCallIterator(ctx context.Context, measurement string, cal
 	// Calculate tag sets and apply SLIMIT/SOFFSET.
 	tagSets = query.LimitTagSets(tagSets, opt.SLimit, opt.SOffset)
 
+	// Determine if the call can be answered from the statistics of blocks.
+	statsType := e.blockStatsType(measurement, call, opt)
+
 	itrs := make([]query.Iterator, 0, len(tagSets))
 	if err := func() error {
 		for _, t := range tagSets {
@@ -2403,6 +2859,26 @@ func (e *Engine) createCallIterator(ctx context.Context, measurement string, cal
 			default:
 			}
 
+			// Series with field filters need to read every point.
+			if statsType != influxql.Unknown && !hasFilters(t) {
+				inputs, err := e.createBlockStatsIterators(ctx, call, measurement, t, statsType, opt)
+				if err != nil {
+					return err
+				} else if len(inputs) == 0 {
+					continue
+				}
+
+				if opt.InterruptCh != nil {
+					for i, input := range inputs {
+						inputs[i] = query.NewInterruptIterator(input, opt.InterruptCh)
+					}
+				}
+
+				itr := query.NewParallelMergeIterator(inputs, opt, runtime.GOMAXPROCS(0))
+				itrs = append(itrs, itr)
+				continue
+			}
+
 			inputs, err := e.createTagSetIterators(ctx, ref, measurement, t, opt)
 			if err != nil {
 				return err
@@ -2816,6 +3292,10 @@ func (e *Engine) buildCursor(ctx context.Context, measurement, seriesKey string,
 		return &stringSliceCursor{values: []string{seriesKey}}
 	}
 
+	// The TSM files must be opened with the field type they hold.
+	e.fieldTypesMu.RLock()
+	defer e.fieldTypesMu.RUnlock()
+
 	// Look up fields for measurement.
 	mf := e.fieldset.FieldsByString(measurement)
 	if mf == nil {
@@ -4,6 +4,8 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -322,6 +324,116 @@ func TestEngine_Backup(t *testing.T) {
 	}
 }
 
+func TestEngine_BackupIncremental(t *testing.T) {
+	sfile := MustOpenSeriesFile()
+	defer sfile.Close()
+
+	// Generate temporary file.
+	f, _ := ioutil.TempFile("", "tsm")
+	f.Close()
+	os.Remove(f.Name())
+	walPath := filepath.Join(f.Name(), "wal")
+	os.MkdirAll(walPath, 0777)
+	defer os.RemoveAll(f.Name())
+
+	db := path.Base(f.Name())
+	opt := tsdb.NewEngineOptions()
+	opt.InmemIndex = inmem.NewIndex(db, sfile.SeriesFile)
+	idx := tsdb.MustOpenIndex(1, db, filepath.Join(f.Name(), "index"), tsdb.NewSeriesIDSet(), sfile.SeriesFile, opt)
+	defer idx.Close()
+
+	e := tsm1.NewEngine(1, idx, f.Name(), walPath, sfile.SeriesFile, opt).(*tsm1.Engine)
+
+	// mock the planner so compactions don't run during the test
+	e.CompactionPlan = &mockPlanner{}
+
+	if err := e.Open(); err != nil {
+		t.Fatalf("failed to open tsm1 engine: %s", err.Error())
+	}
+	defer e.Close()
+
+	// backup reads the archive of an incremental backup from pos, returning
+	// the new position and the names of the other entries.
+	backup := func(pos *tsdb.BackupPosition) (*tsdb.BackupPosition, []string) {
+		b := bytes.NewBuffer(nil)
+		if err := e.BackupIncremental(b, "db/rp/1", pos); err != nil {
+			t.Fatalf("failed to backup: %s", err.Error())
+		}
+
+		var next tsdb.BackupPosition
+		var names []string
+		tr := tar.NewReader(b)
+		for {
+			th, err := tr.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Problem reading tar header: %s", err)
+			}
+
+			if th.Name == "db/rp/1/"+tsdb.BackupPositionFile {
+				if err := json.NewDecoder(tr).Decode(&next); err != nil {
+					t.Fatal(err)
+				}
+				continue
+			}
+			names = append(names, th.Name)
+		}
+		return &next, names
+	}
+
+	if err := e.WritePoints([]models.Point{MustParsePointString("cpu,host=A value=1.1 1000000000")}); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+	if err := e.WriteSnapshot(); err != nil {
+		t.Fatalf("failed to snapshot: %s", err.Error())
+	}
+	if err := e.WritePoints([]models.Point{MustParsePointString("cpu,host=B value=1.2 2000000000")}); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+
+	// The first backup holds the TSM file and the WAL segment with the
+	// second point.
+	tsmFile := filepath.Base(e.FileStore.Files()[0].Path())
+	pos, names := backup(nil)
+	if len(pos.Files) != 1 || pos.Files[tsmFile] == 0 {
+		t.Fatalf("unexpected files: %v", pos.Files)
+	} else if len(pos.WAL) != 1 || len(pos.Tails) != 1 {
+		t.Fatalf("unexpected WAL position: %v, tails: %v", pos.WAL, pos.Tails)
+	}
+
+	var segment string
+	for name := range pos.WAL {
+		segment = name
+	}
+	if exp := []string{"db/rp/1/" + tsmFile, "db/rp/1/wal/" + segment}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("unexpected entries:\n\tgot: %v\n\texp: %v", names, exp)
+	}
+
+	// The next backup only holds the tail of the segment.
+	if err := e.WritePoints([]models.Point{MustParsePointString("cpu,host=C value=1.3 3000000000")}); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+
+	next, names := backup(pos)
+	if !reflect.DeepEqual(next.Files, pos.Files) {
+		t.Fatalf("unexpected files: %v", next.Files)
+	} else if next.WAL[segment] <= pos.WAL[segment] {
+		t.Fatalf("unexpected WAL position: %v", next.WAL)
+	} else if next.Tails[segment] != pos.WAL[segment] {
+		t.Fatalf("unexpected tails: %v", next.Tails)
+	} else if exp := []string{"db/rp/1/wal/" + segment}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("unexpected entries:\n\tgot: %v\n\texp: %v", names, exp)
+	}
+
+	// Nothing is backed up without new data.
+	if last, names := backup(next); !reflect.DeepEqual(last.Files, next.Files) || !reflect.DeepEqual(last.WAL, next.WAL) || len(last.Tails) != 0 {
+		t.Fatalf("unexpected position: %v", last)
+	} else if len(names) != 0 {
+		t.Fatalf("unexpected entries: %v", names)
+	}
+}
+
 func TestEngine_Export(t *testing.T) {
 	// Generate temporary file.
 	f, _ := ioutil.TempFile("", "tsm")
@@ -758,6 +870,158 @@ func TestEngine_CreateIterator_TSM_Ascending(t *testing.T) {
 	}
 }
 
+// Ensure queries merge points of overlapping TSM files and the cache with the
+// duplicate policy of the engine.
+func TestEngine_CreateIterator_DuplicatePolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		policy string
+		exp    float64
+	}{
+		{"last", 5.5},
+		{"first", 1.1},
+		{"max", 9.9},
+	} {
+		t.Run(tt.policy, func(t *testing.T) {
+			e := MustOpenEngine(tsdb.InmemIndexName)
+			defer e.Close()
+			e.SetDuplicatePolicy(tt.policy)
+
+			e.MeasurementFields([]byte("cpu")).CreateFieldIfNotExists([]byte("value"), influxql.Float)
+			e.CreateSeriesIfNotExists([]byte("cpu,host=A"), []byte("cpu"), models.NewTags(map[string]string{"host": "A"}))
+
+			// Two overlapping TSM files and a cached value at the same time.
+			for _, p := range []string{`cpu,host=A value=1.1 1000000000`, `cpu,host=A value=9.9 1000000000`} {
+				if err := e.WritePointsString(p); err != nil {
+					t.Fatalf("failed to write points: %s", err.Error())
+				}
+				e.MustWriteSnapshot()
+			}
+			if err := e.WritePointsString(`cpu,host=A value=5.5 1000000000`); err != nil {
+				t.Fatalf("failed to write points: %s", err.Error())
+			}
+
+			for _, ascending := range []bool{true, false} {
+				itr, err := e.CreateIterator(context.Background(), "cpu", query.IteratorOptions{
+					Expr:       influxql.MustParseExpr(`value`),
+					Dimensions: []string{"host"},
+					StartTime:  influxql.MinTime,
+					EndTime:    influxql.MaxTime,
+					Ascending:  ascending,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				fitr := itr.(query.FloatIterator)
+
+				if p, err := fitr.Next(); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				} else if !reflect.DeepEqual(p, &query.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 1000000000, Value: tt.exp}) {
+					t.Fatalf("unexpected point (ascending=%v): %v", ascending, p)
+				}
+				if p, err := fitr.Next(); err != nil {
+					t.Fatalf("expected eof, got error: %v", err)
+				} else if p != nil {
+					t.Fatalf("expected eof: %v", p)
+				}
+				itr.Close()
+			}
+		})
+	}
+}
+
+// Ensure engine answers count(), sum(), min() and max() from block statistics
+// the same way as from the points of the blocks.
+func TestEngine_CreateIterator_BlockStats(t *testing.T) {
+	t.Parallel()
+
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) {
+			e := MustOpenEngine(index)
+			defer e.Close()
+
+			e.MeasurementFields([]byte("cpu")).CreateFieldIfNotExists([]byte("value"), influxql.Float)
+			e.CreateSeriesIfNotExists([]byte("cpu,host=A"), []byte("cpu"), models.NewTags(map[string]string{"host": "A"}))
+
+			// Blocks in a single window, overlapping blocks and cached values.
+			for _, points := range [][]string{
+				{`cpu,host=A value=1 1000000000`, `cpu,host=A value=5 2000000000`, `cpu,host=A value=2 3000000000`, `cpu,host=A value=5 4000000000`},
+				{`cpu,host=A value=3 11000000000`, `cpu,host=A value=1 12000000000`, `cpu,host=A value=1 13000000000`},
+				{`cpu,host=A value=7 12000000000`},
+				{`cpu,host=A value=6 22000000000`, `cpu,host=A value=4 23000000000`},
+			} {
+				if err := e.WritePointsString(points...); err != nil {
+					t.Fatalf("failed to write points: %s", err.Error())
+				}
+				e.MustWriteSnapshot()
+			}
+			if err := e.WritePointsString(`cpu,host=A value=4 21000000000`); err != nil {
+				t.Fatalf("failed to write points: %s", err.Error())
+			}
+
+			read := func(expr string, cond influxql.Expr, ascending bool) []string {
+				t.Helper()
+				itr, err := e.CreateIterator(context.Background(), "cpu", query.IteratorOptions{
+					Expr:      influxql.MustParseExpr(expr),
+					Condition: cond,
+					Interval:  query.Interval{Duration: 10 * time.Second},
+					StartTime: 0,
+					EndTime:   30000000000 - 1,
+					Ascending: ascending,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer itr.Close()
+
+				var points []string
+				for {
+					switch itr := itr.(type) {
+					case query.FloatIterator:
+						p, err := itr.Next()
+						if err != nil {
+							t.Fatal(err)
+						} else if p == nil {
+							return points
+						}
+						points = append(points, fmt.Sprintf("%d:%v", p.Time, p.Value))
+					case query.IntegerIterator:
+						p, err := itr.Next()
+						if err != nil {
+							t.Fatal(err)
+						} else if p == nil {
+							return points
+						}
+						points = append(points, fmt.Sprintf("%d:%v", p.Time, p.Value))
+					default:
+						t.Fatalf("unexpected iterator: %T", itr)
+					}
+				}
+			}
+
+			for _, tt := range []struct {
+				expr string
+				exp  []string
+			}{
+				{`count(value)`, []string{"0:4", "10000000000:3", "20000000000:3"}},
+				{`sum(value)`, []string{"0:13", "10000000000:11", "20000000000:14"}},
+				{`min(value)`, []string{"1000000000:1", "13000000000:1", "21000000000:4"}},
+				{`max(value)`, []string{"2000000000:5", "12000000000:7", "22000000000:6"}},
+			} {
+				if got := read(tt.expr, nil, true); !reflect.DeepEqual(got, tt.exp) {
+					t.Fatalf("%s: unexpected points:\n got=%v\n exp=%v", tt.expr, got, tt.exp)
+				}
+
+				// The points of the blocks are read when a field is filtered on.
+				if got, exp := read(tt.expr, nil, false), read(tt.expr, influxql.MustParseExpr(`value > -1000`), false); !reflect.DeepEqual(got, exp) {
+					t.Fatalf("%s: unexpected descending points:\n got=%v\n exp=%v", tt.expr, got, exp)
+				}
+			}
+		})
+	}
+}
+
 // Ensure engine can create an descending iterator for cached values.
 func TestEngine_CreateIterator_TSM_Descending(t *testing.T) {
 	t.Parallel()
@@ -1672,6 +1936,108 @@ func TestEngine_LastModified(t *testing.T) {
 	}
 }
 
+// Ensure engine converts the values of a field in TSM files and the cache.
+func TestEngine_ConvertFieldType(t *testing.T) {
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) {
+			e := MustOpenEngine(index)
+			defer e.Close()
+
+			e.MeasurementFields([]byte("cpu")).CreateFieldIfNotExists([]byte("value"), influxql.Integer)
+			e.MeasurementFields([]byte("mem")).CreateFieldIfNotExists([]byte("value"), influxql.Integer)
+			if err := e.WritePointsString(
+				`cpu,host=A value=1i 1000000000`,
+				`mem value=2i 1000000000`,
+			); err != nil {
+				t.Fatalf("failed to write points: %s", err.Error())
+			}
+			if err := e.WriteSnapshot(); err != nil {
+				t.Fatalf("failed to snapshot: %s", err.Error())
+			}
+
+			// Leave a value in the cache.
+			if err := e.WritePointsString(`cpu,host=A value=3i 3000000000`); err != nil {
+				t.Fatalf("failed to write points: %s", err.Error())
+			}
+
+			if err := e.ConvertFieldType([]byte("cpu"), []byte("value"), influxql.Float, &sync.Mutex{}); err != nil {
+				t.Fatalf("failed to convert field: %s", err.Error())
+			}
+
+			if got, exp := e.MeasurementFields([]byte("cpu")).Field("value").Type, influxql.Float; got != exp {
+				t.Fatalf("field type mismatch: got %v, exp %v", got, exp)
+			} else if got, exp := e.MeasurementFields([]byte("mem")).Field("value").Type, influxql.Integer; got != exp {
+				t.Fatalf("field type mismatch: got %v, exp %v", got, exp)
+			} else if got, exp := e.Cache.Size(), uint64(0); got != exp {
+				t.Fatalf("cache size mismatch: got %v, exp %v", got, exp)
+			}
+
+			keys := e.FileStore.Keys()
+			if got, exp := keys["cpu,host=A#!~#value"], byte(tsm1.BlockFloat64); got != exp {
+				t.Fatalf("block type mismatch: got %v, exp %v", got, exp)
+			} else if got, exp := keys["mem#!~#value"], byte(tsm1.BlockInteger); got != exp {
+				t.Fatalf("block type mismatch: got %v, exp %v", got, exp)
+			}
+
+			for _, v := range []tsm1.Value{tsm1.NewValue(1000000000, 1.0), tsm1.NewValue(3000000000, 3.0)} {
+				values, err := e.FileStore.Read([]byte("cpu,host=A#!~#value"), v.UnixNano())
+				if err != nil {
+					t.Fatal(err)
+				} else if exp := []tsm1.Value{v}; !reflect.DeepEqual(values, exp) {
+					t.Fatalf("values mismatch: got %v, exp %v", values, exp)
+				}
+			}
+
+			if err := e.ConvertFieldType([]byte("cpu"), []byte("value"), influxql.String, &sync.Mutex{}); err != tsdb.ErrFieldTypeConversion {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// Ensure engine rejects a conversion when a value cannot be represented by
+// the new type, and leaves the field unchanged.
+func TestEngine_ConvertFieldType_OutOfRange(t *testing.T) {
+	for _, tt := range []struct {
+		value interface{}
+		from  influxql.DataType
+		to    influxql.DataType
+	}{
+		{int64(-1), influxql.Integer, influxql.Unsigned},
+		{uint64(math.MaxUint64), influxql.Unsigned, influxql.Integer},
+		{-1.5, influxql.Float, influxql.Unsigned},
+		{1e19, influxql.Float, influxql.Integer},
+	} {
+		t.Run(fmt.Sprintf("%v %s", tt.value, tt.to), func(t *testing.T) {
+			e := MustOpenEngine(tsdb.InmemIndexName)
+			defer e.Close()
+
+			e.MeasurementFields([]byte("cpu")).CreateFieldIfNotExists([]byte("value"), tt.from)
+			e.CreateSeriesIfNotExists([]byte("cpu"), []byte("cpu"), nil)
+			p := models.MustNewPoint("cpu", nil, models.Fields{"value": tt.value}, time.Unix(1, 0))
+			if err := e.WritePoints([]models.Point{p}); err != nil {
+				t.Fatalf("failed to write points: %s", err.Error())
+			}
+
+			if err := e.ConvertFieldType([]byte("cpu"), []byte("value"), tt.to, &sync.Mutex{}); !errors.Is(err, tsdb.ErrFieldValueOutOfRange) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := e.MeasurementFields([]byte("cpu")).Field("value").Type; got != tt.from {
+				t.Fatalf("field type mismatch: got %v, exp %v", got, tt.from)
+			}
+			values, err := e.FileStore.Read([]byte("cpu#!~#value"), 1000000000)
+			if err != nil {
+				t.Fatal(err)
+			} else if len(values) != 1 {
+				t.Fatalf("values mismatch: got %v", values)
+			} else if typ, _ := tsm1.Values(values).InfluxQLType(); typ != tt.from {
+				t.Fatalf("value type mismatch: got %v, exp %v", typ, tt.from)
+			}
+		})
+	}
+}
+
 func TestEngine_SnapshotsDisabled(t *testing.T) {
 	sfile := MustOpenSeriesFile()
 	defer sfile.Close()
@@ -2585,6 +2951,7 @@ func (m *mockPlanner) PlanOptimize() []tsm1.CompactionGroup            { return
 func (m *mockPlanner) Release(groups []tsm1.CompactionGroup)           {}
 func (m *mockPlanner) FullyCompacted() bool                            { return false }
 func (m *mockPlanner) ForceFull()                                      {}
+func (m *mockPlanner) ForceOptimize()                                  {}
 func (m *mockPlanner) SetFileStore(fs *tsm1.FileStore)                 {}
 
 // ParseTags returns an instance of Tags for a comma-delimited list of key/values.
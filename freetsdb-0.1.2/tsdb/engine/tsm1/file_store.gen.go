@@ -12,6 +12,12 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
+
+	// Skip blocks that have been read, or summarized, without decoding them.
+	if first.read() {
+		c.current = c.current[1:]
+		goto LOOP
+	}
 	*buf = (*buf)[:0]
 	var values FloatValues
 	values, err := first.r.ReadFloatBlockAt(&first.entry, buf)
@@ -107,7 +113,7 @@ LOOP:
 				// Only use values in the overlapping window
 				v = v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values = values.Merge(v)
+				values = values.MergeWith(v, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -170,7 +176,7 @@ LOOP:
 			if v.Len() > 0 {
 				v = v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values = v.Merge(values)
+				values = v.MergeWith(values, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -198,6 +204,12 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
+
+	// Skip blocks that have been read, or summarized, without decoding them.
+	if first.read() {
+		c.current = c.current[1:]
+		goto LOOP
+	}
 	*buf = (*buf)[:0]
 	var values IntegerValues
 	values, err := first.r.ReadIntegerBlockAt(&first.entry, buf)
@@ -293,7 +305,7 @@ LOOP:
 				// Only use values in the overlapping window
 				v = v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values = values.Merge(v)
+				values = values.MergeWith(v, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -356,7 +368,7 @@ LOOP:
 			if v.Len() > 0 {
 				v = v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values = v.Merge(values)
+				values = v.MergeWith(values, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -384,6 +396,12 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
+
+	// Skip blocks that have been read, or summarized, without decoding them.
+	if first.read() {
+		c.current = c.current[1:]
+		goto LOOP
+	}
 	*buf = (*buf)[:0]
 	var values UnsignedValues
 	values, err := first.r.ReadUnsignedBlockAt(&first.entry, buf)
@@ -479,7 +497,7 @@ LOOP:
 				// Only use values in the overlapping window
 				v = v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values = values.Merge(v)
+				values = values.MergeWith(v, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -542,7 +560,7 @@ LOOP:
 			if v.Len() > 0 {
 				v = v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values = v.Merge(values)
+				values = v.MergeWith(values, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -570,6 +588,12 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
+
+	// Skip blocks that have been read, or summarized, without decoding them.
+	if first.read() {
+		c.current = c.current[1:]
+		goto LOOP
+	}
 	*buf = (*buf)[:0]
 	var values StringValues
 	values, err := first.r.ReadStringBlockAt(&first.entry, buf)
@@ -665,7 +689,7 @@ LOOP:
 				// Only use values in the overlapping window
 				v = v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values = values.Merge(v)
+				values = values.MergeWith(v, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -728,7 +752,7 @@ LOOP:
 			if v.Len() > 0 {
 				v = v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values = v.Merge(values)
+				values = v.MergeWith(values, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -756,6 +780,12 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
+
+	// Skip blocks that have been read, or summarized, without decoding them.
+	if first.read() {
+		c.current = c.current[1:]
+		goto LOOP
+	}
 	*buf = (*buf)[:0]
 	var values BooleanValues
 	values, err := first.r.ReadBooleanBlockAt(&first.entry, buf)
@@ -851,7 +881,7 @@ LOOP:
 				// Only use values in the overlapping window
 				v = v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values = values.Merge(v)
+				values = values.MergeWith(v, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -914,7 +944,7 @@ LOOP:
 			if v.Len() > 0 {
 				v = v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values = v.Merge(values)
+				values = v.MergeWith(values, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -193,6 +193,10 @@ type FileStore struct {
 	parseFileName ParseFileNameFunc
 
 	obs tsdb.FileStoreObserver
+
+	// duplicates is the DuplicatePolicy merging values with the same timestamp
+	// when blocks overlap.
+	duplicates int32
 }
 
 // FileStat holds information about a TSM file on disk.
@@ -246,6 +250,17 @@ func (f *FileStore) WithObserver(obs tsdb.FileStoreObserver) {
 	f.obs = obs
 }
 
+// SetDuplicatePolicy sets the policy merging values with the same timestamp
+// when blocks of a key overlap.
+func (f *FileStore) SetDuplicatePolicy(policy DuplicatePolicy) {
+	atomic.StoreInt32(&f.duplicates, int32(policy))
+}
+
+// duplicatePolicy returns the policy merging values with the same timestamp.
+func (f *FileStore) duplicatePolicy() DuplicatePolicy {
+	return DuplicatePolicy(atomic.LoadInt32(&f.duplicates))
+}
+
 func (f *FileStore) WithParseFileNameFunc(parseFileNameFunc ParseFileNameFunc) {
 	f.parseFileName = parseFileNameFunc
 }
@@ -1125,6 +1140,7 @@ func DefaultParseFileName(name string) (int, int, error) {
 // KeyCursor allows iteration through keys in a set of files within a FileStore.
 type KeyCursor struct {
 	key []byte
+	fs  *FileStore
 
 	// seeks is all the file locations that we need to return during iteration.
 	seeks []*location
@@ -1142,6 +1158,10 @@ type KeyCursor struct {
 	// decrement through the size of seeks slice.
 	pos       int
 	ascending bool
+
+	// duplicates is the policy merging values of overlapping blocks with the
+	// same timestamp.
+	duplicates DuplicatePolicy
 }
 
 type location struct {
@@ -1193,11 +1213,13 @@ func (a ascLocations) Less(i, j int) bool {
 // This function assumes the read-lock has been taken.
 func newKeyCursor(ctx context.Context, fs *FileStore, key []byte, t int64, ascending bool) *KeyCursor {
 	c := &KeyCursor{
-		key:       key,
-		seeks:     fs.locations(key, t, ascending),
-		ctx:       ctx,
-		col:       metrics.GroupFromContext(ctx),
-		ascending: ascending,
+		key:        key,
+		fs:         fs,
+		seeks:      fs.locations(key, t, ascending),
+		ctx:        ctx,
+		col:        metrics.GroupFromContext(ctx),
+		ascending:  ascending,
+		duplicates: fs.duplicatePolicy(),
 	}
 
 	if ascending {
@@ -1267,6 +1289,48 @@ func (c *KeyCursor) seekDescending(t int64) {
 	}
 }
 
+// summarize marks the blocks that can be summarized by their statistics as read
+// and returns them.  A block can be summarized if it has statistics, it doesn't
+// overlap any other block or tombstone of the key, none of its values have been
+// read and fn returns true for its entry.  Summarized blocks are skipped by the
+// ReadBlock functions.
+func (c *KeyCursor) summarize(fn func(entry *IndexEntry) bool) []*location {
+	seeks := make([]*location, len(c.seeks))
+	copy(seeks, c.seeks)
+	sort.Slice(seeks, func(i, j int) bool { return seeks[i].entry.MinTime < seeks[j].entry.MinTime })
+
+	var summarized []*location
+	maxT := int64(math.MinInt64)
+	for i, l := range seeks {
+		overlaps := i > 0 && maxT >= l.entry.MinTime
+		if i+1 < len(seeks) && seeks[i+1].entry.MinTime <= l.entry.MaxTime {
+			overlaps = true
+		}
+		if l.entry.MaxTime > maxT {
+			maxT = l.entry.MaxTime
+		}
+
+		if overlaps || l.entry.Stats.Count == 0 || (l.readMin <= l.entry.MaxTime && l.readMax >= l.entry.MinTime) {
+			continue
+		}
+
+		tombstoned := false
+		for _, t := range l.r.TombstoneRange(c.key) {
+			if t.Min <= l.entry.MaxTime && t.Max >= l.entry.MinTime {
+				tombstoned = true
+				break
+			}
+		}
+		if tombstoned || !fn(&l.entry) {
+			continue
+		}
+
+		l.markRead(l.entry.MinTime, l.entry.MaxTime)
+		summarized = append(summarized, l)
+	}
+	return summarized
+}
+
 // Next moves the cursor to the next position.
 // Data should be read by the ReadBlock functions.
 func (c *KeyCursor) Next() {
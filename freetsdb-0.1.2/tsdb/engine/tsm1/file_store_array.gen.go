@@ -18,6 +18,12 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
+
+	// Skip blocks that have been read, or summarized, without decoding them.
+	if first.read() {
+		c.current = c.current[1:]
+		goto LOOP
+	}
 	err := first.r.ReadFloatArrayBlockAt(&first.entry, values)
 	if err != nil {
 		return nil, err
@@ -110,7 +116,7 @@ LOOP:
 				// Only use values in the overlapping window
 				v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values.Merge(v)
+				mergeFloatArrayWith(values, v, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -172,7 +178,7 @@ LOOP:
 			if v.Len() > 0 {
 				v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				v.Merge(values)
+				mergeFloatArrayWith(v, values, c.duplicates)
 				*values = *v
 			}
 			cur.markRead(minT, maxT)
@@ -190,6 +196,24 @@ func excludeTombstonesFloatArray(t []TimeRange, values *tsdb.FloatArray) {
 	}
 }
 
+// mergeFloatArrayWith merges b into a with policy, where the values of b
+// were written after the values of a.
+func mergeFloatArrayWith(a, b *tsdb.FloatArray, policy DuplicatePolicy) {
+	if policy == DuplicateLast {
+		a.Merge(b)
+		return
+	}
+
+	values := make(FloatValues, 0, a.Len()+b.Len())
+	for i, t := range a.Timestamps {
+		values = append(values, FloatValue{unixnano: t, value: a.Values[i]})
+	}
+	for i, t := range b.Timestamps {
+		values = append(values, FloatValue{unixnano: t, value: b.Values[i]})
+	}
+	*a = *NewFloatArrayFromValues(values.DeduplicateWith(policy))
+}
+
 // ReadIntegerArrayBlock reads the next block as a set of integer values.
 func (c *KeyCursor) ReadIntegerArrayBlock(values *tsdb.IntegerArray) (*tsdb.IntegerArray, error) {
 LOOP:
@@ -202,6 +226,12 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
+
+	// Skip blocks that have been read, or summarized, without decoding them.
+	if first.read() {
+		c.current = c.current[1:]
+		goto LOOP
+	}
 	err := first.r.ReadIntegerArrayBlockAt(&first.entry, values)
 	if err != nil {
 		return nil, err
@@ -294,7 +324,7 @@ LOOP:
 				// Only use values in the overlapping window
 				v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values.Merge(v)
+				mergeIntegerArrayWith(values, v, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -356,7 +386,7 @@ LOOP:
 			if v.Len() > 0 {
 				v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				v.Merge(values)
+				mergeIntegerArrayWith(v, values, c.duplicates)
 				*values = *v
 			}
 			cur.markRead(minT, maxT)
@@ -374,6 +404,24 @@ func excludeTombstonesIntegerArray(t []TimeRange, values *tsdb.IntegerArray) {
 	}
 }
 
+// mergeIntegerArrayWith merges b into a with policy, where the values of b
+// were written after the values of a.
+func mergeIntegerArrayWith(a, b *tsdb.IntegerArray, policy DuplicatePolicy) {
+	if policy == DuplicateLast {
+		a.Merge(b)
+		return
+	}
+
+	values := make(IntegerValues, 0, a.Len()+b.Len())
+	for i, t := range a.Timestamps {
+		values = append(values, IntegerValue{unixnano: t, value: a.Values[i]})
+	}
+	for i, t := range b.Timestamps {
+		values = append(values, IntegerValue{unixnano: t, value: b.Values[i]})
+	}
+	*a = *NewIntegerArrayFromValues(values.DeduplicateWith(policy))
+}
+
 // ReadUnsignedArrayBlock reads the next block as a set of unsigned values.
 func (c *KeyCursor) ReadUnsignedArrayBlock(values *tsdb.UnsignedArray) (*tsdb.UnsignedArray, error) {
 LOOP:
@@ -386,6 +434,12 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
+
+	// Skip blocks that have been read, or summarized, without decoding them.
+	if first.read() {
+		c.current = c.current[1:]
+		goto LOOP
+	}
 	err := first.r.ReadUnsignedArrayBlockAt(&first.entry, values)
 	if err != nil {
 		return nil, err
@@ -478,7 +532,7 @@ LOOP:
 				// Only use values in the overlapping window
 				v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values.Merge(v)
+				mergeUnsignedArrayWith(values, v, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -540,7 +594,7 @@ LOOP:
 			if v.Len() > 0 {
 				v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				v.Merge(values)
+				mergeUnsignedArrayWith(v, values, c.duplicates)
 				*values = *v
 			}
 			cur.markRead(minT, maxT)
@@ -558,6 +612,24 @@ func excludeTombstonesUnsignedArray(t []TimeRange, values *tsdb.UnsignedArray) {
 	}
 }
 
+// mergeUnsignedArrayWith merges b into a with policy, where the values of b
+// were written after the values of a.
+func mergeUnsignedArrayWith(a, b *tsdb.UnsignedArray, policy DuplicatePolicy) {
+	if policy == DuplicateLast {
+		a.Merge(b)
+		return
+	}
+
+	values := make(UnsignedValues, 0, a.Len()+b.Len())
+	for i, t := range a.Timestamps {
+		values = append(values, UnsignedValue{unixnano: t, value: a.Values[i]})
+	}
+	for i, t := range b.Timestamps {
+		values = append(values, UnsignedValue{unixnano: t, value: b.Values[i]})
+	}
+	*a = *NewUnsignedArrayFromValues(values.DeduplicateWith(policy))
+}
+
 // ReadStringArrayBlock reads the next block as a set of string values.
 func (c *KeyCursor) ReadStringArrayBlock(values *tsdb.StringArray) (*tsdb.StringArray, error) {
 LOOP:
@@ -570,6 +642,12 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
+
+	// Skip blocks that have been read, or summarized, without decoding them.
+	if first.read() {
+		c.current = c.current[1:]
+		goto LOOP
+	}
 	err := first.r.ReadStringArrayBlockAt(&first.entry, values)
 	if err != nil {
 		return nil, err
@@ -662,7 +740,7 @@ LOOP:
 				// Only use values in the overlapping window
 				v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values.Merge(v)
+				mergeStringArrayWith(values, v, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -724,7 +802,7 @@ LOOP:
 			if v.Len() > 0 {
 				v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				v.Merge(values)
+				mergeStringArrayWith(v, values, c.duplicates)
 				*values = *v
 			}
 			cur.markRead(minT, maxT)
@@ -742,6 +820,24 @@ func excludeTombstonesStringArray(t []TimeRange, values *tsdb.StringArray) {
 	}
 }
 
+// mergeStringArrayWith merges b into a with policy, where the values of b
+// were written after the values of a.
+func mergeStringArrayWith(a, b *tsdb.StringArray, policy DuplicatePolicy) {
+	if policy == DuplicateLast {
+		a.Merge(b)
+		return
+	}
+
+	values := make(StringValues, 0, a.Len()+b.Len())
+	for i, t := range a.Timestamps {
+		values = append(values, StringValue{unixnano: t, value: a.Values[i]})
+	}
+	for i, t := range b.Timestamps {
+		values = append(values, StringValue{unixnano: t, value: b.Values[i]})
+	}
+	*a = *NewStringArrayFromValues(values.DeduplicateWith(policy))
+}
+
 // ReadBooleanArrayBlock reads the next block as a set of boolean values.
 func (c *KeyCursor) ReadBooleanArrayBlock(values *tsdb.BooleanArray) (*tsdb.BooleanArray, error) {
 LOOP:
@@ -754,6 +850,12 @@ LOOP:
 
 	// First block is the oldest block containing the points we're searching for.
 	first := c.current[0]
+
+	// Skip blocks that have been read, or summarized, without decoding them.
+	if first.read() {
+		c.current = c.current[1:]
+		goto LOOP
+	}
 	err := first.r.ReadBooleanArrayBlockAt(&first.entry, values)
 	if err != nil {
 		return nil, err
@@ -846,7 +948,7 @@ LOOP:
 				// Only use values in the overlapping window
 				v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				values.Merge(v)
+				mergeBooleanArrayWith(values, v, c.duplicates)
 			}
 			cur.markRead(minT, maxT)
 		}
@@ -908,7 +1010,7 @@ LOOP:
 			if v.Len() > 0 {
 				v.Include(minT, maxT)
 				// Merge the remaining values with the existing
-				v.Merge(values)
+				mergeBooleanArrayWith(v, values, c.duplicates)
 				*values = *v
 			}
 			cur.markRead(minT, maxT)
@@ -925,3 +1027,21 @@ func excludeTombstonesBooleanArray(t []TimeRange, values *tsdb.BooleanArray) {
 		values.Exclude(t[i].Min, t[i].Max)
 	}
 }
+
+// mergeBooleanArrayWith merges b into a with policy, where the values of b
+// were written after the values of a.
+func mergeBooleanArrayWith(a, b *tsdb.BooleanArray, policy DuplicatePolicy) {
+	if policy == DuplicateLast {
+		a.Merge(b)
+		return
+	}
+
+	values := make(BooleanValues, 0, a.Len()+b.Len())
+	for i, t := range a.Timestamps {
+		values = append(values, BooleanValue{unixnano: t, value: a.Values[i]})
+	}
+	for i, t := range b.Timestamps {
+		values = append(values, BooleanValue{unixnano: t, value: b.Values[i]})
+	}
+	*a = *NewBooleanArrayFromValues(values.DeduplicateWith(policy))
+}
@@ -11,6 +11,7 @@ this version.
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"math"
 	"math/bits"
@@ -151,11 +152,30 @@ type FloatDecoder struct {
 	first    bool
 	finished bool
 
+	// raw holds the remaining values of zstd compressed floats.
+	zstd bool
+	raw  []byte
+
 	err error
 }
 
 // SetBytes initializes the decoder with b. Must call before calling Next().
 func (it *FloatDecoder) SetBytes(b []byte) error {
+	it.zstd, it.raw = false, nil
+	if len(b) > 0 && b[0]>>4 == floatCompressedZstd {
+		raw, err := zstdDecode(b[1:])
+		if err != nil {
+			return fmt.Errorf("failed to decode float block: %v", err.Error())
+		}
+
+		it.zstd, it.raw = true, raw
+		it.b = b
+		it.first = false
+		it.finished = false
+		it.err = nil
+		return nil
+	}
+
 	var v uint64
 	if len(b) == 0 {
 		v = uvnan
@@ -189,6 +209,16 @@ func (it *FloatDecoder) Next() bool {
 		return false
 	}
 
+	if it.zstd {
+		if len(it.raw) < 8 {
+			it.finished = true
+			return false
+		}
+		it.val = binary.BigEndian.Uint64(it.raw)
+		it.raw = it.raw[8:]
+		return true
+	}
+
 	if it.first {
 		it.first = false
 
@@ -16,8 +16,8 @@ import (
 	"github.com/freetsdb/freetsdb/pkg/tracing"
 	"github.com/freetsdb/freetsdb/pkg/tracing/fields"
 	"github.com/freetsdb/freetsdb/query"
-	"github.com/freetsdb/freetsdb/tsdb"
 	"github.com/freetsdb/freetsdb/services/influxql"
+	"github.com/freetsdb/freetsdb/tsdb"
 	"go.uber.org/zap"
 )
 
@@ -386,7 +386,7 @@ type floatAscendingCursor struct {
 func newFloatAscendingCursor(seek int64, cacheValues Values, tsmKeyCursor *KeyCursor) *floatAscendingCursor {
 	c := &floatAscendingCursor{}
 
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -446,11 +446,9 @@ func (c *floatAscendingCursor) nextFloat() (int64, float64) {
 		return tsdb.EOF, 0
 	}
 
-	// Both cache and tsm files have the same key, cache takes precedence.
+	// Both cache and tsm files have the same key, merge them with the duplicate policy.
 	if ckey == tkey {
-		c.nextCache()
-		c.nextTSM()
-		return ckey, cvalue
+		return c.nextDuplicate(ckey, cvalue, tvalue)
 	}
 
 	// Buffered cache key precedes that in TSM file.
@@ -464,6 +462,17 @@ func (c *floatAscendingCursor) nextFloat() (int64, float64) {
 	return tkey, tvalue
 }
 
+// nextDuplicate returns the value of a key held by both the cache and the TSM
+// files, merged with the duplicate policy.
+func (c *floatAscendingCursor) nextDuplicate(t int64, cvalue, tvalue float64) (int64, float64) {
+	c.nextCache()
+	c.nextTSM()
+	if cacheValueWins(c.tsm.keyCursor.duplicates, FloatValue{value: cvalue}, FloatValue{value: tvalue}) {
+		return t, cvalue
+	}
+	return t, tvalue
+}
+
 // nextCache returns the next value from the cache.
 func (c *floatAscendingCursor) nextCache() {
 	if c.cache.pos >= len(c.cache.values) {
@@ -501,7 +510,7 @@ type floatDescendingCursor struct {
 func newFloatDescendingCursor(seek int64, cacheValues Values, tsmKeyCursor *KeyCursor) *floatDescendingCursor {
 	c := &floatDescendingCursor{}
 
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -567,11 +576,9 @@ func (c *floatDescendingCursor) nextFloat() (int64, float64) {
 		return tsdb.EOF, 0
 	}
 
-	// Both cache and tsm files have the same key, cache takes precedence.
+	// Both cache and tsm files have the same key, merge them with the duplicate policy.
 	if ckey == tkey {
-		c.nextCache()
-		c.nextTSM()
-		return ckey, cvalue
+		return c.nextDuplicate(ckey, cvalue, tvalue)
 	}
 
 	// Buffered cache key precedes that in TSM file.
@@ -585,6 +592,17 @@ func (c *floatDescendingCursor) nextFloat() (int64, float64) {
 	return tkey, tvalue
 }
 
+// nextDuplicate returns the value of a key held by both the cache and the TSM
+// files, merged with the duplicate policy.
+func (c *floatDescendingCursor) nextDuplicate(t int64, cvalue, tvalue float64) (int64, float64) {
+	c.nextCache()
+	c.nextTSM()
+	if cacheValueWins(c.tsm.keyCursor.duplicates, FloatValue{value: cvalue}, FloatValue{value: tvalue}) {
+		return t, cvalue
+	}
+	return t, tvalue
+}
+
 // nextCache returns the next value from the cache.
 func (c *floatDescendingCursor) nextCache() {
 	if c.cache.pos < 0 {
@@ -864,7 +882,7 @@ type integerAscendingCursor struct {
 func newIntegerAscendingCursor(seek int64, cacheValues Values, tsmKeyCursor *KeyCursor) *integerAscendingCursor {
 	c := &integerAscendingCursor{}
 
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -924,11 +942,9 @@ func (c *integerAscendingCursor) nextInteger() (int64, int64) {
 		return tsdb.EOF, 0
 	}
 
-	// Both cache and tsm files have the same key, cache takes precedence.
+	// Both cache and tsm files have the same key, merge them with the duplicate policy.
 	if ckey == tkey {
-		c.nextCache()
-		c.nextTSM()
-		return ckey, cvalue
+		return c.nextDuplicate(ckey, cvalue, tvalue)
 	}
 
 	// Buffered cache key precedes that in TSM file.
@@ -942,6 +958,17 @@ func (c *integerAscendingCursor) nextInteger() (int64, int64) {
 	return tkey, tvalue
 }
 
+// nextDuplicate returns the value of a key held by both the cache and the TSM
+// files, merged with the duplicate policy.
+func (c *integerAscendingCursor) nextDuplicate(t int64, cvalue, tvalue int64) (int64, int64) {
+	c.nextCache()
+	c.nextTSM()
+	if cacheValueWins(c.tsm.keyCursor.duplicates, IntegerValue{value: cvalue}, IntegerValue{value: tvalue}) {
+		return t, cvalue
+	}
+	return t, tvalue
+}
+
 // nextCache returns the next value from the cache.
 func (c *integerAscendingCursor) nextCache() {
 	if c.cache.pos >= len(c.cache.values) {
@@ -979,7 +1006,7 @@ type integerDescendingCursor struct {
 func newIntegerDescendingCursor(seek int64, cacheValues Values, tsmKeyCursor *KeyCursor) *integerDescendingCursor {
 	c := &integerDescendingCursor{}
 
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -1045,11 +1072,9 @@ func (c *integerDescendingCursor) nextInteger() (int64, int64) {
 		return tsdb.EOF, 0
 	}
 
-	// Both cache and tsm files have the same key, cache takes precedence.
+	// Both cache and tsm files have the same key, merge them with the duplicate policy.
 	if ckey == tkey {
-		c.nextCache()
-		c.nextTSM()
-		return ckey, cvalue
+		return c.nextDuplicate(ckey, cvalue, tvalue)
 	}
 
 	// Buffered cache key precedes that in TSM file.
@@ -1063,6 +1088,17 @@ func (c *integerDescendingCursor) nextInteger() (int64, int64) {
 	return tkey, tvalue
 }
 
+// nextDuplicate returns the value of a key held by both the cache and the TSM
+// files, merged with the duplicate policy.
+func (c *integerDescendingCursor) nextDuplicate(t int64, cvalue, tvalue int64) (int64, int64) {
+	c.nextCache()
+	c.nextTSM()
+	if cacheValueWins(c.tsm.keyCursor.duplicates, IntegerValue{value: cvalue}, IntegerValue{value: tvalue}) {
+		return t, cvalue
+	}
+	return t, tvalue
+}
+
 // nextCache returns the next value from the cache.
 func (c *integerDescendingCursor) nextCache() {
 	if c.cache.pos < 0 {
@@ -1342,7 +1378,7 @@ type unsignedAscendingCursor struct {
 func newUnsignedAscendingCursor(seek int64, cacheValues Values, tsmKeyCursor *KeyCursor) *unsignedAscendingCursor {
 	c := &unsignedAscendingCursor{}
 
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -1402,11 +1438,9 @@ func (c *unsignedAscendingCursor) nextUnsigned() (int64, uint64) {
 		return tsdb.EOF, 0
 	}
 
-	// Both cache and tsm files have the same key, cache takes precedence.
+	// Both cache and tsm files have the same key, merge them with the duplicate policy.
 	if ckey == tkey {
-		c.nextCache()
-		c.nextTSM()
-		return ckey, cvalue
+		return c.nextDuplicate(ckey, cvalue, tvalue)
 	}
 
 	// Buffered cache key precedes that in TSM file.
@@ -1420,6 +1454,17 @@ func (c *unsignedAscendingCursor) nextUnsigned() (int64, uint64) {
 	return tkey, tvalue
 }
 
+// nextDuplicate returns the value of a key held by both the cache and the TSM
+// files, merged with the duplicate policy.
+func (c *unsignedAscendingCursor) nextDuplicate(t int64, cvalue, tvalue uint64) (int64, uint64) {
+	c.nextCache()
+	c.nextTSM()
+	if cacheValueWins(c.tsm.keyCursor.duplicates, UnsignedValue{value: cvalue}, UnsignedValue{value: tvalue}) {
+		return t, cvalue
+	}
+	return t, tvalue
+}
+
 // nextCache returns the next value from the cache.
 func (c *unsignedAscendingCursor) nextCache() {
 	if c.cache.pos >= len(c.cache.values) {
@@ -1457,7 +1502,7 @@ type unsignedDescendingCursor struct {
 func newUnsignedDescendingCursor(seek int64, cacheValues Values, tsmKeyCursor *KeyCursor) *unsignedDescendingCursor {
 	c := &unsignedDescendingCursor{}
 
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -1523,11 +1568,9 @@ func (c *unsignedDescendingCursor) nextUnsigned() (int64, uint64) {
 		return tsdb.EOF, 0
 	}
 
-	// Both cache and tsm files have the same key, cache takes precedence.
+	// Both cache and tsm files have the same key, merge them with the duplicate policy.
 	if ckey == tkey {
-		c.nextCache()
-		c.nextTSM()
-		return ckey, cvalue
+		return c.nextDuplicate(ckey, cvalue, tvalue)
 	}
 
 	// Buffered cache key precedes that in TSM file.
@@ -1541,6 +1584,17 @@ func (c *unsignedDescendingCursor) nextUnsigned() (int64, uint64) {
 	return tkey, tvalue
 }
 
+// nextDuplicate returns the value of a key held by both the cache and the TSM
+// files, merged with the duplicate policy.
+func (c *unsignedDescendingCursor) nextDuplicate(t int64, cvalue, tvalue uint64) (int64, uint64) {
+	c.nextCache()
+	c.nextTSM()
+	if cacheValueWins(c.tsm.keyCursor.duplicates, UnsignedValue{value: cvalue}, UnsignedValue{value: tvalue}) {
+		return t, cvalue
+	}
+	return t, tvalue
+}
+
 // nextCache returns the next value from the cache.
 func (c *unsignedDescendingCursor) nextCache() {
 	if c.cache.pos < 0 {
@@ -1820,7 +1874,7 @@ type stringAscendingCursor struct {
 func newStringAscendingCursor(seek int64, cacheValues Values, tsmKeyCursor *KeyCursor) *stringAscendingCursor {
 	c := &stringAscendingCursor{}
 
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -1880,11 +1934,9 @@ func (c *stringAscendingCursor) nextString() (int64, string) {
 		return tsdb.EOF, ""
 	}
 
-	// Both cache and tsm files have the same key, cache takes precedence.
+	// Both cache and tsm files have the same key, merge them with the duplicate policy.
 	if ckey == tkey {
-		c.nextCache()
-		c.nextTSM()
-		return ckey, cvalue
+		return c.nextDuplicate(ckey, cvalue, tvalue)
 	}
 
 	// Buffered cache key precedes that in TSM file.
@@ -1898,6 +1950,17 @@ func (c *stringAscendingCursor) nextString() (int64, string) {
 	return tkey, tvalue
 }
 
+// nextDuplicate returns the value of a key held by both the cache and the TSM
+// files, merged with the duplicate policy.
+func (c *stringAscendingCursor) nextDuplicate(t int64, cvalue, tvalue string) (int64, string) {
+	c.nextCache()
+	c.nextTSM()
+	if cacheValueWins(c.tsm.keyCursor.duplicates, StringValue{value: cvalue}, StringValue{value: tvalue}) {
+		return t, cvalue
+	}
+	return t, tvalue
+}
+
 // nextCache returns the next value from the cache.
 func (c *stringAscendingCursor) nextCache() {
 	if c.cache.pos >= len(c.cache.values) {
@@ -1935,7 +1998,7 @@ type stringDescendingCursor struct {
 func newStringDescendingCursor(seek int64, cacheValues Values, tsmKeyCursor *KeyCursor) *stringDescendingCursor {
 	c := &stringDescendingCursor{}
 
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -2001,11 +2064,9 @@ func (c *stringDescendingCursor) nextString() (int64, string) {
 		return tsdb.EOF, ""
 	}
 
-	// Both cache and tsm files have the same key, cache takes precedence.
+	// Both cache and tsm files have the same key, merge them with the duplicate policy.
 	if ckey == tkey {
-		c.nextCache()
-		c.nextTSM()
-		return ckey, cvalue
+		return c.nextDuplicate(ckey, cvalue, tvalue)
 	}
 
 	// Buffered cache key precedes that in TSM file.
@@ -2019,6 +2080,17 @@ func (c *stringDescendingCursor) nextString() (int64, string) {
 	return tkey, tvalue
 }
 
+// nextDuplicate returns the value of a key held by both the cache and the TSM
+// files, merged with the duplicate policy.
+func (c *stringDescendingCursor) nextDuplicate(t int64, cvalue, tvalue string) (int64, string) {
+	c.nextCache()
+	c.nextTSM()
+	if cacheValueWins(c.tsm.keyCursor.duplicates, StringValue{value: cvalue}, StringValue{value: tvalue}) {
+		return t, cvalue
+	}
+	return t, tvalue
+}
+
 // nextCache returns the next value from the cache.
 func (c *stringDescendingCursor) nextCache() {
 	if c.cache.pos < 0 {
@@ -2298,7 +2370,7 @@ type booleanAscendingCursor struct {
 func newBooleanAscendingCursor(seek int64, cacheValues Values, tsmKeyCursor *KeyCursor) *booleanAscendingCursor {
 	c := &booleanAscendingCursor{}
 
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -2358,11 +2430,9 @@ func (c *booleanAscendingCursor) nextBoolean() (int64, bool) {
 		return tsdb.EOF, false
 	}
 
-	// Both cache and tsm files have the same key, cache takes precedence.
+	// Both cache and tsm files have the same key, merge them with the duplicate policy.
 	if ckey == tkey {
-		c.nextCache()
-		c.nextTSM()
-		return ckey, cvalue
+		return c.nextDuplicate(ckey, cvalue, tvalue)
 	}
 
 	// Buffered cache key precedes that in TSM file.
@@ -2376,6 +2446,17 @@ func (c *booleanAscendingCursor) nextBoolean() (int64, bool) {
 	return tkey, tvalue
 }
 
+// nextDuplicate returns the value of a key held by both the cache and the TSM
+// files, merged with the duplicate policy.
+func (c *booleanAscendingCursor) nextDuplicate(t int64, cvalue, tvalue bool) (int64, bool) {
+	c.nextCache()
+	c.nextTSM()
+	if cacheValueWins(c.tsm.keyCursor.duplicates, BooleanValue{value: cvalue}, BooleanValue{value: tvalue}) {
+		return t, cvalue
+	}
+	return t, tvalue
+}
+
 // nextCache returns the next value from the cache.
 func (c *booleanAscendingCursor) nextCache() {
 	if c.cache.pos >= len(c.cache.values) {
@@ -2413,7 +2494,7 @@ type booleanDescendingCursor struct {
 func newBooleanDescendingCursor(seek int64, cacheValues Values, tsmKeyCursor *KeyCursor) *booleanDescendingCursor {
 	c := &booleanDescendingCursor{}
 
-	c.cache.values = cacheValues
+	c.cache.values = tsmKeyCursor.keepCacheValues(cacheValues)
 	c.cache.pos = sort.Search(len(c.cache.values), func(i int) bool {
 		return c.cache.values[i].UnixNano() >= seek
 	})
@@ -2479,11 +2560,9 @@ func (c *booleanDescendingCursor) nextBoolean() (int64, bool) {
 		return tsdb.EOF, false
 	}
 
-	// Both cache and tsm files have the same key, cache takes precedence.
+	// Both cache and tsm files have the same key, merge them with the duplicate policy.
 	if ckey == tkey {
-		c.nextCache()
-		c.nextTSM()
-		return ckey, cvalue
+		return c.nextDuplicate(ckey, cvalue, tvalue)
 	}
 
 	// Buffered cache key precedes that in TSM file.
@@ -2497,6 +2576,17 @@ func (c *booleanDescendingCursor) nextBoolean() (int64, bool) {
 	return tkey, tvalue
 }
 
+// nextDuplicate returns the value of a key held by both the cache and the TSM
+// files, merged with the duplicate policy.
+func (c *booleanDescendingCursor) nextDuplicate(t int64, cvalue, tvalue bool) (int64, bool) {
+	c.nextCache()
+	c.nextTSM()
+	if cacheValueWins(c.tsm.keyCursor.duplicates, BooleanValue{value: cvalue}, BooleanValue{value: tvalue}) {
+		return t, cvalue
+	}
+	return t, tvalue
+}
+
 // nextCache returns the next value from the cache.
 func (c *booleanDescendingCursor) nextCache() {
 	if c.cache.pos < 0 {
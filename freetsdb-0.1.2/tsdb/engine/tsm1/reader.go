@@ -203,6 +203,15 @@ func (b *BlockIterator) Read() (key []byte, minTime int64, maxTime int64, typ by
 	return b.key, b.entries[0].MinTime, b.entries[0].MaxTime, b.typ, checksum, buf, err
 }
 
+// Stats returns the statistics of the values in the next block to be
+// iterated, as recorded in its index entry.
+func (b *BlockIterator) Stats() BlockStats {
+	if len(b.entries) == 0 {
+		return BlockStats{}
+	}
+	return b.entries[0].Stats
+}
+
 // Err returns any errors encounter during iteration.
 func (b *BlockIterator) Err() error {
 	return b.err
@@ -912,7 +921,7 @@ func (d *indirectIndex) Key(idx int, entries *[]IndexEntry) ([]byte, byte, []Ind
 	ofs := binary.BigEndian.Uint32(d.offsets[idx*4 : idx*4+4])
 	n, key := readKey(d.b[ofs:])
 
-	typ := d.b[int(ofs)+n]
+	typ := d.b[int(ofs)+n] &^ indexStatsFlag
 
 	var ie indexEntries
 	if entries != nil {
@@ -940,7 +949,7 @@ func (d *indirectIndex) KeyAt(idx int) ([]byte, byte) {
 
 	n, key := readKey(d.b[ofs:])
 	ofs = ofs + int32(n)
-	typ := d.b[ofs]
+	typ := d.b[ofs] &^ indexStatsFlag
 	d.mu.RUnlock()
 	return key, typ
 }
@@ -1162,7 +1171,7 @@ func (d *indirectIndex) Type(key []byte) (byte, error) {
 	if ofs < len(d.b) {
 		n, _ := readKey(d.b[ofs:])
 		ofs += n
-		return d.b[ofs], nil
+		return d.b[ofs] &^ indexStatsFlag, nil
 	}
 	return 0, fmt.Errorf("key does not exist: %s", key)
 }
@@ -1227,6 +1236,10 @@ func (d *indirectIndex) UnmarshalBinary(b []byte) error {
 			return fmt.Errorf("indirectIndex: not enough data for key length value")
 		}
 		i += 3 + int32(binary.BigEndian.Uint16(b[i:i+2]))
+		entrySize := int32(indexEntrySize)
+		if b[i-1]&indexStatsFlag != 0 {
+			entrySize += indexStatsSize
+		}
 
 		// count of index entries
 		if i+indexCountSize >= iMax {
@@ -1244,7 +1257,7 @@ func (d *indirectIndex) UnmarshalBinary(b []byte) error {
 			minTime = minT
 		}
 
-		i += (count - 1) * indexEntrySize
+		i += (count - 1) * entrySize
 
 		// Find the max time for the block
 		if i+16 >= iMax {
@@ -1255,7 +1268,7 @@ func (d *indirectIndex) UnmarshalBinary(b []byte) error {
 			maxTime = maxT
 		}
 
-		i += indexEntrySize
+		i += entrySize
 	}
 
 	firstOfs := offsets[0]
@@ -1569,23 +1582,50 @@ func (a *indexEntries) Less(i, j int) bool {
 	return a.entries[i].MinTime < a.entries[j].MinTime
 }
 
+// hasStats returns true if any of the entries has statistics, in which case
+// all of them are encoded with statistics.
+func (a *indexEntries) hasStats() bool {
+	for i := range a.entries {
+		if a.entries[i].Stats.Count > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// entrySize returns the size in bytes of each encoded entry.
+func (a *indexEntries) entrySize() int {
+	if a.hasStats() {
+		return indexEntrySize + indexStatsSize
+	}
+	return indexEntrySize
+}
+
 func (a *indexEntries) MarshalBinary() ([]byte, error) {
-	buf := make([]byte, len(a.entries)*indexEntrySize)
+	size := a.entrySize()
+	buf := make([]byte, len(a.entries)*size)
 
 	for i, entry := range a.entries {
-		entry.AppendTo(buf[indexEntrySize*i:])
+		entry.AppendTo(buf[size*i:])
+		if size > indexEntrySize {
+			entry.Stats.appendTo(buf[size*i+indexEntrySize:])
+		}
 	}
 
 	return buf, nil
 }
 
 func (a *indexEntries) WriteTo(w io.Writer) (total int64, err error) {
-	var buf [indexEntrySize]byte
+	var buf [indexEntrySize + indexStatsSize]byte
 	var n int
 
+	size := a.entrySize()
 	for _, entry := range a.entries {
 		entry.AppendTo(buf[:])
-		n, err = w.Write(buf[:])
+		if size > indexEntrySize {
+			entry.Stats.appendTo(buf[indexEntrySize:])
+		}
+		n, err = w.Write(buf[:size])
 		total += int64(n)
 		if err != nil {
 			return total, err
@@ -1611,8 +1651,12 @@ func readEntries(b []byte, entries *indexEntries) (n int, err error) {
 		return 0, fmt.Errorf("readEntries: data too short for headers")
 	}
 
-	// 1 byte block type
-	entries.Type = b[n]
+	// 1 byte block type, with the high bit set if the entries have statistics
+	entries.Type = b[n] &^ indexStatsFlag
+	size := indexEntrySize
+	if b[n]&indexStatsFlag != 0 {
+		size += indexStatsSize
+	}
 	n++
 
 	// 2 byte count of index entries
@@ -1626,14 +1670,22 @@ func readEntries(b []byte, entries *indexEntries) (n int, err error) {
 	}
 
 	b = b[indexCountSize+indexTypeSize:]
+	if len(b) < count*size {
+		return 0, fmt.Errorf("readEntries: data too short for entries")
+	}
 	for i := 0; i < len(entries.entries); i++ {
 		if err = entries.entries[i].UnmarshalBinary(b); err != nil {
 			return 0, fmt.Errorf("readEntries: unmarshal error: %v", err)
 		}
-		b = b[indexEntrySize:]
+		if size > indexEntrySize {
+			entries.entries[i].Stats.unmarshalBinary(b[indexEntrySize:])
+		} else {
+			entries.entries[i].Stats = BlockStats{}
+		}
+		b = b[size:]
 	}
 
-	n += count * indexEntrySize
+	n += count * size
 
 	return
 }
@@ -3,7 +3,8 @@ package tsm1
 // String encoding uses snappy compression to compress each string.  Each string is
 // appended to byte slice prefixed with a variable byte length followed by the string
 // bytes.  The bytes are compressed using snappy compressor and a 1 byte header is used
-// to indicate the type of encoding.
+// to indicate the type of encoding.  The bytes may also be compressed using zstd when
+// blocks are recompressed by the compactor.
 
 import (
 	"encoding/binary"
@@ -68,14 +69,13 @@ type StringDecoder struct {
 // SetBytes initializes the decoder with bytes to read from.
 // This must be called before calling any other method.
 func (e *StringDecoder) SetBytes(b []byte) error {
-	// First byte stores the encoding type, only have snappy format
-	// currently so ignore for now.
+	// First byte stores the encoding type, snappy or zstd.
 	var data []byte
 	if len(b) > 0 {
 		var err error
-		data, err = snappy.Decode(nil, b[1:])
+		data, err = decodeStringBytes(b)
 		if err != nil {
-			return fmt.Errorf("failed to decode string block: %v", err.Error())
+			return err
 		}
 	}
 
@@ -490,6 +490,43 @@ func (l *WAL) CloseSegment() error {
 	return nil
 }
 
+// LinkSegments flushes the current segment and creates hard links to all of
+// the segment files in dir. It returns the size of each segment, by file name,
+// when it was linked. Entries are written whole under the lock, so each size
+// ends at an entry boundary even if the current segment grows afterwards.
+func (l *WAL) LinkSegments(dir string) (map[string]int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// Not loading files from disk so nothing to do
+	if l.path == "" {
+		return nil, nil
+	}
+
+	if l.currentSegmentWriter != nil {
+		if err := l.currentSegmentWriter.Flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := segmentFileNames(l.path)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]int64, len(files))
+	for _, fn := range files {
+		fi, err := os.Stat(fn)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.Link(fn, filepath.Join(dir, fi.Name())); err != nil {
+			return nil, fmt.Errorf("error creating wal hard link: %q", err)
+		}
+		sizes[fi.Name()] = fi.Size()
+	}
+	return sizes, nil
+}
+
 // Delete deletes the given keys, returning the segment ID for the operation.
 func (l *WAL) Delete(keys [][]byte) (int, error) {
 	if len(keys) == 0 {
@@ -36,7 +36,9 @@ composed of a sequence of index entries ordered lexicographically by key and
 then by time.  Each index entry starts with a key length and key followed by a
 count of the number of blocks in the file.  Each block entry is composed of
 the min and max time for the block, the offset into the file where the block
-is located and the the size of the block.
+is located and the the size of the block.  Since version 2, the high bit of the
+type is set for keys whose block entries are followed by the count, min, max
+and sum of the values in the block.
 
 The index structure can provide efficient access to all blocks as well as the
 ability to determine the cost associated with acessing a given key.  Given a key
@@ -52,6 +54,13 @@ file, we can use the size to determine how much to read in a given IO.
 │ 2 bytes │ N bytes │1 byte│2 bytes│ 8 bytes │ 8 bytes │8 bytes │4 bytes │   │
 └─────────┴─────────┴──────┴───────┴─────────┴─────────┴────────┴────────┴───┘
 
+┌───────────────────────────────────────────────────────────────────────────────┐
+│                             Block Entry With Stats                            │
+├─────────┬─────────┬─────────┬─────────┬─────────┬─────────┬─────────┬─────────┤
+│ Min Time│ Max Time│  Offset │   Size  │  Count  │   Min   │   Max   │   Sum   │
+│ 8 bytes │ 8 bytes │ 8 bytes │ 4 bytes │ 4 bytes │ 8 bytes │ 8 bytes │ 8 bytes │
+└─────────┴─────────┴─────────┴─────────┴─────────┴─────────┴─────────┴─────────┘
+
 The last section is the footer that stores the offset of the start of the index.
 
 ┌─────────┐
@@ -81,7 +90,10 @@ const (
 	MagicNumber uint32 = 0x16D116D1
 
 	// Version indicates the version of the TSM file format.
-	Version byte = 1
+	Version byte = 2
+
+	// minVersion is the oldest version of the TSM file format that can be read.
+	minVersion byte = 1
 
 	// Size in bytes of an index entry
 	indexEntrySize = 28
@@ -135,6 +147,11 @@ type TSMWriter interface {
 	// timestamp values are used as the minimum and maximum values for the index entry.
 	WriteBlock(key []byte, minTime, maxTime int64, block []byte) error
 
+	// WriteBlockWithStats writes a block like WriteBlock, recording stats in the index
+	// entry instead of decoding the block to compute them.  If stats is empty the
+	// statistics are computed from the block.
+	WriteBlockWithStats(key []byte, minTime, maxTime int64, block []byte, stats BlockStats) error
+
 	// WriteIndex finishes the TSM write streams and writes the index.
 	WriteIndex() error
 
@@ -155,6 +172,10 @@ type IndexWriter interface {
 	// Add records a new block entry for a key in the index.
 	Add(key []byte, blockType byte, minTime, maxTime int64, offset int64, size uint32)
 
+	// AddEntry records a new block entry, including its statistics, for a key
+	// in the index.
+	AddEntry(key []byte, blockType byte, entry IndexEntry)
+
 	// Entries returns all index entries for a key.
 	Entries(key []byte) []IndexEntry
 
@@ -185,6 +206,9 @@ type IndexEntry struct {
 
 	// The size in bytes of the block in the file.
 	Size uint32
+
+	// The statistics of the values in the block, if any.
+	Stats BlockStats
 }
 
 // UnmarshalBinary decodes an IndexEntry from a byte slice.
@@ -271,6 +295,15 @@ type directIndex struct {
 }
 
 func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, offset int64, size uint32) {
+	d.AddEntry(key, blockType, IndexEntry{
+		MinTime: minTime,
+		MaxTime: maxTime,
+		Offset:  offset,
+		Size:    size,
+	})
+}
+
+func (d *directIndex) AddEntry(key []byte, blockType byte, entry IndexEntry) {
 	// Is this the first block being added?
 	if len(d.key) == 0 {
 		// size of the key stored in the index
@@ -283,15 +316,7 @@ func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, of
 			d.indexEntries = &indexEntries{}
 		}
 		d.indexEntries.Type = blockType
-		d.indexEntries.entries = append(d.indexEntries.entries, IndexEntry{
-			MinTime: minTime,
-			MaxTime: maxTime,
-			Offset:  offset,
-			Size:    size,
-		})
-
-		// size of the encoded index entry
-		d.size += indexEntrySize
+		d.addEntry(entry)
 		d.keyCount++
 		return
 	}
@@ -300,15 +325,7 @@ func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, of
 	cmp := bytes.Compare(d.key, key)
 	if cmp == 0 {
 		// The last block is still this key
-		d.indexEntries.entries = append(d.indexEntries.entries, IndexEntry{
-			MinTime: minTime,
-			MaxTime: maxTime,
-			Offset:  offset,
-			Size:    size,
-		})
-
-		// size of the encoded index entry
-		d.size += indexEntrySize
+		d.addEntry(entry)
 
 	} else if cmp < 0 {
 		d.flush(d.w)
@@ -322,15 +339,7 @@ func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, of
 
 		d.key = key
 		d.indexEntries.Type = blockType
-		d.indexEntries.entries = append(d.indexEntries.entries, IndexEntry{
-			MinTime: minTime,
-			MaxTime: maxTime,
-			Offset:  offset,
-			Size:    size,
-		})
-
-		// size of the encoded index entry
-		d.size += indexEntrySize
+		d.addEntry(entry)
 		d.keyCount++
 	} else {
 		// Keys can't be added out of order.
@@ -338,6 +347,22 @@ func (d *directIndex) Add(key []byte, blockType byte, minTime, maxTime int64, of
 	}
 }
 
+// addEntry appends entry to the entries of the current key.  If the entry is
+// the first one of the key with statistics, the statistics of the previous
+// entries are accounted for as they are all encoded with statistics.
+func (d *directIndex) addEntry(entry IndexEntry) {
+	hasStats := d.indexEntries.hasStats()
+	d.indexEntries.entries = append(d.indexEntries.entries, entry)
+
+	// size of the encoded index entry
+	d.size += indexEntrySize
+	if hasStats {
+		d.size += indexStatsSize
+	} else if entry.Stats.Count > 0 {
+		d.size += uint32(d.indexEntries.Len()) * indexStatsSize
+	}
+}
+
 func (d *directIndex) entries(key []byte) []IndexEntry {
 	if len(d.key) == 0 {
 		return nil
@@ -455,6 +480,9 @@ func (d *directIndex) flush(w io.Writer) (int64, error) {
 
 	binary.BigEndian.PutUint16(buf[0:2], uint16(len(key)))
 	buf[2] = entries.Type
+	if entries.hasStats() {
+		buf[2] |= indexStatsFlag
+	}
 	binary.BigEndian.PutUint16(buf[3:5], uint16(entries.Len()))
 
 	// Append the key length and key
@@ -631,7 +659,13 @@ func (t *tsmWriter) Write(key []byte, values Values) error {
 	n += len(checksum)
 
 	// Record this block in index
-	t.index.Add(key, blockType, values[0].UnixNano(), values[len(values)-1].UnixNano(), t.n, uint32(n))
+	t.index.AddEntry(key, blockType, IndexEntry{
+		MinTime: values[0].UnixNano(),
+		MaxTime: values[len(values)-1].UnixNano(),
+		Offset:  t.n,
+		Size:    uint32(n),
+		Stats:   valuesStats(values),
+	})
 
 	// Increment file position pointer
 	t.n += int64(n)
@@ -647,6 +681,12 @@ func (t *tsmWriter) Write(key []byte, values Values) error {
 // exceeds max entries for a given key, ErrMaxBlocksExceeded is returned.  This indicates
 // that the index is now full for this key and no future writes to this key will succeed.
 func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte) error {
+	return t.WriteBlockWithStats(key, minTime, maxTime, block, BlockStats{})
+}
+
+// WriteBlockWithStats writes block like WriteBlock, using stats as the statistics of its
+// values.  The block is only decoded to compute them if stats is empty.
+func (t *tsmWriter) WriteBlockWithStats(key []byte, minTime, maxTime int64, block []byte, stats BlockStats) error {
 	if len(key) > maxKeyLength {
 		return ErrMaxKeyLengthExceeded
 	}
@@ -682,8 +722,18 @@ func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte)
 	}
 	n += len(checksum)
 
-	// Record this block in index
-	t.index.Add(key, blockType, minTime, maxTime, t.n, uint32(n))
+	// Record this block in index.  Blocks that fail to decode are still
+	// written as is, only without statistics.
+	if stats.Count == 0 {
+		stats, _ = blockStats(block)
+	}
+	t.index.AddEntry(key, blockType, IndexEntry{
+		MinTime: minTime,
+		MaxTime: maxTime,
+		Offset:  t.n,
+		Size:    uint32(n),
+		Stats:   stats,
+	})
 
 	// Increment file position pointer (checksum + block len)
 	t.n += int64(n)
@@ -798,7 +848,7 @@ func (t *tsmWriter) Size() uint32 {
 }
 
 // verifyVersion verifies that the reader's bytes are a TSM byte
-// stream of the correct version (1)
+// stream of a supported version (1 or 2)
 func verifyVersion(r io.ReadSeeker) error {
 	_, err := r.Seek(0, 0)
 	if err != nil {
@@ -816,7 +866,7 @@ func verifyVersion(r io.ReadSeeker) error {
 	if err != nil {
 		return fmt.Errorf("init: error reading version: %v", err)
 	}
-	if b[0] != Version {
+	if b[0] < minVersion || b[0] > Version {
 		return fmt.Errorf("init: file is version %b. expected %b", b[0], Version)
 	}
 
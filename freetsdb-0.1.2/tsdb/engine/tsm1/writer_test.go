@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"io"
 	"io/ioutil"
+	"math"
 	"os"
 	"testing"
 
@@ -621,3 +622,167 @@ func TestTSMWriter_Sync(t *testing.T) {
 		t.Fatal("failed to sync")
 	}
 }
+
+// Tests that the statistics of numeric blocks are stored in the index,
+// whether blocks are written from values or as encoded blocks.
+func TestTSMWriter_BlockStats(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	data := []struct {
+		key    string
+		values []tsm1.Value
+		typ    byte
+		stats  tsm1.BlockStats
+	}{
+		{"cpu", []tsm1.Value{tsm1.NewValue(0, 1.5), tsm1.NewValue(1, -2.0), tsm1.NewValue(2, 4.0)}, tsm1.BlockFloat64,
+			tsm1.BlockStats{Count: 3, Min: math.Float64bits(-2), Max: math.Float64bits(4), Sum: math.Float64bits(3.5)}},
+		{"disk", []tsm1.Value{tsm1.NewValue(0, int64(-3)), tsm1.NewValue(1, int64(7))}, tsm1.BlockInteger,
+			tsm1.BlockStats{Count: 2, Min: uint64(0xfffffffffffffffd), Max: 7, Sum: 4}},
+		{"load", []tsm1.Value{tsm1.NewValue(0, uint64(9)), tsm1.NewValue(1, uint64(2))}, tsm1.BlockUnsigned,
+			tsm1.BlockStats{Count: 2, Min: 2, Max: 9, Sum: 11}},
+		{"mem", []tsm1.Value{tsm1.NewValue(0, "a")}, tsm1.BlockString, tsm1.BlockStats{}},
+	}
+
+	verify := func(r *tsm1.TSMReader) {
+		t.Helper()
+		for _, d := range data {
+			if typ, err := r.Type([]byte(d.key)); err != nil {
+				t.Fatalf("unexpected error reading type: %v", err)
+			} else if typ != d.typ {
+				t.Fatalf("type mismatch for %s: got %v, exp %v", d.key, typ, d.typ)
+			}
+
+			entries := r.Entries([]byte(d.key))
+			if got, exp := len(entries), 1; got != exp {
+				t.Fatalf("entries length mismatch for %s: got %v, exp %v", d.key, got, exp)
+			}
+			if got, exp := entries[0].Stats, d.stats; got != exp {
+				t.Fatalf("stats mismatch for %s: got %+v, exp %+v", d.key, got, exp)
+			}
+
+			values, err := r.ReadAll([]byte(d.key))
+			if err != nil {
+				t.Fatalf("unexpected error reading: %v", err)
+			} else if got, exp := len(values), len(d.values); got != exp {
+				t.Fatalf("values length mismatch for %s: got %v, exp %v", d.key, got, exp)
+			}
+		}
+	}
+
+	// Write the values.
+	f := MustTempFile(dir)
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		t.Fatalf("unexpected error creating writer: %v", err)
+	}
+	for _, d := range data {
+		if err := w.Write([]byte(d.key), d.values); err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatalf("unexpected error writing index: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	fd, err := os.Open(f.Name())
+	if err != nil {
+		t.Fatalf("unexpected error open file: %v", err)
+	}
+	r, err := tsm1.NewTSMReader(fd)
+	if err != nil {
+		t.Fatalf("unexpected error created reader: %v", err)
+	}
+	defer r.Close()
+	verify(r)
+
+	// Copy the encoded blocks to another file.
+	f2 := MustTempFile(dir)
+	w2, err := tsm1.NewTSMWriter(f2)
+	if err != nil {
+		t.Fatalf("unexpected error creating writer: %v", err)
+	}
+	iter := r.BlockIterator()
+	for iter.Next() {
+		key, minTime, maxTime, _, _, b, err := iter.Read()
+		if err != nil {
+			t.Fatalf("unexpected error reading block: %v", err)
+		}
+		if err := w2.WriteBlock(key, minTime, maxTime, b); err != nil {
+			t.Fatalf("unexpected error writing block: %v", err)
+		}
+	}
+	if err := w2.WriteIndex(); err != nil {
+		t.Fatalf("unexpected error writing index: %v", err)
+	}
+	if err := w2.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	fd2, err := os.Open(f2.Name())
+	if err != nil {
+		t.Fatalf("unexpected error open file: %v", err)
+	}
+	r2, err := tsm1.NewTSMReader(fd2)
+	if err != nil {
+		t.Fatalf("unexpected error created reader: %v", err)
+	}
+	defer r2.Close()
+	verify(r2)
+}
+
+// Tests that blocks written with statistics keep them in the index instead
+// of having them computed from the block.
+func TestTSMWriter_WriteBlockWithStats(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	values := []tsm1.Value{tsm1.NewValue(0, 1.0), tsm1.NewValue(1, 2.0)}
+	b, err := tsm1.Values(values).Encode(nil)
+	if err != nil {
+		t.Fatalf("unexpected error encoding: %v", err)
+	}
+
+	// The statistics do not match the values, so they can only be in the
+	// index if the block was not decoded.
+	stats := tsm1.BlockStats{Count: 2, Min: math.Float64bits(-1), Max: math.Float64bits(5), Sum: math.Float64bits(4)}
+
+	f := MustTempFile(dir)
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		t.Fatalf("unexpected error creating writer: %v", err)
+	}
+	if err := w.WriteBlockWithStats([]byte("cpu"), 0, 1, b, stats); err != nil {
+		t.Fatalf("unexpected error writing block: %v", err)
+	}
+	if err := w.WriteBlockWithStats([]byte("mem"), 0, 1, b, tsm1.BlockStats{}); err != nil {
+		t.Fatalf("unexpected error writing block: %v", err)
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatalf("unexpected error writing index: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	fd, err := os.Open(f.Name())
+	if err != nil {
+		t.Fatalf("unexpected error open file: %v", err)
+	}
+	r, err := tsm1.NewTSMReader(fd)
+	if err != nil {
+		t.Fatalf("unexpected error created reader: %v", err)
+	}
+	defer r.Close()
+
+	if got, exp := r.Entries([]byte("cpu"))[0].Stats, stats; got != exp {
+		t.Fatalf("stats mismatch for cpu: got %+v, exp %+v", got, exp)
+	}
+	exp := tsm1.BlockStats{Count: 2, Min: math.Float64bits(1), Max: math.Float64bits(2), Sum: math.Float64bits(3)}
+	if got := r.Entries([]byte("mem"))[0].Stats; got != exp {
+		t.Fatalf("stats mismatch for mem: got %+v, exp %+v", got, exp)
+	}
+}
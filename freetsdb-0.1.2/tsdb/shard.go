@@ -51,6 +51,14 @@ var (
 	// ErrFieldTypeConflict is returned when a new field already exists with a different type.
 	ErrFieldTypeConflict = errors.New("field type conflict")
 
+	// ErrFieldTypeConversion is returned when the values of a field cannot be
+	// converted to the requested type.
+	ErrFieldTypeConversion = errors.New("field type conversion not supported")
+
+	// ErrFieldValueOutOfRange is returned when a value of a field cannot be
+	// represented by the type the field is converted to.
+	ErrFieldValueOutOfRange = errors.New("field value out of range")
+
 	// ErrFieldNotFound is returned when a field cannot be found.
 	ErrFieldNotFound = errors.New("field not found")
 
@@ -222,6 +230,31 @@ func (s *Shard) ScheduleFullCompaction() error {
 	return engine.ScheduleFullCompaction()
 }
 
+// ScheduleOptimizeCompaction forces an optimize compaction to be scheduled on the shard.
+func (s *Shard) ScheduleOptimizeCompaction() error {
+	engine, err := s.Engine()
+	if err != nil {
+		return err
+	}
+	return engine.ScheduleOptimizeCompaction()
+}
+
+// Compactions returns the compactions running on the shard.
+func (s *Shard) Compactions() []CompactionInfo {
+	engine, err := s.Engine()
+	if err != nil {
+		return nil
+	}
+
+	compactions := engine.Compactions()
+	for i := range compactions {
+		compactions[i].ShardID = s.id
+		compactions[i].Database = s.database
+		compactions[i].RetentionPolicy = s.retentionPolicy
+	}
+	return compactions
+}
+
 // ID returns the shards ID.
 func (s *Shard) ID() uint64 {
 	return s.id
@@ -294,7 +327,11 @@ func (s *Shard) Statistics(tags map[string]string) []models.Statistic {
 }
 
 // Path returns the path set on the shard when it was created.
-func (s *Shard) Path() string { return s.path }
+func (s *Shard) Path() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.path
+}
 
 // Open initializes and opens the shard's store.
 func (s *Shard) Open() error {
@@ -361,6 +398,27 @@ func (s *Shard) Open() error {
 	return nil
 }
 
+// Relocate closes the shard, moves its data directory to path and reopens it
+// from there. The WAL directory stays in place. The shard is reopened from its
+// previous path if the move fails.
+func (s *Shard) Relocate(path string) error {
+	s.mu.Lock()
+	enabled := s.enabled
+	err := s.close()
+	if err == nil {
+		if err = moveDir(s.path, path); err == nil {
+			s.path = path
+		}
+	}
+	s.mu.Unlock()
+
+	if oerr := s.Open(); err == nil {
+		err = oerr
+	}
+	s.SetEnabled(enabled)
+	return err
+}
+
 // Close shuts down the shard's store.
 func (s *Shard) Close() error {
 	s.mu.Lock()
@@ -472,6 +530,25 @@ func (s *Shard) SetCompactionsEnabled(enabled bool) {
 	engine.SetCompactionsEnabled(enabled)
 }
 
+// SetCompactionsPaused pauses or resumes shard background compactions.
+func (s *Shard) SetCompactionsPaused(paused bool) {
+	engine, err := s.Engine()
+	if err != nil {
+		return
+	}
+	engine.SetCompactionsPaused(paused)
+}
+
+// SetDuplicatePolicy sets the policy merging points of a series with the same
+// timestamp.
+func (s *Shard) SetDuplicatePolicy(policy string) {
+	engine, err := s.Engine()
+	if err != nil {
+		return
+	}
+	engine.SetDuplicatePolicy(policy)
+}
+
 // DiskSize returns the size on disk of this shard.
 func (s *Shard) DiskSize() (int64, error) {
 	s.mu.RLock()
@@ -743,6 +820,16 @@ func (s *Shard) DeleteMeasurement(name []byte) error {
 	return engine.DeleteMeasurement(name)
 }
 
+// ConvertFieldType converts the values of a field of a measurement to typ.
+// Writes to the shard are blocked while the engine switches the field type.
+func (s *Shard) ConvertFieldType(measurement, field []byte, typ influxql.DataType) error {
+	engine, err := s.Engine()
+	if err != nil {
+		return err
+	}
+	return engine.ConvertFieldType(measurement, field, typ, &s.mu)
+}
+
 // SeriesN returns the unique number of series in the shard.
 func (s *Shard) SeriesN() int64 {
 	engine, err := s.Engine()
@@ -1071,6 +1158,26 @@ func (s *Shard) Export(w io.Writer, basePath string, start time.Time, end time.T
 	return engine.Export(w, basePath, start, end)
 }
 
+// ExportMeasurement writes a tar archive of the data of the measurement name
+// in the shard. See Engine.ExportMeasurement for more details.
+func (s *Shard) ExportMeasurement(w io.Writer, basePath string, name []byte) error {
+	engine, err := s.Engine()
+	if err != nil {
+		return err
+	}
+	return engine.ExportMeasurement(w, basePath, name)
+}
+
+// BackupIncremental writes a tar archive of the data added to the shard since
+// the position from. See Engine.BackupIncremental for more details.
+func (s *Shard) BackupIncremental(w io.Writer, basePath string, from *BackupPosition) error {
+	engine, err := s.Engine()
+	if err != nil {
+		return err
+	}
+	return engine.BackupIncremental(w, basePath, from)
+}
+
 // Restore restores data to the underlying engine for the shard.
 // The shard is reopened after restore.
 func (s *Shard) Restore(r io.Reader, basePath string) error {
@@ -1549,6 +1656,43 @@ func (m *MeasurementFields) CreateFieldIfNotExists(name []byte, typ influxql.Dat
 	return nil
 }
 
+// SetFieldType changes the type of an existing field.  Returns
+// ErrFieldNotFound if the field does not exist.
+func (m *MeasurementFields) SetFieldType(name []byte, typ influxql.DataType) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fields := m.fields.Load().(map[string]*Field)
+	f := fields[string(name)]
+	if f == nil {
+		return ErrFieldNotFound
+	}
+
+	fieldsUpdate := make(map[string]*Field, len(fields))
+	for k, v := range fields {
+		fieldsUpdate[k] = v
+	}
+	fieldsUpdate[string(name)] = &Field{ID: f.ID, Name: f.Name, Type: typ}
+	m.fields.Store(fieldsUpdate)
+
+	return nil
+}
+
+// CanConvertFieldType returns true if values of type from can be converted to
+// type to.  Only numeric types can be converted.
+func CanConvertFieldType(from, to influxql.DataType) bool {
+	switch from {
+	case influxql.Float, influxql.Integer, influxql.Unsigned:
+	default:
+		return false
+	}
+	switch to {
+	case influxql.Float, influxql.Integer, influxql.Unsigned:
+		return true
+	}
+	return false
+}
+
 func (m *MeasurementFields) FieldN() int {
 	n := len(m.fields.Load().(map[string]*Field))
 	return n
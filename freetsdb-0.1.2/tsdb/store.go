@@ -36,6 +36,15 @@ var (
 	// ErrMultipleIndexTypes is returned when trying to do deletes on a database with
 	// multiple index types.
 	ErrMultipleIndexTypes = errors.New("cannot delete data. DB contains shards using both inmem and tsi1 indexes. Please convert all shards to use the same index type to delete data.")
+	// ErrColdDirNotSet is returned when moving a shard to the cold tier
+	// without a cold dir configured.
+	ErrColdDirNotSet = errors.New("cold dir not set")
+	// ErrTrashEntryNotFound is returned when undropping data that is not in
+	// the trash.
+	ErrTrashEntryNotFound = errors.New("trash entry not found")
+	// ErrCompactionsPaused is returned when compacting a shard of a database
+	// whose compactions are paused.
+	ErrCompactionsPaused = errors.New("compactions are paused")
 )
 
 // Statistics gathered by the store.
@@ -92,6 +101,10 @@ type Store struct {
 	// is stored by shard.
 	epochs map[uint64]*epochTracker
 
+	// Databases whose compactions are paused.  New shards of these databases
+	// start with their compactions paused.
+	pausedCompactions map[string]struct{}
+
 	EngineOptions EngineOptions
 
 	baseLogger *zap.Logger
@@ -113,6 +126,7 @@ func NewStore(path string) *Store {
 		indexes:             make(map[string]interface{}),
 		pendingShardDeletes: make(map[uint64]struct{}),
 		epochs:              make(map[uint64]*epochTracker),
+		pausedCompactions:   make(map[string]struct{}),
 		EngineOptions:       NewEngineOptions(),
 		Logger:              logger,
 		baseLogger:          logger,
@@ -197,6 +211,10 @@ func (s *Store) IndexBytes() int {
 // Path returns the store's root path.
 func (s *Store) Path() string { return s.path }
 
+// ColdPath returns the directory shards are moved to when they become cold,
+// or an empty string if shards are never moved.
+func (s *Store) ColdPath() string { return s.EngineOptions.Config.ColdDir }
+
 // Open initializes the store, creating all necessary directories, loading all
 // shards as well as initializing periodic maintenance of them.
 func (s *Store) Open() error {
@@ -217,6 +235,12 @@ func (s *Store) Open() error {
 	if err := os.MkdirAll(s.path, 0777); err != nil {
 		return err
 	}
+	if coldDir := s.ColdPath(); coldDir != "" {
+		s.Logger.Info("Using cold dir", zap.String("path", coldDir))
+		if err := os.MkdirAll(coldDir, 0777); err != nil {
+			return err
+		}
+	}
 
 	if err := s.loadShards(); err != nil {
 		return err
@@ -293,124 +317,152 @@ func (s *Store) loadShards() error {
 	resC := make(chan *res)
 	var n int
 
-	// Determine how many shards we need to open by checking the store path.
-	dbDirs, err := ioutil.ReadDir(s.path)
-	if err != nil {
-		return err
+	// Determine how many shards we need to open by checking the store path
+	// and the cold dir.
+	coldDir := s.EngineOptions.Config.ColdDir
+	dirs := []string{s.path}
+	if coldDir != "" {
+		dirs = append(dirs, coldDir)
 	}
 
-	for _, db := range dbDirs {
-		dbPath := filepath.Join(s.path, db.Name())
-		if !db.IsDir() {
-			log.Info("Skipping database dir", zap.String("name", db.Name()), zap.String("reason", "not a directory"))
-			continue
-		}
-
-		if s.EngineOptions.DatabaseFilter != nil && !s.EngineOptions.DatabaseFilter(db.Name()) {
-			log.Info("Skipping database dir", logger.Database(db.Name()), zap.String("reason", "failed database filter"))
-			continue
-		}
-
-		// Load series file.
-		sfile, err := s.openSeriesFile(db.Name())
-		if err != nil {
-			return err
-		}
-
-		// Retrieve database index.
-		idx, err := s.createIndexIfNotExists(db.Name())
-		if err != nil {
-			return err
-		}
-
-		// Load each retention policy within the database directory.
-		rpDirs, err := ioutil.ReadDir(dbPath)
+	for _, dir := range dirs {
+		dbDirs, err := ioutil.ReadDir(dir)
 		if err != nil {
 			return err
 		}
 
-		for _, rp := range rpDirs {
-			rpPath := filepath.Join(s.path, db.Name(), rp.Name())
-			if !rp.IsDir() {
-				log.Info("Skipping retention policy dir", zap.String("name", rp.Name()), zap.String("reason", "not a directory"))
+		for _, db := range dbDirs {
+			dbPath := filepath.Join(dir, db.Name())
+			if !db.IsDir() {
+				log.Info("Skipping database dir", zap.String("name", db.Name()), zap.String("reason", "not a directory"))
 				continue
 			}
 
-			// The .series directory is not a retention policy.
-			if rp.Name() == SeriesFileDirectory {
+			if s.EngineOptions.DatabaseFilter != nil && !s.EngineOptions.DatabaseFilter(db.Name()) {
+				log.Info("Skipping database dir", logger.Database(db.Name()), zap.String("reason", "failed database filter"))
 				continue
 			}
 
-			if s.EngineOptions.RetentionPolicyFilter != nil && !s.EngineOptions.RetentionPolicyFilter(db.Name(), rp.Name()) {
-				log.Info("Skipping retention policy dir", logger.RetentionPolicy(rp.Name()), zap.String("reason", "failed retention policy filter"))
-				continue
+			// Load series file.
+			sfile, err := s.openSeriesFile(db.Name())
+			if err != nil {
+				return err
 			}
 
-			shardDirs, err := ioutil.ReadDir(rpPath)
+			// Retrieve database index.
+			idx, err := s.createIndexIfNotExists(db.Name())
 			if err != nil {
 				return err
 			}
 
-			for _, sh := range shardDirs {
-				// Series file should not be in a retention policy but skip just in case.
-				if sh.Name() == SeriesFileDirectory {
-					log.Warn("Skipping series file in retention policy dir", zap.String("path", filepath.Join(s.path, db.Name(), rp.Name())))
+			// Load each retention policy within the database directory.
+			rpDirs, err := ioutil.ReadDir(dbPath)
+			if err != nil {
+				return err
+			}
+
+			for _, rp := range rpDirs {
+				rpPath := filepath.Join(dir, db.Name(), rp.Name())
+				if !rp.IsDir() {
+					log.Info("Skipping retention policy dir", zap.String("name", rp.Name()), zap.String("reason", "not a directory"))
 					continue
 				}
 
-				n++
-				go func(db, rp, sh string) {
-					t.Take()
-					defer t.Release()
+				// The .series directory is not a retention policy.
+				if rp.Name() == SeriesFileDirectory {
+					continue
+				}
 
-					start := time.Now()
-					path := filepath.Join(s.path, db, rp, sh)
-					walPath := filepath.Join(s.EngineOptions.Config.WALDir, db, rp, sh)
+				if s.EngineOptions.RetentionPolicyFilter != nil && !s.EngineOptions.RetentionPolicyFilter(db.Name(), rp.Name()) {
+					log.Info("Skipping retention policy dir", logger.RetentionPolicy(rp.Name()), zap.String("reason", "failed retention policy filter"))
+					continue
+				}
 
-					// Shard file names are numeric shardIDs
-					shardID, err := strconv.ParseUint(sh, 10, 64)
-					if err != nil {
-						log.Info("invalid shard ID found at path", zap.String("path", path))
-						resC <- &res{err: fmt.Errorf("%s is not a valid ID. Skipping shard.", sh)}
-						return
+				shardDirs, err := ioutil.ReadDir(rpPath)
+				if err != nil {
+					return err
+				}
+
+				for _, sh := range shardDirs {
+					// Series file should not be in a retention policy but skip just in case.
+					if sh.Name() == SeriesFileDirectory {
+						log.Warn("Skipping series file in retention policy dir", zap.String("path", rpPath))
+						continue
 					}
 
-					if s.EngineOptions.ShardFilter != nil && !s.EngineOptions.ShardFilter(db, rp, shardID) {
-						log.Info("skipping shard", zap.String("path", path), logger.Shard(shardID))
-						resC <- &res{}
-						return
+					// A shard copied to the cold dir is only complete once renamed
+					// into place. Remove partial copies and the data dir copy of a
+					// shard whose move was interrupted before it was removed.
+					if strings.HasSuffix(sh.Name(), shardMoveSuffix) {
+						log.Info("Removing partial shard copy", zap.String("path", filepath.Join(rpPath, sh.Name())))
+						if err := os.RemoveAll(filepath.Join(rpPath, sh.Name())); err != nil {
+							return err
+						}
+						continue
+					} else if coldDir != "" && dir != coldDir {
+						if _, err := os.Stat(filepath.Join(coldDir, db.Name(), rp.Name(), sh.Name())); err == nil {
+							log.Info("Removing shard copy moved to cold dir", zap.String("path", filepath.Join(rpPath, sh.Name())))
+							if err := os.RemoveAll(filepath.Join(rpPath, sh.Name())); err != nil {
+								return err
+							}
+							continue
+						}
 					}
 
-					// Copy options and assign shared index.
-					opt := s.EngineOptions
-					opt.InmemIndex = idx
+					n++
+					go func(dir, db, rp, sh string) {
+						t.Take()
+						defer t.Release()
+
+						start := time.Now()
+						path := filepath.Join(dir, db, rp, sh)
+						walPath := filepath.Join(s.EngineOptions.Config.WALDir, db, rp, sh)
+
+						// Shard file names are numeric shardIDs
+						shardID, err := strconv.ParseUint(sh, 10, 64)
+						if err != nil {
+							log.Info("invalid shard ID found at path", zap.String("path", path))
+							resC <- &res{err: fmt.Errorf("%s is not a valid ID. Skipping shard.", sh)}
+							return
+						}
+
+						if s.EngineOptions.ShardFilter != nil && !s.EngineOptions.ShardFilter(db, rp, shardID) {
+							log.Info("skipping shard", zap.String("path", path), logger.Shard(shardID))
+							resC <- &res{}
+							return
+						}
 
-					// Provide an implementation of the ShardIDSets
-					opt.SeriesIDSets = shardSet{store: s, db: db}
+						// Copy options and assign shared index.
+						opt := s.EngineOptions
+						opt.InmemIndex = idx
 
-					// Existing shards should continue to use inmem index.
-					if _, err := os.Stat(filepath.Join(path, "index")); os.IsNotExist(err) {
-						opt.IndexVersion = InmemIndexName
-					}
+						// Provide an implementation of the ShardIDSets
+						opt.SeriesIDSets = shardSet{store: s, db: db}
 
-					// Open engine.
-					shard := NewShard(shardID, path, walPath, sfile, opt)
+						// Existing shards should continue to use inmem index.
+						if _, err := os.Stat(filepath.Join(path, "index")); os.IsNotExist(err) {
+							opt.IndexVersion = InmemIndexName
+						}
 
-					// Disable compactions, writes and queries until all shards are loaded
-					shard.EnableOnOpen = false
-					shard.CompactionDisabled = s.EngineOptions.CompactionDisabled
-					shard.WithLogger(s.baseLogger)
+						// Open engine.
+						shard := NewShard(shardID, path, walPath, sfile, opt)
 
-					err = shard.Open()
-					if err != nil {
-						log.Info("Failed to open shard", logger.Shard(shardID), zap.Error(err))
-						resC <- &res{err: fmt.Errorf("Failed to open shard: %d: %s", shardID, err)}
-						return
-					}
+						// Disable compactions, writes and queries until all shards are loaded
+						shard.EnableOnOpen = false
+						shard.CompactionDisabled = s.EngineOptions.CompactionDisabled
+						shard.WithLogger(s.baseLogger)
 
-					resC <- &res{s: shard}
-					log.Info("Opened shard", zap.String("index_version", shard.IndexType()), zap.String("path", path), zap.Duration("duration", time.Since(start)))
-				}(db.Name(), rp.Name(), sh.Name())
+						err = shard.Open()
+						if err != nil {
+							log.Info("Failed to open shard", logger.Shard(shardID), zap.Error(err))
+							resC <- &res{err: fmt.Errorf("Failed to open shard: %d: %s", shardID, err)}
+							return
+						}
+
+						resC <- &res{s: shard}
+						log.Info("Opened shard", zap.String("index_version", shard.IndexType()), zap.String("path", path), zap.Duration("duration", time.Since(start)))
+					}(dir, db.Name(), rp.Name(), sh.Name())
+				}
 			}
 		}
 	}
@@ -643,6 +695,9 @@ func (s *Store) CreateShard(database, retentionPolicy string, shardID uint64, en
 	if err := shard.Open(); err != nil {
 		return err
 	}
+	if _, ok := s.pausedCompactions[database]; ok {
+		shard.SetCompactionsPaused(true)
+	}
 
 	s.shards[shardID] = shard
 	s.epochs[shardID] = newEpochTracker()
@@ -661,6 +716,51 @@ func (s *Store) CreateShard(database, retentionPolicy string, shardID uint64, en
 	return nil
 }
 
+// ShardTier returns the storage tier of a shard, or an empty string if the
+// shard does not exist.
+func (s *Store) ShardTier(id uint64) string {
+	sh := s.Shard(id)
+	if sh == nil {
+		return ""
+	}
+	return s.shardTier(sh)
+}
+
+// shardTier returns the storage tier of sh.
+func (s *Store) shardTier(sh *Shard) string {
+	if coldDir := s.ColdPath(); coldDir != "" && filepath.Clean(coldDir) == shardRoot(sh) {
+		return ShardTierCold
+	}
+	return ShardTierHot
+}
+
+// MoveShardToCold moves the data files of a fully compacted shard to the cold
+// dir. The shard cannot be queried while it moves. Moving a shard already in
+// the cold dir is a no-op.
+func (s *Store) MoveShardToCold(id uint64) error {
+	coldDir := s.ColdPath()
+	if coldDir == "" {
+		return ErrColdDirNotSet
+	}
+
+	sh := s.Shard(id)
+	if sh == nil {
+		return ErrShardNotFound
+	} else if s.shardTier(sh) == ShardTierCold {
+		return nil
+	} else if !sh.IsIdle() {
+		return ErrShardNotIdle
+	}
+
+	path := filepath.Join(coldDir, sh.database, sh.retentionPolicy, strconv.FormatUint(id, 10))
+	if err := sh.Relocate(path); err != nil {
+		return err
+	}
+
+	s.Logger.Info("Moved shard to cold dir", logger.Shard(id), zap.String("path", path))
+	return nil
+}
+
 // CreateShardSnapShot will create a hard link to the underlying shard and return a path.
 // The caller is responsible for cleaning up (removing) the file path returned.
 func (s *Store) CreateShardSnapshot(id uint64) (string, error) {
@@ -682,8 +782,87 @@ func (s *Store) SetShardEnabled(shardID uint64, enabled bool) error {
 	return nil
 }
 
+// CompactShard schedules a full compaction of a shard, or an optimize
+// compaction of its fully compacted files if optimize is true.
+func (s *Store) CompactShard(shardID uint64, optimize bool) error {
+	s.mu.RLock()
+	sh := s.shards[shardID]
+	var paused bool
+	if sh != nil {
+		_, paused = s.pausedCompactions[sh.database]
+	}
+	s.mu.RUnlock()
+
+	if sh == nil {
+		return ErrShardNotFound
+	} else if paused {
+		return ErrCompactionsPaused
+	}
+
+	if optimize {
+		return sh.ScheduleOptimizeCompaction()
+	}
+	return sh.ScheduleFullCompaction()
+}
+
+// SetCompactionsPaused pauses or resumes the compactions of every shard of a
+// database, including shards created while they are paused.  Snapshots of
+// the caches keep running while compactions are paused.  The pause is kept in
+// memory only and does not survive a restart.
+func (s *Store) SetCompactionsPaused(database string, paused bool) {
+	s.mu.Lock()
+	if paused {
+		s.pausedCompactions[database] = struct{}{}
+	} else {
+		delete(s.pausedCompactions, database)
+	}
+	shards := s.filterShards(byDatabase(database))
+	s.mu.Unlock()
+
+	for _, sh := range shards {
+		sh.SetCompactionsPaused(paused)
+	}
+}
+
+// UpdateDuplicatePolicies resolves the duplicate policy of every shard again
+// using EngineOptions.DuplicatePolicy.  It should be called when the duplicate
+// policy of a retention policy may have changed.
+func (s *Store) UpdateDuplicatePolicies() {
+	if s.EngineOptions.DuplicatePolicy == nil {
+		return
+	}
+
+	s.mu.RLock()
+	shards := s.shardsSlice()
+	s.mu.RUnlock()
+
+	for _, sh := range shards {
+		sh.SetDuplicatePolicy(s.EngineOptions.DuplicatePolicy(sh.Database(), sh.RetentionPolicy()))
+	}
+}
+
+// Compactions returns the compactions running on the shards of the store,
+// ordered by shard.
+func (s *Store) Compactions() []CompactionInfo {
+	s.mu.RLock()
+	shards := s.shardsSlice()
+	s.mu.RUnlock()
+
+	var compactions []CompactionInfo
+	for _, sh := range shards {
+		compactions = append(compactions, sh.Compactions()...)
+	}
+	return compactions
+}
+
 // DeleteShard removes a shard from disk.
 func (s *Store) DeleteShard(shardID uint64) error {
+	return s.deleteShard(shardID, "")
+}
+
+// deleteShard removes a shard from the store. Its files are moved to the
+// trash entry directory trash, or deleted if trash is empty.
+func (s *Store) deleteShard(shardID uint64, trash string) error {
 	sh := s.Shard(shardID)
 	if sh == nil {
 		return nil
@@ -720,6 +899,18 @@ func (s *Store) DeleteShard(shardID uint64) error {
 		s.databases[db].removeIndexType(sh.IndexType())
 	}()
 
+	// The series of a trashed shard stay in the series file, so the shard
+	// index still matches it if the shard is restored.
+	if trash != "" {
+		if err := sh.Close(); err != nil {
+			return err
+		}
+		if err := moveDir(sh.path, filepath.Join(trash, trashDataDir)); err != nil {
+			return err
+		}
+		return moveDirIfExists(sh.walPath, filepath.Join(trash, trashWALDir))
+	}
+
 	// Get the shard's local bitset of series IDs.
 	index, err := sh.Index()
 	if err != nil {
@@ -791,6 +982,13 @@ func (s *Store) DeleteShard(shardID uint64) error {
 
 // DeleteDatabase will close all shards associated with a database and remove the directory and files from disk.
 func (s *Store) DeleteDatabase(name string) error {
+	return s.deleteDatabase(name, "")
+}
+
+// deleteDatabase closes all shards of a database and removes it from the
+// store. Its files are moved to the trash entry directory trash, or deleted
+// if trash is empty.
+func (s *Store) deleteDatabase(name, trash string) error {
 	s.mu.RLock()
 	if _, ok := s.databases[name]; !ok {
 		s.mu.RUnlock()
@@ -833,10 +1031,11 @@ func (s *Store) DeleteDatabase(name string) error {
 		return fmt.Errorf("invalid database directory location for database '%s': %s", name, dbPath)
 	}
 
-	if err := os.RemoveAll(dbPath); err != nil {
-		return err
-	}
-	if err := os.RemoveAll(filepath.Join(s.EngineOptions.Config.WALDir, name)); err != nil {
+	if trash != "" {
+		if err := s.trashDatabaseFiles(name, trash); err != nil {
+			return err
+		}
+	} else if err := s.removeDatabaseFiles(name); err != nil {
 		return err
 	}
 
@@ -847,6 +1046,7 @@ func (s *Store) DeleteDatabase(name string) error {
 
 	// Remove database from store list of databases
 	delete(s.databases, name)
+	delete(s.pausedCompactions, name)
 
 	// Remove shared index for database if using inmem index.
 	delete(s.indexes, name)
@@ -854,6 +1054,23 @@ func (s *Store) DeleteDatabase(name string) error {
 	return nil
 }
 
+// removeDatabaseFiles removes the data, WAL and cold directories of a
+// database from disk.
+func (s *Store) removeDatabaseFiles(name string) error {
+	if err := os.RemoveAll(filepath.Join(s.path, name)); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(s.EngineOptions.Config.WALDir, name)); err != nil {
+		return err
+	}
+	if coldDir := s.ColdPath(); coldDir != "" && filepath.Clean(coldDir) == filepath.Dir(filepath.Clean(filepath.Join(coldDir, name))) {
+		if err := os.RemoveAll(filepath.Join(coldDir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DeleteRetentionPolicy will close all shards associated with the
 // provided retention policy, remove the retention policy directories on
 // both the DB and WAL, and remove all shard files from disk.
@@ -899,6 +1116,13 @@ func (s *Store) DeleteRetentionPolicy(database, name string) error {
 		return err
 	}
 
+	// Remove the retention policy folder from the cold dir.
+	if coldDir := s.ColdPath(); coldDir != "" {
+		if err := os.RemoveAll(filepath.Join(coldDir, database, name)); err != nil {
+			return err
+		}
+	}
+
 	s.mu.Lock()
 	state := s.databases[database]
 	for _, sh := range shards {
@@ -937,6 +1161,25 @@ func (s *Store) DeleteMeasurement(database, name string) error {
 	})
 }
 
+// ConvertFieldType converts the values of a field of a measurement to typ in
+// every shard of a database.  Shards are converted one at a time and queries
+// cast the values of the shards not converted yet.
+func (s *Store) ConvertFieldType(database, measurement, field string, typ influxql.DataType) error {
+	s.mu.RLock()
+	shards := s.filterShards(byDatabase(database))
+	s.mu.RUnlock()
+
+	// Limit to 1 conversion at a time since each one rewrites the TSM files
+	// of the shard holding the field.
+	limit := limiter.NewFixed(1)
+	return s.walkShards(shards, func(sh *Shard) error {
+		limit.Take()
+		defer limit.Release()
+
+		return sh.ConvertFieldType([]byte(measurement), []byte(field), typ)
+	})
+}
+
 // filterShards returns a slice of shards where fn returns true
 // for the shard. If the provided predicate is nil then all shards are returned.
 // filterShards should be called under a lock.
@@ -1181,7 +1424,7 @@ func (s *Store) BackupShard(id uint64, since time.Time, w io.Writer) error {
 		return fmt.Errorf("shard %d doesn't exist on this server", id)
 	}
 
-	path, err := relativePath(s.path, shard.path)
+	path, err := relativePath(shardRoot(shard), shard.Path())
 	if err != nil {
 		return err
 	}
@@ -1195,7 +1438,7 @@ func (s *Store) ExportShard(id uint64, start time.Time, end time.Time, w io.Writ
 		return fmt.Errorf("shard %d doesn't exist on this server", id)
 	}
 
-	path, err := relativePath(s.path, shard.path)
+	path, err := relativePath(shardRoot(shard), shard.Path())
 	if err != nil {
 		return err
 	}
@@ -1203,6 +1446,23 @@ func (s *Store) ExportShard(id uint64, start time.Time, end time.Time, w io.Writ
 	return shard.Export(w, path, start, end)
 }
 
+// BackupShardIncremental will get the shard and have the engine back up the
+// data added since the position from to the writer. A nil position backs up
+// the whole shard.
+func (s *Store) BackupShardIncremental(id uint64, from *BackupPosition, w io.Writer) error {
+	shard := s.Shard(id)
+	if shard == nil {
+		return fmt.Errorf("shard %d doesn't exist on this server", id)
+	}
+
+	path, err := relativePath(shardRoot(shard), shard.Path())
+	if err != nil {
+		return err
+	}
+
+	return shard.BackupIncremental(w, path, from)
+}
+
 // RestoreShard restores a backup from r to a given shard.
 // This will only overwrite files included in the backup.
 func (s *Store) RestoreShard(id uint64, r io.Reader) error {
@@ -1211,7 +1471,7 @@ func (s *Store) RestoreShard(id uint64, r io.Reader) error {
 		return fmt.Errorf("shard %d doesn't exist on this server", id)
 	}
 
-	path, err := relativePath(s.path, shard.path)
+	path, err := relativePath(shardRoot(shard), shard.Path())
 	if err != nil {
 		return err
 	}
@@ -1229,7 +1489,7 @@ func (s *Store) ImportShard(id uint64, r io.Reader) error {
 		return fmt.Errorf("shard %d doesn't exist on this server", id)
 	}
 
-	path, err := relativePath(s.path, shard.path)
+	path, err := relativePath(shardRoot(shard), shard.Path())
 	if err != nil {
 		return err
 	}
@@ -1244,7 +1504,7 @@ func (s *Store) ShardRelativePath(id uint64) (string, error) {
 	if shard == nil {
 		return "", fmt.Errorf("shard %d doesn't exist on this server", id)
 	}
-	return relativePath(s.path, shard.path)
+	return relativePath(shardRoot(shard), shard.Path())
 }
 
 // DeleteSeries loops through the local shards and deletes the series data for
@@ -2044,6 +2304,11 @@ func decodeStorePath(shardOrWALPath string) (database, retentionPolicy string) {
 	return db, rp
 }
 
+// shardRoot returns the directory holding the database directory of sh.
+func shardRoot(sh *Shard) string {
+	return filepath.Dir(filepath.Dir(filepath.Dir(filepath.Clean(sh.Path()))))
+}
+
 // relativePath will expand out the full paths passed in and return
 // the relative shard path from the store
 func relativePath(storePath, shardPath string) (string, error) {
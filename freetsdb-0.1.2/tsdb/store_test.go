@@ -279,6 +279,272 @@ func TestStore_CreateShardSnapShot(t *testing.T) {
 	}
 }
 
+// Ensure the store moves idle shards to the cold dir and opens them from there.
+func TestStore_MoveShardToCold(t *testing.T) {
+	t.Parallel()
+
+	test := func(index string) {
+		coldDir, err := ioutil.TempDir("", "freetsdb-tsdb-cold-")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(coldDir)
+
+		s := NewStore(index)
+		s.EngineOptions.Config.ColdDir = coldDir
+		if err := s.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+
+		s.MustCreateShardWithData("db0", "rp0", 1, "cpu,host=serverA value=1 0")
+		if tier := s.ShardTier(1); tier != tsdb.ShardTierHot {
+			t.Fatalf("unexpected tier: %q", tier)
+		}
+
+		// Data still in the cache keeps the shard in the data dir.
+		if err := s.MoveShardToCold(1); err != tsdb.ErrShardNotIdle {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		sh := s.Shard(1)
+		if err := sh.ScheduleFullCompaction(); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; !sh.IsIdle(); i++ {
+			if i == 100 {
+				t.Fatal("shard not idle")
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		if err := s.MoveShardToCold(1); err != nil {
+			t.Fatal(err)
+		} else if tier := s.ShardTier(1); tier != tsdb.ShardTierCold {
+			t.Fatalf("unexpected tier: %q", tier)
+		} else if dirExists(filepath.Join(s.Path(), "db0", "rp0", "1")) {
+			t.Fatal("shard still in data dir")
+		} else if !dirExists(filepath.Join(coldDir, "db0", "rp0", "1")) {
+			t.Fatal("shard not in cold dir")
+		} else if path, err := s.ShardRelativePath(1); err != nil || path != filepath.Join("db0", "rp0", "1") {
+			t.Fatalf("unexpected relative path: %q (%v)", path, err)
+		}
+
+		// The shard is loaded from the cold dir on open.
+		if err := s.Reopen(); err != nil {
+			t.Fatal(err)
+		} else if tier := s.ShardTier(1); tier != tsdb.ShardTierCold {
+			t.Fatalf("unexpected tier: %q", tier)
+		}
+
+		names, err := s.MeasurementNames(nil, "db0", nil)
+		if err != nil {
+			t.Fatal(err)
+		} else if exp := [][]byte{[]byte("cpu")}; !reflect.DeepEqual(names, exp) {
+			t.Fatalf("unexpected measurements: %s", names)
+		}
+	}
+
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) { test(index) })
+	}
+}
+
+// Ensure the store can convert the type of a field in every shard.
+func TestStore_ConvertFieldType(t *testing.T) {
+	t.Parallel()
+
+	test := func(index string) {
+		s := MustOpenStore(index)
+		defer s.Close()
+
+		s.MustCreateShardWithData("db0", "rp0", 1, `cpu,host=A value=1i 10`, `mem value=2i 10`)
+		s.MustCreateShardWithData("db0", "rp0", 2, `cpu,host=A value=3i 20`)
+
+		if err := s.ConvertFieldType("db0", "cpu", "value", influxql.Float); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, id := range []uint64{1, 2} {
+			if got, exp := s.Shard(id).MeasurementFields([]byte("cpu")).Field("value").Type, influxql.Float; got != exp {
+				t.Fatalf("shard %d: field type mismatch: got %v, exp %v", id, got, exp)
+			}
+		}
+		if got, exp := s.Shard(1).MeasurementFields([]byte("mem")).Field("value").Type, influxql.Integer; got != exp {
+			t.Fatalf("field type mismatch: got %v, exp %v", got, exp)
+		}
+
+		// Writes of the new type are accepted and of the old type rejected.
+		s.MustWriteToShardString(1, `cpu,host=A value=1.5 30`)
+		points, err := models.ParsePointsString(`cpu,host=A value=4i 40`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.WriteToShard(1, points); err == nil || !strings.Contains(err.Error(), tsdb.ErrFieldTypeConflict.Error()) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := s.ConvertFieldType("db0", "cpu", "value", influxql.String); err == nil || !strings.Contains(err.Error(), tsdb.ErrFieldTypeConversion.Error()) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) { test(index) })
+	}
+}
+
+// Ensure the store can compact shards and pause the compactions of a database.
+func TestStore_CompactShard(t *testing.T) {
+	t.Parallel()
+
+	test := func(index string) {
+		s := MustOpenStore(index)
+		defer s.Close()
+
+		s.MustCreateShardWithData("db0", "rp0", 1, `cpu,host=A value=1 10`)
+		s.MustCreateShardWithData("db1", "rp0", 2, `cpu,host=A value=1 10`)
+
+		if err := s.CompactShard(3, false); err != tsdb.ErrShardNotFound {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Shards created while paused are paused too.
+		s.SetCompactionsPaused("db0", true)
+		s.MustCreateShardWithData("db0", "rp0", 3, `cpu,host=A value=1 20`)
+		for _, id := range []uint64{1, 3} {
+			if err := s.CompactShard(id, false); err != tsdb.ErrCompactionsPaused {
+				t.Fatalf("shard %d: unexpected error: %v", id, err)
+			}
+		}
+		if err := s.CompactShard(2, true); err != nil {
+			t.Fatal(err)
+		}
+
+		s.SetCompactionsPaused("db0", false)
+		if err := s.CompactShard(1, false); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; !s.Shard(1).IsIdle() || len(s.Compactions()) > 0; i++ {
+			if i == 100 {
+				t.Fatalf("shard not compacted: %v", s.Compactions())
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) { test(index) })
+	}
+}
+
+func TestStore_Trash(t *testing.T) {
+	t.Parallel()
+
+	test := func(index string) {
+		trashDir, err := ioutil.TempDir("", "freetsdb-tsdb-trash-")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(trashDir)
+
+		s := NewStore(index)
+		s.EngineOptions.Config.TrashDir = trashDir
+		if err := s.Open(); err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+
+		measurements := func(db string) [][]byte {
+			names, err := s.MeasurementNames(nil, db, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return names
+		}
+
+		s.MustCreateShardWithData("db0", "rp0", 1, "cpu,host=serverA value=1 0", "mem,host=serverA value=1 0")
+		s.MustCreateShardWithData("db0", "rp0", 2, "cpu,host=serverB value=1 0")
+		s.MustCreateShardWithData("db1", "rp0", 3, "disk,host=serverA value=1 0")
+
+		// Drop and restore a measurement.
+		if err := s.TrashMeasurement("db0", "cpu"); err != nil {
+			t.Fatal(err)
+		} else if names := measurements("db0"); !reflect.DeepEqual(names, [][]byte{[]byte("mem")}) {
+			t.Fatalf("unexpected measurements: %s", names)
+		}
+		if err := s.UndropMeasurement("db0", "cpu"); err != nil {
+			t.Fatal(err)
+		} else if names := measurements("db0"); !reflect.DeepEqual(names, [][]byte{[]byte("cpu"), []byte("mem")}) {
+			t.Fatalf("unexpected measurements: %s", names)
+		}
+
+		// Drop and restore a shard.
+		if err := s.TrashShard(2); err != nil {
+			t.Fatal(err)
+		} else if s.Shard(2) != nil {
+			t.Fatal("expected shard to be dropped")
+		} else if dirExists(filepath.Join(s.Path(), "db0", "rp0", "2")) {
+			t.Fatal("shard still in data dir")
+		}
+		if err := s.UndropShard(2); err != nil {
+			t.Fatal(err)
+		} else if s.Shard(2) == nil {
+			t.Fatal("expected shard to be restored")
+		}
+
+		// Drop and restore a database.
+		if err := s.TrashDatabase("db1"); err != nil {
+			t.Fatal(err)
+		} else if s.Shard(3) != nil {
+			t.Fatal("expected shard to be dropped")
+		} else if dirExists(filepath.Join(s.Path(), "db1")) {
+			t.Fatal("database still in data dir")
+		}
+
+		entries, err := s.Trash()
+		if err != nil {
+			t.Fatal(err)
+		} else if len(entries) != 1 || entries[0].Type != tsdb.TrashTypeDatabase || entries[0].Database != "db1" {
+			t.Fatalf("unexpected trash entries: %+v", entries)
+		}
+
+		if err := s.UndropDatabase("db1"); err != nil {
+			t.Fatal(err)
+		} else if s.Shard(3) == nil {
+			t.Fatal("expected shard to be restored")
+		} else if names := measurements("db1"); !reflect.DeepEqual(names, [][]byte{[]byte("disk")}) {
+			t.Fatalf("unexpected measurements: %s", names)
+		}
+		if err := s.UndropDatabase("db1"); err != tsdb.ErrTrashEntryNotFound {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Restored shards are loaded on open.
+		if err := s.Reopen(); err != nil {
+			t.Fatal(err)
+		}
+		for id := uint64(1); id <= 3; id++ {
+			if s.Shard(id) == nil {
+				t.Fatalf("shard %d missing", id)
+			}
+		}
+
+		// Purged entries can no longer be restored.
+		if err := s.TrashShard(1); err != nil {
+			t.Fatal(err)
+		} else if err := s.PurgeTrash(time.Now().Add(time.Minute)); err != nil {
+			t.Fatal(err)
+		} else if err := s.UndropShard(1); err != tsdb.ErrTrashEntryNotFound {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) { test(index) })
+	}
+}
+
 func TestStore_Open(t *testing.T) {
 	t.Parallel()
 
@@ -2058,9 +2324,12 @@ func (s *Store) Reopen() error {
 		return err
 	}
 
+	coldDir, trashDir := s.ColdPath(), s.TrashPath()
 	s.Store = tsdb.NewStore(s.Path())
 	s.EngineOptions.IndexVersion = s.index
 	s.EngineOptions.Config.WALDir = filepath.Join(s.Path(), "wal")
+	s.EngineOptions.Config.ColdDir = coldDir
+	s.EngineOptions.Config.TrashDir = trashDir
 	s.EngineOptions.Config.TraceLoggingEnabled = true
 
 	if testing.Verbose() {
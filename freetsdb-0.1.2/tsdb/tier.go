@@ -0,0 +1,98 @@
+package tsdb
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/freetsdb/freetsdb/pkg/file"
+)
+
+// Storage tiers of a shard.
+const (
+	// ShardTierHot is the tier of shards in the data directory.
+	ShardTierHot = "hot"
+
+	// ShardTierCold is the tier of shards in the cold directory.
+	ShardTierCold = "cold"
+
+	// ShardTierArchived is the tier of shards moved to the archive, which
+	// are not kept by any data node.
+	ShardTierArchived = "archived"
+)
+
+// shardMoveSuffix is appended to the directory a shard is copied to while it
+// moves between tiers on different file systems.
+const shardMoveSuffix = ".moving"
+
+// moveDir moves the directory src to dst. When both are not on the same file
+// system, src is copied next to dst first so dst only exists once complete.
+func moveDir(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+
+	if err := os.Rename(src, dst); err == nil {
+		return file.SyncDir(filepath.Dir(dst))
+	}
+
+	tmp := dst + shardMoveSuffix
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if err := copyDir(src, tmp); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := file.RenameFile(tmp, dst); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := file.SyncDir(filepath.Dir(dst)); err != nil {
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// copyDir recursively copies the directory src to dst.
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode())
+		}
+		return copyFile(path, target, info.Mode())
+	})
+}
+
+// copyFile copies the file src to the new file dst and syncs it.
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	} else if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,448 @@
+package tsdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/freetsdb/freetsdb/logger"
+	"github.com/freetsdb/freetsdb/pkg/file"
+	"go.uber.org/zap"
+)
+
+// Types of trash entries.
+const (
+	TrashTypeDatabase    = "database"
+	TrashTypeMeasurement = "measurement"
+	TrashTypeShard       = "shard"
+)
+
+// Names of the files and directories in a trash entry. A trash entry is a
+// directory in the trash dir holding the moved data, WAL and cold
+// directories of a database or shard, or a backup of a measurement per
+// shard. The entry file is written last, so an entry without it is
+// incomplete.
+const (
+	trashEntryFile = "entry.json"
+	trashDataDir   = "data"
+	trashWALDir    = "wal"
+	trashColdDir   = "cold"
+	trashShardsDir = "shards"
+)
+
+// TrashEntry describes dropped data kept in the trash dir.
+type TrashEntry struct {
+	// Name is the name of the entry directory in the trash dir.
+	Name string `json:"-"`
+
+	Type            string    `json:"type"`
+	Database        string    `json:"database"`
+	RetentionPolicy string    `json:"retentionPolicy,omitempty"`
+	Measurement     string    `json:"measurement,omitempty"`
+	ShardID         uint64    `json:"shardID,omitempty"`
+	DroppedAt       time.Time `json:"droppedAt"`
+}
+
+// TrashPath returns the directory dropped data is moved to. It defaults to a
+// "trash" directory next to the data dir.
+func (s *Store) TrashPath() string {
+	if s.EngineOptions.Config.TrashDir != "" {
+		return s.EngineOptions.Config.TrashDir
+	}
+	return filepath.Join(filepath.Dir(s.path), "trash")
+}
+
+// TrashDatabase closes all shards of a database and moves its files to the
+// trash dir.
+func (s *Store) TrashDatabase(name string) error {
+	s.mu.RLock()
+	_, ok := s.databases[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	e := &TrashEntry{Type: TrashTypeDatabase, Database: name}
+	return s.trash(e, func(dir string) error {
+		return s.deleteDatabase(name, dir)
+	})
+}
+
+// TrashShard closes a shard and moves its files to the trash dir.
+func (s *Store) TrashShard(shardID uint64) error {
+	sh := s.Shard(shardID)
+	if sh == nil {
+		return nil
+	}
+
+	e := &TrashEntry{
+		Type:            TrashTypeShard,
+		Database:        sh.Database(),
+		RetentionPolicy: sh.RetentionPolicy(),
+		ShardID:         shardID,
+	}
+	return s.trash(e, func(dir string) error {
+		return s.deleteShard(shardID, dir)
+	})
+}
+
+// TrashMeasurement backs up the data of a measurement of each shard of a
+// database to the trash dir and then deletes the measurement. Points written
+// to the measurement while it is dropped may not be backed up. Nothing is
+// moved to the trash if no shard holds the measurement, so dropping it again
+// does not hide the backup from UndropMeasurement.
+func (s *Store) TrashMeasurement(database, name string) error {
+	s.mu.RLock()
+	dbShards := s.filterShards(byDatabase(database))
+	s.mu.RUnlock()
+
+	var shards []*Shard
+	for _, sh := range dbShards {
+		if ok, err := sh.MeasurementExists([]byte(name)); err != nil {
+			return err
+		} else if ok {
+			shards = append(shards, sh)
+		}
+	}
+	if len(shards) == 0 {
+		return nil
+	}
+
+	e := &TrashEntry{Type: TrashTypeMeasurement, Database: database, Measurement: name}
+	return s.trash(e, func(dir string) error {
+		if err := os.MkdirAll(filepath.Join(dir, trashShardsDir), 0700); err != nil {
+			return err
+		}
+		for _, sh := range shards {
+			if err := exportMeasurement(sh, name, trashShardFile(dir, sh.ID())); err != nil {
+				return fmt.Errorf("backup measurement of shard %d: %s", sh.ID(), err)
+			}
+		}
+		return s.DeleteMeasurement(database, name)
+	})
+}
+
+// UndropDatabase moves the files of the most recently dropped database name
+// back from the trash dir and opens its shards. Shards that were in the cold
+// dir are moved to the data dir.
+func (s *Store) UndropDatabase(name string) error {
+	e, err := s.trashEntry(func(e *TrashEntry) bool {
+		return e.Type == TrashTypeDatabase && e.Database == name
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	_, ok := s.databases[name]
+	s.mu.RUnlock()
+	if ok {
+		return fmt.Errorf("database %q already exists", name)
+	}
+
+	dir := filepath.Join(s.TrashPath(), e.Name)
+	dbPath := filepath.Join(s.path, name)
+	if err := moveDirIfExists(filepath.Join(dir, trashDataDir), dbPath); err != nil {
+		return err
+	}
+	if err := moveDirIfExists(filepath.Join(dir, trashWALDir), filepath.Join(s.EngineOptions.Config.WALDir, name)); err != nil {
+		return err
+	}
+
+	coldPath := filepath.Join(dir, trashColdDir)
+	rps, err := ioutil.ReadDir(coldPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, rp := range rps {
+		ids, err := ioutil.ReadDir(filepath.Join(coldPath, rp.Name()))
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := moveDir(filepath.Join(coldPath, rp.Name(), id.Name()), filepath.Join(dbPath, rp.Name(), id.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Open the shards of the database.
+	rps, err = ioutil.ReadDir(dbPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, rp := range rps {
+		if !rp.IsDir() || rp.Name() == SeriesFileDirectory {
+			continue
+		}
+		ids, err := ioutil.ReadDir(filepath.Join(dbPath, rp.Name()))
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			shardID, err := strconv.ParseUint(id.Name(), 10, 64)
+			if err != nil || !id.IsDir() {
+				continue
+			}
+			if err := s.CreateShard(name, rp.Name(), shardID, true); err != nil {
+				return fmt.Errorf("open shard %d: %s", shardID, err)
+			}
+		}
+	}
+
+	s.Logger.Info("Undropped database", logger.Database(name))
+	return os.RemoveAll(dir)
+}
+
+// UndropShard moves the files of a dropped shard back from the trash dir and
+// opens the shard.
+func (s *Store) UndropShard(shardID uint64) error {
+	e, err := s.trashEntry(func(e *TrashEntry) bool {
+		return e.Type == TrashTypeShard && e.ShardID == shardID
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.Shard(shardID) != nil {
+		return fmt.Errorf("shard %d already exists", shardID)
+	}
+
+	dir := filepath.Join(s.TrashPath(), e.Name)
+	id := strconv.FormatUint(shardID, 10)
+	if err := moveDir(filepath.Join(dir, trashDataDir), filepath.Join(s.path, e.Database, e.RetentionPolicy, id)); err != nil {
+		return err
+	}
+	if err := moveDirIfExists(filepath.Join(dir, trashWALDir), filepath.Join(s.EngineOptions.Config.WALDir, e.Database, e.RetentionPolicy, id)); err != nil {
+		return err
+	}
+	if err := s.CreateShard(e.Database, e.RetentionPolicy, shardID, true); err != nil {
+		return err
+	}
+
+	s.Logger.Info("Undropped shard", logger.Shard(shardID))
+	return os.RemoveAll(dir)
+}
+
+// UndropMeasurement imports the backed up data of the most recently dropped
+// measurement name of a database into the shards that still exist.
+func (s *Store) UndropMeasurement(database, name string) error {
+	e, err := s.trashEntry(func(e *TrashEntry) bool {
+		return e.Type == TrashTypeMeasurement && e.Database == database && e.Measurement == name
+	})
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(s.TrashPath(), e.Name)
+	fis, err := ioutil.ReadDir(filepath.Join(dir, trashShardsDir))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, fi := range fis {
+		shardID, err := strconv.ParseUint(strings.TrimSuffix(fi.Name(), ".tar"), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		// Shards deleted since the measurement was dropped are skipped.
+		if s.Shard(shardID) == nil {
+			continue
+		}
+
+		f, err := os.Open(filepath.Join(dir, trashShardsDir, fi.Name()))
+		if err != nil {
+			return err
+		}
+		err = s.ImportShard(shardID, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("import measurement to shard %d: %s", shardID, err)
+		}
+	}
+
+	s.Logger.Info("Undropped measurement", logger.Database(database), zap.String("measurement", name))
+	return os.RemoveAll(dir)
+}
+
+// Trash returns the complete entries of the trash dir, oldest first.
+func (s *Store) Trash() ([]TrashEntry, error) {
+	trashDir := s.TrashPath()
+	fis, err := ioutil.ReadDir(trashDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var entries []TrashEntry
+	for _, fi := range fis {
+		e, err := readTrashEntry(trashDir, fi.Name())
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *e)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].DroppedAt.Before(entries[j].DroppedAt) })
+	return entries, nil
+}
+
+// PurgeTrash deletes the entries of the trash dir dropped before the given
+// time, as well as incomplete entries last modified before it.
+func (s *Store) PurgeTrash(before time.Time) error {
+	trashDir := s.TrashPath()
+	fis, err := ioutil.ReadDir(trashDir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, fi := range fis {
+		droppedAt := fi.ModTime()
+		if e, err := readTrashEntry(trashDir, fi.Name()); err == nil {
+			droppedAt = e.DroppedAt
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+
+		if !droppedAt.Before(before) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(trashDir, fi.Name())); err != nil {
+			return err
+		}
+		s.Logger.Info("Purged trash entry", zap.String("name", fi.Name()))
+	}
+	return nil
+}
+
+// trash creates an entry directory for e in the trash dir, calls fn to move
+// the dropped data into it and writes the entry file.
+func (s *Store) trash(e *TrashEntry, fn func(dir string) error) error {
+	trashDir := s.TrashPath()
+	if err := os.MkdirAll(trashDir, 0700); err != nil {
+		return err
+	}
+
+	// Create a unique entry directory.
+	e.DroppedAt = time.Now().UTC()
+	for t := e.DroppedAt.UnixNano(); ; t++ {
+		e.Name = fmt.Sprintf("%s-%d", e.Type, t)
+		if err := os.Mkdir(filepath.Join(trashDir, e.Name), 0700); err == nil {
+			break
+		} else if !os.IsExist(err) {
+			return err
+		}
+	}
+
+	dir := filepath.Join(trashDir, e.Name)
+	if err := fn(dir); err != nil {
+		// Keep any data that was moved already. Incomplete entries are
+		// removed by PurgeTrash.
+		os.Remove(dir)
+		return err
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, trashEntryFile+".tmp")
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return file.RenameFile(tmp, filepath.Join(dir, trashEntryFile))
+}
+
+// trashEntry returns the most recent complete entry of the trash dir for
+// which fn returns true.
+func (s *Store) trashEntry(fn func(e *TrashEntry) bool) (*TrashEntry, error) {
+	entries, err := s.Trash()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if fn(&entries[i]) {
+			return &entries[i], nil
+		}
+	}
+	return nil, ErrTrashEntryNotFound
+}
+
+// trashDatabaseFiles moves the data, WAL and cold directories of a database
+// to the trash entry directory dir.
+func (s *Store) trashDatabaseFiles(name, dir string) error {
+	if err := moveDirIfExists(filepath.Join(s.path, name), filepath.Join(dir, trashDataDir)); err != nil {
+		return err
+	}
+	if err := moveDirIfExists(filepath.Join(s.EngineOptions.Config.WALDir, name), filepath.Join(dir, trashWALDir)); err != nil {
+		return err
+	}
+	if coldDir := s.ColdPath(); coldDir != "" {
+		return moveDirIfExists(filepath.Join(coldDir, name), filepath.Join(dir, trashColdDir))
+	}
+	return nil
+}
+
+// readTrashEntry reads the entry file of the trash entry directory name.
+func readTrashEntry(trashDir, name string) (*TrashEntry, error) {
+	b, err := ioutil.ReadFile(filepath.Join(trashDir, name, trashEntryFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var e TrashEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, fmt.Errorf("read trash entry %s: %s", name, err)
+	}
+	e.Name = name
+	return &e, nil
+}
+
+// trashShardFile returns the path of the measurement backup of a shard in
+// the trash entry directory dir.
+func trashShardFile(dir string, shardID uint64) string {
+	return filepath.Join(dir, trashShardsDir, strconv.FormatUint(shardID, 10)+".tar")
+}
+
+// exportMeasurement writes a backup of the data of measurement name in sh
+// to the file path.
+func exportMeasurement(sh *Shard, name, path string) error {
+	basePath, err := relativePath(shardRoot(sh), sh.Path())
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if err := sh.ExportMeasurement(f, basePath, []byte(name)); err != nil {
+		f.Close()
+		return err
+	} else if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// moveDirIfExists moves the directory src to dst if src exists.
+func moveDirIfExists(src, dst string) error {
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return moveDir(src, dst)
+}